@@ -0,0 +1,118 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchAlertingMonitorState string
+
+const (
+	OpensearchAlertingMonitorPending OpensearchAlertingMonitorState = "PENDING"
+	OpensearchAlertingMonitorCreated OpensearchAlertingMonitorState = "CREATED"
+	OpensearchAlertingMonitorError   OpensearchAlertingMonitorState = "ERROR"
+	OpensearchAlertingMonitorIgnored OpensearchAlertingMonitorState = "IGNORED"
+	OpensearchAlertingMonitorDeferred OpensearchAlertingMonitorState = "DEFERRED"
+)
+
+// MonitorType is the kind of monitor of the Alerting plugin
+// +kubebuilder:validation:Enum=query_level_monitor;bucket_level_monitor;doc_level_monitor
+type MonitorType string
+
+const (
+	QueryLevelMonitor  MonitorType = "query_level_monitor"
+	BucketLevelMonitor MonitorType = "bucket_level_monitor"
+	DocLevelMonitor    MonitorType = "doc_level_monitor"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=monitor;alertingmonitor
+//+kubebuilder:subresource:status
+
+// OpensearchAlertingMonitor is the schema for the monitors of the OpenSearch Alerting plugin
+type OpensearchAlertingMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchAlertingMonitorSpec   `json:"spec,omitempty"`
+	Status OpensearchAlertingMonitorStatus `json:"status,omitempty"`
+}
+
+type OpensearchAlertingMonitorStatus struct {
+	State           OpensearchAlertingMonitorState `json:"state,omitempty"`
+	Reason          string                         `json:"reason,omitempty"`
+	ExistingMonitor *bool                          `json:"existingMonitor,omitempty"`
+	ManagedCluster  *types.UID                     `json:"managedCluster,omitempty"`
+	// Name of the currently managed monitor
+	MonitorName string `json:"monitorName,omitempty"`
+	// Id OpenSearch generated for the monitor
+	MonitorID string `json:"monitorId,omitempty"`
+}
+
+type OpensearchAlertingMonitorSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the monitor. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// The kind of monitor
+	// +kubebuilder:default=query_level_monitor
+	MonitorType MonitorType `json:"monitorType,omitempty"`
+
+	// Whether the monitor runs. Defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// When the monitor runs
+	Schedule MonitorSchedule `json:"schedule"`
+
+	// Inputs of the monitor as accepted by the Alerting API, e.g. {"search":{"indices":["logs-*"],"query":{...}}}
+	// +kubebuilder:validation:MinItems=1
+	Inputs []apiextensionsv1.JSON `json:"inputs"`
+
+	// Triggers of the monitor
+	Triggers []MonitorTrigger `json:"triggers,omitempty"`
+}
+
+// MonitorSchedule runs the monitor either periodically or on a cron expression, exactly one has to be set
+type MonitorSchedule struct {
+	Period *MonitorPeriod `json:"period,omitempty"`
+	Cron   *CronSchedule  `json:"cron,omitempty"`
+}
+
+type MonitorPeriod struct {
+	// +kubebuilder:validation:Minimum=1
+	Interval int `json:"interval"`
+	// +kubebuilder:validation:Enum=MINUTES;HOURS;DAYS
+	// +kubebuilder:default=MINUTES
+	Unit string `json:"unit,omitempty"`
+}
+
+type MonitorTrigger struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Severity of the alerts, 1 is the highest
+	// +kubebuilder:validation:Enum="1";"2";"3";"4";"5"
+	// +kubebuilder:default="1"
+	Severity string `json:"severity,omitempty"`
+	// Condition of the trigger as accepted by the Alerting API for the monitor type, e.g.
+	// {"script":{"source":"ctx.results[0].hits.total.value > 0","lang":"painless"}}
+	Condition apiextensionsv1.JSON `json:"condition"`
+	// Actions of the trigger as accepted by the Alerting API, e.g. sending a message to a notification channel
+	Actions []apiextensionsv1.JSON `json:"actions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchAlertingMonitorList contains a list of OpensearchAlertingMonitor
+type OpensearchAlertingMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchAlertingMonitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchAlertingMonitor{}, &OpensearchAlertingMonitorList{})
+}
@@ -0,0 +1,127 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchAnomalyDetectorState string
+
+const (
+	OpensearchAnomalyDetectorPending OpensearchAnomalyDetectorState = "PENDING"
+	OpensearchAnomalyDetectorCreated OpensearchAnomalyDetectorState = "CREATED"
+	OpensearchAnomalyDetectorError   OpensearchAnomalyDetectorState = "ERROR"
+	OpensearchAnomalyDetectorIgnored OpensearchAnomalyDetectorState = "IGNORED"
+	OpensearchAnomalyDetectorDeferred OpensearchAnomalyDetectorState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=detector;anomalydetector
+//+kubebuilder:subresource:status
+
+// OpensearchAnomalyDetector is the schema for the detectors of the OpenSearch Anomaly Detection plugin
+type OpensearchAnomalyDetector struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchAnomalyDetectorSpec   `json:"spec,omitempty"`
+	Status OpensearchAnomalyDetectorStatus `json:"status,omitempty"`
+}
+
+type OpensearchAnomalyDetectorStatus struct {
+	State            OpensearchAnomalyDetectorState `json:"state,omitempty"`
+	Reason           string                         `json:"reason,omitempty"`
+	ExistingDetector *bool                          `json:"existingDetector,omitempty"`
+	ManagedCluster   *types.UID                     `json:"managedCluster,omitempty"`
+	// Name of the currently managed detector
+	DetectorName string `json:"detectorName,omitempty"`
+	// Id OpenSearch generated for the detector
+	DetectorID string `json:"detectorId,omitempty"`
+	// State of the job of the detector as reported by OpenSearch
+	Job *DetectorJobStatus `json:"job,omitempty"`
+}
+
+type DetectorJobStatus struct {
+	// State of the job, one of DISABLED, INIT and RUNNING
+	State string `json:"state,omitempty"`
+	// Error that stopped the job or keeps it from initializing
+	Error string `json:"error,omitempty"`
+	// Progress of the initialization of the models, e.g. 70%
+	InitProgress string `json:"initProgress,omitempty"`
+}
+
+type OpensearchAnomalyDetectorSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the detector. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// Optional description of the detector
+	Description string `json:"description,omitempty"`
+
+	// Whether the job of the detector runs. Setting it to false stops the detector, setting it back to true starts
+	// it again. Defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Indices or index patterns the detector reads
+	// +kubebuilder:validation:MinItems=1
+	Indices []string `json:"indices"`
+
+	// Timestamp field of the documents
+	// +kubebuilder:validation:MinLength=1
+	TimeField string `json:"timeField"`
+
+	// Features the detector models, at most 5
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=5
+	Features []DetectorFeature `json:"features"`
+
+	// Optional query selecting the documents the detector reads. Defaults to all documents
+	FilterQuery *apiextensionsv1.JSON `json:"filterQuery,omitempty"`
+
+	// How often the detector aggregates the features, a whole number of minutes, e.g. 10m
+	// +kubebuilder:default="10m"
+	DetectionInterval string `json:"detectionInterval,omitempty"`
+
+	// Optional time the detector waits for late documents, a whole number of minutes, e.g. 1m
+	WindowDelay string `json:"windowDelay,omitempty"`
+
+	// Optional fields the detector splits the documents by to model each entity separately, at most 2
+	// +kubebuilder:validation:MaxItems=2
+	CategoryFields []string `json:"categoryFields,omitempty"`
+
+	// Number of intervals the detector considers together. Defaults to 8
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	ShingleSize *int `json:"shingleSize,omitempty"`
+
+	// Optional custom index the results are written to, it has to start with opensearch-ad-plugin-result-
+	// +immutable
+	ResultIndex string `json:"resultIndex,omitempty"`
+}
+
+type DetectorFeature struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Whether the feature is modeled. Defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+	// Aggregation computing the feature as accepted by OpenSearch, with a name as its only key, e.g.
+	// {"total_bytes":{"sum":{"field":"bytes"}}}
+	AggregationQuery apiextensionsv1.JSON `json:"aggregationQuery"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchAnomalyDetectorList contains a list of OpensearchAnomalyDetector
+type OpensearchAnomalyDetectorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchAnomalyDetector `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchAnomalyDetector{}, &OpensearchAnomalyDetectorList{})
+}
@@ -0,0 +1,64 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchClusterSettingsState string
+
+const (
+	OpensearchClusterSettingsPending OpensearchClusterSettingsState = "PENDING"
+	OpensearchClusterSettingsCreated OpensearchClusterSettingsState = "CREATED"
+	OpensearchClusterSettingsError   OpensearchClusterSettingsState = "ERROR"
+	OpensearchClusterSettingsDeferred OpensearchClusterSettingsState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=clustersettings
+//+kubebuilder:subresource:status
+
+// OpensearchClusterSettings is the schema for the OpenSearch cluster settings API
+type OpensearchClusterSettings struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchClusterSettingsSpec   `json:"spec,omitempty"`
+	Status OpensearchClusterSettingsStatus `json:"status,omitempty"`
+}
+
+type OpensearchClusterSettingsStatus struct {
+	State          OpensearchClusterSettingsState `json:"state,omitempty"`
+	Reason         string                         `json:"reason,omitempty"`
+	ManagedCluster *types.UID                     `json:"managedCluster,omitempty"`
+	// Persistent settings as last applied by the operator, they are reset when they are removed from the spec
+	Persistent map[string]string `json:"persistent,omitempty"`
+	// Transient settings as last applied by the operator, they are reset when they are removed from the spec
+	Transient map[string]string `json:"transient,omitempty"`
+}
+
+// OpensearchClusterSettingsSpec defines cluster settings by their flat name, e.g. cluster.max_shards_per_node. List
+// settings are written as comma separated values. Settings in the deny list of the operator cannot be managed.
+type OpensearchClusterSettingsSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// Settings that survive a full restart of the cluster
+	Persistent map[string]string `json:"persistent,omitempty"`
+
+	// Settings that are lost on a full restart of the cluster
+	Transient map[string]string `json:"transient,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchClusterSettingsList contains a list of OpensearchClusterSettings
+type OpensearchClusterSettingsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchClusterSettings `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchClusterSettings{}, &OpensearchClusterSettingsList{})
+}
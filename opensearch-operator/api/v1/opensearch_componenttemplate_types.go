@@ -14,6 +14,40 @@ const (
 	OpensearchComponentTemplateCreated OpensearchComponentTemplateState = "CREATED"
 	OpensearchComponentTemplateError   OpensearchComponentTemplateState = "ERROR"
 	OpensearchComponentTemplateIgnored OpensearchComponentTemplateState = "IGNORED"
+	// The operator user lacks the OpenSearch privileges needed to manage the component template
+	OpensearchComponentTemplateForbidden OpensearchComponentTemplateState = "FORBIDDEN"
+	// The previously applied version was reapplied because of the opensearch.opster.io/rollback annotation
+	OpensearchComponentTemplateRolledBack OpensearchComponentTemplateState = "ROLLED_BACK"
+	// Changes are deferred until the cluster health and a recent snapshot required by the spec allow them
+	OpensearchComponentTemplateDeferred OpensearchComponentTemplateState = "DEFERRED"
+	// A change is not applied until the current generation is approved with the opensearch.opster.io/approved-generation annotation
+	OpensearchComponentTemplateAwaitingApproval OpensearchComponentTemplateState = "AWAITING_APPROVAL"
+	// The TTL has passed and the component template was deleted from OpenSearch with the Retain expiry policy
+	OpensearchComponentTemplateExpired OpensearchComponentTemplateState = "EXPIRED"
+)
+
+// ExternalEditPolicy controls what the operator does with edits made to a component template directly in OpenSearch
+// +kubebuilder:validation:Enum=Revert;Warn;Adopt
+type ExternalEditPolicy string
+
+const (
+	// ExternalEditPolicyRevert applies the spec again, reverting the edit
+	ExternalEditPolicyRevert ExternalEditPolicy = "Revert"
+	// ExternalEditPolicyWarn emits an event and only reverts the edit after the grace period
+	ExternalEditPolicyWarn ExternalEditPolicy = "Warn"
+	// ExternalEditPolicyAdopt keeps the edit and records it as the last applied state until the spec changes
+	ExternalEditPolicyAdopt ExternalEditPolicy = "Adopt"
+)
+
+// IndexTier is a data tier of the cluster, identified by a custom node attribute
+// +kubebuilder:validation:Enum=hot;warm;cold;frozen
+type IndexTier string
+
+const (
+	IndexTierHot    IndexTier = "hot"
+	IndexTierWarm   IndexTier = "warm"
+	IndexTierCold   IndexTier = "cold"
+	IndexTierFrozen IndexTier = "frozen"
 )
 
 //+kubebuilder:object:root=true
@@ -34,13 +68,175 @@ type OpensearchComponentTemplateStatus struct {
 	Reason                    string                           `json:"reason,omitempty"`
 	ExistingComponentTemplate *bool                            `json:"existingComponentTemplate,omitempty"`
 	ManagedCluster            *types.UID                       `json:"managedCluster,omitempty"`
+	// Name of the OpenSearchCluster the component template is applied to, to tell a recreated cluster from a
+	// changed reference
+	ManagedClusterName string `json:"managedClusterName,omitempty"`
+	// UUID of the OpenSearch cluster the component template is applied to, which changes if the cluster is replaced,
+	// e.g. by restoring a snapshot into a new cluster
+	ClusterUUID string `json:"clusterUUID,omitempty"`
 	// Name of the currently managed component template
 	ComponentTemplateName string `json:"componentTemplateName,omitempty"`
+	// Set once the template was created in CreateOnly apply mode
+	CreatedOnce bool `json:"createdOnce,omitempty"`
+	// SHA1 hash of the component template as last applied or adopted by the operator
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+	// Generation of the spec that was last applied or adopted
+	LastAppliedGeneration int64 `json:"lastAppliedGeneration,omitempty"`
+	// Git revision the component template in OpenSearch was applied from, recorded in its _meta from the
+	// opensearch.opster.io/git-revision annotation
+	LastAppliedGitRevision string `json:"lastAppliedGitRevision,omitempty"`
+	// When an edit made in OpenSearch was first detected with the Warn external edit policy
+	ExternalEditDetectedAt *metav1.Time `json:"externalEditDetectedAt,omitempty"`
+	// Component template that was replaced by the last change, reapplied by the opensearch.opster.io/rollback
+	// annotation. Not kept if it is larger than 32KiB or holds values that look sensitive
+	PreviousApplied *apiextensionsv1.JSON `json:"previousApplied,omitempty"`
+	// Generation of the spec the component template was rolled back at, zero if it is not rolled back
+	RollbackGeneration int64 `json:"rollbackGeneration,omitempty"`
+	// SHA1 hash of the component template as last verified with a test index
+	VerifiedHash string `json:"verifiedHash,omitempty"`
+	// Change waiting for approval with the opensearch.opster.io/approved-generation annotation
+	AwaitingApproval *ComponentTemplateApproval `json:"awaitingApproval,omitempty"`
+	// State of the component template on each cluster it targets
+	// +listType=map
+	// +listMapKey=name
+	Clusters []ComponentTemplateClusterStatus `json:"clusters,omitempty"`
+	// Progress of the migration of the existing indices to the codec of the component template
+	CodecMigration *CodecMigrationStatus `json:"codecMigration,omitempty"`
+	// Schema version the write alias of the schema migration was last migrated to
+	SchemaVersion int64 `json:"schemaVersion,omitempty"`
+	// Progress of the migration of the write index to the last increased schema version
+	Migration *SchemaMigrationStatus `json:"migration,omitempty"`
+	// Index simulated for the simulateIndexName of the spec
+	SimulatedIndex *ComponentTemplateSimulatedIndex `json:"simulatedIndex,omitempty"`
+	// Region of the cluster the component template was applied to with the region affinity
+	Region string `json:"region,omitempty"`
+	// When the TTL of the spec passes, the creation of the resource plus the TTL
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// X-Opaque-Id sent with the last write of the component template to OpenSearch
+	LastRequestID string `json:"lastRequestId,omitempty"`
+	// Id OpenSearch, or a proxy in front of it, returned for the last write in the X-Request-Id or X-Amzn-RequestId
+	// header, empty if it returned none
+	LastServerRequestID string `json:"lastServerRequestId,omitempty"`
+	// SHA1 hash of the component template as last validated with the canary documents
+	CanaryValidatedHash string `json:"canaryValidatedHash,omitempty"`
+}
+
+// ComponentTemplateSimulatedIndex is the index OpenSearch would create from the index templates of the cluster
+type ComponentTemplateSimulatedIndex struct {
+	// Name of the simulated index
+	Name string `json:"name"`
+	// SHA1 hash of the component template the index was simulated with
+	Hash string `json:"hash,omitempty"`
+	// Resolved settings, mappings and aliases of the index with redacted values. Not kept if it is larger than 32KiB
+	Template *apiextensionsv1.JSON `json:"template,omitempty"`
+	// True if the resolved template was too large to be kept
+	Truncated bool `json:"truncated,omitempty"`
+	// Other index templates matching the index, which are not used as they have a lower priority
+	Overlapping []string `json:"overlapping,omitempty"`
+}
+
+// CodecMigrationIndexState is the state of an index in a codec migration
+type CodecMigrationIndexState string
+
+const (
+	CodecMigrationIndexPending  CodecMigrationIndexState = "Pending"
+	CodecMigrationIndexMerging  CodecMigrationIndexState = "Merging"
+	CodecMigrationIndexMigrated CodecMigrationIndexState = "Migrated"
+	CodecMigrationIndexFailed   CodecMigrationIndexState = "Failed"
+)
+
+// CodecMigrationStatus is the progress of the migration of the existing indices to a codec
+type CodecMigrationStatus struct {
+	// Codec the indices are migrated to
+	Codec string `json:"codec"`
+	// Indices that used another codec when the migration started
+	// +listType=map
+	// +listMapKey=name
+	Indices []CodecMigrationIndex `json:"indices,omitempty"`
+	// When the indices were enumerated
+	StartedAt metav1.Time `json:"startedAt,omitempty"`
+	// When the last index was migrated or failed
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// CodecMigrationIndex is the state of an index in a codec migration
+type CodecMigrationIndex struct {
+	Name  string                   `json:"name"`
+	State CodecMigrationIndexState `json:"state"`
+	// Task of the running force merge
+	Task   string `json:"task,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// SchemaMigrationPhase is the step a schema migration is at
+type SchemaMigrationPhase string
+
+const (
+	SchemaMigrationCreatingIndex SchemaMigrationPhase = "CreatingIndex"
+	SchemaMigrationSwappingAlias SchemaMigrationPhase = "SwappingAlias"
+	SchemaMigrationReindexing    SchemaMigrationPhase = "Reindexing"
+	SchemaMigrationRetiring      SchemaMigrationPhase = "Retiring"
+	SchemaMigrationCompleted     SchemaMigrationPhase = "Completed"
+	SchemaMigrationFailed        SchemaMigrationPhase = "Failed"
+)
+
+// SchemaMigrationStatus is the progress of the migration of the write index of an alias to a schema version
+type SchemaMigrationStatus struct {
+	// Schema version the index is migrated to
+	SchemaVersion int64                `json:"schemaVersion"`
+	Phase         SchemaMigrationPhase `json:"phase"`
+	// Write index of the alias when the migration started
+	SourceIndex string `json:"sourceIndex"`
+	// Index created from the template, <alias>-v<schemaVersion>
+	TargetIndex string `json:"targetIndex"`
+	// Task of the running reindex
+	Task   string `json:"task,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// When the migration started
+	StartedAt metav1.Time `json:"startedAt,omitempty"`
+	// When the migration completed or failed
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// ComponentTemplateApproval is a change of a component template that is only applied once it is approved
+type ComponentTemplateApproval struct {
+	// Generation of the spec the approved-generation annotation has to be set to
+	Generation int64 `json:"generation"`
+	// Changes applying the spec would make to the component template in OpenSearch, with redacted values
+	Diff []ComponentTemplateChange `json:"diff,omitempty"`
+}
+
+// ComponentTemplateChange is a single change to a value of a component template
+type ComponentTemplateChange struct {
+	// add, remove or replace
+	Op string `json:"op"`
+	// Dotted path of the value in the component template
+	Path string                `json:"path"`
+	From *apiextensionsv1.JSON `json:"from,omitempty"`
+	To   *apiextensionsv1.JSON `json:"to,omitempty"`
+}
+
+// ComponentTemplateClusterStatus is the state of a component template on one of the clusters it targets
+type ComponentTemplateClusterStatus struct {
+	// Name of the OpenSearchCluster
+	Name string `json:"name"`
+	// Phase of the cluster as of the last reconcile, empty if the cluster does not exist
+	ClusterPhase string                           `json:"clusterPhase,omitempty"`
+	State        OpensearchComponentTemplateState `json:"state,omitempty"`
+	Reason       string                           `json:"reason,omitempty"`
+	// When the state of the component template on the cluster last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 type OpensearchComponentTemplateSpec struct {
+	// The cluster the template is applied to. The name may be left empty if the cluster is selected by region affinity
 	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
 
+	// Optional selection of the cluster by region. If set, the template is only applied to a cluster carrying the
+	// region label with the same value as the resource. Without a cluster name the running cluster of the region is
+	// selected, and kept once the template is applied to it
+	RegionAffinity *RegionAffinity `json:"regionAffinity,omitempty"`
+
 	// The name of the component template. Defaults to metadata.name
 	// +immutable
 	Name string `json:"name,omitempty"`
@@ -56,6 +252,302 @@ type OpensearchComponentTemplateSpec struct {
 
 	// Optional user metadata about the component template
 	Meta *apiextensionsv1.JSON `json:"_meta,omitempty"`
+
+	// Optional name of a group of component templates referring to the same cluster that are applied all-or-nothing.
+	// All pending changes of the group are validated before any of them is applied, and members that were already
+	// applied are rolled back to their previous state if applying another member fails
+	TransactionGroup string `json:"transactionGroup,omitempty"`
+
+	// If true, an index.codec that is not available in the version of the cluster is replaced by the default codec
+	// instead of failing the reconcile
+	ReplaceUnsupportedCodec bool `json:"replaceUnsupportedCodec,omitempty"`
+
+	// If true, analysis components of plugins that are not installed on all nodes only emit a warning event
+	// instead of failing the reconcile
+	IgnoreMissingAnalysisPlugins bool `json:"ignoreMissingAnalysisPlugins,omitempty"`
+
+	// Templates with a higher priority are applied first when applies to the same cluster have to wait,
+	// see the --cluster-apply-concurrency flag of the operator
+	ApplyPriority int32 `json:"applyPriority,omitempty"`
+
+	// Reconcile (default) keeps the template in sync with the spec. CreateOnly creates the template once and then
+	// stops updating it, so it can be tuned in OpenSearch; it is only created again if it is deleted from OpenSearch.
+	// +kubebuilder:default=Reconcile
+	ApplyMode TemplateApplyMode `json:"applyMode,omitempty"`
+
+	// If set, changes to the template are only applied while the health of the cluster is at least this status,
+	// otherwise they are deferred. The template is still compared with OpenSearch while changes are deferred
+	// +kubebuilder:validation:Enum=green;yellow
+	RequiredClusterHealth OpenSearchHealth `json:"requiredClusterHealth,omitempty"`
+
+	// What to do if the component template was edited in OpenSearch since the operator last applied it.
+	// Revert (default) applies the spec again, Warn emits an event and reverts the edit only after the
+	// externalEditGracePeriod, Adopt keeps the edit until the spec is changed. Not used for transaction groups.
+	// +kubebuilder:default=Revert
+	ExternalEditPolicy ExternalEditPolicy `json:"externalEditPolicy,omitempty"`
+
+	// How long an edit made in OpenSearch is kept with the Warn external edit policy. Defaults to 10m
+	ExternalEditGracePeriod *metav1.Duration `json:"externalEditGracePeriod,omitempty"`
+
+	// Optional data tier the indices created from the template are allocated to. Adds
+	// index.routing.allocation.require.<tierAttribute>: <tier> to the settings of the template.
+	// The reconcile fails if no node of the cluster has the tier attribute set to this tier
+	Tier IndexTier `json:"tier,omitempty"`
+
+	// Custom node attribute holding the data tier of the nodes (node.attr.<tierAttribute>). Defaults to temp
+	TierAttribute string `json:"tierAttribute,omitempty"`
+
+	// Optional field level security the indices created from the template require. The template is only
+	// applied while the referenced OpensearchRole hides the listed fields
+	FieldSecurity *ComponentTemplateFieldSecurity `json:"fieldSecurity,omitempty"`
+
+	// If true, every applied version of the template is verified by creating a test index from it and comparing the
+	// settings and mappings of the index with the template. The test index and its index template are only used for
+	// the verification and deleted afterwards. Not used for transaction groups
+	VerifyAfterApply bool `json:"verifyAfterApply,omitempty"`
+
+	// Optional name of a representative index. If set, the index OpenSearch would create under this name from the
+	// index templates of the cluster is simulated whenever the template is applied, and its resolved settings,
+	// mappings and aliases are recorded in the status. The index is never created. Not used for transaction groups
+	SimulateIndexName string `json:"simulateIndexName,omitempty"`
+
+	// Optional policy computing index.number_of_replicas from the number of data nodes of the cluster instead of
+	// a fixed value. The replicas are computed on every reconcile, so they follow the cluster as it is scaled.
+	// Cannot be combined with index.number_of_replicas or index.auto_expand_replicas in the settings
+	ReplicaPolicy *ReplicaPolicy `json:"replicaPolicy,omitempty"`
+
+	// If true, a component template OpenSearch reports deprecation warnings for is not kept: the write is reverted
+	// and the reconcile fails. Otherwise the deprecations are only reported as events
+	FailOnDeprecatedSettings bool `json:"failOnDeprecatedSettings,omitempty"`
+
+	// What to do with index settings that are not known OpenSearch index settings, e.g. misspelled ones. Warn emits an
+	// event naming them and applies the template, Reject fails the reconcile before the template is applied and
+	// Ignore does not check the settings. User and plugin defined settings like index.analysis.* or index.plugins.*
+	// are not checked
+	// +kubebuilder:default=Warn
+	UnknownSettings UnknownSettingsPolicy `json:"unknownSettings,omitempty"`
+
+	// What the operator does with index.blocks.* settings the template sets to true, which block the indices created
+	// from it. Warn emits an event naming the blocks and applies the template, RequireAcknowledgment only applies it
+	// once the opensearch.opster.io/acknowledge-index-blocks annotation is set to true
+	// +kubebuilder:default=Warn
+	IndexBlocks IndexBlocksPolicy `json:"indexBlocks,omitempty"`
+
+	// If true, changes to the template are only applied once the opensearch.opster.io/approved-generation annotation
+	// is set to the generation of the spec. Until then the change is not written and listed in the status.
+	// Not used for transaction groups
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// If true, a written template is only reported as applied once it is returned through the service of every node
+	// pool of the cluster. Nodes that do not return it yet, e.g. during a network partition, are asked again and the
+	// write is confirmed again in a later reconcile if they still do not. Not used for transaction groups
+	ConfirmOnAllNodePools bool `json:"confirmOnAllNodePools,omitempty"`
+
+	// Optional migration of the existing indices to the index.codec of the template once it is applied. Not used for
+	// transaction groups
+	CodecMigration *CodecMigration `json:"codecMigration,omitempty"`
+
+	// Version of the schema of the component template. Increasing it migrates the write index of the schema
+	// migration to a new index created from the template
+	// +kubebuilder:validation:Minimum=0
+	SchemaVersion int64 `json:"schemaVersion,omitempty"`
+
+	// Optional migration of the write index of an alias to a new index whenever the schema version is increased.
+	// Not used for transaction groups
+	SchemaMigration *SchemaMigration `json:"schemaMigration,omitempty"`
+
+	// Optional ISM policy managing the indices created from the template. The settings its actions change, e.g. the
+	// replicas of a replica_count action, are not compared when the indices are checked against the template
+	ISMPolicy *ComponentTemplateISMPolicy `json:"ismPolicy,omitempty"`
+
+	// Optional time to live of the component template, counted from the creation of the resource. Once it has
+	// passed the template is deleted from OpenSearch as selected by the expiry policy
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// What happens once the TTL has passed. Delete deletes the resource, which deletes the template from OpenSearch.
+	// Retain deletes the template from OpenSearch and keeps the resource in the EXPIRED state until the TTL is
+	// extended or removed
+	// +kubebuilder:default=Delete
+	ExpiryPolicy ExpiryPolicy `json:"expiryPolicy,omitempty"`
+
+	// Optional id appended to the X-Opaque-Id of the writes of the component template, e.g. the id of a change
+	// request, to find them in the OpenSearch audit log. A correlation id is generated for every write if it is empty
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9._:-]*$`
+	RequestID string `json:"requestId,omitempty"`
+
+	// Optional precondition that a successful snapshot not older than the max age exists before a change of the
+	// component template is applied, changes are deferred until one does
+	RequireRecentSnapshot *RecentSnapshotRequirement `json:"requireRecentSnapshot,omitempty"`
+
+	// Optional validation of a change with sample documents before it is applied. A canary index is created with the
+	// settings and mappings of the template, the documents are indexed into it and the resulting field types are
+	// compared with the expected ones. The change is not applied if a document is rejected or a field is mapped
+	// differently. The canary index is deleted afterwards. Not used for transaction groups
+	CanaryValidation *CanaryValidation `json:"canaryValidation,omitempty"`
+}
+
+// RegionAffinity selects the cluster of a component template by the region label of the resource
+type RegionAffinity struct {
+	// Label holding the region of the OpenSearchClusters and of the component template
+	// +kubebuilder:default=topology.kubernetes.io/region
+	Label string `json:"label,omitempty"`
+}
+
+// RecentSnapshotRequirement requires a recent successful snapshot of the cluster before changes are applied
+type RecentSnapshotRequirement struct {
+	// Maximum age of the latest successful snapshot, counted from when it ended
+	MaxAge metav1.Duration `json:"maxAge"`
+	// Snapshot repository the snapshot has to be in, any repository of the cluster if empty
+	Repository string `json:"repository,omitempty"`
+}
+
+// CanaryValidation lists the documents a change of a component template is validated with
+type CanaryValidation struct {
+	// Documents indexed into the canary index, every one of them has to be accepted
+	// +kubebuilder:validation:MinItems=1
+	Documents []apiextensionsv1.JSON `json:"documents"`
+	// Types the fields of the canary index have to be mapped as once the documents are indexed, by field path, e.g.
+	// user.id: keyword
+	ExpectedFieldTypes map[string]string `json:"expectedFieldTypes,omitempty"`
+}
+
+// ExpiryPolicy selects what happens to a component template once its TTL has passed
+// +kubebuilder:validation:Enum=Delete;Retain
+type ExpiryPolicy string
+
+const (
+	// ExpiryPolicyDelete deletes the resource, the template is deleted from OpenSearch by its finalizer
+	ExpiryPolicyDelete ExpiryPolicy = "Delete"
+	// ExpiryPolicyRetain deletes the template from OpenSearch and keeps the resource
+	ExpiryPolicyRetain ExpiryPolicy = "Retain"
+)
+
+// UnknownSettingsPolicy controls what the operator does with index settings OpenSearch does not know
+// +kubebuilder:validation:Enum=Warn;Reject;Ignore
+type UnknownSettingsPolicy string
+
+const (
+	// UnknownSettingsWarn emits an event naming the unknown settings and applies the template
+	UnknownSettingsWarn UnknownSettingsPolicy = "Warn"
+	// UnknownSettingsReject fails the reconcile without applying the template
+	UnknownSettingsReject UnknownSettingsPolicy = "Reject"
+	// UnknownSettingsIgnore does not check the settings
+	UnknownSettingsIgnore UnknownSettingsPolicy = "Ignore"
+)
+
+// IndexBlocksPolicy controls what the operator does with templates blocking the indices created from them
+// +kubebuilder:validation:Enum=Warn;RequireAcknowledgment
+type IndexBlocksPolicy string
+
+const (
+	// IndexBlocksWarn emits an event naming the blocks and applies the template
+	IndexBlocksWarn IndexBlocksPolicy = "Warn"
+	// IndexBlocksRequireAcknowledgment fails the reconcile until the blocks are acknowledged with an annotation
+	IndexBlocksRequireAcknowledgment IndexBlocksPolicy = "RequireAcknowledgment"
+)
+
+// CodecMigrationMode selects what is done with the existing indices using another codec than the template
+// +kubebuilder:validation:Enum=Enumerate;ForceMerge
+type CodecMigrationMode string
+
+const (
+	// CodecMigrationEnumerate only lists the indices using another codec
+	CodecMigrationEnumerate CodecMigrationMode = "Enumerate"
+	// CodecMigrationForceMerge changes the codec of the indices and force merges them
+	CodecMigrationForceMerge CodecMigrationMode = "ForceMerge"
+)
+
+// CodecMigration migrates the existing indices matching the index patterns to the codec of the component template,
+// indices without an index.codec use the default codec
+type CodecMigration struct {
+	// Index patterns of the existing indices to migrate
+	// +kubebuilder:validation:MinItems=1
+	IndexPatterns []string `json:"indexPatterns"`
+	// Enumerate (default) only lists the indices using another codec in the status. ForceMerge migrates them one
+	// after the other while the cluster is green: the index is closed, its codec is changed, it is opened again and
+	// force merged to a single segment, which rewrites all its segments with the new codec. A closed index cannot be
+	// written to, exclude write indices from the index patterns
+	// +kubebuilder:default=Enumerate
+	Mode CodecMigrationMode `json:"mode,omitempty"`
+	// The migration is refused if more indices use another codec. Defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	MaxIndices *int32 `json:"maxIndices,omitempty"`
+}
+
+// SchemaMigrationRetirePolicy selects what happens to the old index once a schema migration moved its documents
+// +kubebuilder:validation:Enum=Close;Delete;Keep
+type SchemaMigrationRetirePolicy string
+
+const (
+	// SchemaMigrationRetireClose closes the old index, it can be opened again
+	SchemaMigrationRetireClose SchemaMigrationRetirePolicy = "Close"
+	// SchemaMigrationRetireDelete deletes the old index
+	SchemaMigrationRetireDelete SchemaMigrationRetirePolicy = "Delete"
+	// SchemaMigrationRetireKeep leaves the old index open
+	SchemaMigrationRetireKeep SchemaMigrationRetirePolicy = "Keep"
+)
+
+// SchemaMigration migrates the write index of an alias to a new index created from the component template whenever
+// the schema version is increased: the new index is created, becomes the write index of the alias, the documents of
+// the old index are reindexed into it and the old index is removed from the alias and retired
+type SchemaMigration struct {
+	// Alias whose write index is migrated. The new indices are named <writeAlias>-v<schemaVersion>, an index
+	// template composed of the component template has to match them
+	// +kubebuilder:validation:MinLength=1
+	WriteAlias string `json:"writeAlias"`
+	// What happens to the old index once its documents were reindexed: Close (default), Delete or Keep
+	// +kubebuilder:default=Close
+	RetirePolicy SchemaMigrationRetirePolicy `json:"retirePolicy,omitempty"`
+}
+
+// ISMConflictPolicy selects whether the settings an ISM policy manages are compared with the template
+// +kubebuilder:validation:Enum=Ignore;Compare
+type ISMConflictPolicy string
+
+const (
+	// ISMConflictIgnore does not compare the settings the ISM policy manages, they may differ from the template
+	ISMConflictIgnore ISMConflictPolicy = "Ignore"
+	// ISMConflictCompare compares all settings, a setting changed by the ISM policy is reported as a mismatch
+	ISMConflictCompare ISMConflictPolicy = "Compare"
+)
+
+// ComponentTemplateISMPolicy is the ISM policy managing the indices created from a component template
+type ComponentTemplateISMPolicy struct {
+	// Id of the ISM policy in OpenSearch
+	// +kubebuilder:validation:MinLength=1
+	PolicyID string `json:"policyId"`
+	// Whether the settings the policy manages are compared with the template: Ignore (default) or Compare
+	// +kubebuilder:default=Ignore
+	ConflictPolicy ISMConflictPolicy `json:"conflictPolicy,omitempty"`
+}
+
+// ReplicaPolicy computes the number of replicas of the indices created from a template from the number of data
+// nodes: replicas = (dataNodes - 1) * factorPercent / 100, rounded down and bounded by minReplicas and maxReplicas
+type ReplicaPolicy struct {
+	// Percentage of the other data nodes that hold a replica of each shard. Defaults to 100, i.e. every data node
+	// holds a copy of each shard
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	FactorPercent *int32 `json:"factorPercent,omitempty"`
+	// Lower bound of the number of replicas, also applied if the cluster has too few data nodes to allocate them
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+	// Optional upper bound of the number of replicas
+	// +kubebuilder:validation:Minimum=0
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// ComponentTemplateFieldSecurity links a component template to the OpensearchRole restricting access to the
+// fields of the indices created from it
+type ComponentTemplateFieldSecurity struct {
+	// OpensearchRole in the namespace of the component template
+	RoleRef corev1.LocalObjectReference `json:"roleRef"`
+	// Index patterns of the indices created from the template. The role must have an index permission for each of them
+	IndexPatterns []string `json:"indexPatterns"`
+	// Fields every index permission of the role covering the index patterns must hide, either by excluding
+	// them (fls: ["~field"]) or by including only other fields
+	HiddenFields []string `json:"hiddenFields"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchCrossClusterReplicationState string
+
+const (
+	OpensearchCrossClusterReplicationPending OpensearchCrossClusterReplicationState = "PENDING"
+	OpensearchCrossClusterReplicationCreated OpensearchCrossClusterReplicationState = "CREATED"
+	OpensearchCrossClusterReplicationError   OpensearchCrossClusterReplicationState = "ERROR"
+	OpensearchCrossClusterReplicationIgnored OpensearchCrossClusterReplicationState = "IGNORED"
+	OpensearchCrossClusterReplicationDeferred OpensearchCrossClusterReplicationState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=replication;ccr
+//+kubebuilder:subresource:status
+
+// OpensearchCrossClusterReplication is the schema for the replication of indices from a leader cluster with the
+// OpenSearch Cross-Cluster Replication plugin
+type OpensearchCrossClusterReplication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchCrossClusterReplicationSpec   `json:"spec,omitempty"`
+	Status OpensearchCrossClusterReplicationStatus `json:"status,omitempty"`
+}
+
+type OpensearchCrossClusterReplicationStatus struct {
+	State              OpensearchCrossClusterReplicationState `json:"state,omitempty"`
+	Reason             string                                 `json:"reason,omitempty"`
+	ExistingConnection *bool                                  `json:"existingConnection,omitempty"`
+	ManagedCluster     *types.UID                             `json:"managedCluster,omitempty"`
+	// Name of the currently managed connection to the leader cluster
+	ConnectionAlias string `json:"connectionAlias,omitempty"`
+	// Auto-follow rules created by the operator
+	AutoFollowRules []AutoFollowRuleStatus `json:"autoFollowRules,omitempty"`
+	// Indices replicated from the leader cluster as reported by OpenSearch
+	Indices []ReplicatedIndexStatus `json:"indices,omitempty"`
+}
+
+type AutoFollowRuleStatus struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	// Leader indices the rule failed to start the replication of
+	FailedIndices []string `json:"failedIndices,omitempty"`
+}
+
+type ReplicatedIndexStatus struct {
+	FollowerIndex string `json:"followerIndex"`
+	LeaderIndex   string `json:"leaderIndex,omitempty"`
+	// State of the replication, one of BOOTSTRAPPING, SYNCING, PAUSED and FAILED
+	Status string `json:"status"`
+	// Why the replication is paused or failed
+	Reason string `json:"reason,omitempty"`
+	// Number of operations the follower index is behind the leader index
+	Lag int64 `json:"lag"`
+}
+
+type OpensearchCrossClusterReplicationSpec struct {
+	// The follower cluster the indices are replicated to
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// Name of the connection to the leader cluster. Defaults to metadata.name
+	// +immutable
+	ConnectionAlias string `json:"connectionAlias,omitempty"`
+
+	// OpenSearchCluster in the namespace of the resource the indices are replicated from, its service is the seed of
+	// the connection. Either leaderCluster or seeds has to be set
+	LeaderCluster *corev1.LocalObjectReference `json:"leaderCluster,omitempty"`
+
+	// Transport addresses of nodes of the leader cluster, e.g. leader.example.com:9300
+	Seeds []string `json:"seeds,omitempty"`
+
+	// Rules that replicate the leader indices matching their pattern, including indices created later
+	AutoFollowRules []AutoFollowRule `json:"autoFollowRules,omitempty"`
+
+	// Security roles the replication runs with, required if the security plugin is enabled
+	UseRoles *ReplicationRoles `json:"useRoles,omitempty"`
+}
+
+type AutoFollowRule struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Pattern of the leader indices the rule replicates, e.g. logs-*
+	// +kubebuilder:validation:MinLength=1
+	Pattern string `json:"pattern"`
+}
+
+type ReplicationRoles struct {
+	// Role of the leader cluster that allows reading the leader indices
+	LeaderClusterRole string `json:"leaderClusterRole"`
+	// Role of the follower cluster that allows writing the follower indices
+	FollowerClusterRole string `json:"followerClusterRole"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchCrossClusterReplicationList contains a list of OpensearchCrossClusterReplication
+type OpensearchCrossClusterReplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchCrossClusterReplication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchCrossClusterReplication{}, &OpensearchCrossClusterReplicationList{})
+}
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchDataStreamState string
+
+const (
+	OpensearchDataStreamPending OpensearchDataStreamState = "PENDING"
+	OpensearchDataStreamCreated OpensearchDataStreamState = "CREATED"
+	OpensearchDataStreamError   OpensearchDataStreamState = "ERROR"
+	OpensearchDataStreamIgnored OpensearchDataStreamState = "IGNORED"
+	OpensearchDataStreamDeferred OpensearchDataStreamState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=datastream
+//+kubebuilder:subresource:status
+
+// OpensearchDataStream is the schema for the OpenSearch data streams API
+type OpensearchDataStream struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchDataStreamSpec   `json:"spec,omitempty"`
+	Status OpensearchDataStreamStatus `json:"status,omitempty"`
+}
+
+type OpensearchDataStreamStatus struct {
+	State              OpensearchDataStreamState `json:"state,omitempty"`
+	Reason             string                    `json:"reason,omitempty"`
+	ExistingDataStream *bool                     `json:"existingDataStream,omitempty"`
+	ManagedCluster     *types.UID                `json:"managedCluster,omitempty"`
+	// Name of the currently managed data stream
+	DataStreamName string `json:"dataStreamName,omitempty"`
+	// Name of the index template the data stream was created from
+	IndexTemplate string `json:"indexTemplate,omitempty"`
+}
+
+// OpensearchDataStreamSpec defines a data stream. An index template with a data stream section has to match its name,
+// the settings and mappings of the backing indices are taken from it.
+type OpensearchDataStreamSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the data stream. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// Whether the data stream and its backing indices are deleted from OpenSearch when the resource is deleted
+	// +kubebuilder:default=Retain
+	DeletionPolicy IndexDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchDataStreamList contains a list of OpensearchDataStream
+type OpensearchDataStreamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchDataStream `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchDataStream{}, &OpensearchDataStreamList{})
+}
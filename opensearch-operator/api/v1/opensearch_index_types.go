@@ -1,7 +1,22 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TemplateApplyMode controls whether the operator keeps a template in sync with its spec
+// +kubebuilder:validation:Enum=Reconcile;CreateOnly
+type TemplateApplyMode string
+
+const (
+	// TemplateApplyModeReconcile keeps the template in sync with the spec
+	TemplateApplyModeReconcile TemplateApplyMode = "Reconcile"
+	// TemplateApplyModeCreateOnly creates the template once and leaves later changes made in OpenSearch untouched.
+	// The template is only created again if it is deleted from OpenSearch.
+	TemplateApplyModeCreateOnly TemplateApplyMode = "CreateOnly"
 )
 
 // Describes the specs of an index
@@ -33,3 +48,79 @@ type OpensearchIndexAliasSpec struct {
 	// If true, the index is the write index for the alias
 	IsWriteIndex bool `json:"isWriteIndex,omitempty"`
 }
+
+type OpensearchIndexState string
+
+const (
+	OpensearchIndexPending OpensearchIndexState = "PENDING"
+	OpensearchIndexCreated OpensearchIndexState = "CREATED"
+	OpensearchIndexError   OpensearchIndexState = "ERROR"
+	OpensearchIndexIgnored OpensearchIndexState = "IGNORED"
+	OpensearchIndexDeferred OpensearchIndexState = "DEFERRED"
+)
+
+// IndexDeletionPolicy controls what happens to an index or data stream when its resource is deleted
+// +kubebuilder:validation:Enum=Retain;Delete
+type IndexDeletionPolicy string
+
+const (
+	// IndexDeletionPolicyRetain keeps the index or the backing indices of the data stream in OpenSearch
+	IndexDeletionPolicyRetain IndexDeletionPolicy = "Retain"
+	// IndexDeletionPolicyDelete deletes the index or the data stream with all their documents
+	IndexDeletionPolicyDelete IndexDeletionPolicy = "Delete"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=osindex
+//+kubebuilder:subresource:status
+
+// OpensearchIndex is the schema for a concrete index in OpenSearch
+type OpensearchIndex struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchIndexResourceSpec `json:"spec,omitempty"`
+	Status OpensearchIndexStatus       `json:"status,omitempty"`
+}
+
+type OpensearchIndexStatus struct {
+	State          OpensearchIndexState `json:"state,omitempty"`
+	Reason         string               `json:"reason,omitempty"`
+	ExistingIndex  *bool                `json:"existingIndex,omitempty"`
+	ManagedCluster *types.UID           `json:"managedCluster,omitempty"`
+	// Name of the currently managed index
+	IndexName string `json:"indexName,omitempty"`
+	// Aliases the operator added to the index, they are removed from the index when they are removed from the spec
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// OpensearchIndexResourceSpec defines a concrete index. Dynamic settings, new mappings and aliases are applied to
+// the existing index, changes of static settings are rejected as OpenSearch only accepts them when the index is created.
+type OpensearchIndexResourceSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the index. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// Settings, mappings and aliases of the index. The keys of the aliases are their names, their index and alias
+	// fields are ignored
+	OpensearchIndexSpec `json:",inline"`
+
+	// Whether the index and its documents are deleted from OpenSearch when the resource is deleted
+	// +kubebuilder:default=Retain
+	DeletionPolicy IndexDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchIndexList contains a list of OpensearchIndex
+type OpensearchIndexList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchIndex `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchIndex{}, &OpensearchIndexList{})
+}
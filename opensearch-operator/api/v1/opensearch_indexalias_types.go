@@ -0,0 +1,90 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchIndexAliasState string
+
+const (
+	OpensearchIndexAliasPending OpensearchIndexAliasState = "PENDING"
+	OpensearchIndexAliasCreated OpensearchIndexAliasState = "CREATED"
+	OpensearchIndexAliasError   OpensearchIndexAliasState = "ERROR"
+	OpensearchIndexAliasIgnored OpensearchIndexAliasState = "IGNORED"
+	OpensearchIndexAliasDeferred OpensearchIndexAliasState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=indexalias
+//+kubebuilder:subresource:status
+
+// OpensearchIndexAlias is the schema for an alias of OpenSearch indices
+type OpensearchIndexAlias struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchIndexAliasResourceSpec `json:"spec,omitempty"`
+	Status OpensearchIndexAliasStatus       `json:"status,omitempty"`
+}
+
+type OpensearchIndexAliasStatus struct {
+	State          OpensearchIndexAliasState `json:"state,omitempty"`
+	Reason         string                    `json:"reason,omitempty"`
+	ExistingAlias  *bool                     `json:"existingAlias,omitempty"`
+	ManagedCluster *types.UID                `json:"managedCluster,omitempty"`
+	// Name of the currently managed alias
+	AliasName string `json:"aliasName,omitempty"`
+	// Indices the alias points to
+	Indices []string `json:"indices,omitempty"`
+	// Index the writes to the alias go to, if any
+	WriteIndex string `json:"writeIndex,omitempty"`
+}
+
+// OpensearchIndexAliasResourceSpec defines the indices an alias points to. Indices are added to and removed from the
+// alias in a single update, so switching an alias from one index to another is atomic.
+type OpensearchIndexAliasResourceSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the alias. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// The indices the alias points to, the alias is removed from all other indices
+	// +kubebuilder:validation:MinItems=1
+	Indices []IndexAliasTarget `json:"indices"`
+}
+
+type IndexAliasTarget struct {
+	// Name of a concrete index, wildcards are not allowed
+	// +kubebuilder:validation:MinLength=1
+	Index string `json:"index"`
+
+	// Query used to limit the documents of the index the alias can access
+	Filter *apiextensionsv1.JSON `json:"filter,omitempty"`
+
+	// Value used to route indexing and search operations through the alias to a specific shard of the index
+	Routing string `json:"routing,omitempty"`
+	// Value used to route indexing operations, overrides routing
+	IndexRouting string `json:"indexRouting,omitempty"`
+	// Value used to route search operations, overrides routing
+	SearchRouting string `json:"searchRouting,omitempty"`
+
+	// If true, writes to the alias go to this index. At most one index can be the write index
+	IsWriteIndex bool `json:"isWriteIndex,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchIndexAliasList contains a list of OpensearchIndexAlias
+type OpensearchIndexAliasList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchIndexAlias `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchIndexAlias{}, &OpensearchIndexAliasList{})
+}
@@ -14,6 +14,7 @@ const (
 	OpensearchIndexTemplateCreated OpensearchIndexTemplateState = "CREATED"
 	OpensearchIndexTemplateError   OpensearchIndexTemplateState = "ERROR"
 	OpensearchIndexTemplateIgnored OpensearchIndexTemplateState = "IGNORED"
+	OpensearchIndexTemplateDeferred OpensearchIndexTemplateState = "DEFERRED"
 )
 
 //+kubebuilder:object:root=true
@@ -36,6 +37,10 @@ type OpensearchIndexTemplateStatus struct {
 	ManagedCluster        *types.UID                   `json:"managedCluster,omitempty"`
 	// Name of the currently managed index template
 	IndexTemplateName string `json:"indexTemplateName,omitempty"`
+	// Set once the template was created in CreateOnly apply mode
+	CreatedOnce bool `json:"createdOnce,omitempty"`
+	// Index patterns generated from the labels and annotations if generateIndexPatterns is set
+	GeneratedIndexPatterns []string `json:"generatedIndexPatterns,omitempty"`
 }
 
 type OpensearchIndexTemplateSpec struct {
@@ -45,8 +50,13 @@ type OpensearchIndexTemplateSpec struct {
 	// +immutable
 	Name string `json:"name,omitempty"`
 
-	// Array of wildcard expressions used to match the names of indices during creation
-	IndexPatterns []string `json:"indexPatterns"`
+	// Array of wildcard expressions used to match the names of indices during creation.
+	// Required unless generateIndexPatterns is set
+	IndexPatterns []string `json:"indexPatterns,omitempty"`
+
+	// If true, the index patterns are generated from the labels and annotations of this object with the
+	// --index-pattern-template of the operator instead of using indexPatterns
+	GenerateIndexPatterns bool `json:"generateIndexPatterns,omitempty"`
 
 	// The template that should be applied
 	Template OpensearchIndexSpec `json:"template,omitempty"`
@@ -64,6 +74,28 @@ type OpensearchIndexTemplateSpec struct {
 
 	// Optional user metadata about the index template
 	Meta *apiextensionsv1.JSON `json:"_meta,omitempty"`
+
+	// If set, indices matching the index patterns are created as data streams
+	DataStream *IndexTemplateDataStream `json:"dataStream,omitempty"`
+
+	// Templates with a higher priority are applied first when applies to the same cluster have to wait,
+	// see the --cluster-apply-concurrency flag of the operator
+	ApplyPriority int32 `json:"applyPriority,omitempty"`
+
+	// Reconcile (default) keeps the template in sync with the spec. CreateOnly creates the template once and then
+	// stops updating it, so it can be tuned in OpenSearch; it is only created again if it is deleted from OpenSearch.
+	// +kubebuilder:default=Reconcile
+	ApplyMode TemplateApplyMode `json:"applyMode,omitempty"`
+
+	// If set, changes to the template are only applied while the health of the cluster is at least this status,
+	// otherwise they are deferred. The template is still compared with OpenSearch while changes are deferred
+	// +kubebuilder:validation:Enum=green;yellow
+	RequiredClusterHealth OpenSearchHealth `json:"requiredClusterHealth,omitempty"`
+}
+
+type IndexTemplateDataStream struct {
+	// Name of the timestamp field of the data stream. Defaults to @timestamp
+	TimestampField string `json:"timestampField,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchIngestPipelineState string
+
+const (
+	OpensearchIngestPipelinePending OpensearchIngestPipelineState = "PENDING"
+	OpensearchIngestPipelineCreated OpensearchIngestPipelineState = "CREATED"
+	OpensearchIngestPipelineError   OpensearchIngestPipelineState = "ERROR"
+	OpensearchIngestPipelineIgnored OpensearchIngestPipelineState = "IGNORED"
+	OpensearchIngestPipelineDeferred OpensearchIngestPipelineState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=opensearchingestpipeline
+//+kubebuilder:subresource:status
+
+// OpensearchIngestPipeline is the schema for the OpenSearch ingest pipelines API
+type OpensearchIngestPipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchIngestPipelineSpec   `json:"spec,omitempty"`
+	Status OpensearchIngestPipelineStatus `json:"status,omitempty"`
+}
+
+type OpensearchIngestPipelineStatus struct {
+	State                  OpensearchIngestPipelineState `json:"state,omitempty"`
+	Reason                 string                        `json:"reason,omitempty"`
+	ExistingIngestPipeline *bool                         `json:"existingIngestPipeline,omitempty"`
+	ManagedCluster         *types.UID                    `json:"managedCluster,omitempty"`
+	// Name of the currently managed ingest pipeline
+	IngestPipelineName string `json:"ingestPipelineName,omitempty"`
+}
+
+type OpensearchIngestPipelineSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the ingest pipeline. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// Optional description of the ingest pipeline
+	Description string `json:"description,omitempty"`
+
+	// The processors of the pipeline, run in the order specified. Each processor is an object with a single key,
+	// the type of the processor, e.g. {"set": {"field": "env", "value": "prod"}}
+	// +kubebuilder:validation:MinItems=1
+	Processors []apiextensionsv1.JSON `json:"processors"`
+
+	// Optional processors run instead of the remaining processors if a processor fails
+	OnFailure []apiextensionsv1.JSON `json:"onFailure,omitempty"`
+
+	// Version number used to manage the ingest pipeline externally
+	Version int `json:"version,omitempty"`
+
+	// Optional user metadata about the ingest pipeline
+	Meta *apiextensionsv1.JSON `json:"_meta,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchIngestPipelineList contains a list of OpensearchIngestPipeline
+type OpensearchIngestPipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchIngestPipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchIngestPipeline{}, &OpensearchIngestPipelineList{})
+}
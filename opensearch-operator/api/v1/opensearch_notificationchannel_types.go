@@ -0,0 +1,117 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchNotificationChannelState string
+
+const (
+	OpensearchNotificationChannelPending OpensearchNotificationChannelState = "PENDING"
+	OpensearchNotificationChannelCreated OpensearchNotificationChannelState = "CREATED"
+	OpensearchNotificationChannelError   OpensearchNotificationChannelState = "ERROR"
+	OpensearchNotificationChannelIgnored OpensearchNotificationChannelState = "IGNORED"
+	OpensearchNotificationChannelDeferred OpensearchNotificationChannelState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=channel;notificationchannel
+//+kubebuilder:subresource:status
+
+// OpensearchNotificationChannel is the schema for the channels of the OpenSearch Notifications plugin
+type OpensearchNotificationChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchNotificationChannelSpec   `json:"spec,omitempty"`
+	Status OpensearchNotificationChannelStatus `json:"status,omitempty"`
+}
+
+type OpensearchNotificationChannelStatus struct {
+	State           OpensearchNotificationChannelState `json:"state,omitempty"`
+	Reason          string                             `json:"reason,omitempty"`
+	ExistingChannel *bool                              `json:"existingChannel,omitempty"`
+	ManagedCluster  *types.UID                         `json:"managedCluster,omitempty"`
+	// Id of the currently managed channel
+	ChannelID string `json:"channelId,omitempty"`
+}
+
+// OpensearchNotificationChannelSpec defines a channel. Exactly one of slack, webhook, sns and email has to be set.
+type OpensearchNotificationChannelSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The id of the channel, monitors and snapshot policies refer to the channel by it. Defaults to metadata.name
+	// +immutable
+	ChannelID string `json:"channelId,omitempty"`
+
+	// The display name of the channel. Defaults to metadata.name
+	Name string `json:"name,omitempty"`
+
+	// Optional description of the channel
+	Description string `json:"description,omitempty"`
+
+	// Whether notifications are sent to the channel. Defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Channel sending messages to a Slack incoming webhook
+	Slack *SlackChannel `json:"slack,omitempty"`
+	// Channel sending messages to a custom webhook
+	Webhook *WebhookChannel `json:"webhook,omitempty"`
+	// Channel publishing messages to an Amazon SNS topic
+	SNS *SNSChannel `json:"sns,omitempty"`
+	// Channel sending emails through an SMTP sender of the Notifications plugin
+	Email *EmailChannel `json:"email,omitempty"`
+}
+
+type SlackChannel struct {
+	// Secret key holding the URL of the incoming webhook, which contains its token
+	URLFrom corev1.SecretKeySelector `json:"urlFrom"`
+}
+
+type WebhookChannel struct {
+	// URL of the webhook, set either url or urlFrom
+	URL string `json:"url,omitempty"`
+	// Secret key holding the URL of the webhook, if it contains credentials
+	URLFrom *corev1.SecretKeySelector `json:"urlFrom,omitempty"`
+	// HTTP method of the requests
+	// +kubebuilder:validation:Enum=POST;PUT;PATCH
+	// +kubebuilder:default=POST
+	Method string `json:"method,omitempty"`
+	// Headers sent with the requests
+	Headers map[string]string `json:"headers,omitempty"`
+	// Headers sent with the requests whose values are read from secrets, e.g. Authorization
+	SecretHeaders map[string]corev1.SecretKeySelector `json:"secretHeaders,omitempty"`
+}
+
+type SNSChannel struct {
+	// ARN of the topic
+	// +kubebuilder:validation:MinLength=1
+	TopicARN string `json:"topicArn"`
+	// Optional ARN of the IAM role assumed to publish to the topic
+	RoleARN string `json:"roleArn,omitempty"`
+}
+
+type EmailChannel struct {
+	// Id of the SMTP sender channel the emails are sent from, its credentials are read from the keystore
+	// +kubebuilder:validation:MinLength=1
+	EmailAccountID string `json:"emailAccountId"`
+	// Addresses the emails are sent to
+	Recipients []string `json:"recipients,omitempty"`
+	// Ids of email recipient groups the emails are sent to
+	EmailGroupIDs []string `json:"emailGroupIds,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchNotificationChannelList contains a list of OpensearchNotificationChannel
+type OpensearchNotificationChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchNotificationChannel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchNotificationChannel{}, &OpensearchNotificationChannelList{})
+}
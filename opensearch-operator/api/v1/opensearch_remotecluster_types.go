@@ -0,0 +1,95 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchRemoteClusterState string
+
+const (
+	OpensearchRemoteClusterPending OpensearchRemoteClusterState = "PENDING"
+	OpensearchRemoteClusterCreated OpensearchRemoteClusterState = "CREATED"
+	OpensearchRemoteClusterError   OpensearchRemoteClusterState = "ERROR"
+	OpensearchRemoteClusterIgnored OpensearchRemoteClusterState = "IGNORED"
+	OpensearchRemoteClusterDeferred OpensearchRemoteClusterState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=remotecluster
+//+kubebuilder:subresource:status
+
+// OpensearchRemoteCluster is the schema for the connections of a cluster to remote clusters used by cross-cluster
+// search
+type OpensearchRemoteCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchRemoteClusterSpec   `json:"spec,omitempty"`
+	Status OpensearchRemoteClusterStatus `json:"status,omitempty"`
+}
+
+type OpensearchRemoteClusterStatus struct {
+	State              OpensearchRemoteClusterState `json:"state,omitempty"`
+	Reason             string                       `json:"reason,omitempty"`
+	ExistingConnection *bool                        `json:"existingConnection,omitempty"`
+	ManagedCluster     *types.UID                   `json:"managedCluster,omitempty"`
+	// Name of the currently managed connection
+	Alias string `json:"alias,omitempty"`
+	// Whether the cluster is connected to the remote cluster as reported by OpenSearch
+	Connected *bool `json:"connected,omitempty"`
+	// Number of nodes of the remote cluster the cluster is connected to in sniff mode
+	NumNodesConnected *int `json:"numNodesConnected,omitempty"`
+}
+
+type OpensearchRemoteClusterSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// Name of the connection, which prefixes the indices of the remote cluster in searches, e.g. alias:logs-*.
+	// Defaults to metadata.name
+	// +immutable
+	Alias string `json:"alias,omitempty"`
+
+	// How the cluster connects to the remote cluster. In sniff mode it connects to the seeds and discovers the other
+	// nodes, in proxy mode it connects through a single address, e.g. a load balancer
+	// +kubebuilder:validation:Enum=sniff;proxy
+	// +kubebuilder:default=sniff
+	Mode string `json:"mode,omitempty"`
+
+	// OpenSearchCluster managed by the operator to connect to, its service is the seed or the proxy address of the
+	// connection. Either remoteCluster, seeds or proxyAddress has to be set
+	RemoteCluster *RemoteClusterReference `json:"remoteCluster,omitempty"`
+
+	// Transport addresses of nodes of the remote cluster in sniff mode, e.g. remote.example.com:9300
+	Seeds []string `json:"seeds,omitempty"`
+
+	// Transport address of the remote cluster in proxy mode, e.g. remote-lb.example.com:9300
+	ProxyAddress string `json:"proxyAddress,omitempty"`
+
+	// Optional server name sent with TLS SNI in proxy mode
+	ServerName string `json:"serverName,omitempty"`
+
+	// Whether searches skip the remote cluster if it is unavailable instead of failing. Defaults to false
+	SkipUnavailable *bool `json:"skipUnavailable,omitempty"`
+}
+
+type RemoteClusterReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Namespace of the cluster. Defaults to the namespace of the resource
+	Namespace string `json:"namespace,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchRemoteClusterList contains a list of OpensearchRemoteCluster
+type OpensearchRemoteClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchRemoteCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchRemoteCluster{}, &OpensearchRemoteClusterList{})
+}
@@ -0,0 +1,162 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchRollupJobState string
+
+const (
+	OpensearchRollupJobPending OpensearchRollupJobState = "PENDING"
+	OpensearchRollupJobCreated OpensearchRollupJobState = "CREATED"
+	OpensearchRollupJobError   OpensearchRollupJobState = "ERROR"
+	OpensearchRollupJobIgnored OpensearchRollupJobState = "IGNORED"
+	OpensearchRollupJobDeferred OpensearchRollupJobState = "DEFERRED"
+)
+
+// RollupMetricType is an aggregation a rollup job computes for a field
+// +kubebuilder:validation:Enum=min;max;sum;avg;value_count
+type RollupMetricType string
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=rollup;rollupjob
+//+kubebuilder:subresource:status
+
+// OpensearchRollupJob is the schema for the jobs of the OpenSearch Index Rollups plugin
+type OpensearchRollupJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchRollupJobSpec   `json:"spec,omitempty"`
+	Status OpensearchRollupJobStatus `json:"status,omitempty"`
+}
+
+type OpensearchRollupJobStatus struct {
+	State          OpensearchRollupJobState `json:"state,omitempty"`
+	Reason         string                   `json:"reason,omitempty"`
+	ExistingJob    *bool                    `json:"existingJob,omitempty"`
+	ManagedCluster *types.UID               `json:"managedCluster,omitempty"`
+	// Id of the currently managed rollup job
+	JobID string `json:"jobId,omitempty"`
+}
+
+// OpensearchRollupJobSpec defines a rollup job. The source and target index, continuous, dimensions and metrics
+// cannot be changed once the job is created.
+type OpensearchRollupJobSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The id of the rollup job. Defaults to metadata.name
+	// +immutable
+	JobID string `json:"jobId,omitempty"`
+
+	// Optional description of the rollup job
+	Description string `json:"description,omitempty"`
+
+	// Whether the job runs. Setting it to false stops the job, setting it back to true starts it again. Defaults
+	// to true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// When the job runs
+	Schedule RollupSchedule `json:"schedule"`
+
+	// Index or index pattern the documents are rolled up from
+	// +kubebuilder:validation:MinLength=1
+	// +immutable
+	SourceIndex string `json:"sourceIndex"`
+
+	// Index the rolled up documents are written to, it is created by the job if it does not exist
+	// +kubebuilder:validation:MinLength=1
+	// +immutable
+	TargetIndex string `json:"targetIndex"`
+
+	// Number of buckets processed per search of the job
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10000
+	// +kubebuilder:default=1000
+	PageSize int `json:"pageSize,omitempty"`
+
+	// Optional time the job waits after the end of a bucket before rolling it up, e.g. 1h
+	Delay string `json:"delay,omitempty"`
+
+	// If true, the job keeps rolling up new documents, otherwise it stops after rolling up the current documents
+	// +immutable
+	Continuous bool `json:"continuous,omitempty"`
+
+	// The fields the documents are grouped by. The first dimension has to be a date histogram
+	// +kubebuilder:validation:MinItems=1
+	// +immutable
+	Dimensions []RollupDimension `json:"dimensions"`
+
+	// The aggregations computed for the groups
+	// +immutable
+	Metrics []RollupMetric `json:"metrics,omitempty"`
+}
+
+// RollupSchedule runs the job either in an interval or on a cron expression, exactly one has to be set
+type RollupSchedule struct {
+	Interval *RollupInterval `json:"interval,omitempty"`
+	Cron     *CronSchedule   `json:"cron,omitempty"`
+}
+
+type RollupInterval struct {
+	// +kubebuilder:validation:Minimum=1
+	Period int `json:"period"`
+	// +kubebuilder:validation:Enum=Minutes;Hours;Days
+	// +kubebuilder:default=Minutes
+	Unit string `json:"unit,omitempty"`
+}
+
+// RollupDimension groups the documents by a field, exactly one of dateHistogram, terms and histogram has to be set
+type RollupDimension struct {
+	DateHistogram *RollupDateHistogram `json:"dateHistogram,omitempty"`
+	Terms         *RollupTerms         `json:"terms,omitempty"`
+	Histogram     *RollupHistogram     `json:"histogram,omitempty"`
+}
+
+// RollupDateHistogram groups the documents by time buckets, exactly one of fixedInterval and calendarInterval has
+// to be set
+type RollupDateHistogram struct {
+	// +kubebuilder:validation:MinLength=1
+	SourceField string `json:"sourceField"`
+	// Fixed length of the buckets, e.g. 30m
+	FixedInterval string `json:"fixedInterval,omitempty"`
+	// Calendar aware length of the buckets, e.g. 1d or 1M
+	CalendarInterval string `json:"calendarInterval,omitempty"`
+	// Time zone of the buckets. Defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type RollupTerms struct {
+	// +kubebuilder:validation:MinLength=1
+	SourceField string `json:"sourceField"`
+}
+
+type RollupHistogram struct {
+	// +kubebuilder:validation:MinLength=1
+	SourceField string `json:"sourceField"`
+	// Width of the buckets, a positive number, e.g. "5" or "0.5"
+	// +kubebuilder:validation:MinLength=1
+	Interval string `json:"interval"`
+}
+
+type RollupMetric struct {
+	// +kubebuilder:validation:MinLength=1
+	SourceField string `json:"sourceField"`
+	// +kubebuilder:validation:MinItems=1
+	Metrics []RollupMetricType `json:"metrics"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchRollupJobList contains a list of OpensearchRollupJob
+type OpensearchRollupJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchRollupJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchRollupJob{}, &OpensearchRollupJobList{})
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchSearchPipelineState string
+
+const (
+	OpensearchSearchPipelinePending OpensearchSearchPipelineState = "PENDING"
+	OpensearchSearchPipelineCreated OpensearchSearchPipelineState = "CREATED"
+	OpensearchSearchPipelineError   OpensearchSearchPipelineState = "ERROR"
+	OpensearchSearchPipelineIgnored OpensearchSearchPipelineState = "IGNORED"
+	OpensearchSearchPipelineDeferred OpensearchSearchPipelineState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=opensearchsearchpipeline
+//+kubebuilder:subresource:status
+
+// OpensearchSearchPipeline is the schema for the OpenSearch search pipelines API
+type OpensearchSearchPipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchSearchPipelineSpec   `json:"spec,omitempty"`
+	Status OpensearchSearchPipelineStatus `json:"status,omitempty"`
+}
+
+type OpensearchSearchPipelineStatus struct {
+	State                  OpensearchSearchPipelineState `json:"state,omitempty"`
+	Reason                 string                        `json:"reason,omitempty"`
+	ExistingSearchPipeline *bool                         `json:"existingSearchPipeline,omitempty"`
+	ManagedCluster         *types.UID                    `json:"managedCluster,omitempty"`
+	// Name of the currently managed search pipeline
+	SearchPipelineName string `json:"searchPipelineName,omitempty"`
+}
+
+// OpensearchSearchPipelineSpec defines a search pipeline. Each processor is an object with a single key, the type
+// of the processor, e.g. {"filter_query": {"query": {"term": {"visibility": "public"}}}}
+type OpensearchSearchPipelineSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the search pipeline. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// Optional description of the search pipeline
+	Description string `json:"description,omitempty"`
+
+	// Processors run on the search request before it is executed, in the order specified
+	RequestProcessors []apiextensionsv1.JSON `json:"requestProcessors,omitempty"`
+
+	// Processors run on the search response before it is returned, in the order specified
+	ResponseProcessors []apiextensionsv1.JSON `json:"responseProcessors,omitempty"`
+
+	// Processors run on the results of the query phase before the fetch phase, e.g. normalization-processor
+	PhaseResultsProcessors []apiextensionsv1.JSON `json:"phaseResultsProcessors,omitempty"`
+
+	// Version number used to manage the search pipeline externally
+	Version int `json:"version,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchSearchPipelineList contains a list of OpensearchSearchPipeline
+type OpensearchSearchPipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchSearchPipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchSearchPipeline{}, &OpensearchSearchPipelineList{})
+}
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchSearchTemplateState string
+
+const (
+	OpensearchSearchTemplatePending OpensearchSearchTemplateState = "PENDING"
+	OpensearchSearchTemplateCreated OpensearchSearchTemplateState = "CREATED"
+	OpensearchSearchTemplateError   OpensearchSearchTemplateState = "ERROR"
+	OpensearchSearchTemplateIgnored OpensearchSearchTemplateState = "IGNORED"
+	OpensearchSearchTemplateDeferred OpensearchSearchTemplateState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=opensearchsearchtemplate;searchtemplate
+//+kubebuilder:subresource:status
+
+// OpensearchSearchTemplate is the schema for search templates, mustache scripts of the OpenSearch stored scripts API
+type OpensearchSearchTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchSearchTemplateSpec   `json:"spec,omitempty"`
+	Status OpensearchSearchTemplateStatus `json:"status,omitempty"`
+}
+
+type OpensearchSearchTemplateStatus struct {
+	State            OpensearchSearchTemplateState `json:"state,omitempty"`
+	Reason           string                        `json:"reason,omitempty"`
+	ExistingTemplate *bool                         `json:"existingTemplate,omitempty"`
+	ManagedCluster   *types.UID                    `json:"managedCluster,omitempty"`
+	// Id of the currently managed search template
+	TemplateID string `json:"templateId,omitempty"`
+	// SHA1 hash of the source of the template as last applied by the operator
+	ContentHash string `json:"contentHash,omitempty"`
+	// Error OpenSearch reported when rendering the template, empty once the template renders
+	RenderError string `json:"renderError,omitempty"`
+}
+
+type OpensearchSearchTemplateSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The id of the search template, searches refer to the template by it. Defaults to metadata.name
+	// +immutable
+	TemplateID string `json:"templateId,omitempty"`
+
+	// Mustache source of the template, rendering it has to result in the body of a search request, e.g.
+	// {"query":{"match":{"title":"{{query}}"}}}
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// Optional params the template is rendered with before it is stored, e.g. {"query":"opensearch"}
+	Params *apiextensionsv1.JSON `json:"params,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchSearchTemplateList contains a list of OpensearchSearchTemplate
+type OpensearchSearchTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchSearchTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchSearchTemplate{}, &OpensearchSearchTemplateList{})
+}
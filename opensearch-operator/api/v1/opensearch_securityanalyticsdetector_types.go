@@ -0,0 +1,94 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchSecurityAnalyticsDetectorState string
+
+const (
+	OpensearchSecurityAnalyticsDetectorPending OpensearchSecurityAnalyticsDetectorState = "PENDING"
+	OpensearchSecurityAnalyticsDetectorCreated OpensearchSecurityAnalyticsDetectorState = "CREATED"
+	OpensearchSecurityAnalyticsDetectorError   OpensearchSecurityAnalyticsDetectorState = "ERROR"
+	OpensearchSecurityAnalyticsDetectorIgnored OpensearchSecurityAnalyticsDetectorState = "IGNORED"
+	OpensearchSecurityAnalyticsDetectorDeferred OpensearchSecurityAnalyticsDetectorState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=securityanalyticsdetector;sadetector
+//+kubebuilder:subresource:status
+
+// OpensearchSecurityAnalyticsDetector is the schema for the threat detectors of the OpenSearch Security Analytics plugin
+type OpensearchSecurityAnalyticsDetector struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchSecurityAnalyticsDetectorSpec   `json:"spec,omitempty"`
+	Status OpensearchSecurityAnalyticsDetectorStatus `json:"status,omitempty"`
+}
+
+type OpensearchSecurityAnalyticsDetectorStatus struct {
+	State            OpensearchSecurityAnalyticsDetectorState `json:"state,omitempty"`
+	Reason           string                                   `json:"reason,omitempty"`
+	ExistingDetector *bool                                    `json:"existingDetector,omitempty"`
+	ManagedCluster   *types.UID                               `json:"managedCluster,omitempty"`
+	// Name of the currently managed detector
+	DetectorName string `json:"detectorName,omitempty"`
+	// Id OpenSearch generated for the detector
+	DetectorID string `json:"detectorId,omitempty"`
+	// Whether the detector is enabled as reported by OpenSearch
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+type OpensearchSecurityAnalyticsDetectorSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the detector. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// Log type of the detector, e.g. windows, linux, network, dns, cloudtrail or s3
+	// +kubebuilder:validation:MinLength=1
+	DetectorType string `json:"detectorType"`
+
+	// Optional description of the input of the detector
+	Description string `json:"description,omitempty"`
+
+	// Whether the detector runs. Setting it to false disables the detector, setting it back to true enables it
+	// again. Defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// How often the detector runs, a whole number of minutes, e.g. 5m
+	// +kubebuilder:default="1m"
+	Schedule string `json:"schedule,omitempty"`
+
+	// Indices or index patterns the detector reads
+	// +kubebuilder:validation:MinItems=1
+	Indices []string `json:"indices"`
+
+	// Ids of prepackaged rules of the plugin the detector applies
+	PrePackagedRules []string `json:"prePackagedRules,omitempty"`
+
+	// OpensearchSecurityAnalyticsRule resources in the namespace of the detector the detector applies
+	CustomRules []corev1.LocalObjectReference `json:"customRules,omitempty"`
+
+	// Optional triggers of the detector as accepted by OpenSearch, e.g.
+	// {"name":"high severity","severity":"1","types":[],"ids":[],"tags":[],"sev_levels":["high"],"actions":[]}
+	Triggers []apiextensionsv1.JSON `json:"triggers,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchSecurityAnalyticsDetectorList contains a list of OpensearchSecurityAnalyticsDetector
+type OpensearchSecurityAnalyticsDetectorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchSecurityAnalyticsDetector `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchSecurityAnalyticsDetector{}, &OpensearchSecurityAnalyticsDetectorList{})
+}
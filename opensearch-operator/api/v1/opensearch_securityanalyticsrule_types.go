@@ -0,0 +1,62 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchSecurityAnalyticsRuleState string
+
+const (
+	OpensearchSecurityAnalyticsRulePending OpensearchSecurityAnalyticsRuleState = "PENDING"
+	OpensearchSecurityAnalyticsRuleCreated OpensearchSecurityAnalyticsRuleState = "CREATED"
+	OpensearchSecurityAnalyticsRuleError   OpensearchSecurityAnalyticsRuleState = "ERROR"
+	OpensearchSecurityAnalyticsRuleDeferred OpensearchSecurityAnalyticsRuleState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=securityanalyticsrule;sarule
+//+kubebuilder:subresource:status
+
+// OpensearchSecurityAnalyticsRule is the schema for the custom Sigma rules of the OpenSearch Security Analytics plugin
+type OpensearchSecurityAnalyticsRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchSecurityAnalyticsRuleSpec   `json:"spec,omitempty"`
+	Status OpensearchSecurityAnalyticsRuleStatus `json:"status,omitempty"`
+}
+
+type OpensearchSecurityAnalyticsRuleStatus struct {
+	State          OpensearchSecurityAnalyticsRuleState `json:"state,omitempty"`
+	Reason         string                               `json:"reason,omitempty"`
+	ManagedCluster *types.UID                           `json:"managedCluster,omitempty"`
+	// Id OpenSearch generated for the rule
+	RuleID string `json:"ruleId,omitempty"`
+}
+
+type OpensearchSecurityAnalyticsRuleSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// Log type the rule applies to, e.g. windows, linux, network, dns, cloudtrail or s3
+	// +kubebuilder:validation:MinLength=1
+	Category string `json:"category"`
+
+	// The rule in the Sigma YAML format
+	// +kubebuilder:validation:MinLength=1
+	Rule string `json:"rule"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchSecurityAnalyticsRuleList contains a list of OpensearchSecurityAnalyticsRule
+type OpensearchSecurityAnalyticsRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchSecurityAnalyticsRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchSecurityAnalyticsRule{}, &OpensearchSecurityAnalyticsRuleList{})
+}
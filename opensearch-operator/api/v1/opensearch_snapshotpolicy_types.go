@@ -0,0 +1,151 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchSnapshotPolicyState string
+
+const (
+	OpensearchSnapshotPolicyPending OpensearchSnapshotPolicyState = "PENDING"
+	OpensearchSnapshotPolicyCreated OpensearchSnapshotPolicyState = "CREATED"
+	OpensearchSnapshotPolicyError   OpensearchSnapshotPolicyState = "ERROR"
+	OpensearchSnapshotPolicyIgnored OpensearchSnapshotPolicyState = "IGNORED"
+	OpensearchSnapshotPolicyDeferred OpensearchSnapshotPolicyState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=smpolicy;snapshotpolicy
+//+kubebuilder:subresource:status
+
+// OpensearchSnapshotPolicy is the schema for the policies of the OpenSearch Snapshot Management plugin, which take
+// and delete snapshots on a schedule
+type OpensearchSnapshotPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchSnapshotPolicySpec   `json:"spec,omitempty"`
+	Status OpensearchSnapshotPolicyStatus `json:"status,omitempty"`
+}
+
+type OpensearchSnapshotPolicyStatus struct {
+	State                  OpensearchSnapshotPolicyState `json:"state,omitempty"`
+	Reason                 string                        `json:"reason,omitempty"`
+	ExistingSnapshotPolicy *bool                         `json:"existingSnapshotPolicy,omitempty"`
+	ManagedCluster         *types.UID                    `json:"managedCluster,omitempty"`
+	// Name of the currently managed snapshot policy
+	PolicyName string `json:"policyName,omitempty"`
+}
+
+type OpensearchSnapshotPolicySpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the snapshot policy. Defaults to metadata.name
+	// +immutable
+	PolicyName string `json:"policyName,omitempty"`
+
+	// Optional description of the snapshot policy
+	Description string `json:"description,omitempty"`
+
+	// Whether snapshots are taken and deleted. Defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// When snapshots are taken
+	Creation SnapshotCreation `json:"creation"`
+
+	// Optional retention of the snapshots taken by the policy. Snapshots are kept forever without it
+	Deletion *SnapshotDeletion `json:"deletion,omitempty"`
+
+	// The repository snapshots are taken into and what they include
+	SnapshotConfig SnapshotConfig `json:"snapshotConfig"`
+
+	// Optional notification about the snapshots of the policy
+	Notification *SnapshotNotification `json:"notification,omitempty"`
+}
+
+// CronSchedule is a cron expression evaluated in a time zone
+type CronSchedule struct {
+	// Cron expression, e.g. "0 8 * * *" for every day at 8:00
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+	// Time zone of the expression, e.g. "America/Los_Angeles"
+	// +kubebuilder:default=UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type SnapshotCreation struct {
+	// Schedule of the snapshots
+	Schedule CronSchedule `json:"schedule"`
+	// Optional maximum time a snapshot may take, e.g. 1h
+	TimeLimit string `json:"timeLimit,omitempty"`
+}
+
+type SnapshotDeletion struct {
+	// Optional schedule of the deletions, defaults to the schedule of the creation
+	Schedule *CronSchedule `json:"schedule,omitempty"`
+	// Which snapshots are deleted
+	Condition SnapshotDeleteCondition `json:"condition"`
+	// Optional maximum time the deletion may take, e.g. 1h
+	TimeLimit string `json:"timeLimit,omitempty"`
+}
+
+type SnapshotDeleteCondition struct {
+	// Snapshots older than this are deleted, e.g. 7d
+	MaxAge string `json:"maxAge,omitempty"`
+	// Only this many snapshots are kept
+	// +kubebuilder:validation:Minimum=1
+	MaxCount *int `json:"maxCount,omitempty"`
+	// At least this many snapshots are kept, even if they are older than maxAge
+	// +kubebuilder:validation:Minimum=1
+	MinCount *int `json:"minCount,omitempty"`
+}
+
+type SnapshotConfig struct {
+	// Name of the snapshot repository, it has to be registered in the cluster
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+	// Index patterns of the indices included in the snapshots. Defaults to all indices
+	Indices string `json:"indices,omitempty"`
+	// Date format appended to the names of the snapshots
+	DateFormat string `json:"dateFormat,omitempty"`
+	// Time zone of the date appended to the names of the snapshots
+	Timezone string `json:"timezone,omitempty"`
+	// Whether indices that are missing or closed are skipped instead of failing the snapshot
+	IgnoreUnavailable bool `json:"ignoreUnavailable,omitempty"`
+	// Whether the cluster state is included in the snapshots
+	IncludeGlobalState *bool `json:"includeGlobalState,omitempty"`
+	// Whether a snapshot is taken even if some shards are not available
+	Partial bool `json:"partial,omitempty"`
+	// Optional metadata added to the snapshots
+	Metadata *apiextensionsv1.JSON `json:"metadata,omitempty"`
+}
+
+type SnapshotNotification struct {
+	// Id of the notification channel
+	// +kubebuilder:validation:MinLength=1
+	ChannelID string `json:"channelId"`
+	// Whether a notification is sent once a snapshot was taken
+	Creation bool `json:"creation,omitempty"`
+	// Whether a notification is sent once snapshots were deleted
+	Deletion bool `json:"deletion,omitempty"`
+	// Whether a notification is sent if taking or deleting snapshots failed. Defaults to true
+	Failure *bool `json:"failure,omitempty"`
+	// Whether a notification is sent if taking or deleting snapshots exceeded the time limit
+	TimeLimitExceeded bool `json:"timeLimitExceeded,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchSnapshotPolicyList contains a list of OpensearchSnapshotPolicy
+type OpensearchSnapshotPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchSnapshotPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchSnapshotPolicy{}, &OpensearchSnapshotPolicyList{})
+}
@@ -0,0 +1,161 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchSnapshotRepositoryState string
+
+const (
+	OpensearchSnapshotRepositoryPending OpensearchSnapshotRepositoryState = "PENDING"
+	OpensearchSnapshotRepositoryCreated OpensearchSnapshotRepositoryState = "CREATED"
+	OpensearchSnapshotRepositoryError   OpensearchSnapshotRepositoryState = "ERROR"
+	OpensearchSnapshotRepositoryIgnored OpensearchSnapshotRepositoryState = "IGNORED"
+	OpensearchSnapshotRepositoryDeferred OpensearchSnapshotRepositoryState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=snapshotrepo;snapshotrepository
+//+kubebuilder:subresource:status
+
+// OpensearchSnapshotRepository is the schema for the OpenSearch snapshot repositories API
+type OpensearchSnapshotRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchSnapshotRepositorySpec   `json:"spec,omitempty"`
+	Status OpensearchSnapshotRepositoryStatus `json:"status,omitempty"`
+}
+
+type OpensearchSnapshotRepositoryStatus struct {
+	State                      OpensearchSnapshotRepositoryState `json:"state,omitempty"`
+	Reason                     string                            `json:"reason,omitempty"`
+	ExistingSnapshotRepository *bool                             `json:"existingSnapshotRepository,omitempty"`
+	ManagedCluster             *types.UID                        `json:"managedCluster,omitempty"`
+	// Name of the currently managed snapshot repository
+	RepositoryName string `json:"repositoryName,omitempty"`
+}
+
+// OpensearchSnapshotRepositorySpec registers a snapshot repository. Exactly one of s3, gcs, azure and fs has to be set.
+type OpensearchSnapshotRepositorySpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The name of the snapshot repository. Defaults to metadata.name
+	// +immutable
+	Name string `json:"name,omitempty"`
+
+	// Repository in an S3 bucket, requires the repository-s3 plugin
+	S3 *S3SnapshotRepository `json:"s3,omitempty"`
+	// Repository in a Google Cloud Storage bucket, requires the repository-gcs plugin
+	GCS *GCSSnapshotRepository `json:"gcs,omitempty"`
+	// Repository in an Azure Blob Storage container, requires the repository-azure plugin
+	Azure *AzureSnapshotRepository `json:"azure,omitempty"`
+	// Repository in a shared file system mounted on all nodes
+	FS *FSSnapshotRepository `json:"fs,omitempty"`
+
+	// If true, snapshots cannot be taken into the repository, only restored from it
+	Readonly bool `json:"readonly,omitempty"`
+	// Whether the metadata files of the snapshots are compressed
+	Compress bool `json:"compress,omitempty"`
+	// Optional maximum size of the files of the snapshots, e.g. 1gb
+	ChunkSize string `json:"chunkSize,omitempty"`
+	// Optional maximum rate snapshots are taken per node, e.g. 40mb
+	MaxSnapshotBytesPerSec string `json:"maxSnapshotBytesPerSec,omitempty"`
+	// Optional maximum rate snapshots are restored per node, e.g. 40mb
+	MaxRestoreBytesPerSec string `json:"maxRestoreBytesPerSec,omitempty"`
+	// Additional settings of the repository that have no field, they must not repeat the settings of the fields
+	AdditionalSettings map[string]string `json:"additionalSettings,omitempty"`
+
+	// Whether OpenSearch verifies that all nodes can access the repository when it is registered. Defaults to true
+	Verify *bool `json:"verify,omitempty"`
+}
+
+type S3SnapshotRepository struct {
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+	// Optional path within the bucket
+	BasePath string `json:"basePath,omitempty"`
+	// Name of the S3 client the credentials are stored for in the keystore. Defaults to default
+	Client string `json:"client,omitempty"`
+	// Optional region of the bucket
+	Region string `json:"region,omitempty"`
+	// Optional endpoint of an S3 compatible service
+	Endpoint string `json:"endpoint,omitempty"`
+	// Whether the files are encrypted on the server side
+	ServerSideEncryption bool `json:"serverSideEncryption,omitempty"`
+	// Optional storage class of the files, e.g. standard_ia
+	StorageClass string `json:"storageClass,omitempty"`
+	// Optional secret with the access key and secret key of the client. The secret has to be added to the keystore
+	// of the cluster, it is checked before the repository is registered
+	Credentials *S3Credentials `json:"credentials,omitempty"`
+}
+
+type S3Credentials struct {
+	Secret corev1.LocalObjectReference `json:"secret"`
+	// Key of the access key in the secret. Defaults to access_key
+	AccessKeyKey string `json:"accessKeyKey,omitempty"`
+	// Key of the secret key in the secret. Defaults to secret_key
+	SecretKeyKey string `json:"secretKeyKey,omitempty"`
+}
+
+type GCSSnapshotRepository struct {
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+	// Optional path within the bucket
+	BasePath string `json:"basePath,omitempty"`
+	// Name of the GCS client the credentials are stored for in the keystore. Defaults to default
+	Client string `json:"client,omitempty"`
+	// Optional secret with the service account credentials file of the client. The secret has to be added to the
+	// keystore of the cluster, it is checked before the repository is registered
+	Credentials *GCSCredentials `json:"credentials,omitempty"`
+}
+
+type GCSCredentials struct {
+	Secret corev1.LocalObjectReference `json:"secret"`
+	// Key of the credentials file in the secret. Defaults to credentials_file
+	CredentialsFileKey string `json:"credentialsFileKey,omitempty"`
+}
+
+type AzureSnapshotRepository struct {
+	// +kubebuilder:validation:MinLength=1
+	Container string `json:"container"`
+	// Optional path within the container
+	BasePath string `json:"basePath,omitempty"`
+	// Name of the Azure client the credentials are stored for in the keystore. Defaults to default
+	Client string `json:"client,omitempty"`
+	// Optional location mode, primary_only (default) or secondary_only
+	// +kubebuilder:validation:Enum=primary_only;secondary_only
+	LocationMode string `json:"locationMode,omitempty"`
+	// Optional secret with the storage account and key of the client. The secret has to be added to the keystore
+	// of the cluster, it is checked before the repository is registered
+	Credentials *AzureCredentials `json:"credentials,omitempty"`
+}
+
+type AzureCredentials struct {
+	Secret corev1.LocalObjectReference `json:"secret"`
+	// Key of the storage account name in the secret. Defaults to account
+	AccountKey string `json:"accountKey,omitempty"`
+	// Key of the storage account key in the secret. Defaults to key
+	KeyKey string `json:"keyKey,omitempty"`
+}
+
+type FSSnapshotRepository struct {
+	// Absolute path of the repository, it has to be listed in path.repo of all nodes
+	// +kubebuilder:validation:MinLength=1
+	Location string `json:"location"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchSnapshotRepositoryList contains a list of OpensearchSnapshotRepository
+type OpensearchSnapshotRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchSnapshotRepository `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchSnapshotRepository{}, &OpensearchSnapshotRepositoryList{})
+}
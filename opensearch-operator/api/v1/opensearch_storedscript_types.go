@@ -0,0 +1,75 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchStoredScriptState string
+
+const (
+	OpensearchStoredScriptPending OpensearchStoredScriptState = "PENDING"
+	OpensearchStoredScriptCreated OpensearchStoredScriptState = "CREATED"
+	OpensearchStoredScriptError   OpensearchStoredScriptState = "ERROR"
+	OpensearchStoredScriptIgnored OpensearchStoredScriptState = "IGNORED"
+	OpensearchStoredScriptDeferred OpensearchStoredScriptState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=opensearchstoredscript;storedscript
+//+kubebuilder:subresource:status
+
+// OpensearchStoredScript is the schema for the OpenSearch stored scripts API
+type OpensearchStoredScript struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchStoredScriptSpec   `json:"spec,omitempty"`
+	Status OpensearchStoredScriptStatus `json:"status,omitempty"`
+}
+
+type OpensearchStoredScriptStatus struct {
+	State          OpensearchStoredScriptState `json:"state,omitempty"`
+	Reason         string                      `json:"reason,omitempty"`
+	ExistingScript *bool                       `json:"existingScript,omitempty"`
+	ManagedCluster *types.UID                  `json:"managedCluster,omitempty"`
+	// Id of the currently managed stored script
+	ScriptID string `json:"scriptId,omitempty"`
+	// SHA1 hash of the language, source and options of the script as last applied by the operator
+	ContentHash string `json:"contentHash,omitempty"`
+}
+
+type OpensearchStoredScriptSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The id of the stored script, templates, pipelines and monitors refer to the script by it. Defaults to
+	// metadata.name
+	// +immutable
+	ScriptID string `json:"scriptId,omitempty"`
+
+	// Language of the script
+	// +kubebuilder:validation:Enum=painless;mustache;expression
+	// +kubebuilder:default=painless
+	Lang string `json:"lang,omitempty"`
+
+	// Source of the script. OpenSearch compiles painless scripts when they are stored and rejects invalid ones
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// Optional options of the script, e.g. content_type for mustache templates
+	Options map[string]string `json:"options,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchStoredScriptList contains a list of OpensearchStoredScript
+type OpensearchStoredScriptList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchStoredScript `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchStoredScript{}, &OpensearchStoredScriptList{})
+}
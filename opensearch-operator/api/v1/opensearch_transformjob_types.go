@@ -0,0 +1,148 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchTransformJobState string
+
+const (
+	OpensearchTransformJobPending OpensearchTransformJobState = "PENDING"
+	OpensearchTransformJobCreated OpensearchTransformJobState = "CREATED"
+	OpensearchTransformJobError   OpensearchTransformJobState = "ERROR"
+	OpensearchTransformJobIgnored OpensearchTransformJobState = "IGNORED"
+	OpensearchTransformJobDeferred OpensearchTransformJobState = "DEFERRED"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=transform;transformjob
+//+kubebuilder:subresource:status
+
+// OpensearchTransformJob is the schema for the jobs of the OpenSearch Index Transforms plugin
+type OpensearchTransformJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchTransformJobSpec   `json:"spec,omitempty"`
+	Status OpensearchTransformJobStatus `json:"status,omitempty"`
+}
+
+type OpensearchTransformJobStatus struct {
+	State          OpensearchTransformJobState `json:"state,omitempty"`
+	Reason         string                      `json:"reason,omitempty"`
+	ExistingJob    *bool                       `json:"existingJob,omitempty"`
+	ManagedCluster *types.UID                  `json:"managedCluster,omitempty"`
+	// Id of the currently managed transform job
+	JobID string `json:"jobId,omitempty"`
+	// State of the last run of the job as reported by OpenSearch, empty before the first run
+	LastRun *TransformRunStatus `json:"lastRun,omitempty"`
+}
+
+type TransformRunStatus struct {
+	// Status of the job, one of init, started, stopped, finished and failed
+	Status string `json:"status,omitempty"`
+	// Why the job failed
+	FailureReason string `json:"failureReason,omitempty"`
+	// When OpenSearch last updated the state of the job
+	LastUpdatedAt *metav1.Time `json:"lastUpdatedAt,omitempty"`
+	// Number of pages of the source index processed by the job
+	PagesProcessed int64 `json:"pagesProcessed,omitempty"`
+	// Number of documents of the source index processed by the job
+	DocumentsProcessed int64 `json:"documentsProcessed,omitempty"`
+	// Number of documents written to the target index by the job
+	DocumentsIndexed int64 `json:"documentsIndexed,omitempty"`
+}
+
+// OpensearchTransformJobSpec defines a transform job. The source and target index, the data selection query,
+// continuous, groups and aggregations cannot be changed once the job is created.
+type OpensearchTransformJobSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The id of the transform job. Defaults to metadata.name
+	// +immutable
+	JobID string `json:"jobId,omitempty"`
+
+	// Optional description of the transform job
+	Description string `json:"description,omitempty"`
+
+	// Whether the job runs. Setting it to false stops the job, setting it back to true starts it again. Defaults
+	// to true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// When the job runs
+	Schedule RollupSchedule `json:"schedule"`
+
+	// Index or index pattern the documents are transformed from
+	// +kubebuilder:validation:MinLength=1
+	// +immutable
+	SourceIndex string `json:"sourceIndex"`
+
+	// Index the transformed documents are written to, it is created by the job if it does not exist
+	// +kubebuilder:validation:MinLength=1
+	// +immutable
+	TargetIndex string `json:"targetIndex"`
+
+	// Optional query selecting the documents of the source index that are transformed. Defaults to all documents
+	// +immutable
+	DataSelectionQuery *apiextensionsv1.JSON `json:"dataSelectionQuery,omitempty"`
+
+	// Number of buckets processed per search of the job
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10000
+	// +kubebuilder:default=1000
+	PageSize int `json:"pageSize,omitempty"`
+
+	// If true, the job keeps transforming new documents, otherwise it stops after transforming the current documents
+	// +immutable
+	Continuous bool `json:"continuous,omitempty"`
+
+	// The fields the documents are grouped by, each group becomes a document of the target index
+	// +kubebuilder:validation:MinItems=1
+	// +immutable
+	Groups []TransformGroup `json:"groups"`
+
+	// Optional aggregations computed for the groups by name, e.g. {"avg_price":{"avg":{"field":"price"}}}
+	// +immutable
+	Aggregations *apiextensionsv1.JSON `json:"aggregations,omitempty"`
+}
+
+// TransformGroup groups the documents by a field, exactly one of dateHistogram, terms and histogram has to be set
+type TransformGroup struct {
+	DateHistogram *TransformDateHistogram `json:"dateHistogram,omitempty"`
+	Terms         *TransformTerms         `json:"terms,omitempty"`
+	Histogram     *TransformHistogram     `json:"histogram,omitempty"`
+}
+
+type TransformDateHistogram struct {
+	RollupDateHistogram `json:",inline"`
+	// Field of the target index the group is written to. Defaults to the source field
+	TargetField string `json:"targetField,omitempty"`
+}
+
+type TransformTerms struct {
+	RollupTerms `json:",inline"`
+	// Field of the target index the group is written to. Defaults to the source field
+	TargetField string `json:"targetField,omitempty"`
+}
+
+type TransformHistogram struct {
+	RollupHistogram `json:",inline"`
+	// Field of the target index the group is written to. Defaults to the source field
+	TargetField string `json:"targetField,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchTransformJobList contains a list of OpensearchTransformJob
+type OpensearchTransformJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchTransformJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchTransformJob{}, &OpensearchTransformJobList{})
+}
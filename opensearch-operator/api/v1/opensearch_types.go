@@ -56,10 +56,13 @@ type GeneralConfig struct {
 	AdditionalConfig map[string]string `json:"additionalConfig,omitempty"`
 	// Adds support for annotations in services
 	Annotations map[string]string `json:"annotations,omitempty"`
-	// Drain data nodes controls whether to drain data notes on rolling restart operations
-	DrainDataNodes bool     `json:"drainDataNodes,omitempty"`
-	PluginsList    []string `json:"pluginsList,omitempty"`
-	Command        string   `json:"command,omitempty"`
+	// Drain data nodes controls whether to drain data notes on rolling restart operations and before they are removed
+	// on scale down
+	DrainDataNodes bool `json:"drainDataNodes,omitempty"`
+	// Create a PodDisruptionBudget with default limits for every node pool without a pdb section and with more than one replica
+	DefaultPdbs bool     `json:"defaultPdbs,omitempty"`
+	PluginsList []string `json:"pluginsList,omitempty"`
+	Command     string   `json:"command,omitempty"`
 	// Additional volumes to mount to all pods in the cluster
 	AdditionalVolumes []AdditionalVolume `json:"additionalVolumes,omitempty"`
 	Monitoring        MonitoringConfig   `json:"monitoring,omitempty"`
@@ -102,6 +105,75 @@ type NodePool struct {
 	Env                       []corev1.EnvVar                   `json:"env,omitempty"`
 	PriorityClassName         string                            `json:"priorityClassName,omitempty"`
 	Pdb                       *PdbConfig                        `json:"pdb,omitempty"`
+	Autoscaling               *NodePoolAutoscaling              `json:"autoscaling,omitempty"`
+	ScalingSchedules          []ScalingSchedule                 `json:"scalingSchedules,omitempty"`
+	ZoneAwareness             *ZoneAwarenessConfig              `json:"zoneAwareness,omitempty"`
+}
+
+// ZoneAwarenessConfig spreads the pods of a node pool across zones and passes the zone of every pod to OpenSearch as a
+// node attribute, so that shard allocation awareness places the copies of a shard in different zones
+type ZoneAwarenessConfig struct {
+	Enable bool `json:"enable,omitempty"`
+	// Label of the Kubernetes nodes holding their zone
+	// +kubebuilder:default="topology.kubernetes.io/zone"
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// Name of the OpenSearch node attribute holding the zone
+	// +kubebuilder:default=zone
+	Attribute string `json:"attribute,omitempty"`
+	// Maximum difference of the number of pods of the pool between two zones
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+	// +kubebuilder:default=DoNotSchedule
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+	// All zones of the cluster for forced awareness. If a zone fails, the copies of its shards are not allocated to
+	// the remaining zones
+	ForcedZones []string `json:"forcedZones,omitempty"`
+}
+
+// ScalingSchedule sets the replicas of a node pool from the time its cron expression fires until another schedule of
+// the pool fires. With autoscaling the replicas are the minimum replicas of the autoscaler instead
+type ScalingSchedule struct {
+	// +kubebuilder:validation:MinLength=1
+	Name     string       `json:"name"`
+	Schedule CronSchedule `json:"schedule"`
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas"`
+}
+
+// NodePoolAutoscaling scales the replicas of a node pool between MinReplicas and MaxReplicas so that the average
+// usage of its nodes, as reported by the _nodes/stats API, stays close to the configured targets
+type NodePoolAutoscaling struct {
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 `json:"minReplicas"`
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// Average CPU usage of the nodes in percent
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetCPUUtilization *int32 `json:"targetCpuUtilization,omitempty"`
+	// Average JVM heap usage of the nodes in percent
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetJvmHeapPressure *int32 `json:"targetJvmHeapPressure,omitempty"`
+	// Average disk usage of the nodes in percent, should be below the low disk watermark of the cluster
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetDiskUsage *int32 `json:"targetDiskUsage,omitempty"`
+	// Time to wait after the last scaling operation of the pool before it is scaled down
+	// +kubebuilder:default="5m"
+	ScaleDownStabilizationWindow string `json:"scaleDownStabilizationWindow,omitempty"`
+	// Vertical scales the cpu and memory of the nodes once the replicas reached their bounds
+	Vertical *NodePoolVerticalAutoscaling `json:"vertical,omitempty"`
+}
+
+// NodePoolVerticalAutoscaling scales the cpu and memory of the nodes of a node pool between MinResources and
+// MaxResources. The cpu follows the cpu utilization and the memory the jvm heap pressure, they grow while the pool
+// has its max replicas and shrink while it has its min replicas. Only the resources set in both bounds are scaled
+type NodePoolVerticalAutoscaling struct {
+	MinResources corev1.ResourceList `json:"minResources"`
+	MaxResources corev1.ResourceList `json:"maxResources"`
 }
 
 // PersistencConfig defines options for data persistence
@@ -125,8 +197,23 @@ type ConfMgmt struct {
 	AutoScaler  bool `json:"autoScaler,omitempty"`
 	VerUpdate   bool `json:"VerUpdate,omitempty"`
 	SmartScaler bool `json:"smartScaler,omitempty"`
+	// Time after which a drain of a node that has not finished is stopped with the drainTimeoutAction
+	// +kubebuilder:default="30m"
+	DrainTimeout string `json:"drainTimeout,omitempty"`
+	// What happens to a scale down whose drain has not finished within the drainTimeout. Fail takes the node back into
+	// the cluster and retries the scale down once the drainTimeout has passed again, Proceed removes the node with
+	// the shards it still holds and keeps its PVC. Defaults to Fail
+	// +kubebuilder:validation:Enum=Fail;Proceed
+	DrainTimeoutAction DrainTimeoutAction `json:"drainTimeoutAction,omitempty"`
 }
 
+type DrainTimeoutAction string
+
+const (
+	DrainTimeoutActionFail    DrainTimeoutAction = "Fail"
+	DrainTimeoutActionProceed DrainTimeoutAction = "Proceed"
+)
+
 type MonitoringConfig struct {
 	Enable               bool                 `json:"enable,omitempty"`
 	MonitoringUserSecret string               `json:"monitoringUserSecret,omitempty"`
@@ -244,8 +331,41 @@ type SecurityConfig struct {
 	AdminSecret corev1.LocalObjectReference `json:"adminSecret,omitempty"`
 	// Secret that contains fields username and password to be used by the operator to access the opensearch cluster for node draining. Must be set if custom securityconfig is provided.
 	AdminCredentialsSecret corev1.LocalObjectReference `json:"adminCredentialsSecret,omitempty"`
+	// How the operator authenticates to the opensearch cluster. basic uses the adminCredentialsSecret, clientCertificate presents the client certificate of the operatorClientCertSecret, bearerToken sends the token of the operatorBearerToken. Defaults to basic
+	// +kubebuilder:validation:Enum=basic;clientCertificate;bearerToken
+	OperatorAuthMode OperatorAuthMode `json:"operatorAuthMode,omitempty"`
+	// TLS Secret that contains the client certificate (tls.crt, tls.key) the operator authenticates with in the clientCertificate auth mode
+	OperatorClientCertSecret corev1.LocalObjectReference `json:"operatorClientCertSecret,omitempty"`
+	// Where the bearer token the operator authenticates with in the bearerToken auth mode is taken from
+	OperatorBearerToken *OperatorBearerToken `json:"operatorBearerToken,omitempty"`
 }
 
+// OperatorBearerToken is the source of the bearer token the operator authenticates with, either a Secret or an OIDC provider
+type OperatorBearerToken struct {
+	// Secret that contains the token in its token field. It is read again when the cluster rejects the token, so a rotated token is picked up
+	TokenSecret *corev1.LocalObjectReference `json:"tokenSecret,omitempty"`
+	// OIDC provider the token is requested from with the client credentials flow
+	OIDC *OperatorOIDCProvider `json:"oidc,omitempty"`
+}
+
+// OperatorOIDCProvider is the OIDC provider the operator requests its bearer token from. The token is refreshed before it expires and when the cluster rejects it
+type OperatorOIDCProvider struct {
+	// Token endpoint of the OIDC provider
+	TokenURL string `json:"tokenURL"`
+	// Secret that contains the fields clientID and clientSecret of the OIDC client of the operator
+	ClientSecret corev1.LocalObjectReference `json:"clientSecret"`
+	// Scopes requested for the token
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+type OperatorAuthMode string
+
+const (
+	OperatorAuthModeBasic             OperatorAuthMode = "basic"
+	OperatorAuthModeClientCertificate OperatorAuthMode = "clientCertificate"
+	OperatorAuthModeBearerToken       OperatorAuthMode = "bearerToken"
+)
+
 type ImageSpec struct {
 	Image            *string                       `json:"image,omitempty"`
 	ImagePullPolicy  *corev1.PullPolicy            `json:"imagePullPolicy,omitempty"`
@@ -306,6 +426,46 @@ type ClusterStatus struct {
 	// AvailableNodes is the number of available instances.
 	AvailableNodes int32            `json:"availableNodes,omitempty"`
 	Health         OpenSearchHealth `json:"health,omitempty"`
+	// Autoscaling holds the state of the autoscaler for every node pool with autoscaling enabled
+	Autoscaling []NodePoolAutoscalingStatus `json:"autoscaling,omitempty"`
+	// ScalingSchedules holds the active scaling schedule of every node pool with scaling schedules
+	ScalingSchedules []ActiveScalingSchedule `json:"scalingSchedules,omitempty"`
+	// Drains holds the progress of every node that is drained before it is removed on scale down
+	Drains []NodeDrainStatus `json:"drains,omitempty"`
+}
+
+// NodeDrainStatus is the progress of moving the shards off a node before it is removed on scale down
+type NodeDrainStatus struct {
+	Component       string      `json:"component"`
+	Node            string      `json:"node"`
+	StartTime       metav1.Time `json:"startTime"`
+	RemainingShards int32       `json:"remainingShards"`
+	// TimedOut is set once the drain takes longer than the drain timeout and the drain timeout action was taken
+	TimedOut bool `json:"timedOut,omitempty"`
+	// RetryTime is the time after which a scale down whose drain failed is retried
+	RetryTime *metav1.Time `json:"retryTime,omitempty"`
+}
+
+// ActiveScalingSchedule is the scaling schedule of a node pool that fired last
+type ActiveScalingSchedule struct {
+	Component string      `json:"component"`
+	Name      string      `json:"name"`
+	Replicas  int32       `json:"replicas"`
+	Since     metav1.Time `json:"since"`
+}
+
+// NodePoolAutoscalingStatus is the last decision of the autoscaler for a node pool
+type NodePoolAutoscalingStatus struct {
+	Component       string `json:"component"`
+	DesiredReplicas int32  `json:"desiredReplicas"`
+	// Average usage of the nodes in percent at the time of the decision
+	CPUUtilization  *int32       `json:"cpuUtilization,omitempty"`
+	JvmHeapPressure *int32       `json:"jvmHeapPressure,omitempty"`
+	DiskUsage       *int32       `json:"diskUsage,omitempty"`
+	Reason          string       `json:"reason,omitempty"`
+	LastScaleTime   *metav1.Time `json:"lastScaleTime,omitempty"`
+	// Resources of the nodes decided by the vertical autoscaler
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // +kubebuilder:object:root=true
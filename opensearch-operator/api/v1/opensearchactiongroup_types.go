@@ -13,6 +13,7 @@ const (
 	OpensearchActionGroupCreated OpensearchActionGroupState = "CREATED"
 	OpensearchActionGroupError   OpensearchActionGroupState = "ERROR"
 	OpensearchActionGroupIgnored OpensearchActionGroupState = "IGNORED"
+	OpensearchActionGroupDeferred OpensearchActionGroupState = "DEFERRED"
 )
 
 // OpensearchActionGroupSpec defines the desired state of OpensearchActionGroup
@@ -0,0 +1,97 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchAuditConfigState string
+
+const (
+	OpensearchAuditConfigStatePending OpensearchAuditConfigState = "PENDING"
+	OpensearchAuditConfigStateCreated OpensearchAuditConfigState = "CREATED"
+	OpensearchAuditConfigStateError   OpensearchAuditConfigState = "ERROR"
+	OpensearchAuditConfigStateDeferred OpensearchAuditConfigState = "DEFERRED"
+)
+
+// OpensearchAuditConfigSpec defines the desired audit configuration of the security plugin. Only the fields that are
+// set are managed, the others keep the value they have in OpenSearch. An empty list is set, e.g. to ignore no users.
+type OpensearchAuditConfigSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+	// Enables the audit log
+	Enabled    *bool           `json:"enabled,omitempty"`
+	Audit      *AuditSpec      `json:"audit,omitempty"`
+	Compliance *ComplianceSpec `json:"compliance,omitempty"`
+}
+
+// AuditSpec defines the events of the REST and transport layers that are audited
+type AuditSpec struct {
+	EnableRest *bool `json:"enableRest,omitempty"`
+	// Categories of REST events that are not logged, e.g. AUTHENTICATED or GRANTED_PRIVILEGES
+	DisabledRestCategories []string `json:"disabledRestCategories,omitempty"`
+	EnableTransport        *bool    `json:"enableTransport,omitempty"`
+	// Categories of transport events that are not logged, e.g. AUTHENTICATED or GRANTED_PRIVILEGES
+	DisabledTransportCategories []string `json:"disabledTransportCategories,omitempty"`
+	ResolveBulkRequests         *bool    `json:"resolveBulkRequests,omitempty"`
+	LogRequestBody              *bool    `json:"logRequestBody,omitempty"`
+	ResolveIndices              *bool    `json:"resolveIndices,omitempty"`
+	ExcludeSensitiveHeaders     *bool    `json:"excludeSensitiveHeaders,omitempty"`
+	// Users whose requests are not logged, with * and ? wildcards
+	IgnoreUsers []string `json:"ignoreUsers,omitempty"`
+	// Request patterns that are not logged, with * and ? wildcards
+	IgnoreRequests []string `json:"ignoreRequests,omitempty"`
+}
+
+// ComplianceSpec defines the compliance events, the reads and writes of watched indices and fields, that are audited
+type ComplianceSpec struct {
+	Enabled           *bool `json:"enabled,omitempty"`
+	InternalConfig    *bool `json:"internalConfig,omitempty"`
+	ExternalConfig    *bool `json:"externalConfig,omitempty"`
+	ReadMetadataOnly  *bool `json:"readMetadataOnly,omitempty"`
+	WriteMetadataOnly *bool `json:"writeMetadataOnly,omitempty"`
+	WriteLogDiffs     *bool `json:"writeLogDiffs,omitempty"`
+	// Fields whose reads are logged by index pattern
+	ReadWatchedFields map[string][]string `json:"readWatchedFields,omitempty"`
+	// Users whose reads are not logged
+	ReadIgnoreUsers []string `json:"readIgnoreUsers,omitempty"`
+	// Index patterns whose writes are logged
+	WriteWatchedIndices []string `json:"writeWatchedIndices,omitempty"`
+	// Users whose writes are not logged
+	WriteIgnoreUsers []string `json:"writeIgnoreUsers,omitempty"`
+}
+
+// OpensearchAuditConfigStatus defines the observed state of OpensearchAuditConfig
+type OpensearchAuditConfigStatus struct {
+	State          OpensearchAuditConfigState `json:"state,omitempty"`
+	Reason         string                     `json:"reason,omitempty"`
+	ManagedCluster *types.UID                 `json:"managedCluster,omitempty"`
+	// SHA1 hash of the managed audit configuration as last applied by the operator
+	ContentHash string `json:"contentHash,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=opensearchauditconfig;auditconfig
+//+kubebuilder:subresource:status
+
+// OpensearchAuditConfig is the Schema for the opensearchauditconfigs API
+type OpensearchAuditConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchAuditConfigSpec   `json:"spec,omitempty"`
+	Status OpensearchAuditConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchAuditConfigList contains a list of OpensearchAuditConfig
+type OpensearchAuditConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchAuditConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchAuditConfig{}, &OpensearchAuditConfigList{})
+}
@@ -13,6 +13,7 @@ const (
 	OpensearchISMPolicyCreated OpensearchISMPolicyState = "CREATED"
 	OpensearchISMPolicyError   OpensearchISMPolicyState = "ERROR"
 	OpensearchISMPolicyIgnored OpensearchISMPolicyState = "IGNORED"
+	OpensearchISMPolicyDeferred OpensearchISMPolicyState = "DEFERRED"
 )
 
 // OpensearchISMPolicyStatus defines the observed state of OpensearchISMPolicy
@@ -22,6 +23,8 @@ type OpensearchISMPolicyStatus struct {
 	ExistingISMPolicy *bool                    `json:"existingISMPolicy,omitempty"`
 	ManagedCluster    *types.UID               `json:"managedCluster,omitempty"`
 	PolicyId          string                   `json:"policyId,omitempty"`
+	// Existing indices the operator attached the policy to
+	AttachedIndices []string `json:"attachedIndices,omitempty"`
 }
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -50,6 +53,9 @@ type OpenSearchISMPolicySpec struct {
 	PolicyID    string       `json:"policyId,omitempty"`
 	// The states that you define in the policy.
 	States []State `json:"states"`
+	// If true, the policy is also attached to existing indices matching the index patterns of the ISM template,
+	// not only to indices created afterwards. The policy is detached from these indices when it is deleted.
+	ApplyToExistingIndices bool `json:"applyToExistingIndices,omitempty"`
 }
 
 type ErrorNotification struct {
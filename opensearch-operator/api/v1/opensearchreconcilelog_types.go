@@ -0,0 +1,57 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=osreconcilelog
+
+// OpensearchReconcileLog keeps a bounded history of the significant reconcile transitions of a managed object.
+// Unlike Kubernetes events it is not subject to the event TTL, and it is kept after the managed object is deleted.
+type OpensearchReconcileLog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OpensearchReconcileLogSpec `json:"spec,omitempty"`
+}
+
+type OpensearchReconcileLogSpec struct {
+	// The object whose reconcile transitions are recorded
+	Target ReconcileLogTarget `json:"target"`
+
+	// Recorded transitions, oldest first
+	Entries []ReconcileLogEntry `json:"entries,omitempty"`
+}
+
+type ReconcileLogTarget struct {
+	Kind string    `json:"kind"`
+	Name string    `json:"name"`
+	UID  types.UID `json:"uid,omitempty"`
+}
+
+type ReconcileLogEntry struct {
+	// Time the transition was observed
+	Time metav1.Time `json:"time"`
+	// Generation of the object that was reconciled
+	Generation int64 `json:"generation,omitempty"`
+	// State before the transition
+	PreviousState string `json:"previousState,omitempty"`
+	// State after the transition
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchReconcileLogList contains a list of OpensearchReconcileLog
+type OpensearchReconcileLogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchReconcileLog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchReconcileLog{}, &OpensearchReconcileLogList{})
+}
@@ -29,6 +29,7 @@ const (
 	OpensearchRoleStateCreated OpensearchRoleState = "CREATED"
 	OpensearchRoleStateError   OpensearchRoleState = "ERROR"
 	OpensearchRoleIgnored      OpensearchRoleState = "IGNORED"
+	OpensearchRoleStateDeferred OpensearchRoleState = "DEFERRED"
 )
 
 // OpensearchRoleSpec defines the desired state of OpensearchRole
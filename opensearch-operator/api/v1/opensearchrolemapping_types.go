@@ -0,0 +1,66 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchRoleMappingState string
+
+const (
+	OpensearchRoleMappingStatePending OpensearchRoleMappingState = "PENDING"
+	OpensearchRoleMappingStateCreated OpensearchRoleMappingState = "CREATED"
+	OpensearchRoleMappingStateError   OpensearchRoleMappingState = "ERROR"
+	OpensearchRoleMappingIgnored      OpensearchRoleMappingState = "IGNORED"
+	OpensearchRoleMappingStateDeferred OpensearchRoleMappingState = "DEFERRED"
+)
+
+// OpensearchRoleMappingSpec defines the desired state of OpensearchRoleMapping
+type OpensearchRoleMappingSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+	// Name of the role that is mapped, defaults to the name of the resource
+	RoleName string `json:"roleName,omitempty"`
+	// Backend roles of users authenticated by an external backend, e.g. LDAP groups or SAML and OIDC roles
+	BackendRoles []string `json:"backendRoles,omitempty"`
+	// Hosts requests are mapped to the role from, by hostname or IP address with * and ? wildcards
+	Hosts []string `json:"hosts,omitempty"`
+	// Users mapped to the role, whether they are internal users or authenticated by an external backend
+	Users []string `json:"users,omitempty"`
+}
+
+// OpensearchRoleMappingStatus defines the observed state of OpensearchRoleMapping
+type OpensearchRoleMappingStatus struct {
+	State               OpensearchRoleMappingState `json:"state,omitempty"`
+	Reason              string                     `json:"reason,omitempty"`
+	ExistingRoleMapping *bool                      `json:"existingRoleMapping,omitempty"`
+	ManagedCluster      *types.UID                 `json:"managedCluster,omitempty"`
+	// Name of the role the operator mapped
+	RoleName string `json:"roleName,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=opensearchrolemapping
+//+kubebuilder:subresource:status
+
+// OpensearchRoleMapping is the Schema for the opensearchrolemappings API
+type OpensearchRoleMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchRoleMappingSpec   `json:"spec,omitempty"`
+	Status OpensearchRoleMappingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchRoleMappingList contains a list of OpensearchRoleMapping
+type OpensearchRoleMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchRoleMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchRoleMapping{}, &OpensearchRoleMappingList{})
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OpensearchSavedObjectsState string
+
+const (
+	OpensearchSavedObjectsPending OpensearchSavedObjectsState = "PENDING"
+	OpensearchSavedObjectsCreated OpensearchSavedObjectsState = "CREATED"
+	OpensearchSavedObjectsError   OpensearchSavedObjectsState = "ERROR"
+	OpensearchSavedObjectsDeferred OpensearchSavedObjectsState = "DEFERRED"
+)
+
+// OpensearchSavedObjectsSpec defines the desired state of OpensearchSavedObjects
+type OpensearchSavedObjectsSpec struct {
+	OpensearchRef corev1.LocalObjectReference `json:"opensearchCluster"`
+
+	// The OpensearchTenant the saved objects are imported into
+	TenantRef corev1.LocalObjectReference `json:"tenant"`
+
+	// Saved objects in the NDJSON format produced by the Dashboards saved objects export
+	Objects string `json:"objects"`
+
+	// If true, saved objects that already exist in the tenant are overwritten, otherwise they are skipped
+	Overwrite bool `json:"overwrite,omitempty"`
+}
+
+// OpensearchSavedObjectsStatus defines the observed state of OpensearchSavedObjects
+type OpensearchSavedObjectsStatus struct {
+	State          OpensearchSavedObjectsState `json:"state,omitempty"`
+	Reason         string                      `json:"reason,omitempty"`
+	ManagedCluster *types.UID                  `json:"managedCluster,omitempty"`
+	// Saved objects imported by the operator, these are removed again when the resource is deleted
+	ImportedObjects []SavedObjectReference `json:"importedObjects,omitempty"`
+	// Hash of the last successfully imported spec
+	ObjectsHash string `json:"objectsHash,omitempty"`
+}
+
+type SavedObjectReference struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchSavedObjects is the Schema for the opensearchsavedobjects API
+type OpensearchSavedObjects struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchSavedObjectsSpec   `json:"spec,omitempty"`
+	Status OpensearchSavedObjectsStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchSavedObjectsList contains a list of OpensearchSavedObjects
+type OpensearchSavedObjectsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchSavedObjects `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchSavedObjects{}, &OpensearchSavedObjectsList{})
+}
@@ -13,6 +13,7 @@ const (
 	OpensearchTenantCreated OpensearchTenantState = "CREATED"
 	OpensearchTenantError   OpensearchTenantState = "ERROR"
 	OpensearchTenantIgnored OpensearchTenantState = "IGNORED"
+	OpensearchTenantDeferred OpensearchTenantState = "DEFERRED"
 )
 
 // OpensearchTenantSpec defines the desired state of OpensearchTenant
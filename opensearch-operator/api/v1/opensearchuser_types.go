@@ -28,6 +28,7 @@ const (
 	OpensearchUserStatePending OpensearchUserState = "PENDING"
 	OpensearchUserStateCreated OpensearchUserState = "CREATED"
 	OpensearchUserStateError   OpensearchUserState = "ERROR"
+	OpensearchUserStateDeferred OpensearchUserState = "DEFERRED"
 )
 
 // OpensearchUserSpec defines the desired state of OpensearchUser
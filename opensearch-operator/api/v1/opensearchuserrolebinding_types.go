@@ -28,6 +28,7 @@ const (
 	OpensearchUserRoleBindingPending      OpensearchUserRoleBindingState = "PENDING"
 	OpensearchUserRoleBindingStateCreated OpensearchUserRoleBindingState = "CREATED"
 	OpensearchUserRoleBindingStateError   OpensearchUserRoleBindingState = "ERROR"
+	OpensearchUserRoleBindingDeferred OpensearchUserRoleBindingState = "DEFERRED"
 )
 
 // OpensearchUserRoleBindingSpec defines the desired state of OpensearchUserRoleBinding
@@ -24,6 +24,7 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -129,6 +130,22 @@ func (in *Action) DeepCopy() *Action {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveScalingSchedule) DeepCopyInto(out *ActiveScalingSchedule) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveScalingSchedule.
+func (in *ActiveScalingSchedule) DeepCopy() *ActiveScalingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveScalingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdditionalVolume) DeepCopyInto(out *AdditionalVolume) {
 	*out = *in
@@ -256,6 +273,142 @@ func (in *Allocation) DeepCopy() *Allocation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSpec) DeepCopyInto(out *AuditSpec) {
+	*out = *in
+	if in.EnableRest != nil {
+		in, out := &in.EnableRest, &out.EnableRest
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisabledRestCategories != nil {
+		in, out := &in.DisabledRestCategories, &out.DisabledRestCategories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnableTransport != nil {
+		in, out := &in.EnableTransport, &out.EnableTransport
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisabledTransportCategories != nil {
+		in, out := &in.DisabledTransportCategories, &out.DisabledTransportCategories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolveBulkRequests != nil {
+		in, out := &in.ResolveBulkRequests, &out.ResolveBulkRequests
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LogRequestBody != nil {
+		in, out := &in.LogRequestBody, &out.LogRequestBody
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ResolveIndices != nil {
+		in, out := &in.ResolveIndices, &out.ResolveIndices
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludeSensitiveHeaders != nil {
+		in, out := &in.ExcludeSensitiveHeaders, &out.ExcludeSensitiveHeaders
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IgnoreUsers != nil {
+		in, out := &in.IgnoreUsers, &out.IgnoreUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoreRequests != nil {
+		in, out := &in.IgnoreRequests, &out.IgnoreRequests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSpec.
+func (in *AuditSpec) DeepCopy() *AuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoFollowRule) DeepCopyInto(out *AutoFollowRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoFollowRule.
+func (in *AutoFollowRule) DeepCopy() *AutoFollowRule {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoFollowRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoFollowRuleStatus) DeepCopyInto(out *AutoFollowRuleStatus) {
+	*out = *in
+	if in.FailedIndices != nil {
+		in, out := &in.FailedIndices, &out.FailedIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoFollowRuleStatus.
+func (in *AutoFollowRuleStatus) DeepCopy() *AutoFollowRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoFollowRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureCredentials) DeepCopyInto(out *AzureCredentials) {
+	*out = *in
+	out.Secret = in.Secret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureCredentials.
+func (in *AzureCredentials) DeepCopy() *AzureCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureSnapshotRepository) DeepCopyInto(out *AzureSnapshotRepository) {
+	*out = *in
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(AzureCredentials)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureSnapshotRepository.
+func (in *AzureSnapshotRepository) DeepCopy() *AzureSnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureSnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapConfig) DeepCopyInto(out *BootstrapConfig) {
 	*out = *in
@@ -298,6 +451,35 @@ func (in *BootstrapConfig) DeepCopy() *BootstrapConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryValidation) DeepCopyInto(out *CanaryValidation) {
+	*out = *in
+	if in.Documents != nil {
+		in, out := &in.Documents, &out.Documents
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpectedFieldTypes != nil {
+		in, out := &in.ExpectedFieldTypes, &out.ExpectedFieldTypes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryValidation.
+func (in *CanaryValidation) DeepCopy() *CanaryValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Close) DeepCopyInto(out *Close) {
 	*out = *in
@@ -355,6 +537,27 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = make([]NodePoolAutoscalingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScalingSchedules != nil {
+		in, out := &in.ScalingSchedules, &out.ScalingSchedules
+		*out = make([]ActiveScalingSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drains != nil {
+		in, out := &in.Drains, &out.Drains
+		*out = make([]NodeDrainStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -368,121 +571,405 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+func (in *CodecMigration) DeepCopyInto(out *CodecMigration) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.MaxIndices != nil {
+		in, out := &in.MaxIndices, &out.MaxIndices
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
-func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodecMigration.
+func (in *CodecMigration) DeepCopy() *CodecMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentStatus)
+	out := new(CodecMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *CodecMigrationIndex) DeepCopyInto(out *CodecMigrationIndex) {
 	*out = *in
-	if in.Cron != nil {
-		in, out := &in.Cron, &out.Cron
-		*out = new(Cron)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodecMigrationIndex.
+func (in *CodecMigrationIndex) DeepCopy() *CodecMigrationIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(CodecMigrationIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodecMigrationStatus) DeepCopyInto(out *CodecMigrationStatus) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]CodecMigrationIndex, len(*in))
+		copy(*out, *in)
+	}
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodecMigrationStatus.
+func (in *CodecMigrationStatus) DeepCopy() *CodecMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CodecMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceSpec) DeepCopyInto(out *ComplianceSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
 		**out = **in
 	}
-	if in.MinDocCount != nil {
-		in, out := &in.MinDocCount, &out.MinDocCount
-		*out = new(int64)
+	if in.InternalConfig != nil {
+		in, out := &in.InternalConfig, &out.InternalConfig
+		*out = new(bool)
 		**out = **in
 	}
-	if in.MinIndexAge != nil {
-		in, out := &in.MinIndexAge, &out.MinIndexAge
-		*out = new(string)
+	if in.ExternalConfig != nil {
+		in, out := &in.ExternalConfig, &out.ExternalConfig
+		*out = new(bool)
 		**out = **in
 	}
-	if in.MinRolloverAge != nil {
-		in, out := &in.MinRolloverAge, &out.MinRolloverAge
-		*out = new(string)
+	if in.ReadMetadataOnly != nil {
+		in, out := &in.ReadMetadataOnly, &out.ReadMetadataOnly
+		*out = new(bool)
 		**out = **in
 	}
-	if in.MinSize != nil {
-		in, out := &in.MinSize, &out.MinSize
-		*out = new(string)
+	if in.WriteMetadataOnly != nil {
+		in, out := &in.WriteMetadataOnly, &out.WriteMetadataOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WriteLogDiffs != nil {
+		in, out := &in.WriteLogDiffs, &out.WriteLogDiffs
+		*out = new(bool)
 		**out = **in
 	}
+	if in.ReadWatchedFields != nil {
+		in, out := &in.ReadWatchedFields, &out.ReadWatchedFields
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ReadIgnoreUsers != nil {
+		in, out := &in.ReadIgnoreUsers, &out.ReadIgnoreUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WriteWatchedIndices != nil {
+		in, out := &in.WriteWatchedIndices, &out.WriteWatchedIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WriteIgnoreUsers != nil {
+		in, out := &in.WriteIgnoreUsers, &out.WriteIgnoreUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSpec.
+func (in *ComplianceSpec) DeepCopy() *ComplianceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(ComplianceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfMgmt) DeepCopyInto(out *ConfMgmt) {
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfMgmt.
-func (in *ConfMgmt) DeepCopy() *ConfMgmt {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfMgmt)
+	out := new(ComponentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Cron) DeepCopyInto(out *Cron) {
+func (in *ComponentTemplateApproval) DeepCopyInto(out *ComponentTemplateApproval) {
 	*out = *in
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = make([]ComponentTemplateChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cron.
-func (in *Cron) DeepCopy() *Cron {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplateApproval.
+func (in *ComponentTemplateApproval) DeepCopy() *ComponentTemplateApproval {
 	if in == nil {
 		return nil
 	}
-	out := new(Cron)
+	out := new(ComponentTemplateApproval)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DashboardsConfig) DeepCopyInto(out *DashboardsConfig) {
+func (in *ComponentTemplateChange) DeepCopyInto(out *ComponentTemplateChange) {
 	*out = *in
-	if in.ImageSpec != nil {
-		in, out := &in.ImageSpec, &out.ImageSpec
-		*out = new(ImageSpec)
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = new(apiextensionsv1.JSON)
 		(*in).DeepCopyInto(*out)
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Tls != nil {
-		in, out := &in.Tls, &out.Tls
-		*out = new(DashboardsTlsConfig)
-		**out = **in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.AdditionalConfig != nil {
-		in, out := &in.AdditionalConfig, &out.AdditionalConfig
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplateChange.
+func (in *ComponentTemplateChange) DeepCopy() *ComponentTemplateChange {
+	if in == nil {
+		return nil
 	}
-	out.OpensearchCredentialsSecret = in.OpensearchCredentialsSecret
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
+	out := new(ComponentTemplateChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTemplateClusterStatus) DeepCopyInto(out *ComponentTemplateClusterStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplateClusterStatus.
+func (in *ComponentTemplateClusterStatus) DeepCopy() *ComponentTemplateClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTemplateClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTemplateFieldSecurity) DeepCopyInto(out *ComponentTemplateFieldSecurity) {
+	*out = *in
+	out.RoleRef = in.RoleRef
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HiddenFields != nil {
+		in, out := &in.HiddenFields, &out.HiddenFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplateFieldSecurity.
+func (in *ComponentTemplateFieldSecurity) DeepCopy() *ComponentTemplateFieldSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTemplateFieldSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTemplateISMPolicy) DeepCopyInto(out *ComponentTemplateISMPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplateISMPolicy.
+func (in *ComponentTemplateISMPolicy) DeepCopy() *ComponentTemplateISMPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTemplateISMPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTemplateSimulatedIndex) DeepCopyInto(out *ComponentTemplateSimulatedIndex) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overlapping != nil {
+		in, out := &in.Overlapping, &out.Overlapping
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplateSimulatedIndex.
+func (in *ComponentTemplateSimulatedIndex) DeepCopy() *ComponentTemplateSimulatedIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTemplateSimulatedIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	if in.Cron != nil {
+		in, out := &in.Cron, &out.Cron
+		*out = new(Cron)
+		**out = **in
+	}
+	if in.MinDocCount != nil {
+		in, out := &in.MinDocCount, &out.MinDocCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinIndexAge != nil {
+		in, out := &in.MinIndexAge, &out.MinIndexAge
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinRolloverAge != nil {
+		in, out := &in.MinRolloverAge, &out.MinRolloverAge
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfMgmt) DeepCopyInto(out *ConfMgmt) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfMgmt.
+func (in *ConfMgmt) DeepCopy() *ConfMgmt {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfMgmt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cron) DeepCopyInto(out *Cron) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cron.
+func (in *Cron) DeepCopy() *Cron {
+	if in == nil {
+		return nil
+	}
+	out := new(Cron)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronSchedule) DeepCopyInto(out *CronSchedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronSchedule.
+func (in *CronSchedule) DeepCopy() *CronSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(CronSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardsConfig) DeepCopyInto(out *DashboardsConfig) {
+	*out = *in
+	if in.ImageSpec != nil {
+		in, out := &in.ImageSpec, &out.ImageSpec
+		*out = new(ImageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Tls != nil {
+		in, out := &in.Tls, &out.Tls
+		*out = new(DashboardsTlsConfig)
+		**out = **in
+	}
+	if in.AdditionalConfig != nil {
+		in, out := &in.AdditionalConfig, &out.AdditionalConfig
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.OpensearchCredentialsSecret = in.OpensearchCredentialsSecret
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
@@ -655,6 +1142,67 @@ func (in *DestinationURL) DeepCopy() *DestinationURL {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DetectorFeature) DeepCopyInto(out *DetectorFeature) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	in.AggregationQuery.DeepCopyInto(&out.AggregationQuery)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DetectorFeature.
+func (in *DetectorFeature) DeepCopy() *DetectorFeature {
+	if in == nil {
+		return nil
+	}
+	out := new(DetectorFeature)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DetectorJobStatus) DeepCopyInto(out *DetectorJobStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DetectorJobStatus.
+func (in *DetectorJobStatus) DeepCopy() *DetectorJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DetectorJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailChannel) DeepCopyInto(out *EmailChannel) {
+	*out = *in
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmailGroupIDs != nil {
+		in, out := &in.EmailGroupIDs, &out.EmailGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailChannel.
+func (in *EmailChannel) DeepCopy() *EmailChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ErrorNotification) DeepCopyInto(out *ErrorNotification) {
 	*out = *in
@@ -680,6 +1228,21 @@ func (in *ErrorNotification) DeepCopy() *ErrorNotification {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FSSnapshotRepository) DeepCopyInto(out *FSSnapshotRepository) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FSSnapshotRepository.
+func (in *FSSnapshotRepository) DeepCopy() *FSSnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(FSSnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ForceMerge) DeepCopyInto(out *ForceMerge) {
 	*out = *in
@@ -695,6 +1258,42 @@ func (in *ForceMerge) DeepCopy() *ForceMerge {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCSCredentials) DeepCopyInto(out *GCSCredentials) {
+	*out = *in
+	out.Secret = in.Secret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCSCredentials.
+func (in *GCSCredentials) DeepCopy() *GCSCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(GCSCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCSSnapshotRepository) DeepCopyInto(out *GCSSnapshotRepository) {
+	*out = *in
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(GCSCredentials)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCSSnapshotRepository.
+func (in *GCSSnapshotRepository) DeepCopy() *GCSSnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(GCSSnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GeneralConfig) DeepCopyInto(out *GeneralConfig) {
 	*out = *in
@@ -822,12 +1421,32 @@ func (in *ImageSpec) DeepCopy() *ImageSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexPermissionSpec) DeepCopyInto(out *IndexPermissionSpec) {
+func (in *IndexAliasTarget) DeepCopyInto(out *IndexAliasTarget) {
 	*out = *in
-	if in.IndexPatterns != nil {
-		in, out := &in.IndexPatterns, &out.IndexPatterns
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexAliasTarget.
+func (in *IndexAliasTarget) DeepCopy() *IndexAliasTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexAliasTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexPermissionSpec) DeepCopyInto(out *IndexPermissionSpec) {
+	*out = *in
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	if in.FieldLevelSecurity != nil {
 		in, out := &in.FieldLevelSecurity, &out.FieldLevelSecurity
@@ -866,6 +1485,21 @@ func (in *IndexPriority) DeepCopy() *IndexPriority {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexTemplateDataStream) DeepCopyInto(out *IndexTemplateDataStream) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexTemplateDataStream.
+func (in *IndexTemplateDataStream) DeepCopy() *IndexTemplateDataStream {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexTemplateDataStream)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InitHelperConfig) DeepCopyInto(out *InitHelperConfig) {
 	*out = *in
@@ -930,6 +1564,69 @@ func (in *MessageTemplate) DeepCopy() *MessageTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorPeriod) DeepCopyInto(out *MonitorPeriod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorPeriod.
+func (in *MonitorPeriod) DeepCopy() *MonitorPeriod {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorPeriod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorSchedule) DeepCopyInto(out *MonitorSchedule) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(MonitorPeriod)
+		**out = **in
+	}
+	if in.Cron != nil {
+		in, out := &in.Cron, &out.Cron
+		*out = new(CronSchedule)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSchedule.
+func (in *MonitorSchedule) DeepCopy() *MonitorSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorTrigger) DeepCopyInto(out *MonitorTrigger) {
+	*out = *in
+	in.Condition.DeepCopyInto(&out.Condition)
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorTrigger.
+func (in *MonitorTrigger) DeepCopy() *MonitorTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitoringConfig) DeepCopyInto(out *MonitoringConfig) {
 	*out = *in
@@ -965,6 +1662,26 @@ func (in *MonitoringConfigTLS) DeepCopy() *MonitoringConfigTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrainStatus) DeepCopyInto(out *NodeDrainStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.RetryTime != nil {
+		in, out := &in.RetryTime, &out.RetryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainStatus.
+func (in *NodeDrainStatus) DeepCopy() *NodeDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePool) DeepCopyInto(out *NodePool) {
 	*out = *in
@@ -1038,6 +1755,21 @@ func (in *NodePool) DeepCopyInto(out *NodePool) {
 		*out = new(PdbConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(NodePoolAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScalingSchedules != nil {
+		in, out := &in.ScalingSchedules, &out.ScalingSchedules
+		*out = make([]ScalingSchedule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwarenessConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
@@ -1050,6 +1782,109 @@ func (in *NodePool) DeepCopy() *NodePool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolAutoscaling) DeepCopyInto(out *NodePoolAutoscaling) {
+	*out = *in
+	if in.TargetCPUUtilization != nil {
+		in, out := &in.TargetCPUUtilization, &out.TargetCPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetJvmHeapPressure != nil {
+		in, out := &in.TargetJvmHeapPressure, &out.TargetJvmHeapPressure
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetDiskUsage != nil {
+		in, out := &in.TargetDiskUsage, &out.TargetDiskUsage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Vertical != nil {
+		in, out := &in.Vertical, &out.Vertical
+		*out = new(NodePoolVerticalAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolAutoscaling.
+func (in *NodePoolAutoscaling) DeepCopy() *NodePoolAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolAutoscalingStatus) DeepCopyInto(out *NodePoolAutoscalingStatus) {
+	*out = *in
+	if in.CPUUtilization != nil {
+		in, out := &in.CPUUtilization, &out.CPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.JvmHeapPressure != nil {
+		in, out := &in.JvmHeapPressure, &out.JvmHeapPressure
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DiskUsage != nil {
+		in, out := &in.DiskUsage, &out.DiskUsage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolAutoscalingStatus.
+func (in *NodePoolAutoscalingStatus) DeepCopy() *NodePoolAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolVerticalAutoscaling) DeepCopyInto(out *NodePoolVerticalAutoscaling) {
+	*out = *in
+	if in.MinResources != nil {
+		in, out := &in.MinResources, &out.MinResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolVerticalAutoscaling.
+func (in *NodePoolVerticalAutoscaling) DeepCopy() *NodePoolVerticalAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolVerticalAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Notification) DeepCopyInto(out *Notification) {
 	*out = *in
@@ -1338,22 +2173,7 @@ func (in *OpensearchActionGroupStatus) DeepCopy() *OpensearchActionGroupStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchClusterSelector) DeepCopyInto(out *OpensearchClusterSelector) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchClusterSelector.
-func (in *OpensearchClusterSelector) DeepCopy() *OpensearchClusterSelector {
-	if in == nil {
-		return nil
-	}
-	out := new(OpensearchClusterSelector)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchComponentTemplate) DeepCopyInto(out *OpensearchComponentTemplate) {
+func (in *OpensearchAlertingMonitor) DeepCopyInto(out *OpensearchAlertingMonitor) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1361,18 +2181,18 @@ func (in *OpensearchComponentTemplate) DeepCopyInto(out *OpensearchComponentTemp
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchComponentTemplate.
-func (in *OpensearchComponentTemplate) DeepCopy() *OpensearchComponentTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAlertingMonitor.
+func (in *OpensearchAlertingMonitor) DeepCopy() *OpensearchAlertingMonitor {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchComponentTemplate)
+	out := new(OpensearchAlertingMonitor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchComponentTemplate) DeepCopyObject() runtime.Object {
+func (in *OpensearchAlertingMonitor) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1380,31 +2200,31 @@ func (in *OpensearchComponentTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchComponentTemplateList) DeepCopyInto(out *OpensearchComponentTemplateList) {
+func (in *OpensearchAlertingMonitorList) DeepCopyInto(out *OpensearchAlertingMonitorList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OpensearchComponentTemplate, len(*in))
+		*out = make([]OpensearchAlertingMonitor, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchComponentTemplateList.
-func (in *OpensearchComponentTemplateList) DeepCopy() *OpensearchComponentTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAlertingMonitorList.
+func (in *OpensearchAlertingMonitorList) DeepCopy() *OpensearchAlertingMonitorList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchComponentTemplateList)
+	out := new(OpensearchAlertingMonitorList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchComponentTemplateList) DeepCopyObject() runtime.Object {
+func (in *OpensearchAlertingMonitorList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1412,32 +2232,46 @@ func (in *OpensearchComponentTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchComponentTemplateSpec) DeepCopyInto(out *OpensearchComponentTemplateSpec) {
+func (in *OpensearchAlertingMonitorSpec) DeepCopyInto(out *OpensearchAlertingMonitorSpec) {
 	*out = *in
 	out.OpensearchRef = in.OpensearchRef
-	in.Template.DeepCopyInto(&out.Template)
-	if in.Meta != nil {
-		in, out := &in.Meta, &out.Meta
-		*out = new(apiextensionsv1.JSON)
-		(*in).DeepCopyInto(*out)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	in.Schedule.DeepCopyInto(&out.Schedule)
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]MonitorTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchComponentTemplateSpec.
-func (in *OpensearchComponentTemplateSpec) DeepCopy() *OpensearchComponentTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAlertingMonitorSpec.
+func (in *OpensearchAlertingMonitorSpec) DeepCopy() *OpensearchAlertingMonitorSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchComponentTemplateSpec)
+	out := new(OpensearchAlertingMonitorSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchComponentTemplateStatus) DeepCopyInto(out *OpensearchComponentTemplateStatus) {
+func (in *OpensearchAlertingMonitorStatus) DeepCopyInto(out *OpensearchAlertingMonitorStatus) {
 	*out = *in
-	if in.ExistingComponentTemplate != nil {
-		in, out := &in.ExistingComponentTemplate, &out.ExistingComponentTemplate
+	if in.ExistingMonitor != nil {
+		in, out := &in.ExistingMonitor, &out.ExistingMonitor
 		*out = new(bool)
 		**out = **in
 	}
@@ -1448,95 +2282,155 @@ func (in *OpensearchComponentTemplateStatus) DeepCopyInto(out *OpensearchCompone
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchComponentTemplateStatus.
-func (in *OpensearchComponentTemplateStatus) DeepCopy() *OpensearchComponentTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAlertingMonitorStatus.
+func (in *OpensearchAlertingMonitorStatus) DeepCopy() *OpensearchAlertingMonitorStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchComponentTemplateStatus)
+	out := new(OpensearchAlertingMonitorStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchISMPolicyStatus) DeepCopyInto(out *OpensearchISMPolicyStatus) {
+func (in *OpensearchAnomalyDetector) DeepCopyInto(out *OpensearchAnomalyDetector) {
 	*out = *in
-	if in.ExistingISMPolicy != nil {
-		in, out := &in.ExistingISMPolicy, &out.ExistingISMPolicy
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ManagedCluster != nil {
-		in, out := &in.ManagedCluster, &out.ManagedCluster
-		*out = new(types.UID)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchISMPolicyStatus.
-func (in *OpensearchISMPolicyStatus) DeepCopy() *OpensearchISMPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAnomalyDetector.
+func (in *OpensearchAnomalyDetector) DeepCopy() *OpensearchAnomalyDetector {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchISMPolicyStatus)
+	out := new(OpensearchAnomalyDetector)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchAnomalyDetector) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchIndexAliasSpec) DeepCopyInto(out *OpensearchIndexAliasSpec) {
+func (in *OpensearchAnomalyDetectorList) DeepCopyInto(out *OpensearchAnomalyDetectorList) {
 	*out = *in
-	if in.Filter != nil {
-		in, out := &in.Filter, &out.Filter
-		*out = new(apiextensionsv1.JSON)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchAnomalyDetector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexAliasSpec.
-func (in *OpensearchIndexAliasSpec) DeepCopy() *OpensearchIndexAliasSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAnomalyDetectorList.
+func (in *OpensearchAnomalyDetectorList) DeepCopy() *OpensearchAnomalyDetectorList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchIndexAliasSpec)
+	out := new(OpensearchAnomalyDetectorList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchAnomalyDetectorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchIndexSpec) DeepCopyInto(out *OpensearchIndexSpec) {
+func (in *OpensearchAnomalyDetectorSpec) DeepCopyInto(out *OpensearchAnomalyDetectorSpec) {
 	*out = *in
-	if in.Settings != nil {
-		in, out := &in.Settings, &out.Settings
-		*out = new(apiextensionsv1.JSON)
-		(*in).DeepCopyInto(*out)
+	out.OpensearchRef = in.OpensearchRef
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Mappings != nil {
-		in, out := &in.Mappings, &out.Mappings
-		*out = new(apiextensionsv1.JSON)
-		(*in).DeepCopyInto(*out)
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Aliases != nil {
-		in, out := &in.Aliases, &out.Aliases
-		*out = make(map[string]OpensearchIndexAliasSpec, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]DetectorFeature, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FilterQuery != nil {
+		in, out := &in.FilterQuery, &out.FilterQuery
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CategoryFields != nil {
+		in, out := &in.CategoryFields, &out.CategoryFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ShingleSize != nil {
+		in, out := &in.ShingleSize, &out.ShingleSize
+		*out = new(int)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexSpec.
-func (in *OpensearchIndexSpec) DeepCopy() *OpensearchIndexSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAnomalyDetectorSpec.
+func (in *OpensearchAnomalyDetectorSpec) DeepCopy() *OpensearchAnomalyDetectorSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchIndexSpec)
+	out := new(OpensearchAnomalyDetectorSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchIndexTemplate) DeepCopyInto(out *OpensearchIndexTemplate) {
+func (in *OpensearchAnomalyDetectorStatus) DeepCopyInto(out *OpensearchAnomalyDetectorStatus) {
+	*out = *in
+	if in.ExistingDetector != nil {
+		in, out := &in.ExistingDetector, &out.ExistingDetector
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(DetectorJobStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAnomalyDetectorStatus.
+func (in *OpensearchAnomalyDetectorStatus) DeepCopy() *OpensearchAnomalyDetectorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchAnomalyDetectorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchAuditConfig) DeepCopyInto(out *OpensearchAuditConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1544,18 +2438,18 @@ func (in *OpensearchIndexTemplate) DeepCopyInto(out *OpensearchIndexTemplate) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexTemplate.
-func (in *OpensearchIndexTemplate) DeepCopy() *OpensearchIndexTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAuditConfig.
+func (in *OpensearchAuditConfig) DeepCopy() *OpensearchAuditConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchIndexTemplate)
+	out := new(OpensearchAuditConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchIndexTemplate) DeepCopyObject() runtime.Object {
+func (in *OpensearchAuditConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1563,31 +2457,31 @@ func (in *OpensearchIndexTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchIndexTemplateList) DeepCopyInto(out *OpensearchIndexTemplateList) {
+func (in *OpensearchAuditConfigList) DeepCopyInto(out *OpensearchAuditConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OpensearchIndexTemplate, len(*in))
+		*out = make([]OpensearchAuditConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexTemplateList.
-func (in *OpensearchIndexTemplateList) DeepCopy() *OpensearchIndexTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAuditConfigList.
+func (in *OpensearchAuditConfigList) DeepCopy() *OpensearchAuditConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchIndexTemplateList)
+	out := new(OpensearchAuditConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchIndexTemplateList) DeepCopyObject() runtime.Object {
+func (in *OpensearchAuditConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1595,45 +2489,39 @@ func (in *OpensearchIndexTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchIndexTemplateSpec) DeepCopyInto(out *OpensearchIndexTemplateSpec) {
+func (in *OpensearchAuditConfigSpec) DeepCopyInto(out *OpensearchAuditConfigSpec) {
 	*out = *in
 	out.OpensearchRef = in.OpensearchRef
-	if in.IndexPatterns != nil {
-		in, out := &in.IndexPatterns, &out.IndexPatterns
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
 	}
-	in.Template.DeepCopyInto(&out.Template)
-	if in.ComposedOf != nil {
-		in, out := &in.ComposedOf, &out.ComposedOf
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Meta != nil {
-		in, out := &in.Meta, &out.Meta
-		*out = new(apiextensionsv1.JSON)
+	if in.Compliance != nil {
+		in, out := &in.Compliance, &out.Compliance
+		*out = new(ComplianceSpec)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexTemplateSpec.
-func (in *OpensearchIndexTemplateSpec) DeepCopy() *OpensearchIndexTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAuditConfigSpec.
+func (in *OpensearchAuditConfigSpec) DeepCopy() *OpensearchAuditConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchIndexTemplateSpec)
+	out := new(OpensearchAuditConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchIndexTemplateStatus) DeepCopyInto(out *OpensearchIndexTemplateStatus) {
+func (in *OpensearchAuditConfigStatus) DeepCopyInto(out *OpensearchAuditConfigStatus) {
 	*out = *in
-	if in.ExistingIndexTemplate != nil {
-		in, out := &in.ExistingIndexTemplate, &out.ExistingIndexTemplate
-		*out = new(bool)
-		**out = **in
-	}
 	if in.ManagedCluster != nil {
 		in, out := &in.ManagedCluster, &out.ManagedCluster
 		*out = new(types.UID)
@@ -1641,18 +2529,33 @@ func (in *OpensearchIndexTemplateStatus) DeepCopyInto(out *OpensearchIndexTempla
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexTemplateStatus.
-func (in *OpensearchIndexTemplateStatus) DeepCopy() *OpensearchIndexTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchAuditConfigStatus.
+func (in *OpensearchAuditConfigStatus) DeepCopy() *OpensearchAuditConfigStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchIndexTemplateStatus)
+	out := new(OpensearchAuditConfigStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchRole) DeepCopyInto(out *OpensearchRole) {
+func (in *OpensearchClusterSelector) DeepCopyInto(out *OpensearchClusterSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchClusterSelector.
+func (in *OpensearchClusterSelector) DeepCopy() *OpensearchClusterSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchClusterSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchClusterSettings) DeepCopyInto(out *OpensearchClusterSettings) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1660,18 +2563,18 @@ func (in *OpensearchRole) DeepCopyInto(out *OpensearchRole) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRole.
-func (in *OpensearchRole) DeepCopy() *OpensearchRole {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchClusterSettings.
+func (in *OpensearchClusterSettings) DeepCopy() *OpensearchClusterSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchRole)
+	out := new(OpensearchClusterSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchRole) DeepCopyObject() runtime.Object {
+func (in *OpensearchClusterSettings) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1679,31 +2582,31 @@ func (in *OpensearchRole) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchRoleList) DeepCopyInto(out *OpensearchRoleList) {
+func (in *OpensearchClusterSettingsList) DeepCopyInto(out *OpensearchClusterSettingsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OpensearchRole, len(*in))
+		*out = make([]OpensearchClusterSettings, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleList.
-func (in *OpensearchRoleList) DeepCopy() *OpensearchRoleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchClusterSettingsList.
+func (in *OpensearchClusterSettingsList) DeepCopy() *OpensearchClusterSettingsList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchRoleList)
+	out := new(OpensearchClusterSettingsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchRoleList) DeepCopyObject() runtime.Object {
+func (in *OpensearchClusterSettingsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1711,86 +2614,90 @@ func (in *OpensearchRoleList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchRoleSpec) DeepCopyInto(out *OpensearchRoleSpec) {
+func (in *OpensearchClusterSettingsSpec) DeepCopyInto(out *OpensearchClusterSettingsSpec) {
 	*out = *in
 	out.OpensearchRef = in.OpensearchRef
-	if in.ClusterPermissions != nil {
-		in, out := &in.ClusterPermissions, &out.ClusterPermissions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.IndexPermissions != nil {
-		in, out := &in.IndexPermissions, &out.IndexPermissions
-		*out = make([]IndexPermissionSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Persistent != nil {
+		in, out := &in.Persistent, &out.Persistent
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	if in.TenantPermissions != nil {
-		in, out := &in.TenantPermissions, &out.TenantPermissions
-		*out = make([]TenantPermissionsSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Transient != nil {
+		in, out := &in.Transient, &out.Transient
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleSpec.
-func (in *OpensearchRoleSpec) DeepCopy() *OpensearchRoleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchClusterSettingsSpec.
+func (in *OpensearchClusterSettingsSpec) DeepCopy() *OpensearchClusterSettingsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchRoleSpec)
+	out := new(OpensearchClusterSettingsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchRoleStatus) DeepCopyInto(out *OpensearchRoleStatus) {
+func (in *OpensearchClusterSettingsStatus) DeepCopyInto(out *OpensearchClusterSettingsStatus) {
 	*out = *in
-	if in.ExistingRole != nil {
-		in, out := &in.ExistingRole, &out.ExistingRole
-		*out = new(bool)
-		**out = **in
-	}
 	if in.ManagedCluster != nil {
 		in, out := &in.ManagedCluster, &out.ManagedCluster
 		*out = new(types.UID)
 		**out = **in
 	}
+	if in.Persistent != nil {
+		in, out := &in.Persistent, &out.Persistent
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Transient != nil {
+		in, out := &in.Transient, &out.Transient
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleStatus.
-func (in *OpensearchRoleStatus) DeepCopy() *OpensearchRoleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchClusterSettingsStatus.
+func (in *OpensearchClusterSettingsStatus) DeepCopy() *OpensearchClusterSettingsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchRoleStatus)
+	out := new(OpensearchClusterSettingsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchTenant) DeepCopyInto(out *OpensearchTenant) {
+func (in *OpensearchComponentTemplate) DeepCopyInto(out *OpensearchComponentTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTenant.
-func (in *OpensearchTenant) DeepCopy() *OpensearchTenant {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchComponentTemplate.
+func (in *OpensearchComponentTemplate) DeepCopy() *OpensearchComponentTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchTenant)
+	out := new(OpensearchComponentTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchTenant) DeepCopyObject() runtime.Object {
+func (in *OpensearchComponentTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1798,31 +2705,31 @@ func (in *OpensearchTenant) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchTenantList) DeepCopyInto(out *OpensearchTenantList) {
+func (in *OpensearchComponentTemplateList) DeepCopyInto(out *OpensearchComponentTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OpensearchTenant, len(*in))
+		*out = make([]OpensearchComponentTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTenantList.
-func (in *OpensearchTenantList) DeepCopy() *OpensearchTenantList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchComponentTemplateList.
+func (in *OpensearchComponentTemplateList) DeepCopy() *OpensearchComponentTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchTenantList)
+	out := new(OpensearchComponentTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchTenantList) DeepCopyObject() runtime.Object {
+func (in *OpensearchComponentTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1830,26 +2737,82 @@ func (in *OpensearchTenantList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchTenantSpec) DeepCopyInto(out *OpensearchTenantSpec) {
+func (in *OpensearchComponentTemplateSpec) DeepCopyInto(out *OpensearchComponentTemplateSpec) {
 	*out = *in
 	out.OpensearchRef = in.OpensearchRef
+	if in.RegionAffinity != nil {
+		in, out := &in.RegionAffinity, &out.RegionAffinity
+		*out = new(RegionAffinity)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Meta != nil {
+		in, out := &in.Meta, &out.Meta
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalEditGracePeriod != nil {
+		in, out := &in.ExternalEditGracePeriod, &out.ExternalEditGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FieldSecurity != nil {
+		in, out := &in.FieldSecurity, &out.FieldSecurity
+		*out = new(ComponentTemplateFieldSecurity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaPolicy != nil {
+		in, out := &in.ReplicaPolicy, &out.ReplicaPolicy
+		*out = new(ReplicaPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CodecMigration != nil {
+		in, out := &in.CodecMigration, &out.CodecMigration
+		*out = new(CodecMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchemaMigration != nil {
+		in, out := &in.SchemaMigration, &out.SchemaMigration
+		*out = new(SchemaMigration)
+		**out = **in
+	}
+	if in.ISMPolicy != nil {
+		in, out := &in.ISMPolicy, &out.ISMPolicy
+		*out = new(ComponentTemplateISMPolicy)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RequireRecentSnapshot != nil {
+		in, out := &in.RequireRecentSnapshot, &out.RequireRecentSnapshot
+		*out = new(RecentSnapshotRequirement)
+		**out = **in
+	}
+	if in.CanaryValidation != nil {
+		in, out := &in.CanaryValidation, &out.CanaryValidation
+		*out = new(CanaryValidation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTenantSpec.
-func (in *OpensearchTenantSpec) DeepCopy() *OpensearchTenantSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchComponentTemplateSpec.
+func (in *OpensearchComponentTemplateSpec) DeepCopy() *OpensearchComponentTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchTenantSpec)
+	out := new(OpensearchComponentTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchTenantStatus) DeepCopyInto(out *OpensearchTenantStatus) {
+func (in *OpensearchComponentTemplateStatus) DeepCopyInto(out *OpensearchComponentTemplateStatus) {
 	*out = *in
-	if in.ExistingTenant != nil {
-		in, out := &in.ExistingTenant, &out.ExistingTenant
+	if in.ExistingComponentTemplate != nil {
+		in, out := &in.ExistingComponentTemplate, &out.ExistingComponentTemplate
 		*out = new(bool)
 		**out = **in
 	}
@@ -1858,20 +2821,60 @@ func (in *OpensearchTenantStatus) DeepCopyInto(out *OpensearchTenantStatus) {
 		*out = new(types.UID)
 		**out = **in
 	}
+	if in.ExternalEditDetectedAt != nil {
+		in, out := &in.ExternalEditDetectedAt, &out.ExternalEditDetectedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousApplied != nil {
+		in, out := &in.PreviousApplied, &out.PreviousApplied
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AwaitingApproval != nil {
+		in, out := &in.AwaitingApproval, &out.AwaitingApproval
+		*out = new(ComponentTemplateApproval)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ComponentTemplateClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CodecMigration != nil {
+		in, out := &in.CodecMigration, &out.CodecMigration
+		*out = new(CodecMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(SchemaMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SimulatedIndex != nil {
+		in, out := &in.SimulatedIndex, &out.SimulatedIndex
+		*out = new(ComponentTemplateSimulatedIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTenantStatus.
-func (in *OpensearchTenantStatus) DeepCopy() *OpensearchTenantStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchComponentTemplateStatus.
+func (in *OpensearchComponentTemplateStatus) DeepCopy() *OpensearchComponentTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchTenantStatus)
+	out := new(OpensearchComponentTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchUser) DeepCopyInto(out *OpensearchUser) {
+func (in *OpensearchCrossClusterReplication) DeepCopyInto(out *OpensearchCrossClusterReplication) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1879,18 +2882,18 @@ func (in *OpensearchUser) DeepCopyInto(out *OpensearchUser) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUser.
-func (in *OpensearchUser) DeepCopy() *OpensearchUser {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchCrossClusterReplication.
+func (in *OpensearchCrossClusterReplication) DeepCopy() *OpensearchCrossClusterReplication {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchUser)
+	out := new(OpensearchCrossClusterReplication)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchUser) DeepCopyObject() runtime.Object {
+func (in *OpensearchCrossClusterReplication) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1898,31 +2901,31 @@ func (in *OpensearchUser) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchUserList) DeepCopyInto(out *OpensearchUserList) {
+func (in *OpensearchCrossClusterReplicationList) DeepCopyInto(out *OpensearchCrossClusterReplicationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OpensearchUser, len(*in))
+		*out = make([]OpensearchCrossClusterReplication, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserList.
-func (in *OpensearchUserList) DeepCopy() *OpensearchUserList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchCrossClusterReplicationList.
+func (in *OpensearchCrossClusterReplicationList) DeepCopy() *OpensearchCrossClusterReplicationList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchUserList)
+	out := new(OpensearchCrossClusterReplicationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchUserList) DeepCopyObject() runtime.Object {
+func (in *OpensearchCrossClusterReplicationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1930,26 +2933,99 @@ func (in *OpensearchUserList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchUserRoleBinding) DeepCopyInto(out *OpensearchUserRoleBinding) {
+func (in *OpensearchCrossClusterReplicationSpec) DeepCopyInto(out *OpensearchCrossClusterReplicationSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
-}
+	out.OpensearchRef = in.OpensearchRef
+	if in.LeaderCluster != nil {
+		in, out := &in.LeaderCluster, &out.LeaderCluster
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Seeds != nil {
+		in, out := &in.Seeds, &out.Seeds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoFollowRules != nil {
+		in, out := &in.AutoFollowRules, &out.AutoFollowRules
+		*out = make([]AutoFollowRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.UseRoles != nil {
+		in, out := &in.UseRoles, &out.UseRoles
+		*out = new(ReplicationRoles)
+		**out = **in
+	}
+}
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserRoleBinding.
-func (in *OpensearchUserRoleBinding) DeepCopy() *OpensearchUserRoleBinding {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchCrossClusterReplicationSpec.
+func (in *OpensearchCrossClusterReplicationSpec) DeepCopy() *OpensearchCrossClusterReplicationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchUserRoleBinding)
+	out := new(OpensearchCrossClusterReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchCrossClusterReplicationStatus) DeepCopyInto(out *OpensearchCrossClusterReplicationStatus) {
+	*out = *in
+	if in.ExistingConnection != nil {
+		in, out := &in.ExistingConnection, &out.ExistingConnection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.AutoFollowRules != nil {
+		in, out := &in.AutoFollowRules, &out.AutoFollowRules
+		*out = make([]AutoFollowRuleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]ReplicatedIndexStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchCrossClusterReplicationStatus.
+func (in *OpensearchCrossClusterReplicationStatus) DeepCopy() *OpensearchCrossClusterReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchCrossClusterReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchDataStream) DeepCopyInto(out *OpensearchDataStream) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchDataStream.
+func (in *OpensearchDataStream) DeepCopy() *OpensearchDataStream {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchDataStream)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchUserRoleBinding) DeepCopyObject() runtime.Object {
+func (in *OpensearchDataStream) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1957,31 +3033,31 @@ func (in *OpensearchUserRoleBinding) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchUserRoleBindingList) DeepCopyInto(out *OpensearchUserRoleBindingList) {
+func (in *OpensearchDataStreamList) DeepCopyInto(out *OpensearchDataStreamList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OpensearchUserRoleBinding, len(*in))
+		*out = make([]OpensearchDataStream, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserRoleBindingList.
-func (in *OpensearchUserRoleBindingList) DeepCopy() *OpensearchUserRoleBindingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchDataStreamList.
+func (in *OpensearchDataStreamList) DeepCopy() *OpensearchDataStreamList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchUserRoleBindingList)
+	out := new(OpensearchDataStreamList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpensearchUserRoleBindingList) DeepCopyObject() runtime.Object {
+func (in *OpensearchDataStreamList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1989,292 +3065,3249 @@ func (in *OpensearchUserRoleBindingList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchUserRoleBindingSpec) DeepCopyInto(out *OpensearchUserRoleBindingSpec) {
+func (in *OpensearchDataStreamSpec) DeepCopyInto(out *OpensearchDataStreamSpec) {
 	*out = *in
 	out.OpensearchRef = in.OpensearchRef
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchDataStreamSpec.
+func (in *OpensearchDataStreamSpec) DeepCopy() *OpensearchDataStreamSpec {
+	if in == nil {
+		return nil
 	}
-	if in.Users != nil {
-		in, out := &in.Users, &out.Users
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(OpensearchDataStreamSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchDataStreamStatus) DeepCopyInto(out *OpensearchDataStreamStatus) {
+	*out = *in
+	if in.ExistingDataStream != nil {
+		in, out := &in.ExistingDataStream, &out.ExistingDataStream
+		*out = new(bool)
+		**out = **in
 	}
-	if in.BackendRoles != nil {
-		in, out := &in.BackendRoles, &out.BackendRoles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserRoleBindingSpec.
-func (in *OpensearchUserRoleBindingSpec) DeepCopy() *OpensearchUserRoleBindingSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchDataStreamStatus.
+func (in *OpensearchDataStreamStatus) DeepCopy() *OpensearchDataStreamStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchUserRoleBindingSpec)
+	out := new(OpensearchDataStreamStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchUserRoleBindingStatus) DeepCopyInto(out *OpensearchUserRoleBindingStatus) {
+func (in *OpensearchISMPolicyStatus) DeepCopyInto(out *OpensearchISMPolicyStatus) {
 	*out = *in
+	if in.ExistingISMPolicy != nil {
+		in, out := &in.ExistingISMPolicy, &out.ExistingISMPolicy
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ManagedCluster != nil {
 		in, out := &in.ManagedCluster, &out.ManagedCluster
 		*out = new(types.UID)
 		**out = **in
 	}
-	if in.ProvisionedRoles != nil {
-		in, out := &in.ProvisionedRoles, &out.ProvisionedRoles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.ProvisionedUsers != nil {
-		in, out := &in.ProvisionedUsers, &out.ProvisionedUsers
+	if in.AttachedIndices != nil {
+		in, out := &in.AttachedIndices, &out.AttachedIndices
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.ProvisionedBackendRoles != nil {
-		in, out := &in.ProvisionedBackendRoles, &out.ProvisionedBackendRoles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchISMPolicyStatus.
+func (in *OpensearchISMPolicyStatus) DeepCopy() *OpensearchISMPolicyStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(OpensearchISMPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserRoleBindingStatus.
-func (in *OpensearchUserRoleBindingStatus) DeepCopy() *OpensearchUserRoleBindingStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndex) DeepCopyInto(out *OpensearchIndex) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndex.
+func (in *OpensearchIndex) DeepCopy() *OpensearchIndex {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchUserRoleBindingStatus)
+	out := new(OpensearchIndex)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchIndex) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchUserSpec) DeepCopyInto(out *OpensearchUserSpec) {
+func (in *OpensearchIndexAlias) DeepCopyInto(out *OpensearchIndexAlias) {
 	*out = *in
-	out.OpensearchRef = in.OpensearchRef
-	in.PasswordFrom.DeepCopyInto(&out.PasswordFrom)
-	if in.OpendistroSecurityRoles != nil {
-		in, out := &in.OpendistroSecurityRoles, &out.OpendistroSecurityRoles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexAlias.
+func (in *OpensearchIndexAlias) DeepCopy() *OpensearchIndexAlias {
+	if in == nil {
+		return nil
 	}
-	if in.BackendRoles != nil {
-		in, out := &in.BackendRoles, &out.BackendRoles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(OpensearchIndexAlias)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchIndexAlias) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.Attributes != nil {
-		in, out := &in.Attributes, &out.Attributes
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexAliasList) DeepCopyInto(out *OpensearchIndexAliasList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchIndexAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserSpec.
-func (in *OpensearchUserSpec) DeepCopy() *OpensearchUserSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexAliasList.
+func (in *OpensearchIndexAliasList) DeepCopy() *OpensearchIndexAliasList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchUserSpec)
+	out := new(OpensearchIndexAliasList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchIndexAliasList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpensearchUserStatus) DeepCopyInto(out *OpensearchUserStatus) {
+func (in *OpensearchIndexAliasResourceSpec) DeepCopyInto(out *OpensearchIndexAliasResourceSpec) {
 	*out = *in
-	if in.ManagedCluster != nil {
-		in, out := &in.ManagedCluster, &out.ManagedCluster
-		*out = new(types.UID)
-		**out = **in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]IndexAliasTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserStatus.
-func (in *OpensearchUserStatus) DeepCopy() *OpensearchUserStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexAliasResourceSpec.
+func (in *OpensearchIndexAliasResourceSpec) DeepCopy() *OpensearchIndexAliasResourceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpensearchUserStatus)
+	out := new(OpensearchIndexAliasResourceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PVCSource) DeepCopyInto(out *PVCSource) {
+func (in *OpensearchIndexAliasSpec) DeepCopyInto(out *OpensearchIndexAliasSpec) {
 	*out = *in
-	if in.AccessModes != nil {
-		in, out := &in.AccessModes, &out.AccessModes
-		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
-		copy(*out, *in)
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCSource.
-func (in *PVCSource) DeepCopy() *PVCSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexAliasSpec.
+func (in *OpensearchIndexAliasSpec) DeepCopy() *OpensearchIndexAliasSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PVCSource)
+	out := new(OpensearchIndexAliasSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PdbConfig) DeepCopyInto(out *PdbConfig) {
+func (in *OpensearchIndexAliasStatus) DeepCopyInto(out *OpensearchIndexAliasStatus) {
 	*out = *in
-	if in.MinAvailable != nil {
-		in, out := &in.MinAvailable, &out.MinAvailable
-		*out = new(intstr.IntOrString)
+	if in.ExistingAlias != nil {
+		in, out := &in.ExistingAlias, &out.ExistingAlias
+		*out = new(bool)
 		**out = **in
 	}
-	if in.MaxUnavailable != nil {
-		in, out := &in.MaxUnavailable, &out.MaxUnavailable
-		*out = new(intstr.IntOrString)
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
 		**out = **in
 	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexAliasStatus.
+func (in *OpensearchIndexAliasStatus) DeepCopy() *OpensearchIndexAliasStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexAliasStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexList) DeepCopyInto(out *OpensearchIndexList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexList.
+func (in *OpensearchIndexList) DeepCopy() *OpensearchIndexList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchIndexList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexResourceSpec) DeepCopyInto(out *OpensearchIndexResourceSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	in.OpensearchIndexSpec.DeepCopyInto(&out.OpensearchIndexSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexResourceSpec.
+func (in *OpensearchIndexResourceSpec) DeepCopy() *OpensearchIndexResourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexResourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexSpec) DeepCopyInto(out *OpensearchIndexSpec) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make(map[string]OpensearchIndexAliasSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexSpec.
+func (in *OpensearchIndexSpec) DeepCopy() *OpensearchIndexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexStatus) DeepCopyInto(out *OpensearchIndexStatus) {
+	*out = *in
+	if in.ExistingIndex != nil {
+		in, out := &in.ExistingIndex, &out.ExistingIndex
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexStatus.
+func (in *OpensearchIndexStatus) DeepCopy() *OpensearchIndexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexTemplate) DeepCopyInto(out *OpensearchIndexTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexTemplate.
+func (in *OpensearchIndexTemplate) DeepCopy() *OpensearchIndexTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchIndexTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexTemplateList) DeepCopyInto(out *OpensearchIndexTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchIndexTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexTemplateList.
+func (in *OpensearchIndexTemplateList) DeepCopy() *OpensearchIndexTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchIndexTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexTemplateSpec) DeepCopyInto(out *OpensearchIndexTemplateSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.ComposedOf != nil {
+		in, out := &in.ComposedOf, &out.ComposedOf
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Meta != nil {
+		in, out := &in.Meta, &out.Meta
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataStream != nil {
+		in, out := &in.DataStream, &out.DataStream
+		*out = new(IndexTemplateDataStream)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexTemplateSpec.
+func (in *OpensearchIndexTemplateSpec) DeepCopy() *OpensearchIndexTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIndexTemplateStatus) DeepCopyInto(out *OpensearchIndexTemplateStatus) {
+	*out = *in
+	if in.ExistingIndexTemplate != nil {
+		in, out := &in.ExistingIndexTemplate, &out.ExistingIndexTemplate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.GeneratedIndexPatterns != nil {
+		in, out := &in.GeneratedIndexPatterns, &out.GeneratedIndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIndexTemplateStatus.
+func (in *OpensearchIndexTemplateStatus) DeepCopy() *OpensearchIndexTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIndexTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIngestPipeline) DeepCopyInto(out *OpensearchIngestPipeline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIngestPipeline.
+func (in *OpensearchIngestPipeline) DeepCopy() *OpensearchIngestPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIngestPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchIngestPipeline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIngestPipelineList) DeepCopyInto(out *OpensearchIngestPipelineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchIngestPipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIngestPipelineList.
+func (in *OpensearchIngestPipelineList) DeepCopy() *OpensearchIngestPipelineList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIngestPipelineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchIngestPipelineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIngestPipelineSpec) DeepCopyInto(out *OpensearchIngestPipelineSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Processors != nil {
+		in, out := &in.Processors, &out.Processors
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OnFailure != nil {
+		in, out := &in.OnFailure, &out.OnFailure
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Meta != nil {
+		in, out := &in.Meta, &out.Meta
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIngestPipelineSpec.
+func (in *OpensearchIngestPipelineSpec) DeepCopy() *OpensearchIngestPipelineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIngestPipelineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchIngestPipelineStatus) DeepCopyInto(out *OpensearchIngestPipelineStatus) {
+	*out = *in
+	if in.ExistingIngestPipeline != nil {
+		in, out := &in.ExistingIngestPipeline, &out.ExistingIngestPipeline
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchIngestPipelineStatus.
+func (in *OpensearchIngestPipelineStatus) DeepCopy() *OpensearchIngestPipelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchIngestPipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchNotificationChannel) DeepCopyInto(out *OpensearchNotificationChannel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchNotificationChannel.
+func (in *OpensearchNotificationChannel) DeepCopy() *OpensearchNotificationChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchNotificationChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchNotificationChannel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchNotificationChannelList) DeepCopyInto(out *OpensearchNotificationChannelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchNotificationChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchNotificationChannelList.
+func (in *OpensearchNotificationChannelList) DeepCopy() *OpensearchNotificationChannelList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchNotificationChannelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchNotificationChannelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchNotificationChannelSpec) DeepCopyInto(out *OpensearchNotificationChannelSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackChannel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookChannel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SNS != nil {
+		in, out := &in.SNS, &out.SNS
+		*out = new(SNSChannel)
+		**out = **in
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(EmailChannel)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchNotificationChannelSpec.
+func (in *OpensearchNotificationChannelSpec) DeepCopy() *OpensearchNotificationChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchNotificationChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchNotificationChannelStatus) DeepCopyInto(out *OpensearchNotificationChannelStatus) {
+	*out = *in
+	if in.ExistingChannel != nil {
+		in, out := &in.ExistingChannel, &out.ExistingChannel
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchNotificationChannelStatus.
+func (in *OpensearchNotificationChannelStatus) DeepCopy() *OpensearchNotificationChannelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchNotificationChannelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchReconcileLog) DeepCopyInto(out *OpensearchReconcileLog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchReconcileLog.
+func (in *OpensearchReconcileLog) DeepCopy() *OpensearchReconcileLog {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchReconcileLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchReconcileLog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchReconcileLogList) DeepCopyInto(out *OpensearchReconcileLogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchReconcileLog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchReconcileLogList.
+func (in *OpensearchReconcileLogList) DeepCopy() *OpensearchReconcileLogList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchReconcileLogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchReconcileLogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchReconcileLogSpec) DeepCopyInto(out *OpensearchReconcileLogSpec) {
+	*out = *in
+	out.Target = in.Target
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]ReconcileLogEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchReconcileLogSpec.
+func (in *OpensearchReconcileLogSpec) DeepCopy() *OpensearchReconcileLogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchReconcileLogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRemoteCluster) DeepCopyInto(out *OpensearchRemoteCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRemoteCluster.
+func (in *OpensearchRemoteCluster) DeepCopy() *OpensearchRemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchRemoteCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRemoteClusterList) DeepCopyInto(out *OpensearchRemoteClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchRemoteCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRemoteClusterList.
+func (in *OpensearchRemoteClusterList) DeepCopy() *OpensearchRemoteClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRemoteClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchRemoteClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRemoteClusterSpec) DeepCopyInto(out *OpensearchRemoteClusterSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.RemoteCluster != nil {
+		in, out := &in.RemoteCluster, &out.RemoteCluster
+		*out = new(RemoteClusterReference)
+		**out = **in
+	}
+	if in.Seeds != nil {
+		in, out := &in.Seeds, &out.Seeds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkipUnavailable != nil {
+		in, out := &in.SkipUnavailable, &out.SkipUnavailable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRemoteClusterSpec.
+func (in *OpensearchRemoteClusterSpec) DeepCopy() *OpensearchRemoteClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRemoteClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRemoteClusterStatus) DeepCopyInto(out *OpensearchRemoteClusterStatus) {
+	*out = *in
+	if in.ExistingConnection != nil {
+		in, out := &in.ExistingConnection, &out.ExistingConnection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.Connected != nil {
+		in, out := &in.Connected, &out.Connected
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NumNodesConnected != nil {
+		in, out := &in.NumNodesConnected, &out.NumNodesConnected
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRemoteClusterStatus.
+func (in *OpensearchRemoteClusterStatus) DeepCopy() *OpensearchRemoteClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRemoteClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRole) DeepCopyInto(out *OpensearchRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRole.
+func (in *OpensearchRole) DeepCopy() *OpensearchRole {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRoleList) DeepCopyInto(out *OpensearchRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleList.
+func (in *OpensearchRoleList) DeepCopy() *OpensearchRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRoleMapping) DeepCopyInto(out *OpensearchRoleMapping) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleMapping.
+func (in *OpensearchRoleMapping) DeepCopy() *OpensearchRoleMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRoleMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchRoleMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRoleMappingList) DeepCopyInto(out *OpensearchRoleMappingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchRoleMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleMappingList.
+func (in *OpensearchRoleMappingList) DeepCopy() *OpensearchRoleMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRoleMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchRoleMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRoleMappingSpec) DeepCopyInto(out *OpensearchRoleMappingSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.BackendRoles != nil {
+		in, out := &in.BackendRoles, &out.BackendRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleMappingSpec.
+func (in *OpensearchRoleMappingSpec) DeepCopy() *OpensearchRoleMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRoleMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRoleMappingStatus) DeepCopyInto(out *OpensearchRoleMappingStatus) {
+	*out = *in
+	if in.ExistingRoleMapping != nil {
+		in, out := &in.ExistingRoleMapping, &out.ExistingRoleMapping
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleMappingStatus.
+func (in *OpensearchRoleMappingStatus) DeepCopy() *OpensearchRoleMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRoleMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRoleSpec) DeepCopyInto(out *OpensearchRoleSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.ClusterPermissions != nil {
+		in, out := &in.ClusterPermissions, &out.ClusterPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IndexPermissions != nil {
+		in, out := &in.IndexPermissions, &out.IndexPermissions
+		*out = make([]IndexPermissionSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TenantPermissions != nil {
+		in, out := &in.TenantPermissions, &out.TenantPermissions
+		*out = make([]TenantPermissionsSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleSpec.
+func (in *OpensearchRoleSpec) DeepCopy() *OpensearchRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRoleStatus) DeepCopyInto(out *OpensearchRoleStatus) {
+	*out = *in
+	if in.ExistingRole != nil {
+		in, out := &in.ExistingRole, &out.ExistingRole
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRoleStatus.
+func (in *OpensearchRoleStatus) DeepCopy() *OpensearchRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRollupJob) DeepCopyInto(out *OpensearchRollupJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRollupJob.
+func (in *OpensearchRollupJob) DeepCopy() *OpensearchRollupJob {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRollupJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchRollupJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRollupJobList) DeepCopyInto(out *OpensearchRollupJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchRollupJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRollupJobList.
+func (in *OpensearchRollupJobList) DeepCopy() *OpensearchRollupJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRollupJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchRollupJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRollupJobSpec) DeepCopyInto(out *OpensearchRollupJobSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	in.Schedule.DeepCopyInto(&out.Schedule)
+	if in.Dimensions != nil {
+		in, out := &in.Dimensions, &out.Dimensions
+		*out = make([]RollupDimension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]RollupMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRollupJobSpec.
+func (in *OpensearchRollupJobSpec) DeepCopy() *OpensearchRollupJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRollupJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRollupJobStatus) DeepCopyInto(out *OpensearchRollupJobStatus) {
+	*out = *in
+	if in.ExistingJob != nil {
+		in, out := &in.ExistingJob, &out.ExistingJob
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRollupJobStatus.
+func (in *OpensearchRollupJobStatus) DeepCopy() *OpensearchRollupJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRollupJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSavedObjects) DeepCopyInto(out *OpensearchSavedObjects) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSavedObjects.
+func (in *OpensearchSavedObjects) DeepCopy() *OpensearchSavedObjects {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSavedObjects)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSavedObjects) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSavedObjectsList) DeepCopyInto(out *OpensearchSavedObjectsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchSavedObjects, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSavedObjectsList.
+func (in *OpensearchSavedObjectsList) DeepCopy() *OpensearchSavedObjectsList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSavedObjectsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSavedObjectsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSavedObjectsSpec) DeepCopyInto(out *OpensearchSavedObjectsSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	out.TenantRef = in.TenantRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSavedObjectsSpec.
+func (in *OpensearchSavedObjectsSpec) DeepCopy() *OpensearchSavedObjectsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSavedObjectsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSavedObjectsStatus) DeepCopyInto(out *OpensearchSavedObjectsStatus) {
+	*out = *in
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.ImportedObjects != nil {
+		in, out := &in.ImportedObjects, &out.ImportedObjects
+		*out = make([]SavedObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSavedObjectsStatus.
+func (in *OpensearchSavedObjectsStatus) DeepCopy() *OpensearchSavedObjectsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSavedObjectsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSearchPipeline) DeepCopyInto(out *OpensearchSearchPipeline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSearchPipeline.
+func (in *OpensearchSearchPipeline) DeepCopy() *OpensearchSearchPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSearchPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSearchPipeline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSearchPipelineList) DeepCopyInto(out *OpensearchSearchPipelineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchSearchPipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSearchPipelineList.
+func (in *OpensearchSearchPipelineList) DeepCopy() *OpensearchSearchPipelineList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSearchPipelineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSearchPipelineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSearchPipelineSpec) DeepCopyInto(out *OpensearchSearchPipelineSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.RequestProcessors != nil {
+		in, out := &in.RequestProcessors, &out.RequestProcessors
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResponseProcessors != nil {
+		in, out := &in.ResponseProcessors, &out.ResponseProcessors
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PhaseResultsProcessors != nil {
+		in, out := &in.PhaseResultsProcessors, &out.PhaseResultsProcessors
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSearchPipelineSpec.
+func (in *OpensearchSearchPipelineSpec) DeepCopy() *OpensearchSearchPipelineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSearchPipelineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSearchPipelineStatus) DeepCopyInto(out *OpensearchSearchPipelineStatus) {
+	*out = *in
+	if in.ExistingSearchPipeline != nil {
+		in, out := &in.ExistingSearchPipeline, &out.ExistingSearchPipeline
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSearchPipelineStatus.
+func (in *OpensearchSearchPipelineStatus) DeepCopy() *OpensearchSearchPipelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSearchPipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSearchTemplate) DeepCopyInto(out *OpensearchSearchTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSearchTemplate.
+func (in *OpensearchSearchTemplate) DeepCopy() *OpensearchSearchTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSearchTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSearchTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSearchTemplateList) DeepCopyInto(out *OpensearchSearchTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchSearchTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSearchTemplateList.
+func (in *OpensearchSearchTemplateList) DeepCopy() *OpensearchSearchTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSearchTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSearchTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSearchTemplateSpec) DeepCopyInto(out *OpensearchSearchTemplateSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSearchTemplateSpec.
+func (in *OpensearchSearchTemplateSpec) DeepCopy() *OpensearchSearchTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSearchTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSearchTemplateStatus) DeepCopyInto(out *OpensearchSearchTemplateStatus) {
+	*out = *in
+	if in.ExistingTemplate != nil {
+		in, out := &in.ExistingTemplate, &out.ExistingTemplate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSearchTemplateStatus.
+func (in *OpensearchSearchTemplateStatus) DeepCopy() *OpensearchSearchTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSearchTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSecurityAnalyticsDetector) DeepCopyInto(out *OpensearchSecurityAnalyticsDetector) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSecurityAnalyticsDetector.
+func (in *OpensearchSecurityAnalyticsDetector) DeepCopy() *OpensearchSecurityAnalyticsDetector {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSecurityAnalyticsDetector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSecurityAnalyticsDetector) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSecurityAnalyticsDetectorList) DeepCopyInto(out *OpensearchSecurityAnalyticsDetectorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchSecurityAnalyticsDetector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSecurityAnalyticsDetectorList.
+func (in *OpensearchSecurityAnalyticsDetectorList) DeepCopy() *OpensearchSecurityAnalyticsDetectorList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSecurityAnalyticsDetectorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSecurityAnalyticsDetectorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSecurityAnalyticsDetectorSpec) DeepCopyInto(out *OpensearchSecurityAnalyticsDetectorSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrePackagedRules != nil {
+		in, out := &in.PrePackagedRules, &out.PrePackagedRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CustomRules != nil {
+		in, out := &in.CustomRules, &out.CustomRules
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSecurityAnalyticsDetectorSpec.
+func (in *OpensearchSecurityAnalyticsDetectorSpec) DeepCopy() *OpensearchSecurityAnalyticsDetectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSecurityAnalyticsDetectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSecurityAnalyticsDetectorStatus) DeepCopyInto(out *OpensearchSecurityAnalyticsDetectorStatus) {
+	*out = *in
+	if in.ExistingDetector != nil {
+		in, out := &in.ExistingDetector, &out.ExistingDetector
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSecurityAnalyticsDetectorStatus.
+func (in *OpensearchSecurityAnalyticsDetectorStatus) DeepCopy() *OpensearchSecurityAnalyticsDetectorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSecurityAnalyticsDetectorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSecurityAnalyticsRule) DeepCopyInto(out *OpensearchSecurityAnalyticsRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSecurityAnalyticsRule.
+func (in *OpensearchSecurityAnalyticsRule) DeepCopy() *OpensearchSecurityAnalyticsRule {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSecurityAnalyticsRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSecurityAnalyticsRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSecurityAnalyticsRuleList) DeepCopyInto(out *OpensearchSecurityAnalyticsRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchSecurityAnalyticsRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSecurityAnalyticsRuleList.
+func (in *OpensearchSecurityAnalyticsRuleList) DeepCopy() *OpensearchSecurityAnalyticsRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSecurityAnalyticsRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSecurityAnalyticsRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSecurityAnalyticsRuleSpec) DeepCopyInto(out *OpensearchSecurityAnalyticsRuleSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSecurityAnalyticsRuleSpec.
+func (in *OpensearchSecurityAnalyticsRuleSpec) DeepCopy() *OpensearchSecurityAnalyticsRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSecurityAnalyticsRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSecurityAnalyticsRuleStatus) DeepCopyInto(out *OpensearchSecurityAnalyticsRuleStatus) {
+	*out = *in
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSecurityAnalyticsRuleStatus.
+func (in *OpensearchSecurityAnalyticsRuleStatus) DeepCopy() *OpensearchSecurityAnalyticsRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSecurityAnalyticsRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSnapshotPolicy) DeepCopyInto(out *OpensearchSnapshotPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSnapshotPolicy.
+func (in *OpensearchSnapshotPolicy) DeepCopy() *OpensearchSnapshotPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSnapshotPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSnapshotPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSnapshotPolicyList) DeepCopyInto(out *OpensearchSnapshotPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchSnapshotPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSnapshotPolicyList.
+func (in *OpensearchSnapshotPolicyList) DeepCopy() *OpensearchSnapshotPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSnapshotPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSnapshotPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSnapshotPolicySpec) DeepCopyInto(out *OpensearchSnapshotPolicySpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	out.Creation = in.Creation
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(SnapshotDeletion)
+		(*in).DeepCopyInto(*out)
+	}
+	in.SnapshotConfig.DeepCopyInto(&out.SnapshotConfig)
+	if in.Notification != nil {
+		in, out := &in.Notification, &out.Notification
+		*out = new(SnapshotNotification)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSnapshotPolicySpec.
+func (in *OpensearchSnapshotPolicySpec) DeepCopy() *OpensearchSnapshotPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSnapshotPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSnapshotPolicyStatus) DeepCopyInto(out *OpensearchSnapshotPolicyStatus) {
+	*out = *in
+	if in.ExistingSnapshotPolicy != nil {
+		in, out := &in.ExistingSnapshotPolicy, &out.ExistingSnapshotPolicy
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSnapshotPolicyStatus.
+func (in *OpensearchSnapshotPolicyStatus) DeepCopy() *OpensearchSnapshotPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSnapshotPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSnapshotRepository) DeepCopyInto(out *OpensearchSnapshotRepository) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSnapshotRepository.
+func (in *OpensearchSnapshotRepository) DeepCopy() *OpensearchSnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSnapshotRepository) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSnapshotRepositoryList) DeepCopyInto(out *OpensearchSnapshotRepositoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchSnapshotRepository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSnapshotRepositoryList.
+func (in *OpensearchSnapshotRepositoryList) DeepCopy() *OpensearchSnapshotRepositoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSnapshotRepositoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchSnapshotRepositoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSnapshotRepositorySpec) DeepCopyInto(out *OpensearchSnapshotRepositorySpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3SnapshotRepository)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(GCSSnapshotRepository)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureSnapshotRepository)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FS != nil {
+		in, out := &in.FS, &out.FS
+		*out = new(FSSnapshotRepository)
+		**out = **in
+	}
+	if in.AdditionalSettings != nil {
+		in, out := &in.AdditionalSettings, &out.AdditionalSettings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSnapshotRepositorySpec.
+func (in *OpensearchSnapshotRepositorySpec) DeepCopy() *OpensearchSnapshotRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSnapshotRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchSnapshotRepositoryStatus) DeepCopyInto(out *OpensearchSnapshotRepositoryStatus) {
+	*out = *in
+	if in.ExistingSnapshotRepository != nil {
+		in, out := &in.ExistingSnapshotRepository, &out.ExistingSnapshotRepository
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchSnapshotRepositoryStatus.
+func (in *OpensearchSnapshotRepositoryStatus) DeepCopy() *OpensearchSnapshotRepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchSnapshotRepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchStoredScript) DeepCopyInto(out *OpensearchStoredScript) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchStoredScript.
+func (in *OpensearchStoredScript) DeepCopy() *OpensearchStoredScript {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchStoredScript)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchStoredScript) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchStoredScriptList) DeepCopyInto(out *OpensearchStoredScriptList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchStoredScript, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchStoredScriptList.
+func (in *OpensearchStoredScriptList) DeepCopy() *OpensearchStoredScriptList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchStoredScriptList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchStoredScriptList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchStoredScriptSpec) DeepCopyInto(out *OpensearchStoredScriptSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchStoredScriptSpec.
+func (in *OpensearchStoredScriptSpec) DeepCopy() *OpensearchStoredScriptSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchStoredScriptSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchStoredScriptStatus) DeepCopyInto(out *OpensearchStoredScriptStatus) {
+	*out = *in
+	if in.ExistingScript != nil {
+		in, out := &in.ExistingScript, &out.ExistingScript
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchStoredScriptStatus.
+func (in *OpensearchStoredScriptStatus) DeepCopy() *OpensearchStoredScriptStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchStoredScriptStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTenant) DeepCopyInto(out *OpensearchTenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTenant.
+func (in *OpensearchTenant) DeepCopy() *OpensearchTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchTenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTenantList) DeepCopyInto(out *OpensearchTenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchTenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTenantList.
+func (in *OpensearchTenantList) DeepCopy() *OpensearchTenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchTenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTenantSpec) DeepCopyInto(out *OpensearchTenantSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTenantSpec.
+func (in *OpensearchTenantSpec) DeepCopy() *OpensearchTenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTenantStatus) DeepCopyInto(out *OpensearchTenantStatus) {
+	*out = *in
+	if in.ExistingTenant != nil {
+		in, out := &in.ExistingTenant, &out.ExistingTenant
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTenantStatus.
+func (in *OpensearchTenantStatus) DeepCopy() *OpensearchTenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTransformJob) DeepCopyInto(out *OpensearchTransformJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTransformJob.
+func (in *OpensearchTransformJob) DeepCopy() *OpensearchTransformJob {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTransformJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchTransformJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTransformJobList) DeepCopyInto(out *OpensearchTransformJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchTransformJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTransformJobList.
+func (in *OpensearchTransformJobList) DeepCopy() *OpensearchTransformJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTransformJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchTransformJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTransformJobSpec) DeepCopyInto(out *OpensearchTransformJobSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	in.Schedule.DeepCopyInto(&out.Schedule)
+	if in.DataSelectionQuery != nil {
+		in, out := &in.DataSelectionQuery, &out.DataSelectionQuery
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]TransformGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Aggregations != nil {
+		in, out := &in.Aggregations, &out.Aggregations
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTransformJobSpec.
+func (in *OpensearchTransformJobSpec) DeepCopy() *OpensearchTransformJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTransformJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchTransformJobStatus) DeepCopyInto(out *OpensearchTransformJobStatus) {
+	*out = *in
+	if in.ExistingJob != nil {
+		in, out := &in.ExistingJob, &out.ExistingJob
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.LastRun != nil {
+		in, out := &in.LastRun, &out.LastRun
+		*out = new(TransformRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchTransformJobStatus.
+func (in *OpensearchTransformJobStatus) DeepCopy() *OpensearchTransformJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchTransformJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchUser) DeepCopyInto(out *OpensearchUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUser.
+func (in *OpensearchUser) DeepCopy() *OpensearchUser {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchUserList) DeepCopyInto(out *OpensearchUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserList.
+func (in *OpensearchUserList) DeepCopy() *OpensearchUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchUserRoleBinding) DeepCopyInto(out *OpensearchUserRoleBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserRoleBinding.
+func (in *OpensearchUserRoleBinding) DeepCopy() *OpensearchUserRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchUserRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchUserRoleBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchUserRoleBindingList) DeepCopyInto(out *OpensearchUserRoleBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpensearchUserRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserRoleBindingList.
+func (in *OpensearchUserRoleBindingList) DeepCopy() *OpensearchUserRoleBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchUserRoleBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpensearchUserRoleBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchUserRoleBindingSpec) DeepCopyInto(out *OpensearchUserRoleBindingSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendRoles != nil {
+		in, out := &in.BackendRoles, &out.BackendRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserRoleBindingSpec.
+func (in *OpensearchUserRoleBindingSpec) DeepCopy() *OpensearchUserRoleBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchUserRoleBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchUserRoleBindingStatus) DeepCopyInto(out *OpensearchUserRoleBindingStatus) {
+	*out = *in
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.ProvisionedRoles != nil {
+		in, out := &in.ProvisionedRoles, &out.ProvisionedRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProvisionedUsers != nil {
+		in, out := &in.ProvisionedUsers, &out.ProvisionedUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProvisionedBackendRoles != nil {
+		in, out := &in.ProvisionedBackendRoles, &out.ProvisionedBackendRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserRoleBindingStatus.
+func (in *OpensearchUserRoleBindingStatus) DeepCopy() *OpensearchUserRoleBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchUserRoleBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchUserSpec) DeepCopyInto(out *OpensearchUserSpec) {
+	*out = *in
+	out.OpensearchRef = in.OpensearchRef
+	in.PasswordFrom.DeepCopyInto(&out.PasswordFrom)
+	if in.OpendistroSecurityRoles != nil {
+		in, out := &in.OpendistroSecurityRoles, &out.OpendistroSecurityRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendRoles != nil {
+		in, out := &in.BackendRoles, &out.BackendRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserSpec.
+func (in *OpensearchUserSpec) DeepCopy() *OpensearchUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchUserStatus) DeepCopyInto(out *OpensearchUserStatus) {
+	*out = *in
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(types.UID)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchUserStatus.
+func (in *OpensearchUserStatus) DeepCopy() *OpensearchUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorBearerToken) DeepCopyInto(out *OperatorBearerToken) {
+	*out = *in
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OperatorOIDCProvider)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorBearerToken.
+func (in *OperatorBearerToken) DeepCopy() *OperatorBearerToken {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorBearerToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorOIDCProvider) DeepCopyInto(out *OperatorOIDCProvider) {
+	*out = *in
+	out.ClientSecret = in.ClientSecret
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorOIDCProvider.
+func (in *OperatorOIDCProvider) DeepCopy() *OperatorOIDCProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorOIDCProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCSource) DeepCopyInto(out *PVCSource) {
+	*out = *in
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCSource.
+func (in *PVCSource) DeepCopy() *PVCSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PdbConfig) DeepCopyInto(out *PdbConfig) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PdbConfig.
+func (in *PdbConfig) DeepCopy() *PdbConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PdbConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceConfig) DeepCopyInto(out *PersistenceConfig) {
+	*out = *in
+	in.PersistenceSource.DeepCopyInto(&out.PersistenceSource)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceConfig.
+func (in *PersistenceConfig) DeepCopy() *PersistenceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceSource) DeepCopyInto(out *PersistenceSource) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PVCSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(corev1.EmptyDirVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostPath != nil {
+		in, out := &in.HostPath, &out.HostPath
+		*out = new(corev1.HostPathVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceSource.
+func (in *PersistenceSource) DeepCopy() *PersistenceSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecentSnapshotRequirement) DeepCopyInto(out *RecentSnapshotRequirement) {
+	*out = *in
+	out.MaxAge = in.MaxAge
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecentSnapshotRequirement.
+func (in *RecentSnapshotRequirement) DeepCopy() *RecentSnapshotRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(RecentSnapshotRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileLogEntry) DeepCopyInto(out *ReconcileLogEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileLogEntry.
+func (in *ReconcileLogEntry) DeepCopy() *ReconcileLogEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileLogEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileLogTarget) DeepCopyInto(out *ReconcileLogTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileLogTarget.
+func (in *ReconcileLogTarget) DeepCopy() *ReconcileLogTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileLogTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionAffinity) DeepCopyInto(out *RegionAffinity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionAffinity.
+func (in *RegionAffinity) DeepCopy() *RegionAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterReference) DeepCopyInto(out *RemoteClusterReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterReference.
+func (in *RemoteClusterReference) DeepCopy() *RemoteClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaCount) DeepCopyInto(out *ReplicaCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaCount.
+func (in *ReplicaCount) DeepCopy() *ReplicaCount {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaPolicy) DeepCopyInto(out *ReplicaPolicy) {
+	*out = *in
+	if in.FactorPercent != nil {
+		in, out := &in.FactorPercent, &out.FactorPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaPolicy.
+func (in *ReplicaPolicy) DeepCopy() *ReplicaPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicatedIndexStatus) DeepCopyInto(out *ReplicatedIndexStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicatedIndexStatus.
+func (in *ReplicatedIndexStatus) DeepCopy() *ReplicatedIndexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicatedIndexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationRoles) DeepCopyInto(out *ReplicationRoles) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationRoles.
+func (in *ReplicationRoles) DeepCopy() *ReplicationRoles {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationRoles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Retry) DeepCopyInto(out *Retry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retry.
+func (in *Retry) DeepCopy() *Retry {
+	if in == nil {
+		return nil
+	}
+	out := new(Retry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rollover) DeepCopyInto(out *Rollover) {
+	*out = *in
+	if in.MinDocCount != nil {
+		in, out := &in.MinDocCount, &out.MinDocCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinIndexAge != nil {
+		in, out := &in.MinIndexAge, &out.MinIndexAge
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinPrimaryShardSize != nil {
+		in, out := &in.MinPrimaryShardSize, &out.MinPrimaryShardSize
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollover.
+func (in *Rollover) DeepCopy() *Rollover {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rollup) DeepCopyInto(out *Rollup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollup.
+func (in *Rollup) DeepCopy() *Rollup {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollupDateHistogram) DeepCopyInto(out *RollupDateHistogram) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupDateHistogram.
+func (in *RollupDateHistogram) DeepCopy() *RollupDateHistogram {
+	if in == nil {
+		return nil
+	}
+	out := new(RollupDateHistogram)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollupDimension) DeepCopyInto(out *RollupDimension) {
+	*out = *in
+	if in.DateHistogram != nil {
+		in, out := &in.DateHistogram, &out.DateHistogram
+		*out = new(RollupDateHistogram)
+		**out = **in
+	}
+	if in.Terms != nil {
+		in, out := &in.Terms, &out.Terms
+		*out = new(RollupTerms)
+		**out = **in
+	}
+	if in.Histogram != nil {
+		in, out := &in.Histogram, &out.Histogram
+		*out = new(RollupHistogram)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupDimension.
+func (in *RollupDimension) DeepCopy() *RollupDimension {
+	if in == nil {
+		return nil
+	}
+	out := new(RollupDimension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollupHistogram) DeepCopyInto(out *RollupHistogram) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupHistogram.
+func (in *RollupHistogram) DeepCopy() *RollupHistogram {
+	if in == nil {
+		return nil
+	}
+	out := new(RollupHistogram)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollupInterval) DeepCopyInto(out *RollupInterval) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupInterval.
+func (in *RollupInterval) DeepCopy() *RollupInterval {
+	if in == nil {
+		return nil
+	}
+	out := new(RollupInterval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollupMetric) DeepCopyInto(out *RollupMetric) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]RollupMetricType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupMetric.
+func (in *RollupMetric) DeepCopy() *RollupMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(RollupMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollupSchedule) DeepCopyInto(out *RollupSchedule) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(RollupInterval)
+		**out = **in
+	}
+	if in.Cron != nil {
+		in, out := &in.Cron, &out.Cron
+		*out = new(CronSchedule)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupSchedule.
+func (in *RollupSchedule) DeepCopy() *RollupSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(RollupSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollupTerms) DeepCopyInto(out *RollupTerms) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollupTerms.
+func (in *RollupTerms) DeepCopy() *RollupTerms {
+	if in == nil {
+		return nil
+	}
+	out := new(RollupTerms)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PdbConfig.
-func (in *PdbConfig) DeepCopy() *PdbConfig {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Credentials) DeepCopyInto(out *S3Credentials) {
+	*out = *in
+	out.Secret = in.Secret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Credentials.
+func (in *S3Credentials) DeepCopy() *S3Credentials {
 	if in == nil {
 		return nil
 	}
-	out := new(PdbConfig)
+	out := new(S3Credentials)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistenceConfig) DeepCopyInto(out *PersistenceConfig) {
+func (in *S3SnapshotRepository) DeepCopyInto(out *S3SnapshotRepository) {
 	*out = *in
-	in.PersistenceSource.DeepCopyInto(&out.PersistenceSource)
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(S3Credentials)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceConfig.
-func (in *PersistenceConfig) DeepCopy() *PersistenceConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3SnapshotRepository.
+func (in *S3SnapshotRepository) DeepCopy() *S3SnapshotRepository {
 	if in == nil {
 		return nil
 	}
-	out := new(PersistenceConfig)
+	out := new(S3SnapshotRepository)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistenceSource) DeepCopyInto(out *PersistenceSource) {
+func (in *SNSChannel) DeepCopyInto(out *SNSChannel) {
 	*out = *in
-	if in.PVC != nil {
-		in, out := &in.PVC, &out.PVC
-		*out = new(PVCSource)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.EmptyDir != nil {
-		in, out := &in.EmptyDir, &out.EmptyDir
-		*out = new(corev1.EmptyDirVolumeSource)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HostPath != nil {
-		in, out := &in.HostPath, &out.HostPath
-		*out = new(corev1.HostPathVolumeSource)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceSource.
-func (in *PersistenceSource) DeepCopy() *PersistenceSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SNSChannel.
+func (in *SNSChannel) DeepCopy() *SNSChannel {
 	if in == nil {
 		return nil
 	}
-	out := new(PersistenceSource)
+	out := new(SNSChannel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicaCount) DeepCopyInto(out *ReplicaCount) {
+func (in *SavedObjectReference) DeepCopyInto(out *SavedObjectReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaCount.
-func (in *ReplicaCount) DeepCopy() *ReplicaCount {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SavedObjectReference.
+func (in *SavedObjectReference) DeepCopy() *SavedObjectReference {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicaCount)
+	out := new(SavedObjectReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Retry) DeepCopyInto(out *Retry) {
+func (in *ScalingSchedule) DeepCopyInto(out *ScalingSchedule) {
 	*out = *in
+	out.Schedule = in.Schedule
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retry.
-func (in *Retry) DeepCopy() *Retry {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingSchedule.
+func (in *ScalingSchedule) DeepCopy() *ScalingSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(Retry)
+	out := new(ScalingSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Rollover) DeepCopyInto(out *Rollover) {
+func (in *SchemaMigration) DeepCopyInto(out *SchemaMigration) {
 	*out = *in
-	if in.MinDocCount != nil {
-		in, out := &in.MinDocCount, &out.MinDocCount
-		*out = new(int64)
-		**out = **in
-	}
-	if in.MinIndexAge != nil {
-		in, out := &in.MinIndexAge, &out.MinIndexAge
-		*out = new(string)
-		**out = **in
-	}
-	if in.MinPrimaryShardSize != nil {
-		in, out := &in.MinPrimaryShardSize, &out.MinPrimaryShardSize
-		*out = new(string)
-		**out = **in
-	}
-	if in.MinSize != nil {
-		in, out := &in.MinSize, &out.MinSize
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollover.
-func (in *Rollover) DeepCopy() *Rollover {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaMigration.
+func (in *SchemaMigration) DeepCopy() *SchemaMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(Rollover)
+	out := new(SchemaMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Rollup) DeepCopyInto(out *Rollup) {
+func (in *SchemaMigrationStatus) DeepCopyInto(out *SchemaMigrationStatus) {
 	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollup.
-func (in *Rollup) DeepCopy() *Rollup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaMigrationStatus.
+func (in *SchemaMigrationStatus) DeepCopy() *SchemaMigrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Rollup)
+	out := new(SchemaMigrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2290,7 +6323,7 @@ func (in *Security) DeepCopyInto(out *Security) {
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = new(SecurityConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -2310,6 +6343,12 @@ func (in *SecurityConfig) DeepCopyInto(out *SecurityConfig) {
 	out.SecurityconfigSecret = in.SecurityconfigSecret
 	out.AdminSecret = in.AdminSecret
 	out.AdminCredentialsSecret = in.AdminCredentialsSecret
+	out.OperatorClientCertSecret = in.OperatorClientCertSecret
+	if in.OperatorBearerToken != nil {
+		in, out := &in.OperatorBearerToken, &out.OperatorBearerToken
+		*out = new(OperatorBearerToken)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityConfig.
@@ -2362,6 +6401,22 @@ func (in *Shrink) DeepCopy() *Shrink {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackChannel) DeepCopyInto(out *SlackChannel) {
+	*out = *in
+	in.URLFrom.DeepCopyInto(&out.URLFrom)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackChannel.
+func (in *SlackChannel) DeepCopy() *SlackChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Snapshot) DeepCopyInto(out *Snapshot) {
 	*out = *in
@@ -2377,6 +6432,113 @@ func (in *Snapshot) DeepCopy() *Snapshot {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotConfig) DeepCopyInto(out *SnapshotConfig) {
+	*out = *in
+	if in.IncludeGlobalState != nil {
+		in, out := &in.IncludeGlobalState, &out.IncludeGlobalState
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotConfig.
+func (in *SnapshotConfig) DeepCopy() *SnapshotConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotCreation) DeepCopyInto(out *SnapshotCreation) {
+	*out = *in
+	out.Schedule = in.Schedule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotCreation.
+func (in *SnapshotCreation) DeepCopy() *SnapshotCreation {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotCreation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotDeleteCondition) DeepCopyInto(out *SnapshotDeleteCondition) {
+	*out = *in
+	if in.MaxCount != nil {
+		in, out := &in.MaxCount, &out.MaxCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.MinCount != nil {
+		in, out := &in.MinCount, &out.MinCount
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotDeleteCondition.
+func (in *SnapshotDeleteCondition) DeepCopy() *SnapshotDeleteCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotDeleteCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotDeletion) DeepCopyInto(out *SnapshotDeletion) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(CronSchedule)
+		**out = **in
+	}
+	in.Condition.DeepCopyInto(&out.Condition)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotDeletion.
+func (in *SnapshotDeletion) DeepCopy() *SnapshotDeletion {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotNotification) DeepCopyInto(out *SnapshotNotification) {
+	*out = *in
+	if in.Failure != nil {
+		in, out := &in.Failure, &out.Failure
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotNotification.
+func (in *SnapshotNotification) DeepCopy() *SnapshotNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnapshotRepoConfig) DeepCopyInto(out *SnapshotRepoConfig) {
 	*out = *in
@@ -2557,6 +6719,103 @@ func (in *TlsSecret) DeepCopy() *TlsSecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransformDateHistogram) DeepCopyInto(out *TransformDateHistogram) {
+	*out = *in
+	out.RollupDateHistogram = in.RollupDateHistogram
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransformDateHistogram.
+func (in *TransformDateHistogram) DeepCopy() *TransformDateHistogram {
+	if in == nil {
+		return nil
+	}
+	out := new(TransformDateHistogram)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransformGroup) DeepCopyInto(out *TransformGroup) {
+	*out = *in
+	if in.DateHistogram != nil {
+		in, out := &in.DateHistogram, &out.DateHistogram
+		*out = new(TransformDateHistogram)
+		**out = **in
+	}
+	if in.Terms != nil {
+		in, out := &in.Terms, &out.Terms
+		*out = new(TransformTerms)
+		**out = **in
+	}
+	if in.Histogram != nil {
+		in, out := &in.Histogram, &out.Histogram
+		*out = new(TransformHistogram)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransformGroup.
+func (in *TransformGroup) DeepCopy() *TransformGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(TransformGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransformHistogram) DeepCopyInto(out *TransformHistogram) {
+	*out = *in
+	out.RollupHistogram = in.RollupHistogram
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransformHistogram.
+func (in *TransformHistogram) DeepCopy() *TransformHistogram {
+	if in == nil {
+		return nil
+	}
+	out := new(TransformHistogram)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransformRunStatus) DeepCopyInto(out *TransformRunStatus) {
+	*out = *in
+	if in.LastUpdatedAt != nil {
+		in, out := &in.LastUpdatedAt, &out.LastUpdatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransformRunStatus.
+func (in *TransformRunStatus) DeepCopy() *TransformRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TransformRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransformTerms) DeepCopyInto(out *TransformTerms) {
+	*out = *in
+	out.RollupTerms = in.RollupTerms
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransformTerms.
+func (in *TransformTerms) DeepCopy() *TransformTerms {
+	if in == nil {
+		return nil
+	}
+	out := new(TransformTerms)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Transition) DeepCopyInto(out *Transition) {
 	*out = *in
@@ -2572,3 +6831,57 @@ func (in *Transition) DeepCopy() *Transition {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookChannel) DeepCopyInto(out *WebhookChannel) {
+	*out = *in
+	if in.URLFrom != nil {
+		in, out := &in.URLFrom, &out.URLFrom
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretHeaders != nil {
+		in, out := &in.SecretHeaders, &out.SecretHeaders
+		*out = make(map[string]corev1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookChannel.
+func (in *WebhookChannel) DeepCopy() *WebhookChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwarenessConfig) DeepCopyInto(out *ZoneAwarenessConfig) {
+	*out = *in
+	if in.ForcedZones != nil {
+		in, out := &in.ForcedZones, &out.ForcedZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwarenessConfig.
+func (in *ZoneAwarenessConfig) DeepCopy() *ZoneAwarenessConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwarenessConfig)
+	in.DeepCopyInto(out)
+	return out
+}
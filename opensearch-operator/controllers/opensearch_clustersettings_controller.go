@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// OpensearchClusterSettingsReconciler reconciles a OpensearchClusterSettings object
+type OpensearchClusterSettingsReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Instance *opsterv1.OpensearchClusterSettings
+	logr.Logger
+	// DenyList are the patterns of the cluster settings that cannot be managed, nil uses the default deny list
+	DenyList []string
+}
+
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchclustersettings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchclustersettings/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchclustersettings/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *OpensearchClusterSettingsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Logger = log.FromContext(ctx).WithValues("clustersettings", req.NamespacedName)
+	r.Logger.Info("Reconciling OpensearchClusterSettings")
+
+	r.Instance = &opsterv1.OpensearchClusterSettings{}
+	err := r.Get(ctx, req.NamespacedName, r.Instance)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	clusterSettingsReconciler := reconcilers.NewClusterSettingsReconciler(
+		ctx,
+		r.Client,
+		r.Recorder,
+		r.Instance,
+		reconcilers.WithClusterSettingsDenyList(r.DenyList),
+	)
+
+	if r.Instance.DeletionTimestamp.IsZero() {
+		controllerutil.AddFinalizer(r.Instance, OpensearchFinalizer)
+		err = r.Client.Update(ctx, r.Instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return clusterSettingsReconciler.Reconcile()
+	} else {
+		if controllerutil.ContainsFinalizer(r.Instance, OpensearchFinalizer) {
+			err = clusterSettingsReconciler.Delete()
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(r.Instance, OpensearchFinalizer)
+			return ctrl.Result{}, r.Client.Update(ctx, r.Instance)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchClusterSettingsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opsterv1.OpensearchClusterSettings{}).
+		Owns(&opsterv1.OpenSearchCluster{}). // Get notified when opensearch clusters change
+		Complete(r)
+}
@@ -2,16 +2,23 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // OpensearchComponentTemplateReconciler reconciles a OpensearchComponentTemplate object
@@ -20,12 +27,59 @@ type OpensearchComponentTemplateReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 	Instance *opsterv1.OpensearchComponentTemplate
+	// ReconcileLog configures the optional durable log of reconcile transitions
+	ReconcileLog reconcilers.ReconcileLogConfig
+	// Tombstones configures keeping the last applied body of deleted component templates
+	Tombstones reconcilers.TombstoneConfig
+	// ApplyQueue orders the applies to the same cluster by priority, nil does not limit applies
+	ApplyQueue *reconcilers.ApplyQueue
+	// ETagCache keeps the ETags of fetched component templates to send conditional requests, nil disables them
+	ETagCache *services.ETagCache
+	// RequestCompression gzips the request bodies sent to OpenSearch, nil sends them uncompressed
+	RequestCompression *services.RequestCompression
+	// RequeueJitter moves the requeue intervals randomly by up to ±RequeueJitter of them, 0 disables it
+	RequeueJitter float64
+	// StatusPusher pushes the state of the component templates to a Pushgateway, nil does not push them
+	StatusPusher *reconcilers.StatusPusher
+	// ConditionalWrites only writes component templates not changed by another writer since they were read,
+	// retrying a conflict up to ConditionalWriteRetries times
+	ConditionalWrites       bool
+	ConditionalWriteRetries int
+	// Redaction redacts the values of template paths wherever template content is surfaced, nil redacts none
+	Redaction *helpers.Redaction
+	// NodePoolConfirmRetries is how often node pools not returning a written component template are asked again,
+	// NodePoolConfirmInterval apart
+	NodePoolConfirmRetries  int
+	NodePoolConfirmInterval time.Duration
+	// RequestLog logs the requests sent to OpenSearch for component templates it is activated for, nil logs none
+	RequestLog *services.RequestLog
+	// ClientFactory shares the OpenSearch clients of a cluster between reconciles, nil creates one per reconcile
+	ClientFactory *util.ClientFactory
+	// EventVerbosity selects the events emitted for component templates without an event verbosity annotation
+	EventVerbosity reconcilers.EventVerbosity
+	// TemplatePolicies is the ConfigMap with the policies component templates are checked against, empty checks none
+	TemplatePolicies types.NamespacedName
+	// StaleSpecPolicy selects what happens to the outcome of a reconcile of a spec that was changed while it ran
+	StaleSpecPolicy reconcilers.StaleSpecPolicy
+	// UpgradeHandling selects how an upgrade of the cluster is detected to defer changes while it runs
+	UpgradeHandling reconcilers.UpgradeHandling
+	// CacheWarmer seeds the cache component templates are read from right after the operator started, nil reads
+	// them from OpenSearch
+	CacheWarmer *reconcilers.ComponentTemplateCacheWarmer
+	// DeadLetterAfter is how long a component template stays in a failure state before it is reported as dead letter,
+	// in the metric and in DeadLetters if it is not nil. 0 reports none
+	DeadLetterAfter time.Duration
+	DeadLetters     *reconcilers.DeadLetterReport
 	logr.Logger
 }
 
 //+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchcomponenttemplates,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchcomponenttemplates/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchcomponenttemplates/finalizers,verbs=update
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchreconcilelogs,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchroles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchindextemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=endpoints,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -44,6 +98,24 @@ func (r *OpensearchComponentTemplateReconciler) Reconcile(ctx context.Context, r
 		r.Client,
 		r.Recorder,
 		r.Instance,
+		reconcilers.WithReconcileLog(r.ReconcileLog),
+		reconcilers.WithTombstones(r.Tombstones),
+		reconcilers.WithApplyQueue(r.ApplyQueue),
+		reconcilers.WithETagCache(r.ETagCache),
+		reconcilers.WithRequestCompression(r.RequestCompression),
+		reconcilers.WithRequeueJitter(r.RequeueJitter),
+		reconcilers.WithStatusPusher(r.StatusPusher),
+		reconcilers.WithConditionalWrites(r.ConditionalWrites, r.ConditionalWriteRetries),
+		reconcilers.WithRedaction(r.Redaction),
+		reconcilers.WithNodePoolConfirmation(r.NodePoolConfirmRetries, r.NodePoolConfirmInterval),
+		reconcilers.WithRequestLog(r.RequestLog),
+		reconcilers.WithClientFactory(r.ClientFactory),
+		reconcilers.WithEventVerbosity(r.EventVerbosity),
+		reconcilers.WithTemplatePolicies(r.TemplatePolicies),
+		reconcilers.WithStaleSpecPolicy(r.StaleSpecPolicy),
+		reconcilers.WithUpgradeHandling(r.UpgradeHandling),
+		reconcilers.WithComponentTemplateCacheWarmer(r.CacheWarmer),
+		reconcilers.WithDeadLetters(r.DeadLetterAfter, r.DeadLetters),
 	)
 
 	if r.Instance.DeletionTimestamp.IsZero() {
@@ -67,10 +139,40 @@ func (r *OpensearchComponentTemplateReconciler) Reconcile(ctx context.Context, r
 	return ctrl.Result{}, nil
 }
 
+func (r *OpensearchComponentTemplateReconciler) handleRoleEvent(ctx context.Context, role client.Object) []reconcile.Request {
+	reconcileRequests := []reconcile.Request{}
+
+	templates := &opsterv1.OpensearchComponentTemplateList{}
+	if err := r.List(ctx, templates, client.InNamespace(role.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list component templates for role", "role", role.GetName())
+		return reconcileRequests
+	}
+
+	// Trigger reconcile for the component templates requiring field security from the role
+	for _, template := range templates.Items {
+		if template.Spec.FieldSecurity == nil || template.Spec.FieldSecurity.RoleRef.Name != role.GetName() {
+			continue
+		}
+		reconcileRequests = append(reconcileRequests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      template.Name,
+				Namespace: template.Namespace,
+			},
+		})
+	}
+
+	return reconcileRequests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *OpensearchComponentTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&opsterv1.OpensearchComponentTemplate{}).
 		Owns(&opsterv1.OpenSearchCluster{}). // Get notified when opensearch clusters change
+		// Get notified when roles providing the field security of templates change
+		Watches(
+			&opsterv1.OpensearchRole{},
+			handler.EnqueueRequestsFromMapFunc(r.handleRoleEvent),
+		).
 		Complete(r)
 }
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// OpensearchCrossClusterReplicationReconciler reconciles a OpensearchCrossClusterReplication object
+type OpensearchCrossClusterReplicationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Instance *opsterv1.OpensearchCrossClusterReplication
+	logr.Logger
+}
+
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchcrossclusterreplications,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchcrossclusterreplications/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.opster.io,resources=opensearchcrossclusterreplications/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *OpensearchCrossClusterReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Logger = log.FromContext(ctx).WithValues("crossclusterreplication", req.NamespacedName)
+	r.Logger.Info("Reconciling OpensearchCrossClusterReplication")
+
+	r.Instance = &opsterv1.OpensearchCrossClusterReplication{}
+	err := r.Get(ctx, req.NamespacedName, r.Instance)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	crossClusterReplicationReconciler := reconcilers.NewCrossClusterReplicationReconciler(
+		ctx,
+		r.Client,
+		r.Recorder,
+		r.Instance,
+	)
+
+	if r.Instance.DeletionTimestamp.IsZero() {
+		controllerutil.AddFinalizer(r.Instance, OpensearchFinalizer)
+		err = r.Client.Update(ctx, r.Instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return crossClusterReplicationReconciler.Reconcile()
+	} else {
+		if controllerutil.ContainsFinalizer(r.Instance, OpensearchFinalizer) {
+			err = crossClusterReplicationReconciler.Delete()
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(r.Instance, OpensearchFinalizer)
+			return ctrl.Result{}, r.Client.Update(ctx, r.Instance)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchCrossClusterReplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opsterv1.OpensearchCrossClusterReplication{}).
+		Owns(&opsterv1.OpenSearchCluster{}). // Get notified when opensearch clusters change
+		Complete(r)
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"text/template"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers"
@@ -20,6 +21,10 @@ type OpensearchIndexTemplateReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 	Instance *opsterv1.OpensearchIndexTemplate
+	// ApplyQueue orders the applies to the same cluster by priority, nil does not limit applies
+	ApplyQueue *reconcilers.ApplyQueue
+	// IndexPatternTemplate generates the index patterns of templates with generateIndexPatterns set
+	IndexPatternTemplate *template.Template
 	logr.Logger
 }
 
@@ -44,6 +49,8 @@ func (r *OpensearchIndexTemplateReconciler) Reconcile(ctx context.Context, req c
 		r.Client,
 		r.Recorder,
 		r.Instance,
+		reconcilers.WithApplyQueue(r.ApplyQueue),
+		reconcilers.WithIndexPatternTemplate(r.IndexPatternTemplate),
 	)
 
 	if r.Instance.DeletionTimestamp.IsZero() {
@@ -21,8 +21,15 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/controllers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
 	"go.uber.org/zap/zapcore"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -57,6 +64,38 @@ func main() {
 	var probeAddr string
 	var watchNamespace string
 	var logLevel string
+	var reconcileLog reconcilers.ReconcileLogConfig
+	var tombstones reconcilers.TombstoneConfig
+	var clusterApplyConcurrency int
+	var componentTemplatePreview bool
+	var indexPatternTemplate string
+	var compressRequests bool
+	var requeueJitter float64
+	var pushgatewayURL string
+	var pushgatewayJob string
+	var conditionalWrites bool
+	var conditionalWriteRetries int
+	var nodePoolConfirmRetries int
+	var nodePoolConfirmInterval time.Duration
+	var requestLogSink string
+	var requestLogAll bool
+	var requestLogTTL time.Duration
+	var requestLogMaxEntries int
+	var shareClients bool
+	var clientIdleTimeout time.Duration
+	var warmCache bool
+	var cacheWarmupTimeout time.Duration
+	var cacheMaxAge time.Duration
+	var clientMaxAge time.Duration
+	var redactedPaths string
+	var mappingValidation string
+	var eventVerbosity string
+	var staleSpecPolicy string
+	var upgradeHandling string
+	var deadLetterAfter time.Duration
+	var deadLetterConfigMap string
+	var templatePolicies string
+	var clusterSettingsDenyList string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -65,6 +104,96 @@ func main() {
 	flag.StringVar(&watchNamespace, "watch-namespace", "",
 		"The namespace that controller manager is restricted to watch. If not set, default is to watch all namespaces.")
 	flag.StringVar(&logLevel, "loglevel", "info", "The log level to use for the operator logs. Possible values: debug,info,warn,error")
+	flag.BoolVar(&reconcileLog.Enabled, "reconcile-log", false,
+		"Record reconcile state transitions in OpensearchReconcileLog objects so they outlive the event TTL.")
+	flag.IntVar(&reconcileLog.MaxEntries, "reconcile-log-max-entries", 50, "The maximum number of entries kept per reconcile log.")
+	flag.DurationVar(&reconcileLog.MaxAge, "reconcile-log-max-age", 0,
+		"Reconcile log entries older than this are dropped. If not set, entries are only bounded by their number.")
+	flag.BoolVar(&tombstones.Enabled, "tombstones", false,
+		"Keep the last applied body of deleted component templates in the opensearch-tombstones ConfigMap of their namespace.")
+	flag.IntVar(&tombstones.MaxEntries, "tombstone-max-entries", 20, "The maximum number of tombstones kept per namespace.")
+	flag.DurationVar(&tombstones.MaxAge, "tombstone-max-age", 0,
+		"Tombstones older than this are dropped. If not set, tombstones are only bounded by their number.")
+	flag.IntVar(&clusterApplyConcurrency, "cluster-apply-concurrency", 0,
+		"The maximum number of index and component templates applied to the same cluster at once. "+
+			"Waiting templates are applied in order of their applyPriority. If not set, applies are not limited.")
+	flag.BoolVar(&componentTemplatePreview, "component-template-preview", false,
+		"Serve the component template preview endpoint on the metrics address. "+
+			"The endpoint is not authorized by the operator, only expose it through an authorizing proxy.")
+	flag.StringVar(&indexPatternTemplate, "index-pattern-template", "",
+		"Go template generating the index patterns of index templates with generateIndexPatterns set "+
+			"from their .Name, .Namespace, .Labels and .Annotations. Separate several patterns with commas.")
+	flag.BoolVar(&compressRequests, "compress-requests", false,
+		"Gzip the component templates sent to OpenSearch. Clusters rejecting compressed requests "+
+			"with 415 Unsupported Media Type are sent uncompressed requests instead.")
+	flag.Float64Var(&requeueJitter, "requeue-jitter", helpers.DefaultRequeueJitter,
+		"The factor component template requeue intervals are randomly moved by, e.g. 0.2 for ±20%, "+
+			"so their reconciles spread out over time. 0 requeues at fixed intervals.")
+	flag.StringVar(&pushgatewayURL, "pushgateway-url", "",
+		"The URL of a Prometheus Pushgateway the state of the component templates is pushed to. If not set, the states are not pushed.")
+	flag.StringVar(&pushgatewayJob, "pushgateway-job", reconcilers.DefaultStatusPushJob,
+		"The job the component template states are pushed to the Pushgateway as.")
+	flag.BoolVar(&conditionalWrites, "conditional-template-writes", false,
+		"Only write component templates that were not changed by another writer since the operator read them, "+
+			"protecting against concurrent operator instances running without leader election.")
+	flag.IntVar(&conditionalWriteRetries, "conditional-template-write-retries", 3,
+		"How often a component template write conflicting with another writer is retried with conditional writes.")
+	flag.IntVar(&nodePoolConfirmRetries, "node-pool-confirm-retries", 3,
+		"How often node pools not returning a written component template with confirmOnAllNodePools are asked again.")
+	flag.DurationVar(&nodePoolConfirmInterval, "node-pool-confirm-interval", 2*time.Second,
+		"The interval node pools not returning a written component template with confirmOnAllNodePools are asked again in.")
+	flag.StringVar(&requestLogSink, "request-log-sink", "",
+		"Where the debug log of the requests sent to OpenSearch is written to: stderr, the path of a file or an http(s) URL "+
+			"every entry is posted to. Requests are logged for component templates with the opensearch.opster.io/log-requests "+
+			"annotation set to true, or for all of them with --request-log-all. Empty disables the request log.")
+	flag.BoolVar(&requestLogAll, "request-log-all", false,
+		"Log the requests of all component templates to the request log sink, for --request-log-ttl after the operator starts.")
+	flag.DurationVar(&requestLogTTL, "request-log-ttl", 15*time.Minute,
+		"How long requests are logged after the request log is activated, for the operator or a component template.")
+	flag.IntVar(&requestLogMaxEntries, "request-log-max-entries", 1000,
+		"The number of requests logged per activation of the request log, further requests are not logged.")
+	flag.BoolVar(&shareClients, "share-opensearch-clients", false,
+		"Share the OpenSearch clients of a cluster between component template reconciles instead of creating a client "+
+			"in every reconcile. A client is created again when the credentials of the operator change.")
+	flag.DurationVar(&clientIdleTimeout, "opensearch-client-idle-timeout", 5*time.Minute,
+		"How long a shared OpenSearch client is kept without being used before it is closed, 0 keeps it.")
+	flag.DurationVar(&clientMaxAge, "opensearch-client-max-age", time.Hour,
+		"How long a shared OpenSearch client is used before it is created again, 0 keeps using it.")
+	flag.BoolVar(&warmCache, "warm-component-template-cache", false,
+		"Read all component templates of every cluster once when the operator starts, so the first component template "+
+			"reconciles read their templates from a cache instead of each from OpenSearch.")
+	flag.DurationVar(&cacheWarmupTimeout, "component-template-cache-warmup-timeout", 30*time.Second,
+		"How long reconciles wait for the component template cache to be warmed, the warmer gives up afterwards.")
+	flag.DurationVar(&cacheMaxAge, "component-template-cache-max-age", 5*time.Minute,
+		"How long the warmed component templates are read from the cache, 0 reads them until they were read once.")
+	flag.StringVar(&redactedPaths, "redacted-paths", "",
+		"Comma separated paths of component template bodies whose values are redacted in events, the status, diffs "+
+			"and tombstones, e.g. template.settings.index.analysis.filter.synonyms.synonyms_path. A * matches any key.")
+	flag.StringVar(&clusterSettingsDenyList, "cluster-settings-deny-list", strings.Join(helpers.DefaultClusterSettingsDenyList, ","),
+		"Comma separated patterns of the cluster settings OpensearchClusterSettings cannot manage, e.g. plugins.security.*. "+
+			"A * matches any part of a setting name, an empty list allows all settings.")
+	flag.StringVar(&eventVerbosity, "component-template-event-verbosity", string(reconcilers.EventVerbosityNormal),
+		"The events emitted for component templates, normal emits all events and warning only Warning events. The "+
+			"opensearch.opster.io/event-verbosity annotation of a component template takes precedence.")
+	flag.StringVar(&staleSpecPolicy, "component-template-stale-spec-policy", string(reconcilers.StaleSpecPolicyRequeue),
+		"What happens to the outcome of a component template reconcile when the spec was changed while it ran, requeue "+
+			"does not record its state and reconciles the new spec immediately, record records it anyway.")
+	flag.StringVar(&upgradeHandling, "component-template-upgrade-handling", string(reconcilers.UpgradeHandlingVersionSkew),
+		"How an upgrade of a cluster is detected to defer component template changes while it runs, status defers them "+
+			"while the cluster status reports an upgrade, version-skew also while its nodes run different versions and "+
+			"ignore does not defer them. Other objects are changed during upgrades.")
+	flag.StringVar(&templatePolicies, "component-template-policies", "",
+		"The namespace/name of a ConfigMap with policies component templates are checked against before they are "+
+			"applied, every key holds one policy. Empty checks no policies.")
+	flag.DurationVar(&deadLetterAfter, "dead-letter-after", time.Hour,
+		"How long a managed object stays in the ERROR or FORBIDDEN state before it is reported as dead letter in the "+
+			"opensearch_operator_dead_letter metric. 0 reports none.")
+	flag.StringVar(&deadLetterConfigMap, "dead-letter-configmap", "",
+		"The namespace/name of a ConfigMap the dead letters are listed in with their kind, cluster and last error. "+
+			"Empty only exports the metric.")
+	flag.StringVar(&mappingValidation, "component-template-mapping-validation", "",
+		"Serve a validating webhook comparing updated component templates against the applied mappings, warn admits "+
+			"incompatible mapping type changes with a warning and reject rejects them. Empty does not serve the webhook.")
 
 	opts := zap.Options{
 		Development: false,
@@ -161,22 +290,348 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "OpensearchActionGroup")
 		os.Exit(1)
 	}
+	var parsedIndexPatternTemplate *template.Template
+	if indexPatternTemplate != "" {
+		parsedIndexPatternTemplate, err = helpers.ParseIndexPatternTemplate(indexPatternTemplate)
+		if err != nil {
+			setupLog.Error(err, "invalid index pattern template")
+			os.Exit(1)
+		}
+	}
+	if err = helpers.ValidateJitter(requeueJitter); err != nil {
+		setupLog.Error(err, "invalid requeue jitter")
+		os.Exit(1)
+	}
+	etagCache := services.NewETagCache()
+	var requestCompression *services.RequestCompression
+	if compressRequests {
+		requestCompression = services.NewRequestCompression()
+	}
+	var applyQueue *reconcilers.ApplyQueue
+	if clusterApplyConcurrency > 0 {
+		applyQueue = reconcilers.NewApplyQueue(clusterApplyConcurrency)
+	}
 	if err = (&controllers.OpensearchIndexTemplateReconciler{
+		Client:               mgr.GetClient(),
+		Scheme:               mgr.GetScheme(),
+		Recorder:             mgr.GetEventRecorderFor("indextemplate-controller"),
+		ApplyQueue:           applyQueue,
+		IndexPatternTemplate: parsedIndexPatternTemplate,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchIndexTemplate")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchIngestPipelineReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("indextemplate-controller"),
+		Recorder: mgr.GetEventRecorderFor("ingestpipeline-controller"),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "OpensearchIndexTemplate")
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchIngestPipeline")
 		os.Exit(1)
 	}
-	if err = (&controllers.OpensearchComponentTemplateReconciler{
+	if err = (&controllers.OpensearchSnapshotPolicyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("snapshotpolicy-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchSnapshotPolicy")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchSnapshotRepositoryReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("snapshotrepository-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchSnapshotRepository")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchAlertingMonitorReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("alertingmonitor-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchAlertingMonitor")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchNotificationChannelReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("notificationchannel-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchNotificationChannel")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchIndexReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("index-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchIndex")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchDataStreamReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("componenttemplate-controller"),
+		Recorder: mgr.GetEventRecorderFor("datastream-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchDataStream")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchIndexAliasReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("indexalias-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchIndexAlias")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchRollupJobReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("rollupjob-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchRollupJob")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchTransformJobReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("transformjob-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchTransformJob")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchAnomalyDetectorReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("anomalydetector-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchAnomalyDetector")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchSearchPipelineReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("searchpipeline-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchSearchPipeline")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchStoredScriptReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("storedscript-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchStoredScript")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchSearchTemplateReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("searchtemplate-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchSearchTemplate")
+		os.Exit(1)
+	}
+	clusterSettingsDenyPatterns := []string{}
+	if clusterSettingsDenyList != "" {
+		clusterSettingsDenyPatterns = strings.Split(clusterSettingsDenyList, ",")
+	}
+	if err = (&controllers.OpensearchClusterSettingsReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("clustersettings-controller"),
+		DenyList: clusterSettingsDenyPatterns,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchClusterSettings")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchRoleMappingReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("rolemapping-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchRoleMapping")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchAuditConfigReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("auditconfig-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchAuditConfig")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchSecurityAnalyticsDetectorReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("opensearchsecurityanalyticsdetector-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchSecurityAnalyticsDetector")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchSecurityAnalyticsRuleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("opensearchsecurityanalyticsrule-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchSecurityAnalyticsRule")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchCrossClusterReplicationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("opensearchcrossclusterreplication-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchCrossClusterReplication")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpensearchRemoteClusterReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("opensearchremotecluster-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchRemoteCluster")
+		os.Exit(1)
+	}
+	var statusPusher *reconcilers.StatusPusher
+	if pushgatewayURL != "" {
+		statusPusher = reconcilers.NewStatusPusher(pushgatewayURL, pushgatewayJob)
+		if err = mgr.Add(statusPusher); err != nil {
+			setupLog.Error(err, "unable to add the status pusher")
+			os.Exit(1)
+		}
+	}
+	redaction := helpers.NewRedaction(strings.Split(redactedPaths, ","))
+	var requestLog *services.RequestLog
+	if requestLogSink != "" {
+		sink, err := services.NewRequestLogSink(requestLogSink)
+		if err != nil {
+			setupLog.Error(err, "unable to open the request log sink")
+			os.Exit(1)
+		}
+		requestLog = services.NewRequestLog(sink, requestLogAll, requestLogTTL, requestLogMaxEntries, redaction)
+	}
+	var clientFactory *util.ClientFactory
+	if shareClients {
+		clientFactory = util.NewClientFactory(clientIdleTimeout, clientMaxAge)
+		if err = mgr.Add(clientFactory); err != nil {
+			setupLog.Error(err, "unable to add the opensearch client factory")
+			os.Exit(1)
+		}
+	}
+	var cacheWarmer *reconcilers.ComponentTemplateCacheWarmer
+	if warmCache {
+		cacheWarmer = reconcilers.NewComponentTemplateCacheWarmer(
+			mgr.GetClient(),
+			cacheWarmupTimeout,
+			cacheMaxAge,
+			reconcilers.WithETagCache(etagCache),
+			reconcilers.WithRequestCompression(requestCompression),
+			reconcilers.WithRequestLog(requestLog),
+			reconcilers.WithClientFactory(clientFactory),
+		)
+		if err = mgr.Add(cacheWarmer); err != nil {
+			setupLog.Error(err, "unable to add the component template cache warmer")
+			os.Exit(1)
+		}
+	}
+	componentTemplateEventVerbosity, err := reconcilers.ParseEventVerbosity(eventVerbosity)
+	if err != nil {
+		setupLog.Error(err, "invalid component template event verbosity")
+		os.Exit(1)
+	}
+	componentTemplateStaleSpecPolicy, err := reconcilers.ParseStaleSpecPolicy(staleSpecPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid component template stale spec policy")
+		os.Exit(1)
+	}
+	componentTemplateUpgradeHandling, err := reconcilers.ParseUpgradeHandling(upgradeHandling)
+	if err != nil {
+		setupLog.Error(err, "invalid component template upgrade handling")
+		os.Exit(1)
+	}
+	templatePolicyConfigMap, err := reconcilers.ParseTemplatePolicyConfigMap(templatePolicies)
+	if err != nil {
+		setupLog.Error(err, "invalid component template policies")
+		os.Exit(1)
+	}
+	deadLetterConfigMapName, err := reconcilers.ParseDeadLetterConfigMap(deadLetterConfigMap)
+	if err != nil {
+		setupLog.Error(err, "invalid dead-letter ConfigMap")
+		os.Exit(1)
+	}
+	var deadLetters *reconcilers.DeadLetterReport
+	if deadLetterConfigMapName.Name != "" {
+		deadLetters = reconcilers.NewDeadLetterReport(mgr.GetClient(), deadLetterConfigMapName)
+		if err = mgr.Add(deadLetters); err != nil {
+			setupLog.Error(err, "unable to add the dead-letter report")
+			os.Exit(1)
+		}
+	}
+	if err = (&controllers.OpensearchComponentTemplateReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Recorder:                mgr.GetEventRecorderFor("componenttemplate-controller"),
+		ReconcileLog:            reconcileLog,
+		Tombstones:              tombstones,
+		ApplyQueue:              applyQueue,
+		ETagCache:               etagCache,
+		RequestCompression:      requestCompression,
+		RequeueJitter:           requeueJitter,
+		StatusPusher:            statusPusher,
+		ConditionalWrites:       conditionalWrites,
+		ConditionalWriteRetries: conditionalWriteRetries,
+		Redaction:               redaction,
+		NodePoolConfirmRetries:  nodePoolConfirmRetries,
+		NodePoolConfirmInterval: nodePoolConfirmInterval,
+		RequestLog:              requestLog,
+		ClientFactory:           clientFactory,
+		EventVerbosity:          componentTemplateEventVerbosity,
+		TemplatePolicies:        templatePolicyConfigMap,
+		StaleSpecPolicy:         componentTemplateStaleSpecPolicy,
+		UpgradeHandling:         componentTemplateUpgradeHandling,
+		CacheWarmer:             cacheWarmer,
+		DeadLetterAfter:         deadLetterAfter,
+		DeadLetters:             deadLetters,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpensearchComponentTemplate")
 		os.Exit(1)
 	}
+	if componentTemplatePreview {
+		previewHandler := reconcilers.NewComponentTemplatePreviewHandler(
+			mgr.GetClient(),
+			reconcilers.WithETagCache(etagCache),
+			reconcilers.WithRequestCompression(requestCompression),
+			reconcilers.WithRedaction(redaction),
+			reconcilers.WithRequestLog(requestLog),
+			reconcilers.WithClientFactory(clientFactory),
+			reconcilers.WithComponentTemplateCacheWarmer(cacheWarmer),
+		)
+		if err = mgr.AddMetricsExtraHandler(reconcilers.ComponentTemplatePreviewPath, previewHandler); err != nil {
+			setupLog.Error(err, "unable to add component template preview endpoint")
+			os.Exit(1)
+		}
+	}
+	if mappingValidation != "" {
+		validator, err := reconcilers.NewComponentTemplateValidator(reconcilers.MappingValidationMode(mappingValidation))
+		if err != nil {
+			setupLog.Error(err, "invalid component template mapping validation")
+			os.Exit(1)
+		}
+		if err = validator.SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OpensearchComponentTemplate")
+			os.Exit(1)
+		}
+	}
+	if err = (&controllers.OpensearchSavedObjectsReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("savedobjects-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchSavedObjects")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
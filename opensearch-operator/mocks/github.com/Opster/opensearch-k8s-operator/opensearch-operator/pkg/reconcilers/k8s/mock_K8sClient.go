@@ -436,6 +436,90 @@ func (_c *MockK8sClient_DeleteJob_Call) RunAndReturn(run func(*batchv1.Job) erro
 	return _c
 }
 
+// DeleteOpensearchComponentTemplate provides a mock function with given fields: template
+func (_m *MockK8sClient) DeleteOpensearchComponentTemplate(template *apiv1.OpensearchComponentTemplate) error {
+	ret := _m.Called(template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*apiv1.OpensearchComponentTemplate) error); ok {
+		r0 = rf(template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockK8sClient_DeleteOpensearchComponentTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteOpensearchComponentTemplate'
+type MockK8sClient_DeleteOpensearchComponentTemplate_Call struct {
+	*mock.Call
+}
+
+// DeleteOpensearchComponentTemplate is a helper method to define mock.On call
+//   - template *apiv1.OpensearchComponentTemplate
+func (_e *MockK8sClient_Expecter) DeleteOpensearchComponentTemplate(template interface{}) *MockK8sClient_DeleteOpensearchComponentTemplate_Call {
+	return &MockK8sClient_DeleteOpensearchComponentTemplate_Call{Call: _e.mock.On("DeleteOpensearchComponentTemplate", template)}
+}
+
+func (_c *MockK8sClient_DeleteOpensearchComponentTemplate_Call) Run(run func(template *apiv1.OpensearchComponentTemplate)) *MockK8sClient_DeleteOpensearchComponentTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*apiv1.OpensearchComponentTemplate))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_DeleteOpensearchComponentTemplate_Call) Return(_a0 error) *MockK8sClient_DeleteOpensearchComponentTemplate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockK8sClient_DeleteOpensearchComponentTemplate_Call) RunAndReturn(run func(*apiv1.OpensearchComponentTemplate) error) *MockK8sClient_DeleteOpensearchComponentTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePVC provides a mock function with given fields: pvc
+func (_m *MockK8sClient) DeletePVC(pvc *v1.PersistentVolumeClaim) error {
+	ret := _m.Called(pvc)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.PersistentVolumeClaim) error); ok {
+		r0 = rf(pvc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockK8sClient_DeletePVC_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePVC'
+type MockK8sClient_DeletePVC_Call struct {
+	*mock.Call
+}
+
+// DeletePVC is a helper method to define mock.On call
+//   - pvc *v1.PersistentVolumeClaim
+func (_e *MockK8sClient_Expecter) DeletePVC(pvc interface{}) *MockK8sClient_DeletePVC_Call {
+	return &MockK8sClient_DeletePVC_Call{Call: _e.mock.On("DeletePVC", pvc)}
+}
+
+func (_c *MockK8sClient_DeletePVC_Call) Run(run func(pvc *v1.PersistentVolumeClaim)) *MockK8sClient_DeletePVC_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*v1.PersistentVolumeClaim))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_DeletePVC_Call) Return(_a0 error) *MockK8sClient_DeletePVC_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockK8sClient_DeletePVC_Call) RunAndReturn(run func(*v1.PersistentVolumeClaim) error) *MockK8sClient_DeletePVC_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeletePod provides a mock function with given fields: pod
 func (_m *MockK8sClient) DeletePod(pod *v1.Pod) error {
 	ret := _m.Called(pod)
@@ -627,6 +711,59 @@ func (_c *MockK8sClient_GetDeployment_Call) RunAndReturn(run func(string, string
 	return _c
 }
 
+// GetEndpoints provides a mock function with given fields: name, namespace
+func (_m *MockK8sClient) GetEndpoints(name string, namespace string) (v1.Endpoints, error) {
+	ret := _m.Called(name, namespace)
+
+	var r0 v1.Endpoints
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (v1.Endpoints, error)); ok {
+		return rf(name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) v1.Endpoints); ok {
+		r0 = rf(name, namespace)
+	} else {
+		r0 = ret.Get(0).(v1.Endpoints)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_GetEndpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEndpoints'
+type MockK8sClient_GetEndpoints_Call struct {
+	*mock.Call
+}
+
+// GetEndpoints is a helper method to define mock.On call
+//   - name string
+//   - namespace string
+func (_e *MockK8sClient_Expecter) GetEndpoints(name interface{}, namespace interface{}) *MockK8sClient_GetEndpoints_Call {
+	return &MockK8sClient_GetEndpoints_Call{Call: _e.mock.On("GetEndpoints", name, namespace)}
+}
+
+func (_c *MockK8sClient_GetEndpoints_Call) Run(run func(name string, namespace string)) *MockK8sClient_GetEndpoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_GetEndpoints_Call) Return(_a0 v1.Endpoints, _a1 error) *MockK8sClient_GetEndpoints_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_GetEndpoints_Call) RunAndReturn(run func(string, string) (v1.Endpoints, error)) *MockK8sClient_GetEndpoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetJob provides a mock function with given fields: name, namespace
 func (_m *MockK8sClient) GetJob(name string, namespace string) (batchv1.Job, error) {
 	ret := _m.Called(name, namespace)
@@ -680,6 +817,58 @@ func (_c *MockK8sClient_GetJob_Call) RunAndReturn(run func(string, string) (batc
 	return _c
 }
 
+// GetNode provides a mock function with given fields: name
+func (_m *MockK8sClient) GetNode(name string) (v1.Node, error) {
+	ret := _m.Called(name)
+
+	var r0 v1.Node
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (v1.Node, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) v1.Node); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(v1.Node)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_GetNode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNode'
+type MockK8sClient_GetNode_Call struct {
+	*mock.Call
+}
+
+// GetNode is a helper method to define mock.On call
+//   - name string
+func (_e *MockK8sClient_Expecter) GetNode(name interface{}) *MockK8sClient_GetNode_Call {
+	return &MockK8sClient_GetNode_Call{Call: _e.mock.On("GetNode", name)}
+}
+
+func (_c *MockK8sClient_GetNode_Call) Run(run func(name string)) *MockK8sClient_GetNode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_GetNode_Call) Return(_a0 v1.Node, _a1 error) *MockK8sClient_GetNode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_GetNode_Call) RunAndReturn(run func(string) (v1.Node, error)) *MockK8sClient_GetNode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetOpenSearchCluster provides a mock function with given fields: name, namespace
 func (_m *MockK8sClient) GetOpenSearchCluster(name string, namespace string) (apiv1.OpenSearchCluster, error) {
 	ret := _m.Called(name, namespace)
@@ -733,6 +922,218 @@ func (_c *MockK8sClient_GetOpenSearchCluster_Call) RunAndReturn(run func(string,
 	return _c
 }
 
+// GetOpensearchReconcileLog provides a mock function with given fields: name, namespace
+func (_m *MockK8sClient) GetOpensearchReconcileLog(name string, namespace string) (apiv1.OpensearchReconcileLog, error) {
+	ret := _m.Called(name, namespace)
+
+	var r0 apiv1.OpensearchReconcileLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (apiv1.OpensearchReconcileLog, error)); ok {
+		return rf(name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) apiv1.OpensearchReconcileLog); ok {
+		r0 = rf(name, namespace)
+	} else {
+		r0 = ret.Get(0).(apiv1.OpensearchReconcileLog)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_GetOpensearchReconcileLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOpensearchReconcileLog'
+type MockK8sClient_GetOpensearchReconcileLog_Call struct {
+	*mock.Call
+}
+
+// GetOpensearchReconcileLog is a helper method to define mock.On call
+//   - name string
+//   - namespace string
+func (_e *MockK8sClient_Expecter) GetOpensearchReconcileLog(name interface{}, namespace interface{}) *MockK8sClient_GetOpensearchReconcileLog_Call {
+	return &MockK8sClient_GetOpensearchReconcileLog_Call{Call: _e.mock.On("GetOpensearchReconcileLog", name, namespace)}
+}
+
+func (_c *MockK8sClient_GetOpensearchReconcileLog_Call) Run(run func(name string, namespace string)) *MockK8sClient_GetOpensearchReconcileLog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_GetOpensearchReconcileLog_Call) Return(_a0 apiv1.OpensearchReconcileLog, _a1 error) *MockK8sClient_GetOpensearchReconcileLog_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_GetOpensearchReconcileLog_Call) RunAndReturn(run func(string, string) (apiv1.OpensearchReconcileLog, error)) *MockK8sClient_GetOpensearchReconcileLog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOpensearchRole provides a mock function with given fields: name, namespace
+func (_m *MockK8sClient) GetOpensearchRole(name string, namespace string) (apiv1.OpensearchRole, error) {
+	ret := _m.Called(name, namespace)
+
+	var r0 apiv1.OpensearchRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (apiv1.OpensearchRole, error)); ok {
+		return rf(name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) apiv1.OpensearchRole); ok {
+		r0 = rf(name, namespace)
+	} else {
+		r0 = ret.Get(0).(apiv1.OpensearchRole)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_GetOpensearchRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOpensearchRole'
+type MockK8sClient_GetOpensearchRole_Call struct {
+	*mock.Call
+}
+
+// GetOpensearchRole is a helper method to define mock.On call
+//   - name string
+//   - namespace string
+func (_e *MockK8sClient_Expecter) GetOpensearchRole(name interface{}, namespace interface{}) *MockK8sClient_GetOpensearchRole_Call {
+	return &MockK8sClient_GetOpensearchRole_Call{Call: _e.mock.On("GetOpensearchRole", name, namespace)}
+}
+
+func (_c *MockK8sClient_GetOpensearchRole_Call) Run(run func(name string, namespace string)) *MockK8sClient_GetOpensearchRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_GetOpensearchRole_Call) Return(_a0 apiv1.OpensearchRole, _a1 error) *MockK8sClient_GetOpensearchRole_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_GetOpensearchRole_Call) RunAndReturn(run func(string, string) (apiv1.OpensearchRole, error)) *MockK8sClient_GetOpensearchRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOpensearchSecurityAnalyticsRule provides a mock function with given fields: name, namespace
+func (_m *MockK8sClient) GetOpensearchSecurityAnalyticsRule(name string, namespace string) (apiv1.OpensearchSecurityAnalyticsRule, error) {
+	ret := _m.Called(name, namespace)
+
+	var r0 apiv1.OpensearchSecurityAnalyticsRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (apiv1.OpensearchSecurityAnalyticsRule, error)); ok {
+		return rf(name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) apiv1.OpensearchSecurityAnalyticsRule); ok {
+		r0 = rf(name, namespace)
+	} else {
+		r0 = ret.Get(0).(apiv1.OpensearchSecurityAnalyticsRule)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOpensearchSecurityAnalyticsRule'
+type MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call struct {
+	*mock.Call
+}
+
+// GetOpensearchSecurityAnalyticsRule is a helper method to define mock.On call
+//   - name string
+//   - namespace string
+func (_e *MockK8sClient_Expecter) GetOpensearchSecurityAnalyticsRule(name interface{}, namespace interface{}) *MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call {
+	return &MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call{Call: _e.mock.On("GetOpensearchSecurityAnalyticsRule", name, namespace)}
+}
+
+func (_c *MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call) Run(run func(name string, namespace string)) *MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call) Return(_a0 apiv1.OpensearchSecurityAnalyticsRule, _a1 error) *MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call) RunAndReturn(run func(string, string) (apiv1.OpensearchSecurityAnalyticsRule, error)) *MockK8sClient_GetOpensearchSecurityAnalyticsRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOpensearchTenant provides a mock function with given fields: name, namespace
+func (_m *MockK8sClient) GetOpensearchTenant(name string, namespace string) (apiv1.OpensearchTenant, error) {
+	ret := _m.Called(name, namespace)
+
+	var r0 apiv1.OpensearchTenant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (apiv1.OpensearchTenant, error)); ok {
+		return rf(name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) apiv1.OpensearchTenant); ok {
+		r0 = rf(name, namespace)
+	} else {
+		r0 = ret.Get(0).(apiv1.OpensearchTenant)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_GetOpensearchTenant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOpensearchTenant'
+type MockK8sClient_GetOpensearchTenant_Call struct {
+	*mock.Call
+}
+
+// GetOpensearchTenant is a helper method to define mock.On call
+//   - name string
+//   - namespace string
+func (_e *MockK8sClient_Expecter) GetOpensearchTenant(name interface{}, namespace interface{}) *MockK8sClient_GetOpensearchTenant_Call {
+	return &MockK8sClient_GetOpensearchTenant_Call{Call: _e.mock.On("GetOpensearchTenant", name, namespace)}
+}
+
+func (_c *MockK8sClient_GetOpensearchTenant_Call) Run(run func(name string, namespace string)) *MockK8sClient_GetOpensearchTenant_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_GetOpensearchTenant_Call) Return(_a0 apiv1.OpensearchTenant, _a1 error) *MockK8sClient_GetOpensearchTenant_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_GetOpensearchTenant_Call) RunAndReturn(run func(string, string) (apiv1.OpensearchTenant, error)) *MockK8sClient_GetOpensearchTenant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetPVC provides a mock function with given fields: name, namespace
 func (_m *MockK8sClient) GetPVC(name string, namespace string) (v1.PersistentVolumeClaim, error) {
 	ret := _m.Called(name, namespace)
@@ -998,6 +1399,201 @@ func (_c *MockK8sClient_GetStatefulSet_Call) RunAndReturn(run func(string, strin
 	return _c
 }
 
+// ListOpenSearchClusters provides a mock function with given fields: listOptions
+func (_m *MockK8sClient) ListOpenSearchClusters(listOptions ...client.ListOption) (apiv1.OpenSearchClusterList, error) {
+	_va := make([]interface{}, len(listOptions))
+	for _i := range listOptions {
+		_va[_i] = listOptions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 apiv1.OpenSearchClusterList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(...client.ListOption) (apiv1.OpenSearchClusterList, error)); ok {
+		return rf(listOptions...)
+	}
+	if rf, ok := ret.Get(0).(func(...client.ListOption) apiv1.OpenSearchClusterList); ok {
+		r0 = rf(listOptions...)
+	} else {
+		r0 = ret.Get(0).(apiv1.OpenSearchClusterList)
+	}
+
+	if rf, ok := ret.Get(1).(func(...client.ListOption) error); ok {
+		r1 = rf(listOptions...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_ListOpenSearchClusters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOpenSearchClusters'
+type MockK8sClient_ListOpenSearchClusters_Call struct {
+	*mock.Call
+}
+
+// ListOpenSearchClusters is a helper method to define mock.On call
+//   - listOptions ...client.ListOption
+func (_e *MockK8sClient_Expecter) ListOpenSearchClusters(listOptions ...interface{}) *MockK8sClient_ListOpenSearchClusters_Call {
+	return &MockK8sClient_ListOpenSearchClusters_Call{Call: _e.mock.On("ListOpenSearchClusters",
+		append([]interface{}{}, listOptions...)...)}
+}
+
+func (_c *MockK8sClient_ListOpenSearchClusters_Call) Run(run func(listOptions ...client.ListOption)) *MockK8sClient_ListOpenSearchClusters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]client.ListOption, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(client.ListOption)
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_ListOpenSearchClusters_Call) Return(_a0 apiv1.OpenSearchClusterList, _a1 error) *MockK8sClient_ListOpenSearchClusters_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_ListOpenSearchClusters_Call) RunAndReturn(run func(...client.ListOption) (apiv1.OpenSearchClusterList, error)) *MockK8sClient_ListOpenSearchClusters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOpensearchComponentTemplates provides a mock function with given fields: listOptions
+func (_m *MockK8sClient) ListOpensearchComponentTemplates(listOptions ...client.ListOption) (apiv1.OpensearchComponentTemplateList, error) {
+	_va := make([]interface{}, len(listOptions))
+	for _i := range listOptions {
+		_va[_i] = listOptions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 apiv1.OpensearchComponentTemplateList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(...client.ListOption) (apiv1.OpensearchComponentTemplateList, error)); ok {
+		return rf(listOptions...)
+	}
+	if rf, ok := ret.Get(0).(func(...client.ListOption) apiv1.OpensearchComponentTemplateList); ok {
+		r0 = rf(listOptions...)
+	} else {
+		r0 = ret.Get(0).(apiv1.OpensearchComponentTemplateList)
+	}
+
+	if rf, ok := ret.Get(1).(func(...client.ListOption) error); ok {
+		r1 = rf(listOptions...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_ListOpensearchComponentTemplates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOpensearchComponentTemplates'
+type MockK8sClient_ListOpensearchComponentTemplates_Call struct {
+	*mock.Call
+}
+
+// ListOpensearchComponentTemplates is a helper method to define mock.On call
+//   - listOptions ...client.ListOption
+func (_e *MockK8sClient_Expecter) ListOpensearchComponentTemplates(listOptions ...interface{}) *MockK8sClient_ListOpensearchComponentTemplates_Call {
+	return &MockK8sClient_ListOpensearchComponentTemplates_Call{Call: _e.mock.On("ListOpensearchComponentTemplates",
+		append([]interface{}{}, listOptions...)...)}
+}
+
+func (_c *MockK8sClient_ListOpensearchComponentTemplates_Call) Run(run func(listOptions ...client.ListOption)) *MockK8sClient_ListOpensearchComponentTemplates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]client.ListOption, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(client.ListOption)
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_ListOpensearchComponentTemplates_Call) Return(_a0 apiv1.OpensearchComponentTemplateList, _a1 error) *MockK8sClient_ListOpensearchComponentTemplates_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_ListOpensearchComponentTemplates_Call) RunAndReturn(run func(...client.ListOption) (apiv1.OpensearchComponentTemplateList, error)) *MockK8sClient_ListOpensearchComponentTemplates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOpensearchIndexTemplates provides a mock function with given fields: listOptions
+func (_m *MockK8sClient) ListOpensearchIndexTemplates(listOptions ...client.ListOption) (apiv1.OpensearchIndexTemplateList, error) {
+	_va := make([]interface{}, len(listOptions))
+	for _i := range listOptions {
+		_va[_i] = listOptions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 apiv1.OpensearchIndexTemplateList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(...client.ListOption) (apiv1.OpensearchIndexTemplateList, error)); ok {
+		return rf(listOptions...)
+	}
+	if rf, ok := ret.Get(0).(func(...client.ListOption) apiv1.OpensearchIndexTemplateList); ok {
+		r0 = rf(listOptions...)
+	} else {
+		r0 = ret.Get(0).(apiv1.OpensearchIndexTemplateList)
+	}
+
+	if rf, ok := ret.Get(1).(func(...client.ListOption) error); ok {
+		r1 = rf(listOptions...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockK8sClient_ListOpensearchIndexTemplates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOpensearchIndexTemplates'
+type MockK8sClient_ListOpensearchIndexTemplates_Call struct {
+	*mock.Call
+}
+
+// ListOpensearchIndexTemplates is a helper method to define mock.On call
+//   - listOptions ...client.ListOption
+func (_e *MockK8sClient_Expecter) ListOpensearchIndexTemplates(listOptions ...interface{}) *MockK8sClient_ListOpensearchIndexTemplates_Call {
+	return &MockK8sClient_ListOpensearchIndexTemplates_Call{Call: _e.mock.On("ListOpensearchIndexTemplates",
+		append([]interface{}{}, listOptions...)...)}
+}
+
+func (_c *MockK8sClient_ListOpensearchIndexTemplates_Call) Run(run func(listOptions ...client.ListOption)) *MockK8sClient_ListOpensearchIndexTemplates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]client.ListOption, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(client.ListOption)
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_ListOpensearchIndexTemplates_Call) Return(_a0 apiv1.OpensearchIndexTemplateList, _a1 error) *MockK8sClient_ListOpensearchIndexTemplates_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockK8sClient_ListOpensearchIndexTemplates_Call) RunAndReturn(run func(...client.ListOption) (apiv1.OpensearchIndexTemplateList, error)) *MockK8sClient_ListOpensearchIndexTemplates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListPVCs provides a mock function with given fields: listOptions
 func (_m *MockK8sClient) ListPVCs(listOptions *client.ListOptions) (v1.PersistentVolumeClaimList, error) {
 	ret := _m.Called(listOptions)
@@ -1393,6 +1989,48 @@ func (_c *MockK8sClient_UpdatePVC_Call) RunAndReturn(run func(*v1.PersistentVolu
 	return _c
 }
 
+// UpdatePod provides a mock function with given fields: pod
+func (_m *MockK8sClient) UpdatePod(pod *v1.Pod) error {
+	ret := _m.Called(pod)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.Pod) error); ok {
+		r0 = rf(pod)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockK8sClient_UpdatePod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePod'
+type MockK8sClient_UpdatePod_Call struct {
+	*mock.Call
+}
+
+// UpdatePod is a helper method to define mock.On call
+//   - pod *v1.Pod
+func (_e *MockK8sClient_Expecter) UpdatePod(pod interface{}) *MockK8sClient_UpdatePod_Call {
+	return &MockK8sClient_UpdatePod_Call{Call: _e.mock.On("UpdatePod", pod)}
+}
+
+func (_c *MockK8sClient_UpdatePod_Call) Run(run func(pod *v1.Pod)) *MockK8sClient_UpdatePod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*v1.Pod))
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_UpdatePod_Call) Return(_a0 error) *MockK8sClient_UpdatePod_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockK8sClient_UpdatePod_Call) RunAndReturn(run func(*v1.Pod) error) *MockK8sClient_UpdatePod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockK8sClient creates a new instance of MockK8sClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockK8sClient(t interface {
@@ -0,0 +1,32 @@
+package requests
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// Monitor is a monitor of the Alerting plugin
+type Monitor struct {
+	Type        string                 `json:"type"`
+	MonitorType string                 `json:"monitor_type"`
+	Name        string                 `json:"name"`
+	Enabled     bool                   `json:"enabled"`
+	Schedule    MonitorSchedule        `json:"schedule"`
+	Inputs      []apiextensionsv1.JSON `json:"inputs"`
+	Triggers    []apiextensionsv1.JSON `json:"triggers"`
+}
+
+type MonitorSchedule struct {
+	Period *MonitorPeriod `json:"period,omitempty"`
+	Cron   *CronSchedule  `json:"cron,omitempty"`
+}
+
+type MonitorPeriod struct {
+	Interval int    `json:"interval"`
+	Unit     string `json:"unit"`
+}
+
+// MonitorTrigger is the body of a trigger, wrapped in a key depending on the monitor type
+type MonitorTrigger struct {
+	Name      string                 `json:"name"`
+	Severity  string                 `json:"severity"`
+	Condition apiextensionsv1.JSON   `json:"condition"`
+	Actions   []apiextensionsv1.JSON `json:"actions"`
+}
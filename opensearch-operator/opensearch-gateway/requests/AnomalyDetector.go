@@ -0,0 +1,33 @@
+package requests
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// AnomalyDetector is a detector of the Anomaly Detection plugin
+type AnomalyDetector struct {
+	Name              string                `json:"name"`
+	Description       string                `json:"description,omitempty"`
+	TimeField         string                `json:"time_field"`
+	Indices           []string              `json:"indices"`
+	FeatureAttributes []DetectorFeature     `json:"feature_attributes"`
+	FilterQuery       *apiextensionsv1.JSON `json:"filter_query,omitempty"`
+	DetectionInterval DetectorInterval      `json:"detection_interval"`
+	WindowDelay       *DetectorInterval     `json:"window_delay,omitempty"`
+	CategoryField     []string              `json:"category_field,omitempty"`
+	ShingleSize       int                   `json:"shingle_size"`
+	ResultIndex       string                `json:"result_index,omitempty"`
+}
+
+type DetectorFeature struct {
+	FeatureName      string               `json:"feature_name"`
+	FeatureEnabled   bool                 `json:"feature_enabled"`
+	AggregationQuery apiextensionsv1.JSON `json:"aggregation_query"`
+}
+
+type DetectorInterval struct {
+	Period DetectorPeriod `json:"period"`
+}
+
+type DetectorPeriod struct {
+	Interval int    `json:"interval"`
+	Unit     string `json:"unit"`
+}
@@ -0,0 +1,11 @@
+package requests
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+type IngestPipeline struct {
+	Description string                 `json:"description,omitempty"`
+	Processors  []apiextensionsv1.JSON `json:"processors"`
+	OnFailure   []apiextensionsv1.JSON `json:"on_failure,omitempty"`
+	Version     int                    `json:"version,omitempty"`
+	Meta        *apiextensionsv1.JSON  `json:"_meta,omitempty"`
+}
@@ -0,0 +1,44 @@
+package requests
+
+// NotificationChannel is the body of a request to create a channel of the Notifications plugin, the config id is
+// omitted when a channel is updated
+type NotificationChannel struct {
+	ConfigID string                    `json:"config_id,omitempty"`
+	Config   NotificationChannelConfig `json:"config"`
+}
+
+type NotificationChannelConfig struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	ConfigType  string               `json:"config_type"`
+	IsEnabled   bool                 `json:"is_enabled"`
+	Slack       *NotificationSlack   `json:"slack,omitempty"`
+	Webhook     *NotificationWebhook `json:"webhook,omitempty"`
+	SNS         *NotificationSNS     `json:"sns,omitempty"`
+	Email       *NotificationEmail   `json:"email,omitempty"`
+}
+
+type NotificationSlack struct {
+	URL string `json:"url"`
+}
+
+type NotificationWebhook struct {
+	URL          string            `json:"url"`
+	Method       string            `json:"method"`
+	HeaderParams map[string]string `json:"header_params,omitempty"`
+}
+
+type NotificationSNS struct {
+	TopicARN string `json:"topic_arn"`
+	RoleARN  string `json:"role_arn,omitempty"`
+}
+
+type NotificationEmail struct {
+	EmailAccountID   string                       `json:"email_account_id"`
+	RecipientList    []NotificationEmailRecipient `json:"recipient_list"`
+	EmailGroupIDList []string                     `json:"email_group_id_list"`
+}
+
+type NotificationEmailRecipient struct {
+	Recipient string `json:"recipient"`
+}
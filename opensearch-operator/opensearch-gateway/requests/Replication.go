@@ -0,0 +1,15 @@
+package requests
+
+// AutoFollowRule is a rule of the Cross-Cluster Replication plugin that replicates the leader indices matching its
+// pattern. Rules are deleted by their leader alias and name only
+type AutoFollowRule struct {
+	LeaderAlias string            `json:"leader_alias"`
+	Name        string            `json:"name"`
+	Pattern     string            `json:"pattern,omitempty"`
+	UseRoles    *ReplicationRoles `json:"use_roles,omitempty"`
+}
+
+type ReplicationRoles struct {
+	LeaderClusterRole   string `json:"leader_cluster_role"`
+	FollowerClusterRole string `json:"follower_cluster_role"`
+}
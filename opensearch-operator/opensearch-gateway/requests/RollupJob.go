@@ -0,0 +1,64 @@
+package requests
+
+import "encoding/json"
+
+// RollupJob is the body of the requests creating or updating a job of the Index Rollups plugin
+type RollupJob struct {
+	Rollup IndexRollup `json:"rollup"`
+}
+
+// IndexRollup is a job of the Index Rollups plugin
+type IndexRollup struct {
+	Enabled     bool              `json:"enabled"`
+	Schedule    RollupSchedule    `json:"schedule"`
+	Description string            `json:"description,omitempty"`
+	SourceIndex string            `json:"source_index"`
+	TargetIndex string            `json:"target_index"`
+	PageSize    int               `json:"page_size"`
+	Delay       *int64            `json:"delay,omitempty"`
+	Continuous  bool              `json:"continuous"`
+	Dimensions  []RollupDimension `json:"dimensions"`
+	Metrics     []RollupMetric    `json:"metrics"`
+}
+
+type RollupSchedule struct {
+	Interval *RollupInterval `json:"interval,omitempty"`
+	Cron     *CronSchedule   `json:"cron,omitempty"`
+}
+
+// RollupInterval is an interval schedule of the job scheduler, the start time is in epoch milliseconds
+type RollupInterval struct {
+	StartTime *int64 `json:"start_time,omitempty"`
+	Period    int    `json:"period"`
+	Unit      string `json:"unit"`
+}
+
+// RollupDimension holds exactly one of its fields
+type RollupDimension struct {
+	DateHistogram *RollupDateHistogram `json:"date_histogram,omitempty"`
+	Terms         *RollupTerms         `json:"terms,omitempty"`
+	Histogram     *RollupHistogram     `json:"histogram,omitempty"`
+}
+
+type RollupDateHistogram struct {
+	SourceField      string `json:"source_field"`
+	FixedInterval    string `json:"fixed_interval,omitempty"`
+	CalendarInterval string `json:"calendar_interval,omitempty"`
+	Timezone         string `json:"timezone"`
+}
+
+type RollupTerms struct {
+	SourceField string `json:"source_field"`
+}
+
+type RollupHistogram struct {
+	SourceField string      `json:"source_field"`
+	Interval    json.Number `json:"interval"`
+}
+
+// RollupMetric lists the aggregations of a field, each one is an object with the aggregation as its only key, e.g.
+// {"avg":{}}
+type RollupMetric struct {
+	SourceField string                         `json:"source_field"`
+	Metrics     []map[string]map[string]string `json:"metrics"`
+}
@@ -0,0 +1,11 @@
+package requests
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+type SearchPipeline struct {
+	Description            string                 `json:"description,omitempty"`
+	RequestProcessors      []apiextensionsv1.JSON `json:"request_processors,omitempty"`
+	ResponseProcessors     []apiextensionsv1.JSON `json:"response_processors,omitempty"`
+	PhaseResultsProcessors []apiextensionsv1.JSON `json:"phase_results_processors,omitempty"`
+	Version                int                    `json:"version,omitempty"`
+}
@@ -0,0 +1,29 @@
+package requests
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// SecurityAnalyticsDetector is a threat detector of the Security Analytics plugin
+type SecurityAnalyticsDetector struct {
+	Type         string                           `json:"type"`
+	Name         string                           `json:"name"`
+	DetectorType string                           `json:"detector_type"`
+	Enabled      bool                             `json:"enabled"`
+	Schedule     DetectorInterval                 `json:"schedule"`
+	Inputs       []SecurityAnalyticsDetectorInput `json:"inputs"`
+	Triggers     []apiextensionsv1.JSON           `json:"triggers"`
+}
+
+type SecurityAnalyticsDetectorInput struct {
+	DetectorInput SecurityAnalyticsInput `json:"detector_input"`
+}
+
+type SecurityAnalyticsInput struct {
+	Description      string                    `json:"description"`
+	Indices          []string                  `json:"indices"`
+	CustomRules      []SecurityAnalyticsRuleID `json:"custom_rules"`
+	PrePackagedRules []SecurityAnalyticsRuleID `json:"pre_packaged_rules"`
+}
+
+type SecurityAnalyticsRuleID struct {
+	ID string `json:"id"`
+}
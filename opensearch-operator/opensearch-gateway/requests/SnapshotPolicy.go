@@ -0,0 +1,66 @@
+package requests
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// SnapshotPolicy is a policy of the Snapshot Management plugin
+type SnapshotPolicy struct {
+	Description    string                `json:"description,omitempty"`
+	Enabled        bool                  `json:"enabled"`
+	Creation       SnapshotCreation      `json:"creation"`
+	Deletion       *SnapshotDeletion     `json:"deletion,omitempty"`
+	SnapshotConfig SnapshotConfig        `json:"snapshot_config"`
+	Notification   *SnapshotNotification `json:"notification,omitempty"`
+}
+
+type SnapshotSchedule struct {
+	Cron CronSchedule `json:"cron"`
+}
+
+type CronSchedule struct {
+	Expression string `json:"expression"`
+	Timezone   string `json:"timezone"`
+}
+
+type SnapshotCreation struct {
+	Schedule  SnapshotSchedule `json:"schedule"`
+	TimeLimit string           `json:"time_limit,omitempty"`
+}
+
+type SnapshotDeletion struct {
+	Schedule  *SnapshotSchedule       `json:"schedule,omitempty"`
+	Condition SnapshotDeleteCondition `json:"condition"`
+	TimeLimit string                  `json:"time_limit,omitempty"`
+}
+
+type SnapshotDeleteCondition struct {
+	MaxAge   string `json:"max_age,omitempty"`
+	MaxCount *int   `json:"max_count,omitempty"`
+	MinCount *int   `json:"min_count,omitempty"`
+}
+
+type SnapshotConfig struct {
+	Repository         string                `json:"repository"`
+	Indices            string                `json:"indices,omitempty"`
+	DateFormat         string                `json:"date_format,omitempty"`
+	Timezone           string                `json:"timezone,omitempty"`
+	IgnoreUnavailable  bool                  `json:"ignore_unavailable,omitempty"`
+	IncludeGlobalState *bool                 `json:"include_global_state,omitempty"`
+	Partial            bool                  `json:"partial,omitempty"`
+	Metadata           *apiextensionsv1.JSON `json:"metadata,omitempty"`
+}
+
+type SnapshotNotification struct {
+	Channel    SnapshotNotificationChannel    `json:"channel"`
+	Conditions SnapshotNotificationConditions `json:"conditions"`
+}
+
+type SnapshotNotificationChannel struct {
+	ID string `json:"id"`
+}
+
+type SnapshotNotificationConditions struct {
+	Creation          bool `json:"creation"`
+	Deletion          bool `json:"deletion"`
+	Failure           bool `json:"failure"`
+	TimeLimitExceeded bool `json:"time_limit_exceeded"`
+}
@@ -0,0 +1,8 @@
+package requests
+
+// SnapshotRepository is the body of a request to register a snapshot repository. OpenSearch returns all settings as
+// strings, so they are sent as strings as well to be comparable
+type SnapshotRepository struct {
+	Type     string            `json:"type"`
+	Settings map[string]string `json:"settings"`
+}
@@ -0,0 +1,20 @@
+package requests
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// StoredScriptRequest is the body of _scripts/<id>
+type StoredScriptRequest struct {
+	Script StoredScript `json:"script"`
+}
+
+type StoredScript struct {
+	Lang    string            `json:"lang"`
+	Source  string            `json:"source"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// RenderTemplate is the body of _render/template
+type RenderTemplate struct {
+	Source string                `json:"source"`
+	Params *apiextensionsv1.JSON `json:"params,omitempty"`
+}
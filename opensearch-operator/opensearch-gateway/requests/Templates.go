@@ -9,6 +9,15 @@ type IndexTemplate struct {
 	Priority      int                   `json:"priority,omitempty"`
 	Version       int                   `json:"version,omitempty"`
 	Meta          *apiextensionsv1.JSON `json:"_meta,omitempty"`
+	DataStream    *DataStream           `json:"data_stream,omitempty"`
+}
+
+type DataStream struct {
+	TimestampField *DataStreamTimestampField `json:"timestamp_field,omitempty"`
+}
+
+type DataStreamTimestampField struct {
+	Name string `json:"name"`
 }
 
 type ComponentTemplate struct {
@@ -25,9 +34,11 @@ type Index struct {
 }
 
 type IndexAlias struct {
-	Index        string                `json:"index,omitempty"`
-	Alias        string                `json:"alias,omitempty"`
-	Filter       *apiextensionsv1.JSON `json:"filter,omitempty"`
-	Routing      string                `json:"routing,omitempty"`
-	IsWriteIndex bool                  `json:"is_write_index,omitempty"`
+	Index         string                `json:"index,omitempty"`
+	Alias         string                `json:"alias,omitempty"`
+	Filter        *apiextensionsv1.JSON `json:"filter,omitempty"`
+	Routing       string                `json:"routing,omitempty"`
+	IndexRouting  string                `json:"index_routing,omitempty"`
+	SearchRouting string                `json:"search_routing,omitempty"`
+	IsWriteIndex  bool                  `json:"is_write_index,omitempty"`
 }
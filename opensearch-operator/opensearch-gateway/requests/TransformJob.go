@@ -0,0 +1,44 @@
+package requests
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// TransformJob is the body of the requests creating or updating a job of the Index Transforms plugin
+type TransformJob struct {
+	Transform IndexTransform `json:"transform"`
+}
+
+// IndexTransform is a job of the Index Transforms plugin
+type IndexTransform struct {
+	Enabled            bool                  `json:"enabled"`
+	Schedule           RollupSchedule        `json:"schedule"`
+	Description        string                `json:"description,omitempty"`
+	SourceIndex        string                `json:"source_index"`
+	TargetIndex        string                `json:"target_index"`
+	DataSelectionQuery *apiextensionsv1.JSON `json:"data_selection_query,omitempty"`
+	PageSize           int                   `json:"page_size"`
+	Continuous         bool                  `json:"continuous"`
+	Groups             []TransformGroup      `json:"groups"`
+	Aggregations       *apiextensionsv1.JSON `json:"aggregations,omitempty"`
+}
+
+// TransformGroup holds exactly one of its fields
+type TransformGroup struct {
+	DateHistogram *TransformDateHistogram `json:"date_histogram,omitempty"`
+	Terms         *TransformTerms         `json:"terms,omitempty"`
+	Histogram     *TransformHistogram     `json:"histogram,omitempty"`
+}
+
+type TransformDateHistogram struct {
+	RollupDateHistogram
+	TargetField string `json:"target_field,omitempty"`
+}
+
+type TransformTerms struct {
+	RollupTerms
+	TargetField string `json:"target_field,omitempty"`
+}
+
+type TransformHistogram struct {
+	RollupHistogram
+	TargetField string `json:"target_field,omitempty"`
+}
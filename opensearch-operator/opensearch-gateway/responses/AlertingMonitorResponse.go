@@ -0,0 +1,24 @@
+package responses
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// GetMonitorResponse is returned when a monitor is fetched, created or updated. The monitor holds fields OpenSearch
+// adds, such as the ids of the triggers, so it is kept as raw JSON
+type GetMonitorResponse struct {
+	ID          string               `json:"_id"`
+	SeqNo       int                  `json:"_seq_no"`
+	PrimaryTerm int                  `json:"_primary_term"`
+	Monitor     apiextensionsv1.JSON `json:"monitor"`
+}
+
+// SearchMonitorsResponse is returned when monitors are searched
+type SearchMonitorsResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string `json:"_id"`
+			Source struct {
+				Name string `json:"name"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
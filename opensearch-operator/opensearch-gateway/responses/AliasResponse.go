@@ -0,0 +1,17 @@
+package responses
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// IndexAliasesResponse is an index with its aliases as returned by the _alias API
+type IndexAliasesResponse struct {
+	Aliases map[string]AliasResponse `json:"aliases"`
+}
+
+type AliasResponse struct {
+	IsWriteIndex *bool `json:"is_write_index,omitempty"`
+}
+
+// IndexAliasDefinitionsResponse is an index with the complete definitions of its aliases as returned by the _alias API
+type IndexAliasDefinitionsResponse struct {
+	Aliases map[string]*apiextensionsv1.JSON `json:"aliases"`
+}
@@ -0,0 +1,36 @@
+package responses
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// GetDetectorResponse is returned when a detector is fetched or created. The detector holds fields OpenSearch adds,
+// such as the ids of the features, so it is kept as raw JSON. The job is only returned once the detector was started
+type GetDetectorResponse struct {
+	ID          string               `json:"_id"`
+	SeqNo       int                  `json:"_seq_no"`
+	PrimaryTerm int                  `json:"_primary_term"`
+	Detector    apiextensionsv1.JSON `json:"anomaly_detector"`
+	Job         *struct {
+		Enabled bool `json:"enabled"`
+	} `json:"anomaly_detector_job,omitempty"`
+}
+
+// SearchDetectorsResponse is returned when detectors are searched
+type SearchDetectorsResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string `json:"_id"`
+			Source struct {
+				Name string `json:"name"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// DetectorProfileResponse is the state of the job of a detector
+type DetectorProfileResponse struct {
+	State        string `json:"state"`
+	Error        string `json:"error"`
+	InitProgress *struct {
+		Percentage string `json:"percentage"`
+	} `json:"init_progress"`
+}
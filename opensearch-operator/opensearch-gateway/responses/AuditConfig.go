@@ -0,0 +1,8 @@
+package responses
+
+// GetAuditConfigResponse is the audit configuration returned by _plugins/_security/api/audit
+type GetAuditConfigResponse struct {
+	// JSON pointers of the settings that cannot be changed through the API, e.g. /compliance/internal_config
+	ReadOnly []string               `json:"_readonly,omitempty"`
+	Config   map[string]interface{} `json:"config"`
+}
@@ -0,0 +1,7 @@
+package responses
+
+type CatNodeAttrsResponse struct {
+	Node  string `json:"node"`
+	Attr  string `json:"attr"`
+	Value string `json:"value"`
+}
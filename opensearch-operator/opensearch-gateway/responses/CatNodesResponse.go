@@ -11,4 +11,5 @@ type CatNodesResponse struct {
 	NodeRole    string `json:"node.role"`
 	Master      string `json:"master"`
 	Name        string `json:"name"`
+	Version     string `json:"version"`
 }
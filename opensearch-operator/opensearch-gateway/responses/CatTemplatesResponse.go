@@ -0,0 +1,9 @@
+package responses
+
+type CatTemplatesResponse struct {
+	Name          string `json:"name"`
+	IndexPatterns string `json:"index_patterns"`
+	Order         string `json:"order"`
+	Version       string `json:"version"`
+	ComposedOf    string `json:"composed_of"`
+}
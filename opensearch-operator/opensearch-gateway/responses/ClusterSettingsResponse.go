@@ -14,3 +14,11 @@ type Settings struct {
 	ClusterRoutingAllocationEnable  string `json:"cluster.routing.allocation.enable,omitempty"`
 	ClusterRoutingAllocationExclude string `json:"cluster.routing.allocation.exclude._name,omitempty"`
 }
+
+// ClusterSettingsWithDefaultsResponse is the response of the _cluster/settings API with flat_settings and
+// include_defaults set
+type ClusterSettingsWithDefaultsResponse struct {
+	Persistent map[string]interface{} `json:"persistent,omitempty"`
+	Transient  map[string]interface{} `json:"transient,omitempty"`
+	Defaults   map[string]interface{} `json:"defaults,omitempty"`
+}
@@ -0,0 +1,13 @@
+package responses
+
+// ErrorResponse is the body OpenSearch returns for a failed request
+type ErrorResponse struct {
+	Error ErrorCause `json:"error"`
+	// Status is the HTTP status code of the response
+	Status int `json:"status"`
+}
+
+type ErrorCause struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
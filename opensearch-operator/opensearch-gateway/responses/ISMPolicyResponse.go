@@ -3,3 +3,16 @@ package responses
 import "github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
 
 type GetISMPoliciesResponse requests.Policy
+
+// ISMChangeIndicesResponse is the response of the add and remove policy APIs
+type ISMChangeIndicesResponse struct {
+	UpdatedIndices int                  `json:"updated_indices"`
+	Failures       bool                 `json:"failures"`
+	FailedIndices  []ISMFailedIndexInfo `json:"failed_indices"`
+}
+
+type ISMFailedIndexInfo struct {
+	IndexName string `json:"index_name"`
+	IndexUUID string `json:"index_uuid"`
+	Reason    string `json:"reason"`
+}
@@ -0,0 +1,22 @@
+package responses
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// GetIndexSettingsResponse holds the flat settings of the requested indices by index name
+type GetIndexSettingsResponse map[string]IndexSettingsResponse
+
+type IndexSettingsResponse struct {
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// GetIndexMappingsResponse holds the mappings of the requested indices by index name
+type GetIndexMappingsResponse map[string]IndexMappingsResponse
+
+type IndexMappingsResponse struct {
+	Mappings *apiextensionsv1.JSON `json:"mappings"`
+}
+
+// CountResponse is returned by the _count API
+type CountResponse struct {
+	Count int64 `json:"count"`
+}
@@ -0,0 +1,6 @@
+package responses
+
+import "github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+
+// GetIngestPipelinesResponse are the ingest pipelines returned by _ingest/pipeline, keyed by their name
+type GetIngestPipelinesResponse map[string]requests.IngestPipeline
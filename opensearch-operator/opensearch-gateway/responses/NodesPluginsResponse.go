@@ -0,0 +1,15 @@
+package responses
+
+type NodesPluginsResponse struct {
+	Nodes map[string]NodePluginsResponse `json:"nodes"`
+}
+
+type NodePluginsResponse struct {
+	Name    string               `json:"name"`
+	Plugins []NodePluginResponse `json:"plugins"`
+}
+
+type NodePluginResponse struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
@@ -0,0 +1,12 @@
+package responses
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// GetNotificationChannelResponse is returned when a channel of the Notifications plugin is fetched. The config holds
+// fields OpenSearch adds, so it is kept as raw JSON
+type GetNotificationChannelResponse struct {
+	ConfigList []struct {
+		ConfigID string               `json:"config_id"`
+		Config   apiextensionsv1.JSON `json:"config"`
+	} `json:"config_list"`
+}
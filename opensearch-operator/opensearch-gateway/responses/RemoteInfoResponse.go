@@ -0,0 +1,9 @@
+package responses
+
+// RemoteClusterInfo is the state of a connection to a remote cluster
+type RemoteClusterInfo struct {
+	Connected         bool   `json:"connected"`
+	Mode              string `json:"mode"`
+	NumNodesConnected *int   `json:"num_nodes_connected,omitempty"`
+	SkipUnavailable   bool   `json:"skip_unavailable"`
+}
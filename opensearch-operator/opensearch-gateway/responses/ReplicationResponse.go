@@ -0,0 +1,32 @@
+package responses
+
+import "encoding/json"
+
+// AutoFollowStatsResponse holds the auto-follow rules of the cluster and the indices they failed to replicate
+type AutoFollowStatsResponse struct {
+	AutoFollowStats []AutoFollowRuleStats `json:"autofollow_stats"`
+}
+
+type AutoFollowRuleStats struct {
+	Name          string   `json:"name"`
+	Pattern       string   `json:"pattern"`
+	FailedIndices []string `json:"failed_indices"`
+}
+
+// FollowerStatsResponse holds the statistics of the replication, the follower indices are the keys of the index stats
+type FollowerStatsResponse struct {
+	IndexStats map[string]json.RawMessage `json:"index_stats"`
+}
+
+// ReplicationStatusResponse is the state of the replication of a follower index
+type ReplicationStatusResponse struct {
+	Status         string `json:"status"`
+	Reason         string `json:"reason"`
+	LeaderAlias    string `json:"leader_alias"`
+	LeaderIndex    string `json:"leader_index"`
+	FollowerIndex  string `json:"follower_index"`
+	SyncingDetails *struct {
+		LeaderCheckpoint   int64 `json:"leader_checkpoint"`
+		FollowerCheckpoint int64 `json:"follower_checkpoint"`
+	} `json:"syncing_details,omitempty"`
+}
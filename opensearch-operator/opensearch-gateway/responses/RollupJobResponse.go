@@ -0,0 +1,12 @@
+package responses
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// GetRollupJobResponse is returned when a rollup job is fetched. The job holds fields OpenSearch adds, such as the
+// target fields of the dimensions, so it is kept as raw JSON
+type GetRollupJobResponse struct {
+	ID          string               `json:"_id"`
+	SeqNo       int                  `json:"_seq_no"`
+	PrimaryTerm int                  `json:"_primary_term"`
+	Rollup      apiextensionsv1.JSON `json:"rollup"`
+}
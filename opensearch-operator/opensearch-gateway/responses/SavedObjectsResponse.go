@@ -0,0 +1,25 @@
+package responses
+
+type SavedObjectsImportResponse struct {
+	Success        bool                      `json:"success"`
+	SuccessCount   int                       `json:"successCount"`
+	SuccessResults []SavedObjectImportResult `json:"successResults,omitempty"`
+	Errors         []SavedObjectImportError  `json:"errors,omitempty"`
+}
+
+type SavedObjectImportResult struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type SavedObjectImportError struct {
+	Type  string                     `json:"type"`
+	ID    string                     `json:"id"`
+	Title string                     `json:"title,omitempty"`
+	Error SavedObjectImportErrorType `json:"error"`
+}
+
+type SavedObjectImportErrorType struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+}
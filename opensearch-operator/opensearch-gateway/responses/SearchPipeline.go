@@ -0,0 +1,6 @@
+package responses
+
+import "github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+
+// GetSearchPipelinesResponse are the search pipelines returned by _search/pipeline, keyed by their name
+type GetSearchPipelinesResponse map[string]requests.SearchPipeline
@@ -0,0 +1,44 @@
+package responses
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// GetSecurityAnalyticsDetectorResponse is returned when a threat detector is fetched or created. The detector holds
+// fields OpenSearch adds, such as the ids of its monitors, so it is kept as raw JSON
+type GetSecurityAnalyticsDetectorResponse struct {
+	ID       string               `json:"_id"`
+	Version  int                  `json:"_version"`
+	Detector apiextensionsv1.JSON `json:"detector"`
+}
+
+// SearchSecurityAnalyticsDetectorsResponse is returned when threat detectors are searched
+type SearchSecurityAnalyticsDetectorsResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string `json:"_id"`
+			Source struct {
+				Name string `json:"name"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// SecurityAnalyticsRuleResponse is returned when a custom rule is created or updated
+type SecurityAnalyticsRuleResponse struct {
+	ID string `json:"_id"`
+}
+
+// SearchSecurityAnalyticsRulesResponse is returned when custom rules are searched
+type SearchSecurityAnalyticsRulesResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string                `json:"_id"`
+			Source SecurityAnalyticsRule `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// SecurityAnalyticsRule is a custom rule as stored by OpenSearch, the rule holds the Sigma YAML it was created with
+type SecurityAnalyticsRule struct {
+	Category string `json:"category"`
+	Rule     string `json:"rule"`
+}
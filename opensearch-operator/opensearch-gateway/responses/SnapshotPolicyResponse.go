@@ -0,0 +1,12 @@
+package responses
+
+import "github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+
+// GetSnapshotPolicyResponse is a policy of the Snapshot Management plugin with the sequence number and primary term
+// its updates have to pass
+type GetSnapshotPolicyResponse struct {
+	ID          string                  `json:"_id"`
+	SeqNo       int                     `json:"_seq_no"`
+	PrimaryTerm int                     `json:"_primary_term"`
+	Policy      requests.SnapshotPolicy `json:"sm_policy"`
+}
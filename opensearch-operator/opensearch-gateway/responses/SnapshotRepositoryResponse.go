@@ -0,0 +1,6 @@
+package responses
+
+import "github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+
+// GetSnapshotRepositoryResponse maps the names of snapshot repositories to their type and settings
+type GetSnapshotRepositoryResponse map[string]requests.SnapshotRepository
@@ -0,0 +1,11 @@
+package responses
+
+type SnapshotsResponse struct {
+	Snapshots []SnapshotResponse `json:"snapshots"`
+}
+
+type SnapshotResponse struct {
+	Snapshot        string `json:"snapshot"`
+	State           string `json:"state"`
+	EndTimeInMillis int64  `json:"end_time_in_millis"`
+}
@@ -0,0 +1,10 @@
+package responses
+
+import "github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+
+// GetStoredScriptResponse is the stored script returned by _scripts/<id>
+type GetStoredScriptResponse struct {
+	ID     string                `json:"_id"`
+	Found  bool                  `json:"found"`
+	Script requests.StoredScript `json:"script"`
+}
@@ -0,0 +1,23 @@
+package responses
+
+// TaskResponse is a task as returned by the _tasks API
+type TaskResponse struct {
+	Completed bool       `json:"completed"`
+	Error     *TaskError `json:"error,omitempty"`
+	// Result of the completed task, e.g. the failures of a reindex
+	Response *TaskResult `json:"response,omitempty"`
+}
+
+type TaskResult struct {
+	Failures []interface{} `json:"failures,omitempty"`
+}
+
+type TaskError struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// AsyncTaskResponse is returned by APIs started with wait_for_completion=false
+type AsyncTaskResponse struct {
+	Task string `json:"task"`
+}
@@ -1,6 +1,9 @@
 package responses
 
-import "github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+import (
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
 
 type GetIndexTemplatesResponse struct {
 	IndexTemplates []IndexTemplate `json:"index_templates"`
@@ -19,3 +22,14 @@ type ComponentTemplate struct {
 	Name              string                     `json:"name"`
 	ComponentTemplate requests.ComponentTemplate `json:"component_template"`
 }
+
+// SimulateIndexResponse is the index OpenSearch would create from the index templates of the cluster
+type SimulateIndexResponse struct {
+	Template    *apiextensionsv1.JSON      `json:"template"`
+	Overlapping []OverlappingIndexTemplate `json:"overlapping,omitempty"`
+}
+
+type OverlappingIndexTemplate struct {
+	Name          string   `json:"name"`
+	IndexPatterns []string `json:"index_patterns"`
+}
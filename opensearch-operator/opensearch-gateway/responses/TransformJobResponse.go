@@ -0,0 +1,30 @@
+package responses
+
+import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+// GetTransformJobResponse is returned when a transform job is fetched. The job holds fields OpenSearch adds, such as
+// the target fields of the groups, so it is kept as raw JSON
+type GetTransformJobResponse struct {
+	ID          string               `json:"_id"`
+	SeqNo       int                  `json:"_seq_no"`
+	PrimaryTerm int                  `json:"_primary_term"`
+	Transform   apiextensionsv1.JSON `json:"transform"`
+}
+
+// ExplainTransformResponse is the state of a transform job, keyed by its id. The metadata is empty before the first
+// run of the job
+type ExplainTransformResponse map[string]struct {
+	TransformMetadata *TransformMetadata `json:"transform_metadata"`
+}
+
+type TransformMetadata struct {
+	Status        string  `json:"status"`
+	FailureReason *string `json:"failure_reason"`
+	// Epoch milliseconds
+	LastUpdatedAt int64 `json:"last_updated_at"`
+	Stats         struct {
+		PagesProcessed     int64 `json:"pages_processed"`
+		DocumentsProcessed int64 `json:"documents_processed"`
+		DocumentsIndexed   int64 `json:"documents_indexed"`
+	} `json:"stats"`
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	headerAuthorization = "Authorization"
+	// bearerTokenRefreshMargin is the time before it expires a token is refreshed at the latest
+	bearerTokenRefreshMargin = time.Minute
+	// oidcErrorBodyLimit bounds the part of an error response of the OIDC provider that is reported
+	oidcErrorBodyLimit = 512
+)
+
+// TokenSource provides the bearer token the requests to OpenSearch are authenticated with
+type TokenSource interface {
+	// Token returns the current token, requesting a new one if needed
+	Token(ctx context.Context) (string, error)
+	// Invalidate drops the token after OpenSearch rejected it, the next call of Token gets a new one
+	Invalidate(token string)
+}
+
+// WithBearerToken authenticates the requests of the client with the token of the source instead of a username and
+// password. A request OpenSearch rejects with 401 is sent once more with a new token.
+func WithBearerToken(source TokenSource) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.tokenSource = source
+	}
+}
+
+type bearerTokenTransport struct {
+	transport http.RoundTripper
+	source    TokenSource
+}
+
+func (t *bearerTokenTransport) CloseIdleConnections() {
+	closeIdleConnections(t.transport)
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, ErrBearerTokenUnavailable(err)
+	}
+	resp, err := t.transport.RoundTrip(withBearerToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	t.source.Invalidate(token)
+	// The body of the request was consumed, it can only be sent again if it can be recreated
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	refreshed, err := t.source.Token(req.Context())
+	if err != nil || refreshed == token {
+		return resp, nil
+	}
+	retry := withBearerToken(req, refreshed)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.transport.RoundTrip(retry)
+}
+
+func withBearerToken(req *http.Request, token string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set(headerAuthorization, "Bearer "+token)
+	return req
+}
+
+// OIDCTokenSource requests bearer tokens from an OIDC provider with the client credentials flow. A token is reused
+// until shortly before it expires, or until OpenSearch rejects it.
+type OIDCTokenSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	client       *http.Client
+	now          func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func NewOIDCTokenSource(tokenURL string, clientID string, clientSecret string, scopes []string, client *http.Client) *OIDCTokenSource {
+	return &OIDCTokenSource{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		client:       client,
+		now:          time.Now,
+	}
+}
+
+// NewOIDCClient returns the client an OIDC token source requests its tokens with. It sends them with the transport
+// and through the proxy of the options like the client of the cluster, the other options are ignored.
+func NewOIDCClient(timeout time.Duration, opts ...OsClusterClientOption) *http.Client {
+	options := OsClusterClientOptions{}
+	options.apply(opts...)
+	transport := options.transport
+	if transport == nil {
+		// Unlike the cluster, the provider has to present a trusted certificate
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if httpTransport, ok := transport.(*http.Transport); ok && options.proxy != nil {
+		httpTransport = httpTransport.Clone()
+		httpTransport.Proxy = options.proxy
+		transport = httpTransport
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+func (s *OIDCTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.token != "" && (s.refreshAt.IsZero() || now.Before(s.refreshAt)) {
+		return s.token, nil
+	}
+	response, err := s.request(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = response.AccessToken
+	s.refreshAt = time.Time{}
+	if response.ExpiresIn > 0 {
+		lifetime := time.Duration(response.ExpiresIn) * time.Second
+		margin := lifetime / 5
+		if margin > bearerTokenRefreshMargin {
+			margin = bearerTokenRefreshMargin
+		}
+		s.refreshAt = now.Add(lifetime - margin)
+	}
+	return s.token, nil
+}
+
+func (s *OIDCTokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+func (s *OIDCTokenSource) request(ctx context.Context) (oidcTokenResponse, error) {
+	form := url.Values{"grant_type": []string{"client_credentials"}}
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oidcTokenResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return oidcTokenResponse{}, fmt.Errorf("failed to request a token from %s: %w", s.tokenURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, oidcErrorBodyLimit))
+		return oidcTokenResponse{}, fmt.Errorf("OIDC provider %s returned %d: %s", s.tokenURL, resp.StatusCode, body)
+	}
+	response := oidcTokenResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return oidcTokenResponse{}, fmt.Errorf("invalid token response from %s: %w", s.tokenURL, err)
+	}
+	if response.AccessToken == "" {
+		return oidcTokenResponse{}, fmt.Errorf("token response from %s has no access_token", s.tokenURL)
+	}
+	if response.TokenType != "" && !strings.EqualFold(response.TokenType, "bearer") {
+		return oidcTokenResponse{}, fmt.Errorf("OIDC provider %s returned a %s token instead of a bearer token", s.tokenURL, response.TokenType)
+	}
+	return response, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+)
+
+// ComponentTemplateCache holds the component templates of clusters read all at once, so the reconciles right after
+// the operator started do not each read their template from OpenSearch. A seeded template only answers the first read
+// of it, later reads and the reads after maxAge go to OpenSearch, so the cache never hides a change made in OpenSearch
+// from more than one reconcile. Clients are created for every reconcile, the cache is therefore shared between clients
+// and keyed by the cluster URL.
+type ComponentTemplateCache struct {
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu          sync.Mutex
+	inventories map[string]*componentTemplateInventory
+}
+
+// componentTemplateInventory are the component templates of a cluster at the time they were seeded
+type componentTemplateInventory struct {
+	seeded    time.Time
+	templates map[string]requests.ComponentTemplate
+	// read are the names that do not answer reads anymore, because they were read or written since
+	read map[string]bool
+}
+
+func NewComponentTemplateCache(maxAge time.Duration) *ComponentTemplateCache {
+	return &ComponentTemplateCache{
+		maxAge:      maxAge,
+		now:         time.Now,
+		inventories: map[string]*componentTemplateInventory{},
+	}
+}
+
+// WithComponentTemplateCache answers the reads of component templates seeded in the cache without a request, a nil
+// cache sends all of them
+func WithComponentTemplateCache(cache *ComponentTemplateCache) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.templateCache = cache
+	}
+}
+
+func (c *ComponentTemplateCache) seed(clusterUrl string, templates map[string]requests.ComponentTemplate) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inventories[clusterUrl] = &componentTemplateInventory{
+		seeded:    c.now(),
+		templates: templates,
+		read:      map[string]bool{},
+	}
+}
+
+// lookup returns the seeded component template of the cluster and whether it exists. answered is false if the cache
+// cannot answer the read, which then has to go to OpenSearch. A consumed template does not answer later reads.
+func (c *ComponentTemplateCache) lookup(clusterUrl string, name string, consume bool) (template requests.ComponentTemplate, exists bool, answered bool) {
+	if c == nil {
+		return requests.ComponentTemplate{}, false, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inventory, ok := c.inventories[clusterUrl]
+	if !ok {
+		return requests.ComponentTemplate{}, false, false
+	}
+	if c.maxAge > 0 && c.now().Sub(inventory.seeded) > c.maxAge {
+		delete(c.inventories, clusterUrl)
+		return requests.ComponentTemplate{}, false, false
+	}
+	if inventory.read[name] {
+		return requests.ComponentTemplate{}, false, false
+	}
+	if consume {
+		inventory.read[name] = true
+	}
+	template, exists = inventory.templates[name]
+	return template, exists, true
+}
+
+// invalidate stops the cache from answering reads of the component template, e.g. because it is written
+func (c *ComponentTemplateCache) invalidate(clusterUrl string, name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if inventory, ok := c.inventories[clusterUrl]; ok {
+		inventory.read[name] = true
+	}
+}
+
+// WarmComponentTemplateCache reads all component templates of the cluster in one request and seeds the component
+// template cache of the client with them
+func WarmComponentTemplateCache(ctx context.Context, service *OsClusterClient) error {
+	if service.templateCache == nil {
+		return nil
+	}
+	var path strings.Builder
+	path.WriteString("/_component_template")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	componentTemplatesResponse := responses.GetComponentTemplatesResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&componentTemplatesResponse); err != nil {
+		return err
+	}
+	templates := make(map[string]requests.ComponentTemplate, len(componentTemplatesResponse.ComponentTemplates))
+	for _, template := range componentTemplatesResponse.ComponentTemplates {
+		templates[template.Name] = template.ComponentTemplate
+	}
+	service.templateCache.seed(service.clusterUrl, templates)
+	return nil
+}
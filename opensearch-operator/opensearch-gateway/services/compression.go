@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	headerContentEncoding = "Content-Encoding"
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentLength   = "Content-Length"
+	encodingGzip          = "gzip"
+)
+
+// RequestCompression gzips the request bodies sent to OpenSearch. Clusters answering a compressed request with
+// 415 Unsupported Media Type, e.g. because of a proxy in front of them, get the request again uncompressed and are
+// remembered, so later requests to them are no longer compressed. Clients are created for every reconcile, the
+// negotiated support is therefore shared between clients and keyed by host.
+type RequestCompression struct {
+	mu          sync.Mutex
+	unsupported map[string]bool
+}
+
+func NewRequestCompression() *RequestCompression {
+	return &RequestCompression{
+		unsupported: map[string]bool{},
+	}
+}
+
+// WithRequestCompression compresses the request bodies sent to OpenSearch, a nil compression sends them as is
+func WithRequestCompression(compression *RequestCompression) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.compression = compression
+	}
+}
+
+func (c *RequestCompression) supported(host string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.unsupported[host]
+}
+
+func (c *RequestCompression) markUnsupported(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unsupported[host] = true
+}
+
+// compressionTransport gzips request bodies and transparently decompresses gzip encoded responses, so the
+// responses, including the error messages returned by OpenSearch, are parsed as usual
+type compressionTransport struct {
+	transport   http.RoundTripper
+	compression *RequestCompression
+}
+
+func (t *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody || !t.compression.supported(req.URL.Host) {
+		return t.roundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := gzipBytes(body)
+	if err != nil {
+		return nil, err
+	}
+
+	compressedReq := withBody(req, compressed)
+	compressedReq.Header.Set(headerContentEncoding, encodingGzip)
+	resp, err := t.roundTrip(compressedReq)
+	if err != nil || resp.StatusCode != http.StatusUnsupportedMediaType {
+		return resp, err
+	}
+
+	// The cluster does not accept compressed bodies, send the request again as is
+	resp.Body.Close()
+	t.compression.markUnsupported(req.URL.Host)
+	return t.roundTrip(withBody(req, body))
+}
+
+func (t *compressionTransport) CloseIdleConnections() {
+	closeIdleConnections(t.transport)
+}
+
+func (t *compressionTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	// Setting Accept-Encoding disables the decompression of net/http, the response is decompressed below instead,
+	// which also covers transports not doing it themselves
+	req.Header.Set(headerAcceptEncoding, encodingGzip)
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || req.Method == http.MethodHead || !strings.EqualFold(resp.Header.Get(headerContentEncoding), encodingGzip) {
+		return resp, err
+	}
+
+	resp.Body = &gzipReadCloser{body: resp.Body}
+	resp.Header.Del(headerContentEncoding)
+	resp.Header.Del(headerContentLength)
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+func withBody(req *http.Request, body []byte) *http.Request {
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+	return req
+}
+
+func gzipBytes(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gzipReadCloser opens the gzip reader on the first read, so empty bodies are read as empty instead of failing
+type gzipReadCloser struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+}
+
+func (r *gzipReadCloser) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		reader, err := gzip.NewReader(r.body)
+		if err != nil {
+			return 0, err
+		}
+		r.reader = reader
+	}
+	return r.reader.Read(p)
+}
+
+func (r *gzipReadCloser) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+const (
+	headerXsrf   = "osd-xsrf"
+	headerTenant = "securitytenant"
+)
+
+// DashboardsClient talks to the HTTP API of OpenSearch Dashboards
+type DashboardsClient struct {
+	OsClusterClientOptions
+	client   *http.Client
+	url      string
+	username string
+	password string
+}
+
+func NewDashboardsClient(dashboardsUrl string, username string, password string, opts ...OsClusterClientOption) *DashboardsClient {
+	options := OsClusterClientOptions{}
+	options.apply(opts...)
+	return &DashboardsClient{
+		OsClusterClientOptions: options,
+		client:                 &http.Client{Transport: options.roundTripper()},
+		url:                    dashboardsUrl,
+		username:               username,
+		password:               password,
+	}
+}
+
+// do performs a request against the Dashboards API, scoped to the given tenant if it is not empty
+func (c *DashboardsClient) do(ctx context.Context, method string, path string, tenant string, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set(headerXsrf, "true")
+	if tenant != "" {
+		req.Header.Set(headerTenant, tenant)
+	}
+	if contentType != "" {
+		req.Header.Set(headerContentType, contentType)
+	}
+	return c.client.Do(req)
+}
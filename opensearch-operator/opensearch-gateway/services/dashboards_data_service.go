@@ -0,0 +1,79 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+)
+
+const savedObjectsPath = "/api/saved_objects"
+
+// ImportSavedObjects imports a bundle of saved objects in NDJSON format into the given tenant.
+// If overwrite is false, objects that already exist are reported as conflicts in the response instead of being replaced.
+func ImportSavedObjects(
+	ctx context.Context,
+	service *DashboardsClient,
+	tenant string,
+	bundle string,
+	overwrite bool,
+) (responses.SavedObjectsImportResponse, error) {
+	var response responses.SavedObjectsImportResponse
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "export.ndjson")
+	if err != nil {
+		return response, err
+	}
+	if _, err := io.WriteString(part, bundle); err != nil {
+		return response, err
+	}
+	if err := writer.Close(); err != nil {
+		return response, err
+	}
+
+	path := fmt.Sprintf("%s/_import", savedObjectsPath)
+	if overwrite {
+		path = path + "?overwrite=true"
+	}
+	resp, err := service.do(ctx, http.MethodPost, path, tenant, writer.FormDataContentType(), body)
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusForbidden {
+		return response, ErrRequestForbidden(resp.Status)
+	} else if resp.StatusCode >= 300 {
+		return response, fmt.Errorf("response from API is %s", resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+// DeleteSavedObject deletes a saved object from the given tenant, objects that do not exist are ignored
+func DeleteSavedObject(ctx context.Context, service *DashboardsClient, tenant string, objectType string, id string) error {
+	path := fmt.Sprintf("%s/%s/%s", savedObjectsPath, url.PathEscape(objectType), url.PathEscape(id))
+	resp, err := service.do(ctx, http.MethodDelete, path, tenant, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	} else if resp.StatusCode == http.StatusForbidden {
+		return ErrRequestForbidden(resp.Status)
+	} else if resp.StatusCode >= 300 {
+		return fmt.Errorf("response from API is %s", resp.Status)
+	}
+	return nil
+}
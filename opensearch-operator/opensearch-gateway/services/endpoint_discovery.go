@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// EndpointResolver returns the addresses (host:port) of the ready OpenSearch nodes requests are balanced across
+type EndpointResolver interface {
+	Endpoints(ctx context.Context) ([]string, error)
+}
+
+// WithEndpointDiscovery sends the requests of the client to the endpoints of the resolver in turn instead of the
+// host of the cluster URL. The endpoints are resolved for every request, so they follow the nodes becoming ready and
+// unready. A request failing to connect is sent to the next endpoint. Without endpoints, or if they cannot be
+// resolved, requests are sent to the cluster URL. TLS certificates are verified against the host of the cluster URL.
+func WithEndpointDiscovery(resolver EndpointResolver) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.endpointResolver = resolver
+	}
+}
+
+type discoveryTransport struct {
+	transport http.RoundTripper
+	resolver  EndpointResolver
+	next      atomic.Uint64
+
+	mu sync.Mutex
+	// endpoints are the endpoints last resolved, the idle connections are closed when they change
+	endpoints []string
+}
+
+func (t *discoveryTransport) CloseIdleConnections() {
+	closeIdleConnections(t.transport)
+}
+
+func (t *discoveryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoints, err := t.resolver.Endpoints(req.Context())
+	if err != nil || len(endpoints) == 0 {
+		return t.transport.RoundTrip(req)
+	}
+	t.update(endpoints)
+
+	// A body that cannot be recreated can only be sent once
+	attempts := len(endpoints)
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		attempts = 1
+	}
+	start := t.next.Add(1) - 1
+	for i := 0; i < attempts; i++ {
+		endpoint := endpoints[(start+uint64(i))%uint64(len(endpoints))]
+		out := req.Clone(req.Context())
+		out.URL.Host = endpoint
+		out.Host = req.URL.Host
+		if i > 0 && req.GetBody != nil {
+			if out.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		var resp *http.Response
+		resp, err = t.transport.RoundTrip(out)
+		if err == nil || req.Context().Err() != nil {
+			return resp, err
+		}
+	}
+	return nil, err
+}
+
+// update records the resolved endpoints, connections kept to endpoints that were removed are closed once idle
+func (t *discoveryTransport) update(endpoints []string) {
+	sorted := append([]string(nil), endpoints...)
+	sort.Strings(sorted)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.endpoints != nil && strings.Join(t.endpoints, ",") != strings.Join(sorted, ",") {
+		closeIdleConnections(t.transport)
+	}
+	t.endpoints = sorted
+}
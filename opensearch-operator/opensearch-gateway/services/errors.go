@@ -9,6 +9,13 @@ var (
 	ErrClusterHealthOperation   = errors.New("cluster health failed")
 	ErrClusterSettingsOperation = errors.New("cluster settings failed")
 	ErrCatIndicesOperation      = errors.New("cat indices failed")
+	ErrForbidden                = errors.New("request forbidden")
+	ErrConcurrentModification   = errors.New("modified concurrently")
+	ErrClientCertificate        = errors.New("client certificate authentication failed")
+	ErrBearerToken              = errors.New("bearer token authentication failed")
+	ErrDocumentRejected         = errors.New("document rejected")
+	ErrProxy                    = errors.New("proxy connection failed")
+	ErrTemplateRender           = errors.New("template does not render")
 )
 
 func ErrClusterHealthGetFailed(resp string) error {
@@ -22,3 +29,49 @@ func ErrClusterSettingsGetFailed(resp string) error {
 func ErrCatIndicesFailed(resp string) error {
 	return fmt.Errorf("%w: %s", ErrCatIndicesOperation, resp)
 }
+
+// ErrRequestForbidden wraps ErrForbidden so callers can detect missing privileges with errors.Is
+func ErrRequestForbidden(resp string) error {
+	return fmt.Errorf("%w: %s", ErrForbidden, resp)
+}
+
+// ErrComponentTemplateModified wraps ErrConcurrentModification for a component template another writer changed
+func ErrComponentTemplateModified(componentTemplateName string) error {
+	return fmt.Errorf("component template %s was %w", componentTemplateName, ErrConcurrentModification)
+}
+
+// ErrInvalidClientCertificate wraps ErrClientCertificate for a client certificate that can not be used
+func ErrInvalidClientCertificate(err error) error {
+	return fmt.Errorf("%w: %s", ErrClientCertificate, err)
+}
+
+// ErrBearerTokenUnavailable wraps ErrBearerToken for a bearer token that could not be read or requested
+func ErrBearerTokenUnavailable(err error) error {
+	return fmt.Errorf("%w: %s", ErrBearerToken, err)
+}
+
+// ErrClientCertificateRejected wraps ErrClientCertificate for a TLS handshake the cluster aborted
+func ErrClientCertificateRejected(err error) error {
+	return fmt.Errorf("%w, the cluster aborted the TLS handshake: %s", ErrClientCertificate, err)
+}
+
+// ErrDocumentRejectedBy wraps ErrDocumentRejected for a document OpenSearch did not index for the given reason
+func ErrDocumentRejectedBy(reason string) error {
+	return fmt.Errorf("%w: %s", ErrDocumentRejected, reason)
+}
+
+// ErrInvalidProxy wraps ErrProxy for a proxy URL that can not be used
+func ErrInvalidProxy(reason string) error {
+	return fmt.Errorf("%w, invalid proxy URL: %s", ErrProxy, reason)
+}
+
+// ErrProxyUnreachable wraps ErrProxy for a proxy the client could not connect to
+func ErrProxyUnreachable(err error) error {
+	return fmt.Errorf("%w, the proxy is unreachable: %s", ErrProxy, err)
+}
+
+// ErrTemplateRenderFailed wraps ErrTemplateRender for a search template OpenSearch could not render for the given
+// reason
+func ErrTemplateRenderFailed(reason string) error {
+	return fmt.Errorf("%w: %s", ErrTemplateRender, reason)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+
+	// defaultETagCacheEntries bounds the number of responses kept by an ETagCache
+	defaultETagCacheEntries = 1000
+)
+
+// ETagCache remembers the ETag and the parsed body of GET responses, so a resource that has not changed since
+// the last request can be answered by OpenSearch or a proxy in front of it with 304 Not Modified.
+// Clients are created for every reconcile, the cache is therefore shared between clients and keyed by the full URL.
+type ETagCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]etagCacheEntry
+}
+
+type etagCacheEntry struct {
+	etag  string
+	value interface{}
+}
+
+func NewETagCache() *ETagCache {
+	return &ETagCache{
+		maxEntries: defaultETagCacheEntries,
+		entries:    map[string]etagCacheEntry{},
+	}
+}
+
+// WithETagCache sends conditional GET requests for the resources supporting it, a nil cache disables them
+func WithETagCache(cache *ETagCache) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.etagCache = cache
+	}
+}
+
+func (c *ETagCache) get(key string) (etagCacheEntry, bool) {
+	if c == nil {
+		return etagCacheEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *ETagCache) put(key string, etag string, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		// Evict an arbitrary entry, it is fetched again with the next request
+		for evicted := range c.entries {
+			delete(c.entries, evicted)
+			break
+		}
+	}
+	c.entries[key] = etagCacheEntry{etag: etag, value: value}
+}
+
+func (c *ETagCache) invalidate(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// etagCacheKey identifies the resource at path of the cluster the client is connected to
+func (client *OsClusterClient) etagCacheKey(path strings.Builder) string {
+	return client.clusterUrl + path.String()
+}
+
+// doHTTPConditional performs a HTTP GET or HEAD request, sending If-None-Match when an ETag is cached for the path.
+// The cached entry is returned alongside the response, it is only valid if the response is 304 Not Modified.
+func doHTTPConditional(ctx context.Context, service *OsClusterClient, method string, path strings.Builder) (*opensearchapi.Response, etagCacheEntry, error) {
+	entry, cached := service.etagCache.get(service.etagCacheKey(path))
+
+	req, err := http.NewRequest(method, path.String(), nil)
+	if err != nil {
+		return nil, entry, err
+	}
+
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	if cached {
+		req.Header.Set(headerIfNoneMatch, entry.etag)
+	}
+
+	res, err := service.client.Perform(req)
+	if err != nil {
+		return nil, entry, err
+	}
+
+	return &opensearchapi.Response{StatusCode: res.StatusCode, Body: res.Body, Header: res.Header}, entry, nil
+}
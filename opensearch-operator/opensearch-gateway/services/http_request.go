@@ -67,6 +67,26 @@ func doHTTPPut(ctx context.Context, client *opensearch.Client, path strings.Buil
 	return &opensearchapi.Response{StatusCode: res.StatusCode, Body: res.Body, Header: res.Header}, nil
 }
 
+// doHTTPPost performs a HTTP POST request
+func doHTTPPost(ctx context.Context, client *opensearch.Client, path strings.Builder, body io.Reader) (*opensearchapi.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, path.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	req.Header.Add(headerContentType, jsonContentHeader)
+
+	res, err := client.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &opensearchapi.Response{StatusCode: res.StatusCode, Body: res.Body, Header: res.Header}, nil
+}
+
 // doHTTPDelete performs a HTTP DELETE request
 func doHTTPDelete(ctx context.Context, client *opensearch.Client, path strings.Builder) (*opensearchapi.Response, error) {
 	req, err := http.NewRequest(http.MethodDelete, path.String(), nil)
@@ -85,3 +105,23 @@ func doHTTPDelete(ctx context.Context, client *opensearch.Client, path strings.B
 
 	return &opensearchapi.Response{StatusCode: res.StatusCode, Body: res.Body, Header: res.Header}, nil
 }
+
+// doHTTPDeleteWithBody performs a HTTP DELETE request with a body, as some plugin APIs identify what to delete by it
+func doHTTPDeleteWithBody(ctx context.Context, client *opensearch.Client, path strings.Builder, body io.Reader) (*opensearchapi.Response, error) {
+	req, err := http.NewRequest(http.MethodDelete, path.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	req.Header.Add(headerContentType, jsonContentHeader)
+
+	res, err := client.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &opensearchapi.Response{StatusCode: res.StatusCode, Body: res.Body, Header: res.Header}, nil
+}
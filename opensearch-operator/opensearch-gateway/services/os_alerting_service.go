@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+// MonitorPath returns a strings.Builder pointing to /_plugins/_alerting/monitors/<monitorID>, or to
+// /_plugins/_alerting/monitors if the id is empty
+func MonitorPath(monitorID string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_plugins/_alerting/monitors/") + len(monitorID))
+	path.WriteString("/_plugins/_alerting/monitors")
+	if monitorID != "" {
+		path.WriteString("/")
+		path.WriteString(url.PathEscape(monitorID))
+	}
+	return path
+}
+
+// FindMonitorID returns the id of the monitor with the passed name, an empty string if there is none
+func FindMonitorID(ctx context.Context, service *OsClusterClient, monitorName string) (string, error) {
+	var path strings.Builder
+	monitorsPath := MonitorPath("")
+	path.WriteString(monitorsPath.String())
+	path.WriteString("/_search")
+	query := map[string]interface{}{
+		"size":  100,
+		"query": map[string]interface{}{"match_phrase": map[string]interface{}{"monitor.name": monitorName}},
+	}
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(query))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// The index of the monitors does not exist before the first monitor is created
+	if resp.StatusCode == 404 {
+		return "", nil
+	} else if resp.IsError() {
+		return "", fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	searchResponse := responses.SearchMonitorsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+		return "", err
+	}
+	// match_phrase also matches names that contain the phrase
+	for _, hit := range searchResponse.Hits.Hits {
+		if hit.Source.Name == monitorName {
+			return hit.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// GetMonitor fetches the monitor with the passed id, ErrNotFound if it does not exist
+func GetMonitor(ctx context.Context, service *OsClusterClient, monitorID string) (*responses.GetMonitorResponse, error) {
+	path := MonitorPath(monitorID)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	monitorResponse := responses.GetMonitorResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&monitorResponse); err != nil {
+		return nil, err
+	}
+	return &monitorResponse, nil
+}
+
+// CreateMonitor creates the passed monitor and returns the id OpenSearch generated for it
+func CreateMonitor(ctx context.Context, service *OsClusterClient, monitor requests.Monitor) (string, error) {
+	path := MonitorPath("")
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(monitor))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return "", fmt.Errorf("failed to create monitor: %s", resp.String())
+	}
+
+	monitorResponse := responses.GetMonitorResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&monitorResponse); err != nil {
+		return "", err
+	}
+	return monitorResponse.ID, nil
+}
+
+// UpdateMonitor updates the monitor with the passed id if it still has the sequence number and primary term it was
+// read with
+func UpdateMonitor(ctx context.Context, service *OsClusterClient, monitorID string, seqno, primterm int, monitor requests.Monitor) error {
+	var path strings.Builder
+	monitorPath := MonitorPath(monitorID)
+	path.WriteString(monitorPath.String())
+	path.WriteString("?if_seq_no=")
+	path.WriteString(strconv.Itoa(seqno))
+	path.WriteString("&if_primary_term=")
+	path.WriteString(strconv.Itoa(primterm))
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(monitor))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update monitor: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteMonitor deletes the monitor with the passed id, a monitor that does not exist is ignored
+func DeleteMonitor(ctx context.Context, service *OsClusterClient, monitorID string) error {
+	path := MonitorPath(monitorID)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+// DetectorPath returns a strings.Builder pointing to /_plugins/_anomaly_detection/detectors/<detectorID>, or to
+// /_plugins/_anomaly_detection/detectors if the id is empty
+func DetectorPath(detectorID string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_plugins/_anomaly_detection/detectors/") + len(detectorID))
+	path.WriteString("/_plugins/_anomaly_detection/detectors")
+	if detectorID != "" {
+		path.WriteString("/")
+		path.WriteString(url.PathEscape(detectorID))
+	}
+	return path
+}
+
+// FindDetectorID returns the id of the detector with the passed name, an empty string if there is none
+func FindDetectorID(ctx context.Context, service *OsClusterClient, detectorName string) (string, error) {
+	var path strings.Builder
+	detectorsPath := DetectorPath("")
+	path.WriteString(detectorsPath.String())
+	path.WriteString("/_search")
+	query := map[string]interface{}{
+		"size":  100,
+		"query": map[string]interface{}{"match_phrase": map[string]interface{}{"name": detectorName}},
+	}
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(query))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// The index of the detectors does not exist before the first detector is created
+	if resp.StatusCode == 404 {
+		return "", nil
+	} else if resp.IsError() {
+		return "", fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	searchResponse := responses.SearchDetectorsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+		return "", err
+	}
+	// match_phrase also matches names that contain the phrase
+	for _, hit := range searchResponse.Hits.Hits {
+		if hit.Source.Name == detectorName {
+			return hit.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// GetDetector fetches the detector with the passed id and its job, ErrNotFound if it does not exist
+func GetDetector(ctx context.Context, service *OsClusterClient, detectorID string) (*responses.GetDetectorResponse, error) {
+	var path strings.Builder
+	detectorPath := DetectorPath(detectorID)
+	path.WriteString(detectorPath.String())
+	path.WriteString("?job=true")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	detectorResponse := responses.GetDetectorResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&detectorResponse); err != nil {
+		return nil, err
+	}
+	return &detectorResponse, nil
+}
+
+// CreateDetector creates the passed detector and returns the id OpenSearch generated for it
+func CreateDetector(ctx context.Context, service *OsClusterClient, detector requests.AnomalyDetector) (string, error) {
+	path := DetectorPath("")
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(detector))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return "", fmt.Errorf("failed to create detector: %s", resp.String())
+	}
+
+	detectorResponse := responses.GetDetectorResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&detectorResponse); err != nil {
+		return "", err
+	}
+	return detectorResponse.ID, nil
+}
+
+// UpdateDetector updates the detector with the passed id if it still has the sequence number and primary term it
+// was read with. OpenSearch rejects updates of running detectors
+func UpdateDetector(ctx context.Context, service *OsClusterClient, detectorID string, seqno, primterm int, detector requests.AnomalyDetector) error {
+	var path strings.Builder
+	detectorPath := DetectorPath(detectorID)
+	path.WriteString(detectorPath.String())
+	path.WriteString("?if_seq_no=")
+	path.WriteString(strconv.Itoa(seqno))
+	path.WriteString("&if_primary_term=")
+	path.WriteString(strconv.Itoa(primterm))
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(detector))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update detector: %s", resp.String())
+	}
+	return nil
+}
+
+// StartDetector starts the job of the detector with the passed id
+func StartDetector(ctx context.Context, service *OsClusterClient, detectorID string) error {
+	return detectorJobAction(ctx, service, detectorID, "_start")
+}
+
+// StopDetector stops the job of the detector with the passed id
+func StopDetector(ctx context.Context, service *OsClusterClient, detectorID string) error {
+	return detectorJobAction(ctx, service, detectorID, "_stop")
+}
+
+func detectorJobAction(ctx context.Context, service *OsClusterClient, detectorID string, action string) error {
+	var path strings.Builder
+	detectorPath := DetectorPath(detectorID)
+	path.WriteString(detectorPath.String())
+	path.WriteString("/")
+	path.WriteString(action)
+	resp, err := doHTTPPost(ctx, service.client, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to %s detector: %s", strings.TrimPrefix(action, "_"), resp.String())
+	}
+	return nil
+}
+
+// DetectorProfile returns the state of the job of the detector with the passed id
+func DetectorProfile(ctx context.Context, service *OsClusterClient, detectorID string) (*responses.DetectorProfileResponse, error) {
+	var path strings.Builder
+	detectorPath := DetectorPath(detectorID)
+	path.WriteString(detectorPath.String())
+	path.WriteString("/_profile/state,error,init_progress")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	profileResponse := responses.DetectorProfileResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&profileResponse); err != nil {
+		return nil, err
+	}
+	return &profileResponse, nil
+}
+
+// DeleteDetector deletes the detector with the passed id, a detector that does not exist is ignored. OpenSearch
+// rejects deleting running detectors
+func DeleteDetector(ctx context.Context, service *OsClusterClient, detectorID string) error {
+	path := DetectorPath(detectorID)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+// GetAuditConfig fetches the audit configuration of the security plugin
+func GetAuditConfig(ctx context.Context, service *OsClusterClient) (responses.GetAuditConfigResponse, error) {
+	var path strings.Builder
+	path.WriteString("/_plugins/_security/api/audit")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return responses.GetAuditConfigResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return responses.GetAuditConfigResponse{}, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	auditResponse := responses.GetAuditConfigResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&auditResponse); err != nil {
+		return responses.GetAuditConfigResponse{}, err
+	}
+	return auditResponse, nil
+}
+
+// PutAuditConfig replaces the audit configuration of the security plugin with the passed configuration
+func PutAuditConfig(ctx context.Context, service *OsClusterClient, config map[string]interface{}) error {
+	resp, err := service.PutSecurityResource(ctx, AUDIT, "config", opensearchutil.NewJSONReader(config))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update audit configuration: %s", resp.String())
+	}
+	return nil
+}
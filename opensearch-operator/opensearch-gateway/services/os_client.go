@@ -4,8 +4,12 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -19,9 +23,13 @@ import (
 
 const (
 	headerContentType = "Content-Type"
+	headerUserAgent   = "User-Agent"
+	headerOpaqueID    = "X-Opaque-Id"
+	userAgentProduct  = "opensearch-k8s-operator"
 
 	jsonContentHeader = "application/json"
 	ismResource       = "_ism"
+	smResource        = "_sm"
 )
 
 var AdditionalSystemIndices = []string{
@@ -39,14 +47,30 @@ var AdditionalSystemIndices = []string{
 	".replication-metadata-store",
 }
 
+// OperatorVersion is reported in the User-Agent of all requests, it is set at build time
+var OperatorVersion = "dev"
+
 type OsClusterClient struct {
 	OsClusterClientOptions
-	client   *opensearch.Client
-	MainPage responses.MainResponse
+	client       *opensearch.Client
+	clusterUrl   string
+	roundTripper http.RoundTripper
+	MainPage     responses.MainResponse
 }
 
 type OsClusterClientOptions struct {
-	transport http.RoundTripper
+	transport        http.RoundTripper
+	reconciledObject reconciledObject
+	etagCache        *ETagCache
+	templateCache    *ComponentTemplateCache
+	compression      *RequestCompression
+	certificate      *tls.Certificate
+	tokenSource      TokenSource
+	requestLog       *RequestLog
+	endpointResolver EndpointResolver
+	proxy            func(*http.Request) (*url.URL, error)
+	// serverName is the host of the cluster URL, certificates are verified against it with endpoint discovery
+	serverName string
 }
 
 type OsClusterClientOption func(*OsClusterClientOptions)
@@ -63,18 +87,167 @@ func WithTransport(transport http.RoundTripper) OsClusterClientOption {
 	}
 }
 
+// WithClientCertificate presents the client certificate in the TLS handshake with OpenSearch. It is added to the
+// configured transport if it is an *http.Transport, other transports are used as is.
+func WithClientCertificate(certificate *tls.Certificate) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.certificate = certificate
+	}
+}
+
+// WithReconciledObject identifies the requests made while reconciling an object. The object is added to the
+// User-Agent and its namespace, name and uid are sent as X-Opaque-Id, which OpenSearch includes in its slow and audit logs
+func WithReconciledObject(kind string, namespace string, name string, uid string) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.reconciledObject = newReconciledObject(kind, namespace, name, uid)
+	}
+}
+
+// reconciledObject identifies the object requests are made for
+type reconciledObject struct {
+	description string
+	opaqueID    string
+}
+
+type reconciledObjectContextKey struct{}
+
+func newReconciledObject(kind string, namespace string, name string, uid string) reconciledObject {
+	return reconciledObject{
+		description: fmt.Sprintf("%s %s/%s", kind, namespace, name),
+		opaqueID:    requestLogKey(namespace, name, uid),
+	}
+}
+
+// ContextWithReconciledObject identifies the requests made with the context like WithReconciledObject, so clients
+// shared between objects identify the requests of each of them. It takes precedence over WithReconciledObject.
+func ContextWithReconciledObject(ctx context.Context, kind string, namespace string, name string, uid string) context.Context {
+	return context.WithValue(ctx, reconciledObjectContextKey{}, newReconciledObject(kind, namespace, name, uid))
+}
+
+// requestObject returns the object the request is made for, from its context or else the one of the client
+func requestObject(req *http.Request, fallback reconciledObject) reconciledObject {
+	if object, ok := req.Context().Value(reconciledObjectContextKey{}).(reconciledObject); ok {
+		return object
+	}
+	return fallback
+}
+
+// roundTripper returns the configured transport wrapped to set the identifying headers on every request, to
+// authenticate with a bearer token, to compress the request bodies, to log the requests and to balance them across
+// discovered endpoints if enabled
+func (o *OsClusterClientOptions) roundTripper() http.RoundTripper {
+	transport := o.transport
+	if transport == nil {
+		transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	if httpTransport, ok := transport.(*http.Transport); ok && o.proxy != nil {
+		httpTransport = httpTransport.Clone()
+		httpTransport.Proxy = o.proxy
+		transport = httpTransport
+	}
+	if httpTransport, ok := transport.(*http.Transport); ok && o.certificate != nil {
+		httpTransport = httpTransport.Clone()
+		if httpTransport.TLSClientConfig == nil {
+			httpTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		httpTransport.TLSClientConfig.Certificates = []tls.Certificate{*o.certificate}
+		transport = httpTransport
+	}
+	if o.endpointResolver != nil {
+		// Requests are sent to the addresses of the endpoints, which the certificates do not name
+		if httpTransport, ok := transport.(*http.Transport); ok && o.serverName != "" {
+			httpTransport = httpTransport.Clone()
+			if httpTransport.TLSClientConfig == nil {
+				httpTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			}
+			if httpTransport.TLSClientConfig.ServerName == "" {
+				httpTransport.TLSClientConfig.ServerName = o.serverName
+			}
+			transport = httpTransport
+		}
+		transport = &discoveryTransport{
+			transport: transport,
+			resolver:  o.endpointResolver,
+		}
+	}
+	if o.tokenSource != nil {
+		// Below the compression and the request log, so a request rejected for an outdated token is sent again as it
+		// was sent before
+		transport = &bearerTokenTransport{
+			transport: transport,
+			source:    o.tokenSource,
+		}
+	}
+	if o.compression != nil {
+		transport = &compressionTransport{
+			transport:   transport,
+			compression: o.compression,
+		}
+	}
+	if o.requestLog != nil {
+		transport = &requestLogTransport{
+			transport: transport,
+			log:       o.requestLog,
+			object:    o.reconciledObject,
+		}
+	}
+	return &headerTransport{
+		transport: transport,
+		object:    o.reconciledObject,
+	}
+}
+
+type headerTransport struct {
+	transport http.RoundTripper
+	object    reconciledObject
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	object := requestObject(req, t.object)
+	userAgent := fmt.Sprintf("%s/%s", userAgentProduct, OperatorVersion)
+	if object.description != "" {
+		userAgent = fmt.Sprintf("%s (%s)", userAgent, object.description)
+	}
+	opaqueID := object.opaqueID
+	trace := requestTrace(req)
+	if trace != nil {
+		opaqueID = trace.tracedOpaqueID(opaqueID)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(headerUserAgent, userAgent)
+	if opaqueID != "" {
+		req.Header.Set(headerOpaqueID, opaqueID)
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if trace != nil {
+		trace.record(opaqueID, resp)
+	}
+	return resp, err
+}
+
+func (t *headerTransport) CloseIdleConnections() {
+	closeIdleConnections(t.transport)
+}
+
+// closeIdleConnections closes the idle connections of the transport if it keeps any
+func closeIdleConnections(transport http.RoundTripper) {
+	if closer, ok := transport.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
 func NewOsClusterClient(clusterUrl string, username string, password string, opts ...OsClusterClientOption) (*OsClusterClient, error) {
 	options := OsClusterClientOptions{}
 	options.apply(opts...)
+	if parsed, err := url.Parse(clusterUrl); err == nil {
+		options.serverName = parsed.Hostname()
+	}
+	roundTripper := options.roundTripper()
 	config := opensearch.Config{
-		Transport: func() http.RoundTripper {
-			if options.transport != nil {
-				return options.transport
-			}
-			return &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			}
-		}(),
+		Transport: roundTripper,
 		Addresses: []string{clusterUrl},
 		Username:  username,
 		Password:  password,
@@ -82,13 +255,35 @@ func NewOsClusterClient(clusterUrl string, username string, password string, opt
 
 	client, err := NewOsClusterClientFromConfig(config)
 	if err != nil {
+		if options.certificate != nil && handshakeAborted(err) {
+			return nil, ErrClientCertificateRejected(err)
+		}
+		if proxyConnectFailed(err) {
+			return nil, ErrProxyUnreachable(err)
+		}
 		return nil, err
 	}
 
 	client.OsClusterClientOptions = options
+	client.clusterUrl = clusterUrl
+	client.roundTripper = roundTripper
 	return client, nil
 }
 
+// Close releases the idle connections of the client. Requests still running complete, the client may still be used
+// afterwards and opens new connections then.
+func (client *OsClusterClient) Close() {
+	if client.roundTripper != nil {
+		closeIdleConnections(client.roundTripper)
+	}
+}
+
+// handshakeAborted returns true if the cluster sent a TLS alert, e.g. because it did not accept the client certificate
+func handshakeAborted(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "remote error"
+}
+
 func NewOsClusterClientFromConfig(config opensearch.Config) (*OsClusterClient, error) {
 	service := new(OsClusterClient)
 	client, err := opensearch.NewClient(config)
@@ -332,7 +527,31 @@ func (client *OsClusterClient) DeleteISMConfig(ctx context.Context, name string)
 	return doHTTPDelete(ctx, client.client, path)
 }
 
-// generateAPIPathISM generates a URI PATH for a specific resource endpoint and name
+// GetSMConfig performs an HTTP GET request to OS to get the snapshot management policy specified by name
+func (client *OsClusterClient) GetSMConfig(ctx context.Context, name string) (*opensearchapi.Response, error) {
+	path := generateAPIPathISM(smResource, name)
+	return doHTTPGet(ctx, client.client, path)
+}
+
+// CreateSMConfig performs an HTTP POST request to OS to create the snapshot management policy specified by name
+func (client *OsClusterClient) CreateSMConfig(ctx context.Context, name string, body io.Reader) (*opensearchapi.Response, error) {
+	path := generateAPIPathISM(smResource, name)
+	return doHTTPPost(ctx, client.client, path, body)
+}
+
+// UpdateSMConfig performs an HTTP PUT request to OS to update the snapshot management policy specified by name
+func (client *OsClusterClient) UpdateSMConfig(ctx context.Context, name string, seqnumber, primterm int, body io.Reader) (*opensearchapi.Response, error) {
+	path := generateAPIPathUpdateISM(smResource, name, seqnumber, primterm)
+	return doHTTPPut(ctx, client.client, path, body)
+}
+
+// DeleteSMConfig performs an HTTP DELETE request to OS to delete the snapshot management policy specified by name
+func (client *OsClusterClient) DeleteSMConfig(ctx context.Context, name string) (*opensearchapi.Response, error) {
+	path := generateAPIPathISM(smResource, name)
+	return doHTTPDelete(ctx, client.client, path)
+}
+
+// generateAPIPathISM generates a URI PATH for a specific resource endpoint and name, also used for the _sm resource
 // For example: resource = _ism, name = example
 // URI PATH = '_plugins/_ism/policies/example'
 func generateAPIPathISM(resource, name string) strings.Builder {
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+// GetFlatClusterSettingsMap returns the persistent and transient settings of the cluster by their flat name
+func GetFlatClusterSettingsMap(ctx context.Context, service *OsClusterClient) (responses.ClusterSettingsResponse, error) {
+	var path strings.Builder
+	path.WriteString("/_cluster/settings?flat_settings=true")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return responses.ClusterSettingsResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return responses.ClusterSettingsResponse{}, ErrClusterSettingsGetFailed(resp.String())
+	}
+
+	settings := responses.ClusterSettingsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return responses.ClusterSettingsResponse{}, err
+	}
+	return settings, nil
+}
+
+// PutFlatClusterSettings writes the passed settings by their flat name, a nil value resets a setting to its default
+func PutFlatClusterSettings(ctx context.Context, service *OsClusterClient, settings responses.ClusterSettingsResponse) error {
+	var path strings.Builder
+	path.WriteString("/_cluster/settings?flat_settings=true")
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(settings))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update cluster settings: %s", resp.String())
+	}
+	return nil
+}
@@ -1,17 +1,22 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
+	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
 	"github.com/go-logr/logr"
 	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -39,17 +44,18 @@ func HasIndicesWithNoReplica(service *OsClusterClient) (bool, error) {
 }
 
 func HasShardsOnNode(service *OsClusterClient, nodeName string) (bool, error) {
+	count, err := ShardsOnNode(service, nodeName)
+	return count > 0, err
+}
+
+// ShardsOnNode returns the number of shards the node holds, including shards relocating away from it
+func ShardsOnNode(service *OsClusterClient, nodeName string) (int, error) {
 	var headers []string
 	response, err := service.CatShards(headers)
 	if err != nil {
-		return false, err
-	}
-	for _, shardsData := range response {
-		if shardsData.NodeName == nodeName {
-			return true, err
-		}
+		return 0, err
 	}
-	return false, err
+	return helpers.CountShardsOnNode(response, nodeName), nil
 }
 
 func HasIndexPrimariesOnNode(service *OsClusterClient, nodeName string, indices []string) (bool, error) {
@@ -110,8 +116,7 @@ func RemoveExcludeNodeHost(service *OsClusterClient, nodeNameToExclude string) (
 	if !ok || val == "" {
 		return true, err
 	}
-	valAsString := strings.ReplaceAll(val.(string), nodeNameToExclude, "")
-	valAsString = strings.ReplaceAll(valAsString, ",,", ",")
+	valAsString := helpers.RemoveExcludedNode(val.(string), nodeNameToExclude)
 	settings := createClusterSettingsResponseWithExcludeName(valAsString)
 	if err == nil {
 		_, err = service.PutClusterSettings(settings)
@@ -349,7 +354,7 @@ func ShouldUpdateIndexTemplate(
 	if indexTemplateResponse.Name != indexTemplateName {
 		return false, fmt.Errorf("returned index template named '%s' does not equal the requested name '%s'", indexTemplateResponse.Name, indexTemplateName)
 	}
-	if reflect.DeepEqual(indexTemplate, indexTemplateResponse.IndexTemplate) {
+	if helpers.IndexTemplatesEqual(indexTemplate, indexTemplateResponse.IndexTemplate) {
 		return false, nil
 	}
 
@@ -359,6 +364,56 @@ func ShouldUpdateIndexTemplate(
 	return true, nil
 }
 
+// SimulateIndexTemplate validates the template composed from the passed index template and the component templates it
+// is composed of, as if the index template replaced the one of the same name, without changing anything in the cluster
+func SimulateIndexTemplate(
+	ctx context.Context,
+	service *OsClusterClient,
+	indexTemplateName string,
+	indexTemplate requests.IndexTemplate,
+) error {
+	var path strings.Builder
+	path.WriteString("/_index_template/_simulate/")
+	path.WriteString(indexTemplateName)
+
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(indexTemplate))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("index template does not compose to a valid template: %s", resp.String())
+	}
+	return nil
+}
+
+// SimulateIndex returns the settings, mappings and aliases the index would be created with from the index templates
+// of the cluster, without creating it
+func SimulateIndex(ctx context.Context, service *OsClusterClient, indexName string) (responses.SimulateIndexResponse, error) {
+	var path strings.Builder
+	path.WriteString("/_index_template/_simulate_index/")
+	path.WriteString(indexName)
+
+	simulated := responses.SimulateIndexResponse{}
+	resp, err := doHTTPPost(ctx, service.client, path, nil)
+	if err != nil {
+		return simulated, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return simulated, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return simulated, fmt.Errorf("failed to simulate index %s: %s", indexName, resp.String())
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&simulated)
+	return simulated, err
+}
+
 // CreateOrUpdateIndexTemplate creates a new index or updates a pre-existing index template
 func CreateOrUpdateIndexTemplate(
 	ctx context.Context,
@@ -406,61 +461,107 @@ func ComponentTemplatePath(templateName string) strings.Builder {
 
 // ComponentTemplateExists checks if the passed component template already exists or not
 func ComponentTemplateExists(ctx context.Context, service *OsClusterClient, templateName string) (bool, error) {
+	// The template is read right after, the read consumes the cached template
+	if _, exists, ok := service.templateCache.lookup(service.clusterUrl, templateName, false); ok {
+		return exists, nil
+	}
 	path := ComponentTemplatePath(templateName)
-	resp, err := doHTTPHead(ctx, service.client, path)
+	resp, _, err := doHTTPConditional(ctx, service, http.MethodHead, path)
 	if err != nil {
 		return false, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
+	if resp.StatusCode == http.StatusNotModified {
+		return true, nil
+	} else if resp.StatusCode == 404 {
+		service.etagCache.invalidate(service.etagCacheKey(path))
 		return false, nil
+	} else if resp.StatusCode == 403 {
+		return false, ErrRequestForbidden(resp.Status())
 	} else if resp.IsError() {
 		return false, fmt.Errorf("response from API is %s", resp.Status())
 	}
 	return true, nil
 }
 
-// ShouldUpdateComponentTemplate checks whether a previously created component template needs an update or not
-func ShouldUpdateComponentTemplate(
-	ctx context.Context,
-	service *OsClusterClient,
-	componentTemplateName string,
-	componentTemplate requests.ComponentTemplate,
-) (bool, error) {
+// GetComponentTemplate fetches the passed component template, returning nil if it does not exist
+func GetComponentTemplate(ctx context.Context, service *OsClusterClient, componentTemplateName string) (*requests.ComponentTemplate, error) {
+	if template, exists, ok := service.templateCache.lookup(service.clusterUrl, componentTemplateName, true); ok {
+		if !exists {
+			return nil, nil
+		}
+		return &template, nil
+	}
 	path := ComponentTemplatePath(componentTemplateName)
-	resp, err := doHTTPGet(ctx, service.client, path)
+	cacheKey := service.etagCacheKey(path)
+	resp, cached, err := doHTTPConditional(ctx, service, http.MethodGet, path)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return true, nil
+	if resp.StatusCode == http.StatusNotModified {
+		// The template is unchanged since it was cached, skip parsing the body
+		if template, ok := cached.value.(requests.ComponentTemplate); ok {
+			return &template, nil
+		}
+		service.etagCache.invalidate(cacheKey)
+		return nil, fmt.Errorf("component template '%s' is not modified but not cached", componentTemplateName)
+	} else if resp.StatusCode == 404 {
+		service.etagCache.invalidate(cacheKey)
+		return nil, nil
+	} else if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
 	} else if resp.IsError() {
-		return false, fmt.Errorf("response from API is %s", resp.Status())
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
 	}
 
 	componentTemplatesResponse := responses.GetComponentTemplatesResponse{}
 
 	err = json.NewDecoder(resp.Body).Decode(&componentTemplatesResponse)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	// we should not be able to get more than one template in the list, but check to make sure
 	if len(componentTemplatesResponse.ComponentTemplates) != 1 {
-		return false, fmt.Errorf("found %d component templates which fits the name '%s'", len(componentTemplatesResponse.ComponentTemplates), componentTemplateName)
+		return nil, fmt.Errorf("found %d component templates which fits the name '%s'", len(componentTemplatesResponse.ComponentTemplates), componentTemplateName)
 	}
 
 	componentTemplateResponse := componentTemplatesResponse.ComponentTemplates[0]
 
 	// verify the component template name
 	if componentTemplateResponse.Name != componentTemplateName {
-		return false, fmt.Errorf("returned component template named '%s' does not equal the requested name '%s'", componentTemplateResponse.Name, componentTemplateName)
+		return nil, fmt.Errorf("returned component template named '%s' does not equal the requested name '%s'", componentTemplateResponse.Name, componentTemplateName)
+	}
+
+	if etag := resp.Header.Get(headerETag); etag != "" {
+		service.etagCache.put(cacheKey, etag, componentTemplateResponse.ComponentTemplate)
+	} else {
+		service.etagCache.invalidate(cacheKey)
+	}
+
+	return &componentTemplateResponse.ComponentTemplate, nil
+}
+
+// ShouldUpdateComponentTemplate checks whether a previously created component template needs an update or not
+func ShouldUpdateComponentTemplate(
+	ctx context.Context,
+	service *OsClusterClient,
+	componentTemplateName string,
+	componentTemplate requests.ComponentTemplate,
+) (bool, error) {
+	existing, err := GetComponentTemplate(ctx, service, componentTemplateName)
+	if err != nil {
+		return false, err
+	}
+
+	if existing == nil {
+		return true, nil
 	}
 
-	if reflect.DeepEqual(componentTemplate, componentTemplateResponse.ComponentTemplate) {
+	if helpers.ComponentTemplatesEqual(componentTemplate, *existing) {
 		return false, nil
 	}
 
@@ -470,38 +571,1032 @@ func ShouldUpdateComponentTemplate(
 	return true, nil
 }
 
-// CreateOrUpdateComponentTemplate creates a new component or updates a pre-existing component template
-func CreateOrUpdateComponentTemplate(
+// SimulateComponentTemplate validates the settings, mappings and aliases of the passed component template
+// by simulating an index template built from it, without changing anything in the cluster
+func SimulateComponentTemplate(
 	ctx context.Context,
 	service *OsClusterClient,
 	componentTemplateName string,
 	componentTemplate requests.ComponentTemplate,
 ) error {
-	path := ComponentTemplatePath(componentTemplateName)
+	var path strings.Builder
+	path.WriteString("/_index_template/_simulate")
 
-	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(componentTemplate))
+	simulated := requests.IndexTemplate{
+		IndexPatterns: []string{fmt.Sprintf("opensearch-operator-simulate-%s", componentTemplateName)},
+		Template:      componentTemplate.Template,
+	}
+
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(simulated))
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.IsError() {
-		return fmt.Errorf("failed to create component template: %s", resp.String())
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("component template is not valid: %s", resp.String())
 	}
 	return nil
 }
 
-// DeleteComponentTemplate deletes a previously created component template
+// CreateOrUpdateComponentTemplate creates a new component or updates a pre-existing component template. It returns
+// the deprecation warnings OpenSearch reported for the template.
+func CreateOrUpdateComponentTemplate(
+	ctx context.Context,
+	service *OsClusterClient,
+	componentTemplateName string,
+	componentTemplate requests.ComponentTemplate,
+) ([]string, error) {
+	path := ComponentTemplatePath(componentTemplateName)
+	service.etagCache.invalidate(service.etagCacheKey(path))
+	service.templateCache.invalidate(service.clusterUrl, componentTemplateName)
+
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(componentTemplate))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.String())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("failed to create component template: %s", resp.String())
+	}
+	return DeprecationWarnings(resp.Header), nil
+}
+
+// UpdateComponentTemplateIfUnchanged creates or updates the component template only if it is still as expected, a nil
+// expected template requires it not to exist. OpenSearch has no conditional writes for templates, so an update
+// compares the template right before writing it, while a creation uses create=true and is rejected by OpenSearch if
+// the template exists. A template that differs is reported as ErrConcurrentModification. It returns the deprecation
+// warnings OpenSearch reported for the template.
+func UpdateComponentTemplateIfUnchanged(
+	ctx context.Context,
+	service *OsClusterClient,
+	componentTemplateName string,
+	componentTemplate requests.ComponentTemplate,
+	expected *requests.ComponentTemplate,
+) ([]string, error) {
+	if expected != nil {
+		// The comparison has to be with the template in OpenSearch right now
+		service.templateCache.invalidate(service.clusterUrl, componentTemplateName)
+		current, err := GetComponentTemplate(ctx, service, componentTemplateName)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil || !helpers.ComponentTemplatesEqual(*current, *expected) {
+			return nil, ErrComponentTemplateModified(componentTemplateName)
+		}
+		return CreateOrUpdateComponentTemplate(ctx, service, componentTemplateName, componentTemplate)
+	}
+
+	var path strings.Builder
+	path.WriteString("/_component_template/")
+	path.WriteString(componentTemplateName)
+	path.WriteString("?create=true")
+	service.etagCache.invalidate(service.etagCacheKey(ComponentTemplatePath(componentTemplateName)))
+	service.templateCache.invalidate(service.clusterUrl, componentTemplateName)
+
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(componentTemplate))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.String())
+	} else if resp.StatusCode == 400 && strings.Contains(resp.String(), "already exists") {
+		return nil, ErrComponentTemplateModified(componentTemplateName)
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("failed to create component template: %s", resp.String())
+	}
+	return DeprecationWarnings(resp.Header), nil
+}
+
+// DeleteComponentTemplate deletes a previously created component template, a template that does not exist anymore
+// is not an error
 func DeleteComponentTemplate(ctx context.Context, service *OsClusterClient, componentTemplateName string) error {
 	path := ComponentTemplatePath(componentTemplateName)
+	service.etagCache.invalidate(service.etagCacheKey(path))
+	service.templateCache.invalidate(service.clusterUrl, componentTemplateName)
 	resp, err := doHTTPDelete(ctx, service.client, path)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.IsError() {
+	// A component template deleted by someone else since it was checked is deleted just the same
+	if resp.StatusCode == 404 {
+		return nil
+	}
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
 		return fmt.Errorf("response from API is %s", resp.Status())
 	}
 	return nil
 }
+
+// MissingPlugins returns the passed plugins that are not installed on every node of the cluster
+func MissingPlugins(ctx context.Context, service *OsClusterClient, plugins []string) ([]string, error) {
+	var path strings.Builder
+	path.WriteString("/_nodes/plugins")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	nodesPlugins := responses.NodesPluginsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&nodesPlugins); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, plugin := range plugins {
+		for _, node := range nodesPlugins.Nodes {
+			installed := false
+			for _, nodePlugin := range node.Plugins {
+				if nodePlugin.Name == plugin {
+					installed = true
+					break
+				}
+			}
+			if !installed {
+				missing = append(missing, plugin)
+				break
+			}
+		}
+	}
+	return missing, nil
+}
+
+// ExistingIndices returns the passed names that are names of concrete indices in the cluster, sorted. Aliases and
+// data streams are not resolved, only exact index names match. Only the passed names are looked up.
+func ExistingIndices(ctx context.Context, service *OsClusterClient, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var path strings.Builder
+	path.WriteString("/_cat/indices/")
+	path.WriteString(strings.Join(names, ","))
+	path.WriteString("?format=json&h=index&expand_wildcards=all&ignore_unavailable=true")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, ErrCatIndicesFailed(resp.String())
+	}
+
+	var indices []responses.CatIndicesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&indices); err != nil {
+		return nil, err
+	}
+	requested := map[string]bool{}
+	for _, name := range names {
+		requested[name] = true
+	}
+	var existing []string
+	for _, index := range indices {
+		if requested[index.Index] {
+			existing = append(existing, index.Index)
+		}
+	}
+	sort.Strings(existing)
+	return existing, nil
+}
+
+// ComponentTemplateReferenced returns true if an index template listed by _cat/templates is composed of the
+// component template. It is the fallback for users that may not read component templates: OpenSearch only accepts
+// index templates composed of existing component templates, so a referenced component template exists, while one
+// that is not referenced may exist or not.
+func ComponentTemplateReferenced(ctx context.Context, service *OsClusterClient, componentTemplateName string) (bool, error) {
+	var path strings.Builder
+	path.WriteString("/_cat/templates?format=json&h=name,composed_of")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return false, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	var templates []responses.CatTemplatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&templates); err != nil {
+		return false, err
+	}
+	for _, template := range templates {
+		// composed_of is listed as [first, second]
+		for _, name := range strings.Split(strings.Trim(template.ComposedOf, "[]"), ",") {
+			if strings.TrimSpace(name) == componentTemplateName {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// NodesWithAttribute returns the names of the nodes that have the custom node attribute set to value
+func NodesWithAttribute(ctx context.Context, service *OsClusterClient, attribute string, value string) ([]string, error) {
+	var path strings.Builder
+	path.WriteString("/_cat/nodeattrs?format=json&h=node,attr,value")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	var nodeAttrs []responses.CatNodeAttrsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&nodeAttrs); err != nil {
+		return nil, err
+	}
+
+	var nodes []string
+	for _, nodeAttr := range nodeAttrs {
+		if nodeAttr.Attr == attribute && nodeAttr.Value == value {
+			nodes = append(nodes, nodeAttr.Node)
+		}
+	}
+	return nodes, nil
+}
+
+// NodeVersions returns the distinct OpenSearch versions the nodes of the cluster run, sorted. More than one version
+// means the nodes are being upgraded one after another.
+func NodeVersions(ctx context.Context, service *OsClusterClient) ([]string, error) {
+	var path strings.Builder
+	path.WriteString("/_cat/nodes?format=json&h=name,version")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	var nodes []responses.CatNodesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&nodes); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var versions []string
+	for _, node := range nodes {
+		if node.Version == "" || seen[node.Version] {
+			continue
+		}
+		seen[node.Version] = true
+		versions = append(versions, node.Version)
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// ClusterSetting returns the effective value of a cluster setting, which is its transient value, else its persistent
+// value, else its default. An empty value is returned for settings the cluster does not know.
+func ClusterSetting(ctx context.Context, service *OsClusterClient, name string) (string, error) {
+	var path strings.Builder
+	path.WriteString("/_cluster/settings?flat_settings=true&include_defaults=true")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return "", ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return "", ErrClusterSettingsGetFailed(resp.String())
+	}
+
+	settings := responses.ClusterSettingsWithDefaultsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return "", err
+	}
+	for _, values := range []map[string]interface{}{settings.Transient, settings.Persistent, settings.Defaults} {
+		if value, ok := values[name]; ok {
+			return fmt.Sprint(value), nil
+		}
+	}
+	return "", nil
+}
+
+// SnapshotRepositories returns the names of the snapshot repositories registered in the cluster, sorted
+func SnapshotRepositories(ctx context.Context, service *OsClusterClient) ([]string, error) {
+	var path strings.Builder
+	path.WriteString("/_snapshot")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	repositories := map[string]json.RawMessage{}
+	if err := json.NewDecoder(resp.Body).Decode(&repositories); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(repositories))
+	for name := range repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LatestSuccessfulSnapshot returns when the latest successful snapshot of the repository ended, the zero time if the
+// repository holds none
+func LatestSuccessfulSnapshot(ctx context.Context, service *OsClusterClient, repository string) (time.Time, error) {
+	var path strings.Builder
+	path.WriteString("/_snapshot/")
+	path.WriteString(url.PathEscape(repository))
+	path.WriteString("/_all")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return time.Time{}, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return time.Time{}, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	snapshots := responses.SnapshotsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&snapshots); err != nil {
+		return time.Time{}, err
+	}
+	var latest time.Time
+	for _, snapshot := range snapshots.Snapshots {
+		if snapshot.State != "SUCCESS" {
+			continue
+		}
+		if ended := time.UnixMilli(snapshot.EndTimeInMillis); ended.After(latest) {
+			latest = ended
+		}
+	}
+	return latest, nil
+}
+
+// Ping checks that the cluster responds to a HEAD request of its root, which also serverless endpoints without the
+// cluster health API answer
+func Ping(ctx context.Context, service *OsClusterClient) error {
+	var path strings.Builder
+	path.WriteString("/")
+
+	resp, err := doHTTPHead(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("ping failed: %s", resp.Status())
+	}
+	return nil
+}
+
+// ClusterHealth returns the health of the cluster as reported by the _cluster/health API
+func ClusterHealth(ctx context.Context, service *OsClusterClient) (responses.ClusterHealthResponse, error) {
+	var path strings.Builder
+	path.WriteString("/_cluster/health")
+
+	health := responses.ClusterHealthResponse{}
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return health, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return health, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return health, ErrClusterHealthGetFailed(resp.String())
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&health)
+	return health, err
+}
+
+// IndexPath returns a strings.Builder pointing to /<indexName>
+func IndexPath(indexName string) strings.Builder {
+	var path strings.Builder
+	path.Grow(1 + len(indexName))
+	path.WriteString("/")
+	path.WriteString(indexName)
+	return path
+}
+
+// CreateNewIndex creates the index with the passed body. It fails if the index already exists, so an existing index
+// is never modified.
+func CreateNewIndex(ctx context.Context, service *OsClusterClient, indexName string, body interface{}) error {
+	path := IndexPath(indexName)
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to create index %s: %s", indexName, resp.String())
+	}
+	return nil
+}
+
+// IndexDocument indexes the document into the index and refreshes the index, so the mappings it adds are visible. A
+// document OpenSearch does not accept, e.g. because it does not match the mappings, fails with ErrDocumentRejected.
+func IndexDocument(ctx context.Context, service *OsClusterClient, indexName string, document *apiextensionsv1.JSON) error {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_doc?refresh=true")
+	resp, err := doHTTPPost(ctx, service.client, path, bytes.NewReader(document.Raw))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.StatusCode == 400 {
+		rejection := responses.ErrorResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(&rejection); err != nil || rejection.Error.Reason == "" {
+			return ErrDocumentRejectedBy(resp.Status())
+		}
+		return ErrDocumentRejectedBy(fmt.Sprintf("%s: %s", rejection.Error.Type, rejection.Error.Reason))
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to index document into %s: %s", indexName, resp.String())
+	}
+	return nil
+}
+
+// GetIndexSettings returns the settings of the index in their flat notation, including the defaults OpenSearch set
+// explicitly on the index
+func GetIndexSettings(ctx context.Context, service *OsClusterClient, indexName string) (map[string]interface{}, error) {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_settings?flat_settings=true")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	settingsResponse := responses.GetIndexSettingsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&settingsResponse); err != nil {
+		return nil, err
+	}
+	index, ok := settingsResponse[indexName]
+	if !ok {
+		return nil, fmt.Errorf("no settings returned for index %s", indexName)
+	}
+	return index.Settings, nil
+}
+
+// GetIndexMappings returns the mappings of the index
+func GetIndexMappings(ctx context.Context, service *OsClusterClient, indexName string) (*apiextensionsv1.JSON, error) {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_mapping")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	mappingsResponse := responses.GetIndexMappingsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&mappingsResponse); err != nil {
+		return nil, err
+	}
+	index, ok := mappingsResponse[indexName]
+	if !ok {
+		return nil, fmt.Errorf("no mappings returned for index %s", indexName)
+	}
+	return index.Mappings, nil
+}
+
+// DeleteIndexIfExists deletes the index, an index that does not exist is ignored
+func DeleteIndexIfExists(ctx context.Context, service *OsClusterClient, indexName string) error {
+	path := IndexPath(indexName)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
+
+// IndexExists returns true if an index or alias with the name exists
+func IndexExists(ctx context.Context, service *OsClusterClient, indexName string) (bool, error) {
+	path := IndexPath(indexName)
+	resp, err := doHTTPHead(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return false, nil
+	} else if resp.StatusCode == 403 {
+		return false, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return true, nil
+}
+
+// PutIndexSettings updates the dynamic settings of the index, the settings are passed in flat notation
+func PutIndexSettings(ctx context.Context, service *OsClusterClient, indexName string, settings map[string]interface{}) error {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_settings")
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(settings))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to update the settings of index %s: %s", indexName, resp.String())
+	}
+	return nil
+}
+
+// PutIndexMappings adds the mappings to the index. OpenSearch merges them into the existing mappings and rejects
+// changes of the type of existing fields
+func PutIndexMappings(ctx context.Context, service *OsClusterClient, indexName string, mappings *apiextensionsv1.JSON) error {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_mapping")
+	resp, err := doHTTPPut(ctx, service.client, path, bytes.NewReader(mappings.Raw))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to update the mappings of index %s: %s", indexName, resp.String())
+	}
+	return nil
+}
+
+// IndexAliases returns the definitions of the aliases of the index by alias name
+func IndexAliases(ctx context.Context, service *OsClusterClient, indexName string) (map[string]*apiextensionsv1.JSON, error) {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_alias")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("failed to get the aliases of index %s: %s", indexName, resp.String())
+	}
+
+	indices := map[string]responses.IndexAliasDefinitionsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&indices); err != nil {
+		return nil, err
+	}
+	aliases := indices[indexName].Aliases
+	if aliases == nil {
+		aliases = map[string]*apiextensionsv1.JSON{}
+	}
+	return aliases, nil
+}
+
+// UpdateIndexAliases adds the aliases to the index, replacing aliases of the same name, and removes the aliases in
+// remove from it in a single update of the aliases
+func UpdateIndexAliases(ctx context.Context, service *OsClusterClient, indexName string, add map[string]requests.IndexAlias, remove []string) error {
+	var actions []map[string]interface{}
+	names := make([]string, 0, len(add))
+	for name := range add {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		actions = append(actions, aliasAddAction(indexName, name, add[name]))
+	}
+	for _, name := range remove {
+		actions = append(actions, map[string]interface{}{"remove": map[string]interface{}{"index": indexName, "alias": name}})
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	return updateAliases(ctx, service, actions)
+}
+
+// IndexCodecs returns the index.codec of the open indices matching the patterns by index name, indices without a
+// codec use the default codec
+func IndexCodecs(ctx context.Context, service *OsClusterClient, patterns []string) (map[string]string, error) {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(strings.Join(patterns, ","))
+	path.WriteString("/_settings/index.codec?flat_settings=true&ignore_unavailable=true")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	settingsResponse := responses.GetIndexSettingsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&settingsResponse); err != nil {
+		return nil, err
+	}
+	codecs := make(map[string]string, len(settingsResponse))
+	for index, settings := range settingsResponse {
+		codec, _ := settings.Settings["index.codec"].(string)
+		if codec == "" {
+			codec = "default"
+		}
+		codecs[index] = codec
+	}
+	return codecs, nil
+}
+
+// CloseIndex closes the index, it cannot be written to or searched until it is opened again
+func CloseIndex(ctx context.Context, service *OsClusterClient, indexName string) error {
+	return postIndexAction(ctx, service, indexName, "_close")
+}
+
+// OpenIndex opens the closed index
+func OpenIndex(ctx context.Context, service *OsClusterClient, indexName string) error {
+	return postIndexAction(ctx, service, indexName, "_open")
+}
+
+func postIndexAction(ctx context.Context, service *OsClusterClient, indexName string, action string) error {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/")
+	path.WriteString(action)
+	resp, err := doHTTPPost(ctx, service.client, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to %s index %s: %s", strings.TrimPrefix(action, "_"), indexName, resp.String())
+	}
+	return nil
+}
+
+// SetIndexCodec changes the index.codec of the closed index
+func SetIndexCodec(ctx context.Context, service *OsClusterClient, indexName string, codec string) error {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_settings")
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(map[string]string{"index.codec": codec}))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to set the codec of index %s: %s", indexName, resp.String())
+	}
+	return nil
+}
+
+// ForceMergeIndex starts merging the segments of the index into a single segment and returns the task merging them
+func ForceMergeIndex(ctx context.Context, service *OsClusterClient, indexName string) (string, error) {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_forcemerge?max_num_segments=1&wait_for_completion=false")
+	resp, err := doHTTPPost(ctx, service.client, path, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return "", ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return "", fmt.Errorf("failed to force merge index %s: %s", indexName, resp.String())
+	}
+
+	task := responses.AsyncTaskResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return "", err
+	}
+	if task.Task == "" {
+		return "", fmt.Errorf("no task returned for the force merge of index %s", indexName)
+	}
+	return task.Task, nil
+}
+
+// GetTask returns the task as reported by the _tasks API
+func GetTask(ctx context.Context, service *OsClusterClient, taskID string) (responses.TaskResponse, error) {
+	var path strings.Builder
+	path.WriteString("/_tasks/")
+	path.WriteString(taskID)
+
+	task := responses.TaskResponse{}
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return task, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return task, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return task, fmt.Errorf("failed to get task %s: %s", taskID, resp.String())
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&task)
+	return task, err
+}
+
+// AliasIndices returns the indices of the alias and whether the alias marks them as its write index, it returns none
+// if the alias does not exist
+func AliasIndices(ctx context.Context, service *OsClusterClient, alias string) (map[string]bool, error) {
+	var path strings.Builder
+	path.WriteString("/_alias/")
+	path.WriteString(alias)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return map[string]bool{}, nil
+	} else if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("failed to get alias %s: %s", alias, resp.String())
+	}
+
+	indices := map[string]responses.IndexAliasesResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&indices); err != nil {
+		return nil, err
+	}
+	writeIndices := map[string]bool{}
+	for index, aliases := range indices {
+		isWriteIndex := aliases.Aliases[alias].IsWriteIndex
+		writeIndices[index] = isWriteIndex != nil && *isWriteIndex
+	}
+	return writeIndices, nil
+}
+
+// SwapWriteIndex makes the index to the write index of the alias in a single update of the aliases, the index from
+// stays in the alias without being its write index
+func SwapWriteIndex(ctx context.Context, service *OsClusterClient, alias string, from string, to string) error {
+	return updateAliases(ctx, service, []map[string]interface{}{
+		{"add": map[string]interface{}{"index": from, "alias": alias, "is_write_index": false}},
+		{"add": map[string]interface{}{"index": to, "alias": alias, "is_write_index": true}},
+	})
+}
+
+// RemoveIndexFromAlias removes the index from the alias
+func RemoveIndexFromAlias(ctx context.Context, service *OsClusterClient, alias string, index string) error {
+	return updateAliases(ctx, service, []map[string]interface{}{
+		{"remove": map[string]interface{}{"index": index, "alias": alias}},
+	})
+}
+
+func updateAliases(ctx context.Context, service *OsClusterClient, actions []map[string]interface{}) error {
+	var path strings.Builder
+	path.WriteString("/_aliases")
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(map[string]interface{}{"actions": actions}))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to update aliases: %s", resp.String())
+	}
+	return nil
+}
+
+// AliasDefinitions returns the definitions of the alias by the names of the indices it points to, an alias that
+// does not exist points to no indices
+func AliasDefinitions(ctx context.Context, service *OsClusterClient, alias string) (map[string]*apiextensionsv1.JSON, error) {
+	var path strings.Builder
+	path.WriteString("/_alias/")
+	path.WriteString(alias)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return map[string]*apiextensionsv1.JSON{}, nil
+	} else if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("failed to get alias %s: %s", alias, resp.String())
+	}
+
+	indices := map[string]responses.IndexAliasDefinitionsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&indices); err != nil {
+		return nil, err
+	}
+	definitions := make(map[string]*apiextensionsv1.JSON, len(indices))
+	for index, aliases := range indices {
+		definitions[index] = aliases.Aliases[alias]
+	}
+	return definitions, nil
+}
+
+// UpdateAlias adds the alias to the indices in add, replacing its definition on indices it already points to, and
+// removes it from the indices in remove in a single update of the aliases, so the alias switches atomically
+func UpdateAlias(ctx context.Context, service *OsClusterClient, alias string, add map[string]requests.IndexAlias, remove []string) error {
+	var actions []map[string]interface{}
+	indices := make([]string, 0, len(add))
+	for index := range add {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	for _, index := range indices {
+		actions = append(actions, aliasAddAction(index, alias, add[index]))
+	}
+	for _, index := range remove {
+		actions = append(actions, map[string]interface{}{"remove": map[string]interface{}{"index": index, "alias": alias}})
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	return updateAliases(ctx, service, actions)
+}
+
+// DeleteAlias removes the alias from all indices, an alias that does not exist is ignored
+func DeleteAlias(ctx context.Context, service *OsClusterClient, alias string) error {
+	var path strings.Builder
+	path.WriteString("/_all/_alias/")
+	path.WriteString(alias)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to delete alias %s: %s", alias, resp.String())
+	}
+	return nil
+}
+
+// aliasAddAction returns the action of the _aliases API adding the alias with the definition to the index. The write
+// index flag is always sent, so an index stops being the write index when the definition no longer sets it
+func aliasAddAction(index string, alias string, definition requests.IndexAlias) map[string]interface{} {
+	action := map[string]interface{}{"index": index, "alias": alias, "is_write_index": definition.IsWriteIndex}
+	if definition.Filter.Size() > 0 {
+		action["filter"] = definition.Filter
+	}
+	if definition.Routing != "" {
+		action["routing"] = definition.Routing
+	}
+	if definition.IndexRouting != "" {
+		action["index_routing"] = definition.IndexRouting
+	}
+	if definition.SearchRouting != "" {
+		action["search_routing"] = definition.SearchRouting
+	}
+	return map[string]interface{}{"add": action}
+}
+
+// Reindex starts copying the documents of the source index into the dest index and returns the task copying them.
+// Documents dest already holds are kept, and dest is refreshed once all documents are copied.
+func Reindex(ctx context.Context, service *OsClusterClient, source string, dest string) (string, error) {
+	var path strings.Builder
+	path.WriteString("/_reindex?wait_for_completion=false&refresh=true")
+	body := map[string]interface{}{
+		"conflicts": "proceed",
+		"source":    map[string]interface{}{"index": source},
+		"dest":      map[string]interface{}{"index": dest, "op_type": "create"},
+	}
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return "", ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return "", fmt.Errorf("failed to reindex index %s into %s: %s", source, dest, resp.String())
+	}
+
+	task := responses.AsyncTaskResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return "", err
+	}
+	if task.Task == "" {
+		return "", fmt.Errorf("no task returned for the reindex of index %s", source)
+	}
+	return task.Task, nil
+}
+
+// CountDocuments returns the number of searchable documents of the index
+func CountDocuments(ctx context.Context, service *OsClusterClient, indexName string) (int64, error) {
+	var path strings.Builder
+	path.WriteString("/")
+	path.WriteString(indexName)
+	path.WriteString("/_count")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return 0, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return 0, fmt.Errorf("failed to count the documents of index %s: %s", indexName, resp.String())
+	}
+
+	count := responses.CountResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&count)
+	return count.Count, err
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+)
+
+// DataStreamPath returns a strings.Builder pointing to /_data_stream/<dataStreamName>
+func DataStreamPath(dataStreamName string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_data_stream/") + len(dataStreamName))
+	path.WriteString("/_data_stream/")
+	path.WriteString(dataStreamName)
+	return path
+}
+
+// DataStreamExists checks if the passed data stream already exists or not
+func DataStreamExists(ctx context.Context, service *OsClusterClient, dataStreamName string) (bool, error) {
+	path := DataStreamPath(dataStreamName)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return false, nil
+	} else if resp.StatusCode == 403 {
+		return false, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return true, nil
+}
+
+// CreateDataStream creates the data stream with its first backing index from the index template matching its name
+func CreateDataStream(ctx context.Context, service *OsClusterClient, dataStreamName string) error {
+	path := DataStreamPath(dataStreamName)
+	resp, err := doHTTPPut(ctx, service.client, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to create data stream %s: %s", dataStreamName, resp.String())
+	}
+	return nil
+}
+
+// DeleteDataStream deletes the data stream with all its backing indices, a data stream that does not exist is ignored
+func DeleteDataStream(ctx context.Context, service *OsClusterClient, dataStreamName string) error {
+	path := DataStreamPath(dataStreamName)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.StatusCode == 403 {
+		return ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
+
+// IndexTemplates returns all index templates of the cluster by name
+func IndexTemplates(ctx context.Context, service *OsClusterClient) (map[string]requests.IndexTemplate, error) {
+	var path strings.Builder
+	path.WriteString("/_index_template")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return map[string]requests.IndexTemplate{}, nil
+	} else if resp.StatusCode == 403 {
+		return nil, ErrRequestForbidden(resp.Status())
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	indexTemplatesResponse := responses.GetIndexTemplatesResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&indexTemplatesResponse); err != nil {
+		return nil, err
+	}
+	templates := make(map[string]requests.IndexTemplate, len(indexTemplatesResponse.IndexTemplates))
+	for _, template := range indexTemplatesResponse.IndexTemplates {
+		templates[template.Name] = template.IndexTemplate
+	}
+	return templates, nil
+}
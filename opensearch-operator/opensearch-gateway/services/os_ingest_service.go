@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// IngestPipelinePath returns a strings.Builder pointing to /_ingest/pipeline/<pipelineName>
+func IngestPipelinePath(pipelineName string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_ingest/pipeline/") + len(pipelineName))
+	path.WriteString("/_ingest/pipeline/")
+	path.WriteString(pipelineName)
+	return path
+}
+
+// IngestPipelineExists checks if the passed ingest pipeline already exists or not
+func IngestPipelineExists(ctx context.Context, service *OsClusterClient, pipelineName string) (bool, error) {
+	path := IngestPipelinePath(pipelineName)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return false, nil
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return true, nil
+}
+
+// ShouldUpdateIngestPipeline checks whether a previously created ingest pipeline needs an update or not
+func ShouldUpdateIngestPipeline(
+	ctx context.Context,
+	service *OsClusterClient,
+	pipelineName string,
+	pipeline requests.IngestPipeline,
+) (bool, error) {
+	path := IngestPipelinePath(pipelineName)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return true, nil
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	pipelinesResponse := responses.GetIngestPipelinesResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&pipelinesResponse)
+	if err != nil {
+		return false, err
+	}
+
+	existing, ok := pipelinesResponse[pipelineName]
+	if !ok {
+		return false, fmt.Errorf("returned ingest pipelines do not include the requested name '%s'", pipelineName)
+	}
+	if helpers.IngestPipelinesEqual(pipeline, existing) {
+		return false, nil
+	}
+
+	lg := log.FromContext(ctx)
+	lg.Info("OpenSearch ingest pipeline requires update")
+
+	return true, nil
+}
+
+// CreateOrUpdateIngestPipeline creates a new ingest pipeline or updates a pre-existing one
+func CreateOrUpdateIngestPipeline(
+	ctx context.Context,
+	service *OsClusterClient,
+	pipelineName string,
+	pipeline requests.IngestPipeline,
+) error {
+	path := IngestPipelinePath(pipelineName)
+
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(pipeline))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to create ingest pipeline: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteIngestPipeline deletes a previously created ingest pipeline
+func DeleteIngestPipeline(ctx context.Context, service *OsClusterClient, pipelineName string) error {
+	path := IngestPipelinePath(pipelineName)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
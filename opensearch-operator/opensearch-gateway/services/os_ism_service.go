@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
 
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
 	"github.com/opensearch-project/opensearch-go/opensearchutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -104,3 +107,89 @@ func DeleteISMPolicy(ctx context.Context, service *OsClusterClient, policyName s
 	}
 	return nil
 }
+
+// ISMIndexBatchSize is the maximum number of indices passed to a single add or remove policy request
+const ISMIndexBatchSize = 50
+
+// ListIndicesMatching returns the names of all open and closed indices matching one of the passed index patterns
+func ListIndicesMatching(ctx context.Context, service *OsClusterClient, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	var path strings.Builder
+	path.WriteString("/_cat/indices/")
+	path.WriteString(strings.Join(patterns, ","))
+	path.WriteString("?format=json&h=index&expand_wildcards=open,closed")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == 404 {
+		return nil, nil
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	var indices []responses.CatIndicesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&indices); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(indices))
+	for _, index := range indices {
+		names = append(names, index.Index)
+	}
+	return names, nil
+}
+
+// AddISMPolicyToIndices attaches the policy to the passed indices in batches of ISMIndexBatchSize.
+// It returns the indices the policy could not be attached to, mapped to the reason.
+func AddISMPolicyToIndices(ctx context.Context, service *OsClusterClient, policyId string, indices []string) (map[string]string, error) {
+	body := map[string]string{"policy_id": policyId}
+	return changeISMIndices(ctx, service, "add", indices, body)
+}
+
+// RemoveISMPolicyFromIndices detaches the policies from the passed indices in batches of ISMIndexBatchSize.
+// It returns the indices the policy could not be detached from, mapped to the reason.
+func RemoveISMPolicyFromIndices(ctx context.Context, service *OsClusterClient, indices []string) (map[string]string, error) {
+	return changeISMIndices(ctx, service, "remove", indices, nil)
+}
+
+func changeISMIndices(ctx context.Context, service *OsClusterClient, action string, indices []string, body interface{}) (map[string]string, error) {
+	failed := map[string]string{}
+	for start := 0; start < len(indices); start += ISMIndexBatchSize {
+		end := start + ISMIndexBatchSize
+		if end > len(indices) {
+			end = len(indices)
+		}
+		var path strings.Builder
+		path.WriteString("/_plugins/_ism/")
+		path.WriteString(action)
+		path.WriteString("/")
+		path.WriteString(strings.Join(indices[start:end], ","))
+
+		var reader io.Reader
+		if body != nil {
+			reader = opensearchutil.NewJSONReader(body)
+		}
+		resp, err := doHTTPPost(ctx, service.client, path, reader)
+		if err != nil {
+			return nil, err
+		}
+		if resp.IsError() {
+			err = fmt.Errorf("failed to %s ism policy: %s", action, resp.String())
+			resp.Body.Close()
+			return nil, err
+		}
+		changeResponse := responses.ISMChangeIndicesResponse{}
+		err = json.NewDecoder(resp.Body).Decode(&changeResponse)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, failure := range changeResponse.FailedIndices {
+			failed[failure.IndexName] = failure.Reason
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// NotificationChannelPath returns a strings.Builder pointing to /_plugins/_notifications/configs/<channelID>, or to
+// /_plugins/_notifications/configs if the id is empty
+func NotificationChannelPath(channelID string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_plugins/_notifications/configs/") + len(channelID))
+	path.WriteString("/_plugins/_notifications/configs")
+	if channelID != "" {
+		path.WriteString("/")
+		path.WriteString(url.PathEscape(channelID))
+	}
+	return path
+}
+
+// GetNotificationChannel fetches the config of the channel with the passed id, ErrNotFound if it does not exist
+func GetNotificationChannel(ctx context.Context, service *OsClusterClient, channelID string) (*apiextensionsv1.JSON, error) {
+	path := NotificationChannelPath(channelID)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	channelResponse := responses.GetNotificationChannelResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&channelResponse); err != nil {
+		return nil, err
+	}
+	for _, config := range channelResponse.ConfigList {
+		if config.ConfigID == channelID {
+			return &config.Config, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// NotificationChannelExists checks if the channel with the passed id already exists or not
+func NotificationChannelExists(ctx context.Context, service *OsClusterClient, channelID string) (bool, error) {
+	_, err := GetNotificationChannel(ctx, service, channelID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CreateNotificationChannel creates a channel with the passed id
+func CreateNotificationChannel(ctx context.Context, service *OsClusterClient, channelID string, config requests.NotificationChannelConfig) error {
+	path := NotificationChannelPath("")
+	body := requests.NotificationChannel{ConfigID: channelID, Config: config}
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to create notification channel: %s", resp.String())
+	}
+	return nil
+}
+
+// UpdateNotificationChannel replaces the config of the channel with the passed id
+func UpdateNotificationChannel(ctx context.Context, service *OsClusterClient, channelID string, config requests.NotificationChannelConfig) error {
+	path := NotificationChannelPath(channelID)
+	body := requests.NotificationChannel{Config: config}
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update notification channel: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteNotificationChannel deletes the channel with the passed id
+func DeleteNotificationChannel(ctx context.Context, service *OsClusterClient, channelID string) error {
+	path := NotificationChannelPath(channelID)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+)
+
+// GetRemoteClusterInfo returns the state of the connections to remote clusters by their alias
+func GetRemoteClusterInfo(ctx context.Context, service *OsClusterClient) (map[string]responses.RemoteClusterInfo, error) {
+	var path strings.Builder
+	path.WriteString("/_remote/info")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	info := map[string]responses.RemoteClusterInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+const replicationPath = "/_plugins/_replication"
+
+// GetAutoFollowRules returns the auto-follow rules of the cluster
+func GetAutoFollowRules(ctx context.Context, service *OsClusterClient) ([]responses.AutoFollowRuleStats, error) {
+	var path strings.Builder
+	path.WriteString(replicationPath)
+	path.WriteString("/autofollow_stats")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	statsResponse := responses.AutoFollowStatsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&statsResponse); err != nil {
+		return nil, err
+	}
+	return statsResponse.AutoFollowStats, nil
+}
+
+// CreateAutoFollowRule creates the passed auto-follow rule, which starts the replication of the matching indices
+func CreateAutoFollowRule(ctx context.Context, service *OsClusterClient, rule requests.AutoFollowRule) error {
+	var path strings.Builder
+	path.WriteString(replicationPath)
+	path.WriteString("/_autofollow")
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(rule))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to create auto-follow rule: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteAutoFollowRule deletes the auto-follow rule with the passed name, a rule that does not exist is ignored.
+// Indices the rule already replicates keep being replicated
+func DeleteAutoFollowRule(ctx context.Context, service *OsClusterClient, leaderAlias string, name string) error {
+	var path strings.Builder
+	path.WriteString(replicationPath)
+	path.WriteString("/_autofollow")
+	rule := requests.AutoFollowRule{LeaderAlias: leaderAlias, Name: name}
+	resp, err := doHTTPDeleteWithBody(ctx, service.client, path, opensearchutil.NewJSONReader(rule))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to delete auto-follow rule: %s", resp.String())
+	}
+	return nil
+}
+
+// GetFollowerIndices returns the sorted names of the indices the cluster replicates
+func GetFollowerIndices(ctx context.Context, service *OsClusterClient) ([]string, error) {
+	var path strings.Builder
+	path.WriteString(replicationPath)
+	path.WriteString("/follower_stats")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	statsResponse := responses.FollowerStatsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&statsResponse); err != nil {
+		return nil, err
+	}
+	indices := make([]string, 0, len(statsResponse.IndexStats))
+	for index := range statsResponse.IndexStats {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	return indices, nil
+}
+
+// GetReplicationStatus returns the state of the replication of the passed follower index
+func GetReplicationStatus(ctx context.Context, service *OsClusterClient, index string) (*responses.ReplicationStatusResponse, error) {
+	var path strings.Builder
+	path.WriteString(replicationPath)
+	path.WriteString("/")
+	path.WriteString(url.PathEscape(index))
+	path.WriteString("/_status")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	statusResponse := responses.ReplicationStatusResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&statusResponse); err != nil {
+		return nil, err
+	}
+	return &statusResponse, nil
+}
+
+// StopReplication stops the replication of the passed follower index, which turns it into a regular index
+func StopReplication(ctx context.Context, service *OsClusterClient, index string) error {
+	var path strings.Builder
+	path.WriteString(replicationPath)
+	path.WriteString("/")
+	path.WriteString(url.PathEscape(index))
+	path.WriteString("/_stop")
+	resp, err := doHTTPPost(ctx, service.client, path, strings.NewReader("{}"))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to stop replication: %s", resp.String())
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+// RollupJobPath returns a strings.Builder pointing to /_plugins/_rollup/jobs/<jobID>
+func RollupJobPath(jobID string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_plugins/_rollup/jobs/") + len(jobID))
+	path.WriteString("/_plugins/_rollup/jobs/")
+	path.WriteString(url.PathEscape(jobID))
+	return path
+}
+
+// GetRollupJob fetches the rollup job with the passed id, ErrNotFound if it does not exist
+func GetRollupJob(ctx context.Context, service *OsClusterClient, jobID string) (*responses.GetRollupJobResponse, error) {
+	path := RollupJobPath(jobID)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	jobResponse := responses.GetRollupJobResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&jobResponse); err != nil {
+		return nil, err
+	}
+	return &jobResponse, nil
+}
+
+// CreateRollupJob creates the passed rollup job
+func CreateRollupJob(ctx context.Context, service *OsClusterClient, jobID string, job requests.RollupJob) error {
+	path := RollupJobPath(jobID)
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(job))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to create rollup job: %s", resp.String())
+	}
+	return nil
+}
+
+// UpdateRollupJob updates the rollup job with the passed id if it still has the sequence number and primary term it
+// was read with
+func UpdateRollupJob(ctx context.Context, service *OsClusterClient, jobID string, seqno, primterm int, job requests.RollupJob) error {
+	var path strings.Builder
+	jobPath := RollupJobPath(jobID)
+	path.WriteString(jobPath.String())
+	path.WriteString("?if_seq_no=")
+	path.WriteString(strconv.Itoa(seqno))
+	path.WriteString("&if_primary_term=")
+	path.WriteString(strconv.Itoa(primterm))
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(job))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update rollup job: %s", resp.String())
+	}
+	return nil
+}
+
+// StartRollupJob starts the rollup job with the passed id, a job that already finished is started again
+func StartRollupJob(ctx context.Context, service *OsClusterClient, jobID string) error {
+	return rollupJobAction(ctx, service, jobID, "_start")
+}
+
+// StopRollupJob stops the rollup job with the passed id
+func StopRollupJob(ctx context.Context, service *OsClusterClient, jobID string) error {
+	return rollupJobAction(ctx, service, jobID, "_stop")
+}
+
+func rollupJobAction(ctx context.Context, service *OsClusterClient, jobID string, action string) error {
+	var path strings.Builder
+	jobPath := RollupJobPath(jobID)
+	path.WriteString(jobPath.String())
+	path.WriteString("/")
+	path.WriteString(action)
+	resp, err := doHTTPPost(ctx, service.client, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to %s rollup job: %s", strings.TrimPrefix(action, "_"), resp.String())
+	}
+	return nil
+}
+
+// DeleteRollupJob deletes the rollup job with the passed id, a job that does not exist is ignored. The target index
+// with the rolled up documents is kept
+func DeleteRollupJob(ctx context.Context, service *OsClusterClient, jobID string) error {
+	path := RollupJobPath(jobID)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// StoredScriptPath returns a strings.Builder pointing to /_scripts/<scriptID>
+func StoredScriptPath(scriptID string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_scripts/") + len(scriptID))
+	path.WriteString("/_scripts/")
+	path.WriteString(url.PathEscape(scriptID))
+	return path
+}
+
+// GetStoredScript fetches the stored script with the passed id, ErrNotFound if it does not exist
+func GetStoredScript(ctx context.Context, service *OsClusterClient, scriptID string) (*requests.StoredScript, error) {
+	path := StoredScriptPath(scriptID)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	scriptResponse := responses.GetStoredScriptResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&scriptResponse); err != nil {
+		return nil, err
+	}
+	if !scriptResponse.Found {
+		return nil, ErrNotFound
+	}
+	return &scriptResponse.Script, nil
+}
+
+// PutStoredScript creates or replaces the stored script with the passed id. OpenSearch compiles painless scripts
+// when they are stored, the error holds the reason a script was rejected
+func PutStoredScript(ctx context.Context, service *OsClusterClient, scriptID string, script requests.StoredScript) error {
+	path := StoredScriptPath(scriptID)
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(requests.StoredScriptRequest{Script: script}))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to store script: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteStoredScript deletes the stored script with the passed id, a script that does not exist is ignored
+func DeleteStoredScript(ctx context.Context, service *OsClusterClient, scriptID string) error {
+	path := StoredScriptPath(scriptID)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
+
+// RenderSearchTemplate renders the passed mustache source of a search template with the passed params. A template
+// OpenSearch can not compile or render fails with ErrTemplateRender holding the reason
+func RenderSearchTemplate(ctx context.Context, service *OsClusterClient, source string, params *apiextensionsv1.JSON) error {
+	var path strings.Builder
+	path.WriteString("/_render/template")
+	body := requests.RenderTemplate{Source: source, Params: params}
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 400 {
+		rejection := responses.ErrorResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(&rejection); err != nil || rejection.Error.Reason == "" {
+			return ErrTemplateRenderFailed(resp.Status())
+		}
+		return ErrTemplateRenderFailed(fmt.Sprintf("%s: %s", rejection.Error.Type, rejection.Error.Reason))
+	} else if resp.IsError() {
+		return fmt.Errorf("failed to render search template: %s", resp.String())
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SearchPipelinePath returns a strings.Builder pointing to /_search/pipeline/<pipelineName>
+func SearchPipelinePath(pipelineName string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_search/pipeline/") + len(pipelineName))
+	path.WriteString("/_search/pipeline/")
+	path.WriteString(pipelineName)
+	return path
+}
+
+// SearchPipelineExists checks if the passed search pipeline already exists or not
+func SearchPipelineExists(ctx context.Context, service *OsClusterClient, pipelineName string) (bool, error) {
+	path := SearchPipelinePath(pipelineName)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return false, nil
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return true, nil
+}
+
+// ShouldUpdateSearchPipeline checks whether a previously created search pipeline needs an update or not
+func ShouldUpdateSearchPipeline(
+	ctx context.Context,
+	service *OsClusterClient,
+	pipelineName string,
+	pipeline requests.SearchPipeline,
+) (bool, error) {
+	path := SearchPipelinePath(pipelineName)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return true, nil
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	pipelinesResponse := responses.GetSearchPipelinesResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&pipelinesResponse)
+	if err != nil {
+		return false, err
+	}
+
+	existing, ok := pipelinesResponse[pipelineName]
+	if !ok {
+		return false, fmt.Errorf("returned search pipelines do not include the requested name '%s'", pipelineName)
+	}
+	if helpers.SearchPipelinesEqual(pipeline, existing) {
+		return false, nil
+	}
+
+	lg := log.FromContext(ctx)
+	lg.Info("OpenSearch search pipeline requires update")
+
+	return true, nil
+}
+
+// CreateOrUpdateSearchPipeline creates a new search pipeline or updates a pre-existing one
+func CreateOrUpdateSearchPipeline(
+	ctx context.Context,
+	service *OsClusterClient,
+	pipelineName string,
+	pipeline requests.SearchPipeline,
+) error {
+	path := SearchPipelinePath(pipelineName)
+
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(pipeline))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to create search pipeline: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteSearchPipeline deletes a previously created search pipeline
+func DeleteSearchPipeline(ctx context.Context, service *OsClusterClient, pipelineName string) error {
+	path := SearchPipelinePath(pipelineName)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
@@ -20,6 +21,7 @@ const (
 	ROLESMAPPING  = "rolesmapping"
 	ACTIONGROUPS  = "actiongroups"
 	TENANTS       = "tenants"
+	AUDIT         = "audit"
 )
 
 func ShouldUpdateUser(
@@ -259,6 +261,58 @@ func FetchExistingRoleMapping(
 	return mappingResp[rolename], nil
 }
 
+// ShouldUpdateRoleMapping checks whether the mapping of a role differs from the passed mapping, ignoring the order of
+// its users, backend roles and hosts
+func ShouldUpdateRoleMapping(
+	ctx context.Context,
+	service *OsClusterClient,
+	rolename string,
+	mapping requests.RoleMapping,
+) (bool, error) {
+	resp, err := service.GetSecurityResource(ctx, ROLESMAPPING, rolename)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return true, nil
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	mappingResponse := responses.GetRoleMappingReponse{}
+
+	err = json.NewDecoder(resp.Body).Decode(&mappingResponse)
+	if err != nil {
+		return false, err
+	}
+
+	existing := mappingResponse[rolename]
+	if sameStrings(mapping.Users, existing.Users) &&
+		sameStrings(mapping.BackendRoles, existing.BackendRoles) &&
+		sameStrings(mapping.Hosts, existing.Hosts) {
+		return false, nil
+	}
+
+	lg := log.FromContext(ctx).WithValues("os_service", "security")
+	lg.Info("OpenSearch Role Mapping requires update")
+
+	return true, nil
+}
+
+// sameStrings reports whether both slices have the same elements in any order, nil and empty slices are the same
+func sameStrings(left, right []string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	l := append([]string{}, left...)
+	r := append([]string{}, right...)
+	sort.Strings(l)
+	sort.Strings(r)
+	return reflect.DeepEqual(l, r)
+}
+
 func CreateOrUpdateRoleMapping(
 	ctx context.Context,
 	service *OsClusterClient,
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+// SecurityAnalyticsDetectorPath returns a strings.Builder pointing to
+// /_plugins/_security_analytics/detectors/<detectorID>, or to /_plugins/_security_analytics/detectors if the id is
+// empty
+func SecurityAnalyticsDetectorPath(detectorID string) strings.Builder {
+	return securityAnalyticsPath("detectors", detectorID)
+}
+
+// SecurityAnalyticsRulePath returns a strings.Builder pointing to /_plugins/_security_analytics/rules/<ruleID>, or to
+// /_plugins/_security_analytics/rules if the id is empty
+func SecurityAnalyticsRulePath(ruleID string) strings.Builder {
+	return securityAnalyticsPath("rules", ruleID)
+}
+
+func securityAnalyticsPath(resource string, id string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_plugins/_security_analytics//") + len(resource) + len(id))
+	path.WriteString("/_plugins/_security_analytics/")
+	path.WriteString(resource)
+	if id != "" {
+		path.WriteString("/")
+		path.WriteString(url.PathEscape(id))
+	}
+	return path
+}
+
+// FindSecurityAnalyticsDetectorID returns the id of the threat detector with the passed name, an empty string if
+// there is none
+func FindSecurityAnalyticsDetectorID(ctx context.Context, service *OsClusterClient, detectorName string) (string, error) {
+	var path strings.Builder
+	detectorsPath := SecurityAnalyticsDetectorPath("")
+	path.WriteString(detectorsPath.String())
+	path.WriteString("/_search")
+	query := map[string]interface{}{
+		"size": 100,
+		"query": map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path":  "detector",
+				"query": map[string]interface{}{"match_phrase": map[string]interface{}{"detector.name": detectorName}},
+			},
+		},
+	}
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(query))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// The index of the detectors does not exist before the first detector is created
+	if resp.StatusCode == 404 {
+		return "", nil
+	} else if resp.IsError() {
+		return "", fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	searchResponse := responses.SearchSecurityAnalyticsDetectorsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+		return "", err
+	}
+	// match_phrase also matches names that contain the phrase
+	for _, hit := range searchResponse.Hits.Hits {
+		if hit.Source.Name == detectorName {
+			return hit.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// GetSecurityAnalyticsDetector fetches the threat detector with the passed id, ErrNotFound if it does not exist
+func GetSecurityAnalyticsDetector(ctx context.Context, service *OsClusterClient, detectorID string) (*responses.GetSecurityAnalyticsDetectorResponse, error) {
+	path := SecurityAnalyticsDetectorPath(detectorID)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	detectorResponse := responses.GetSecurityAnalyticsDetectorResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&detectorResponse); err != nil {
+		return nil, err
+	}
+	return &detectorResponse, nil
+}
+
+// CreateSecurityAnalyticsDetector creates the passed threat detector and returns the id OpenSearch generated for it
+func CreateSecurityAnalyticsDetector(ctx context.Context, service *OsClusterClient, detector requests.SecurityAnalyticsDetector) (string, error) {
+	path := SecurityAnalyticsDetectorPath("")
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(detector))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return "", fmt.Errorf("failed to create detector: %s", resp.String())
+	}
+
+	detectorResponse := responses.GetSecurityAnalyticsDetectorResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&detectorResponse); err != nil {
+		return "", err
+	}
+	return detectorResponse.ID, nil
+}
+
+// UpdateSecurityAnalyticsDetector replaces the threat detector with the passed id, which also enables or disables it
+func UpdateSecurityAnalyticsDetector(ctx context.Context, service *OsClusterClient, detectorID string, detector requests.SecurityAnalyticsDetector) error {
+	path := SecurityAnalyticsDetectorPath(detectorID)
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(detector))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update detector: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteSecurityAnalyticsDetector deletes the threat detector with the passed id, a detector that does not exist is
+// ignored
+func DeleteSecurityAnalyticsDetector(ctx context.Context, service *OsClusterClient, detectorID string) error {
+	path := SecurityAnalyticsDetectorPath(detectorID)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
+
+// GetSecurityAnalyticsRule fetches the custom rule with the passed id, ErrNotFound if it does not exist
+func GetSecurityAnalyticsRule(ctx context.Context, service *OsClusterClient, ruleID string) (*responses.SecurityAnalyticsRule, error) {
+	var path strings.Builder
+	rulesPath := SecurityAnalyticsRulePath("")
+	path.WriteString(rulesPath.String())
+	path.WriteString("/_search?pre_packaged=false")
+	query := map[string]interface{}{
+		"query": map[string]interface{}{"ids": map[string]interface{}{"values": []string{ruleID}}},
+	}
+	resp, err := doHTTPPost(ctx, service.client, path, opensearchutil.NewJSONReader(query))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// The index of the custom rules does not exist before the first rule is created
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	searchResponse := responses.SearchSecurityAnalyticsRulesResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+		return nil, err
+	}
+	for _, hit := range searchResponse.Hits.Hits {
+		if hit.ID == ruleID {
+			rule := hit.Source
+			return &rule, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateSecurityAnalyticsRule creates a custom rule of the passed category from the Sigma YAML and returns the id
+// OpenSearch generated for it
+func CreateSecurityAnalyticsRule(ctx context.Context, service *OsClusterClient, category string, rule string) (string, error) {
+	var path strings.Builder
+	rulesPath := SecurityAnalyticsRulePath("")
+	path.WriteString(rulesPath.String())
+	path.WriteString("?category=")
+	path.WriteString(url.QueryEscape(category))
+	resp, err := doHTTPPost(ctx, service.client, path, strings.NewReader(rule))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return "", fmt.Errorf("failed to create rule: %s", resp.String())
+	}
+
+	ruleResponse := responses.SecurityAnalyticsRuleResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&ruleResponse); err != nil {
+		return "", err
+	}
+	return ruleResponse.ID, nil
+}
+
+// UpdateSecurityAnalyticsRule replaces the custom rule with the passed id. The update is forced, so detectors using
+// the rule are updated with it
+func UpdateSecurityAnalyticsRule(ctx context.Context, service *OsClusterClient, ruleID string, category string, rule string) error {
+	var path strings.Builder
+	rulePath := SecurityAnalyticsRulePath(ruleID)
+	path.WriteString(rulePath.String())
+	path.WriteString("?category=")
+	path.WriteString(url.QueryEscape(category))
+	path.WriteString("&forced=true")
+	resp, err := doHTTPPut(ctx, service.client, path, strings.NewReader(rule))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update rule: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteSecurityAnalyticsRule deletes the custom rule with the passed id, a rule that does not exist is ignored. The
+// deletion is forced, so the rule is also removed from detectors using it
+func DeleteSecurityAnalyticsRule(ctx context.Context, service *OsClusterClient, ruleID string) error {
+	var path strings.Builder
+	rulePath := SecurityAnalyticsRulePath(ruleID)
+	path.WriteString(rulePath.String())
+	path.WriteString("?forced=true")
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+// SnapshotPolicyExists checks if the passed snapshot management policy already exists or not
+func SnapshotPolicyExists(ctx context.Context, service *OsClusterClient, policyName string) (bool, error) {
+	resp, err := service.GetSMConfig(ctx, policyName)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == 404 {
+		return false, nil
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return true, nil
+}
+
+// GetSnapshotPolicy fetches the passed snapshot management policy, ErrNotFound if it does not exist
+func GetSnapshotPolicy(ctx context.Context, service *OsClusterClient, policyName string) (*responses.GetSnapshotPolicyResponse, error) {
+	resp, err := service.GetSMConfig(ctx, policyName)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+	policyResponse := responses.GetSnapshotPolicyResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&policyResponse); err != nil {
+		return nil, err
+	}
+	return &policyResponse, nil
+}
+
+// CreateSnapshotPolicy creates the passed snapshot management policy
+func CreateSnapshotPolicy(ctx context.Context, service *OsClusterClient, policy requests.SnapshotPolicy, policyName string) error {
+	resp, err := service.CreateSMConfig(ctx, policyName, opensearchutil.NewJSONReader(policy))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return fmt.Errorf("failed to create snapshot policy: %s", resp.String())
+	}
+	return nil
+}
+
+// UpdateSnapshotPolicy updates the passed snapshot management policy if it still has the sequence number and primary
+// term it was read with
+func UpdateSnapshotPolicy(ctx context.Context, service *OsClusterClient, policy requests.SnapshotPolicy, seqno, primterm int, policyName string) error {
+	resp, err := service.UpdateSMConfig(ctx, policyName, seqno, primterm, opensearchutil.NewJSONReader(policy))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return fmt.Errorf("failed to update snapshot policy: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteSnapshotPolicy deletes the passed snapshot management policy
+func DeleteSnapshotPolicy(ctx context.Context, service *OsClusterClient, policyName string) error {
+	resp, err := service.DeleteSMConfig(ctx, policyName)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return fmt.Errorf("failed to delete snapshot policy: %s", resp.String())
+	}
+	return nil
+}
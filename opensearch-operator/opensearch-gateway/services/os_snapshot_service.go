@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SnapshotRepositoryPath returns a strings.Builder pointing to /_snapshot/<repositoryName>
+func SnapshotRepositoryPath(repositoryName string) strings.Builder {
+	escaped := url.PathEscape(repositoryName)
+	var path strings.Builder
+	path.Grow(len("/_snapshot/") + len(escaped))
+	path.WriteString("/_snapshot/")
+	path.WriteString(escaped)
+	return path
+}
+
+// SnapshotRepositoryExists checks if the passed snapshot repository is already registered or not
+func SnapshotRepositoryExists(ctx context.Context, service *OsClusterClient, repositoryName string) (bool, error) {
+	path := SnapshotRepositoryPath(repositoryName)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return false, nil
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return true, nil
+}
+
+// ShouldUpdateSnapshotRepository checks whether a previously registered snapshot repository needs an update or not
+func ShouldUpdateSnapshotRepository(
+	ctx context.Context,
+	service *OsClusterClient,
+	repositoryName string,
+	repository requests.SnapshotRepository,
+) (bool, error) {
+	path := SnapshotRepositoryPath(repositoryName)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return true, nil
+	} else if resp.IsError() {
+		return false, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	repositoryResponse := responses.GetSnapshotRepositoryResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&repositoryResponse)
+	if err != nil {
+		return false, err
+	}
+
+	existing, ok := repositoryResponse[repositoryName]
+	if !ok {
+		return false, fmt.Errorf("returned snapshot repositories do not include the requested name '%s'", repositoryName)
+	}
+	if helpers.SnapshotRepositoriesEqual(repository, existing) {
+		return false, nil
+	}
+
+	lg := log.FromContext(ctx)
+	lg.Info("OpenSearch snapshot repository requires update")
+
+	return true, nil
+}
+
+// CreateOrUpdateSnapshotRepository registers a new snapshot repository or updates a pre-existing one. If verify is
+// true OpenSearch checks that all nodes can access the repository before it is registered
+func CreateOrUpdateSnapshotRepository(
+	ctx context.Context,
+	service *OsClusterClient,
+	repositoryName string,
+	repository requests.SnapshotRepository,
+	verify bool,
+) error {
+	var path strings.Builder
+	repositoryPath := SnapshotRepositoryPath(repositoryName)
+	path.WriteString(repositoryPath.String())
+	path.WriteString(fmt.Sprintf("?verify=%t", verify))
+
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(repository))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to register snapshot repository: %s", resp.String())
+	}
+	return nil
+}
+
+// DeleteSnapshotRepository unregisters a previously registered snapshot repository, the snapshots in it are kept
+func DeleteSnapshotRepository(ctx context.Context, service *OsClusterClient, repositoryName string) error {
+	path := SnapshotRepositoryPath(repositoryName)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+// TransformJobPath returns a strings.Builder pointing to /_plugins/_transform/<jobID>
+func TransformJobPath(jobID string) strings.Builder {
+	var path strings.Builder
+	path.Grow(len("/_plugins/_transform/") + len(jobID))
+	path.WriteString("/_plugins/_transform/")
+	path.WriteString(url.PathEscape(jobID))
+	return path
+}
+
+// GetTransformJob fetches the transform job with the passed id, ErrNotFound if it does not exist
+func GetTransformJob(ctx context.Context, service *OsClusterClient, jobID string) (*responses.GetTransformJobResponse, error) {
+	path := TransformJobPath(jobID)
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	jobResponse := responses.GetTransformJobResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&jobResponse); err != nil {
+		return nil, err
+	}
+	return &jobResponse, nil
+}
+
+// CreateTransformJob creates the passed transform job
+func CreateTransformJob(ctx context.Context, service *OsClusterClient, jobID string, job requests.TransformJob) error {
+	path := TransformJobPath(jobID)
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(job))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to create transform job: %s", resp.String())
+	}
+	return nil
+}
+
+// UpdateTransformJob updates the transform job with the passed id if it still has the sequence number and primary term it
+// was read with
+func UpdateTransformJob(ctx context.Context, service *OsClusterClient, jobID string, seqno, primterm int, job requests.TransformJob) error {
+	var path strings.Builder
+	jobPath := TransformJobPath(jobID)
+	path.WriteString(jobPath.String())
+	path.WriteString("?if_seq_no=")
+	path.WriteString(strconv.Itoa(seqno))
+	path.WriteString("&if_primary_term=")
+	path.WriteString(strconv.Itoa(primterm))
+	resp, err := doHTTPPut(ctx, service.client, path, opensearchutil.NewJSONReader(job))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to update transform job: %s", resp.String())
+	}
+	return nil
+}
+
+// StartTransformJob starts the transform job with the passed id, a job that already finished is started again
+func StartTransformJob(ctx context.Context, service *OsClusterClient, jobID string) error {
+	return transformJobAction(ctx, service, jobID, "_start")
+}
+
+// StopTransformJob stops the transform job with the passed id
+func StopTransformJob(ctx context.Context, service *OsClusterClient, jobID string) error {
+	return transformJobAction(ctx, service, jobID, "_stop")
+}
+
+func transformJobAction(ctx context.Context, service *OsClusterClient, jobID string, action string) error {
+	var path strings.Builder
+	jobPath := TransformJobPath(jobID)
+	path.WriteString(jobPath.String())
+	path.WriteString("/")
+	path.WriteString(action)
+	resp, err := doHTTPPost(ctx, service.client, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to %s transform job: %s", strings.TrimPrefix(action, "_"), resp.String())
+	}
+	return nil
+}
+
+// DeleteTransformJob deletes the transform job with the passed id, a job that does not exist is ignored. The target index
+// with the transformed documents is kept
+func DeleteTransformJob(ctx context.Context, service *OsClusterClient, jobID string) error {
+	path := TransformJobPath(jobID)
+	resp, err := doHTTPDelete(ctx, service.client, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.IsError() {
+		return fmt.Errorf("response from API is %s", resp.Status())
+	}
+	return nil
+}
+
+// ExplainTransformJob returns the state of the last run of the transform job with the passed id, nil before the first
+// run
+func ExplainTransformJob(ctx context.Context, service *OsClusterClient, jobID string) (*responses.TransformMetadata, error) {
+	var path strings.Builder
+	jobPath := TransformJobPath(jobID)
+	path.WriteString(jobPath.String())
+	path.WriteString("/_explain")
+	resp, err := doHTTPGet(ctx, service.client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("response from API is %s", resp.Status())
+	}
+
+	explainResponse := responses.ExplainTransformResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&explainResponse); err != nil {
+		return nil, err
+	}
+	return explainResponse[jobID].TransformMetadata, nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// WithProxy sends the requests of the client through the HTTP proxy, except the requests to the hosts of noProxy,
+// which has the syntax of the NO_PROXY environment variable. Without the option the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables of the operator apply. It is set on the configured transport if it is an
+// *http.Transport, other transports are used as is.
+func WithProxy(proxyURL *url.URL, noProxy string) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.proxy = proxyFunc(proxyURL, noProxy)
+	}
+}
+
+// proxyFunc returns the proxy of a request, nil for the requests to the hosts of noProxy and to localhost
+func proxyFunc(proxyURL *url.URL, noProxy string) func(*http.Request) (*url.URL, error) {
+	proxy := (&httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    noProxy,
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
+
+// proxyConnectFailed returns true if the request failed because the proxy could not be connected to
+func proxyConnectFailed(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "proxyconnect"
+}
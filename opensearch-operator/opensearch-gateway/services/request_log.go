@@ -0,0 +1,271 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+const (
+	// requestLogMaxBody is the number of bytes of a request or response body that is logged
+	requestLogMaxBody = 16 * 1024
+	// requestLogSinkTimeout bounds the time writing an entry to a debug endpoint may take
+	requestLogSinkTimeout = 2 * time.Second
+)
+
+// requestLogSensitiveKeys are the JSON keys whose values are never logged, wherever they appear in a body
+var requestLogSensitiveKeys = map[string]bool{
+	"password":      true,
+	"hash":          true,
+	"secret":        true,
+	"token":         true,
+	"authorization": true,
+	"credentials":   true,
+}
+
+// RequestLog dumps the requests sent to OpenSearch and their responses to a sink for debugging. It is opt-in, either
+// for all requests or for the requests made while reconciling objects that ask for it, and time-boxed: logging ends
+// after the TTL and after maxEntries requests, so it cannot flood the sink when it is left enabled. Headers are not
+// logged, the values of sensitive keys and of the redacted paths of component template bodies are redacted.
+type RequestLog struct {
+	sink       io.Writer
+	ttl        time.Duration
+	maxEntries int
+	redaction  *helpers.Redaction
+
+	mu      sync.Mutex
+	global  *requestLogActivation
+	objects map[string]*requestLogActivation
+}
+
+type requestLogActivation struct {
+	until   time.Time
+	entries int
+}
+
+type requestLogEntry struct {
+	Time     time.Time `json:"time"`
+	Object   string    `json:"object,omitempty"`
+	Method   string    `json:"method,omitempty"`
+	URL      string    `json:"url,omitempty"`
+	Request  string    `json:"request,omitempty"`
+	Status   int       `json:"status,omitempty"`
+	Response string    `json:"response,omitempty"`
+	Duration string    `json:"duration,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Message  string    `json:"message,omitempty"`
+}
+
+// NewRequestLog returns a request log writing to sink. If all is set the requests of all clients using it are logged
+// for the TTL from now on, otherwise only the requests of objects activated with Activate.
+func NewRequestLog(sink io.Writer, all bool, ttl time.Duration, maxEntries int, redaction *helpers.Redaction) *RequestLog {
+	l := &RequestLog{
+		sink:       sink,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		redaction:  redaction,
+		objects:    map[string]*requestLogActivation{},
+	}
+	if all {
+		l.global = &requestLogActivation{until: time.Now().Add(ttl)}
+	}
+	return l
+}
+
+// NewRequestLogSink opens the sink of a request log: stderr, an http(s) URL every entry is posted to or the path of
+// a file the entries are appended to
+func NewRequestLogSink(target string) (io.Writer, error) {
+	switch {
+	case target == "stderr":
+		return os.Stderr, nil
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		return &requestLogEndpoint{url: target, client: &http.Client{Timeout: requestLogSinkTimeout}}, nil
+	default:
+		return os.OpenFile(target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	}
+}
+
+// WithRequestLog logs the requests of the client to the request log while it is active, nil does not log them
+func WithRequestLog(log *RequestLog) OsClusterClientOption {
+	return func(o *OsClusterClientOptions) {
+		o.requestLog = log
+	}
+}
+
+// Activate starts logging the requests of the object identified by its namespace, name and uid if enabled, for the
+// TTL from the first activation on. Disabling it ends the logging and allows activating it again.
+func (l *RequestLog) Activate(namespace string, name string, uid string, enabled bool) {
+	if l == nil {
+		return
+	}
+	key := requestLogKey(namespace, name, uid)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !enabled {
+		delete(l.objects, key)
+		return
+	}
+	if _, ok := l.objects[key]; !ok {
+		l.objects[key] = &requestLogActivation{until: time.Now().Add(l.ttl)}
+	}
+}
+
+// requestLogKey identifies an object like the X-Opaque-Id sent for it
+func requestLogKey(namespace string, name string, uid string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, name, uid)
+}
+
+// admit counts a request of the object against the activation it is logged by and returns false if it is not logged.
+// The entry announcing that the limit is reached is written by the caller if limitReached is set.
+func (l *RequestLog) admit(key string) (logged bool, limitReached bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	activation := l.objects[key]
+	if activation == nil || time.Now().After(activation.until) {
+		activation = l.global
+	}
+	if activation == nil || time.Now().After(activation.until) || activation.entries > l.maxEntries {
+		return false, false
+	}
+	activation.entries++
+	if activation.entries > l.maxEntries {
+		return false, true
+	}
+	return true, false
+}
+
+func (l *RequestLog) write(entry requestLogEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// A debug log failing to write must not fail the request
+	_, _ = l.sink.Write(append(line, '\n'))
+}
+
+// redactBody returns the body as it is logged. Values of sensitive keys are redacted in JSON bodies, component
+// template bodies are redacted like wherever else template content is surfaced.
+func (l *RequestLog) redactBody(path string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var parsed interface{}
+	if err := helpers.UnmarshalPreservingNumbers(body, &parsed); err == nil {
+		parsed = redactSensitiveKeys(parsed)
+		if strings.Contains(path, "/_component_template/") {
+			parsed = l.redaction.RedactValue("", parsed)
+		}
+		if redacted, err := json.Marshal(parsed); err == nil {
+			body = redacted
+		}
+	}
+	if len(body) > requestLogMaxBody {
+		return string(body[:requestLogMaxBody]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+func redactSensitiveKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if requestLogSensitiveKeys[strings.ToLower(key)] {
+				v[key] = helpers.RedactedValue
+				continue
+			}
+			v[key] = redactSensitiveKeys(nested)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactSensitiveKeys(nested)
+		}
+	}
+	return value
+}
+
+// requestLogTransport logs the requests and responses passing it while the request log is active for the object
+type requestLogTransport struct {
+	transport http.RoundTripper
+	log       *RequestLog
+	object    reconciledObject
+}
+
+func (t *requestLogTransport) CloseIdleConnections() {
+	closeIdleConnections(t.transport)
+}
+
+func (t *requestLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	object := requestObject(req, t.object)
+	logged, limitReached := t.log.admit(object.opaqueID)
+	if limitReached {
+		t.log.write(requestLogEntry{
+			Time:    time.Now(),
+			Object:  object.description,
+			Message: fmt.Sprintf("request log limit of %d entries reached, further requests are not logged", t.log.maxEntries),
+		})
+	}
+	if !logged {
+		return t.transport.RoundTrip(req)
+	}
+
+	entry := requestLogEntry{
+		Time:   time.Now(),
+		Object: object.description,
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = withBody(req, body)
+		entry.Request = t.log.redactBody(req.URL.Path, body)
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	entry.Duration = time.Since(entry.Time).String()
+	if err != nil {
+		entry.Error = err.Error()
+		t.log.write(entry)
+		return resp, err
+	}
+	entry.Status = resp.StatusCode
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		entry.Error = err.Error()
+		t.log.write(entry)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	entry.Response = t.log.redactBody(req.URL.Path, body)
+	t.log.write(entry)
+	return resp, nil
+}
+
+// requestLogEndpoint posts every entry to a debug endpoint
+type requestLogEndpoint struct {
+	url    string
+	client *http.Client
+}
+
+func (e *requestLogEndpoint) Write(p []byte) (int, error) {
+	resp, err := e.client.Post(e.url, jsonContentHeader, bytes.NewReader(p))
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return len(p), nil
+}
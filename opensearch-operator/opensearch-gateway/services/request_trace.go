@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// serverRequestIDHeaders are the response headers in which OpenSearch, or a proxy or managed service in front of it,
+// returns the id it assigned to a request
+var serverRequestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid"}
+
+// RequestTrace records the ids of the last request made with a context returned by ContextWithRequestTrace
+type RequestTrace struct {
+	correlationID string
+
+	mu              sync.Mutex
+	opaqueID        string
+	serverRequestID string
+}
+
+type requestTraceContextKey struct{}
+
+// ContextWithRequestTrace appends the correlation id to the X-Opaque-Id of the requests made with the returned
+// context, and records the X-Opaque-Id and the id OpenSearch returned for the last of them in the returned trace
+func ContextWithRequestTrace(ctx context.Context, correlationID string) (context.Context, *RequestTrace) {
+	trace := &RequestTrace{correlationID: correlationID}
+	return context.WithValue(ctx, requestTraceContextKey{}, trace), trace
+}
+
+// OpaqueID returns the X-Opaque-Id sent with the last traced request
+func (t *RequestTrace) OpaqueID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.opaqueID
+}
+
+// ServerRequestID returns the id OpenSearch returned for the last traced request, empty if it returned none
+func (t *RequestTrace) ServerRequestID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.serverRequestID
+}
+
+// requestTrace returns the trace of the request, nil if it is not traced
+func requestTrace(req *http.Request) *RequestTrace {
+	trace, _ := req.Context().Value(requestTraceContextKey{}).(*RequestTrace)
+	return trace
+}
+
+// tracedOpaqueID returns the X-Opaque-Id identifying the object with the correlation id appended
+func (t *RequestTrace) tracedOpaqueID(opaqueID string) string {
+	if opaqueID == "" {
+		return t.correlationID
+	}
+	return opaqueID + "/" + t.correlationID
+}
+
+// record keeps the ids of a request, the response is nil if the request failed
+func (t *RequestTrace) record(opaqueID string, resp *http.Response) {
+	serverRequestID := ""
+	if resp != nil {
+		for _, header := range serverRequestIDHeaders {
+			if serverRequestID = resp.Header.Get(header); serverRequestID != "" {
+				break
+			}
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.opaqueID = opaqueID
+	t.serverRequestID = serverRequestID
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const headerWarning = "Warning"
+
+// DeprecationWarnings returns the messages of the Warning headers of a response, which OpenSearch uses to report
+// deprecated settings and APIs a request used. The headers have the form 299 OpenSearch-<version> "<message>", the
+// message is returned without the code and agent, a header not in this form is returned as is.
+func DeprecationWarnings(header http.Header) []string {
+	var warnings []string
+	for _, value := range header.Values(headerWarning) {
+		warnings = append(warnings, warningMessage(value))
+	}
+	return warnings
+}
+
+func warningMessage(value string) string {
+	start := strings.Index(value, `"`)
+	if start < 0 {
+		return value
+	}
+	// The quoted message may be followed by a quoted date
+	message, err := strconv.QuotedPrefix(value[start:])
+	if err != nil {
+		return value
+	}
+	unquoted, err := strconv.Unquote(message)
+	if err != nil {
+		return value
+	}
+	return unquoted
+}
@@ -169,6 +169,8 @@ func NewSTSForNodePool(
 		// vendor ="elasticsearch"
 	}
 
+	// The resources decided by the vertical autoscaler replace those of the spec, the heap follows their memory
+	node.Resources = helpers.EffectiveResources(cr.Status, &node)
 	jvm := helpers.CalculateJvmHeapSize(&node)
 
 	// If node role `search` defined add required experimental flag if version less than 2.7
@@ -352,6 +354,7 @@ func NewSTSForNodePool(
 		initContainers = append(initContainers, keystoreInitContainer)
 	}
 
+	replicas := helpers.EffectiveReplicas(cr.Status, &node)
 	sts := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        cr.Name + "-" + node.Component,
@@ -360,7 +363,7 @@ func NewSTSForNodePool(
 			Annotations: annotations,
 		},
 		Spec: appsv1.StatefulSetSpec{
-			Replicas: &node.Replicas,
+			Replicas: &replicas,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: matchLabels,
 			},
@@ -466,6 +469,10 @@ func NewSTSForNodePool(
 	// Append additional env vars from cr.Spec.NodePool.env
 	sts.Spec.Template.Spec.Containers[0].Env = append(sts.Spec.Template.Spec.Containers[0].Env, node.Env...)
 
+	if helpers.ZoneAwarenessEnabled(&node) {
+		addZoneAwareness(cr, &node, sts, matchLabels)
+	}
+
 	if cr.Spec.General.SetVMMaxMapCount {
 		initHelperImage := helpers.ResolveInitHelperImage(cr)
 
@@ -487,6 +494,47 @@ func NewSTSForNodePool(
 	return sts
 }
 
+// addZoneAwareness spreads the pods of the statefulset across zones and passes the zone of a pod to OpenSearch as a
+// node attribute. The operator annotates every scheduled pod with the zone of its node, the zone is read through the
+// downward API. As the annotation is only set after scheduling an init container waits for it, so that the
+// environment of the opensearch container, which is resolved when the container starts, contains the zone
+func addZoneAwareness(cr *opsterv1.OpenSearchCluster, node *opsterv1.NodePool, sts *appsv1.StatefulSet, matchLabels map[string]string) {
+	podSpec := &sts.Spec.Template.Spec
+	podSpec.TopologySpreadConstraints = helpers.ZoneTopologySpreadConstraints(podSpec.TopologySpreadConstraints, node.ZoneAwareness, matchLabels)
+
+	zoneFieldPath := fmt.Sprintf("metadata.annotations['%s']", helpers.ZoneAnnotation)
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+		Name:      "node.attr." + helpers.ZoneAttribute(node.ZoneAwareness),
+		ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: zoneFieldPath}},
+	})
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "zone",
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{Path: "zone", FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: zoneFieldPath}},
+				},
+			},
+		},
+	})
+	initHelperImage := helpers.ResolveInitHelperImage(cr)
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:            "wait-for-zone",
+		Image:           initHelperImage.GetImage(),
+		ImagePullPolicy: initHelperImage.GetImagePullPolicy(),
+		Resources:       cr.Spec.InitHelper.Resources,
+		Command:         []string{"sh", "-c"},
+		Args:            []string{"until [ -s /zone/zone ]; do echo 'Waiting for the zone of the node'; sleep 2; done"},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "zone",
+				MountPath: "/zone",
+			},
+		},
+	})
+}
+
 func NewHeadlessServiceForNodePool(cr *opsterv1.OpenSearchCluster, nodePool *opsterv1.NodePool) *corev1.Service {
 	labels := map[string]string{
 		helpers.ClusterLabel:  cr.Name,
@@ -850,6 +898,11 @@ func URLForCluster(cr *opsterv1.OpenSearchCluster) string {
 	return fmt.Sprintf("https://%s.svc.%s:%d", DnsOfService(cr), helpers.ClusterDnsBase(), httpPort)
 }
 
+// TransportAddressForCluster returns the address other clusters connect to the transport port of the cluster with
+func TransportAddressForCluster(cr *opsterv1.OpenSearchCluster) string {
+	return fmt.Sprintf("%s.svc.%s:9300", DnsOfService(cr), helpers.ClusterDnsBase())
+}
+
 func PasswordSecret(cr *opsterv1.OpenSearchCluster, username, password string) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -871,6 +924,11 @@ func StsName(cr *opsterv1.OpenSearchCluster, nodePool *opsterv1.NodePool) string
 	return cr.Name + "-" + nodePool.Component
 }
 
+// DataPVCName returns the name of the PVC the statefulset creates for the data of the pod
+func DataPVCName(podName string) string {
+	return "data-" + podName
+}
+
 func DiscoveryServiceName(cr *opsterv1.OpenSearchCluster) string {
 	return fmt.Sprintf("%s-discovery", cr.Name)
 }
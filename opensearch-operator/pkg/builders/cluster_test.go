@@ -687,4 +687,56 @@ var _ = Describe("Builders", func() {
 			}))
 		})
 	})
+
+	When("Constructing a STS for a zone aware NodePool", func() {
+		It("should spread the pods across zones and pass the zone to OpenSearch", func() {
+			clusterObject := ClusterDescWithVersion("2.2.1")
+			clusterObject.ObjectMeta.Namespace = "foobar"
+			clusterObject.ObjectMeta.Name = "foobar"
+			nodePool := opsterv1.NodePool{
+				Replicas:      3,
+				Component:     "data",
+				Roles:         []string{"data"},
+				ZoneAwareness: &opsterv1.ZoneAwarenessConfig{Enable: true},
+			}
+			clusterObject.Spec.NodePools = append(clusterObject.Spec.NodePools, nodePool)
+
+			sts := NewSTSForNodePool("foobar", &clusterObject, nodePool, "foobar", nil, nil, nil)
+			podSpec := sts.Spec.Template.Spec
+			Expect(podSpec.TopologySpreadConstraints).To(HaveLen(1))
+			Expect(podSpec.TopologySpreadConstraints[0].TopologyKey).To(Equal("topology.kubernetes.io/zone"))
+			Expect(podSpec.TopologySpreadConstraints[0].LabelSelector.MatchLabels).To(HaveKeyWithValue(helpers.NodePoolLabel, "data"))
+			Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name: "node.attr.zone",
+				ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "metadata.annotations['opensearch.opster.io/zone']",
+				}},
+			}))
+			var waitForZone *corev1.Container
+			for i, container := range podSpec.InitContainers {
+				if container.Name == "wait-for-zone" {
+					waitForZone = &podSpec.InitContainers[i]
+				}
+			}
+			Expect(waitForZone).ToNot(BeNil())
+			Expect(waitForZone.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "zone", MountPath: "/zone"}))
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", "zone")))
+		})
+
+		It("should use the configured node attribute", func() {
+			clusterObject := ClusterDescWithVersion("2.2.1")
+			nodePool := opsterv1.NodePool{
+				Replicas:      3,
+				Component:     "data",
+				Roles:         []string{"data"},
+				ZoneAwareness: &opsterv1.ZoneAwarenessConfig{Enable: true, Attribute: "rack", TopologyKey: "example.com/rack"},
+			}
+			clusterObject.Spec.NodePools = append(clusterObject.Spec.NodePools, nodePool)
+
+			sts := NewSTSForNodePool("foobar", &clusterObject, nodePool, "foobar", nil, nil, nil)
+			Expect(sts.Spec.Template.Spec.TopologySpreadConstraints[0].TopologyKey).To(Equal("example.com/rack"))
+			Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(HaveField("Name", "node.attr.rack")))
+		})
+	})
 })
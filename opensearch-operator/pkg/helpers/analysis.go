@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// analysisComponentPlugins maps analysis components that are not built into OpenSearch to the plugin providing them
+var analysisComponentPlugins = map[string]string{
+	"ja_stop":     "analysis-kuromoji",
+	"phonetic":    "analysis-phonetic",
+	"polish":      "analysis-stempel",
+	"polish_stem": "analysis-stempel",
+	"ukrainian":   "analysis-ukrainian",
+}
+
+// analysisComponentPluginPrefixes maps name prefixes of analysis components to the plugin providing them
+var analysisComponentPluginPrefixes = map[string]string{
+	"icu_":     "analysis-icu",
+	"kuromoji": "analysis-kuromoji",
+	"nori":     "analysis-nori",
+	"smartcn":  "analysis-smartcn",
+}
+
+// analysisReferenceKeys are the keys in analysis settings that reference analysis components by name
+var analysisReferenceKeys = map[string]bool{
+	"type":        true,
+	"tokenizer":   true,
+	"filter":      true,
+	"char_filter": true,
+}
+
+// AnalysisPlugins returns the sorted names of the plugins providing the analysis components referenced in the
+// given index settings. Both the nested and the flat (e.g. "index.analysis.analyzer.a.tokenizer") notation are supported.
+func AnalysisPlugins(settings *apiextensionsv1.JSON) ([]string, error) {
+	if settings.Size() == 0 {
+		return nil, nil
+	}
+	var parsed interface{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return nil, err
+	}
+
+	plugins := map[string]bool{}
+	collectAnalysisPlugins(parsed, nil, plugins)
+
+	var result []string
+	for plugin := range plugins {
+		result = append(result, plugin)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// collectAnalysisPlugins walks the settings and adds the plugins of all referenced analysis components to plugins
+func collectAnalysisPlugins(value interface{}, path []string, plugins map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			collectAnalysisPlugins(nested, append(path[:len(path):len(path)], strings.Split(key, ".")...), plugins)
+		}
+	case []interface{}:
+		for _, nested := range v {
+			collectAnalysisPlugins(nested, path, plugins)
+		}
+	case string:
+		if len(path) == 0 || !analysisReferenceKeys[path[len(path)-1]] || !ContainsString(path, "analysis") {
+			return
+		}
+		if plugin := analysisComponentPlugin(v); plugin != "" {
+			plugins[plugin] = true
+		}
+	}
+}
+
+func analysisComponentPlugin(component string) string {
+	if plugin, ok := analysisComponentPlugins[component]; ok {
+		return plugin
+	}
+	for prefix, plugin := range analysisComponentPluginPrefixes {
+		if strings.HasPrefix(component, prefix) {
+			return plugin
+		}
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("analysis plugin lookup",
+	func(settings string, expectedPlugins []string) {
+		plugins, err := AnalysisPlugins(&apiextensionsv1.JSON{Raw: []byte(settings)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plugins).To(Equal(expectedPlugins))
+	},
+	Entry("When no analysis is configured", `{"index":{"number_of_shards":"1"}}`, nil),
+	Entry("When only built-in components are used",
+		`{"analysis":{"analyzer":{"a":{"type":"custom","tokenizer":"standard","filter":["lowercase"]}}}}`, nil),
+	Entry("When plugin components are used in nested settings",
+		`{"index":{"analysis":{"analyzer":{"a":{"type":"custom","tokenizer":"icu_tokenizer","filter":["kuromoji_baseform","icu_folding"]}}}}}`,
+		[]string{"analysis-icu", "analysis-kuromoji"}),
+	Entry("When plugin components are used in flat settings",
+		`{"index.analysis.analyzer.a.tokenizer":"nori_tokenizer","index.analysis.filter.b.type":"phonetic"}`,
+		[]string{"analysis-nori", "analysis-phonetic"}),
+	Entry("When a plugin analyzer type is used", `{"analysis":{"analyzer":{"a":{"type":"smartcn"}}}}`, []string{"analysis-smartcn"}),
+	Entry("When a plugin component name is used outside of analysis", `{"index":{"codec":"icu_tokenizer"}}`, nil),
+)
@@ -0,0 +1,128 @@
+package helpers
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+)
+
+// TranslateAuditConfigToRequest returns the audit configuration of the spec in the format of the security plugin,
+// holding only the fields that are set
+func TranslateAuditConfigToRequest(spec v1.OpensearchAuditConfigSpec) map[string]interface{} {
+	config := map[string]interface{}{}
+	setBool(config, "enabled", spec.Enabled)
+	if audit := spec.Audit; audit != nil {
+		section := map[string]interface{}{}
+		setBool(section, "enable_rest", audit.EnableRest)
+		setStrings(section, "disabled_rest_categories", audit.DisabledRestCategories)
+		setBool(section, "enable_transport", audit.EnableTransport)
+		setStrings(section, "disabled_transport_categories", audit.DisabledTransportCategories)
+		setBool(section, "resolve_bulk_requests", audit.ResolveBulkRequests)
+		setBool(section, "log_request_body", audit.LogRequestBody)
+		setBool(section, "resolve_indices", audit.ResolveIndices)
+		setBool(section, "exclude_sensitive_headers", audit.ExcludeSensitiveHeaders)
+		setStrings(section, "ignore_users", audit.IgnoreUsers)
+		setStrings(section, "ignore_requests", audit.IgnoreRequests)
+		config["audit"] = section
+	}
+	if compliance := spec.Compliance; compliance != nil {
+		section := map[string]interface{}{}
+		setBool(section, "enabled", compliance.Enabled)
+		setBool(section, "internal_config", compliance.InternalConfig)
+		setBool(section, "external_config", compliance.ExternalConfig)
+		setBool(section, "read_metadata_only", compliance.ReadMetadataOnly)
+		setBool(section, "write_metadata_only", compliance.WriteMetadataOnly)
+		setBool(section, "write_log_diffs", compliance.WriteLogDiffs)
+		if compliance.ReadWatchedFields != nil {
+			fields := map[string]interface{}{}
+			for pattern, names := range compliance.ReadWatchedFields {
+				setStrings(fields, pattern, names)
+			}
+			section["read_watched_fields"] = fields
+		}
+		setStrings(section, "read_ignore_users", compliance.ReadIgnoreUsers)
+		setStrings(section, "write_watched_indices", compliance.WriteWatchedIndices)
+		setStrings(section, "write_ignore_users", compliance.WriteIgnoreUsers)
+		config["compliance"] = section
+	}
+	return config
+}
+
+func setBool(config map[string]interface{}, key string, value *bool) {
+	if value != nil {
+		config[key] = *value
+	}
+}
+
+// setStrings sets non nil lists, so an empty list in the spec clears the list in OpenSearch
+func setStrings(config map[string]interface{}, key string, values []string) {
+	if values == nil {
+		return
+	}
+	list := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		list = append(list, value)
+	}
+	config[key] = list
+}
+
+// AuditConfigEqual returns true if the audit configuration in OpenSearch holds every field of the desired one
+func AuditConfigEqual(desired map[string]interface{}, existing map[string]interface{}) bool {
+	return JSONSubset(desired, existing)
+}
+
+// MergeAuditConfig returns a copy of the existing audit configuration with the fields of the desired one, the security
+// plugin replaces the whole configuration on an update. The fields watched by read_watched_fields are replaced as a
+// whole, so index patterns removed from the spec are no longer watched.
+func MergeAuditConfig(existing map[string]interface{}, desired map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(existing))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range desired {
+		desiredSection, ok := value.(map[string]interface{})
+		existingSection, isMap := merged[key].(map[string]interface{})
+		if ok && isMap && key != "read_watched_fields" {
+			merged[key] = MergeAuditConfig(existingSection, desiredSection)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// AuditConfigReadOnlyViolations returns the sorted JSON pointers of the read only settings whose desired value differs
+// from the existing one. The security plugin rejects updates changing them.
+func AuditConfigReadOnlyViolations(desired map[string]interface{}, existing map[string]interface{}, readOnly []string) []string {
+	var violations []string
+	for _, pointer := range readOnly {
+		keys := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+		desiredValue, ok := auditConfigValue(desired, keys)
+		if !ok {
+			continue
+		}
+		existingValue, _ := auditConfigValue(existing, keys)
+		if !reflect.DeepEqual(desiredValue, existingValue) {
+			violations = append(violations, pointer)
+		}
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+// auditConfigValue returns the value at the path of keys, false if any of them is missing
+func auditConfigValue(config map[string]interface{}, keys []string) (interface{}, bool) {
+	var value interface{} = config
+	for _, key := range keys {
+		section, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = section[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("audit config", func() {
+	spec := v1.OpensearchAuditConfigSpec{
+		Enabled: pointer.Bool(true),
+		Audit: &v1.AuditSpec{
+			EnableRest:             pointer.Bool(true),
+			DisabledRestCategories: []string{"AUTHENTICATED", "GRANTED_PRIVILEGES"},
+			IgnoreUsers:            []string{},
+		},
+		Compliance: &v1.ComplianceSpec{
+			WriteLogDiffs:     pointer.Bool(true),
+			ReadWatchedFields: map[string][]string{"customers-*": {"ssn"}},
+		},
+	}
+
+	It("should only translate the fields that are set", func() {
+		Expect(TranslateAuditConfigToRequest(spec)).To(Equal(map[string]interface{}{
+			"enabled": true,
+			"audit": map[string]interface{}{
+				"enable_rest":              true,
+				"disabled_rest_categories": []interface{}{"AUTHENTICATED", "GRANTED_PRIVILEGES"},
+				"ignore_users":             []interface{}{},
+			},
+			"compliance": map[string]interface{}{
+				"write_log_diffs":     true,
+				"read_watched_fields": map[string]interface{}{"customers-*": []interface{}{"ssn"}},
+			},
+		}))
+	})
+
+	It("should keep the fields that are not set when merging", func() {
+		existing := map[string]interface{}{
+			"enabled": false,
+			"audit": map[string]interface{}{
+				"enable_rest":              true,
+				"enable_transport":         true,
+				"disabled_rest_categories": []interface{}{"AUTHENTICATED"},
+				"ignore_users":             []interface{}{"kibanaserver"},
+			},
+			"compliance": map[string]interface{}{
+				"enabled":             true,
+				"write_log_diffs":     false,
+				"read_watched_fields": map[string]interface{}{"orders-*": []interface{}{"card"}},
+			},
+		}
+		desired := TranslateAuditConfigToRequest(spec)
+		Expect(AuditConfigEqual(desired, existing)).To(BeFalse())
+
+		merged := MergeAuditConfig(existing, desired)
+		Expect(merged).To(Equal(map[string]interface{}{
+			"enabled": true,
+			"audit": map[string]interface{}{
+				"enable_rest":              true,
+				"enable_transport":         true,
+				"disabled_rest_categories": []interface{}{"AUTHENTICATED", "GRANTED_PRIVILEGES"},
+				"ignore_users":             []interface{}{},
+			},
+			"compliance": map[string]interface{}{
+				"enabled":             true,
+				"write_log_diffs":     true,
+				"read_watched_fields": map[string]interface{}{"customers-*": []interface{}{"ssn"}},
+			},
+		}))
+		Expect(AuditConfigEqual(desired, merged)).To(BeTrue())
+		Expect(existing["enabled"]).To(BeFalse())
+	})
+
+	It("should report read only settings that would change", func() {
+		desired := map[string]interface{}{
+			"enabled":    true,
+			"audit":      map[string]interface{}{"exclude_sensitive_headers": false},
+			"compliance": map[string]interface{}{"external_config": false},
+		}
+		existing := map[string]interface{}{
+			"enabled":    true,
+			"audit":      map[string]interface{}{"exclude_sensitive_headers": true},
+			"compliance": map[string]interface{}{"internal_config": true, "external_config": false},
+		}
+		readOnly := []string{"/compliance/internal_config", "/compliance/external_config", "/audit/exclude_sensitive_headers", "/enabled"}
+		Expect(AuditConfigReadOnlyViolations(desired, existing, readOnly)).To(Equal([]string{"/audit/exclude_sensitive_headers"}))
+	})
+})
@@ -0,0 +1,31 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AutoCreateIndexSetting is the cluster setting deciding which indices are created when a document is first
+// written to them
+const AutoCreateIndexSetting = "action.auto_create_index"
+
+// AutoCreateIndexWarning returns why indices of a template allowing auto-create may not be created automatically
+// with the given action.auto_create_index value, which is true, false or a comma separated list of +/- index
+// patterns. Nothing is returned if the cluster auto-creates every index.
+func AutoCreateIndexWarning(setting string) string {
+	setting = strings.TrimSpace(setting)
+	switch strings.ToLower(setting) {
+	case "", "true":
+		return ""
+	case "false":
+		return fmt.Sprintf("component template allows auto-creating indices, but %s of the cluster is false, so indices are not created on their first write", AutoCreateIndexSetting)
+	}
+	var patterns []string
+	for _, pattern := range strings.Split(setting, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return fmt.Sprintf("component template allows auto-creating indices, but %s of the cluster only auto-creates indices matching %s, other indices are not created on their first write",
+		AutoCreateIndexSetting, strings.Join(patterns, ", "))
+}
@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("allow auto-create", func() {
+	It("should round-trip allow_auto_create and differ when it changes", func() {
+		request := TranslateComponentTemplateToRequest(v1.OpensearchComponentTemplateSpec{AllowAutoCreate: true})
+		body, err := json.Marshal(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal(`{"template":{},"allow_auto_create":true}`))
+
+		live := requests.ComponentTemplate{}
+		Expect(json.Unmarshal(body, &live)).To(Succeed())
+		Expect(live.AllowAutoCreate).To(BeTrue())
+		Expect(ComponentTemplatesEqual(request, live)).To(BeTrue())
+
+		live.AllowAutoCreate = false
+		Expect(ComponentTemplatesEqual(request, live)).To(BeFalse())
+		changes, err := JSONDiff(live, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Path).To(Equal("allow_auto_create"))
+	})
+})
+
+var _ = DescribeTable("auto-create index warnings",
+	func(setting string, expected string) {
+		Expect(AutoCreateIndexWarning(setting)).To(Equal(expected))
+	},
+	Entry("When the setting is unknown", "", ""),
+	Entry("When every index is auto-created", "true", ""),
+	Entry("When no index is auto-created", " False ", "component template allows auto-creating indices, but action.auto_create_index of the cluster is false, so indices are not created on their first write"),
+	Entry("When only some indices are auto-created", "+logs-*, -*", "component template allows auto-creating indices, but action.auto_create_index of the cluster only auto-creates indices matching +logs-*, -*, other indices are not created on their first write"),
+)
@@ -0,0 +1,399 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// AutoscalingTolerance is the relative deviation of a usage from its target the autoscaler ignores
+const AutoscalingTolerance = 0.1
+
+// DefaultScaleDownStabilizationWindow is used if a node pool sets no scale down stabilization window
+const DefaultScaleDownStabilizationWindow = 5 * time.Minute
+
+// NodePoolUsage is the average usage in percent of the nodes of a node pool, a nil usage was not reported by any node
+type NodePoolUsage struct {
+	Nodes           int
+	CPUUtilization  *float64
+	JvmHeapPressure *float64
+	DiskUsage       *float64
+}
+
+// ValidateAutoscaling returns an error naming every problem of the autoscaling config of the node pool
+func ValidateAutoscaling(nodePool *opsterv1.NodePool) error {
+	autoscaling := nodePool.Autoscaling
+	if autoscaling == nil {
+		return nil
+	}
+	var invalid []string
+	if autoscaling.MinReplicas < 1 {
+		invalid = append(invalid, "minReplicas has to be at least 1")
+	}
+	if autoscaling.MaxReplicas < autoscaling.MinReplicas {
+		invalid = append(invalid, "maxReplicas has to be at least minReplicas")
+	}
+	if autoscaling.TargetCPUUtilization == nil && autoscaling.TargetJvmHeapPressure == nil && autoscaling.TargetDiskUsage == nil {
+		invalid = append(invalid, "at least one of targetCpuUtilization, targetJvmHeapPressure and targetDiskUsage has to be set")
+	}
+	if _, err := ScaleDownStabilizationWindow(autoscaling); err != nil {
+		invalid = append(invalid, err.Error())
+	}
+	if autoscaling.Vertical != nil {
+		invalid = append(invalid, verticalAutoscalingProblems(nodePool)...)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid autoscaling of node pool %s: %s", nodePool.Component, strings.Join(invalid, "; "))
+}
+
+// verticalAutoscalingProblems returns the problems of the vertical autoscaling config of the node pool
+func verticalAutoscalingProblems(nodePool *opsterv1.NodePool) []string {
+	autoscaling := nodePool.Autoscaling
+	vertical := autoscaling.Vertical
+	var invalid []string
+	scaled := verticalResources(vertical)
+	if len(scaled) == 0 {
+		invalid = append(invalid, "vertical has to set cpu or memory in both minResources and maxResources")
+	}
+	for name := range vertical.MinResources {
+		if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+			invalid = append(invalid, fmt.Sprintf("vertical can only scale cpu and memory, not %s", name))
+		}
+	}
+	for name := range vertical.MaxResources {
+		if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+			invalid = append(invalid, fmt.Sprintf("vertical can only scale cpu and memory, not %s", name))
+		}
+	}
+	for _, name := range scaled {
+		min, max := vertical.MinResources[name], vertical.MaxResources[name]
+		if min.Sign() <= 0 {
+			invalid = append(invalid, fmt.Sprintf("minResources.%s has to be positive", name))
+		}
+		if max.Cmp(min) < 0 {
+			invalid = append(invalid, fmt.Sprintf("maxResources.%s has to be at least minResources.%s", name, name))
+		}
+	}
+	for _, name := range scaled {
+		if name == corev1.ResourceCPU && autoscaling.TargetCPUUtilization == nil {
+			invalid = append(invalid, "vertical scaling of cpu needs targetCpuUtilization")
+		}
+		if name == corev1.ResourceMemory && autoscaling.TargetJvmHeapPressure == nil {
+			invalid = append(invalid, "vertical scaling of memory needs targetJvmHeapPressure")
+		}
+		if name == corev1.ResourceMemory && nodePool.Jvm != "" {
+			// The heap is only derived from the memory if the pool sets no jvm options
+			invalid = append(invalid, "vertical scaling of memory needs the jvm of the node pool to be unset")
+		}
+	}
+	return invalid
+}
+
+// verticalResources returns the resources set in both bounds of the vertical autoscaling config, sorted
+func verticalResources(vertical *opsterv1.NodePoolVerticalAutoscaling) []corev1.ResourceName {
+	var names []corev1.ResourceName
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		_, hasMin := vertical.MinResources[name]
+		_, hasMax := vertical.MaxResources[name]
+		if hasMin && hasMax {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ScaleDownStabilizationWindow returns the time to wait after a scaling operation before the pool is scaled down
+func ScaleDownStabilizationWindow(autoscaling *opsterv1.NodePoolAutoscaling) (time.Duration, error) {
+	if autoscaling.ScaleDownStabilizationWindow == "" {
+		return DefaultScaleDownStabilizationWindow, nil
+	}
+	window, err := ParseTimeValue(autoscaling.ScaleDownStabilizationWindow)
+	if err != nil {
+		return 0, fmt.Errorf("invalid scaleDownStabilizationWindow: %w", err)
+	}
+	if window < 0 {
+		return 0, fmt.Errorf("invalid scaleDownStabilizationWindow: %s", autoscaling.ScaleDownStabilizationWindow)
+	}
+	return window, nil
+}
+
+// isNodeOfStatefulSet returns true if the node name is the name of a pod of the statefulset, i.e. <sts>-<ordinal>
+func isNodeOfStatefulSet(nodeName string, stsName string) bool {
+	if !strings.HasPrefix(nodeName, stsName+"-") {
+		return false
+	}
+	_, err := strconv.ParseUint(strings.TrimPrefix(nodeName, stsName+"-"), 10, 32)
+	return err == nil
+}
+
+// statValue returns the number at the path of the stats of a node
+func statValue(stats map[string]interface{}, path ...string) (float64, bool) {
+	var current interface{} = stats
+	for _, key := range path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return 0, false
+		}
+		current = object[key]
+	}
+	value, ok := current.(float64)
+	return value, ok
+}
+
+// average returns the average of the values or nil if there are none
+func average(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	avg := sum / float64(len(values))
+	return &avg
+}
+
+// NodePoolUsageFromStats returns the average usage of the nodes of the statefulset in the response of _nodes/stats
+func NodePoolUsageFromStats(stats responses.NodesStatsResponse, stsName string) NodePoolUsage {
+	usage := NodePoolUsage{}
+	var cpu, heap, disk []float64
+	for _, node := range stats.Nodes {
+		if !isNodeOfStatefulSet(node.Name, stsName) {
+			continue
+		}
+		usage.Nodes++
+		if value, ok := statValue(node.Os, "cpu", "percent"); ok {
+			cpu = append(cpu, value)
+		}
+		if value, ok := statValue(node.Jvm, "mem", "heap_used_percent"); ok {
+			heap = append(heap, value)
+		}
+		total, totalOk := statValue(node.Fs, "total", "total_in_bytes")
+		available, availableOk := statValue(node.Fs, "total", "available_in_bytes")
+		if totalOk && availableOk && total > 0 {
+			disk = append(disk, (total-available)/total*100)
+		}
+	}
+	usage.CPUUtilization = average(cpu)
+	usage.JvmHeapPressure = average(heap)
+	usage.DiskUsage = average(disk)
+	return usage
+}
+
+// DesiredReplicas returns the number of replicas that brings the usage of the node pool closest to its targets and the
+// reason for it. Every metric proposes current * usage / target replicas unless the usage is within the tolerance of
+// its target, the largest proposal wins so that no metric exceeds its target. The result is bounded by the min and
+// max replicas of the autoscaling config
+func DesiredReplicas(autoscaling opsterv1.NodePoolAutoscaling, current int32, usage NodePoolUsage) (int32, string) {
+	if usage.Nodes == 0 {
+		return boundReplicas(autoscaling, current), "no node of the pool reported stats"
+	}
+
+	metrics := []struct {
+		name   string
+		target *int32
+		usage  *float64
+	}{
+		{"cpu utilization", autoscaling.TargetCPUUtilization, usage.CPUUtilization},
+		{"jvm heap pressure", autoscaling.TargetJvmHeapPressure, usage.JvmHeapPressure},
+		{"disk usage", autoscaling.TargetDiskUsage, usage.DiskUsage},
+	}
+	var desired int32
+	reason := ""
+	for _, metric := range metrics {
+		if metric.target == nil || metric.usage == nil {
+			continue
+		}
+		ratio := *metric.usage / float64(*metric.target)
+		proposal := current
+		if math.Abs(ratio-1) > AutoscalingTolerance {
+			proposal = int32(math.Ceil(float64(current) * ratio))
+		}
+		if reason == "" || proposal > desired {
+			desired = proposal
+			reason = fmt.Sprintf("%s is %.0f%%, target %d%%", metric.name, *metric.usage, *metric.target)
+		}
+	}
+	if reason == "" {
+		return boundReplicas(autoscaling, current), "no usage reported for the configured targets"
+	}
+
+	bounded := boundReplicas(autoscaling, desired)
+	if bounded != desired {
+		reason = fmt.Sprintf("%s, limited to %d replicas", reason, bounded)
+	}
+	return bounded, reason
+}
+
+// boundReplicas returns the replicas limited to the min and max replicas of the autoscaling config
+func boundReplicas(autoscaling opsterv1.NodePoolAutoscaling, replicas int32) int32 {
+	if replicas > autoscaling.MaxReplicas {
+		replicas = autoscaling.MaxReplicas
+	}
+	if replicas < autoscaling.MinReplicas {
+		replicas = autoscaling.MinReplicas
+	}
+	return replicas
+}
+
+// DesiredResources returns the resources of the nodes of the pool that bring its usage closest to its targets once the
+// replicas reached their bounds, the reason for them and whether they differ from the current resources. At max
+// replicas the cpu grows with the cpu utilization and the memory with the jvm heap pressure above their targets, at
+// min replicas they shrink with usages below them. Requests and limits are scaled by current * usage / target and
+// bounded by the min and max resources of the vertical autoscaling config
+func DesiredResources(autoscaling opsterv1.NodePoolAutoscaling, replicas int32, current corev1.ResourceRequirements, usage NodePoolUsage) (corev1.ResourceRequirements, string, bool) {
+	vertical := autoscaling.Vertical
+	if vertical == nil || usage.Nodes == 0 {
+		return current, "", false
+	}
+	metrics := map[corev1.ResourceName]struct {
+		name   string
+		target *int32
+		usage  *float64
+	}{
+		corev1.ResourceCPU:    {"cpu utilization", autoscaling.TargetCPUUtilization, usage.CPUUtilization},
+		corev1.ResourceMemory: {"jvm heap pressure", autoscaling.TargetJvmHeapPressure, usage.JvmHeapPressure},
+	}
+
+	desired := boundResources(vertical, current)
+	var reasons []string
+	for _, name := range verticalResources(vertical) {
+		metric := metrics[name]
+		if metric.target == nil || metric.usage == nil {
+			continue
+		}
+		ratio := *metric.usage / float64(*metric.target)
+		if math.Abs(ratio-1) <= AutoscalingTolerance ||
+			(ratio > 1 && replicas < autoscaling.MaxReplicas) || (ratio < 1 && replicas > autoscaling.MinReplicas) {
+			continue
+		}
+		request := desired.Requests[name]
+		scaledRequest := boundQuantity(vertical, name, scaleQuantity(name, request, ratio))
+		if scaledRequest.Cmp(request) == 0 {
+			continue
+		}
+		desired.Requests[name] = scaledRequest
+		if limit, ok := desired.Limits[name]; ok {
+			desired.Limits[name] = boundQuantity(vertical, name, scaleQuantity(name, limit, ratio))
+		}
+		reasons = append(reasons, fmt.Sprintf("%s is %.0f%%, target %d%%, %s request %s", metric.name, *metric.usage, *metric.target, name, scaledRequest.String()))
+	}
+	if len(reasons) == 0 {
+		return current, "", false
+	}
+	return desired, strings.Join(reasons, ", "), true
+}
+
+// scaleQuantity returns the quantity multiplied by the ratio, rounded up to millicores or mebibytes
+func scaleQuantity(name corev1.ResourceName, quantity resource.Quantity, ratio float64) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(int64(math.Ceil(float64(quantity.MilliValue())*ratio)), resource.DecimalSI)
+	}
+	const mebibyte = 1024 * 1024
+	return *resource.NewQuantity(int64(math.Ceil(float64(quantity.Value())*ratio/mebibyte))*mebibyte, resource.BinarySI)
+}
+
+// boundQuantity returns the quantity limited to the min and max resources of the vertical autoscaling config
+func boundQuantity(vertical *opsterv1.NodePoolVerticalAutoscaling, name corev1.ResourceName, quantity resource.Quantity) resource.Quantity {
+	if max := vertical.MaxResources[name]; quantity.Cmp(max) > 0 {
+		return max.DeepCopy()
+	}
+	if min := vertical.MinResources[name]; quantity.Cmp(min) < 0 {
+		return min.DeepCopy()
+	}
+	return quantity
+}
+
+// boundResources returns the resources with the scaled requests and limits limited to the min and max resources of the
+// vertical autoscaling config, a missing request is set to the min resources
+func boundResources(vertical *opsterv1.NodePoolVerticalAutoscaling, resources corev1.ResourceRequirements) corev1.ResourceRequirements {
+	bounded := *resources.DeepCopy()
+	for _, name := range verticalResources(vertical) {
+		if bounded.Requests == nil {
+			bounded.Requests = corev1.ResourceList{}
+		}
+		request, ok := bounded.Requests[name]
+		if !ok {
+			request = vertical.MinResources[name]
+		}
+		bounded.Requests[name] = boundQuantity(vertical, name, request)
+		if limit, ok := bounded.Limits[name]; ok {
+			bounded.Limits[name] = boundQuantity(vertical, name, limit)
+		}
+	}
+	return bounded
+}
+
+// FindAutoscalingStatus returns the autoscaling status of the node pool with the passed component name
+func FindAutoscalingStatus(status opsterv1.ClusterStatus, component string) (opsterv1.NodePoolAutoscalingStatus, bool) {
+	for _, autoscalingStatus := range status.Autoscaling {
+		if autoscalingStatus.Component == component {
+			return autoscalingStatus, true
+		}
+	}
+	return opsterv1.NodePoolAutoscalingStatus{}, false
+}
+
+// SetAutoscalingStatus returns the list with the status of its node pool replaced by or extended with the passed one
+func SetAutoscalingStatus(statuses []opsterv1.NodePoolAutoscalingStatus, status opsterv1.NodePoolAutoscalingStatus) []opsterv1.NodePoolAutoscalingStatus {
+	result := []opsterv1.NodePoolAutoscalingStatus{}
+	for _, existing := range statuses {
+		if existing.Component != status.Component {
+			result = append(result, existing)
+		}
+	}
+	return append(result, status)
+}
+
+// EffectiveReplicas returns the number of replicas a node pool should have. Without autoscaling it's the replicas of the
+// spec or of its active scaling schedule, with autoscaling the last decision of the autoscaler, or the replicas of the
+// spec bounded by the min and max replicas if the autoscaler has not decided yet
+func EffectiveReplicas(status opsterv1.ClusterStatus, nodePool *opsterv1.NodePool) int32 {
+	scheduled := ScheduledNodePool(*nodePool, time.Now())
+	nodePool = &scheduled
+	if nodePool.Autoscaling == nil || ValidateAutoscaling(nodePool) != nil {
+		return nodePool.Replicas
+	}
+	if autoscalingStatus, found := FindAutoscalingStatus(status, nodePool.Component); found {
+		return boundReplicas(*nodePool.Autoscaling, autoscalingStatus.DesiredReplicas)
+	}
+	return boundReplicas(*nodePool.Autoscaling, nodePool.Replicas)
+}
+
+// EffectiveResources returns the resources the nodes of a node pool should have. Without vertical autoscaling it's the
+// resources of the spec, with vertical autoscaling the last decision of the autoscaler, or the resources of the spec
+// bounded by the min and max resources if the autoscaler has not decided yet
+func EffectiveResources(status opsterv1.ClusterStatus, nodePool *opsterv1.NodePool) corev1.ResourceRequirements {
+	if nodePool.Autoscaling == nil || nodePool.Autoscaling.Vertical == nil || ValidateAutoscaling(nodePool) != nil {
+		return nodePool.Resources
+	}
+	if autoscalingStatus, found := FindAutoscalingStatus(status, nodePool.Component); found && autoscalingStatus.Resources != nil {
+		return boundResources(nodePool.Autoscaling.Vertical, *autoscalingStatus.Resources)
+	}
+	return boundResources(nodePool.Autoscaling.Vertical, nodePool.Resources)
+}
+
+// MaxReplicas returns the largest number of replicas the node pool can have
+func MaxReplicas(nodePool *opsterv1.NodePool) int32 {
+	replicas := nodePool.Replicas
+	if nodePool.Autoscaling != nil && nodePool.Autoscaling.MaxReplicas > replicas {
+		replicas = nodePool.Autoscaling.MaxReplicas
+	}
+	for _, schedule := range nodePool.ScalingSchedules {
+		if schedule.Replicas > replicas {
+			replicas = schedule.Replicas
+		}
+	}
+	return replicas
+}
@@ -0,0 +1,247 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+)
+
+var _ = DescribeTable("autoscaling validation",
+	func(autoscaling opsterv1.NodePoolAutoscaling, expected string) {
+		err := ValidateAutoscaling(&opsterv1.NodePool{Component: "data", Autoscaling: &autoscaling})
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When a target is set", opsterv1.NodePoolAutoscaling{MinReplicas: 2, MaxReplicas: 5, TargetCPUUtilization: pointer.Int32(70)}, ""),
+	Entry("When no target is set", opsterv1.NodePoolAutoscaling{MinReplicas: 2, MaxReplicas: 5}, "at least one of targetCpuUtilization"),
+	Entry("When max is below min", opsterv1.NodePoolAutoscaling{MinReplicas: 3, MaxReplicas: 2, TargetDiskUsage: pointer.Int32(70)}, "maxReplicas has to be at least minReplicas"),
+	Entry("When min is zero", opsterv1.NodePoolAutoscaling{MaxReplicas: 2, TargetDiskUsage: pointer.Int32(70)}, "minReplicas has to be at least 1"),
+	Entry("When the window is invalid", opsterv1.NodePoolAutoscaling{
+		MinReplicas:                  1,
+		MaxReplicas:                  2,
+		TargetDiskUsage:              pointer.Int32(70),
+		ScaleDownStabilizationWindow: "soon",
+	}, "invalid scaleDownStabilizationWindow"),
+	Entry("When vertical scaling is set", opsterv1.NodePoolAutoscaling{
+		MinReplicas:           1,
+		MaxReplicas:           2,
+		TargetCPUUtilization:  pointer.Int32(70),
+		TargetJvmHeapPressure: pointer.Int32(75),
+		Vertical: &opsterv1.NodePoolVerticalAutoscaling{
+			MinResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+			MaxResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("8Gi")},
+		},
+	}, ""),
+	Entry("When vertical scaling has no bounds", opsterv1.NodePoolAutoscaling{
+		MinReplicas:          1,
+		MaxReplicas:          2,
+		TargetCPUUtilization: pointer.Int32(70),
+		Vertical: &opsterv1.NodePoolVerticalAutoscaling{
+			MinResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		},
+	}, "vertical has to set cpu or memory in both minResources and maxResources"),
+	Entry("When vertical scaling bounds are reversed", opsterv1.NodePoolAutoscaling{
+		MinReplicas:          1,
+		MaxReplicas:          2,
+		TargetCPUUtilization: pointer.Int32(70),
+		Vertical: &opsterv1.NodePoolVerticalAutoscaling{
+			MinResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			MaxResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+	}, "maxResources.cpu has to be at least minResources.cpu"),
+	Entry("When vertical scaling of memory has no heap target", opsterv1.NodePoolAutoscaling{
+		MinReplicas:          1,
+		MaxReplicas:          2,
+		TargetCPUUtilization: pointer.Int32(70),
+		Vertical: &opsterv1.NodePoolVerticalAutoscaling{
+			MinResources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			MaxResources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+		},
+	}, "vertical scaling of memory needs targetJvmHeapPressure"),
+	Entry("When vertical scaling sets other resources", opsterv1.NodePoolAutoscaling{
+		MinReplicas:          1,
+		MaxReplicas:          2,
+		TargetCPUUtilization: pointer.Int32(70),
+		Vertical: &opsterv1.NodePoolVerticalAutoscaling{
+			MinResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+			MaxResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		},
+	}, "vertical can only scale cpu and memory, not ephemeral-storage"),
+)
+
+var _ = It("rejects vertical scaling of memory with jvm options", func() {
+	err := ValidateAutoscaling(&opsterv1.NodePool{
+		Component: "data",
+		Jvm:       "-Xmx1G -Xms1G",
+		Autoscaling: &opsterv1.NodePoolAutoscaling{
+			MinReplicas:           1,
+			MaxReplicas:           2,
+			TargetJvmHeapPressure: pointer.Int32(75),
+			Vertical: &opsterv1.NodePoolVerticalAutoscaling{
+				MinResources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+				MaxResources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+			},
+		},
+	})
+	Expect(err).To(MatchError(ContainSubstring("vertical scaling of memory needs the jvm of the node pool to be unset")))
+})
+
+var _ = Describe("node pool usage", func() {
+	It("averages the stats of the nodes of the statefulset", func() {
+		body := `{"nodes":{
+			"a":{"name":"cluster-data-0","os":{"cpu":{"percent":60}},"jvm":{"mem":{"heap_used_percent":50}},"fs":{"total":{"total_in_bytes":100,"available_in_bytes":40}}},
+			"b":{"name":"cluster-data-1","os":{"cpu":{"percent":80}},"jvm":{"mem":{"heap_used_percent":70}},"fs":{"total":{"total_in_bytes":100,"available_in_bytes":20}}},
+			"c":{"name":"cluster-data-hot-0","os":{"cpu":{"percent":100}},"jvm":{"mem":{"heap_used_percent":100}}},
+			"d":{"name":"cluster-masters-0","os":{"cpu":{"percent":1}}}
+		}}`
+		stats := responses.NodesStatsResponse{}
+		Expect(json.Unmarshal([]byte(body), &stats)).To(Succeed())
+
+		usage := NodePoolUsageFromStats(stats, "cluster-data")
+		Expect(usage.Nodes).To(Equal(2))
+		Expect(*usage.CPUUtilization).To(BeNumerically("~", 70))
+		Expect(*usage.JvmHeapPressure).To(BeNumerically("~", 60))
+		Expect(*usage.DiskUsage).To(BeNumerically("~", 70))
+	})
+
+	It("leaves usages no node reported unset", func() {
+		body := `{"nodes":{"a":{"name":"cluster-data-0","os":{"cpu":{"percent":60}}}}}`
+		stats := responses.NodesStatsResponse{}
+		Expect(json.Unmarshal([]byte(body), &stats)).To(Succeed())
+
+		usage := NodePoolUsageFromStats(stats, "cluster-data")
+		Expect(usage.Nodes).To(Equal(1))
+		Expect(usage.JvmHeapPressure).To(BeNil())
+		Expect(usage.DiskUsage).To(BeNil())
+	})
+})
+
+var _ = DescribeTable("desired replicas",
+	func(usage NodePoolUsage, current int32, expected int32, reason string) {
+		autoscaling := opsterv1.NodePoolAutoscaling{
+			MinReplicas:           2,
+			MaxReplicas:           6,
+			TargetCPUUtilization:  pointer.Int32(50),
+			TargetJvmHeapPressure: pointer.Int32(75),
+		}
+		desired, actualReason := DesiredReplicas(autoscaling, current, usage)
+		Expect(desired).To(Equal(expected))
+		Expect(actualReason).To(ContainSubstring(reason))
+	},
+	Entry("When the usage is at the target", NodePoolUsage{Nodes: 3, CPUUtilization: pointer.Float64(52)}, int32(3), int32(3), "cpu utilization is 52%"),
+	Entry("When the cpu is above the target", NodePoolUsage{Nodes: 3, CPUUtilization: pointer.Float64(80)}, int32(3), int32(5), "cpu utilization is 80%"),
+	Entry("When the usage is below the targets", NodePoolUsage{Nodes: 4, CPUUtilization: pointer.Float64(20), JvmHeapPressure: pointer.Float64(30)}, int32(4), int32(2), ""),
+	Entry("When one metric is at its target", NodePoolUsage{Nodes: 4, CPUUtilization: pointer.Float64(20), JvmHeapPressure: pointer.Float64(75)}, int32(4), int32(4), "jvm heap pressure is 75%"),
+	Entry("When the max is reached", NodePoolUsage{Nodes: 4, JvmHeapPressure: pointer.Float64(150)}, int32(4), int32(6), "limited to 6 replicas"),
+	Entry("When the min is reached", NodePoolUsage{Nodes: 3, CPUUtilization: pointer.Float64(5)}, int32(3), int32(2), "limited to 2 replicas"),
+	Entry("When no node reported stats", NodePoolUsage{}, int32(3), int32(3), "no node of the pool reported stats"),
+	Entry("When no target was reported", NodePoolUsage{Nodes: 3, DiskUsage: pointer.Float64(90)}, int32(3), int32(3), "no usage reported"),
+)
+
+var _ = Describe("effective replicas", func() {
+	nodePool := opsterv1.NodePool{
+		Component: "data",
+		Replicas:  1,
+		Autoscaling: &opsterv1.NodePoolAutoscaling{
+			MinReplicas:          2,
+			MaxReplicas:          5,
+			TargetCPUUtilization: pointer.Int32(70),
+		},
+	}
+
+	It("uses the replicas without autoscaling", func() {
+		Expect(EffectiveReplicas(opsterv1.ClusterStatus{}, &opsterv1.NodePool{Replicas: 3})).To(Equal(int32(3)))
+	})
+
+	It("bounds the replicas before the first decision", func() {
+		Expect(EffectiveReplicas(opsterv1.ClusterStatus{}, &nodePool)).To(Equal(int32(2)))
+	})
+
+	It("uses the last decision of the autoscaler", func() {
+		status := opsterv1.ClusterStatus{}
+		status.Autoscaling = SetAutoscalingStatus(status.Autoscaling, opsterv1.NodePoolAutoscalingStatus{Component: "data", DesiredReplicas: 3})
+		status.Autoscaling = SetAutoscalingStatus(status.Autoscaling, opsterv1.NodePoolAutoscalingStatus{Component: "data", DesiredReplicas: 4})
+		Expect(status.Autoscaling).To(HaveLen(1))
+		Expect(EffectiveReplicas(status, &nodePool)).To(Equal(int32(4)))
+		Expect(MaxReplicas(&nodePool)).To(Equal(int32(5)))
+	})
+})
+
+var _ = Describe("desired resources", func() {
+	autoscaling := opsterv1.NodePoolAutoscaling{
+		MinReplicas:           2,
+		MaxReplicas:           4,
+		TargetCPUUtilization:  pointer.Int32(50),
+		TargetJvmHeapPressure: pointer.Int32(75),
+		Vertical: &opsterv1.NodePoolVerticalAutoscaling{
+			MinResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+			MaxResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("8Gi")},
+		},
+	}
+	current := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+
+	It("grows the cpu at max replicas", func() {
+		desired, reason, changed := DesiredResources(autoscaling, 4, current, NodePoolUsage{Nodes: 4, CPUUtilization: pointer.Float64(75), JvmHeapPressure: pointer.Float64(75)})
+		Expect(changed).To(BeTrue())
+		Expect(reason).To(Equal("cpu utilization is 75%, target 50%, cpu request 3"))
+		Expect(desired.Requests.Cpu().String()).To(Equal("3"))
+		// The limit grows by the same ratio, bounded by the max resources
+		Expect(desired.Limits.Cpu().String()).To(Equal("4"))
+		Expect(desired.Requests.Memory().String()).To(Equal("4Gi"))
+		// The current resources are not changed
+		Expect(current.Requests.Cpu().String()).To(Equal("2"))
+	})
+
+	It("bounds the resources by the max resources", func() {
+		desired, _, changed := DesiredResources(autoscaling, 4, current, NodePoolUsage{Nodes: 4, JvmHeapPressure: pointer.Float64(300)})
+		Expect(changed).To(BeTrue())
+		Expect(desired.Requests.Memory().String()).To(Equal("8Gi"))
+		Expect(desired.Limits.Memory().String()).To(Equal("8Gi"))
+	})
+
+	It("shrinks the memory at min replicas", func() {
+		desired, reason, changed := DesiredResources(autoscaling, 2, current, NodePoolUsage{Nodes: 2, CPUUtilization: pointer.Float64(50), JvmHeapPressure: pointer.Float64(30)})
+		Expect(changed).To(BeTrue())
+		Expect(reason).To(HavePrefix("jvm heap pressure is 30%, target 75%, memory request"))
+		// 40% of the request is bounded by the min resources
+		Expect(desired.Requests.Memory().String()).To(Equal("2Gi"))
+		Expect(desired.Requests.Cpu().String()).To(Equal("2"))
+	})
+
+	It("leaves the resources to the replicas between their bounds", func() {
+		_, _, changed := DesiredResources(autoscaling, 3, current, NodePoolUsage{Nodes: 3, CPUUtilization: pointer.Float64(90), JvmHeapPressure: pointer.Float64(10)})
+		Expect(changed).To(BeFalse())
+	})
+
+	It("does not shrink at max replicas", func() {
+		_, _, changed := DesiredResources(autoscaling, 4, current, NodePoolUsage{Nodes: 4, CPUUtilization: pointer.Float64(10)})
+		Expect(changed).To(BeFalse())
+	})
+
+	It("uses the resources of the spec bounded before the first decision", func() {
+		nodePool := opsterv1.NodePool{
+			Component: "data",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}},
+			Autoscaling: &autoscaling,
+		}
+		resources := EffectiveResources(opsterv1.ClusterStatus{}, &nodePool)
+		Expect(resources.Requests.Cpu().String()).To(Equal("4"))
+		Expect(resources.Requests.Memory().String()).To(Equal("2Gi"))
+
+		status := opsterv1.ClusterStatus{}
+		status.Autoscaling = SetAutoscalingStatus(status.Autoscaling, opsterv1.NodePoolAutoscalingStatus{Component: "data", DesiredReplicas: 2, Resources: &current})
+		Expect(EffectiveResources(status, &nodePool)).To(Equal(current))
+	})
+})
@@ -0,0 +1,50 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// indexBlockConsequences describes what each index block does to the indices created from a template
+var indexBlockConsequences = map[string]string{
+	"read_only":              "indices and their metadata cannot be changed",
+	"read_only_allow_delete": "indices cannot be changed, only deleted",
+	"read":                   "indices cannot be searched or read",
+	"write":                  "documents cannot be written to indices",
+	"metadata":               "settings and mappings of indices cannot be read or changed",
+}
+
+// IndexBlocks returns the index.blocks settings set to true, sorted, each with what it does to the indices created
+// from the template. Blocks OpenSearch does not know are returned without a consequence.
+func IndexBlocks(settings *apiextensionsv1.JSON) ([]string, error) {
+	if settings.Size() == 0 {
+		return nil, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+
+	var blocks []string
+	for key, value := range flat {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		if !strings.HasPrefix(key, "index.blocks.") || !strings.EqualFold(fmt.Sprint(value), "true") {
+			continue
+		}
+		block := strings.TrimPrefix(key, "index.blocks.")
+		if consequence, ok := indexBlockConsequences[block]; ok {
+			blocks = append(blocks, fmt.Sprintf("%s (%s)", key, consequence))
+		} else {
+			blocks = append(blocks, key)
+		}
+	}
+	sort.Strings(blocks)
+	return blocks, nil
+}
@@ -0,0 +1,23 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("index blocks",
+	func(settings string, expected []string) {
+		blocks, err := IndexBlocks(&apiextensionsv1.JSON{Raw: []byte(settings)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(blocks).To(Equal(expected))
+	},
+	Entry("When no block is set", `{"index":{"number_of_shards":1}}`, nil),
+	Entry("When the blocks are false", `{"index":{"blocks":{"write":false,"read_only":"false"}}}`, nil),
+	Entry("When blocks are set in nested and flat form", `{"index":{"blocks":{"write":true}},"blocks.read_only":"true","index.blocks.metadata":"TRUE"}`, []string{
+		"index.blocks.metadata (settings and mappings of indices cannot be read or changed)",
+		"index.blocks.read_only (indices and their metadata cannot be changed)",
+		"index.blocks.write (documents cannot be written to indices)",
+	}),
+	Entry("When an unknown block is set", `{"index":{"blocks":{"custom":true}}}`, []string{"index.blocks.custom"}),
+)
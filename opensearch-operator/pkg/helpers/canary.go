@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// CanaryIndexSettings returns the index settings in flat notation with the number of replicas set to 0, so an index
+// created with them does not change the health of the cluster
+func CanaryIndexSettings(settings *apiextensionsv1.JSON) (map[string]interface{}, error) {
+	parsed := map[string]interface{}{}
+	if settings.Size() > 0 {
+		if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+			return nil, err
+		}
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+	canary := map[string]interface{}{}
+	for key, value := range flat {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		canary[key] = value
+	}
+	delete(canary, "index.auto_expand_replicas")
+	canary["index.number_of_replicas"] = "0"
+	return canary, nil
+}
+
+// FieldTypeMismatches compares the types of the fields of the index mappings with the expected types by field path,
+// e.g. user.id for a property of an object or title.raw for a multi-field. It returns a sorted description of every
+// field that is not mapped or mapped as another type.
+func FieldTypeMismatches(mappings *apiextensionsv1.JSON, expected map[string]string) ([]string, error) {
+	parsed := map[string]interface{}{}
+	if mappings.Size() > 0 {
+		if err := json.Unmarshal(mappings.Raw, &parsed); err != nil {
+			return nil, err
+		}
+	}
+	var mismatches []string
+	for path, expectedType := range expected {
+		actual := mappedFieldType(parsed, strings.Split(path, "."))
+		switch {
+		case actual == "":
+			mismatches = append(mismatches, fmt.Sprintf("field %s is not mapped", path))
+		case actual != expectedType:
+			mismatches = append(mismatches, fmt.Sprintf("field %s is mapped as %s instead of %s", path, actual, expectedType))
+		}
+	}
+	sort.Strings(mismatches)
+	return mismatches, nil
+}
+
+// mappedFieldType returns the type of the field at the path below the definition, empty if it is not mapped
+func mappedFieldType(definition map[string]interface{}, path []string) string {
+	if len(path) == 0 {
+		return fieldType(definition)
+	}
+	for _, children := range []string{"properties", "fields"} {
+		fields, ok := definition[children].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if field, ok := fields[path[0]].(map[string]interface{}); ok {
+			if fieldType := mappedFieldType(field, path[1:]); fieldType != "" {
+				return fieldType
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = Describe("canary index", func() {
+	It("should create the canary index without replicas", func() {
+		settings, err := CanaryIndexSettings(&apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"2","auto_expand_replicas":"0-all"},"number_of_replicas":3}`)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(map[string]interface{}{
+			"index.number_of_shards":   "2",
+			"index.number_of_replicas": "0",
+		}))
+	})
+})
+
+var _ = DescribeTable("field type mismatches",
+	func(expected map[string]string, mismatches []string) {
+		mappings := &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"user":{"properties":{"id":{"type":"keyword"}}},"title":{"type":"text","fields":{"raw":{"type":"keyword"}}},"count":{"type":"long"}}}`)}
+		actual, err := FieldTypeMismatches(mappings, expected)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(mismatches))
+	},
+	Entry("When all fields are mapped as expected", map[string]string{"user": "object", "user.id": "keyword", "title": "text", "title.raw": "keyword"}, nil),
+	Entry("When fields are missing or mapped as another type", map[string]string{"count": "integer", "user.name": "keyword", "title": "text"}, []string{
+		"field count is mapped as long instead of integer",
+		"field user.name is not mapped",
+	}),
+)
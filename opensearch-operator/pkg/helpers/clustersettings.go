@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+)
+
+// DefaultClusterSettingsDenyList are the cluster settings OpensearchClusterSettings cannot manage unless the operator
+// is started with another deny list: the settings of the security plugin and the allocation settings the operator
+// sets itself while it restarts and drains nodes
+var DefaultClusterSettingsDenyList = []string{
+	"plugins.security.*",
+	"opendistro_security.*",
+	"cluster.routing.allocation.enable",
+	"cluster.routing.allocation.exclude._name",
+}
+
+// ValidateClusterSettings returns an error naming every setting of the spec that matches a pattern of the deny list,
+// in which * matches any part of a name, or is not a single setting
+func ValidateClusterSettings(spec v1.OpensearchClusterSettingsSpec, denyList []string) error {
+	var invalid []string
+	for scope, settings := range map[string]map[string]string{"persistent": spec.Persistent, "transient": spec.Transient} {
+		for name := range settings {
+			if strings.Contains(name, "*") {
+				invalid = append(invalid, fmt.Sprintf("%s setting %s must not contain *", scope, name))
+				continue
+			}
+			for _, pattern := range denyList {
+				if matched, _ := path.Match(pattern, name); matched {
+					invalid = append(invalid, fmt.Sprintf("%s setting %s is denied by %s", scope, name, pattern))
+					break
+				}
+			}
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid cluster settings: %s", strings.Join(invalid, "; "))
+}
+
+// ClusterSettingsChanges returns the settings to write to get from the existing to the desired settings of a scope, and
+// the sorted names of the settings whose existing value differs from the value last applied by the operator. Settings
+// that were applied before but are no longer desired are reset with a nil value.
+func ClusterSettingsChanges(desired map[string]string, existing map[string]interface{}, applied map[string]string) (map[string]interface{}, []string) {
+	changes := map[string]interface{}{}
+	var drifted []string
+	for name, value := range desired {
+		current, ok := existing[name]
+		if !ok || ClusterSettingString(current) != value {
+			changes[name] = value
+		}
+	}
+	for name, value := range applied {
+		current, ok := existing[name]
+		if _, wanted := desired[name]; !wanted && ok {
+			changes[name] = nil
+		}
+		if !ok || ClusterSettingString(current) != value {
+			drifted = append(drifted, name)
+		}
+	}
+	sort.Strings(drifted)
+	return changes, drifted
+}
+
+// ClusterSettingString returns the value of a flat cluster setting as it is written in the spec, list settings as
+// comma separated values
+func ClusterSettingString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, ClusterSettingString(item))
+		}
+		return strings.Join(values, ",")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
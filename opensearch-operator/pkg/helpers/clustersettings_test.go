@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("cluster settings validation",
+	func(spec v1.OpensearchClusterSettingsSpec, expected string) {
+		err := ValidateClusterSettings(spec, DefaultClusterSettingsDenyList)
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(expected))
+	},
+	Entry("When no setting is denied", v1.OpensearchClusterSettingsSpec{
+		Persistent: map[string]string{"cluster.max_shards_per_node": "2000"},
+		Transient:  map[string]string{"cluster.routing.allocation.node_concurrent_recoveries": "4"},
+	}, ""),
+	Entry("When security and allocation settings are set", v1.OpensearchClusterSettingsSpec{
+		Persistent: map[string]string{"plugins.security.audit.type": "internal_opensearch"},
+		Transient:  map[string]string{"cluster.routing.allocation.enable": "none"},
+	}, "invalid cluster settings: persistent setting plugins.security.audit.type is denied by plugins.security.*; "+
+		"transient setting cluster.routing.allocation.enable is denied by cluster.routing.allocation.enable"),
+	Entry("When a setting is a pattern", v1.OpensearchClusterSettingsSpec{
+		Persistent: map[string]string{"indices.recovery.*": "10"},
+	}, "invalid cluster settings: persistent setting indices.recovery.* must not contain *"),
+)
+
+var _ = Describe("cluster settings changes", func() {
+	It("should write changed settings and reset settings no longer desired", func() {
+		changes, drifted := ClusterSettingsChanges(
+			map[string]string{"cluster.max_shards_per_node": "2000", "action.auto_create_index": "false"},
+			map[string]interface{}{"cluster.max_shards_per_node": "1000", "action.auto_create_index": "false", "search.max_buckets": "20000"},
+			map[string]string{"cluster.max_shards_per_node": "1000", "search.max_buckets": "20000"},
+		)
+		Expect(changes).To(Equal(map[string]interface{}{"cluster.max_shards_per_node": "2000", "search.max_buckets": nil}))
+		Expect(drifted).To(BeEmpty())
+	})
+
+	It("should report settings changed outside of the operator", func() {
+		changes, drifted := ClusterSettingsChanges(
+			map[string]string{"cluster.max_shards_per_node": "2000", "cluster.routing.allocation.awareness.attributes": "zone,rack"},
+			map[string]interface{}{"cluster.max_shards_per_node": "5000", "cluster.routing.allocation.awareness.attributes": []interface{}{"zone", "rack"}},
+			map[string]string{"cluster.max_shards_per_node": "2000", "cluster.routing.allocation.awareness.attributes": "zone,rack"},
+		)
+		Expect(changes).To(Equal(map[string]interface{}{"cluster.max_shards_per_node": "2000"}))
+		Expect(drifted).To(Equal([]string{"cluster.max_shards_per_node"}))
+	})
+})
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// DefaultIndexCodec is available in every OpenSearch version
+const DefaultIndexCodec = "default"
+
+// indexCodecMinVersions maps the index codecs to the OpenSearch version that introduced them.
+// Codecs not listed here (e.g. provided by plugins) are not validated.
+var indexCodecMinVersions = map[string]string{
+	"default":          "1.0.0",
+	"best_compression": "1.0.0",
+	"zstd":             "2.9.0",
+	"zstd_no_dict":     "2.9.0",
+	"qat_lz4":          "2.14.0",
+	"qat_deflate":      "2.14.0",
+}
+
+// IndexCodec returns the index.codec set in the given index settings, or an empty string if no codec is set.
+// Both the nested ({"index": {"codec": ...}}) and the flat ({"index.codec": ...}) notation are supported.
+func IndexCodec(settings *apiextensionsv1.JSON) (string, error) {
+	parsed, container, key, err := findIndexCodec(settings)
+	if err != nil || parsed == nil {
+		return "", err
+	}
+	codec, _ := container[key].(string)
+	return codec, nil
+}
+
+// SetIndexCodec returns a copy of the given index settings with index.codec replaced by codec
+func SetIndexCodec(settings *apiextensionsv1.JSON, codec string) (*apiextensionsv1.JSON, error) {
+	parsed, container, key, err := findIndexCodec(settings)
+	if err != nil {
+		return nil, err
+	}
+	if parsed == nil {
+		return settings, nil
+	}
+	container[key] = codec
+	raw, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+// CheckIndexCodec returns an error if the index codec is not available in the given OpenSearch version
+func CheckIndexCodec(codec string, clusterVersion string) error {
+	minVersion, ok := indexCodecMinVersions[codec]
+	if !ok {
+		return nil
+	}
+	if CompareVersions(clusterVersion, minVersion) {
+		return fmt.Errorf("index codec %s requires OpenSearch %s or later, but the cluster runs %s", codec, minVersion, clusterVersion)
+	}
+	return nil
+}
+
+// findIndexCodec parses the settings and returns the map and key holding the codec,
+// parsed is nil if the settings do not set a codec
+func findIndexCodec(settings *apiextensionsv1.JSON) (parsed map[string]interface{}, container map[string]interface{}, key string, err error) {
+	if settings.Size() == 0 {
+		return nil, nil, "", nil
+	}
+	parsed = map[string]interface{}{}
+	if err = UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return nil, nil, "", err
+	}
+	for _, flatKey := range []string{"index.codec", "codec"} {
+		if _, ok := parsed[flatKey]; ok {
+			return parsed, parsed, flatKey, nil
+		}
+	}
+	if index, ok := parsed["index"].(map[string]interface{}); ok {
+		if _, ok := index["codec"]; ok {
+			return parsed, index, "codec", nil
+		}
+	}
+	return nil, nil, "", nil
+}
@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("index codec check",
+	func(codec string, clusterVersion string, supported bool) {
+		err := CheckIndexCodec(codec, clusterVersion)
+		if supported {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+		}
+	},
+	Entry("When no codec is set", "", "1.3.0", true),
+	Entry("When the default codec is used with version 1.3.0", "default", "1.3.0", true),
+	Entry("When best_compression is used with version 1.3.0", "best_compression", "1.3.0", true),
+	Entry("When zstd is used with version 2.8.0", "zstd", "2.8.0", false),
+	Entry("When zstd is used with version 2.9.0", "zstd", "2.9.0", true),
+	Entry("When zstd_no_dict is used with version 2.11.1", "zstd_no_dict", "2.11.1", true),
+	Entry("When qat_lz4 is used with version 2.13.0", "qat_lz4", "2.13.0", false),
+	Entry("When qat_deflate is used with version 2.14.0", "qat_deflate", "2.14.0", true),
+	Entry("When an unknown codec is used", "custom_codec", "1.3.0", true),
+	Entry("When the cluster version is unknown", "zstd", "", true),
+)
+
+var _ = DescribeTable("index codec lookup",
+	func(settings string, expectedCodec string, expectedSettings string) {
+		json := &apiextensionsv1.JSON{Raw: []byte(settings)}
+		codec, err := IndexCodec(json)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codec).To(Equal(expectedCodec))
+
+		replaced, err := SetIndexCodec(json, DefaultIndexCodec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(replaced.Raw)).To(Equal(expectedSettings))
+	},
+	Entry("When the codec is nested", `{"index":{"codec":"zstd","number_of_shards":1}}`, "zstd", `{"index":{"codec":"default","number_of_shards":1}}`),
+	Entry("When the codec is flat", `{"index.codec":"zstd"}`, "zstd", `{"index.codec":"default"}`),
+	Entry("When the codec has no index prefix", `{"codec":"zstd"}`, "zstd", `{"codec":"default"}`),
+	Entry("When no codec is set", `{"index":{"number_of_shards":1}}`, "", `{"index":{"number_of_shards":1}}`),
+)
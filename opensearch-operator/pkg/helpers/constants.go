@@ -12,6 +12,21 @@ const (
 	NodePoolLabel                = "opster.io/opensearch-nodepool"
 	OsUserNameAnnotation         = "opensearchuser/name"
 	OsUserNamespaceAnnotation    = "opensearchuser/namespace"
+	RollbackAnnotation           = "opensearch.opster.io/rollback"
+	FreezeAnnotation             = "opensearch.opster.io/freeze-managed-objects"
+	EndpointFlavorAnnotation     = "opensearch.opster.io/endpoint-flavor"
+	EndpointFlavorServerless     = "serverless"
+	EndpointDiscoveryAnnotation  = "opensearch.opster.io/endpoint-discovery-service"
+	ProxyURLAnnotation           = "opensearch.opster.io/proxy-url"
+	NoProxyAnnotation            = "opensearch.opster.io/no-proxy"
+	SyncWaveAnnotation           = "opensearch.opster.io/sync-wave"
+	AllowMappingTypeChanges      = "opensearch.opster.io/allow-mapping-type-changes"
+	ApprovedGenerationAnnotation = "opensearch.opster.io/approved-generation"
+	LogRequestsAnnotation        = "opensearch.opster.io/log-requests"
+	GitRevisionAnnotation        = "opensearch.opster.io/git-revision"
+	EventVerbosityAnnotation     = "opensearch.opster.io/event-verbosity"
+	AcknowledgeIndexBlocks       = "opensearch.opster.io/acknowledge-index-blocks"
+	ZoneAnnotation               = "opensearch.opster.io/zone"
 	DnsBaseEnvVariable           = "DNS_BASE"
 	ParallelRecoveryEnabled      = "PARALLEL_RECOVERY_ENABLED"
 	SkipInitContainerEnvVariable = "SKIP_INIT_CONTAINER"
@@ -0,0 +1,153 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronLookback is how far back the last run of a cron expression is searched
+const cronLookback = 366 * 24 * time.Hour
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// cronExpression is a parsed standard cron expression with the fields minute, hour, day of month, month and day of week
+type cronExpression struct {
+	minutes  [60]bool
+	hours    [24]bool
+	days     [32]bool
+	months   [13]bool
+	weekdays [8]bool
+	// As in cron a day matches if either the day of month or the day of week matches when both are restricted
+	daysRestricted     bool
+	weekdaysRestricted bool
+}
+
+// parseCronField sets the values of the field in values. Supported are *, numbers, names, ranges, steps and lists
+func parseCronField(field string, values []bool, min int, max int, names map[string]int) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed < 1 {
+				return fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = parsed
+		}
+
+		start, end := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseCronValue(from, min, max, names); err != nil {
+				return err
+			}
+			end = start
+			if isRange {
+				if end, err = parseCronValue(to, min, max, names); err != nil {
+					return err
+				}
+			} else if hasStep {
+				end = max
+			}
+			if end < start {
+				return fmt.Errorf("invalid range %q", rangePart)
+			}
+		}
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+	return nil
+}
+
+func parseCronValue(value string, min int, max int, names map[string]int) (int, error) {
+	if named, ok := names[strings.ToLower(value)]; ok {
+		return named, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < min || parsed > max {
+		return 0, fmt.Errorf("invalid value %q, has to be between %d and %d", value, min, max)
+	}
+	return parsed, nil
+}
+
+// parseCronExpression parses a cron expression with five fields, e.g. "0 8 * * MON-FRI"
+func parseCronExpression(expression string) (*cronExpression, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expression, len(fields))
+	}
+	cron := &cronExpression{}
+	for i, field := range []struct {
+		values []bool
+		min    int
+		max    int
+		names  map[string]int
+	}{
+		{cron.minutes[:], 0, 59, nil},
+		{cron.hours[:], 0, 23, nil},
+		{cron.days[:], 1, 31, nil},
+		{cron.months[:], 1, 12, cronMonthNames},
+		{cron.weekdays[:], 0, 7, cronWeekdayNames},
+	} {
+		if err := parseCronField(fields[i], field.values, field.min, field.max, field.names); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expression, err)
+		}
+	}
+	// Both 0 and 7 are Sunday
+	if cron.weekdays[7] {
+		cron.weekdays[0] = true
+	}
+	cron.daysRestricted = fields[2] != "*"
+	cron.weekdaysRestricted = fields[4] != "*"
+	return cron, nil
+}
+
+func (c *cronExpression) matchesDay(day time.Time) bool {
+	if !c.months[day.Month()] {
+		return false
+	}
+	dayMatches := c.days[day.Day()]
+	weekdayMatches := c.weekdays[day.Weekday()]
+	if c.daysRestricted && c.weekdaysRestricted {
+		return dayMatches || weekdayMatches
+	}
+	return dayMatches && weekdayMatches
+}
+
+// previous returns the last time at or before now the expression matched, in the location of now. false is returned
+// if it did not match within the lookback
+func (c *cronExpression) previous(now time.Time) (time.Time, bool) {
+	loc := now.Location()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	for day.After(now.Add(-cronLookback)) {
+		if c.matchesDay(day) {
+			for hour := 23; hour >= 0; hour-- {
+				if !c.hours[hour] {
+					continue
+				}
+				for minute := 59; minute >= 0; minute-- {
+					if !c.minutes[minute] {
+						continue
+					}
+					candidate := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+					if !candidate.After(now) {
+						return candidate, true
+					}
+				}
+			}
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()-1, 0, 0, 0, 0, loc)
+	}
+	return time.Time{}, false
+}
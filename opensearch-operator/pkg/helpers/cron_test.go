@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("cron expression parsing",
+	func(expression string, expected string) {
+		_, err := parseCronExpression(expression)
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When every field is a wildcard", "* * * * *", ""),
+	Entry("When names, ranges, steps and lists are used", "*/15 8-18/2 1,15 JAN-jun mon-FRI", ""),
+	Entry("When Sunday is 7", "0 0 * * 7", ""),
+	Entry("When a field is missing", "0 8 * *", "expected 5 fields, got 4"),
+	Entry("When a value is out of range", "60 8 * * *", `invalid value "60", has to be between 0 and 59`),
+	Entry("When a range is reversed", "0 18-8 * * *", `invalid range "18-8"`),
+	Entry("When a step is invalid", "*/0 * * * *", `invalid step "0"`),
+)
+
+var _ = DescribeTable("previous run of a cron expression",
+	func(expression string, now string, expected string) {
+		cron, err := parseCronExpression(expression)
+		Expect(err).ToNot(HaveOccurred())
+		nowTime, err := time.Parse(time.RFC3339, now)
+		Expect(err).ToNot(HaveOccurred())
+
+		previous, found := cron.previous(nowTime)
+		if expected == "" {
+			Expect(found).To(BeFalse())
+			return
+		}
+		Expect(found).To(BeTrue())
+		Expect(previous.Format(time.RFC3339)).To(Equal(expected))
+	},
+	// 2024-03-13 is a Wednesday
+	Entry("When it runs at now", "0 8 * * *", "2024-03-13T08:00:00Z", "2024-03-13T08:00:00Z"),
+	Entry("When it ran earlier the same day", "0 8 * * *", "2024-03-13T10:30:00Z", "2024-03-13T08:00:00Z"),
+	Entry("When it ran the day before", "0 8 * * *", "2024-03-13T07:59:00Z", "2024-03-12T08:00:00Z"),
+	Entry("When it runs on weekdays only", "0 20 * * MON-FRI", "2024-03-17T12:00:00Z", "2024-03-15T20:00:00Z"),
+	Entry("When steps are used", "*/20 * * * *", "2024-03-13T10:59:00Z", "2024-03-13T10:40:00Z"),
+	Entry("When day of month and day of week are restricted", "0 0 1 * SUN", "2024-03-13T12:00:00Z", "2024-03-10T00:00:00Z"),
+	Entry("When it runs on leap days only", "0 0 29 2 *", "2024-03-13T12:00:00Z", "2024-02-29T00:00:00Z"),
+	Entry("When it did not run within a year", "0 0 30 2 *", "2024-03-13T12:00:00Z", ""),
+)
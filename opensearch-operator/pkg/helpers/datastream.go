@@ -0,0 +1,69 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+)
+
+// ValidateDataStreamName returns an error if OpenSearch would reject the name of the data stream
+func ValidateDataStreamName(name string) error {
+	if strings.Contains(name, "*") {
+		return fmt.Errorf("data stream name %q must not contain *", name)
+	}
+	if err := ValidateIndexPattern(name); err != nil {
+		return fmt.Errorf("invalid data stream name: %w", err)
+	}
+	return nil
+}
+
+// MatchingIndexTemplate returns the name of the index template OpenSearch applies to a new index or data stream of
+// the name, the template with the highest priority among those with a matching index pattern. It returns false if no
+// template matches
+func MatchingIndexTemplate(name string, templates map[string]requests.IndexTemplate) (string, bool) {
+	names := make([]string, 0, len(templates))
+	for templateName := range templates {
+		names = append(names, templateName)
+	}
+	sort.Strings(names)
+
+	matching := ""
+	found := false
+	for _, templateName := range names {
+		template := templates[templateName]
+		if found && template.Priority <= templates[matching].Priority {
+			continue
+		}
+		for _, pattern := range template.IndexPatterns {
+			if simpleMatch(pattern, name) {
+				matching = templateName
+				found = true
+				break
+			}
+		}
+	}
+	return matching, found
+}
+
+// simpleMatch matches the name against a pattern in which * matches any sequence of characters, the only wildcard
+// of index patterns
+func simpleMatch(pattern string, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(name, part)
+		if index < 0 {
+			return false
+		}
+		name = name[index+len(part):]
+	}
+	return strings.HasSuffix(name, parts[len(parts)-1])
+}
@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("data stream name validation",
+	func(name string, expected string) {
+		err := ValidateDataStreamName(name)
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When the name is valid", "logs-nginx", ""),
+	Entry("When the name contains a wildcard", "logs-*", `data stream name "logs-*" must not contain *`),
+	Entry("When the name has uppercase letters", "Logs", `index pattern "Logs" must be lowercase`),
+	Entry("When the name starts with an underscore", "_logs", "must not start with -, _ or +"),
+)
+
+var _ = DescribeTable("matching index templates",
+	func(name string, expected string, expectedFound bool) {
+		templates := map[string]requests.IndexTemplate{
+			"logs":       {IndexPatterns: []string{"logs-*"}, Priority: 100},
+			"logs-nginx": {IndexPatterns: []string{"logs-nginx-*", "nginx"}, Priority: 200},
+			"metrics":    {IndexPatterns: []string{"metrics-*-prod"}},
+		}
+		template, found := MatchingIndexTemplate(name, templates)
+		Expect(found).To(Equal(expectedFound))
+		Expect(template).To(Equal(expected))
+	},
+	Entry("When one template matches", "logs-app", "logs", true),
+	Entry("When several templates match", "logs-nginx-access", "logs-nginx", true),
+	Entry("When a pattern without wildcard matches", "nginx", "logs-nginx", true),
+	Entry("When a wildcard is in the middle of a pattern", "metrics-node-prod", "metrics", true),
+	Entry("When the suffix does not match", "metrics-node-dev", "", false),
+	Entry("When no template matches", "traces", "", false),
+)
@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+// detectorResultIndexPrefix is the prefix OpenSearch requires for custom result indices of detectors
+const detectorResultIndexPrefix = "opensearch-ad-plugin-result-"
+
+// TranslateDetectorToRequest validates the spec and rewrites the CRD format to the gateway format. The error names
+// every problem of the spec OpenSearch would reject
+func TranslateDetectorToRequest(spec v1.OpensearchAnomalyDetectorSpec, name string) (requests.AnomalyDetector, error) {
+	var invalid []string
+
+	request := requests.AnomalyDetector{
+		Name:              name,
+		Description:       spec.Description,
+		TimeField:         spec.TimeField,
+		Indices:           spec.Indices,
+		FeatureAttributes: []requests.DetectorFeature{},
+		FilterQuery:       spec.FilterQuery,
+		CategoryField:     spec.CategoryFields,
+		ShingleSize:       pointer.IntDeref(spec.ShingleSize, 8),
+		ResultIndex:       spec.ResultIndex,
+	}
+
+	for _, index := range spec.Indices {
+		if err := ValidateIndexPattern(index); err != nil {
+			invalid = append(invalid, err.Error())
+		}
+	}
+
+	detectionInterval := spec.DetectionInterval
+	if detectionInterval == "" {
+		detectionInterval = "10m"
+	}
+	if minutes, ok := detectorMinutes(detectionInterval); ok && minutes > 0 {
+		request.DetectionInterval = requests.DetectorInterval{Period: requests.DetectorPeriod{Interval: minutes, Unit: "Minutes"}}
+	} else {
+		invalid = append(invalid, fmt.Sprintf("detectionInterval %q has to be a positive whole number of minutes, e.g. 10m", detectionInterval))
+	}
+	if spec.WindowDelay != "" {
+		if minutes, ok := detectorMinutes(spec.WindowDelay); ok {
+			request.WindowDelay = &requests.DetectorInterval{Period: requests.DetectorPeriod{Interval: minutes, Unit: "Minutes"}}
+		} else {
+			invalid = append(invalid, fmt.Sprintf("windowDelay %q has to be a whole number of minutes, e.g. 1m", spec.WindowDelay))
+		}
+	}
+
+	featureNames := map[string]bool{}
+	for _, feature := range spec.Features {
+		if featureNames[feature.Name] {
+			invalid = append(invalid, fmt.Sprintf("feature %s is defined more than once", feature.Name))
+		}
+		featureNames[feature.Name] = true
+		var aggregation map[string]interface{}
+		if err := json.Unmarshal(feature.AggregationQuery.Raw, &aggregation); err != nil || len(aggregation) != 1 {
+			invalid = append(invalid, fmt.Sprintf("aggregationQuery of feature %s has to be an object with the name of the aggregation as its only key", feature.Name))
+		}
+		request.FeatureAttributes = append(request.FeatureAttributes, requests.DetectorFeature{
+			FeatureName:      feature.Name,
+			FeatureEnabled:   pointer.BoolDeref(feature.Enabled, true),
+			AggregationQuery: feature.AggregationQuery,
+		})
+	}
+
+	categoryFields := map[string]bool{}
+	for _, field := range spec.CategoryFields {
+		if categoryFields[field] {
+			invalid = append(invalid, fmt.Sprintf("category field %s is listed more than once", field))
+		}
+		categoryFields[field] = true
+	}
+
+	if spec.ResultIndex != "" && !strings.HasPrefix(spec.ResultIndex, detectorResultIndexPrefix) {
+		invalid = append(invalid, fmt.Sprintf("resultIndex %q has to start with %s", spec.ResultIndex, detectorResultIndexPrefix))
+	}
+
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return requests.AnomalyDetector{}, fmt.Errorf("invalid detector: %s", strings.Join(invalid, "; "))
+	}
+	return request, nil
+}
+
+// detectorMinutes parses a time value that is a whole number of minutes
+func detectorMinutes(value string) (int, bool) {
+	duration, err := ParseTimeValue(value)
+	if err != nil || duration < 0 || duration%time.Minute != 0 {
+		return 0, false
+	}
+	return int(duration / time.Minute), true
+}
+
+// DetectorsEqual returns true if the detector in OpenSearch holds everything of the desired detector. OpenSearch
+// adds fields to detectors, such as the ids of the features and the default filter query, which are ignored
+func DetectorsEqual(desired requests.AnomalyDetector, existing *apiextensionsv1.JSON) (bool, error) {
+	return isJSONSubset(desired, existing)
+}
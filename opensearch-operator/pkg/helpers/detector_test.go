@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+func detectorSpec() v1.OpensearchAnomalyDetectorSpec {
+	return v1.OpensearchAnomalyDetectorSpec{
+		Indices:   []string{"logs-*"},
+		TimeField: "@timestamp",
+		Features: []v1.DetectorFeature{{
+			Name:             "bytes",
+			AggregationQuery: apiextensionsv1.JSON{Raw: []byte(`{"total_bytes":{"sum":{"field":"bytes"}}}`)},
+		}},
+		WindowDelay:    "1m",
+		CategoryFields: []string{"host"},
+	}
+}
+
+var _ = DescribeTable("detector validation",
+	func(modify func(spec *v1.OpensearchAnomalyDetectorSpec), expected string) {
+		spec := detectorSpec()
+		modify(&spec)
+		_, err := TranslateDetectorToRequest(spec, "bytes-per-host")
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When the detector is complete", func(spec *v1.OpensearchAnomalyDetectorSpec) {}, ""),
+	Entry("When the interval is not whole minutes", func(spec *v1.OpensearchAnomalyDetectorSpec) {
+		spec.DetectionInterval = "90s"
+	}, `detectionInterval "90s" has to be a positive whole number of minutes`),
+	Entry("When the window delay is not a time value", func(spec *v1.OpensearchAnomalyDetectorSpec) {
+		spec.WindowDelay = "late"
+	}, `windowDelay "late" has to be a whole number of minutes`),
+	Entry("When a feature is defined twice", func(spec *v1.OpensearchAnomalyDetectorSpec) {
+		spec.Features = append(spec.Features, spec.Features[0])
+	}, "feature bytes is defined more than once"),
+	Entry("When an aggregation has no name", func(spec *v1.OpensearchAnomalyDetectorSpec) {
+		spec.Features[0].AggregationQuery = apiextensionsv1.JSON{Raw: []byte(`{"sum":{"field":"bytes"},"max":{"field":"bytes"}}`)}
+	}, "aggregationQuery of feature bytes has to be an object with the name of the aggregation as its only key"),
+	Entry("When a category field is listed twice", func(spec *v1.OpensearchAnomalyDetectorSpec) {
+		spec.CategoryFields = []string{"host", "host"}
+	}, "category field host is listed more than once"),
+	Entry("When the result index has no prefix", func(spec *v1.OpensearchAnomalyDetectorSpec) {
+		spec.ResultIndex = "ad-results"
+	}, `resultIndex "ad-results" has to start with opensearch-ad-plugin-result-`),
+)
+
+var _ = Describe("detector request bodies", func() {
+	It("should translate the spec", func() {
+		spec := detectorSpec()
+		spec.Features[0].Enabled = pointer.Bool(false)
+		request, err := TranslateDetectorToRequest(spec, "bytes-per-host")
+		Expect(err).ToNot(HaveOccurred())
+		raw, err := json.Marshal(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raw).To(MatchJSON(`{
+			"name": "bytes-per-host",
+			"time_field": "@timestamp",
+			"indices": ["logs-*"],
+			"feature_attributes": [{
+				"feature_name": "bytes",
+				"feature_enabled": false,
+				"aggregation_query": {"total_bytes": {"sum": {"field": "bytes"}}}
+			}],
+			"detection_interval": {"period": {"interval": 10, "unit": "Minutes"}},
+			"window_delay": {"period": {"interval": 1, "unit": "Minutes"}},
+			"category_field": ["host"],
+			"shingle_size": 8
+		}`))
+	})
+
+	It("should ignore the fields OpenSearch adds", func() {
+		request, err := TranslateDetectorToRequest(detectorSpec(), "bytes-per-host")
+		Expect(err).ToNot(HaveOccurred())
+		existing := &apiextensionsv1.JSON{Raw: []byte(`{
+			"name": "bytes-per-host",
+			"description": "",
+			"time_field": "@timestamp",
+			"indices": ["logs-*"],
+			"filter_query": {"match_all": {"boost": 1.0}},
+			"feature_attributes": [{
+				"feature_id": "Zx6hMIwB",
+				"feature_name": "bytes",
+				"feature_enabled": true,
+				"aggregation_query": {"total_bytes": {"sum": {"field": "bytes"}}}
+			}],
+			"detection_interval": {"period": {"interval": 10, "unit": "Minutes"}},
+			"window_delay": {"period": {"interval": 1, "unit": "Minutes"}},
+			"category_field": ["host"],
+			"shingle_size": 8,
+			"schema_version": 0,
+			"last_update_time": 1700000000000
+		}`)}
+		Expect(DetectorsEqual(request, existing)).To(BeTrue())
+		request.ShingleSize = 4
+		Expect(DetectorsEqual(request, existing)).To(BeFalse())
+	})
+})
@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	JSONChangeAdd     = "add"
+	JSONChangeRemove  = "remove"
+	JSONChangeReplace = "replace"
+)
+
+// JSONChange is a difference between two JSON documents at the dot separated path
+type JSONChange struct {
+	Op   string          `json:"op"`
+	Path string          `json:"path"`
+	From json.RawMessage `json:"from,omitempty"`
+	To   json.RawMessage `json:"to,omitempty"`
+}
+
+// JSONDiff returns the changes needed to turn from into to, sorted by path. Both are compared in their JSON form,
+// objects are compared key by key while arrays and scalar values are compared as a whole.
+func JSONDiff(from interface{}, to interface{}) ([]JSONChange, error) {
+	fromValue, err := toJSONValue(from)
+	if err != nil {
+		return nil, err
+	}
+	toValue, err := toJSONValue(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []JSONChange
+	if err := diffJSONValues("", fromValue, toValue, &changes); err != nil {
+		return nil, err
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func toJSONValue(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var parsed interface{}
+	err = UnmarshalPreservingNumbers(raw, &parsed)
+	return parsed, err
+}
+
+func diffJSONValues(path string, from interface{}, to interface{}, changes *[]JSONChange) error {
+	fromObject, fromIsObject := from.(map[string]interface{})
+	toObject, toIsObject := to.(map[string]interface{})
+	if fromIsObject && toIsObject {
+		for key, fromNested := range fromObject {
+			if err := diffJSONValues(joinJSONPath(path, key), fromNested, toObject[key], changes); err != nil {
+				return err
+			}
+		}
+		for key, toNested := range toObject {
+			if _, ok := fromObject[key]; !ok {
+				if err := diffJSONValues(joinJSONPath(path, key), nil, toNested, changes); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+	change := JSONChange{Op: JSONChangeReplace, Path: path}
+	switch {
+	case from == nil:
+		change.Op = JSONChangeAdd
+	case to == nil:
+		change.Op = JSONChangeRemove
+	}
+	var err error
+	if from != nil {
+		if change.From, err = json.Marshal(from); err != nil {
+			return err
+		}
+	}
+	if to != nil {
+		if change.To, err = json.Marshal(to); err != nil {
+			return err
+		}
+	}
+	*changes = append(*changes, change)
+	return nil
+}
+
+func joinJSONPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return strings.Join([]string{path, key}, ".")
+}
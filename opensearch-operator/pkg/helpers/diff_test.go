@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("JSON diff",
+	func(from string, to string, expected []JSONChange) {
+		var fromValue, toValue interface{}
+		Expect(json.Unmarshal([]byte(from), &fromValue)).To(Succeed())
+		Expect(json.Unmarshal([]byte(to), &toValue)).To(Succeed())
+		changes, err := JSONDiff(fromValue, toValue)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal(expected))
+	},
+	Entry("When the documents are equal", `{"a":{"b":1}}`, `{"a":{"b":1}}`, nil),
+	Entry("When a nested value is added", `{"a":{}}`, `{"a":{"b":"x"}}`, []JSONChange{
+		{Op: JSONChangeAdd, Path: "a.b", To: json.RawMessage(`"x"`)},
+	}),
+	Entry("When a nested object is removed", `{"a":{"b":{"c":1}},"d":2}`, `{"d":2}`, []JSONChange{
+		{Op: JSONChangeRemove, Path: "a", From: json.RawMessage(`{"b":{"c":1}}`)},
+	}),
+	Entry("When values are replaced", `{"a":1,"b":[1,2],"c":"x"}`, `{"a":2,"b":[2],"c":"x"}`, []JSONChange{
+		{Op: JSONChangeReplace, Path: "a", From: json.RawMessage(`1`), To: json.RawMessage(`2`)},
+		{Op: JSONChangeReplace, Path: "b", From: json.RawMessage(`[1,2]`), To: json.RawMessage(`[2]`)},
+	}),
+)
@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+)
+
+// DefaultDrainTimeout is used if the cluster sets no drain timeout
+const DefaultDrainTimeout = 30 * time.Minute
+
+// DrainTimeout returns the time after which a drain that has not finished is reported as stuck
+func DrainTimeout(confMgmt opsterv1.ConfMgmt) (time.Duration, error) {
+	if confMgmt.DrainTimeout == "" {
+		return DefaultDrainTimeout, nil
+	}
+	timeout, err := ParseTimeValue(confMgmt.DrainTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid drainTimeout: %w", err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid drainTimeout: %s", confMgmt.DrainTimeout)
+	}
+	return timeout, nil
+}
+
+// CountShardsOnNode returns the number of shards of _cat/shards held by the node. A shard relocating away from the node
+// is listed as "<node> -> <ip> <id> <target node>" and still counts, as the node holds it until the relocation is done
+func CountShardsOnNode(shards []responses.CatShardsResponse, nodeName string) int {
+	count := 0
+	for _, shard := range shards {
+		source, _, _ := strings.Cut(shard.NodeName, " ")
+		if source == nodeName {
+			count++
+		}
+	}
+	return count
+}
+
+// RemoveExcludedNode returns the comma separated list of excluded node names without the passed node
+func RemoveExcludedNode(excluded string, nodeName string) string {
+	remaining := []string{}
+	for _, name := range strings.Split(excluded, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && name != nodeName {
+			remaining = append(remaining, name)
+		}
+	}
+	return strings.Join(remaining, ",")
+}
+
+// FindDrainStatus returns the drain in the status of the node pool with the passed name
+func FindDrainStatus(status opsterv1.ClusterStatus, component string) (opsterv1.NodeDrainStatus, bool) {
+	for _, drain := range status.Drains {
+		if drain.Component == component {
+			return drain, true
+		}
+	}
+	return opsterv1.NodeDrainStatus{}, false
+}
+
+// SetDrainStatus returns the list with the drain of its node pool replaced by or extended with the passed one
+func SetDrainStatus(drains []opsterv1.NodeDrainStatus, drain opsterv1.NodeDrainStatus) []opsterv1.NodeDrainStatus {
+	return append(RemoveDrainStatus(drains, drain.Component), drain)
+}
+
+// RemoveDrainStatus returns the list without the drain of the node pool with the passed name
+func RemoveDrainStatus(drains []opsterv1.NodeDrainStatus, component string) []opsterv1.NodeDrainStatus {
+	result := []opsterv1.NodeDrainStatus{}
+	for _, existing := range drains {
+		if existing.Component != component {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
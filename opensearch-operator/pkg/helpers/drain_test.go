@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("node drain", func() {
+	It("counts the shards held by the node", func() {
+		shards := []responses.CatShardsResponse{
+			{Index: "logs", Shard: "0", NodeName: "cluster-data-2"},
+			{Index: "logs", Shard: "1", NodeName: "cluster-data-2 -> 10.0.0.1 aBcD cluster-data-0"},
+			{Index: "logs", Shard: "2", NodeName: "cluster-data-20"},
+			{Index: "logs", Shard: "3", NodeName: "cluster-data-0"},
+			{Index: "logs", Shard: "4", NodeName: ""},
+		}
+		Expect(CountShardsOnNode(shards, "cluster-data-2")).To(Equal(2))
+		Expect(CountShardsOnNode(shards, "cluster-data-1")).To(Equal(0))
+	})
+
+	DescribeTable("removing a node from the exclusion list",
+		func(excluded string, expected string) {
+			Expect(RemoveExcludedNode(excluded, "cluster-data-1")).To(Equal(expected))
+		},
+		Entry("When it is the only node", "cluster-data-1", ""),
+		Entry("When other nodes share its prefix", "cluster-data-10,cluster-data-1,cluster-data-11", "cluster-data-10,cluster-data-11"),
+		Entry("When it is not excluded", "cluster-data-2", "cluster-data-2"),
+		Entry("When the list has empty entries", ",cluster-data-2,,cluster-data-1", "cluster-data-2"),
+	)
+
+	DescribeTable("drain timeout",
+		func(timeout string, expected time.Duration, expectedErr string) {
+			actual, err := DrainTimeout(opsterv1.ConfMgmt{DrainTimeout: timeout})
+			if expectedErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(expected))
+		},
+		Entry("When no timeout is set", "", DefaultDrainTimeout, ""),
+		Entry("When a timeout is set", "2h", 2*time.Hour, ""),
+		Entry("When the timeout is disabled", "-1", time.Duration(0), "invalid drainTimeout"),
+		Entry("When the timeout is invalid", "later", time.Duration(0), "invalid drainTimeout"),
+	)
+
+	It("keeps one drain per node pool", func() {
+		drains := SetDrainStatus(nil, opsterv1.NodeDrainStatus{Component: "data", Node: "cluster-data-2", RemainingShards: 5})
+		drains = SetDrainStatus(drains, opsterv1.NodeDrainStatus{Component: "hot", Node: "cluster-hot-1"})
+		drains = SetDrainStatus(drains, opsterv1.NodeDrainStatus{Component: "data", Node: "cluster-data-2", RemainingShards: 1})
+
+		drain, found := FindDrainStatus(opsterv1.ClusterStatus{Drains: drains}, "data")
+		Expect(found).To(BeTrue())
+		Expect(drain.RemainingShards).To(Equal(int32(1)))
+		Expect(RemoveDrainStatus(drains, "data")).To(HaveLen(1))
+	})
+})
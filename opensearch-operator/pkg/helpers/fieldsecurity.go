@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+)
+
+// FieldSecurityDrift returns a description of every way the index permissions of the role fail to provide the field
+// level security required by a component template. Index patterns and fields are compared literally, wildcards are
+// not expanded.
+func FieldSecurityDrift(required opsterv1.ComponentTemplateFieldSecurity, role opsterv1.OpensearchRoleSpec) []string {
+	var drift []string
+	for _, pattern := range required.IndexPatterns {
+		var covering []opsterv1.IndexPermissionSpec
+		for _, permission := range role.IndexPermissions {
+			for _, rolePattern := range permission.IndexPatterns {
+				if rolePattern == pattern {
+					covering = append(covering, permission)
+					break
+				}
+			}
+		}
+		if len(covering) == 0 {
+			drift = append(drift, fmt.Sprintf("no index permission for %s", pattern))
+			continue
+		}
+
+		exposed := map[string]bool{}
+		for _, permission := range covering {
+			for _, field := range required.HiddenFields {
+				if !fieldHidden(permission.FieldLevelSecurity, field) {
+					exposed[field] = true
+				}
+			}
+		}
+		if len(exposed) > 0 {
+			fields := make([]string, 0, len(exposed))
+			for field := range exposed {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			drift = append(drift, fmt.Sprintf("fields %s are not hidden for %s", strings.Join(fields, ", "), pattern))
+		}
+	}
+	return drift
+}
+
+// fieldHidden returns true if the field level security rules hide the field. Rules starting with ~ exclude fields,
+// otherwise only the listed fields are visible.
+func fieldHidden(fls []string, field string) bool {
+	if len(fls) == 0 {
+		return false
+	}
+	excluding := strings.HasPrefix(fls[0], "~")
+	for _, rule := range fls {
+		if excluding && rule == "~"+field {
+			return true
+		}
+		if !excluding && rule == field {
+			return false
+		}
+	}
+	return !excluding
+}
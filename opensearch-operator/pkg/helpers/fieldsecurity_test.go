@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("field security drift",
+	func(permissions []opsterv1.IndexPermissionSpec, expected []string) {
+		required := opsterv1.ComponentTemplateFieldSecurity{
+			IndexPatterns: []string{"customers-*"},
+			HiddenFields:  []string{"ssn", "iban"},
+		}
+		Expect(FieldSecurityDrift(required, opsterv1.OpensearchRoleSpec{IndexPermissions: permissions})).To(Equal(expected))
+	},
+	Entry("When the fields are excluded", []opsterv1.IndexPermissionSpec{
+		{IndexPatterns: []string{"customers-*"}, FieldLevelSecurity: []string{"~ssn", "~iban"}},
+	}, nil),
+	Entry("When only other fields are included", []opsterv1.IndexPermissionSpec{
+		{IndexPatterns: []string{"orders-*", "customers-*"}, FieldLevelSecurity: []string{"name", "email"}},
+	}, nil),
+	Entry("When no index permission covers the pattern", []opsterv1.IndexPermissionSpec{
+		{IndexPatterns: []string{"orders-*"}, FieldLevelSecurity: []string{"~ssn", "~iban"}},
+	}, []string{"no index permission for customers-*"}),
+	Entry("When a field is not excluded", []opsterv1.IndexPermissionSpec{
+		{IndexPatterns: []string{"customers-*"}, FieldLevelSecurity: []string{"~ssn"}},
+	}, []string{"fields iban are not hidden for customers-*"}),
+	Entry("When a field is included", []opsterv1.IndexPermissionSpec{
+		{IndexPatterns: []string{"customers-*"}, FieldLevelSecurity: []string{"name", "ssn"}},
+	}, []string{"fields ssn are not hidden for customers-*"}),
+	Entry("When another permission has no field level security", []opsterv1.IndexPermissionSpec{
+		{IndexPatterns: []string{"customers-*"}, FieldLevelSecurity: []string{"~ssn", "~iban"}},
+		{IndexPatterns: []string{"customers-*"}, AllowedActions: []string{"read"}},
+	}, []string{"fields iban, ssn are not hidden for customers-*"}),
+)
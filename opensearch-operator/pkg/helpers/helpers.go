@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"reflect"
@@ -114,6 +116,42 @@ func UsernameAndPassword(k8sClient k8s.K8sClient, cr *opsterv1.OpenSearchCluster
 	}
 }
 
+// OperatorClientCertificate returns the client certificate the operator authenticates to the cluster with, nil if the
+// cluster uses basic auth. The certificate and key must match and the certificate must be valid at the current time
+func OperatorClientCertificate(k8sClient k8s.K8sClient, cr *opsterv1.OpenSearchCluster) (*tls.Certificate, error) {
+	if cr.Spec.Security == nil || cr.Spec.Security.Config == nil || cr.Spec.Security.Config.OperatorAuthMode != opsterv1.OperatorAuthModeClientCertificate {
+		return nil, nil
+	}
+	secretName := cr.Spec.Security.Config.OperatorClientCertSecret.Name
+	if secretName == "" {
+		return nil, errors.New("operatorClientCertSecret must be set for the clientCertificate auth mode")
+	}
+	secret, err := k8sClient.GetSecret(secretName, cr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	certPEM, certExists := secret.Data[corev1.TLSCertKey]
+	keyPEM, keyExists := secret.Data[corev1.TLSPrivateKeyKey]
+	if !certExists || !keyExists {
+		return nil, fmt.Errorf("%s or %s field missing in secret %s", corev1.TLSCertKey, corev1.TLSPrivateKeyKey, secretName)
+	}
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate in secret %s: %w", secretName, err)
+	}
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate in secret %s: %w", secretName, err)
+	}
+	now := time.Now()
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return nil, fmt.Errorf("client certificate in secret %s is only valid from %s to %s", secretName,
+			leaf.NotBefore.UTC().Format(time.RFC3339), leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+	certificate.Leaf = leaf
+	return &certificate, nil
+}
+
 func GetByDescriptionAndGroup(left opsterv1.ComponentStatus, right opsterv1.ComponentStatus) (opsterv1.ComponentStatus, bool) {
 	if left.Description == right.Description && left.Component == right.Component {
 		return left, true
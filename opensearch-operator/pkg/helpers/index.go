@@ -0,0 +1,169 @@
+package helpers
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// staticIndexSettings are the index settings OpenSearch only accepts when an index is created or closed, settings of
+// the groups in staticIndexSettingGroups are not listed
+var staticIndexSettings = map[string]bool{
+	"index.number_of_shards":                  true,
+	"index.number_of_routing_shards":          true,
+	"index.routing_partition_size":            true,
+	"index.codec":                             true,
+	"index.codec.compression_level":           true,
+	"index.format":                            true,
+	"index.load_fixed_bitset_filters_eagerly": true,
+	"index.shard.check_on_startup":            true,
+	"index.soft_deletes.enabled":              true,
+	"index.append_only.enabled":               true,
+	"index.replication.type":                  true,
+	"index.knn":                               true,
+}
+
+// staticIndexSettingGroups are the prefixes of groups of static index settings
+var staticIndexSettingGroups = []string{
+	"index.sort.",
+	"index.analysis.",
+	"index.similarity.",
+	"index.store.",
+	"index.remote_store.",
+	"index.composite_index",
+}
+
+func staticIndexSetting(key string) bool {
+	if staticIndexSettings[key] {
+		return true
+	}
+	for _, group := range staticIndexSettingGroups {
+		if strings.HasPrefix(key, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslateOpensearchIndexToRequest returns the body creating the index of the spec. The aliases are named by their
+// keys, their index and alias fields are dropped
+func TranslateOpensearchIndexToRequest(spec v1.OpensearchIndexResourceSpec) requests.Index {
+	request := TranslateIndexToRequest(spec.OpensearchIndexSpec)
+	for name, alias := range request.Aliases {
+		alias.Index = ""
+		alias.Alias = ""
+		request.Aliases[name] = alias
+	}
+	return request
+}
+
+// IndexSettingChanges compares the settings of an index spec with the flat settings of the existing index. It returns
+// the dynamic settings that differ in flat notation with the index. prefix, and the sorted keys of the static settings
+// that differ, which OpenSearch does not accept for an open index
+func IndexSettingChanges(desired *apiextensionsv1.JSON, current map[string]interface{}) (map[string]interface{}, []string, error) {
+	dynamic := map[string]interface{}{}
+	var static []string
+	if desired.Size() == 0 {
+		return dynamic, nil, nil
+	}
+
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(desired.Raw, &parsed); err != nil {
+		return nil, nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+	for key, value := range flat {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		actual, ok := current[key]
+		if (ok && settingValuesEqual(value, actual)) || (!ok && value == nil) {
+			continue
+		}
+		if staticIndexSetting(key) {
+			static = append(static, key)
+			continue
+		}
+		dynamic[key] = value
+	}
+	sort.Strings(static)
+	return dynamic, static, nil
+}
+
+// IndexAliasChanges compares the aliases of an index spec with the aliases of the existing index as returned by the
+// _alias API. It returns the sorted names of the aliases to add, including aliases the index has with other settings,
+// and the sorted names of the aliases in managed the spec no longer has
+func IndexAliasChanges(desired map[string]requests.IndexAlias, existing map[string]*apiextensionsv1.JSON, managed []string) ([]string, []string, error) {
+	var add, remove []string
+
+	for name, alias := range desired {
+		current, ok := existing[name]
+		if !ok {
+			add = append(add, name)
+			continue
+		}
+		equal, err := indexAliasEqual(alias, current)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !equal {
+			add = append(add, name)
+		}
+	}
+
+	for _, name := range managed {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		if _, ok := existing[name]; ok {
+			remove = append(remove, name)
+		}
+	}
+
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove, nil
+}
+
+// indexAliasEqual returns true if the alias of the index has the filter, routing and write index flag of the desired
+// alias. OpenSearch returns the routing as index_routing and search_routing and may expand the queries of the filter
+func indexAliasEqual(desired requests.IndexAlias, existing *apiextensionsv1.JSON) (bool, error) {
+	expected := map[string]interface{}{}
+	if desired.Filter.Size() > 0 {
+		var filter interface{}
+		if err := json.Unmarshal(desired.Filter.Raw, &filter); err != nil {
+			return false, err
+		}
+		expected["filter"] = filter
+	}
+	for key, routing := range map[string]string{"index_routing": desired.IndexRouting, "search_routing": desired.SearchRouting} {
+		if routing == "" {
+			routing = desired.Routing
+		}
+		if routing != "" {
+			expected[key] = routing
+		}
+	}
+
+	actual := map[string]interface{}{}
+	if existing.Size() > 0 {
+		if err := json.Unmarshal(existing.Raw, &actual); err != nil {
+			return false, err
+		}
+	}
+
+	for _, key := range []string{"filter", "index_routing", "search_routing"} {
+		expectedValue, expectedOk := expected[key]
+		actualValue, actualOk := actual[key]
+		if expectedOk != actualOk || (expectedOk && !JSONSubset(expectedValue, actualValue)) {
+			return false, nil
+		}
+	}
+	isWriteIndex, _ := actual["is_write_index"].(bool)
+	return isWriteIndex == desired.IsWriteIndex, nil
+}
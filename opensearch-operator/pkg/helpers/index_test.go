@@ -0,0 +1,65 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("index setting changes",
+	func(desired string, expectedDynamic map[string]interface{}, expectedStatic []string) {
+		current := map[string]interface{}{
+			"index.number_of_shards":   "2",
+			"index.number_of_replicas": "1",
+			"index.refresh_interval":   "30000ms",
+			"index.sort.field":         []interface{}{"timestamp"},
+		}
+		dynamic, static, err := IndexSettingChanges(&apiextensionsv1.JSON{Raw: []byte(desired)}, current)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dynamic).To(Equal(expectedDynamic))
+		Expect(static).To(Equal(expectedStatic))
+	},
+	Entry("When the index has the settings", `{"index":{"number_of_shards":2,"refresh_interval":"30s","sort.field":["timestamp"]}}`,
+		map[string]interface{}{}, nil),
+	Entry("When a dynamic setting differs", `{"number_of_replicas":2,"index.max_result_window":50000}`,
+		map[string]interface{}{"index.number_of_replicas": json.Number("2"), "index.max_result_window": json.Number("50000")}, nil),
+	Entry("When static settings differ", `{"index":{"number_of_shards":3,"codec":"best_compression","number_of_replicas":1}}`,
+		map[string]interface{}{}, []string{"index.codec", "index.number_of_shards"}),
+	Entry("When an analyzer is added", `{"analysis":{"analyzer":{"folding":{"tokenizer":"standard"}}}}`,
+		map[string]interface{}{}, []string{"index.analysis.analyzer.folding.tokenizer"}),
+	Entry("When a setting the index does not have is reset", `{"index":{"max_result_window":null}}`,
+		map[string]interface{}{}, nil),
+)
+
+var _ = DescribeTable("index alias changes",
+	func(desired map[string]requests.IndexAlias, managed []string, expectedAdd []string, expectedRemove []string) {
+		existing := map[string]*apiextensionsv1.JSON{
+			"logs":     {Raw: []byte(`{"is_write_index":true}`)},
+			"errors":   {Raw: []byte(`{"filter":{"term":{"level":{"value":"error"}}},"index_routing":"1","search_routing":"1"}`)},
+			"external": {Raw: []byte(`{}`)},
+		}
+		add, remove, err := IndexAliasChanges(desired, existing, managed)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(add).To(Equal(expectedAdd))
+		Expect(remove).To(Equal(expectedRemove))
+	},
+	Entry("When the index has the aliases",
+		map[string]requests.IndexAlias{
+			"logs":   {IsWriteIndex: true},
+			"errors": {Filter: &apiextensionsv1.JSON{Raw: []byte(`{"term":{"level":"error"}}`)}, Routing: "1"},
+		},
+		[]string{"logs", "errors"}, nil, nil),
+	Entry("When aliases are missing or differ",
+		map[string]requests.IndexAlias{
+			"logs":    {},
+			"errors":  {Filter: &apiextensionsv1.JSON{Raw: []byte(`{"term":{"level":"error"}}`)}},
+			"metrics": {},
+		},
+		nil, []string{"errors", "logs", "metrics"}, nil),
+	Entry("When managed aliases are removed from the spec",
+		map[string]requests.IndexAlias{"logs": {IsWriteIndex: true}},
+		[]string{"logs", "errors", "deleted"}, nil, []string{"errors"}),
+)
@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// ValidateIndexAlias returns an error naming every problem of the alias OpenSearch would reject, or that would make
+// the indices of the alias ambiguous
+func ValidateIndexAlias(name string, spec v1.OpensearchIndexAliasResourceSpec) error {
+	var invalid []string
+
+	if strings.Contains(name, "*") {
+		invalid = append(invalid, fmt.Sprintf("alias name %q must not contain *", name))
+	} else if err := ValidateIndexPattern(name); err != nil {
+		invalid = append(invalid, err.Error())
+	}
+
+	if len(spec.Indices) == 0 {
+		invalid = append(invalid, "at least one index is required")
+	}
+	seen := map[string]bool{}
+	var writeIndices []string
+	for _, target := range spec.Indices {
+		switch {
+		case target.Index == "":
+			invalid = append(invalid, "index names must not be empty")
+		case strings.ContainsAny(target.Index, "*,"):
+			invalid = append(invalid, fmt.Sprintf("index %q must be a single concrete index", target.Index))
+		case seen[target.Index]:
+			invalid = append(invalid, fmt.Sprintf("index %s is listed more than once", target.Index))
+		}
+		seen[target.Index] = true
+		if target.IsWriteIndex {
+			writeIndices = append(writeIndices, target.Index)
+		}
+	}
+	if len(writeIndices) > 1 {
+		invalid = append(invalid, fmt.Sprintf("at most one write index is allowed, got %s", strings.Join(writeIndices, ", ")))
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid alias: %s", strings.Join(invalid, "; "))
+}
+
+// TranslateIndexAliasToRequest rewrites the indices of the alias to the gateway format, keyed by index name
+func TranslateIndexAliasToRequest(spec v1.OpensearchIndexAliasResourceSpec) map[string]requests.IndexAlias {
+	definitions := make(map[string]requests.IndexAlias, len(spec.Indices))
+	for _, target := range spec.Indices {
+		definitions[target.Index] = requests.IndexAlias{
+			Filter:        SortJSONKeys(target.Filter),
+			Routing:       target.Routing,
+			IndexRouting:  target.IndexRouting,
+			SearchRouting: target.SearchRouting,
+			IsWriteIndex:  target.IsWriteIndex,
+		}
+	}
+	return definitions
+}
+
+// AliasIndexChanges compares the desired definitions of an alias with its definitions in OpenSearch, both keyed by
+// index name. It returns the sorted indices to add the alias to, including indices whose definition differs, and the
+// sorted indices to remove it from
+func AliasIndexChanges(desired map[string]requests.IndexAlias, existing map[string]*apiextensionsv1.JSON) ([]string, []string, error) {
+	indices := make([]string, 0, len(existing))
+	for index := range existing {
+		indices = append(indices, index)
+	}
+	// every index the alias points to is managed, the comparison is the same as for the aliases of an index
+	return IndexAliasChanges(desired, existing, indices)
+}
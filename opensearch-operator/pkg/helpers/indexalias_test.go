@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("index alias validation",
+	func(name string, indices []v1.IndexAliasTarget, expected string) {
+		err := ValidateIndexAlias(name, v1.OpensearchIndexAliasResourceSpec{Indices: indices})
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When the alias is valid", "products",
+		[]v1.IndexAliasTarget{{Index: "products-v1"}, {Index: "products-v2", IsWriteIndex: true}}, ""),
+	Entry("When the name contains a wildcard", "products-*",
+		[]v1.IndexAliasTarget{{Index: "products-v1"}}, `alias name "products-*" must not contain *`),
+	Entry("When an index is a pattern", "products",
+		[]v1.IndexAliasTarget{{Index: "products-*"}}, `index "products-*" must be a single concrete index`),
+	Entry("When an index is listed twice", "products",
+		[]v1.IndexAliasTarget{{Index: "products-v1"}, {Index: "products-v1", IsWriteIndex: true}}, "index products-v1 is listed more than once"),
+	Entry("When there are two write indices", "products",
+		[]v1.IndexAliasTarget{{Index: "products-v1", IsWriteIndex: true}, {Index: "products-v2", IsWriteIndex: true}},
+		"at most one write index is allowed, got products-v1, products-v2"),
+	Entry("When no index is listed", "products", nil, "at least one index is required"),
+)
+
+var _ = DescribeTable("alias index changes",
+	func(desired map[string]requests.IndexAlias, expectedAdd []string, expectedRemove []string) {
+		existing := map[string]*apiextensionsv1.JSON{
+			"products-v1": {Raw: []byte(`{"is_write_index":true}`)},
+			"products-v2": {Raw: []byte(`{"index_routing":"1","search_routing":"1,2"}`)},
+		}
+		add, remove, err := AliasIndexChanges(desired, existing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(add).To(Equal(expectedAdd))
+		Expect(remove).To(Equal(expectedRemove))
+	},
+	Entry("When the alias points to the indices",
+		map[string]requests.IndexAlias{
+			"products-v1": {IsWriteIndex: true},
+			"products-v2": {Routing: "1", SearchRouting: "1,2"},
+		}, nil, nil),
+	Entry("When the alias is switched to another index",
+		map[string]requests.IndexAlias{"products-v3": {IsWriteIndex: true}},
+		[]string{"products-v3"}, []string{"products-v1", "products-v2"}),
+	Entry("When the write index is moved",
+		map[string]requests.IndexAlias{
+			"products-v1": {},
+			"products-v2": {IndexRouting: "1", SearchRouting: "1,2", IsWriteIndex: true},
+		}, []string{"products-v1", "products-v2"}, nil),
+)
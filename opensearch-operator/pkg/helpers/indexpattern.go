@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxIndexNameBytes is the maximum length of an index name in OpenSearch
+const maxIndexNameBytes = 255
+
+// indexPatternForbiddenChars are the characters OpenSearch does not allow in index names, * is allowed in patterns
+const indexPatternForbiddenChars = `\/?"<>| ,#:`
+
+// indexPatternData is passed to the index pattern template
+type indexPatternData struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// ParseIndexPatternTemplate parses a Go template generating index patterns. Referencing a missing label or
+// annotation fails the generation instead of producing an empty value.
+func ParseIndexPatternTemplate(text string) (*template.Template, error) {
+	return template.New("indexPatterns").Option("missingkey=error").Parse(text)
+}
+
+// GenerateIndexPatterns executes the index pattern template with the name, namespace, labels and annotations of
+// the object. The output is split at commas, every pattern is validated and duplicates are dropped.
+func GenerateIndexPatterns(tmpl *template.Template, object metav1.Object) ([]string, error) {
+	data := indexPatternData{
+		Name:        object.GetName(),
+		Namespace:   object.GetNamespace(),
+		Labels:      object.GetLabels(),
+		Annotations: object.GetAnnotations(),
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	seen := map[string]bool{}
+	for _, pattern := range strings.Split(out.String(), ",") {
+		pattern = strings.TrimSpace(pattern)
+		if err := ValidateIndexPattern(pattern); err != nil {
+			return nil, err
+		}
+		if !seen[pattern] {
+			seen[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns, nil
+}
+
+// ValidateIndexPattern returns an error if OpenSearch would reject the index pattern
+func ValidateIndexPattern(pattern string) error {
+	switch {
+	case pattern == "":
+		return fmt.Errorf("index pattern must not be empty")
+	case pattern == "." || pattern == "..":
+		return fmt.Errorf("index pattern %q is not allowed", pattern)
+	case strings.ToLower(pattern) != pattern:
+		return fmt.Errorf("index pattern %q must be lowercase", pattern)
+	case strings.ContainsAny(pattern[:1], "-_+"):
+		return fmt.Errorf("index pattern %q must not start with -, _ or +", pattern)
+	case strings.ContainsAny(pattern, indexPatternForbiddenChars):
+		return fmt.Errorf("index pattern %q must not contain any of %s", pattern, indexPatternForbiddenChars)
+	case len(pattern) > maxIndexNameBytes:
+		return fmt.Errorf("index pattern %q is longer than %d bytes", pattern, maxIndexNameBytes)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = DescribeTable("index pattern generation",
+	func(text string, labels map[string]string, expected []string, valid bool) {
+		tmpl, err := ParseIndexPatternTemplate(text)
+		Expect(err).ToNot(HaveOccurred())
+		patterns, err := GenerateIndexPatterns(tmpl, &metav1.ObjectMeta{
+			Name:        "my-template",
+			Namespace:   "logging",
+			Labels:      labels,
+			Annotations: map[string]string{"retention": "short"},
+		})
+		if !valid {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patterns).To(Equal(expected))
+	},
+	Entry("When generated from labels", "{{ .Labels.team }}-{{ .Labels.app }}-logs-*",
+		map[string]string{"team": "payments", "app": "checkout"}, []string{"payments-checkout-logs-*"}, true),
+	Entry("When generating several patterns", "{{ .Labels.team }}-logs-*, {{ .Namespace }}-{{ .Annotations.retention }}-*, {{ .Labels.team }}-logs-*",
+		map[string]string{"team": "payments"}, []string{"payments-logs-*", "logging-short-*"}, true),
+	Entry("When a label is missing", "{{ .Labels.team }}-{{ .Labels.app }}-logs-*",
+		map[string]string{"team": "payments"}, nil, false),
+	Entry("When the object has no labels", "{{ .Labels.team }}-logs-*", nil, nil, false),
+	Entry("When a label is empty", "{{ .Labels.team }}-logs-*", map[string]string{"team": ""}, nil, false),
+	Entry("When a label is not lowercase", "{{ .Labels.team }}-logs-*", map[string]string{"team": "Payments"}, nil, false),
+	Entry("When a label contains a forbidden character", "{{ .Labels.team }}-logs-*", map[string]string{"team": "a:b"}, nil, false),
+)
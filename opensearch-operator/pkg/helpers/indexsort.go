@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// indexSortMetadataFields are the metadata fields an index can be sorted by without mapping them
+var indexSortMetadataFields = map[string]bool{
+	"_id":           true,
+	"_index":        true,
+	"_routing":      true,
+	"_seq_no":       true,
+	"_primary_term": true,
+	"_version":      true,
+}
+
+// IndexSortFields returns the fields set in index.sort.field of the given index settings. The setting is either a
+// single field or a list of fields.
+func IndexSortFields(settings *apiextensionsv1.JSON) ([]string, error) {
+	if settings.Size() == 0 {
+		return nil, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+
+	for _, key := range []string{"index.sort.field", "sort.field"} {
+		value, ok := flat[key]
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			return []string{v}, nil
+		case []interface{}:
+			fields := make([]string, 0, len(v))
+			for _, field := range v {
+				name, ok := field.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid %s: %v", key, value)
+				}
+				fields = append(fields, name)
+			}
+			return fields, nil
+		default:
+			return nil, fmt.Errorf("invalid %s: %v", key, value)
+		}
+	}
+	return nil, nil
+}
+
+// UnmappedSortFields returns the fields of index.sort.field in the given settings that are neither declared in the
+// given mappings nor known metadata fields, sorted by name. Object fields cannot be sorted by and count as unmapped,
+// multi-fields are referenced by their full path, e.g. title.raw. If the mappings declare no properties the sort
+// fields are expected to be mapped by another template and nothing is returned.
+func UnmappedSortFields(settings *apiextensionsv1.JSON, mappings *apiextensionsv1.JSON) ([]string, error) {
+	fields, err := IndexSortFields(settings)
+	if err != nil || len(fields) == 0 || mappings.Size() == 0 {
+		return nil, err
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(mappings.Raw, &parsed); err != nil {
+		return nil, err
+	}
+	properties, ok := parsed["properties"]
+	if !ok {
+		return nil, nil
+	}
+	mapped := map[string]bool{}
+	collectFieldPaths("", properties, mapped)
+
+	var unmapped []string
+	for _, field := range fields {
+		if !mapped[field] && !indexSortMetadataFields[field] {
+			unmapped = append(unmapped, field)
+		}
+	}
+	sort.Strings(unmapped)
+	return unmapped, nil
+}
+
+// collectFieldPaths adds the full paths of the leaf fields and multi-fields declared in the given properties
+func collectFieldPaths(prefix string, properties interface{}, paths map[string]bool) {
+	fields, ok := properties.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for name, field := range fields {
+		path := prefix + name
+		definition, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if nested, ok := definition["properties"]; ok {
+			collectFieldPaths(path+".", nested, paths)
+			continue
+		}
+		paths[path] = true
+		collectFieldPaths(path+".", definition["fields"], paths)
+	}
+}
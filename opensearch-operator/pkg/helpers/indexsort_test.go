@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("unmapped index sort fields",
+	func(settings string, mappings string, expected []string) {
+		unmapped, err := UnmappedSortFields(
+			&apiextensionsv1.JSON{Raw: []byte(settings)},
+			&apiextensionsv1.JSON{Raw: []byte(mappings)},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		if expected == nil {
+			Expect(unmapped).To(BeEmpty())
+		} else {
+			Expect(unmapped).To(Equal(expected))
+		}
+	},
+	Entry("When a single mapped field is set",
+		`{"index":{"sort.field":"timestamp","sort.order":"desc"}}`,
+		`{"properties":{"timestamp":{"type":"date"}}}`,
+		nil),
+	Entry("When mapped fields are set in flat notation",
+		`{"index.sort.field":["timestamp","host.name"]}`,
+		`{"properties":{"timestamp":{"type":"date"},"host":{"properties":{"name":{"type":"keyword"}}}}}`,
+		nil),
+	Entry("When a multi-field is set",
+		`{"index":{"sort":{"field":["message.raw"]}}}`,
+		`{"properties":{"message":{"type":"text","fields":{"raw":{"type":"keyword"}}}}}`,
+		nil),
+	Entry("When a metadata field is set",
+		`{"index":{"sort":{"field":"_seq_no"}}}`,
+		`{"properties":{"timestamp":{"type":"date"}}}`,
+		nil),
+	Entry("When an unmapped field is set",
+		`{"index":{"sort":{"field":["timestamp","@timestamp","host.ip"]}}}`,
+		`{"properties":{"timestamp":{"type":"date"},"host":{"properties":{"name":{"type":"keyword"}}}}}`,
+		[]string{"@timestamp", "host.ip"}),
+	Entry("When an object field is set",
+		`{"index":{"sort":{"field":"host"}}}`,
+		`{"properties":{"host":{"properties":{"name":{"type":"keyword"}}}}}`,
+		[]string{"host"}),
+	Entry("When the template maps no properties",
+		`{"index":{"sort":{"field":"timestamp"}}}`,
+		`{"_source":{"enabled":true}}`,
+		nil),
+	Entry("When no sort is set",
+		`{"index":{"number_of_shards":1}}`,
+		`{"properties":{"timestamp":{"type":"date"}}}`,
+		nil),
+)
+
+var _ = Describe("IndexSortFields", func() {
+	It("should reject sort fields that are not strings", func() {
+		_, err := IndexSortFields(&apiextensionsv1.JSON{Raw: []byte(`{"index":{"sort":{"field":[1]}}}`)})
+		Expect(err).To(HaveOccurred())
+	})
+})
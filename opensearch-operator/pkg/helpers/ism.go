@@ -0,0 +1,69 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// ISMManagedSettings returns the sorted settings of the index settings, in flat notation with the index. prefix, that
+// the actions of the ISM policy change on the managed indices, e.g. index.number_of_replicas for a replica_count
+// action. They are expected to differ from the template once the policy ran.
+func ISMManagedSettings(policy requests.ISMPolicy, settings *apiextensionsv1.JSON) ([]string, error) {
+	if settings.Size() == 0 {
+		return nil, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+
+	var keys, prefixes []string
+	for _, state := range policy.States {
+		for _, action := range state.Actions {
+			if action.ReplicaCount != nil {
+				keys = append(keys, "index.number_of_replicas")
+			}
+			if action.ReadOnly != nil || action.ReadWrite != nil || action.ForceMerge != nil {
+				keys = append(keys, "index.blocks.write")
+			}
+			if action.IndexPriority != nil {
+				keys = append(keys, "index.priority")
+			}
+			if action.Allocation != nil {
+				if action.Allocation.Require != "" {
+					prefixes = append(prefixes, "index.routing.allocation.require.")
+				}
+				if action.Allocation.Include != "" {
+					prefixes = append(prefixes, "index.routing.allocation.include.")
+				}
+				if action.Allocation.Exclude != "" {
+					prefixes = append(prefixes, "index.routing.allocation.exclude.")
+				}
+			}
+		}
+	}
+
+	var managed []string
+	for key := range flat {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		if ContainsString(keys, key) {
+			managed = append(managed, key)
+			continue
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				managed = append(managed, key)
+				break
+			}
+		}
+	}
+	sort.Strings(managed)
+	return managed, nil
+}
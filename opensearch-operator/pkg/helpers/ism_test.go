@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("ISM managed settings",
+	func(actions string, settings string, expected []string) {
+		policy := requests.ISMPolicy{}
+		Expect(json.Unmarshal([]byte(`{"states":[{"name":"hot","actions":`+actions+`}]}`), &policy)).To(Succeed())
+		var input *apiextensionsv1.JSON
+		if settings != "" {
+			input = &apiextensionsv1.JSON{Raw: []byte(settings)}
+		}
+		managed, err := ISMManagedSettings(policy, input)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(managed).To(Equal(expected))
+	},
+	Entry("When the template has no settings", `[{"replica_count":{"number_of_replicas":0}}]`, "", nil),
+	Entry("When the policy has no actions", `[]`, `{"index":{"number_of_replicas":1}}`, nil),
+	Entry("When the policy changes the replicas", `[{"replica_count":{"number_of_replicas":0}}]`,
+		`{"index":{"number_of_replicas":1,"number_of_shards":1}}`, []string{"index.number_of_replicas"}),
+	Entry("When the replicas have no index prefix", `[{"replica_count":{"number_of_replicas":0}}]`,
+		`{"number_of_replicas":1}`, []string{"index.number_of_replicas"}),
+	Entry("When the policy sets the index read only", `[{"read_only":""}]`,
+		`{"index":{"blocks":{"write":false}}}`, []string{"index.blocks.write"}),
+	Entry("When the policy sets the priority", `[{"index_priority":{"priority":10}}]`,
+		`{"index.priority":50}`, []string{"index.priority"}),
+	Entry("When the policy allocates the index", `[{"allocation":{"require":"warm"}}]`,
+		`{"index":{"routing":{"allocation":{"require":{"temp":"hot"},"exclude":{"zone":"a"}}}}}`,
+		[]string{"index.routing.allocation.require.temp"}),
+	Entry("When the policy does not change the settings", `[{"rollover":{"min_doc_count":100}}]`,
+		`{"index":{"number_of_replicas":1}}`, nil),
+)
@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// DefaultRequeueJitter spreads requeues by ±20% of their interval
+const DefaultRequeueJitter = 0.2
+
+// ValidateJitter checks that a jitter factor keeps jittered intervals positive
+func ValidateJitter(factor float64) error {
+	if factor < 0 || factor >= 1 {
+		return fmt.Errorf("jitter %v must be at least 0 and less than 1", factor)
+	}
+	return nil
+}
+
+// Jitter returns the interval moved randomly by up to ±factor of it, so objects requeued with the same interval
+// spread out over time instead of being reconciled in sync. Intervals that are not positive and a factor of 0 are
+// returned as is.
+func Jitter(interval time.Duration, factor float64) time.Duration {
+	if interval <= 0 || factor <= 0 {
+		return interval
+	}
+	return interval + time.Duration(float64(interval)*factor*(2*rand.Float64()-1))
+}
@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Jitter", func() {
+	It("should keep jittered intervals within the bounds", func() {
+		seen := map[time.Duration]bool{}
+		for i := 0; i < 1000; i++ {
+			jittered := Jitter(30*time.Second, 0.2)
+			Expect(jittered).To(BeNumerically(">=", 24*time.Second))
+			Expect(jittered).To(BeNumerically("<=", 36*time.Second))
+			seen[jittered] = true
+		}
+		Expect(len(seen)).To(BeNumerically(">", 1))
+	})
+
+	DescribeTable("returns the interval as is",
+		func(interval time.Duration, factor float64) {
+			Expect(Jitter(interval, factor)).To(Equal(interval))
+		},
+		Entry("When the factor is 0", 30*time.Second, 0.0),
+		Entry("When the interval is 0", time.Duration(0), 0.2),
+	)
+
+	DescribeTable("validates the factor",
+		func(factor float64, valid bool) {
+			err := ValidateJitter(factor)
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("When the factor is 0", 0.0, true),
+		Entry("When the factor is the default", DefaultRequeueJitter, true),
+		Entry("When the factor is negative", -0.1, false),
+		Entry("When the factor is 1", 1.0, false),
+	)
+})
@@ -0,0 +1,23 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// UnmarshalPreservingNumbers parses JSON like json.Unmarshal, but decodes numbers into interface values as
+// json.Number instead of float64. Settings and mappings that are parsed and marshaled again then keep their numbers
+// exactly as written, e.g. 9007199254740993 is not rounded to 9007199254740992 and 1000000 is not turned into 1e+06.
+func UnmarshalPreservingNumbers(raw []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("invalid JSON: unexpected data after the top-level value")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("numeric settings round-trip",
+	func(number string) {
+		settings := &apiextensionsv1.JSON{Raw: []byte(`{"index":{"refresh_interval":"30000ms","mapping":{"total_fields":{"limit":` + number + `}}}}`)}
+		request := TranslateComponentTemplateToRequest(v1.OpensearchComponentTemplateSpec{
+			Template: v1.OpensearchIndexSpec{Settings: settings},
+		})
+
+		normalized, err := NormalizeTimeSettings(request.Template.Settings)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(normalized.Raw)).To(ContainSubstring(`"limit":` + number + `}`))
+
+		tiered, err := SetAllocationTier(request.Template.Settings, DefaultTierAttribute, "hot")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(tiered.Raw)).To(ContainSubstring(`"limit":` + number + `}`))
+
+		live := TranslateComponentTemplateToRequest(v1.OpensearchComponentTemplateSpec{
+			Template: v1.OpensearchIndexSpec{Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"mapping":{"total_fields":{"limit":` + number + `}},"refresh_interval":"30s"}}`)}},
+		})
+		Expect(ComponentTemplatesEqual(request, live)).To(BeTrue())
+		changes, err := JSONDiff(live, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Path).To(Equal("template.settings.index.refresh_interval"))
+	},
+	Entry("When given a large integer", "1000000"),
+	Entry("When given an integer above the float64 precision", "9007199254740993"),
+	Entry("When given an integer above the int64 range", "123456789012345678901234567890"),
+	Entry("When given a float", "0.30000000000000004"),
+	Entry("When given a float with a trailing zero", "1.50"),
+	Entry("When given an exponent", "1e+06"),
+)
+
+var _ = Describe("numeric settings drift", func() {
+	It("should detect changes beyond the float64 precision", func() {
+		spec := TranslateComponentTemplateToRequest(v1.OpensearchComponentTemplateSpec{
+			Template: v1.OpensearchIndexSpec{Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"max_result_window":9007199254740993}}`)}},
+		})
+		live := TranslateComponentTemplateToRequest(v1.OpensearchComponentTemplateSpec{
+			Template: v1.OpensearchIndexSpec{Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"max_result_window":9007199254740992}}`)}},
+		})
+		changes, err := JSONDiff(live, spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(string(changes[0].From)).To(Equal("9007199254740992"))
+		Expect(string(changes[0].To)).To(Equal("9007199254740993"))
+	})
+
+	It("should reject trailing data", func() {
+		var parsed interface{}
+		Expect(UnmarshalPreservingNumbers([]byte(`{"a":1} {"b":2}`), &parsed)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,250 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const (
+	// DefaultTotalFieldsLimit is the default of index.mapping.total_fields.limit in OpenSearch
+	DefaultTotalFieldsLimit = 1000
+	// FieldCountWarningRatio is the share of the total fields limit above which a mapping is considered close to the limit
+	FieldCountWarningRatio = 0.9
+	// DefaultDepthLimit is the default of index.mapping.depth.limit in OpenSearch
+	DefaultDepthLimit = 20
+	// DepthWarningRatio is the share of the depth limit above which a mapping is considered close to the limit
+	DepthWarningRatio = 0.9
+)
+
+// MappingFieldCount estimates the number of fields the given mappings declare explicitly, counting object fields
+// and multi-fields the same way index.mapping.total_fields.limit does. Dynamically mapped fields are not included.
+func MappingFieldCount(mappings *apiextensionsv1.JSON) (int, error) {
+	if mappings.Size() == 0 {
+		return 0, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(mappings.Raw, &parsed); err != nil {
+		return 0, err
+	}
+	return countProperties(parsed["properties"]), nil
+}
+
+func countProperties(properties interface{}) int {
+	fields, ok := properties.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	count := 0
+	for _, field := range fields {
+		count++
+		definition, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		count += countProperties(definition["properties"])
+		count += countProperties(definition["fields"])
+	}
+	return count
+}
+
+// MappingTypeChange is a field declared in two versions of mappings with different types
+type MappingTypeChange struct {
+	// Field is the path of the field, with multi-fields below their parent field (e.g. title.raw)
+	Field    string
+	Previous string
+	Proposed string
+}
+
+func (c MappingTypeChange) String() string {
+	return fmt.Sprintf("%s changes from %s to %s", c.Field, c.Previous, c.Proposed)
+}
+
+// numericWidths orders the numeric types that can be widened without changing how values are queried
+var numericWidths = map[string]struct {
+	family string
+	width  int
+}{
+	"byte":       {family: "integer", width: 1},
+	"short":      {family: "integer", width: 2},
+	"integer":    {family: "integer", width: 3},
+	"long":       {family: "integer", width: 4},
+	"half_float": {family: "float", width: 1},
+	"float":      {family: "float", width: 2},
+	"double":     {family: "float", width: 3},
+}
+
+// IncompatibleMappingTypeChanges returns the fields declared in both mappings whose type changes, sorted by field.
+// Widening a numeric type within integers or within floating point types is compatible. Fields added or removed
+// and fields without an explicit type, other than object fields, are not compared.
+func IncompatibleMappingTypeChanges(previous *apiextensionsv1.JSON, proposed *apiextensionsv1.JSON) ([]MappingTypeChange, error) {
+	if previous.Size() == 0 || proposed.Size() == 0 {
+		return nil, nil
+	}
+	parsedPrevious := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(previous.Raw, &parsedPrevious); err != nil {
+		return nil, err
+	}
+	parsedProposed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(proposed.Raw, &parsedProposed); err != nil {
+		return nil, err
+	}
+	changes := mappingTypeChanges("", parsedPrevious["properties"], parsedProposed["properties"])
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
+
+func mappingTypeChanges(prefix string, previous interface{}, proposed interface{}) []MappingTypeChange {
+	previousFields, ok := previous.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	proposedFields, ok := proposed.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var changes []MappingTypeChange
+	for name, field := range previousFields {
+		previousDefinition, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		proposedDefinition, ok := proposedFields[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := prefix + name
+		previousType, proposedType := fieldType(previousDefinition), fieldType(proposedDefinition)
+		if previousType != "" && proposedType != "" && !compatibleFieldTypes(previousType, proposedType) {
+			changes = append(changes, MappingTypeChange{Field: path, Previous: previousType, Proposed: proposedType})
+			continue
+		}
+		changes = append(changes, mappingTypeChanges(path+".", previousDefinition["properties"], proposedDefinition["properties"])...)
+		changes = append(changes, mappingTypeChanges(path+".", previousDefinition["fields"], proposedDefinition["fields"])...)
+	}
+	return changes
+}
+
+// fieldType returns the declared type of a field, object for fields only declaring properties
+func fieldType(definition map[string]interface{}) string {
+	if fieldType, ok := definition["type"].(string); ok {
+		return fieldType
+	}
+	if _, ok := definition["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func compatibleFieldTypes(previous string, proposed string) bool {
+	if previous == proposed {
+		return true
+	}
+	previousWidth, ok := numericWidths[previous]
+	if !ok {
+		return false
+	}
+	proposedWidth, ok := numericWidths[proposed]
+	return ok && previousWidth.family == proposedWidth.family && proposedWidth.width >= previousWidth.width
+}
+
+// TotalFieldsLimit returns the index.mapping.total_fields.limit set in the given index settings or the OpenSearch default
+func TotalFieldsLimit(settings *apiextensionsv1.JSON) (int, error) {
+	return mappingLimit(settings, "mapping.total_fields.limit", DefaultTotalFieldsLimit)
+}
+
+// DepthLimit returns the index.mapping.depth.limit set in the given index settings or the OpenSearch default
+func DepthLimit(settings *apiextensionsv1.JSON) (int, error) {
+	return mappingLimit(settings, "mapping.depth.limit", DefaultDepthLimit)
+}
+
+// MappingDepth returns the depth of the given mappings as index.mapping.depth.limit counts it: the root object
+// counts as one and every object field nested in it adds a level. Leaf fields and multi-fields add no level.
+func MappingDepth(mappings *apiextensionsv1.JSON) (int, error) {
+	if mappings.Size() == 0 {
+		return 0, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(mappings.Raw, &parsed); err != nil {
+		return 0, err
+	}
+	return 1 + objectDepth(parsed["properties"]), nil
+}
+
+func objectDepth(properties interface{}) int {
+	fields, ok := properties.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	depth := 0
+	for _, field := range fields {
+		definition, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		nested, ok := definition["properties"]
+		if !ok {
+			continue
+		}
+		if d := 1 + objectDepth(nested); d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// mappingLimit returns the limit set for the index setting (given without the index. prefix) or the default
+func mappingLimit(settings *apiextensionsv1.JSON, setting string, defaultLimit int) (int, error) {
+	if settings.Size() == 0 {
+		return defaultLimit, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return 0, err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+
+	for _, key := range []string{"index." + setting, setting} {
+		value, ok := flat[key]
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case json.Number:
+			limit, err := strconv.Atoi(v.String())
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			return limit, nil
+		case string:
+			limit, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			return limit, nil
+		default:
+			return 0, fmt.Errorf("invalid %s: %v", key, value)
+		}
+	}
+	return defaultLimit, nil
+}
+
+// flattenSettings converts nested settings into their flat notation (e.g. {"index": {"codec": ...}} to "index.codec")
+func flattenSettings(prefix string, settings map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenSettings(key, nested, flat)
+			continue
+		}
+		flat[key] = value
+	}
+}
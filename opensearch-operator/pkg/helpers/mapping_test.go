@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("mapping field count",
+	func(mappings string, expectedCount int) {
+		count, err := MappingFieldCount(&apiextensionsv1.JSON{Raw: []byte(mappings)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(count).To(Equal(expectedCount))
+	},
+	Entry("When no fields are declared", `{}`, 0),
+	Entry("When flat fields are declared", `{"properties":{"a":{"type":"keyword"},"b":{"type":"long"}}}`, 2),
+	Entry("When object fields are declared",
+		`{"properties":{"user":{"properties":{"name":{"type":"text"},"address":{"properties":{"city":{"type":"keyword"}}}}}}}`, 4),
+	Entry("When multi-fields are declared",
+		`{"properties":{"title":{"type":"text","fields":{"raw":{"type":"keyword"},"english":{"type":"text"}}}}}`, 3),
+)
+
+var _ = DescribeTable("total fields limit lookup",
+	func(settings string, expectedLimit int) {
+		limit, err := TotalFieldsLimit(&apiextensionsv1.JSON{Raw: []byte(settings)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(limit).To(Equal(expectedLimit))
+	},
+	Entry("When no limit is set", `{"index":{"number_of_shards":"1"}}`, DefaultTotalFieldsLimit),
+	Entry("When the limit is set in nested notation", `{"index":{"mapping":{"total_fields":{"limit":2000}}}}`, 2000),
+	Entry("When the limit is set in flat notation", `{"index.mapping.total_fields.limit":"1500"}`, 1500),
+	Entry("When the limit is set without the index prefix", `{"mapping":{"total_fields.limit":"500"}}`, 500),
+)
+
+var _ = DescribeTable("mapping depth",
+	func(mappings string, expectedDepth int) {
+		depth, err := MappingDepth(&apiextensionsv1.JSON{Raw: []byte(mappings)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(depth).To(Equal(expectedDepth))
+	},
+	Entry("When no fields are declared", `{}`, 1),
+	Entry("When flat fields are declared", `{"properties":{"a":{"type":"keyword"},"b":{"type":"long"}}}`, 1),
+	Entry("When multi-fields are declared",
+		`{"properties":{"title":{"type":"text","fields":{"raw":{"type":"keyword"}}}}}`, 1),
+	Entry("When object fields are nested",
+		`{"properties":{"user":{"properties":{"name":{"type":"text"},"address":{"properties":{"city":{"type":"keyword"}}}}},"tag":{"type":"keyword"}}}`, 3),
+	Entry("When nested fields are declared",
+		`{"properties":{"comments":{"type":"nested","properties":{"author":{"properties":{"name":{"type":"text"}}}}}}}`, 3),
+)
+
+var _ = DescribeTable("depth limit lookup",
+	func(settings string, expectedLimit int) {
+		limit, err := DepthLimit(&apiextensionsv1.JSON{Raw: []byte(settings)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(limit).To(Equal(expectedLimit))
+	},
+	Entry("When no limit is set", `{"index":{"number_of_shards":"1"}}`, DefaultDepthLimit),
+	Entry("When the limit is set in nested notation", `{"index":{"mapping":{"depth":{"limit":5}}}}`, 5),
+	Entry("When the limit is set in flat notation", `{"index.mapping.depth.limit":"10"}`, 10),
+)
+
+var _ = DescribeTable("incompatible mapping type changes",
+	func(previous string, proposed string, expectedChanges []string) {
+		changes, err := IncompatibleMappingTypeChanges(&apiextensionsv1.JSON{Raw: []byte(previous)}, &apiextensionsv1.JSON{Raw: []byte(proposed)})
+		Expect(err).ToNot(HaveOccurred())
+		var described []string
+		for _, change := range changes {
+			described = append(described, change.String())
+		}
+		Expect(described).To(Equal(expectedChanges))
+	},
+	Entry("When the types are unchanged",
+		`{"properties":{"a":{"type":"keyword"},"b":{"type":"long"}}}`,
+		`{"properties":{"a":{"type":"keyword","ignore_above":256},"b":{"type":"long"}}}`, nil),
+	Entry("When fields are added or removed",
+		`{"properties":{"a":{"type":"keyword"}}}`,
+		`{"properties":{"b":{"type":"long"}}}`, nil),
+	Entry("When a numeric type is widened",
+		`{"properties":{"count":{"type":"integer"},"ratio":{"type":"float"}}}`,
+		`{"properties":{"count":{"type":"long"},"ratio":{"type":"double"}}}`, nil),
+	Entry("When a numeric type is narrowed",
+		`{"properties":{"count":{"type":"long"}}}`,
+		`{"properties":{"count":{"type":"integer"}}}`, []string{"count changes from long to integer"}),
+	Entry("When a type changes between families",
+		`{"properties":{"id":{"type":"long"},"ratio":{"type":"float"}}}`,
+		`{"properties":{"id":{"type":"keyword"},"ratio":{"type":"long"}}}`,
+		[]string{"id changes from long to keyword", "ratio changes from float to long"}),
+	Entry("When a nested field changes",
+		`{"properties":{"user":{"properties":{"name":{"type":"text"},"id":{"type":"long"}}}}}`,
+		`{"properties":{"user":{"properties":{"name":{"type":"text"},"id":{"type":"keyword"}}}}}`,
+		[]string{"user.id changes from long to keyword"}),
+	Entry("When an object field becomes a leaf field",
+		`{"properties":{"user":{"properties":{"name":{"type":"text"}}}}}`,
+		`{"properties":{"user":{"type":"keyword"}}}`, []string{"user changes from object to keyword"}),
+	Entry("When a multi-field changes",
+		`{"properties":{"title":{"type":"text","fields":{"raw":{"type":"keyword"}}}}}`,
+		`{"properties":{"title":{"type":"text","fields":{"raw":{"type":"wildcard"}}}}}`,
+		[]string{"title.raw changes from keyword to wildcard"}),
+)
@@ -0,0 +1,143 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+// monitorTriggerKeys are the keys the triggers of each monitor type are wrapped in
+var monitorTriggerKeys = map[v1.MonitorType]string{
+	v1.QueryLevelMonitor:  "query_level_trigger",
+	v1.BucketLevelMonitor: "bucket_level_trigger",
+	v1.DocLevelMonitor:    "document_level_trigger",
+}
+
+// TranslateMonitorToRequest rewrites the CRD format to the gateway format, wrapping the triggers in the key of the
+// monitor type
+func TranslateMonitorToRequest(spec v1.OpensearchAlertingMonitorSpec, name string) (requests.Monitor, error) {
+	monitorType := spec.MonitorType
+	if monitorType == "" {
+		monitorType = v1.QueryLevelMonitor
+	}
+	triggerKey, ok := monitorTriggerKeys[monitorType]
+	if !ok {
+		return requests.Monitor{}, fmt.Errorf("unknown monitor type %s", monitorType)
+	}
+
+	request := requests.Monitor{
+		Type:        "monitor",
+		MonitorType: string(monitorType),
+		Name:        name,
+		Enabled:     pointer.BoolDeref(spec.Enabled, true),
+		Inputs:      spec.Inputs,
+		Triggers:    []apiextensionsv1.JSON{},
+	}
+
+	switch {
+	case spec.Schedule.Period != nil && spec.Schedule.Cron == nil:
+		unit := spec.Schedule.Period.Unit
+		if unit == "" {
+			unit = "MINUTES"
+		}
+		request.Schedule.Period = &requests.MonitorPeriod{Interval: spec.Schedule.Period.Interval, Unit: unit}
+	case spec.Schedule.Cron != nil && spec.Schedule.Period == nil:
+		cron := translateCronSchedule(*spec.Schedule.Cron).Cron
+		request.Schedule.Cron = &cron
+	default:
+		return requests.Monitor{}, fmt.Errorf("exactly one of period and cron has to be set in the schedule")
+	}
+
+	for _, trigger := range spec.Triggers {
+		severity := trigger.Severity
+		if severity == "" {
+			severity = "1"
+		}
+		actions := trigger.Actions
+		if actions == nil {
+			actions = []apiextensionsv1.JSON{}
+		}
+		raw, err := json.Marshal(map[string]requests.MonitorTrigger{triggerKey: {
+			Name:      trigger.Name,
+			Severity:  severity,
+			Condition: trigger.Condition,
+			Actions:   actions,
+		}})
+		if err != nil {
+			return requests.Monitor{}, fmt.Errorf("invalid trigger %s: %w", trigger.Name, err)
+		}
+		request.Triggers = append(request.Triggers, apiextensionsv1.JSON{Raw: raw})
+	}
+	return request, nil
+}
+
+// MonitorsEqual returns true if the monitor in OpenSearch holds everything of the desired monitor. OpenSearch adds
+// fields to monitors, such as the ids of the triggers and defaults of the queries, which are ignored
+func MonitorsEqual(desired requests.Monitor, existing *apiextensionsv1.JSON) (bool, error) {
+	return isJSONSubset(desired, existing)
+}
+
+// isJSONSubset marshals desired and checks that it is a JSONSubset of existing
+func isJSONSubset(desired interface{}, existing *apiextensionsv1.JSON) (bool, error) {
+	raw, err := json.Marshal(desired)
+	if err != nil {
+		return false, err
+	}
+	var desiredValue interface{}
+	if err := json.Unmarshal(raw, &desiredValue); err != nil {
+		return false, err
+	}
+	var existingValue interface{}
+	if existing.Size() > 0 {
+		if err := json.Unmarshal(existing.Raw, &existingValue); err != nil {
+			return false, err
+		}
+	}
+	return JSONSubset(desiredValue, existingValue), nil
+}
+
+// JSONSubset returns true if every value of desired is in actual. Objects may have additional keys in actual, lists
+// have to have the same length. A scalar also matches an object holding it as its query or value, the form
+// OpenSearch expands the short form of queries like {"term":{"field":"value"}} to
+func JSONSubset(desired interface{}, actual interface{}) bool {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		actualMap, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range desiredValue {
+			actualValue, ok := actualMap[key]
+			if !ok || !JSONSubset(value, actualValue) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actualList, ok := actual.([]interface{})
+		if !ok || len(actualList) != len(desiredValue) {
+			return false
+		}
+		for i := range desiredValue {
+			if !JSONSubset(desiredValue[i], actualList[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if actualMap, ok := actual.(map[string]interface{}); ok {
+			for _, key := range []string{"query", "value"} {
+				if expanded, ok := actualMap[key]; ok && reflect.DeepEqual(desired, expanded) {
+					return true
+				}
+			}
+			return false
+		}
+		return reflect.DeepEqual(desired, actual)
+	}
+}
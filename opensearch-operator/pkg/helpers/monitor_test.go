@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("JSON subsets",
+	func(desired string, actual string, expected bool) {
+		var desiredValue, actualValue interface{}
+		Expect(json.Unmarshal([]byte(desired), &desiredValue)).To(Succeed())
+		Expect(json.Unmarshal([]byte(actual), &actualValue)).To(Succeed())
+		Expect(JSONSubset(desiredValue, actualValue)).To(Equal(expected))
+	},
+	Entry("When the values are the same", `{"a":1,"b":["x"]}`, `{"b":["x"],"a":1}`, true),
+	Entry("When the actual value has additional keys", `{"name":"errors"}`, `{"name":"errors","id":"abc"}`, true),
+	Entry("When a number is returned as a float", `{"size":0}`, `{"size":0.0}`, true),
+	Entry("When a value differs", `{"name":"errors"}`, `{"name":"warnings"}`, false),
+	Entry("When a key is missing", `{"name":"errors","severity":"1"}`, `{"name":"errors"}`, false),
+	Entry("When a list has another length", `{"actions":[]}`, `{"actions":[{"name":"notify"}]}`, false),
+	Entry("When a query is expanded",
+		`{"term":{"level":"error"}}`, `{"term":{"level":{"value":"error","boost":1.0}}}`, true),
+	Entry("When a match query is expanded",
+		`{"match":{"message":"timeout"}}`, `{"match":{"message":{"query":"timeout","operator":"OR"}}}`, true),
+	Entry("When an expanded query differs",
+		`{"term":{"level":"error"}}`, `{"term":{"level":{"value":"warn","boost":1.0}}}`, false),
+)
+
+var _ = Describe("monitor request bodies", func() {
+	spec := func() v1.OpensearchAlertingMonitorSpec {
+		return v1.OpensearchAlertingMonitorSpec{
+			MonitorType: v1.BucketLevelMonitor,
+			Schedule:    v1.MonitorSchedule{Period: &v1.MonitorPeriod{Interval: 5}},
+			Inputs:      []apiextensionsv1.JSON{{Raw: []byte(`{"search":{"indices":["logs-*"],"query":{"size":0}}}`)}},
+			Triggers: []v1.MonitorTrigger{{
+				Name:      "errors",
+				Condition: apiextensionsv1.JSON{Raw: []byte(`{"buckets_path":{"count":"_count"},"script":{"source":"params.count > 10"}}`)},
+			}},
+		}
+	}
+
+	It("should wrap the triggers in the key of the monitor type", func() {
+		request, err := TranslateMonitorToRequest(spec(), "log-errors")
+		Expect(err).ToNot(HaveOccurred())
+		raw, err := json.Marshal(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raw).To(MatchJSON(`{
+			"type": "monitor",
+			"monitor_type": "bucket_level_monitor",
+			"name": "log-errors",
+			"enabled": true,
+			"schedule": {"period": {"interval": 5, "unit": "MINUTES"}},
+			"inputs": [{"search": {"indices": ["logs-*"], "query": {"size": 0}}}],
+			"triggers": [{"bucket_level_trigger": {
+				"name": "errors",
+				"severity": "1",
+				"condition": {"buckets_path": {"count": "_count"}, "script": {"source": "params.count > 10"}},
+				"actions": []
+			}}]
+		}`))
+	})
+
+	It("should require exactly one schedule", func() {
+		invalid := spec()
+		invalid.Schedule.Cron = &v1.CronSchedule{Expression: "0 * * * *"}
+		_, err := TranslateMonitorToRequest(invalid, "log-errors")
+		Expect(err).To(MatchError("exactly one of period and cron has to be set in the schedule"))
+	})
+
+	It("should ignore the fields OpenSearch adds when comparing", func() {
+		request, err := TranslateMonitorToRequest(spec(), "log-errors")
+		Expect(err).ToNot(HaveOccurred())
+		equal, err := MonitorsEqual(request, &apiextensionsv1.JSON{Raw: []byte(`{
+			"type": "monitor",
+			"schema_version": 8,
+			"monitor_type": "bucket_level_monitor",
+			"name": "log-errors",
+			"enabled": true,
+			"enabled_time": 1700000000000,
+			"last_update_time": 1700000000000,
+			"schedule": {"period": {"interval": 5, "unit": "MINUTES"}},
+			"inputs": [{"search": {"indices": ["logs-*"], "query": {"size": 0}}}],
+			"triggers": [{"bucket_level_trigger": {
+				"id": "abc",
+				"name": "errors",
+				"severity": "1",
+				"condition": {"buckets_path": {"count": "_count"}, "parent_bucket_path": "composite_agg", "script": {"source": "params.count > 10", "lang": "painless"}},
+				"actions": []
+			}}]
+		}`)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(equal).To(BeTrue())
+	})
+})
@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+// SecretValueFunc returns the value of a key of a secret
+type SecretValueFunc func(selector corev1.SecretKeySelector) (string, error)
+
+// TranslateNotificationChannelToRequest rewrites the CRD format to the gateway format, reading the credentials of the
+// channel with secretValue
+func TranslateNotificationChannelToRequest(
+	spec v1.OpensearchNotificationChannelSpec,
+	name string,
+	secretValue SecretValueFunc,
+) (requests.NotificationChannelConfig, error) {
+	types := 0
+	for _, set := range []bool{spec.Slack != nil, spec.Webhook != nil, spec.SNS != nil, spec.Email != nil} {
+		if set {
+			types++
+		}
+	}
+	if types != 1 {
+		return requests.NotificationChannelConfig{}, fmt.Errorf("exactly one of slack, webhook, sns and email has to be set, got %d", types)
+	}
+
+	config := requests.NotificationChannelConfig{
+		Name:        name,
+		Description: spec.Description,
+		IsEnabled:   pointer.BoolDeref(spec.Enabled, true),
+	}
+
+	switch {
+	case spec.Slack != nil:
+		url, err := secretValue(spec.Slack.URLFrom)
+		if err != nil {
+			return requests.NotificationChannelConfig{}, err
+		}
+		config.ConfigType = "slack"
+		config.Slack = &requests.NotificationSlack{URL: url}
+	case spec.Webhook != nil:
+		url := spec.Webhook.URL
+		if (url == "") == (spec.Webhook.URLFrom == nil) {
+			return requests.NotificationChannelConfig{}, fmt.Errorf("exactly one of url and urlFrom has to be set for the webhook")
+		}
+		if spec.Webhook.URLFrom != nil {
+			var err error
+			if url, err = secretValue(*spec.Webhook.URLFrom); err != nil {
+				return requests.NotificationChannelConfig{}, err
+			}
+		}
+		method := spec.Webhook.Method
+		if method == "" {
+			method = "POST"
+		}
+		headers := map[string]string{}
+		for header, value := range spec.Webhook.Headers {
+			headers[header] = value
+		}
+		for _, header := range sortedSelectorKeys(spec.Webhook.SecretHeaders) {
+			if _, ok := headers[header]; ok {
+				return requests.NotificationChannelConfig{}, fmt.Errorf("header %s is set in headers and secretHeaders", header)
+			}
+			value, err := secretValue(spec.Webhook.SecretHeaders[header])
+			if err != nil {
+				return requests.NotificationChannelConfig{}, err
+			}
+			headers[header] = value
+		}
+		if len(headers) == 0 {
+			headers = nil
+		}
+		config.ConfigType = "webhook"
+		config.Webhook = &requests.NotificationWebhook{URL: url, Method: method, HeaderParams: headers}
+	case spec.SNS != nil:
+		config.ConfigType = "sns"
+		config.SNS = &requests.NotificationSNS{TopicARN: spec.SNS.TopicARN, RoleARN: spec.SNS.RoleARN}
+	case spec.Email != nil:
+		recipients := []requests.NotificationEmailRecipient{}
+		for _, recipient := range spec.Email.Recipients {
+			recipients = append(recipients, requests.NotificationEmailRecipient{Recipient: recipient})
+		}
+		groups := spec.Email.EmailGroupIDs
+		if groups == nil {
+			groups = []string{}
+		}
+		if len(recipients) == 0 && len(groups) == 0 {
+			return requests.NotificationChannelConfig{}, fmt.Errorf("the email channel needs at least one recipient or email group")
+		}
+		config.ConfigType = "email"
+		config.Email = &requests.NotificationEmail{
+			EmailAccountID:   spec.Email.EmailAccountID,
+			RecipientList:    recipients,
+			EmailGroupIDList: groups,
+		}
+	}
+	return config, nil
+}
+
+// NotificationChannelsEqual returns true if the channel config in OpenSearch holds everything of the desired config
+func NotificationChannelsEqual(desired requests.NotificationChannelConfig, existing *apiextensionsv1.JSON) (bool, error) {
+	return isJSONSubset(desired, existing)
+}
+
+func sortedSelectorKeys(selectors map[string]corev1.SecretKeySelector) []string {
+	keys := make([]string, 0, len(selectors))
+	for key := range selectors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = DescribeTable("notification channel request bodies",
+	func(spec v1.OpensearchNotificationChannelSpec, expected string) {
+		secretValue := func(selector corev1.SecretKeySelector) (string, error) {
+			if selector.Name != "channel-secrets" {
+				return "", fmt.Errorf("secret %s not found", selector.Name)
+			}
+			return "secret-" + selector.Key, nil
+		}
+		config, err := TranslateNotificationChannelToRequest(spec, "alerts", secretValue)
+		if err != nil {
+			Expect(err).To(MatchError(expected))
+			return
+		}
+		raw, err := json.Marshal(config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raw).To(MatchJSON(expected))
+	},
+	Entry("When a slack channel is given",
+		v1.OpensearchNotificationChannelSpec{Slack: &v1.SlackChannel{URLFrom: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "channel-secrets"}, Key: "url",
+		}}},
+		`{"name":"alerts","config_type":"slack","is_enabled":true,"slack":{"url":"secret-url"}}`),
+	Entry("When a webhook with secret headers is given",
+		v1.OpensearchNotificationChannelSpec{Webhook: &v1.WebhookChannel{
+			URL:     "https://hooks.example.com",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			SecretHeaders: map[string]corev1.SecretKeySelector{"Authorization": {
+				LocalObjectReference: corev1.LocalObjectReference{Name: "channel-secrets"}, Key: "token",
+			}},
+		}},
+		`{"name":"alerts","config_type":"webhook","is_enabled":true,"webhook":{
+			"url":"https://hooks.example.com","method":"POST",
+			"header_params":{"Content-Type":"application/json","Authorization":"secret-token"}
+		}}`),
+	Entry("When an email channel is given",
+		v1.OpensearchNotificationChannelSpec{
+			Enabled: new(bool),
+			Email:   &v1.EmailChannel{EmailAccountID: "smtp", Recipients: []string{"oncall@example.com"}},
+		},
+		`{"name":"alerts","config_type":"email","is_enabled":false,"email":{
+			"email_account_id":"smtp","recipient_list":[{"recipient":"oncall@example.com"}],"email_group_id_list":[]
+		}}`),
+	Entry("When no type is given", v1.OpensearchNotificationChannelSpec{}, "exactly one of slack, webhook, sns and email has to be set, got 0"),
+	Entry("When the webhook has no url",
+		v1.OpensearchNotificationChannelSpec{Webhook: &v1.WebhookChannel{}},
+		"exactly one of url and urlFrom has to be set for the webhook"),
+	Entry("When the secret does not exist",
+		v1.OpensearchNotificationChannelSpec{Slack: &v1.SlackChannel{URLFrom: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "other"}, Key: "url",
+		}}},
+		"secret other not found"),
+)
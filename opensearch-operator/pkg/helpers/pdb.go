@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"fmt"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DefaultPdbConfig returns the PodDisruptionBudget limits used if a node pool sets none. A majority of the nodes of a
+// cluster manager pool must stay available so that the pool keeps its quorum, other pools may lose one node at a time
+func DefaultPdbConfig(nodePool *opsterv1.NodePool, replicas int32) opsterv1.PdbConfig {
+	if HasManagerRole(nodePool) {
+		minAvailable := intstr.FromInt(int(replicas/2 + 1))
+		return opsterv1.PdbConfig{Enable: true, MinAvailable: &minAvailable}
+	}
+	maxUnavailable := intstr.FromInt(1)
+	return opsterv1.PdbConfig{Enable: true, MaxUnavailable: &maxUnavailable}
+}
+
+// PdbConfigForNodePool returns the PodDisruptionBudget the node pool should have, nil if it should have none. A node
+// pool without pdb section uses the defaults if the cluster enables default pdbs, unless it has a single replica, as a
+// default pdb could block the eviction of its only pod forever
+func PdbConfigForNodePool(cr *opsterv1.OpenSearchCluster, nodePool *opsterv1.NodePool) (*opsterv1.PdbConfig, error) {
+	pdb := nodePool.Pdb
+	if pdb == nil {
+		replicas := EffectiveReplicas(cr.Status, nodePool)
+		if !cr.Spec.General.DefaultPdbs || replicas <= 1 {
+			return nil, nil
+		}
+		defaults := DefaultPdbConfig(nodePool, replicas)
+		return &defaults, nil
+	}
+	if !pdb.Enable {
+		return nil, nil
+	}
+	if (pdb.MinAvailable != nil && pdb.MaxUnavailable != nil) || (pdb.MinAvailable == nil && pdb.MaxUnavailable == nil) {
+		return nil, fmt.Errorf("please provide only one parameter (minAvailable OR maxUnavailable) in order to configure a PodDisruptionBudget")
+	}
+	return pdb, nil
+}
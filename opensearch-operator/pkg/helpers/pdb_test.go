@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("pod disruption budgets", func() {
+	cluster := func(defaultPdbs bool) *opsterv1.OpenSearchCluster {
+		cr := &opsterv1.OpenSearchCluster{}
+		cr.Spec.General.DefaultPdbs = defaultPdbs
+		return cr
+	}
+	masters := &opsterv1.NodePool{Component: "masters", Replicas: 5, Roles: []string{"cluster_manager"}}
+	data := &opsterv1.NodePool{Component: "data", Replicas: 7, Roles: []string{"data"}}
+
+	It("creates no pdb without pdb section and defaults", func() {
+		pdb, err := PdbConfigForNodePool(cluster(false), data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pdb).To(BeNil())
+	})
+
+	It("keeps a majority of the cluster managers available by default", func() {
+		pdb, err := PdbConfigForNodePool(cluster(true), masters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*pdb.MinAvailable).To(Equal(intstr.FromInt(3)))
+		Expect(pdb.MaxUnavailable).To(BeNil())
+	})
+
+	It("allows one unavailable data node by default", func() {
+		pdb, err := PdbConfigForNodePool(cluster(true), data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*pdb.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+		Expect(pdb.MinAvailable).To(BeNil())
+	})
+
+	It("creates no default pdb for a single replica", func() {
+		pool := *masters
+		pool.Replicas = 1
+		pdb, err := PdbConfigForNodePool(cluster(true), &pool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pdb).To(BeNil())
+	})
+
+	It("rejects an enabled pdb without limits", func() {
+		pool := *masters
+		pool.Pdb = &opsterv1.PdbConfig{Enable: true}
+		_, err := PdbConfigForNodePool(cluster(true), &pool)
+		Expect(err).To(MatchError(ContainSubstring("only one parameter")))
+	})
+
+	It("uses the limits of the node pool", func() {
+		maxUnavailable := intstr.FromString("25%")
+		pool := *data
+		pool.Pdb = &opsterv1.PdbConfig{Enable: true, MaxUnavailable: &maxUnavailable}
+		pdb, err := PdbConfigForNodePool(cluster(true), &pool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pdb).To(Equal(pool.Pdb))
+	})
+
+	It("creates no pdb for a disabled pdb section", func() {
+		pool := *data
+		pool.Pdb = &opsterv1.PdbConfig{Enable: false}
+		pdb, err := PdbConfigForNodePool(cluster(true), &pool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pdb).To(BeNil())
+	})
+
+	It("rejects both limits", func() {
+		one := intstr.FromInt(1)
+		pool := *data
+		pool.Pdb = &opsterv1.PdbConfig{Enable: true, MinAvailable: &one, MaxUnavailable: &one}
+		_, err := PdbConfigForNodePool(cluster(false), &pool)
+		Expect(err).To(MatchError(ContainSubstring("only one parameter")))
+	})
+})
@@ -0,0 +1,191 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// PolicyEnforcement selects what happens to a component template violating a template policy
+type PolicyEnforcement string
+
+const (
+	// PolicyEnforcementDeny fails the reconcile of a violating component template, it is not applied
+	PolicyEnforcementDeny PolicyEnforcement = "deny"
+	// PolicyEnforcementWarn reports the violation and applies the component template anyway
+	PolicyEnforcementWarn PolicyEnforcement = "warn"
+)
+
+// TemplatePolicies are organization-wide rules written in Rego the translated component templates are checked
+// against. Every package of the modules may define deny and warn rules holding the messages of its violations.
+type TemplatePolicies struct {
+	packages []templatePolicyPackage
+}
+
+// templatePolicyPackage is a package of the policy modules with the prepared query of its deny and warn rules
+type templatePolicyPackage struct {
+	name  string
+	query rego.PreparedEvalQuery
+}
+
+// PolicyViolation is a message of a deny or warn rule of a policy package
+type PolicyViolation struct {
+	Policy      string
+	Enforcement PolicyEnforcement
+	Message     string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("policy %s: %s", v.Policy, v.Message)
+}
+
+// ParseTemplatePolicies compiles the Rego modules of a ConfigMap, every key holds one module. The modules are
+// compiled together, so they may share packages and import each other.
+func ParseTemplatePolicies(ctx context.Context, data map[string]string) (*TemplatePolicies, error) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var options []func(*rego.Rego)
+	paths := map[string]bool{}
+	for _, key := range keys {
+		module, err := ast.ParseModule(key, data[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid template policy %s: %w", key, err)
+		}
+		if module == nil {
+			return nil, fmt.Errorf("invalid template policy %s: no Rego module", key)
+		}
+		paths[module.Package.Path.String()] = true
+		options = append(options, rego.ParsedModule(module))
+	}
+
+	policies := &TemplatePolicies{}
+	for path := range paths {
+		query := fmt.Sprintf("deny := [msg | msg := %s.deny[_]]; warn := [msg | msg := %s.warn[_]]", path, path)
+		prepared, err := rego.New(append([]func(*rego.Rego){rego.Query(query)}, options...)...).PrepareForEval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template policy %s: %w", strings.TrimPrefix(path, "data."), err)
+		}
+		policies.packages = append(policies.packages, templatePolicyPackage{
+			name:  strings.TrimPrefix(path, "data."),
+			query: prepared,
+		})
+	}
+	sort.Slice(policies.packages, func(i, j int) bool { return policies.packages[i].name < policies.packages[j].name })
+	return policies, nil
+}
+
+// EvaluateTemplatePolicies returns the violations of the policies by the translated component template. The template
+// is the input of the policies as it is sent to OpenSearch, except that its settings are nested and have the index.
+// prefix, however they are written in the spec, e.g. input.template.settings.index.number_of_replicas.
+func EvaluateTemplatePolicies(ctx context.Context, policies *TemplatePolicies, template requests.ComponentTemplate) ([]PolicyViolation, error) {
+	if policies == nil || len(policies.packages) == 0 {
+		return nil, nil
+	}
+	input, err := templatePolicyInput(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []PolicyViolation
+	for _, policy := range policies.packages {
+		results, err := policy.query.Eval(ctx, rego.EvalInput(input))
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate template policy %s: %w", policy.name, err)
+		}
+		for _, result := range results {
+			for _, enforcement := range []PolicyEnforcement{PolicyEnforcementDeny, PolicyEnforcementWarn} {
+				messages, _ := result.Bindings[string(enforcement)].([]interface{})
+				for _, message := range policyMessages(messages) {
+					violations = append(violations, PolicyViolation{
+						Policy:      policy.name,
+						Enforcement: enforcement,
+						Message:     message,
+					})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// policyMessages returns the sorted messages of a rule, values that are no strings are formatted as JSON
+func policyMessages(values []interface{}) []string {
+	messages := make([]string, 0, len(values))
+	for _, value := range values {
+		message, ok := value.(string)
+		if !ok {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				message = fmt.Sprintf("%v", value)
+			} else {
+				message = string(raw)
+			}
+		}
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// templatePolicyInput returns the component template as the input of the policies, with nested settings
+func templatePolicyInput(template requests.ComponentTemplate) (map[string]interface{}, error) {
+	raw, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	input := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(raw, &input); err != nil {
+		return nil, err
+	}
+
+	parsed := map[string]interface{}{}
+	if template.Template.Settings.Size() > 0 {
+		if err := UnmarshalPreservingNumbers(template.Template.Settings.Raw, &parsed); err != nil {
+			return nil, err
+		}
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+	settings := map[string]interface{}{}
+	for key, value := range flat {
+		keys := policySettingKeys(key)
+		nested := settings
+		for _, key := range keys[:len(keys)-1] {
+			next, ok := nested[key].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				nested[key] = next
+			}
+			nested = next
+		}
+		nested[keys[len(keys)-1]] = value
+	}
+
+	index, _ := input["template"].(map[string]interface{})
+	if index == nil {
+		index = map[string]interface{}{}
+		input["template"] = index
+	}
+	index["settings"] = settings
+	return input, nil
+}
+
+// policySettingKeys splits the flat setting into its keys, settings without the index. prefix get it like OpenSearch
+// adds it
+func policySettingKeys(setting string) []string {
+	keys := strings.Split(setting, ".")
+	if keys[0] != "index" {
+		keys = append([]string{"index"}, keys...)
+	}
+	return keys
+}
@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"context"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = Describe("template policies", func() {
+	const requireReplicas = `
+package templates.replicas
+
+deny[msg] {
+	not input.template.settings.index.number_of_replicas
+	msg := "all templates must set index.number_of_replicas"
+}
+`
+	const forbidStandardAnalyzer = `
+package templates.analysis
+
+warn[msg] {
+	analyzer := input.template.settings.index.analysis.analyzer[name]
+	analyzer.type == "standard"
+	msg := sprintf("analyzer %s uses the standard analyzer, use the language analyzers", [name])
+}
+
+warn[msg] {
+	input.template.settings.index.analysis.analyzer[name].stopwords
+	msg := sprintf("analyzer %s sets stopwords, use stopwords_path", [name])
+}
+`
+
+	template := func(settings string) requests.ComponentTemplate {
+		return requests.ComponentTemplate{Template: requests.Index{Settings: &apiextensionsv1.JSON{Raw: []byte(settings)}}}
+	}
+
+	It("should reject a template missing a required setting", func() {
+		policies, err := ParseTemplatePolicies(context.Background(), map[string]string{"replicas.rego": requireReplicas})
+		Expect(err).ToNot(HaveOccurred())
+
+		violations, err := EvaluateTemplatePolicies(context.Background(), policies, template(`{"index":{"number_of_shards":1}}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Enforcement).To(Equal(PolicyEnforcementDeny))
+		Expect(violations[0].String()).To(Equal("policy templates.replicas: all templates must set index.number_of_replicas"))
+	})
+
+	It("should accept required settings in flat and nested notation", func() {
+		policies, err := ParseTemplatePolicies(context.Background(), map[string]string{"replicas.rego": requireReplicas})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, settings := range []string{`{"index":{"number_of_replicas":1}}`, `{"index.number_of_replicas":1}`, `{"number_of_replicas":1}`} {
+			violations, err := EvaluateTemplatePolicies(context.Background(), policies, template(settings))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(violations).To(BeEmpty(), settings)
+		}
+	})
+
+	It("should report the warnings of all policies", func() {
+		policies, err := ParseTemplatePolicies(context.Background(), map[string]string{
+			"analysis.rego": forbidStandardAnalyzer,
+			"replicas.rego": requireReplicas,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		violations, err := EvaluateTemplatePolicies(context.Background(), policies, template(`{"index":{"number_of_replicas":1,"analysis":{"analyzer":{
+			"text":{"type":"standard","stopwords":"_english_"},
+			"title":{"type":"custom","tokenizer":"whitespace"}
+		}}}}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(Equal([]PolicyViolation{
+			{Policy: "templates.analysis", Enforcement: PolicyEnforcementWarn, Message: "analyzer text sets stopwords, use stopwords_path"},
+			{Policy: "templates.analysis", Enforcement: PolicyEnforcementWarn, Message: "analyzer text uses the standard analyzer, use the language analyzers"},
+		}))
+	})
+
+	It("should reject invalid policies", func() {
+		_, err := ParseTemplatePolicies(context.Background(), map[string]string{"typo.rego": "package templates\n\ndeny[msg] {"})
+		Expect(err).To(MatchError(ContainSubstring("invalid template policy typo.rego")))
+		_, err = ParseTemplatePolicies(context.Background(), map[string]string{"empty.rego": "# no module"})
+		Expect(err).To(MatchError(ContainSubstring("invalid template policy empty.rego")))
+	})
+})
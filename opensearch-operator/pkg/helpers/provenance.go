@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const (
+	// ProvenanceMetaKey is the key of the _meta of applied templates the operator records their provenance in
+	ProvenanceMetaKey     = "opensearch_operator"
+	provenanceManagedBy   = "managed_by"
+	provenanceGitRevision = "git_revision"
+)
+
+// WithProvenance merges the provenance of an applied template into its _meta: that the operator manages it and the
+// Git revision it was applied from. Keys of the _meta set in the spec are kept, a _meta that is not a JSON object is
+// returned unchanged.
+func WithProvenance(meta *apiextensionsv1.JSON, revision string) *apiextensionsv1.JSON {
+	parsed := map[string]interface{}{}
+	if meta.Size() > 0 {
+		if err := UnmarshalPreservingNumbers(meta.Raw, &parsed); err != nil || parsed == nil {
+			return meta
+		}
+	}
+	parsed[ProvenanceMetaKey] = map[string]interface{}{
+		provenanceManagedBy:   "opensearch-operator",
+		provenanceGitRevision: revision,
+	}
+	raw, err := json.Marshal(parsed)
+	if err != nil {
+		return meta
+	}
+	return &apiextensionsv1.JSON{Raw: raw}
+}
+
+// WithoutProvenance removes the provenance from the _meta of a template, so templates only differing by their
+// provenance compare equal. A _meta only holding the provenance is removed altogether.
+func WithoutProvenance(meta *apiextensionsv1.JSON) *apiextensionsv1.JSON {
+	if meta.Size() == 0 {
+		return meta
+	}
+	var parsed map[string]interface{}
+	if err := UnmarshalPreservingNumbers(meta.Raw, &parsed); err != nil {
+		return meta
+	}
+	if _, ok := parsed[ProvenanceMetaKey]; !ok {
+		return meta
+	}
+	delete(parsed, ProvenanceMetaKey)
+	if len(parsed) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(parsed)
+	if err != nil {
+		return meta
+	}
+	return &apiextensionsv1.JSON{Raw: raw}
+}
+
+// ProvenanceRevision returns the Git revision recorded in the _meta of a template, empty if none is recorded
+func ProvenanceRevision(meta *apiextensionsv1.JSON) string {
+	if meta.Size() == 0 {
+		return ""
+	}
+	var parsed struct {
+		Provenance struct {
+			GitRevision string `json:"git_revision"`
+		} `json:"opensearch_operator"`
+	}
+	if err := json.Unmarshal(meta.Raw, &parsed); err != nil {
+		return ""
+	}
+	return parsed.Provenance.GitRevision
+}
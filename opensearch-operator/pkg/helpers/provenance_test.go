@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = Describe("template provenance", func() {
+	It("should merge the provenance into the _meta of the spec", func() {
+		meta := WithProvenance(&apiextensionsv1.JSON{Raw: []byte(`{"owner":"search-team"}`)}, "4f2c9e1")
+		Expect(string(meta.Raw)).To(MatchJSON(`{"owner":"search-team","opensearch_operator":{"managed_by":"opensearch-operator","git_revision":"4f2c9e1"}}`))
+		Expect(ProvenanceRevision(meta)).To(Equal("4f2c9e1"))
+		Expect(string(WithoutProvenance(meta).Raw)).To(MatchJSON(`{"owner":"search-team"}`))
+	})
+
+	It("should remove a _meta only holding the provenance", func() {
+		meta := WithProvenance(nil, "4f2c9e1")
+		Expect(ProvenanceRevision(meta)).To(Equal("4f2c9e1"))
+		Expect(WithoutProvenance(meta)).To(BeNil())
+	})
+
+	It("should not compare the provenance of component templates", func() {
+		template := requests.ComponentTemplate{Meta: &apiextensionsv1.JSON{Raw: []byte(`{"owner":"search-team"}`)}}
+		applied := template
+		applied.Meta = WithProvenance(template.Meta, "0b7d3aa")
+		Expect(ComponentTemplatesEqual(template, applied)).To(BeTrue())
+
+		changed := applied
+		changed.Meta = WithProvenance(&apiextensionsv1.JSON{Raw: []byte(`{"owner":"logs-team"}`)}, "0b7d3aa")
+		Expect(ComponentTemplatesEqual(template, changed)).To(BeFalse())
+	})
+})
@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted paths wherever template content is surfaced
+const RedactedValue = "REDACTED"
+
+// Redaction holds the dot separated paths of component template bodies whose values must not be surfaced in events,
+// the status, diffs or tombstones, e.g. template.settings.index.analysis.filter.synonyms.synonyms_path. A * matches
+// any single key. Settings are matched in nested and in flat notation alike, as both join to the same path. The
+// values are only replaced in the surfaced copies, drift detection keeps comparing the real values.
+type Redaction struct {
+	patterns [][]string
+}
+
+// NewRedaction returns the redaction of the passed paths, empty paths are ignored
+func NewRedaction(paths []string) *Redaction {
+	redaction := &Redaction{}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			redaction.patterns = append(redaction.patterns, strings.Split(path, "."))
+		}
+	}
+	return redaction
+}
+
+// Redacts checks if the value at the dot separated path is redacted, a nil redaction redacts nothing
+func (r *Redaction) Redacts(path string) bool {
+	if r == nil || len(r.patterns) == 0 {
+		return false
+	}
+	keys := redactionKeys(path)
+	for _, pattern := range r.patterns {
+		if pathMatches(pattern, keys) {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers checks if a redacted path lies within the dot separated path, so its value would be part of the value there
+func (r *Redaction) Covers(path string) bool {
+	if r == nil {
+		return false
+	}
+	keys := redactionKeys(path)
+	for _, pattern := range r.patterns {
+		if len(pattern) >= len(keys) && pathMatches(pattern[:len(keys)], keys) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactionKeys splits the path into its keys. Settings may be given without the index. prefix, which OpenSearch
+// adds, so they are matched with it.
+func redactionKeys(path string) []string {
+	keys := strings.Split(path, ".")
+	if len(keys) > 2 && keys[0] == "template" && keys[1] == "settings" && keys[2] != "index" {
+		keys = append([]string{"template", "settings", "index"}, keys[2:]...)
+	}
+	return keys
+}
+
+func pathMatches(pattern []string, keys []string) bool {
+	if len(pattern) != len(keys) {
+		return false
+	}
+	for i, key := range keys {
+		if pattern[i] != "*" && pattern[i] != key {
+			return false
+		}
+	}
+	return true
+}
+
+// RedactValue replaces the values of the redacted paths in the parsed JSON value found at path in place and returns it
+func (r *Redaction) RedactValue(path string, value interface{}) interface{} {
+	if r.Redacts(path) {
+		return RedactedValue
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		for key, nested := range object {
+			object[key] = r.RedactValue(joinJSONPath(path, key), nested)
+		}
+	}
+	return value
+}
+
+// RedactChanges returns the changes with the values of redacted paths replaced
+func (r *Redaction) RedactChanges(changes []JSONChange) ([]JSONChange, error) {
+	if r == nil {
+		return changes, nil
+	}
+	redacted := make([]JSONChange, 0, len(changes))
+	for _, change := range changes {
+		if !r.Covers(change.Path) {
+			redacted = append(redacted, change)
+			continue
+		}
+		var err error
+		if change.From, err = r.redactRaw(change.Path, change.From); err != nil {
+			return nil, err
+		}
+		if change.To, err = r.redactRaw(change.Path, change.To); err != nil {
+			return nil, err
+		}
+		redacted = append(redacted, change)
+	}
+	return redacted, nil
+}
+
+func (r *Redaction) redactRaw(path string, raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+	var parsed interface{}
+	if err := UnmarshalPreservingNumbers(raw, &parsed); err != nil {
+		return nil, err
+	}
+	return json.Marshal(r.RedactValue(path, parsed))
+}
@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("redacted values",
+	func(paths []string, body string, expected string) {
+		var parsed interface{}
+		Expect(UnmarshalPreservingNumbers([]byte(body), &parsed)).To(Succeed())
+		redacted, err := json.Marshal(NewRedaction(paths).RedactValue("", parsed))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(redacted).To(MatchJSON(expected))
+	},
+	Entry("When a nested setting is redacted", []string{"template.settings.index.analysis.filter.synonyms.synonyms_path"},
+		`{"template":{"settings":{"index":{"number_of_shards":"1","analysis":{"filter":{"synonyms":{"type":"synonym","synonyms_path":"/internal/synonyms.txt"}}}}}}}`,
+		`{"template":{"settings":{"index":{"number_of_shards":"1","analysis":{"filter":{"synonyms":{"type":"synonym","synonyms_path":"REDACTED"}}}}}}}`),
+	Entry("When a flat setting is redacted", []string{"template.settings.index.analysis.filter.synonyms.synonyms_path"},
+		`{"template":{"settings":{"index.analysis.filter.synonyms.synonyms_path":"/internal/synonyms.txt"}}}`,
+		`{"template":{"settings":{"index.analysis.filter.synonyms.synonyms_path":"REDACTED"}}}`),
+	Entry("When a setting is given without the index prefix", []string{"template.settings.index.routing.allocation.require.host"},
+		`{"template":{"settings":{"routing":{"allocation":{"require":{"host":"node-1.internal"}}}}}}`,
+		`{"template":{"settings":{"routing":{"allocation":{"require":{"host":"REDACTED"}}}}}}`),
+	Entry("When a path uses a wildcard", []string{"template.settings.index.analysis.filter.*.synonyms_path"},
+		`{"template":{"settings":{"index":{"analysis":{"filter":{"a":{"synonyms_path":"/a"},"b":{"synonyms_path":"/b","type":"synonym"}}}}}}}`,
+		`{"template":{"settings":{"index":{"analysis":{"filter":{"a":{"synonyms_path":"REDACTED"},"b":{"synonyms_path":"REDACTED","type":"synonym"}}}}}}}`),
+	Entry("When an object is redacted", []string{"_meta.owner"},
+		`{"_meta":{"owner":{"team":"search","host":"ops.internal"}}}`,
+		`{"_meta":{"owner":"REDACTED"}}`),
+	Entry("When no path is redacted", nil,
+		`{"template":{"settings":{"index":{"number_of_shards":"1"}}}}`,
+		`{"template":{"settings":{"index":{"number_of_shards":"1"}}}}`),
+)
+
+var _ = Describe("redacted diffs and mismatches", func() {
+	redaction := NewRedaction([]string{"template.settings.index.analysis.filter.synonyms.synonyms_path", "template.mappings.properties.host.null_value"})
+
+	It("should redact the values of changes at or above redacted paths", func() {
+		from := map[string]interface{}{"template": map[string]interface{}{"settings": map[string]interface{}{"index": map[string]interface{}{
+			"number_of_shards": "1",
+			"analysis":         map[string]interface{}{"filter": map[string]interface{}{"synonyms": map[string]interface{}{"synonyms_path": "/old.txt"}}},
+		}}}}
+		to := map[string]interface{}{"template": map[string]interface{}{"settings": map[string]interface{}{"index": map[string]interface{}{
+			"number_of_shards": "2",
+			"analysis":         map[string]interface{}{"filter": map[string]interface{}{"synonyms": map[string]interface{}{"synonyms_path": "/new.txt"}}},
+		}}}}
+		changes, err := JSONDiff(from, to)
+		Expect(err).ToNot(HaveOccurred())
+		redacted, err := redaction.RedactChanges(changes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(redacted).To(HaveLen(2))
+		Expect(redacted[0].Path).To(Equal("template.settings.index.analysis.filter.synonyms.synonyms_path"))
+		Expect(string(redacted[0].From)).To(Equal(`"REDACTED"`))
+		Expect(string(redacted[0].To)).To(Equal(`"REDACTED"`))
+		Expect(string(redacted[1].From)).To(Equal(`"1"`))
+		Expect(string(redacted[1].To)).To(Equal(`"2"`))
+
+		// The diff itself is computed from the real values
+		Expect(string(changes[0].To)).To(Equal(`"/new.txt"`))
+
+		added, err := JSONDiff(map[string]interface{}{}, to)
+		Expect(err).ToNot(HaveOccurred())
+		redacted, err = redaction.RedactChanges(added)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(redacted[0].To)).ToNot(ContainSubstring("/new.txt"))
+		Expect(string(redacted[0].To)).To(ContainSubstring(`"number_of_shards":"2"`))
+	})
+
+	It("should not include redacted values in index mismatches", func() {
+		template := requests.Index{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"1","analysis":{"filter":{"synonyms":{"synonyms_path":"/expected.txt"}}}}}`)},
+			Mappings: &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"host":{"type":"keyword","null_value":"expected.internal"}}}`)},
+		}
+		settings := map[string]interface{}{
+			"index.number_of_shards":                       "2",
+			"index.analysis.filter.synonyms.synonyms_path": "/actual.txt",
+		}
+		mappings := &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"host":{"type":"keyword","null_value":"actual.internal"}}}`)}
+
+		mismatches, err := IndexMismatches(template, settings, mappings, redaction)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mismatches).To(Equal([]string{
+			"mapping properties.host.null_value differs",
+			"setting index.analysis.filter.synonyms.synonyms_path differs",
+			"setting index.number_of_shards is 2 instead of 1",
+		}))
+	})
+})
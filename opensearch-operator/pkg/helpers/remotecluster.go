@@ -0,0 +1,142 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"k8s.io/utils/pointer"
+)
+
+// remoteClusterSetting returns the flat name of a setting of the remote cluster connection with the passed alias
+func remoteClusterSetting(alias string, name string) string {
+	return fmt.Sprintf("cluster.remote.%s.%s", alias, name)
+}
+
+// ValidateRemoteCluster returns an error naming every problem of the spec OpenSearch would reject
+func ValidateRemoteCluster(spec v1.OpensearchRemoteClusterSpec) error {
+	var invalid []string
+	if spec.Mode == "proxy" {
+		if spec.RemoteCluster == nil && spec.ProxyAddress == "" {
+			invalid = append(invalid, "either remoteCluster or proxyAddress has to be set in proxy mode")
+		}
+		if spec.RemoteCluster != nil && spec.ProxyAddress != "" {
+			invalid = append(invalid, "remoteCluster and proxyAddress are mutually exclusive")
+		}
+		if len(spec.Seeds) > 0 {
+			invalid = append(invalid, "seeds can only be set in sniff mode")
+		}
+	} else {
+		if spec.RemoteCluster == nil && len(spec.Seeds) == 0 {
+			invalid = append(invalid, "either remoteCluster or seeds has to be set in sniff mode")
+		}
+		if spec.RemoteCluster != nil && len(spec.Seeds) > 0 {
+			invalid = append(invalid, "remoteCluster and seeds are mutually exclusive")
+		}
+		if spec.ProxyAddress != "" || spec.ServerName != "" {
+			invalid = append(invalid, "proxyAddress and serverName can only be set in proxy mode")
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid remote cluster: %s", strings.Join(invalid, "; "))
+}
+
+// DesiredRemoteClusterSettings returns the flat persistent settings of the connection the spec describes.
+// remoteAddress is the transport address of the referenced remote cluster, if any. Settings of the other mode are
+// nil, as OpenSearch only accepts a change of the mode together with the reset of those settings
+func DesiredRemoteClusterSettings(spec v1.OpensearchRemoteClusterSpec, alias string, remoteAddress string) map[string]interface{} {
+	mode := spec.Mode
+	if mode == "" {
+		mode = "sniff"
+	}
+	settings := map[string]interface{}{
+		remoteClusterSetting(alias, "mode"):             mode,
+		remoteClusterSetting(alias, "skip_unavailable"): strconv.FormatBool(pointer.BoolDeref(spec.SkipUnavailable, false)),
+		remoteClusterSetting(alias, "seeds"):            nil,
+		remoteClusterSetting(alias, "proxy_address"):    nil,
+		remoteClusterSetting(alias, "server_name"):      nil,
+	}
+	if mode == "proxy" {
+		address := spec.ProxyAddress
+		if address == "" {
+			address = remoteAddress
+		}
+		settings[remoteClusterSetting(alias, "proxy_address")] = address
+		if spec.ServerName != "" {
+			settings[remoteClusterSetting(alias, "server_name")] = spec.ServerName
+		}
+	} else {
+		seeds := spec.Seeds
+		if len(seeds) == 0 {
+			seeds = []string{remoteAddress}
+		}
+		settings[remoteClusterSetting(alias, "seeds")] = seeds
+	}
+	return settings
+}
+
+// RemoteClusterSettingsChanges returns the desired settings that differ from the persistent settings of the cluster.
+// Settings that are not desired are only reset if they are set
+func RemoteClusterSettingsChanges(desired map[string]interface{}, existing responses.ClusterSettingsResponse) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for name, value := range desired {
+		current, ok := existing.Persistent[name]
+		if value == nil {
+			if ok {
+				changes[name] = nil
+			}
+			continue
+		}
+		if !ok || !sameSettingValue(value, current) {
+			changes[name] = value
+		}
+	}
+	return changes
+}
+
+// sameSettingValue compares a desired value with the value OpenSearch returns, which has lists decoded as
+// []interface{}
+func sameSettingValue(desired interface{}, current interface{}) bool {
+	desiredJSON, err := json.Marshal(desired)
+	if err != nil {
+		return false
+	}
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return false
+	}
+	return string(desiredJSON) == string(currentJSON)
+}
+
+// RemoteClusterExists returns true if any setting of the connection with the passed alias is set
+func RemoteClusterExists(settings responses.ClusterSettingsResponse, alias string) bool {
+	prefix := remoteClusterSetting(alias, "")
+	for _, scope := range []map[string]interface{}{settings.Persistent, settings.Transient} {
+		for name := range scope {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RemoteClusterResetSettings returns the persistent settings of the connection with the passed alias set to nil,
+// which removes the connection
+func RemoteClusterResetSettings(settings responses.ClusterSettingsResponse, alias string) map[string]interface{} {
+	prefix := remoteClusterSetting(alias, "")
+	reset := map[string]interface{}{}
+	for name := range settings.Persistent {
+		if strings.HasPrefix(name, prefix) {
+			reset[name] = nil
+		}
+	}
+	return reset
+}
@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = DescribeTable("remote cluster validation",
+	func(spec v1.OpensearchRemoteClusterSpec, expected string) {
+		err := ValidateRemoteCluster(spec)
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When seeds are set in sniff mode", v1.OpensearchRemoteClusterSpec{Seeds: []string{"remote:9300"}}, ""),
+	Entry("When the remote cluster is set in proxy mode", v1.OpensearchRemoteClusterSpec{
+		Mode:          "proxy",
+		RemoteCluster: &v1.RemoteClusterReference{Name: "remote"},
+	}, ""),
+	Entry("When the remote is missing in sniff mode", v1.OpensearchRemoteClusterSpec{}, "either remoteCluster or seeds has to be set in sniff mode"),
+	Entry("When the remote is missing in proxy mode", v1.OpensearchRemoteClusterSpec{Mode: "proxy"}, "either remoteCluster or proxyAddress has to be set in proxy mode"),
+	Entry("When seeds are set in proxy mode", v1.OpensearchRemoteClusterSpec{
+		Mode:         "proxy",
+		ProxyAddress: "remote-lb:9300",
+		Seeds:        []string{"remote:9300"},
+	}, "seeds can only be set in sniff mode"),
+	Entry("When a proxy address is set in sniff mode", v1.OpensearchRemoteClusterSpec{
+		Seeds:        []string{"remote:9300"},
+		ProxyAddress: "remote-lb:9300",
+	}, "proxyAddress and serverName can only be set in proxy mode"),
+	Entry("When the remote cluster and seeds are set", v1.OpensearchRemoteClusterSpec{
+		RemoteCluster: &v1.RemoteClusterReference{Name: "remote"},
+		Seeds:         []string{"remote:9300"},
+	}, "remoteCluster and seeds are mutually exclusive"),
+)
+
+var _ = Describe("remote cluster settings", func() {
+	var existing responses.ClusterSettingsResponse
+
+	BeforeEach(func() {
+		existing = responses.ClusterSettingsResponse{}
+		Expect(json.Unmarshal([]byte(`{
+			"persistent": {
+				"cluster.remote.remote.mode": "sniff",
+				"cluster.remote.remote.seeds": ["remote-0:9300"],
+				"cluster.remote.remote.skip_unavailable": "false",
+				"cluster.remote.other.seeds": ["other:9300"]
+			},
+			"transient": {}
+		}`), &existing)).To(Succeed())
+	})
+
+	It("should not change a connection in sync", func() {
+		spec := v1.OpensearchRemoteClusterSpec{Seeds: []string{"remote-0:9300"}}
+		desired := DesiredRemoteClusterSettings(spec, "remote", "")
+		Expect(RemoteClusterSettingsChanges(desired, existing)).To(BeEmpty())
+	})
+
+	It("should reset the seeds when switching to proxy mode", func() {
+		spec := v1.OpensearchRemoteClusterSpec{Mode: "proxy", ServerName: "remote.example.com", SkipUnavailable: pointer.Bool(true)}
+		desired := DesiredRemoteClusterSettings(spec, "remote", "remote.ns.svc.cluster.local:9300")
+		Expect(RemoteClusterSettingsChanges(desired, existing)).To(Equal(map[string]interface{}{
+			"cluster.remote.remote.mode":             "proxy",
+			"cluster.remote.remote.seeds":            nil,
+			"cluster.remote.remote.proxy_address":    "remote.ns.svc.cluster.local:9300",
+			"cluster.remote.remote.server_name":      "remote.example.com",
+			"cluster.remote.remote.skip_unavailable": "true",
+		}))
+	})
+
+	It("should find and reset only the settings of the connection", func() {
+		Expect(RemoteClusterExists(existing, "remote")).To(BeTrue())
+		Expect(RemoteClusterExists(existing, "rem")).To(BeFalse())
+		Expect(RemoteClusterResetSettings(existing, "remote")).To(Equal(map[string]interface{}{
+			"cluster.remote.remote.mode":             nil,
+			"cluster.remote.remote.seeds":            nil,
+			"cluster.remote.remote.skip_unavailable": nil,
+		}))
+	})
+})
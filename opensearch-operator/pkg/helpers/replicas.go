@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// DefaultReplicaFactorPercent is the share of the other data nodes holding a replica if a policy sets none
+const DefaultReplicaFactorPercent = 100
+
+// ReplicaCount returns the number of replicas the policy asks for on a cluster with the passed number of data nodes
+func ReplicaCount(policy opsterv1.ReplicaPolicy, dataNodes int) int {
+	factor := DefaultReplicaFactorPercent
+	if policy.FactorPercent != nil {
+		factor = int(*policy.FactorPercent)
+	}
+	replicas := 0
+	if dataNodes > 1 {
+		replicas = (dataNodes - 1) * factor / 100
+	}
+	if policy.MaxReplicas != nil && replicas > int(*policy.MaxReplicas) {
+		replicas = int(*policy.MaxReplicas)
+	}
+	if replicas < int(policy.MinReplicas) {
+		replicas = int(policy.MinReplicas)
+	}
+	return replicas
+}
+
+// SetNumberOfReplicas returns a copy of the index settings with index.number_of_replicas set to replicas. An error is
+// returned if the settings already set the number of replicas, either directly or with index.auto_expand_replicas.
+func SetNumberOfReplicas(settings *apiextensionsv1.JSON, replicas int) (*apiextensionsv1.JSON, error) {
+	parsed := map[string]interface{}{}
+	if settings.Size() > 0 {
+		if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+			return nil, err
+		}
+	}
+
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+	for _, key := range []string{"index.number_of_replicas", "number_of_replicas", "index.auto_expand_replicas", "auto_expand_replicas"} {
+		if _, ok := flat[key]; ok {
+			return nil, fmt.Errorf("settings already set %s, which conflicts with the replica policy", key)
+		}
+	}
+
+	index, ok := parsed["index"].(map[string]interface{})
+	if !ok {
+		if _, exists := parsed["index"]; exists {
+			return nil, fmt.Errorf("setting index is not an object")
+		}
+		index = map[string]interface{}{}
+		parsed["index"] = index
+	}
+	index["number_of_replicas"] = strconv.Itoa(replicas)
+
+	raw, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = DescribeTable("replica count",
+	func(policy opsterv1.ReplicaPolicy, dataNodes int, expected int) {
+		Expect(ReplicaCount(policy, dataNodes)).To(Equal(expected))
+	},
+	Entry("When the cluster has a single data node", opsterv1.ReplicaPolicy{}, 1, 0),
+	Entry("When the cluster has no data node", opsterv1.ReplicaPolicy{}, 0, 0),
+	Entry("When every data node holds a copy", opsterv1.ReplicaPolicy{}, 5, 4),
+	Entry("When half of the other data nodes hold a replica", opsterv1.ReplicaPolicy{FactorPercent: pointer.Int32(50)}, 6, 2),
+	Entry("When no data node holds a replica", opsterv1.ReplicaPolicy{FactorPercent: pointer.Int32(0)}, 6, 0),
+	Entry("When the replicas exceed the maximum", opsterv1.ReplicaPolicy{MaxReplicas: pointer.Int32(2)}, 10, 2),
+	Entry("When the replicas are below the minimum", opsterv1.ReplicaPolicy{MinReplicas: 1}, 1, 1),
+	Entry("When the replicas are within the bounds", opsterv1.ReplicaPolicy{MinReplicas: 1, MaxReplicas: pointer.Int32(3)}, 3, 2),
+)
+
+var _ = DescribeTable("number of replicas settings",
+	func(settings string, expected string, valid bool) {
+		var input *apiextensionsv1.JSON
+		if settings != "" {
+			input = &apiextensionsv1.JSON{Raw: []byte(settings)}
+		}
+		result, err := SetNumberOfReplicas(input, 2)
+		if !valid {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Raw).To(MatchJSON(expected))
+	},
+	Entry("When the template has no settings", "", `{"index":{"number_of_replicas":"2"}}`, true),
+	Entry("When the template has other settings", `{"index":{"number_of_shards":"1"}}`,
+		`{"index":{"number_of_shards":"1","number_of_replicas":"2"}}`, true),
+	Entry("When the template sets the replicas", `{"index.number_of_replicas":"1"}`, "", false),
+	Entry("When the template expands the replicas", `{"index":{"auto_expand_replicas":"0-all"}}`, "", false),
+)
@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+)
+
+// ValidateReplication returns an error naming every problem of the spec OpenSearch would reject
+func ValidateReplication(spec v1.OpensearchCrossClusterReplicationSpec) error {
+	var invalid []string
+	if spec.LeaderCluster == nil && len(spec.Seeds) == 0 {
+		invalid = append(invalid, "either leaderCluster or seeds has to be set")
+	}
+	if spec.LeaderCluster != nil && len(spec.Seeds) > 0 {
+		invalid = append(invalid, "leaderCluster and seeds are mutually exclusive")
+	}
+	names := map[string]bool{}
+	for _, rule := range spec.AutoFollowRules {
+		if names[rule.Name] {
+			invalid = append(invalid, fmt.Sprintf("auto-follow rule %s is defined more than once", rule.Name))
+		}
+		names[rule.Name] = true
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid replication: %s", strings.Join(invalid, "; "))
+}
+
+// RemoteClusterSeedsSetting returns the flat name of the setting holding the seeds of the remote cluster connection
+func RemoteClusterSeedsSetting(alias string) string {
+	return remoteClusterSetting(alias, "seeds")
+}
+
+// RemoteClusterSeeds returns the seeds of the remote cluster connection with the passed alias from the flat cluster
+// settings, persistent settings take precedence over transient ones. The second value is false if the connection
+// does not exist
+func RemoteClusterSeeds(settings responses.ClusterSettingsResponse, alias string) ([]string, bool) {
+	setting := RemoteClusterSeedsSetting(alias)
+	value, ok := settings.Persistent[setting]
+	if !ok {
+		value, ok = settings.Transient[setting]
+	}
+	if !ok {
+		return nil, false
+	}
+	var seeds []string
+	switch seedValue := value.(type) {
+	case []interface{}:
+		for _, seed := range seedValue {
+			seeds = append(seeds, fmt.Sprint(seed))
+		}
+	case string:
+		seeds = append(seeds, seedValue)
+	}
+	return seeds, true
+}
+
+// AutoFollowRuleChanges returns the rules to create and the names of the rules to delete to go from the existing
+// rules to the desired ones. Rules cannot be updated, so a rule whose pattern changed is deleted and created again.
+// Only the managed rules, which the operator created before, are deleted when they are no longer desired
+func AutoFollowRuleChanges(desired []v1.AutoFollowRule, existing []responses.AutoFollowRuleStats, managed []v1.AutoFollowRuleStatus) (create []v1.AutoFollowRule, remove []string) {
+	existingPatterns := map[string]string{}
+	for _, rule := range existing {
+		existingPatterns[rule.Name] = rule.Pattern
+	}
+	desiredNames := map[string]bool{}
+	for _, rule := range desired {
+		desiredNames[rule.Name] = true
+		pattern, ok := existingPatterns[rule.Name]
+		if !ok {
+			create = append(create, rule)
+		} else if pattern != rule.Pattern {
+			remove = append(remove, rule.Name)
+			create = append(create, rule)
+		}
+	}
+	for _, rule := range managed {
+		if _, ok := existingPatterns[rule.Name]; ok && !desiredNames[rule.Name] {
+			remove = append(remove, rule.Name)
+		}
+	}
+	sort.Strings(remove)
+	return create, remove
+}
+
+// ReplicatedIndexStatus converts the state OpenSearch reports for a follower index to the status of the resource
+func ReplicatedIndexStatus(status *responses.ReplicationStatusResponse) v1.ReplicatedIndexStatus {
+	indexStatus := v1.ReplicatedIndexStatus{
+		FollowerIndex: status.FollowerIndex,
+		LeaderIndex:   status.LeaderIndex,
+		Status:        status.Status,
+		Reason:        status.Reason,
+	}
+	if status.SyncingDetails != nil && status.SyncingDetails.LeaderCheckpoint > status.SyncingDetails.FollowerCheckpoint {
+		indexStatus.Lag = status.SyncingDetails.LeaderCheckpoint - status.SyncingDetails.FollowerCheckpoint
+	}
+	return indexStatus
+}
@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = DescribeTable("replication validation",
+	func(spec v1.OpensearchCrossClusterReplicationSpec, expected string) {
+		err := ValidateReplication(spec)
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When seeds are set", v1.OpensearchCrossClusterReplicationSpec{Seeds: []string{"leader:9300"}}, ""),
+	Entry("When the leader cluster is set", v1.OpensearchCrossClusterReplicationSpec{
+		LeaderCluster: &corev1.LocalObjectReference{Name: "leader"},
+	}, ""),
+	Entry("When the leader is missing", v1.OpensearchCrossClusterReplicationSpec{}, "either leaderCluster or seeds has to be set"),
+	Entry("When both leader cluster and seeds are set", v1.OpensearchCrossClusterReplicationSpec{
+		LeaderCluster: &corev1.LocalObjectReference{Name: "leader"},
+		Seeds:         []string{"leader:9300"},
+	}, "leaderCluster and seeds are mutually exclusive"),
+	Entry("When a rule is defined twice", v1.OpensearchCrossClusterReplicationSpec{
+		Seeds:           []string{"leader:9300"},
+		AutoFollowRules: []v1.AutoFollowRule{{Name: "logs", Pattern: "logs-*"}, {Name: "logs", Pattern: "metrics-*"}},
+	}, "auto-follow rule logs is defined more than once"),
+)
+
+var _ = Describe("replication changes", func() {
+	It("should read the seeds of the connection", func() {
+		settings := responses.ClusterSettingsResponse{}
+		Expect(json.Unmarshal([]byte(`{
+			"persistent": {"cluster.remote.leader.seeds": ["leader-0:9300", "leader-1:9300"]},
+			"transient": {"cluster.remote.other.seeds": "other:9300"}
+		}`), &settings)).To(Succeed())
+
+		seeds, ok := RemoteClusterSeeds(settings, "leader")
+		Expect(ok).To(BeTrue())
+		Expect(seeds).To(Equal([]string{"leader-0:9300", "leader-1:9300"}))
+		seeds, ok = RemoteClusterSeeds(settings, "other")
+		Expect(ok).To(BeTrue())
+		Expect(seeds).To(Equal([]string{"other:9300"}))
+		_, ok = RemoteClusterSeeds(settings, "missing")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should only delete managed rules and recreate changed rules", func() {
+		desired := []v1.AutoFollowRule{{Name: "logs", Pattern: "logs-*"}, {Name: "metrics", Pattern: "metrics-v2-*"}, {Name: "traces", Pattern: "traces-*"}}
+		existing := []responses.AutoFollowRuleStats{
+			{Name: "logs", Pattern: "logs-*"},
+			{Name: "metrics", Pattern: "metrics-*"},
+			{Name: "audit", Pattern: "audit-*"},
+			{Name: "manual", Pattern: "manual-*"},
+		}
+		managed := []v1.AutoFollowRuleStatus{{Name: "logs"}, {Name: "metrics"}, {Name: "audit"}, {Name: "gone"}}
+
+		create, remove := AutoFollowRuleChanges(desired, existing, managed)
+		Expect(create).To(Equal([]v1.AutoFollowRule{{Name: "metrics", Pattern: "metrics-v2-*"}, {Name: "traces", Pattern: "traces-*"}}))
+		Expect(remove).To(Equal([]string{"audit", "metrics"}))
+	})
+
+	It("should compute the lag of syncing indices", func() {
+		status := responses.ReplicationStatusResponse{}
+		Expect(json.Unmarshal([]byte(`{
+			"status": "SYNCING",
+			"reason": "User initiated",
+			"leader_alias": "leader",
+			"leader_index": "logs-1",
+			"follower_index": "logs-1",
+			"syncing_details": {"leader_checkpoint": 25, "follower_checkpoint": 19, "seq_no": 0}
+		}`), &status)).To(Succeed())
+		Expect(ReplicatedIndexStatus(&status)).To(Equal(v1.ReplicatedIndexStatus{
+			FollowerIndex: "logs-1",
+			LeaderIndex:   "logs-1",
+			Status:        "SYNCING",
+			Reason:        "User initiated",
+			Lag:           6,
+		}))
+	})
+})
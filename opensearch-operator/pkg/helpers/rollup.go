@@ -0,0 +1,256 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+// rollupImmutableFields maps the fields of a rollup job OpenSearch refuses to update to the fields of the spec
+var rollupImmutableFields = map[string]string{
+	"source_index": "sourceIndex",
+	"target_index": "targetIndex",
+	"continuous":   "continuous",
+	"dimensions":   "dimensions",
+	"metrics":      "metrics",
+}
+
+// ValidateRollupJob returns an error naming every problem of the rollup job spec OpenSearch would reject
+func ValidateRollupJob(spec v1.OpensearchRollupJobSpec) error {
+	invalid := validateScheduledJob(spec.Schedule, spec.SourceIndex, spec.TargetIndex)
+	if spec.Delay != "" {
+		if delay, err := ParseTimeValue(spec.Delay); err != nil || delay < 0 {
+			invalid = append(invalid, fmt.Sprintf("delay %q is not a time value, e.g. 1h", spec.Delay))
+		}
+	}
+	if len(spec.Dimensions) > 0 && spec.Dimensions[0].DateHistogram == nil {
+		invalid = append(invalid, "the first dimension has to be a date histogram")
+	}
+	invalid = append(invalid, validateRollupDimensions("dimension", spec.Dimensions)...)
+
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid rollup job: %s", strings.Join(invalid, "; "))
+}
+
+// validateScheduledJob returns the problems of the schedule and indices shared by rollup and transform jobs
+func validateScheduledJob(schedule v1.RollupSchedule, sourceIndex string, targetIndex string) []string {
+	var invalid []string
+	if (schedule.Interval == nil) == (schedule.Cron == nil) {
+		invalid = append(invalid, "exactly one of interval and cron has to be set in the schedule")
+	}
+	if err := ValidateIndexPattern(targetIndex); err != nil {
+		invalid = append(invalid, err.Error())
+	} else if strings.Contains(targetIndex, "*") {
+		invalid = append(invalid, fmt.Sprintf("target index %q must not contain *", targetIndex))
+	}
+	if sourceIndex == targetIndex {
+		invalid = append(invalid, "source and target index have to differ")
+	}
+	return invalid
+}
+
+// validateRollupDimensions returns the problems of the dimensions, named by the passed noun and their position
+func validateRollupDimensions(noun string, dimensions []v1.RollupDimension) []string {
+	var invalid []string
+	for i, dimension := range dimensions {
+		types := 0
+		for _, set := range []bool{dimension.DateHistogram != nil, dimension.Terms != nil, dimension.Histogram != nil} {
+			if set {
+				types++
+			}
+		}
+		if types != 1 {
+			invalid = append(invalid, fmt.Sprintf("%s %d has to set exactly one of dateHistogram, terms and histogram, got %d", noun, i, types))
+			continue
+		}
+		switch {
+		case dimension.DateHistogram != nil && (dimension.DateHistogram.FixedInterval == "") == (dimension.DateHistogram.CalendarInterval == ""):
+			invalid = append(invalid, fmt.Sprintf("date histogram of %s has to set exactly one of fixedInterval and calendarInterval", dimension.DateHistogram.SourceField))
+		case dimension.Histogram != nil:
+			if interval, err := strconv.ParseFloat(dimension.Histogram.Interval, 64); err != nil || interval <= 0 {
+				invalid = append(invalid, fmt.Sprintf("histogram interval %q of %s is not a positive number", dimension.Histogram.Interval, dimension.Histogram.SourceField))
+			}
+		}
+	}
+	return invalid
+}
+
+// TranslateRollupJobToRequest validates the spec and rewrites the CRD format to the gateway format. The start time
+// of an interval schedule is left empty, it is set when the job is created
+func TranslateRollupJobToRequest(spec v1.OpensearchRollupJobSpec) (requests.IndexRollup, error) {
+	if err := ValidateRollupJob(spec); err != nil {
+		return requests.IndexRollup{}, err
+	}
+
+	pageSize := spec.PageSize
+	if pageSize == 0 {
+		pageSize = 1000
+	}
+	request := requests.IndexRollup{
+		Enabled:     pointer.BoolDeref(spec.Enabled, true),
+		Description: spec.Description,
+		SourceIndex: spec.SourceIndex,
+		TargetIndex: spec.TargetIndex,
+		PageSize:    pageSize,
+		Continuous:  spec.Continuous,
+		Dimensions:  []requests.RollupDimension{},
+		Metrics:     []requests.RollupMetric{},
+	}
+
+	request.Schedule = translateRollupSchedule(spec.Schedule)
+
+	if spec.Delay != "" {
+		delay, err := ParseTimeValue(spec.Delay)
+		if err != nil {
+			return requests.IndexRollup{}, err
+		}
+		request.Delay = pointer.Int64(delay.Milliseconds())
+	}
+
+	for _, dimension := range spec.Dimensions {
+		request.Dimensions = append(request.Dimensions, translateRollupDimension(dimension))
+	}
+
+	for _, metric := range spec.Metrics {
+		translated := requests.RollupMetric{SourceField: metric.SourceField}
+		for _, metricType := range metric.Metrics {
+			translated.Metrics = append(translated.Metrics, map[string]map[string]string{string(metricType): {}})
+		}
+		request.Metrics = append(request.Metrics, translated)
+	}
+	return request, nil
+}
+
+// translateRollupSchedule rewrites the schedule of a rollup or transform job, leaving the start time empty
+func translateRollupSchedule(schedule v1.RollupSchedule) requests.RollupSchedule {
+	if schedule.Interval == nil {
+		cron := translateCronSchedule(*schedule.Cron).Cron
+		return requests.RollupSchedule{Cron: &cron}
+	}
+	unit := schedule.Interval.Unit
+	if unit == "" {
+		unit = "Minutes"
+	}
+	return requests.RollupSchedule{Interval: &requests.RollupInterval{Period: schedule.Interval.Period, Unit: unit}}
+}
+
+func translateRollupDimension(dimension v1.RollupDimension) requests.RollupDimension {
+	switch {
+	case dimension.DateHistogram != nil:
+		timezone := dimension.DateHistogram.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+		return requests.RollupDimension{DateHistogram: &requests.RollupDateHistogram{
+			SourceField:      dimension.DateHistogram.SourceField,
+			FixedInterval:    dimension.DateHistogram.FixedInterval,
+			CalendarInterval: dimension.DateHistogram.CalendarInterval,
+			Timezone:         timezone,
+		}}
+	case dimension.Terms != nil:
+		return requests.RollupDimension{Terms: &requests.RollupTerms{SourceField: dimension.Terms.SourceField}}
+	case dimension.Histogram != nil:
+		return requests.RollupDimension{Histogram: &requests.RollupHistogram{
+			SourceField: dimension.Histogram.SourceField,
+			Interval:    json.Number(dimension.Histogram.Interval),
+		}}
+	}
+	return requests.RollupDimension{}
+}
+
+// RollupImmutableChanges returns the sorted spec fields of the desired rollup job that differ from the existing job
+// and cannot be updated
+func RollupImmutableChanges(desired requests.IndexRollup, existing *apiextensionsv1.JSON) ([]string, error) {
+	return jsonFieldChanges(desired, existing, rollupImmutableFields)
+}
+
+// jsonFieldChanges returns the sorted names the passed fields are mapped to for the fields of desired that are not a
+// JSONSubset of the same field of existing. Fields desired leaves out keep the value OpenSearch defaulted them to
+func jsonFieldChanges(desired interface{}, existing *apiextensionsv1.JSON, fields map[string]string) ([]string, error) {
+	raw, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+	desiredFields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &desiredFields); err != nil {
+		return nil, err
+	}
+	existingFields := map[string]interface{}{}
+	if existing.Size() > 0 {
+		if err := json.Unmarshal(existing.Raw, &existingFields); err != nil {
+			return nil, err
+		}
+	}
+
+	var changed []string
+	for field, name := range fields {
+		desiredField, ok := desiredFields[field]
+		if ok && !JSONSubset(desiredField, existingFields[field]) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// RollupJobUpdate returns the job to update the existing rollup job with, nil if the existing job holds everything
+// of the desired job, and whether the existing job is enabled. The returned job keeps the enabled flag and the start
+// time of the existing job, a changed enabled flag is applied by starting or stopping the job instead
+func RollupJobUpdate(desired requests.IndexRollup, existing *apiextensionsv1.JSON) (*requests.IndexRollup, bool, error) {
+	enabled, startTime, err := scheduledJobState(existing)
+	if err != nil {
+		return nil, false, err
+	}
+
+	update := desired
+	update.Enabled = enabled
+	update.Schedule = withStartTime(desired.Schedule, startTime)
+
+	equal, err := isJSONSubset(update, existing)
+	if err != nil || equal {
+		return nil, enabled, err
+	}
+	return &update, enabled, nil
+}
+
+// scheduledJobState returns whether the existing rollup or transform job is enabled and the start time of its
+// interval schedule
+func scheduledJobState(existing *apiextensionsv1.JSON) (bool, *int64, error) {
+	current := struct {
+		Enabled  bool `json:"enabled"`
+		Schedule struct {
+			Interval *struct {
+				StartTime *int64 `json:"start_time"`
+			} `json:"interval"`
+		} `json:"schedule"`
+	}{}
+	if existing.Size() > 0 {
+		if err := json.Unmarshal(existing.Raw, &current); err != nil {
+			return false, nil, err
+		}
+	}
+	if current.Schedule.Interval == nil {
+		return current.Enabled, nil, nil
+	}
+	return current.Enabled, current.Schedule.Interval.StartTime, nil
+}
+
+// withStartTime returns a copy of the schedule whose interval starts at the passed time
+func withStartTime(schedule requests.RollupSchedule, startTime *int64) requests.RollupSchedule {
+	if schedule.Interval != nil {
+		interval := *schedule.Interval
+		interval.StartTime = startTime
+		schedule.Interval = &interval
+	}
+	return schedule
+}
@@ -0,0 +1,174 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+func rollupJobSpec() v1.OpensearchRollupJobSpec {
+	return v1.OpensearchRollupJobSpec{
+		Schedule:    v1.RollupSchedule{Interval: &v1.RollupInterval{Period: 1, Unit: "Hours"}},
+		SourceIndex: "logs-*",
+		TargetIndex: "rollup-logs",
+		Delay:       "10m",
+		Dimensions: []v1.RollupDimension{
+			{DateHistogram: &v1.RollupDateHistogram{SourceField: "@timestamp", FixedInterval: "1h"}},
+			{Terms: &v1.RollupTerms{SourceField: "service"}},
+			{Histogram: &v1.RollupHistogram{SourceField: "latency", Interval: "0.5"}},
+		},
+		Metrics: []v1.RollupMetric{{SourceField: "bytes", Metrics: []v1.RollupMetricType{"sum", "avg"}}},
+	}
+}
+
+var _ = DescribeTable("rollup job validation",
+	func(modify func(spec *v1.OpensearchRollupJobSpec), expected string) {
+		spec := rollupJobSpec()
+		modify(&spec)
+		err := ValidateRollupJob(spec)
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When the job is complete", func(spec *v1.OpensearchRollupJobSpec) {}, ""),
+	Entry("When both schedules are set", func(spec *v1.OpensearchRollupJobSpec) {
+		spec.Schedule.Cron = &v1.CronSchedule{Expression: "0 * * * *"}
+	}, "exactly one of interval and cron has to be set in the schedule"),
+	Entry("When the target index is a pattern", func(spec *v1.OpensearchRollupJobSpec) {
+		spec.TargetIndex = "rollup-*"
+	}, `target index "rollup-*" must not contain *`),
+	Entry("When the source index is the target index", func(spec *v1.OpensearchRollupJobSpec) {
+		spec.SourceIndex = "rollup-logs"
+	}, "source and target index have to differ"),
+	Entry("When the delay is not a time value", func(spec *v1.OpensearchRollupJobSpec) {
+		spec.Delay = "soon"
+	}, `delay "soon" is not a time value`),
+	Entry("When the first dimension is not a date histogram", func(spec *v1.OpensearchRollupJobSpec) {
+		spec.Dimensions = spec.Dimensions[1:]
+	}, "the first dimension has to be a date histogram"),
+	Entry("When a dimension sets two types", func(spec *v1.OpensearchRollupJobSpec) {
+		spec.Dimensions[1].Histogram = &v1.RollupHistogram{SourceField: "size", Interval: "10"}
+	}, "dimension 1 has to set exactly one of dateHistogram, terms and histogram, got 2"),
+	Entry("When a date histogram sets both intervals", func(spec *v1.OpensearchRollupJobSpec) {
+		spec.Dimensions[0].DateHistogram.CalendarInterval = "1d"
+	}, "date histogram of @timestamp has to set exactly one of fixedInterval and calendarInterval"),
+	Entry("When a histogram interval is not positive", func(spec *v1.OpensearchRollupJobSpec) {
+		spec.Dimensions[2].Histogram.Interval = "-1"
+	}, `histogram interval "-1" of latency is not a positive number`),
+)
+
+var _ = Describe("rollup job request bodies", func() {
+	It("should translate the spec", func() {
+		request, err := TranslateRollupJobToRequest(rollupJobSpec())
+		Expect(err).ToNot(HaveOccurred())
+		raw, err := json.Marshal(requests.RollupJob{Rollup: request})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raw).To(MatchJSON(`{"rollup": {
+			"enabled": true,
+			"schedule": {"interval": {"period": 1, "unit": "Hours"}},
+			"source_index": "logs-*",
+			"target_index": "rollup-logs",
+			"page_size": 1000,
+			"delay": 600000,
+			"continuous": false,
+			"dimensions": [
+				{"date_histogram": {"source_field": "@timestamp", "fixed_interval": "1h", "timezone": "UTC"}},
+				{"terms": {"source_field": "service"}},
+				{"histogram": {"source_field": "latency", "interval": 0.5}}
+			],
+			"metrics": [{"source_field": "bytes", "metrics": [{"sum": {}}, {"avg": {}}]}]
+		}}`))
+	})
+
+	It("should translate a cron schedule", func() {
+		spec := rollupJobSpec()
+		spec.Schedule = v1.RollupSchedule{Cron: &v1.CronSchedule{Expression: "0 2 * * *"}}
+		spec.Enabled = pointer.Bool(false)
+		request, err := TranslateRollupJobToRequest(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(request.Enabled).To(BeFalse())
+		Expect(request.Schedule).To(Equal(requests.RollupSchedule{Cron: &requests.CronSchedule{Expression: "0 2 * * *", Timezone: "UTC"}}))
+	})
+})
+
+var _ = Describe("rollup job updates", func() {
+	// existing is the job as OpenSearch returns it for rollupJobSpec, with the fields it adds
+	existing := func(enabled bool) *apiextensionsv1.JSON {
+		raw, _ := json.Marshal(map[string]interface{}{
+			"rollup_id":      "logs",
+			"enabled":        enabled,
+			"schema_version": 17,
+			"schedule":       map[string]interface{}{"interval": map[string]interface{}{"start_time": 1700000000000, "period": 1, "unit": "Hours"}},
+			"source_index":   "logs-*",
+			"target_index":   "rollup-logs",
+			"page_size":      1000,
+			"delay":          600000,
+			"continuous":     false,
+			"dimensions": []interface{}{
+				map[string]interface{}{"date_histogram": map[string]interface{}{
+					"source_field": "@timestamp", "target_field": "@timestamp", "fixed_interval": "1h", "timezone": "UTC",
+				}},
+				map[string]interface{}{"terms": map[string]interface{}{"source_field": "service", "target_field": "service"}},
+				map[string]interface{}{"histogram": map[string]interface{}{"source_field": "latency", "target_field": "latency", "interval": 0.5}},
+			},
+			"metrics": []interface{}{map[string]interface{}{
+				"source_field": "bytes", "target_field": "bytes", "metrics": []interface{}{map[string]interface{}{"sum": map[string]interface{}{}}, map[string]interface{}{"avg": map[string]interface{}{}}},
+			}},
+		})
+		return &apiextensionsv1.JSON{Raw: raw}
+	}
+
+	It("should not update a job in sync", func() {
+		desired, err := TranslateRollupJobToRequest(rollupJobSpec())
+		Expect(err).ToNot(HaveOccurred())
+		immutable, err := RollupImmutableChanges(desired, existing(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(immutable).To(BeEmpty())
+		update, enabled, err := RollupJobUpdate(desired, existing(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(update).To(BeNil())
+		Expect(enabled).To(BeTrue())
+	})
+
+	It("should keep the enabled flag and start time of the existing job", func() {
+		spec := rollupJobSpec()
+		spec.PageSize = 500
+		desired, err := TranslateRollupJobToRequest(spec)
+		Expect(err).ToNot(HaveOccurred())
+		update, enabled, err := RollupJobUpdate(desired, existing(false))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(enabled).To(BeFalse())
+		Expect(update).ToNot(BeNil())
+		Expect(update.Enabled).To(BeFalse())
+		Expect(update.PageSize).To(Equal(500))
+		Expect(update.Schedule.Interval.StartTime).To(Equal(pointer.Int64(1700000000000)))
+		Expect(desired.Schedule.Interval.StartTime).To(BeNil())
+	})
+
+	It("should only report a changed enabled flag", func() {
+		spec := rollupJobSpec()
+		spec.Enabled = pointer.Bool(false)
+		desired, err := TranslateRollupJobToRequest(spec)
+		Expect(err).ToNot(HaveOccurred())
+		update, enabled, err := RollupJobUpdate(desired, existing(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(update).To(BeNil())
+		Expect(enabled).To(BeTrue())
+	})
+
+	It("should name the fields that cannot be changed", func() {
+		spec := rollupJobSpec()
+		spec.TargetIndex = "rollup-logs-v2"
+		spec.Metrics[0].Metrics = []v1.RollupMetricType{"max"}
+		desired, err := TranslateRollupJobToRequest(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(RollupImmutableChanges(desired, existing(true))).To(Equal([]string{"metrics", "targetIndex"}))
+	})
+})
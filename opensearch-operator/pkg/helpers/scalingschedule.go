@@ -0,0 +1,116 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// scheduleLocation returns the location of the time zone of the schedule, UTC if none is set
+func scheduleLocation(schedule opsterv1.CronSchedule) (*time.Location, error) {
+	if schedule.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(schedule.Timezone)
+}
+
+// ValidateScalingSchedules returns an error naming every problem of the scaling schedules of the node pool
+func ValidateScalingSchedules(nodePool *opsterv1.NodePool) error {
+	var invalid []string
+	names := map[string]bool{}
+	for _, schedule := range nodePool.ScalingSchedules {
+		if names[schedule.Name] {
+			invalid = append(invalid, fmt.Sprintf("schedule %s is defined more than once", schedule.Name))
+		}
+		names[schedule.Name] = true
+		if _, err := parseCronExpression(schedule.Schedule.Expression); err != nil {
+			invalid = append(invalid, fmt.Sprintf("schedule %s: %s", schedule.Name, err))
+		}
+		if _, err := scheduleLocation(schedule.Schedule); err != nil {
+			invalid = append(invalid, fmt.Sprintf("schedule %s: invalid timezone %q", schedule.Name, schedule.Schedule.Timezone))
+		}
+		if schedule.Replicas < 1 {
+			invalid = append(invalid, fmt.Sprintf("schedule %s: replicas has to be at least 1", schedule.Name))
+		}
+		if nodePool.Autoscaling != nil && schedule.Replicas > nodePool.Autoscaling.MaxReplicas {
+			invalid = append(invalid, fmt.Sprintf("schedule %s: replicas has to be at most the maxReplicas of the autoscaling", schedule.Name))
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid scaling schedules of node pool %s: %s", nodePool.Component, strings.Join(invalid, "; "))
+}
+
+// FindActiveScalingSchedule returns the scaling schedule of the node pool that fired last at or before now. If several
+// schedules fired at the same time the first one wins. false is returned if the pool has no valid schedules or none of
+// them fired within the last year
+func FindActiveScalingSchedule(nodePool *opsterv1.NodePool, now time.Time) (opsterv1.ActiveScalingSchedule, bool) {
+	active := opsterv1.ActiveScalingSchedule{}
+	found := false
+	if ValidateScalingSchedules(nodePool) != nil {
+		return active, false
+	}
+	for _, schedule := range nodePool.ScalingSchedules {
+		cron, _ := parseCronExpression(schedule.Schedule.Expression)
+		loc, _ := scheduleLocation(schedule.Schedule)
+		fired, ok := cron.previous(now.In(loc))
+		if !ok || (found && !fired.After(active.Since.Time)) {
+			continue
+		}
+		active = opsterv1.ActiveScalingSchedule{
+			Component: nodePool.Component,
+			Name:      schedule.Name,
+			Replicas:  schedule.Replicas,
+			Since:     metav1.NewTime(fired.UTC()),
+		}
+		found = true
+	}
+	return active, found
+}
+
+// ScheduledNodePool returns a copy of the node pool with the replicas of its active scaling schedule applied. With
+// autoscaling the replicas of the schedule raise the minimum replicas of the autoscaler
+func ScheduledNodePool(nodePool opsterv1.NodePool, now time.Time) opsterv1.NodePool {
+	active, found := FindActiveScalingSchedule(&nodePool, now)
+	if !found {
+		return nodePool
+	}
+	if nodePool.Autoscaling == nil {
+		nodePool.Replicas = active.Replicas
+		return nodePool
+	}
+	autoscaling := *nodePool.Autoscaling
+	if active.Replicas > autoscaling.MinReplicas {
+		autoscaling.MinReplicas = active.Replicas
+	}
+	nodePool.Autoscaling = &autoscaling
+	return nodePool
+}
+
+// FindScalingScheduleStatus returns the active scaling schedule in the status of the node pool with the passed name
+func FindScalingScheduleStatus(status opsterv1.ClusterStatus, component string) (opsterv1.ActiveScalingSchedule, bool) {
+	for _, active := range status.ScalingSchedules {
+		if active.Component == component {
+			return active, true
+		}
+	}
+	return opsterv1.ActiveScalingSchedule{}, false
+}
+
+// SetScalingScheduleStatus returns the list with the active schedule of its node pool replaced by or extended with the
+// passed one
+func SetScalingScheduleStatus(statuses []opsterv1.ActiveScalingSchedule, active opsterv1.ActiveScalingSchedule) []opsterv1.ActiveScalingSchedule {
+	result := []opsterv1.ActiveScalingSchedule{}
+	for _, existing := range statuses {
+		if existing.Component != active.Component {
+			result = append(result, existing)
+		}
+	}
+	return append(result, active)
+}
@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("scaling schedules", func() {
+	nodePool := func() opsterv1.NodePool {
+		return opsterv1.NodePool{
+			Component: "data",
+			Replicas:  3,
+			ScalingSchedules: []opsterv1.ScalingSchedule{
+				{Name: "business-hours", Schedule: opsterv1.CronSchedule{Expression: "0 8 * * MON-FRI", Timezone: "Europe/Berlin"}, Replicas: 6},
+				{Name: "night", Schedule: opsterv1.CronSchedule{Expression: "0 20 * * MON-FRI", Timezone: "Europe/Berlin"}, Replicas: 2},
+			},
+		}
+	}
+	// 2024-03-13 is a Wednesday, Berlin is at UTC+1
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		Expect(err).ToNot(HaveOccurred())
+		return parsed
+	}
+
+	It("accepts valid schedules", func() {
+		pool := nodePool()
+		Expect(ValidateScalingSchedules(&pool)).To(Succeed())
+	})
+
+	It("reports every invalid schedule", func() {
+		pool := nodePool()
+		pool.ScalingSchedules[0].Schedule.Expression = "0 25 * * *"
+		pool.ScalingSchedules[1].Name = "business-hours"
+		pool.ScalingSchedules[1].Schedule.Timezone = "Mars/Olympus"
+		err := ValidateScalingSchedules(&pool)
+		Expect(err).To(MatchError(ContainSubstring("schedule business-hours is defined more than once")))
+		Expect(err).To(MatchError(ContainSubstring(`invalid value "25"`)))
+		Expect(err).To(MatchError(ContainSubstring(`invalid timezone "Mars/Olympus"`)))
+	})
+
+	It("rejects replicas above the max replicas of the autoscaling", func() {
+		pool := nodePool()
+		pool.Autoscaling = &opsterv1.NodePoolAutoscaling{MinReplicas: 1, MaxReplicas: 4, TargetCPUUtilization: pointer.Int32(70)}
+		Expect(ValidateScalingSchedules(&pool)).To(MatchError(ContainSubstring("schedule business-hours: replicas has to be at most the maxReplicas")))
+	})
+
+	It("uses the schedule that fired last", func() {
+		pool := nodePool()
+		active, found := FindActiveScalingSchedule(&pool, at("2024-03-13T10:00:00Z"))
+		Expect(found).To(BeTrue())
+		Expect(active.Name).To(Equal("business-hours"))
+		Expect(active.Since.Time).To(BeTemporally("==", at("2024-03-13T07:00:00Z")))
+		Expect(ScheduledNodePool(pool, at("2024-03-13T10:00:00Z")).Replicas).To(Equal(int32(6)))
+
+		active, found = FindActiveScalingSchedule(&pool, at("2024-03-16T10:00:00Z"))
+		Expect(found).To(BeTrue())
+		Expect(active.Name).To(Equal("night"))
+		Expect(ScheduledNodePool(pool, at("2024-03-16T10:00:00Z")).Replicas).To(Equal(int32(2)))
+	})
+
+	It("keeps the replicas without an active schedule", func() {
+		pool := nodePool()
+		pool.ScalingSchedules = pool.ScalingSchedules[:1]
+		pool.ScalingSchedules[0].Schedule.Expression = "0 0 30 2 *"
+		_, found := FindActiveScalingSchedule(&pool, at("2024-03-13T10:00:00Z"))
+		Expect(found).To(BeFalse())
+		Expect(ScheduledNodePool(pool, at("2024-03-13T10:00:00Z")).Replicas).To(Equal(int32(3)))
+	})
+
+	It("raises the min replicas of the autoscaling", func() {
+		pool := nodePool()
+		pool.Autoscaling = &opsterv1.NodePoolAutoscaling{MinReplicas: 3, MaxReplicas: 8, TargetCPUUtilization: pointer.Int32(70)}
+
+		scheduled := ScheduledNodePool(pool, at("2024-03-13T10:00:00Z"))
+		Expect(scheduled.Autoscaling.MinReplicas).To(Equal(int32(6)))
+		Expect(pool.Autoscaling.MinReplicas).To(Equal(int32(3)))
+
+		scheduled = ScheduledNodePool(pool, at("2024-03-13T22:00:00Z"))
+		Expect(scheduled.Autoscaling.MinReplicas).To(Equal(int32(3)))
+		Expect(MaxReplicas(&pool)).To(Equal(int32(8)))
+	})
+
+	It("replaces the status of the node pool", func() {
+		statuses := SetScalingScheduleStatus(nil, opsterv1.ActiveScalingSchedule{Component: "data", Name: "night"})
+		statuses = SetScalingScheduleStatus(statuses, opsterv1.ActiveScalingSchedule{Component: "masters", Name: "night"})
+		statuses = SetScalingScheduleStatus(statuses, opsterv1.ActiveScalingSchedule{Component: "data", Name: "business-hours"})
+		active, found := FindScalingScheduleStatus(opsterv1.ClusterStatus{ScalingSchedules: statuses}, "data")
+		Expect(found).To(BeTrue())
+		Expect(active.Name).To(Equal("business-hours"))
+		Expect(statuses).To(HaveLen(2))
+	})
+})
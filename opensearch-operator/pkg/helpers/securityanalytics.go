@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+// TranslateSecurityAnalyticsDetectorToRequest validates the spec and rewrites the CRD format to the gateway format.
+// customRuleIDs are the ids OpenSearch generated for the custom rules of the spec. The error names every problem of
+// the spec OpenSearch would reject
+func TranslateSecurityAnalyticsDetectorToRequest(spec v1.OpensearchSecurityAnalyticsDetectorSpec, name string, customRuleIDs []string) (requests.SecurityAnalyticsDetector, error) {
+	var invalid []string
+
+	request := requests.SecurityAnalyticsDetector{
+		Type:         "detector",
+		Name:         name,
+		DetectorType: spec.DetectorType,
+		Enabled:      pointer.BoolDeref(spec.Enabled, true),
+		Triggers:     []apiextensionsv1.JSON{},
+	}
+
+	for _, index := range spec.Indices {
+		if err := ValidateIndexPattern(index); err != nil {
+			invalid = append(invalid, err.Error())
+		}
+	}
+
+	schedule := spec.Schedule
+	if schedule == "" {
+		schedule = "1m"
+	}
+	if minutes, ok := detectorMinutes(schedule); ok && minutes > 0 {
+		request.Schedule = requests.DetectorInterval{Period: requests.DetectorPeriod{Interval: minutes, Unit: "MINUTES"}}
+	} else {
+		invalid = append(invalid, fmt.Sprintf("schedule %q has to be a positive whole number of minutes, e.g. 5m", schedule))
+	}
+
+	input := requests.SecurityAnalyticsInput{
+		Description:      spec.Description,
+		Indices:          spec.Indices,
+		CustomRules:      []requests.SecurityAnalyticsRuleID{},
+		PrePackagedRules: []requests.SecurityAnalyticsRuleID{},
+	}
+	ruleIDs := map[string]bool{}
+	for _, id := range spec.PrePackagedRules {
+		if ruleIDs[id] {
+			invalid = append(invalid, fmt.Sprintf("prepackaged rule %s is listed more than once", id))
+		}
+		ruleIDs[id] = true
+		input.PrePackagedRules = append(input.PrePackagedRules, requests.SecurityAnalyticsRuleID{ID: id})
+	}
+	customRules := map[string]bool{}
+	for _, rule := range spec.CustomRules {
+		if customRules[rule.Name] {
+			invalid = append(invalid, fmt.Sprintf("custom rule %s is listed more than once", rule.Name))
+		}
+		customRules[rule.Name] = true
+	}
+	for _, id := range customRuleIDs {
+		input.CustomRules = append(input.CustomRules, requests.SecurityAnalyticsRuleID{ID: id})
+	}
+	request.Inputs = []requests.SecurityAnalyticsDetectorInput{{DetectorInput: input}}
+
+	for i, trigger := range spec.Triggers {
+		var object map[string]interface{}
+		if err := json.Unmarshal(trigger.Raw, &object); err != nil {
+			invalid = append(invalid, fmt.Sprintf("trigger %d has to be an object", i))
+			continue
+		}
+		request.Triggers = append(request.Triggers, trigger)
+	}
+
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return requests.SecurityAnalyticsDetector{}, fmt.Errorf("invalid detector: %s", strings.Join(invalid, "; "))
+	}
+	return request, nil
+}
+
+// SecurityAnalyticsDetectorsEqual returns true if the threat detector in OpenSearch holds everything of the desired
+// detector. OpenSearch adds fields to detectors, such as the ids of their monitors, which are ignored
+func SecurityAnalyticsDetectorsEqual(desired requests.SecurityAnalyticsDetector, existing *apiextensionsv1.JSON) (bool, error) {
+	return isJSONSubset(desired, existing)
+}
+
+// SecurityAnalyticsDetectorEnabled returns whether the threat detector in OpenSearch is enabled
+func SecurityAnalyticsDetectorEnabled(existing *apiextensionsv1.JSON) (bool, error) {
+	detector := struct {
+		Enabled bool `json:"enabled"`
+	}{}
+	if err := json.Unmarshal(existing.Raw, &detector); err != nil {
+		return false, err
+	}
+	return detector.Enabled, nil
+}
+
+// SecurityAnalyticsRulesEqual returns true if the custom rule in OpenSearch has the category and the Sigma YAML of
+// the spec. Whitespace around the YAML is ignored, OpenSearch may store the rule without the trailing newline
+func SecurityAnalyticsRulesEqual(spec v1.OpensearchSecurityAnalyticsRuleSpec, category string, rule string) bool {
+	return spec.Category == category && strings.TrimSpace(spec.Rule) == strings.TrimSpace(rule)
+}
@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+func securityAnalyticsDetectorSpec() v1.OpensearchSecurityAnalyticsDetectorSpec {
+	return v1.OpensearchSecurityAnalyticsDetectorSpec{
+		DetectorType:     "windows",
+		Indices:          []string{"windows-*"},
+		PrePackagedRules: []string{"06724b9a-52fc-11ed-bdc3-0242ac120002"},
+		CustomRules:      []corev1.LocalObjectReference{{Name: "suspicious-login"}},
+		Triggers:         []apiextensionsv1.JSON{{Raw: []byte(`{"name":"high","severity":"1"}`)}},
+	}
+}
+
+var _ = DescribeTable("security analytics detector validation",
+	func(modify func(spec *v1.OpensearchSecurityAnalyticsDetectorSpec), expected string) {
+		spec := securityAnalyticsDetectorSpec()
+		modify(&spec)
+		_, err := TranslateSecurityAnalyticsDetectorToRequest(spec, "windows-detector", []string{"rule-id"})
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When the detector is complete", func(spec *v1.OpensearchSecurityAnalyticsDetectorSpec) {}, ""),
+	Entry("When the schedule is not whole minutes", func(spec *v1.OpensearchSecurityAnalyticsDetectorSpec) {
+		spec.Schedule = "30s"
+	}, `schedule "30s" has to be a positive whole number of minutes`),
+	Entry("When a prepackaged rule is listed twice", func(spec *v1.OpensearchSecurityAnalyticsDetectorSpec) {
+		spec.PrePackagedRules = append(spec.PrePackagedRules, spec.PrePackagedRules[0])
+	}, "prepackaged rule 06724b9a-52fc-11ed-bdc3-0242ac120002 is listed more than once"),
+	Entry("When a custom rule is listed twice", func(spec *v1.OpensearchSecurityAnalyticsDetectorSpec) {
+		spec.CustomRules = append(spec.CustomRules, spec.CustomRules[0])
+	}, "custom rule suspicious-login is listed more than once"),
+	Entry("When a trigger is not an object", func(spec *v1.OpensearchSecurityAnalyticsDetectorSpec) {
+		spec.Triggers = []apiextensionsv1.JSON{{Raw: []byte(`["high"]`)}}
+	}, "trigger 0 has to be an object"),
+)
+
+var _ = Describe("security analytics request bodies", func() {
+	It("should translate the spec", func() {
+		spec := securityAnalyticsDetectorSpec()
+		spec.Enabled = pointer.Bool(false)
+		request, err := TranslateSecurityAnalyticsDetectorToRequest(spec, "windows-detector", []string{"rule-id"})
+		Expect(err).ToNot(HaveOccurred())
+		body, err := json.Marshal(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(MatchJSON(`{
+			"type": "detector",
+			"name": "windows-detector",
+			"detector_type": "windows",
+			"enabled": false,
+			"schedule": {"period": {"interval": 1, "unit": "MINUTES"}},
+			"inputs": [{"detector_input": {
+				"description": "",
+				"indices": ["windows-*"],
+				"custom_rules": [{"id": "rule-id"}],
+				"pre_packaged_rules": [{"id": "06724b9a-52fc-11ed-bdc3-0242ac120002"}]
+			}}],
+			"triggers": [{"name": "high", "severity": "1"}]
+		}`))
+	})
+
+	It("should ignore the fields OpenSearch adds to detectors", func() {
+		request, err := TranslateSecurityAnalyticsDetectorToRequest(securityAnalyticsDetectorSpec(), "windows-detector", []string{"rule-id"})
+		Expect(err).ToNot(HaveOccurred())
+		body, _ := json.Marshal(request)
+		var existing map[string]interface{}
+		Expect(json.Unmarshal(body, &existing)).To(Succeed())
+		existing["monitor_id"] = []string{"monitor"}
+		existing["last_update_time"] = 1700000000000
+		raw, _ := json.Marshal(existing)
+		equal, err := SecurityAnalyticsDetectorsEqual(request, &apiextensionsv1.JSON{Raw: raw})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(equal).To(BeTrue())
+
+		request.Enabled = false
+		equal, err = SecurityAnalyticsDetectorsEqual(request, &apiextensionsv1.JSON{Raw: raw})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(equal).To(BeFalse())
+	})
+
+	It("should compare rules without surrounding whitespace", func() {
+		spec := v1.OpensearchSecurityAnalyticsRuleSpec{Category: "windows", Rule: "title: test\n"}
+		Expect(SecurityAnalyticsRulesEqual(spec, "windows", "title: test")).To(BeTrue())
+		Expect(SecurityAnalyticsRulesEqual(spec, "linux", "title: test")).To(BeFalse())
+	})
+})
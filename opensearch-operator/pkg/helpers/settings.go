@@ -0,0 +1,217 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// knownIndexSettings are the index settings of OpenSearch itself, settings of the groups in indexSettingGroups are
+// not listed
+var knownIndexSettings = map[string]bool{
+	"index.number_of_shards":                                            true,
+	"index.number_of_replicas":                                          true,
+	"index.number_of_routing_shards":                                    true,
+	"index.auto_expand_replicas":                                        true,
+	"index.routing_partition_size":                                      true,
+	"index.codec":                                                       true,
+	"index.codec.compression_level":                                     true,
+	"index.hidden":                                                      true,
+	"index.format":                                                      true,
+	"index.priority":                                                    true,
+	"index.refresh_interval":                                            true,
+	"index.gc_deletes":                                                  true,
+	"index.default_pipeline":                                            true,
+	"index.final_pipeline":                                              true,
+	"index.load_fixed_bitset_filters_eagerly":                           true,
+	"index.shard.check_on_startup":                                      true,
+	"index.flush_after_merge":                                           true,
+	"index.check_pending_flush.enabled":                                 true,
+	"index.append_only.enabled":                                         true,
+	"index.use_compound_file":                                           true,
+	"index.unassigned.node_left.delayed_timeout":                        true,
+	"index.write.wait_for_active_shards":                                true,
+	"index.requests.cache.enable":                                       true,
+	"index.queries.cache.enabled":                                       true,
+	"index.warmer.enabled":                                              true,
+	"index.max_result_window":                                           true,
+	"index.max_inner_result_window":                                     true,
+	"index.max_rescore_window":                                          true,
+	"index.max_docvalue_fields_search":                                  true,
+	"index.max_script_fields":                                           true,
+	"index.max_ngram_diff":                                              true,
+	"index.max_shingle_diff":                                            true,
+	"index.max_refresh_listeners":                                       true,
+	"index.max_terms_count":                                             true,
+	"index.max_regex_length":                                            true,
+	"index.max_adjacency_matrix_filters":                                true,
+	"index.max_slices_per_scroll":                                       true,
+	"index.max_slices_per_pit":                                          true,
+	"index.analyze.max_token_count":                                     true,
+	"index.highlight.max_analyzed_offset":                               true,
+	"index.blocks.read_only":                                            true,
+	"index.blocks.read_only_allow_delete":                               true,
+	"index.blocks.read":                                                 true,
+	"index.blocks.write":                                                true,
+	"index.blocks.metadata":                                             true,
+	"index.mapping.total_fields.limit":                                  true,
+	"index.mapping.depth.limit":                                         true,
+	"index.mapping.nested_fields.limit":                                 true,
+	"index.mapping.nested_objects.limit":                                true,
+	"index.mapping.field_name_length.limit":                             true,
+	"index.mapping.coerce":                                              true,
+	"index.mapping.ignore_malformed":                                    true,
+	"index.mapper.dynamic":                                              true,
+	"index.query.default_field":                                         true,
+	"index.query.parse.allow_unmapped_fields":                           true,
+	"index.query_string.lenient":                                        true,
+	"index.search.idle.after":                                           true,
+	"index.search.throttled":                                            true,
+	"index.search.default_pipeline":                                     true,
+	"index.search.concurrent_segment_search.enabled":                    true,
+	"index.sort.field":                                                  true,
+	"index.sort.order":                                                  true,
+	"index.sort.mode":                                                   true,
+	"index.sort.missing":                                                true,
+	"index.soft_deletes.enabled":                                        true,
+	"index.soft_deletes.retention.operations":                           true,
+	"index.soft_deletes.retention_lease.period":                         true,
+	"index.translog.durability":                                         true,
+	"index.translog.sync_interval":                                      true,
+	"index.translog.flush_threshold_size":                               true,
+	"index.translog.generation_threshold_size":                          true,
+	"index.translog.retention.size":                                     true,
+	"index.translog.retention.age":                                      true,
+	"index.routing.allocation.enable":                                   true,
+	"index.routing.allocation.total_shards_per_node":                    true,
+	"index.routing.allocation.total_primary_shards_per_node":            true,
+	"index.routing.rebalance.enable":                                    true,
+	"index.allocation.max_retries":                                      true,
+	"index.replication.type":                                            true,
+	"index.doc_id_fuzzy_set.enabled":                                    true,
+	"index.doc_id_fuzzy_set.false_positive_probability":                 true,
+	"index.optimize_doc_id_lookup.fuzzy_set.enabled":                    true,
+	"index.optimize_doc_id_lookup.fuzzy_set.false_positive_probability": true,
+	"index.merge.on_flush.enabled":                                      true,
+	"index.merge.on_flush.max_full_flush_merge_wait_time":               true,
+	"index.merge.on_flush.policy":                                       true,
+}
+
+// indexSettingGroups are the prefixes of index settings whose keys are defined by the user, e.g. the names of
+// analyzers, or by plugins
+var indexSettingGroups = []string{
+	"index.analysis.",
+	"index.similarity.",
+	"index.routing.allocation.require.",
+	"index.routing.allocation.include.",
+	"index.routing.allocation.exclude.",
+	"index.merge.policy.",
+	"index.merge.scheduler.",
+	"index.store.",
+	"index.search.slowlog.",
+	"index.indexing.slowlog.",
+	"index.remote_store.",
+	"index.composite_index",
+	"index.plugins.",
+	"index.opendistro.",
+	"index.knn",
+}
+
+// UnknownSetting is an index setting that is not known to be an OpenSearch index setting
+type UnknownSetting struct {
+	// Key of the setting in flat notation, with the index. prefix
+	Key string
+	// Known setting the key is probably a misspelling of, empty if there is none
+	Suggestion string
+}
+
+func (s UnknownSetting) String() string {
+	if s.Suggestion == "" {
+		return s.Key
+	}
+	return fmt.Sprintf("%s (did you mean %s?)", s.Key, s.Suggestion)
+}
+
+// UnknownIndexSettings returns the settings that are neither known index settings of OpenSearch nor part of a
+// group of user or plugin defined settings, sorted by key
+func UnknownIndexSettings(settings *apiextensionsv1.JSON) ([]UnknownSetting, error) {
+	if settings.Size() == 0 {
+		return nil, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+
+	var unknown []UnknownSetting
+	for key := range flat {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		if knownIndexSetting(key) {
+			continue
+		}
+		unknown = append(unknown, UnknownSetting{Key: key, Suggestion: suggestIndexSetting(key)})
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Key < unknown[j].Key })
+	return unknown, nil
+}
+
+func knownIndexSetting(key string) bool {
+	if knownIndexSettings[key] {
+		return true
+	}
+	for _, group := range indexSettingGroups {
+		if strings.HasPrefix(key, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestIndexSetting returns the known setting closest to the key if it differs by at most two edits
+func suggestIndexSetting(key string) string {
+	suggestion := ""
+	best := 3
+	for known := range knownIndexSettings {
+		if distance := editDistance(key, known); distance < best || (distance == best && known < suggestion) {
+			suggestion, best = known, distance
+		}
+	}
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance of a and b
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("unknown index settings",
+	func(settings string, expected []string) {
+		unknown, err := UnknownIndexSettings(&apiextensionsv1.JSON{Raw: []byte(settings)})
+		Expect(err).ToNot(HaveOccurred())
+		var keys []string
+		for _, setting := range unknown {
+			keys = append(keys, setting.String())
+		}
+		Expect(keys).To(Equal(expected))
+	},
+	Entry("When all settings are known", `{"index":{"number_of_shards":"1","mapping":{"total_fields":{"limit":2000}}}}`, nil),
+	Entry("When settings lack the index prefix", `{"number_of_replicas":1,"refresh_interval":"1s"}`, nil),
+	Entry("When settings are user or plugin defined",
+		`{"index":{"analysis":{"analyzer":{"my_analyzer":{"type":"custom"}}},"routing.allocation.require.temp":"hot","knn":true,"plugins.index_state_management.rollover_alias":"logs"}}`, nil),
+	Entry("When a setting is misspelled", `{"index":{"numbr_of_shards":"1"}}`,
+		[]string{"index.numbr_of_shards (did you mean index.number_of_shards?)"}),
+	Entry("When a nested setting is misspelled", `{"index.mapping.total_feilds.limit":2000}`,
+		[]string{"index.mapping.total_feilds.limit (did you mean index.mapping.total_fields.limit?)"}),
+	Entry("When a setting is unknown", `{"index":{"refresh_interval":"1s","compression":"lz4","my_setting":true}}`,
+		[]string{"index.compression", "index.my_setting"}),
+)
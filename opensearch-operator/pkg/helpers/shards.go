@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// NumberOfShards returns the index.number_of_shards the index settings set, empty if they do not set it. Numbers and
+// strings are returned alike, as OpenSearch returns the settings it was given as numbers as strings.
+func NumberOfShards(settings *apiextensionsv1.JSON) (string, error) {
+	if settings.Size() == 0 {
+		return "", nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return "", err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+	return numericSetting(flat, "number_of_shards")
+}
+
+// ValidateRoutingShards returns an error if the index settings set an index.number_of_routing_shards OpenSearch
+// would reject when creating an index. The routing shards must be a multiple of index.number_of_shards, 1 if the
+// settings do not set it, as the shards of an index can only be split into a factor of them.
+func ValidateRoutingShards(settings *apiextensionsv1.JSON) error {
+	if settings.Size() == 0 {
+		return nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+
+	routingValue, err := numericSetting(flat, "number_of_routing_shards")
+	if err != nil || routingValue == "" {
+		return err
+	}
+	routing, err := strconv.Atoi(routingValue)
+	if err != nil || routing < 1 {
+		return fmt.Errorf("invalid index.number_of_routing_shards %q, must be a positive integer", routingValue)
+	}
+	shardsValue, err := numericSetting(flat, "number_of_shards")
+	if err != nil {
+		return err
+	}
+	shards := 1
+	if shardsValue != "" {
+		shards, err = strconv.Atoi(shardsValue)
+		if err != nil || shards < 1 {
+			return fmt.Errorf("invalid index.number_of_shards %q, must be a positive integer", shardsValue)
+		}
+	}
+
+	if routing < shards {
+		return fmt.Errorf("index.number_of_routing_shards %d must not be less than index.number_of_shards %d", routing, shards)
+	}
+	if routing%shards != 0 {
+		lower := routing / shards * shards
+		return fmt.Errorf("index.number_of_routing_shards %d is not a multiple of index.number_of_shards %d, "+
+			"the closest valid values are %d and %d", routing, shards, lower, lower+shards)
+	}
+	return nil
+}
+
+// numericSetting returns the index setting of the flat settings with or without index prefix, empty if they do not
+// set it. Numbers and strings are returned alike.
+func numericSetting(flat map[string]interface{}, name string) (string, error) {
+	for _, key := range []string{"index." + name, name} {
+		switch value := flat[key].(type) {
+		case nil:
+			continue
+		case json.Number:
+			return value.String(), nil
+		case string:
+			return value, nil
+		default:
+			return "", fmt.Errorf("invalid %s: %v", key, value)
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("number of shards",
+	func(settings string, expected string, valid bool) {
+		var input *apiextensionsv1.JSON
+		if settings != "" {
+			input = &apiextensionsv1.JSON{Raw: []byte(settings)}
+		}
+		shards, err := NumberOfShards(input)
+		if !valid {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shards).To(Equal(expected))
+	},
+	Entry("When the template has no settings", "", "", true),
+	Entry("When the template does not set the shards", `{"index":{"number_of_replicas":"1"}}`, "", true),
+	Entry("When the shards are nested", `{"index":{"number_of_shards":3}}`, "3", true),
+	Entry("When the shards are flat", `{"index.number_of_shards":"3"}`, "3", true),
+	Entry("When the shards have no index prefix", `{"number_of_shards":2}`, "2", true),
+	Entry("When the shards are not a number", `{"index":{"number_of_shards":true}}`, "", false),
+)
+
+var _ = DescribeTable("routing shards",
+	func(settings string, message string) {
+		var input *apiextensionsv1.JSON
+		if settings != "" {
+			input = &apiextensionsv1.JSON{Raw: []byte(settings)}
+		}
+		err := ValidateRoutingShards(input)
+		if message == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(message))
+	},
+	Entry("When the template has no settings", "", ""),
+	Entry("When the template does not set the routing shards", `{"index":{"number_of_shards":3}}`, ""),
+	Entry("When the routing shards are a power of two multiple", `{"index":{"number_of_shards":3,"number_of_routing_shards":24}}`, ""),
+	Entry("When the routing shards are any other multiple", `{"index.number_of_shards":"2","index.number_of_routing_shards":"6"}`, ""),
+	Entry("When the routing shards equal the shards", `{"number_of_shards":5,"number_of_routing_shards":5}`, ""),
+	Entry("When the template does not set the shards", `{"index":{"number_of_routing_shards":7}}`, ""),
+	Entry("When the routing shards are not a multiple", `{"index":{"number_of_shards":3,"number_of_routing_shards":10}}`,
+		"index.number_of_routing_shards 10 is not a multiple of index.number_of_shards 3, the closest valid values are 9 and 12"),
+	Entry("When the routing shards are less than the shards", `{"index":{"number_of_shards":8,"number_of_routing_shards":4}}`,
+		"index.number_of_routing_shards 4 must not be less than index.number_of_shards 8"),
+	Entry("When the routing shards are not a number", `{"index":{"number_of_routing_shards":"many"}}`,
+		`invalid index.number_of_routing_shards "many", must be a positive integer`),
+	Entry("When the routing shards are 0", `{"index":{"number_of_routing_shards":0}}`,
+		`invalid index.number_of_routing_shards "0", must be a positive integer`),
+	Entry("When the shards are not a number", `{"index":{"number_of_shards":"1.5","number_of_routing_shards":3}}`,
+		`invalid index.number_of_shards "1.5", must be a positive integer`),
+)
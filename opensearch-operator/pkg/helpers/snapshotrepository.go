@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+)
+
+// byteSizePattern matches the byte size values OpenSearch accepts, e.g. "1gb" or "512 kb"
+var byteSizePattern = regexp.MustCompile(`^(-1|0|[0-9]+(\.[0-9]+)?\s*(b|kb?|mb?|gb?|tb?|pb?))$`)
+
+// ValidateSnapshotRepository returns an error naming every problem of the snapshot repository spec OpenSearch would
+// reject or silently misinterpret
+func ValidateSnapshotRepository(spec v1.OpensearchSnapshotRepositorySpec) error {
+	var invalid []string
+
+	types := 0
+	for _, set := range []bool{spec.S3 != nil, spec.GCS != nil, spec.Azure != nil, spec.FS != nil} {
+		if set {
+			types++
+		}
+	}
+	if types != 1 {
+		invalid = append(invalid, fmt.Sprintf("exactly one of s3, gcs, azure and fs has to be set, got %d", types))
+	}
+
+	switch {
+	case spec.S3 != nil && spec.S3.Bucket == "":
+		invalid = append(invalid, "s3.bucket is required")
+	case spec.GCS != nil && spec.GCS.Bucket == "":
+		invalid = append(invalid, "gcs.bucket is required")
+	case spec.Azure != nil && spec.Azure.Container == "":
+		invalid = append(invalid, "azure.container is required")
+	case spec.FS != nil && !path.IsAbs(spec.FS.Location):
+		invalid = append(invalid, fmt.Sprintf("fs.location %q has to be an absolute path", spec.FS.Location))
+	}
+
+	for field, value := range map[string]string{
+		"chunkSize":              spec.ChunkSize,
+		"maxSnapshotBytesPerSec": spec.MaxSnapshotBytesPerSec,
+		"maxRestoreBytesPerSec":  spec.MaxRestoreBytesPerSec,
+	} {
+		if value != "" && !byteSizePattern.MatchString(strings.ToLower(strings.TrimSpace(value))) {
+			invalid = append(invalid, fmt.Sprintf("%s %q is not a byte size, e.g. 1gb", field, value))
+		}
+	}
+
+	if len(spec.AdditionalSettings) > 0 {
+		typed := spec.DeepCopy()
+		typed.AdditionalSettings = nil
+		fields := TranslateSnapshotRepositoryToRequest(*typed).Settings
+		for name := range spec.AdditionalSettings {
+			if _, ok := fields[name]; ok {
+				invalid = append(invalid, fmt.Sprintf("additionalSettings repeat the setting %s of a field", name))
+			}
+		}
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid snapshot repository: %s", strings.Join(invalid, "; "))
+}
+
+// SnapshotRepositoryCredentials returns the name of the secret with the credentials of the repository and maps its
+// keys to the keystore keys the repository plugin reads them from. The secret name is empty without credentials
+func SnapshotRepositoryCredentials(spec v1.OpensearchSnapshotRepositorySpec) (string, map[string]string) {
+	client := func(name string) string {
+		if name == "" {
+			return "default"
+		}
+		return name
+	}
+	key := func(key string, defaultKey string) string {
+		if key == "" {
+			return defaultKey
+		}
+		return key
+	}
+
+	switch {
+	case spec.S3 != nil && spec.S3.Credentials != nil:
+		prefix := "s3.client." + client(spec.S3.Client) + "."
+		return spec.S3.Credentials.Secret.Name, map[string]string{
+			key(spec.S3.Credentials.AccessKeyKey, "access_key"): prefix + "access_key",
+			key(spec.S3.Credentials.SecretKeyKey, "secret_key"): prefix + "secret_key",
+		}
+	case spec.GCS != nil && spec.GCS.Credentials != nil:
+		prefix := "gcs.client." + client(spec.GCS.Client) + "."
+		return spec.GCS.Credentials.Secret.Name, map[string]string{
+			key(spec.GCS.Credentials.CredentialsFileKey, "credentials_file"): prefix + "credentials_file",
+		}
+	case spec.Azure != nil && spec.Azure.Credentials != nil:
+		prefix := "azure.client." + client(spec.Azure.Client) + "."
+		return spec.Azure.Credentials.Secret.Name, map[string]string{
+			key(spec.Azure.Credentials.AccountKey, "account"): prefix + "account",
+			key(spec.Azure.Credentials.KeyKey, "key"):         prefix + "key",
+		}
+	}
+	return "", nil
+}
+
+// KeystoreProvides returns true if the keystore of the cluster adds the key of the secret under the keystore key. A
+// keystore value without key mappings adds all keys of its secret under their own name
+func KeystoreProvides(keystore []v1.KeystoreValue, secretName string, secretKey string, keystoreKey string) bool {
+	for _, value := range keystore {
+		if value.Secret.Name != secretName {
+			continue
+		}
+		if len(value.KeyMappings) == 0 {
+			if secretKey == keystoreKey {
+				return true
+			}
+			continue
+		}
+		if value.KeyMappings[secretKey] == keystoreKey {
+			return true
+		}
+	}
+	return false
+}
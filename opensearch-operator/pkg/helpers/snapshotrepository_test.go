@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = DescribeTable("snapshot repository validation",
+	func(spec v1.OpensearchSnapshotRepositorySpec, expected string) {
+		err := ValidateSnapshotRepository(spec)
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When an s3 repository is complete",
+		v1.OpensearchSnapshotRepositorySpec{S3: &v1.S3SnapshotRepository{Bucket: "backups"}, ChunkSize: "1gb"}, ""),
+	Entry("When no type is set", v1.OpensearchSnapshotRepositorySpec{}, "exactly one of s3, gcs, azure and fs has to be set, got 0"),
+	Entry("When two types are set",
+		v1.OpensearchSnapshotRepositorySpec{
+			S3:  &v1.S3SnapshotRepository{Bucket: "backups"},
+			GCS: &v1.GCSSnapshotRepository{Bucket: "backups"},
+		}, "got 2"),
+	Entry("When the bucket is missing", v1.OpensearchSnapshotRepositorySpec{GCS: &v1.GCSSnapshotRepository{}}, "gcs.bucket is required"),
+	Entry("When the container is missing", v1.OpensearchSnapshotRepositorySpec{Azure: &v1.AzureSnapshotRepository{}}, "azure.container is required"),
+	Entry("When the location is relative",
+		v1.OpensearchSnapshotRepositorySpec{FS: &v1.FSSnapshotRepository{Location: "backups"}}, `fs.location "backups" has to be an absolute path`),
+	Entry("When a rate is not a byte size",
+		v1.OpensearchSnapshotRepositorySpec{FS: &v1.FSSnapshotRepository{Location: "/mnt/backups"}, MaxRestoreBytesPerSec: "fast"},
+		`maxRestoreBytesPerSec "fast" is not a byte size`),
+	Entry("When additional settings repeat a field",
+		v1.OpensearchSnapshotRepositorySpec{
+			S3:                 &v1.S3SnapshotRepository{Bucket: "backups"},
+			AdditionalSettings: map[string]string{"bucket": "other", "canned_acl": "private"},
+		}, "additionalSettings repeat the setting bucket of a field"),
+)
+
+var _ = DescribeTable("snapshot repository request bodies",
+	func(spec v1.OpensearchSnapshotRepositorySpec, expected requests.SnapshotRepository) {
+		Expect(TranslateSnapshotRepositoryToRequest(spec)).To(Equal(expected))
+	},
+	Entry("When an s3 repository is given",
+		v1.OpensearchSnapshotRepositorySpec{
+			S3:       &v1.S3SnapshotRepository{Bucket: "backups", BasePath: "cluster", ServerSideEncryption: true},
+			Compress: true,
+		},
+		requests.SnapshotRepository{Type: "s3", Settings: map[string]string{
+			"bucket": "backups", "base_path": "cluster", "server_side_encryption": "true", "compress": "true",
+		}}),
+	Entry("When an azure repository with additional settings is given",
+		v1.OpensearchSnapshotRepositorySpec{
+			Azure:              &v1.AzureSnapshotRepository{Container: "backups", Client: "secondary"},
+			AdditionalSettings: map[string]string{"readonly": "true"},
+		},
+		requests.SnapshotRepository{Type: "azure", Settings: map[string]string{"container": "backups", "client": "secondary", "readonly": "true"}}),
+	Entry("When an fs repository is given",
+		v1.OpensearchSnapshotRepositorySpec{FS: &v1.FSSnapshotRepository{Location: "/mnt/backups"}, ChunkSize: "100mb"},
+		requests.SnapshotRepository{Type: "fs", Settings: map[string]string{"location": "/mnt/backups", "chunk_size": "100mb"}}),
+)
+
+var _ = DescribeTable("snapshot repository credentials in the keystore",
+	func(spec v1.OpensearchSnapshotRepositorySpec, keystore []v1.KeystoreValue, expected bool) {
+		secret, keys := SnapshotRepositoryCredentials(spec)
+		Expect(secret).To(Equal("s3-credentials"))
+		provided := true
+		for secretKey, keystoreKey := range keys {
+			provided = provided && KeystoreProvides(keystore, secret, secretKey, keystoreKey)
+		}
+		Expect(provided).To(Equal(expected))
+	},
+	Entry("When the keystore maps the keys of the secret",
+		v1.OpensearchSnapshotRepositorySpec{S3: &v1.S3SnapshotRepository{
+			Bucket: "backups", Credentials: &v1.S3Credentials{Secret: corev1.LocalObjectReference{Name: "s3-credentials"}},
+		}},
+		[]v1.KeystoreValue{{
+			Secret:      corev1.LocalObjectReference{Name: "s3-credentials"},
+			KeyMappings: map[string]string{"access_key": "s3.client.default.access_key", "secret_key": "s3.client.default.secret_key"},
+		}}, true),
+	Entry("When the keystore maps the keys to another client",
+		v1.OpensearchSnapshotRepositorySpec{S3: &v1.S3SnapshotRepository{
+			Bucket: "backups", Client: "backup", Credentials: &v1.S3Credentials{Secret: corev1.LocalObjectReference{Name: "s3-credentials"}},
+		}},
+		[]v1.KeystoreValue{{
+			Secret:      corev1.LocalObjectReference{Name: "s3-credentials"},
+			KeyMappings: map[string]string{"access_key": "s3.client.default.access_key", "secret_key": "s3.client.default.secret_key"},
+		}}, false),
+	Entry("When the secret keys are named like the keystore keys",
+		v1.OpensearchSnapshotRepositorySpec{S3: &v1.S3SnapshotRepository{
+			Bucket: "backups", Credentials: &v1.S3Credentials{
+				Secret:       corev1.LocalObjectReference{Name: "s3-credentials"},
+				AccessKeyKey: "s3.client.default.access_key",
+				SecretKeyKey: "s3.client.default.secret_key",
+			},
+		}},
+		[]v1.KeystoreValue{{Secret: corev1.LocalObjectReference{Name: "s3-credentials"}}}, true),
+	Entry("When the secret is not in the keystore",
+		v1.OpensearchSnapshotRepositorySpec{S3: &v1.S3SnapshotRepository{
+			Bucket: "backups", Credentials: &v1.S3Credentials{Secret: corev1.LocalObjectReference{Name: "s3-credentials"}},
+		}},
+		nil, false),
+)
@@ -0,0 +1,124 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// luceneFileExtensions are the extensions of the files of a Lucene index, which index.store.preload refers to
+var luceneFileExtensions = map[string]bool{
+	"si": true, "cfs": true, "cfe": true, "fnm": true,
+	"fdt": true, "fdx": true, "fdm": true,
+	"tim": true, "tip": true, "tmd": true,
+	"doc": true, "pos": true, "pay": true, "psm": true,
+	"nvd": true, "nvm": true, "dvd": true, "dvm": true,
+	"tvd": true, "tvx": true, "tvm": true,
+	"liv": true, "dii": true, "dim": true,
+	"kdd": true, "kdi": true, "kdm": true,
+	"vec": true, "vex": true, "vem": true, "vemf": true, "vemq": true, "veq": true,
+}
+
+// defaultHybridMmapExtensions are the extensions the hybridfs store type memory maps if
+// index.store.hybrid.mmap.extensions is not set, only these are preloaded
+var defaultHybridMmapExtensions = []string{"nvd", "dvd", "tim", "tip", "dim", "kdd", "kdi", "cfs", "doc"}
+
+// StorePreloadWarnings returns the problems of the index.store.preload setting: entries that are not Lucene file
+// extensions, and entries the declared or default index.store.type does not preload. No problems are returned
+// without the setting.
+func StorePreloadWarnings(settings *apiextensionsv1.JSON) ([]string, error) {
+	if settings.Size() == 0 {
+		return nil, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+	setting := func(key string) (interface{}, bool) {
+		if value, ok := flat["index."+key]; ok {
+			return value, true
+		}
+		value, ok := flat[key]
+		return value, ok
+	}
+
+	value, ok := setting("store.preload")
+	if !ok {
+		return nil, nil
+	}
+	preload := listSetting(value)
+	if len(preload) == 0 {
+		return nil, nil
+	}
+
+	var warnings []string
+	all := false
+	var extensions []string
+	for _, entry := range preload {
+		switch {
+		case entry == "*":
+			all = true
+		case strings.HasPrefix(entry, "."):
+			warnings = append(warnings, fmt.Sprintf("index.store.preload entry %q must be a file extension without the leading dot", entry))
+		case !luceneFileExtensions[entry]:
+			warnings = append(warnings, fmt.Sprintf("index.store.preload entry %q is not a Lucene file extension", entry))
+		default:
+			extensions = append(extensions, entry)
+		}
+	}
+
+	storeType := ""
+	if value, ok := setting("store.type"); ok {
+		storeType = fmt.Sprint(value)
+	}
+	switch storeType {
+	case "niofs", "simplefs":
+		warnings = append(warnings, fmt.Sprintf("index.store.preload has no effect with index.store.type %s, only mmapfs and hybridfs preload files", storeType))
+	case "", "fs", "hybridfs":
+		if storeType == "" {
+			storeType = "fs"
+		}
+		mmapped := defaultHybridMmapExtensions
+		if value, ok := setting("store.hybrid.mmap.extensions"); ok {
+			mmapped = listSetting(value)
+		}
+		var notMapped []string
+		for _, extension := range extensions {
+			if !ContainsString(mmapped, extension) {
+				notMapped = append(notMapped, extension)
+			}
+		}
+		if all {
+			// Preloading everything only preloads the memory mapped files, which is not worth a warning
+			notMapped = nil
+		}
+		if len(notMapped) > 0 {
+			sort.Strings(notMapped)
+			warnings = append(warnings, fmt.Sprintf("index.store.preload entries %s are not memory mapped with index.store.type %s and not preloaded, add them to index.store.hybrid.mmap.extensions or use mmapfs",
+				strings.Join(notMapped, ", "), storeType))
+		}
+	}
+	return warnings, nil
+}
+
+// listSetting returns the entries of a list valued setting, given as a list or as a comma separated string
+func listSetting(value interface{}) []string {
+	var entries []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			entries = append(entries, strings.TrimSpace(fmt.Sprint(item)))
+		}
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				entries = append(entries, item)
+			}
+		}
+	}
+	return entries
+}
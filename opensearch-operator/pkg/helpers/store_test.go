@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("store preload warnings",
+	func(settings string, expected []string) {
+		warnings, err := StorePreloadWarnings(&apiextensionsv1.JSON{Raw: []byte(settings)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(Equal(expected))
+	},
+	Entry("When nothing is preloaded", `{"index":{"store":{"type":"niofs"}}}`, nil),
+	Entry("When memory mapped files are preloaded with the default store type", `{"index":{"store":{"preload":["nvd","dvd"]}}}`, nil),
+	Entry("When any file is preloaded with mmapfs", `{"index.store.type":"mmapfs","index.store.preload":"nvd, tvd"}`, nil),
+	Entry("When everything is preloaded with hybridfs", `{"store":{"type":"hybridfs","preload":["*"]}}`, nil),
+	Entry("When hybridfs memory maps the preloaded files", `{"index":{"store":{"type":"hybridfs","preload":["tvd"],"hybrid":{"mmap":{"extensions":["tvd"]}}}}}`, nil),
+	Entry("When entries are not extensions", `{"index":{"store":{"type":"mmapfs","preload":[".nvd","nvdd"]}}}`, []string{
+		`index.store.preload entry ".nvd" must be a file extension without the leading dot`,
+		`index.store.preload entry "nvdd" is not a Lucene file extension`,
+	}),
+	Entry("When the store type does not preload", `{"index":{"store":{"type":"niofs","preload":["nvd"]}}}`, []string{
+		"index.store.preload has no effect with index.store.type niofs, only mmapfs and hybridfs preload files",
+	}),
+	Entry("When the default store type does not memory map the preloaded files", `{"index":{"store":{"preload":["tvd","fdt","nvd"]}}}`, []string{
+		"index.store.preload entries fdt, tvd are not memory mapped with index.store.type fs and not preloaded, add them to index.store.hybrid.mmap.extensions or use mmapfs",
+	}),
+)
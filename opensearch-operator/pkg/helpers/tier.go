@@ -0,0 +1,57 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// DefaultTierAttribute is the custom node attribute holding the data tier of a node if none is configured
+const DefaultTierAttribute = "temp"
+
+// SetAllocationTier returns a copy of the index settings requiring the indices to be allocated to nodes having the
+// custom node attribute set to tier, i.e. index.routing.allocation.require.<attribute>: <tier>. An error is returned
+// if the settings already require a different value for the attribute.
+func SetAllocationTier(settings *apiextensionsv1.JSON, attribute string, tier string) (*apiextensionsv1.JSON, error) {
+	parsed := map[string]interface{}{}
+	if settings.Size() > 0 {
+		if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+			return nil, err
+		}
+	}
+
+	requireKey := "routing.allocation.require." + attribute
+	flat := map[string]interface{}{}
+	flattenSettings("", parsed, flat)
+	for _, key := range []string{"index." + requireKey, requireKey} {
+		value, ok := flat[key]
+		if !ok {
+			continue
+		}
+		if fmt.Sprint(value) != tier {
+			return nil, fmt.Errorf("settings require %s to be %v, which conflicts with the %s tier", key, value, tier)
+		}
+		return settings, nil
+	}
+
+	container := parsed
+	for _, key := range []string{"index", "routing", "allocation", "require"} {
+		nested, ok := container[key].(map[string]interface{})
+		if !ok {
+			if _, exists := container[key]; exists {
+				return nil, fmt.Errorf("setting %s is not an object", key)
+			}
+			nested = map[string]interface{}{}
+			container[key] = nested
+		}
+		container = nested
+	}
+	container[attribute] = tier
+
+	raw, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("allocation tier settings",
+	func(settings string, tier string, expected string, valid bool) {
+		var input *apiextensionsv1.JSON
+		if settings != "" {
+			input = &apiextensionsv1.JSON{Raw: []byte(settings)}
+		}
+		result, err := SetAllocationTier(input, DefaultTierAttribute, tier)
+		if !valid {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Raw).To(MatchJSON(expected))
+	},
+	Entry("When the hot tier is requested", "", "hot", `{"index":{"routing":{"allocation":{"require":{"temp":"hot"}}}}}`, true),
+	Entry("When the warm tier is requested", `{"index":{"number_of_shards":"1"}}`, "warm",
+		`{"index":{"number_of_shards":"1","routing":{"allocation":{"require":{"temp":"warm"}}}}}`, true),
+	Entry("When the cold tier is requested", `{"index.number_of_replicas":"0"}`, "cold",
+		`{"index.number_of_replicas":"0","index":{"routing":{"allocation":{"require":{"temp":"cold"}}}}}`, true),
+	Entry("When the frozen tier is requested", `{"index":{"routing":{"allocation":{"include":{"zone":"a"}}}}}`, "frozen",
+		`{"index":{"routing":{"allocation":{"include":{"zone":"a"},"require":{"temp":"frozen"}}}}}`, true),
+	Entry("When the settings already require the tier", `{"index.routing.allocation.require.temp":"hot"}`, "hot",
+		`{"index.routing.allocation.require.temp":"hot"}`, true),
+	Entry("When the settings require another tier", `{"index":{"routing.allocation.require.temp":"warm"}}`, "hot", "", false),
+)
@@ -0,0 +1,211 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// timeIndexSettings are the index settings holding a time value
+var timeIndexSettings = map[string]bool{
+	"index.refresh_interval":                          true,
+	"index.translog.sync_interval":                    true,
+	"index.translog.retention.age":                    true,
+	"index.gc_deletes":                                true,
+	"index.search.idle.after":                         true,
+	"index.unassigned.node_left.delayed_timeout":      true,
+	"index.soft_deletes.retention_lease.period":       true,
+	"index.remote_store.translog.buffer_interval":     true,
+	"index.plugins.replication.follower.poll_timeout": true,
+}
+
+// timeIndexSettingPrefixes are the prefixes of index settings holding a time value
+var timeIndexSettingPrefixes = []string{
+	"index.search.slowlog.threshold.",
+	"index.indexing.slowlog.threshold.",
+}
+
+// timeUnits are the units OpenSearch accepts for time values, ordered from the largest to the smallest
+var timeUnits = []struct {
+	suffix   string
+	duration time.Duration
+}{
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"micros", time.Microsecond},
+	{"nanos", time.Nanosecond},
+}
+
+// timeUnitParseOrder lists the suffixes of timeUnits so that no suffix is checked before a longer one ending in it
+var timeUnitParseOrder = []string{"micros", "nanos", "ms", "d", "h", "m", "s"}
+
+// ParseTimeValue parses a time value the way OpenSearch does, e.g. "30s" or "500ms". The values "-1" (disabled)
+// and "0" are accepted without a unit, -1 is returned as a negative duration.
+func ParseTimeValue(value string) (time.Duration, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	switch normalized {
+	case "-1":
+		return -1, nil
+	case "0":
+		return 0, nil
+	}
+
+	for _, suffix := range timeUnitParseOrder {
+		if !strings.HasSuffix(normalized, suffix) {
+			continue
+		}
+		var unit time.Duration
+		for _, u := range timeUnits {
+			if u.suffix == suffix {
+				unit = u.duration
+			}
+		}
+		amount, err := strconv.ParseInt(strings.TrimSpace(strings.TrimSuffix(normalized, suffix)), 10, 64)
+		if err != nil || amount < 0 {
+			break
+		}
+		if amount > math.MaxInt64/int64(unit) {
+			return 0, fmt.Errorf("time value %q is too large", value)
+		}
+		return time.Duration(amount) * unit, nil
+	}
+	return 0, fmt.Errorf("invalid time value %q, expected a whole number followed by one of d, h, m, s, ms, micros or nanos", value)
+}
+
+// FormatTimeValue formats a duration in the largest unit that represents it exactly, e.g. 30000ms as "30s"
+func FormatTimeValue(duration time.Duration) string {
+	if duration < 0 {
+		return "-1"
+	}
+	if duration == 0 {
+		return "0"
+	}
+	for _, unit := range timeUnits {
+		if duration%unit.duration == 0 {
+			return fmt.Sprintf("%d%s", duration/unit.duration, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%dnanos", duration)
+}
+
+// IsTimeIndexSetting returns true if the index setting (in flat notation) holds a time value
+func IsTimeIndexSetting(key string) bool {
+	if !strings.HasPrefix(key, "index.") {
+		key = "index." + key
+	}
+	if timeIndexSettings[key] {
+		return true
+	}
+	for _, prefix := range timeIndexSettingPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTimeSettings returns an error naming every time valued index setting whose value OpenSearch would reject
+func ValidateTimeSettings(settings *apiextensionsv1.JSON) error {
+	if settings.Size() == 0 {
+		return nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return err
+	}
+
+	var invalid []string
+	walkTimeSettings("", parsed, func(key string, value interface{}) interface{} {
+		if _, err := ParseTimeValue(fmt.Sprint(value)); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %s", key, err))
+		}
+		return value
+	})
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid time settings: %s", strings.Join(invalid, "; "))
+}
+
+// NormalizeTimeSettings returns a copy of the index settings with all valid time values formatted in the largest
+// exact unit, so values OpenSearch normalizes (e.g. "30s" to "30000ms") compare equal
+func NormalizeTimeSettings(settings *apiextensionsv1.JSON) (*apiextensionsv1.JSON, error) {
+	if settings.Size() == 0 {
+		return settings, nil
+	}
+	parsed := map[string]interface{}{}
+	if err := UnmarshalPreservingNumbers(settings.Raw, &parsed); err != nil {
+		return nil, err
+	}
+
+	walkTimeSettings("", parsed, func(_ string, value interface{}) interface{} {
+		duration, err := ParseTimeValue(fmt.Sprint(value))
+		if err != nil {
+			return value
+		}
+		return FormatTimeValue(duration)
+	})
+	raw, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+// walkTimeSettings calls replace for every time valued setting and stores the returned value in its place
+func walkTimeSettings(prefix string, settings map[string]interface{}, replace func(key string, value interface{}) interface{}) {
+	for key, value := range settings {
+		flatKey := key
+		if prefix != "" {
+			flatKey = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			walkTimeSettings(flatKey, nested, replace)
+			continue
+		}
+		if IsTimeIndexSetting(flatKey) {
+			settings[key] = replace(flatKey, value)
+		}
+	}
+}
+
+// ComponentTemplatesEqual compares component templates normalized like NormalizeComponentTemplate
+func ComponentTemplatesEqual(a requests.ComponentTemplate, b requests.ComponentTemplate) bool {
+	return reflect.DeepEqual(NormalizeComponentTemplate(a), NormalizeComponentTemplate(b))
+}
+
+// IndexTemplatesEqual compares index templates with their time valued settings normalized
+func IndexTemplatesEqual(a requests.IndexTemplate, b requests.IndexTemplate) bool {
+	a.Template = normalizeIndexTimeSettings(a.Template)
+	b.Template = normalizeIndexTimeSettings(b.Template)
+	return reflect.DeepEqual(a, b)
+}
+
+// NormalizeComponentTemplate returns a copy of the component template with its time valued settings normalized and
+// without the provenance in its _meta, which does not make it differ
+func NormalizeComponentTemplate(template requests.ComponentTemplate) requests.ComponentTemplate {
+	template.Template = normalizeIndexTimeSettings(template.Template)
+	template.Meta = WithoutProvenance(template.Meta)
+	return template
+}
+
+// normalizeIndexTimeSettings keeps the settings unchanged if they cannot be parsed, the comparison then falls
+// back to the raw settings
+func normalizeIndexTimeSettings(index requests.Index) requests.Index {
+	if normalized, err := NormalizeTimeSettings(index.Settings); err == nil {
+		index.Settings = normalized
+	}
+	return index
+}
@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"time"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("time value parsing",
+	func(value string, expected time.Duration, valid bool) {
+		duration, err := ParseTimeValue(value)
+		if !valid {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(duration).To(Equal(expected))
+	},
+	Entry("When given seconds", "30s", 30*time.Second, true),
+	Entry("When given milliseconds", "30000ms", 30*time.Second, true),
+	Entry("When given minutes", "5m", 5*time.Minute, true),
+	Entry("When given hours", "2h", 2*time.Hour, true),
+	Entry("When given days", "7d", 7*24*time.Hour, true),
+	Entry("When given microseconds", "1500micros", 1500*time.Microsecond, true),
+	Entry("When given nanoseconds", "10nanos", 10*time.Nanosecond, true),
+	Entry("When given upper case units", "30S", 30*time.Second, true),
+	Entry("When given a space before the unit", "30 s", 30*time.Second, true),
+	Entry("When disabled", "-1", time.Duration(-1), true),
+	Entry("When zero", "0", time.Duration(0), true),
+	Entry("When given a spelled out unit", "30 seconds", time.Duration(0), false),
+	Entry("When given no unit", "30", time.Duration(0), false),
+	Entry("When given a fraction", "1.5s", time.Duration(0), false),
+	Entry("When given an unknown unit", "2w", time.Duration(0), false),
+	Entry("When given a negative value", "-5s", time.Duration(0), false),
+)
+
+var _ = DescribeTable("time value formatting",
+	func(duration time.Duration, expected string) {
+		Expect(FormatTimeValue(duration)).To(Equal(expected))
+	},
+	Entry("When a whole number of seconds", 30*time.Second, "30s"),
+	Entry("When a whole number of days", 48*time.Hour, "2d"),
+	Entry("When not a whole number of seconds", 1500*time.Millisecond, "1500ms"),
+	Entry("When disabled", time.Duration(-1), "-1"),
+)
+
+var _ = DescribeTable("time settings validation",
+	func(settings string, valid bool) {
+		err := ValidateTimeSettings(&apiextensionsv1.JSON{Raw: []byte(settings)})
+		if valid {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+		}
+	},
+	Entry("When no time settings are set", `{"index":{"number_of_shards":"1"}}`, true),
+	Entry("When time settings are valid", `{"index":{"refresh_interval":"1s","translog":{"sync_interval":"5000ms"}}}`, true),
+	Entry("When a flat time setting is invalid", `{"index.refresh_interval":"30 seconds"}`, false),
+	Entry("When a slowlog threshold is invalid", `{"index":{"search":{"slowlog":{"threshold":{"query":{"warn":"10"}}}}}}`, false),
+	Entry("When a time setting without the index prefix is invalid", `{"gc_deletes":"1 minute"}`, false),
+)
+
+var _ = Describe("time settings normalization", func() {
+	It("should consider equivalent units equal", func() {
+		desired := requests.ComponentTemplate{Template: requests.Index{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"refresh_interval":"30s","number_of_shards":"1"}}`)},
+		}}
+		live := requests.ComponentTemplate{Template: requests.Index{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"1","refresh_interval":"30000ms"}}`)},
+		}}
+		Expect(ComponentTemplatesEqual(desired, live)).To(BeTrue())
+	})
+
+	It("should detect changed time values", func() {
+		desired := requests.IndexTemplate{Template: requests.Index{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index.translog.sync_interval":"5s"}`)},
+		}}
+		live := requests.IndexTemplate{Template: requests.Index{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index.translog.sync_interval":"10s"}`)},
+		}}
+		Expect(IndexTemplatesEqual(desired, live)).To(BeFalse())
+	})
+})
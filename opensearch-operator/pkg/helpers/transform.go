@@ -0,0 +1,140 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+// transformImmutableFields maps the fields of a transform job OpenSearch refuses to update to the fields of the spec
+var transformImmutableFields = map[string]string{
+	"source_index":         "sourceIndex",
+	"target_index":         "targetIndex",
+	"data_selection_query": "dataSelectionQuery",
+	"continuous":           "continuous",
+	"groups":               "groups",
+	"aggregations":         "aggregations",
+}
+
+// ValidateTransformJob returns an error naming every problem of the transform job spec OpenSearch would reject
+func ValidateTransformJob(spec v1.OpensearchTransformJobSpec) error {
+	invalid := validateScheduledJob(spec.Schedule, spec.SourceIndex, spec.TargetIndex)
+	invalid = append(invalid, validateRollupDimensions("group", transformGroupDimensions(spec.Groups))...)
+	for name, value := range map[string]*apiextensionsv1.JSON{
+		"dataSelectionQuery": spec.DataSelectionQuery,
+		"aggregations":       spec.Aggregations,
+	} {
+		if value.Size() == 0 {
+			continue
+		}
+		var object map[string]interface{}
+		if err := json.Unmarshal(value.Raw, &object); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s has to be an object", name))
+		}
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid transform job: %s", strings.Join(invalid, "; "))
+}
+
+// transformGroupDimensions returns the groups as rollup dimensions, which they extend by a target field
+func transformGroupDimensions(groups []v1.TransformGroup) []v1.RollupDimension {
+	dimensions := make([]v1.RollupDimension, 0, len(groups))
+	for _, group := range groups {
+		dimension := v1.RollupDimension{}
+		if group.DateHistogram != nil {
+			dimension.DateHistogram = &group.DateHistogram.RollupDateHistogram
+		}
+		if group.Terms != nil {
+			dimension.Terms = &group.Terms.RollupTerms
+		}
+		if group.Histogram != nil {
+			dimension.Histogram = &group.Histogram.RollupHistogram
+		}
+		dimensions = append(dimensions, dimension)
+	}
+	return dimensions
+}
+
+// TranslateTransformJobToRequest validates the spec and rewrites the CRD format to the gateway format. The start
+// time of an interval schedule is left empty, it is set when the job is created
+func TranslateTransformJobToRequest(spec v1.OpensearchTransformJobSpec) (requests.IndexTransform, error) {
+	if err := ValidateTransformJob(spec); err != nil {
+		return requests.IndexTransform{}, err
+	}
+
+	pageSize := spec.PageSize
+	if pageSize == 0 {
+		pageSize = 1000
+	}
+	request := requests.IndexTransform{
+		Enabled:            pointer.BoolDeref(spec.Enabled, true),
+		Schedule:           translateRollupSchedule(spec.Schedule),
+		Description:        spec.Description,
+		SourceIndex:        spec.SourceIndex,
+		TargetIndex:        spec.TargetIndex,
+		DataSelectionQuery: spec.DataSelectionQuery,
+		PageSize:           pageSize,
+		Continuous:         spec.Continuous,
+		Groups:             []requests.TransformGroup{},
+		Aggregations:       spec.Aggregations,
+	}
+
+	for i, dimension := range transformGroupDimensions(spec.Groups) {
+		translated := translateRollupDimension(dimension)
+		group := spec.Groups[i]
+		switch {
+		case group.DateHistogram != nil:
+			request.Groups = append(request.Groups, requests.TransformGroup{DateHistogram: &requests.TransformDateHistogram{
+				RollupDateHistogram: *translated.DateHistogram,
+				TargetField:         group.DateHistogram.TargetField,
+			}})
+		case group.Terms != nil:
+			request.Groups = append(request.Groups, requests.TransformGroup{Terms: &requests.TransformTerms{
+				RollupTerms: *translated.Terms,
+				TargetField: group.Terms.TargetField,
+			}})
+		case group.Histogram != nil:
+			request.Groups = append(request.Groups, requests.TransformGroup{Histogram: &requests.TransformHistogram{
+				RollupHistogram: *translated.Histogram,
+				TargetField:     group.Histogram.TargetField,
+			}})
+		}
+	}
+	return request, nil
+}
+
+// TransformImmutableChanges returns the sorted spec fields of the desired transform job that differ from the existing
+// job and cannot be updated
+func TransformImmutableChanges(desired requests.IndexTransform, existing *apiextensionsv1.JSON) ([]string, error) {
+	return jsonFieldChanges(desired, existing, transformImmutableFields)
+}
+
+// TransformJobUpdate returns the job to update the existing transform job with, nil if the existing job holds
+// everything of the desired job, and whether the existing job is enabled. Like RollupJobUpdate the returned job keeps
+// the enabled flag and the start time of the existing job
+func TransformJobUpdate(desired requests.IndexTransform, existing *apiextensionsv1.JSON) (*requests.IndexTransform, bool, error) {
+	enabled, startTime, err := scheduledJobState(existing)
+	if err != nil {
+		return nil, false, err
+	}
+
+	update := desired
+	update.Enabled = enabled
+	update.Schedule = withStartTime(desired.Schedule, startTime)
+
+	equal, err := isJSONSubset(update, existing)
+	if err != nil || equal {
+		return nil, enabled, err
+	}
+	return &update, enabled, nil
+}
@@ -0,0 +1,123 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+func transformJobSpec() v1.OpensearchTransformJobSpec {
+	return v1.OpensearchTransformJobSpec{
+		Schedule:    v1.RollupSchedule{Interval: &v1.RollupInterval{Period: 1}},
+		SourceIndex: "orders-*",
+		TargetIndex: "orders-by-customer",
+		Groups: []v1.TransformGroup{
+			{Terms: &v1.TransformTerms{RollupTerms: v1.RollupTerms{SourceField: "customer.id"}, TargetField: "customer"}},
+			{DateHistogram: &v1.TransformDateHistogram{RollupDateHistogram: v1.RollupDateHistogram{SourceField: "date", CalendarInterval: "1d"}}},
+		},
+		Aggregations: &apiextensionsv1.JSON{Raw: []byte(`{"revenue":{"sum":{"field":"price"}}}`)},
+	}
+}
+
+var _ = DescribeTable("transform job validation",
+	func(modify func(spec *v1.OpensearchTransformJobSpec), expected string) {
+		spec := transformJobSpec()
+		modify(&spec)
+		err := ValidateTransformJob(spec)
+		if expected == "" {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+	Entry("When the job is complete", func(spec *v1.OpensearchTransformJobSpec) {}, ""),
+	Entry("When no schedule is set", func(spec *v1.OpensearchTransformJobSpec) {
+		spec.Schedule = v1.RollupSchedule{}
+	}, "exactly one of interval and cron has to be set in the schedule"),
+	Entry("When the target index is the source index", func(spec *v1.OpensearchTransformJobSpec) {
+		spec.TargetIndex = "orders-*"
+	}, `source and target index have to differ; target index "orders-*" must not contain *`),
+	Entry("When a group sets no type", func(spec *v1.OpensearchTransformJobSpec) {
+		spec.Groups = append(spec.Groups, v1.TransformGroup{})
+	}, "group 2 has to set exactly one of dateHistogram, terms and histogram, got 0"),
+	Entry("When a date histogram sets no interval", func(spec *v1.OpensearchTransformJobSpec) {
+		spec.Groups[1].DateHistogram.CalendarInterval = ""
+	}, "date histogram of date has to set exactly one of fixedInterval and calendarInterval"),
+	Entry("When the aggregations are a list", func(spec *v1.OpensearchTransformJobSpec) {
+		spec.Aggregations = &apiextensionsv1.JSON{Raw: []byte(`[{"sum":{"field":"price"}}]`)}
+	}, "aggregations has to be an object"),
+)
+
+var _ = Describe("transform jobs", func() {
+	It("should translate the spec", func() {
+		request, err := TranslateTransformJobToRequest(transformJobSpec())
+		Expect(err).ToNot(HaveOccurred())
+		raw, err := json.Marshal(requests.TransformJob{Transform: request})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raw).To(MatchJSON(`{"transform": {
+			"enabled": true,
+			"schedule": {"interval": {"period": 1, "unit": "Minutes"}},
+			"source_index": "orders-*",
+			"target_index": "orders-by-customer",
+			"page_size": 1000,
+			"continuous": false,
+			"groups": [
+				{"terms": {"source_field": "customer.id", "target_field": "customer"}},
+				{"date_histogram": {"source_field": "date", "calendar_interval": "1d", "timezone": "UTC"}}
+			],
+			"aggregations": {"revenue": {"sum": {"field": "price"}}}
+		}}`))
+	})
+
+	existing := &apiextensionsv1.JSON{Raw: []byte(`{
+		"transform_id": "orders",
+		"enabled": false,
+		"schedule": {"interval": {"start_time": 1700000000000, "period": 1, "unit": "Minutes"}},
+		"source_index": "orders-*",
+		"target_index": "orders-by-customer",
+		"data_selection_query": {"match_all": {"boost": 1.0}},
+		"page_size": 1000,
+		"continuous": false,
+		"groups": [
+			{"terms": {"source_field": "customer.id", "target_field": "customer"}},
+			{"date_histogram": {"source_field": "date", "target_field": "date", "calendar_interval": "1d", "timezone": "UTC"}}
+		],
+		"aggregations": {"revenue": {"sum": {"field": "price"}}}
+	}`)}
+
+	It("should not update a job in sync", func() {
+		request, err := TranslateTransformJobToRequest(transformJobSpec())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(TransformImmutableChanges(request, existing)).To(BeEmpty())
+		update, enabled, err := TransformJobUpdate(request, existing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(update).To(BeNil())
+		Expect(enabled).To(BeFalse())
+	})
+
+	It("should keep the start time of the existing job", func() {
+		spec := transformJobSpec()
+		spec.Description = "revenue per customer"
+		request, err := TranslateTransformJobToRequest(spec)
+		Expect(err).ToNot(HaveOccurred())
+		update, _, err := TransformJobUpdate(request, existing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(update).ToNot(BeNil())
+		Expect(update.Schedule.Interval.StartTime).To(Equal(pointer.Int64(1700000000000)))
+		Expect(update.Enabled).To(BeFalse())
+	})
+
+	It("should name the fields that cannot be changed", func() {
+		spec := transformJobSpec()
+		spec.Continuous = true
+		spec.DataSelectionQuery = &apiextensionsv1.JSON{Raw: []byte(`{"term":{"status":"paid"}}`)}
+		request, err := TranslateTransformJobToRequest(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(TransformImmutableChanges(request, existing)).To(Equal([]string{"continuous", "dataSelectionQuery"}))
+	})
+})
@@ -1,8 +1,12 @@
 package helpers
 
 import (
+	"encoding/json"
+	"reflect"
+
 	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 // TranslateIndexTemplateToRequest rewrites the CRD format to the gateway format
@@ -14,16 +18,26 @@ func TranslateIndexTemplateToRequest(spec v1.OpensearchIndexTemplateSpec) reques
 		Version:       spec.Version,
 	}
 	if spec.Meta.Size() > 0 {
-		request.Meta = spec.Meta
+		request.Meta = SortJSONKeys(spec.Meta)
 	}
 	if len(spec.ComposedOf) > 0 {
 		request.ComposedOf = spec.ComposedOf
 	}
+	if spec.DataStream != nil {
+		// OpenSearch returns the default timestamp field, so it is set explicitly for the comparison
+		timestampField := spec.DataStream.TimestampField
+		if timestampField == "" {
+			timestampField = "@timestamp"
+		}
+		request.DataStream = &requests.DataStream{TimestampField: &requests.DataStreamTimestampField{Name: timestampField}}
+	}
 
 	return request
 }
 
-// TranslateComponentTemplateToRequest rewrites the CRD format to the gateway format
+// TranslateComponentTemplateToRequest rewrites the CRD format to the gateway format. The JSON objects of the spec
+// are rewritten with sorted keys, so the same spec always results in the same request body, which proxies caching
+// or signing requests by their body rely on.
 func TranslateComponentTemplateToRequest(spec v1.OpensearchComponentTemplateSpec) requests.ComponentTemplate {
 	request := requests.ComponentTemplate{
 		AllowAutoCreate: spec.AllowAutoCreate,
@@ -31,12 +45,239 @@ func TranslateComponentTemplateToRequest(spec v1.OpensearchComponentTemplateSpec
 		Version:         spec.Version,
 	}
 	if spec.Meta.Size() > 0 {
-		request.Meta = spec.Meta
+		request.Meta = SortJSONKeys(spec.Meta)
+	}
+
+	return request
+}
+
+// TranslateIngestPipelineToRequest rewrites the CRD format to the gateway format, the processors are rewritten with
+// sorted keys like the JSON objects of templates
+func TranslateIngestPipelineToRequest(spec v1.OpensearchIngestPipelineSpec) requests.IngestPipeline {
+	request := requests.IngestPipeline{
+		Description: spec.Description,
+		Processors:  sortJSONKeysOfList(spec.Processors),
+		OnFailure:   sortJSONKeysOfList(spec.OnFailure),
+		Version:     spec.Version,
+	}
+	if spec.Meta.Size() > 0 {
+		request.Meta = SortJSONKeys(spec.Meta)
+	}
+	return request
+}
+
+// IngestPipelinesEqual compares two ingest pipelines regardless of the key order and whitespace of their processors,
+// which OpenSearch does not return as they were written
+func IngestPipelinesEqual(a requests.IngestPipeline, b requests.IngestPipeline) bool {
+	normalize := func(pipeline requests.IngestPipeline) requests.IngestPipeline {
+		pipeline.Processors = sortJSONKeysOfList(pipeline.Processors)
+		pipeline.OnFailure = sortJSONKeysOfList(pipeline.OnFailure)
+		if pipeline.Meta.Size() > 0 {
+			pipeline.Meta = SortJSONKeys(pipeline.Meta)
+		} else {
+			pipeline.Meta = nil
+		}
+		return pipeline
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// TranslateSnapshotPolicyToRequest rewrites the CRD format to the gateway format. A deletion without a schedule
+// gets the schedule of the creation, as OpenSearch returns it once the policy is created.
+func TranslateSnapshotPolicyToRequest(spec v1.OpensearchSnapshotPolicySpec) requests.SnapshotPolicy {
+	request := requests.SnapshotPolicy{
+		Description: spec.Description,
+		Enabled:     spec.Enabled == nil || *spec.Enabled,
+		Creation: requests.SnapshotCreation{
+			Schedule:  translateCronSchedule(spec.Creation.Schedule),
+			TimeLimit: spec.Creation.TimeLimit,
+		},
+		SnapshotConfig: requests.SnapshotConfig{
+			Repository:         spec.SnapshotConfig.Repository,
+			Indices:            spec.SnapshotConfig.Indices,
+			DateFormat:         spec.SnapshotConfig.DateFormat,
+			Timezone:           spec.SnapshotConfig.Timezone,
+			IgnoreUnavailable:  spec.SnapshotConfig.IgnoreUnavailable,
+			IncludeGlobalState: spec.SnapshotConfig.IncludeGlobalState,
+			Partial:            spec.SnapshotConfig.Partial,
+		},
+	}
+	if spec.SnapshotConfig.Metadata.Size() > 0 {
+		request.SnapshotConfig.Metadata = SortJSONKeys(spec.SnapshotConfig.Metadata)
+	}
+	if spec.Deletion != nil {
+		schedule := request.Creation.Schedule
+		if spec.Deletion.Schedule != nil {
+			schedule = translateCronSchedule(*spec.Deletion.Schedule)
+		}
+		request.Deletion = &requests.SnapshotDeletion{
+			Schedule: &schedule,
+			Condition: requests.SnapshotDeleteCondition{
+				MaxAge:   spec.Deletion.Condition.MaxAge,
+				MaxCount: spec.Deletion.Condition.MaxCount,
+				MinCount: spec.Deletion.Condition.MinCount,
+			},
+			TimeLimit: spec.Deletion.TimeLimit,
+		}
+	}
+	if spec.Notification != nil {
+		request.Notification = &requests.SnapshotNotification{
+			Channel: requests.SnapshotNotificationChannel{ID: spec.Notification.ChannelID},
+			Conditions: requests.SnapshotNotificationConditions{
+				Creation:          spec.Notification.Creation,
+				Deletion:          spec.Notification.Deletion,
+				Failure:           spec.Notification.Failure == nil || *spec.Notification.Failure,
+				TimeLimitExceeded: spec.Notification.TimeLimitExceeded,
+			},
+		}
+	}
+	return request
+}
+
+func translateCronSchedule(schedule v1.CronSchedule) requests.SnapshotSchedule {
+	timezone := schedule.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return requests.SnapshotSchedule{Cron: requests.CronSchedule{Expression: schedule.Expression, Timezone: timezone}}
+}
+
+// SnapshotPoliciesEqual compares two snapshot policies regardless of the key order and whitespace of their metadata
+func SnapshotPoliciesEqual(a requests.SnapshotPolicy, b requests.SnapshotPolicy) bool {
+	normalize := func(policy requests.SnapshotPolicy) requests.SnapshotPolicy {
+		if policy.SnapshotConfig.Metadata.Size() > 0 {
+			policy.SnapshotConfig.Metadata = SortJSONKeys(policy.SnapshotConfig.Metadata)
+		} else {
+			policy.SnapshotConfig.Metadata = nil
+		}
+		return policy
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// TranslateSnapshotRepositoryToRequest rewrites the CRD format to the gateway format, the spec has to be valid
+func TranslateSnapshotRepositoryToRequest(spec v1.OpensearchSnapshotRepositorySpec) requests.SnapshotRepository {
+	request := requests.SnapshotRepository{Settings: map[string]string{}}
+	set := func(name string, value string) {
+		if value != "" {
+			request.Settings[name] = value
+		}
+	}
+	setBool := func(name string, value bool) {
+		if value {
+			request.Settings[name] = "true"
+		}
+	}
+
+	switch {
+	case spec.S3 != nil:
+		request.Type = "s3"
+		set("bucket", spec.S3.Bucket)
+		set("base_path", spec.S3.BasePath)
+		set("client", spec.S3.Client)
+		set("region", spec.S3.Region)
+		set("endpoint", spec.S3.Endpoint)
+		setBool("server_side_encryption", spec.S3.ServerSideEncryption)
+		set("storage_class", spec.S3.StorageClass)
+	case spec.GCS != nil:
+		request.Type = "gcs"
+		set("bucket", spec.GCS.Bucket)
+		set("base_path", spec.GCS.BasePath)
+		set("client", spec.GCS.Client)
+	case spec.Azure != nil:
+		request.Type = "azure"
+		set("container", spec.Azure.Container)
+		set("base_path", spec.Azure.BasePath)
+		set("client", spec.Azure.Client)
+		set("location_mode", spec.Azure.LocationMode)
+	case spec.FS != nil:
+		request.Type = "fs"
+		set("location", spec.FS.Location)
 	}
 
+	setBool("readonly", spec.Readonly)
+	setBool("compress", spec.Compress)
+	set("chunk_size", spec.ChunkSize)
+	set("max_snapshot_bytes_per_sec", spec.MaxSnapshotBytesPerSec)
+	set("max_restore_bytes_per_sec", spec.MaxRestoreBytesPerSec)
+	for name, value := range spec.AdditionalSettings {
+		request.Settings[name] = value
+	}
 	return request
 }
 
+// SnapshotRepositoriesEqual compares the type and settings of two snapshot repositories
+func SnapshotRepositoriesEqual(a requests.SnapshotRepository, b requests.SnapshotRepository) bool {
+	if a.Type != b.Type || len(a.Settings) != len(b.Settings) {
+		return false
+	}
+	for name, value := range a.Settings {
+		if other, ok := b.Settings[name]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// TranslateSearchPipelineToRequest rewrites the CRD format to the gateway format, the processors are rewritten with
+// sorted keys like the processors of ingest pipelines
+func TranslateSearchPipelineToRequest(spec v1.OpensearchSearchPipelineSpec) requests.SearchPipeline {
+	return requests.SearchPipeline{
+		Description:            spec.Description,
+		RequestProcessors:      sortJSONKeysOfList(spec.RequestProcessors),
+		ResponseProcessors:     sortJSONKeysOfList(spec.ResponseProcessors),
+		PhaseResultsProcessors: sortJSONKeysOfList(spec.PhaseResultsProcessors),
+		Version:                spec.Version,
+	}
+}
+
+// SearchPipelinesEqual compares two search pipelines regardless of the key order and whitespace of their processors
+func SearchPipelinesEqual(a requests.SearchPipeline, b requests.SearchPipeline) bool {
+	normalize := func(pipeline requests.SearchPipeline) requests.SearchPipeline {
+		pipeline.RequestProcessors = sortJSONKeysOfList(pipeline.RequestProcessors)
+		pipeline.ResponseProcessors = sortJSONKeysOfList(pipeline.ResponseProcessors)
+		pipeline.PhaseResultsProcessors = sortJSONKeysOfList(pipeline.PhaseResultsProcessors)
+		return pipeline
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func sortJSONKeysOfList(list []apiextensionsv1.JSON) []apiextensionsv1.JSON {
+	if len(list) == 0 {
+		return nil
+	}
+	sorted := make([]apiextensionsv1.JSON, len(list))
+	for i := range list {
+		sorted[i] = *SortJSONKeys(&list[i])
+	}
+	return sorted
+}
+
+// TranslateStoredScriptToRequest rewrites the CRD format to the gateway format
+func TranslateStoredScriptToRequest(spec v1.OpensearchStoredScriptSpec) requests.StoredScript {
+	lang := spec.Lang
+	if lang == "" {
+		lang = "painless"
+	}
+	request := requests.StoredScript{
+		Lang:   lang,
+		Source: spec.Source,
+	}
+	if len(spec.Options) > 0 {
+		request.Options = spec.Options
+	}
+	return request
+}
+
+// TranslateSearchTemplateToRequest rewrites the CRD format to the gateway format. The source is stored as a string,
+// OpenSearch would add a content type option to a source stored as an object
+func TranslateSearchTemplateToRequest(spec v1.OpensearchSearchTemplateSpec) requests.StoredScript {
+	return requests.StoredScript{
+		Lang:   "mustache",
+		Source: spec.Source,
+	}
+}
+
 // TranslateIndexToRequest rewrites the CRD format to the gateway format
 func TranslateIndexToRequest(spec v1.OpensearchIndexSpec) requests.Index {
 	aliases := make(map[string]requests.IndexAlias)
@@ -44,7 +285,7 @@ func TranslateIndexToRequest(spec v1.OpensearchIndexSpec) requests.Index {
 		aliases[key] = requests.IndexAlias{
 			Index:        val.Index,
 			Alias:        val.Alias,
-			Filter:       val.Filter,
+			Filter:       SortJSONKeys(val.Filter),
 			Routing:      val.Routing,
 			IsWriteIndex: val.IsWriteIndex,
 		}
@@ -56,11 +297,28 @@ func TranslateIndexToRequest(spec v1.OpensearchIndexSpec) requests.Index {
 		request.Aliases = aliases
 	}
 	if spec.Settings.Size() > 0 {
-		request.Settings = spec.Settings
+		request.Settings = SortJSONKeys(spec.Settings)
 	}
 	if spec.Mappings.Size() > 0 {
-		request.Mappings = spec.Mappings
+		request.Mappings = SortJSONKeys(spec.Mappings)
 	}
 
 	return request
 }
+
+// SortJSONKeys returns the JSON with the keys of all objects sorted and insignificant whitespace removed. Numbers
+// are kept exactly as written. JSON that cannot be parsed is returned as is, OpenSearch then rejects it.
+func SortJSONKeys(raw *apiextensionsv1.JSON) *apiextensionsv1.JSON {
+	if raw.Size() == 0 {
+		return raw
+	}
+	var parsed interface{}
+	if err := UnmarshalPreservingNumbers(raw.Raw, &parsed); err != nil {
+		return raw
+	}
+	sorted, err := json.Marshal(parsed)
+	if err != nil {
+		return raw
+	}
+	return &apiextensionsv1.JSON{Raw: sorted}
+}
@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"encoding/json"
+
+	v1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("component template request bodies",
+	func(first v1.OpensearchComponentTemplateSpec, second v1.OpensearchComponentTemplateSpec, expected string) {
+		firstBody, err := json.Marshal(TranslateComponentTemplateToRequest(first))
+		Expect(err).ToNot(HaveOccurred())
+		secondBody, err := json.Marshal(TranslateComponentTemplateToRequest(second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(firstBody)).To(Equal(expected))
+		Expect(string(secondBody)).To(Equal(expected))
+	},
+	Entry("When the settings are given in another key order",
+		v1.OpensearchComponentTemplateSpec{Template: v1.OpensearchIndexSpec{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"1","codec":"best_compression"}}`)},
+		}},
+		v1.OpensearchComponentTemplateSpec{Template: v1.OpensearchIndexSpec{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{ "index": { "codec": "best_compression", "number_of_shards": "1" } }`)},
+		}},
+		`{"template":{"settings":{"index":{"codec":"best_compression","number_of_shards":"1"}}}}`),
+	Entry("When the mappings are given in another key order",
+		v1.OpensearchComponentTemplateSpec{Template: v1.OpensearchIndexSpec{
+			Mappings: &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"b":{"type":"long"},"a":{"type":"keyword","ignore_above":256}}}`)},
+		}},
+		v1.OpensearchComponentTemplateSpec{Template: v1.OpensearchIndexSpec{
+			Mappings: &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"a":{"ignore_above":256,"type":"keyword"},"b":{"type":"long"}}}`)},
+		}},
+		`{"template":{"mappings":{"properties":{"a":{"ignore_above":256,"type":"keyword"},"b":{"type":"long"}}}}}`),
+	Entry("When the meta data and alias filters are given in another key order",
+		v1.OpensearchComponentTemplateSpec{
+			Template: v1.OpensearchIndexSpec{Aliases: map[string]v1.OpensearchIndexAliasSpec{
+				"recent": {Filter: &apiextensionsv1.JSON{Raw: []byte(`{"range":{"@timestamp":{"lt":"now","gte":"now-1d"}}}`)}},
+				"all":    {},
+			}},
+			Meta: &apiextensionsv1.JSON{Raw: []byte(`{"owner":"team-a","description":"logs of the last day"}`)},
+		},
+		v1.OpensearchComponentTemplateSpec{
+			Template: v1.OpensearchIndexSpec{Aliases: map[string]v1.OpensearchIndexAliasSpec{
+				"all":    {},
+				"recent": {Filter: &apiextensionsv1.JSON{Raw: []byte(`{"range":{"@timestamp":{"gte":"now-1d","lt":"now"}}}`)}},
+			}},
+			Meta: &apiextensionsv1.JSON{Raw: []byte(`{"description":"logs of the last day","owner":"team-a"}`)},
+		},
+		`{"template":{"aliases":{"all":{},"recent":{"filter":{"range":{"@timestamp":{"gte":"now-1d","lt":"now"}}}}}},"_meta":{"description":"logs of the last day","owner":"team-a"}}`),
+	Entry("When the settings hold numbers",
+		v1.OpensearchComponentTemplateSpec{Template: v1.OpensearchIndexSpec{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"refresh_interval":"1s","mapping":{"total_fields":{"limit":9007199254740993}}}}`)},
+		}},
+		v1.OpensearchComponentTemplateSpec{Template: v1.OpensearchIndexSpec{
+			Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"mapping":{"total_fields":{"limit":9007199254740993}},"refresh_interval":"1s"}}`)},
+		}},
+		`{"template":{"settings":{"index":{"mapping":{"total_fields":{"limit":9007199254740993}},"refresh_interval":"1s"}}}}`),
+)
+
+var _ = DescribeTable("ingest pipeline comparison",
+	func(processors string, existing string, equal bool) {
+		spec := v1.OpensearchIngestPipelineSpec{Description: "enrich logs"}
+		Expect(json.Unmarshal([]byte(processors), &spec.Processors)).To(Succeed())
+		current := requests.IngestPipeline{Description: "enrich logs"}
+		Expect(json.Unmarshal([]byte(existing), &current.Processors)).To(Succeed())
+		Expect(IngestPipelinesEqual(TranslateIngestPipelineToRequest(spec), current)).To(Equal(equal))
+	},
+	Entry("When the processors are the same", `[{"set":{"field":"env","value":"prod"}}]`, `[{"set":{"field":"env","value":"prod"}}]`, true),
+	Entry("When the processors are returned in another key order",
+		`[{"set":{"value":"prod","field":"env"}},{"lowercase":{"field":"level"}}]`,
+		`[{"set":{"field":"env","value":"prod"}},{"lowercase":{"field":"level"}}]`, true),
+	Entry("When a processor differs", `[{"set":{"field":"env","value":"prod"}}]`, `[{"set":{"field":"env","value":"dev"}}]`, false),
+	Entry("When the processors are in another order",
+		`[{"set":{"field":"env","value":"prod"}},{"lowercase":{"field":"level"}}]`,
+		`[{"lowercase":{"field":"level"}},{"set":{"field":"env","value":"prod"}}]`, false),
+)
+
+var _ = DescribeTable("search pipeline comparison",
+	func(processors string, existing string, equal bool) {
+		spec := v1.OpensearchSearchPipelineSpec{Description: "public documents only"}
+		Expect(json.Unmarshal([]byte(processors), &spec.RequestProcessors)).To(Succeed())
+		current := requests.SearchPipeline{Description: "public documents only"}
+		Expect(json.Unmarshal([]byte(existing), &current.RequestProcessors)).To(Succeed())
+		Expect(SearchPipelinesEqual(TranslateSearchPipelineToRequest(spec), current)).To(Equal(equal))
+	},
+	Entry("When the processors are the same",
+		`[{"filter_query":{"query":{"term":{"visibility":"public"}}}}]`,
+		`[{"filter_query":{"query":{"term":{"visibility":"public"}}}}]`, true),
+	Entry("When the processors are returned in another key order",
+		`[{"filter_query":{"tag":"public","query":{"term":{"visibility":"public"}}}}]`,
+		`[{"filter_query":{"query":{"term":{"visibility":"public"}},"tag":"public"}}]`, true),
+	Entry("When a processor differs",
+		`[{"filter_query":{"query":{"term":{"visibility":"public"}}}}]`,
+		`[{"filter_query":{"query":{"term":{"visibility":"private"}}}}]`, false),
+	Entry("When the pipeline has no processors", `[]`, `null`, true),
+)
+
+var _ = DescribeTable("stored script request bodies",
+	func(spec v1.OpensearchStoredScriptSpec, expected string) {
+		raw, err := json.Marshal(TranslateStoredScriptToRequest(spec))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raw).To(MatchJSON(expected))
+	},
+	Entry("When the language is not set", v1.OpensearchStoredScriptSpec{Source: "doc['price'].value"},
+		`{"lang":"painless","source":"doc['price'].value"}`),
+	Entry("When the script has options", v1.OpensearchStoredScriptSpec{
+		Lang:    "mustache",
+		Source:  `{"query":{"match":{"title":"{{query}}"}}}`,
+		Options: map[string]string{"content_type": "application/json"},
+	}, `{"lang":"mustache","source":"{\"query\":{\"match\":{\"title\":\"{{query}}\"}}}","options":{"content_type":"application/json"}}`),
+)
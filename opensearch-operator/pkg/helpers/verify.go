@@ -0,0 +1,101 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// IndexMismatches compares the settings and mappings of a template with the flat settings and the mappings of an
+// index created from it. It returns a sorted description of every setting and mapping of the template the index does
+// not have, settings in ignoredSettings (in flat notation with the index. prefix) are not compared. Values OpenSearch
+// only reformats, e.g. numbers returned as strings or equal time values in different units, are not reported. The
+// values of paths the redaction redacts are not included in the descriptions.
+func IndexMismatches(expected requests.Index, settings map[string]interface{}, mappings *apiextensionsv1.JSON, redaction *Redaction, ignoredSettings ...string) ([]string, error) {
+	var mismatches []string
+
+	if expected.Settings.Size() > 0 {
+		parsed := map[string]interface{}{}
+		if err := UnmarshalPreservingNumbers(expected.Settings.Raw, &parsed); err != nil {
+			return nil, err
+		}
+		flat := map[string]interface{}{}
+		flattenSettings("", parsed, flat)
+		for key, value := range flat {
+			if !strings.HasPrefix(key, "index.") {
+				key = "index." + key
+			}
+			if ContainsString(ignoredSettings, key) {
+				continue
+			}
+			actual, ok := settings[key]
+			if !ok {
+				mismatches = append(mismatches, fmt.Sprintf("setting %s is missing", key))
+			} else if !settingValuesEqual(value, actual) && redaction.Covers("template.settings."+key) {
+				mismatches = append(mismatches, fmt.Sprintf("setting %s differs", key))
+			} else if !settingValuesEqual(value, actual) {
+				mismatches = append(mismatches, fmt.Sprintf("setting %s is %v instead of %v", key, actual, value))
+			}
+		}
+	}
+
+	if expected.Mappings.Size() > 0 {
+		var expectedMappings, actualMappings interface{}
+		if err := UnmarshalPreservingNumbers(expected.Mappings.Raw, &expectedMappings); err != nil {
+			return nil, err
+		}
+		if mappings.Size() > 0 {
+			if err := UnmarshalPreservingNumbers(mappings.Raw, &actualMappings); err != nil {
+				return nil, err
+			}
+		}
+		collectMappingMismatches("", expectedMappings, actualMappings, redaction, &mismatches)
+	}
+
+	sort.Strings(mismatches)
+	return mismatches, nil
+}
+
+// settingValuesEqual compares setting values in their string form, as OpenSearch returns all settings as strings
+func settingValuesEqual(expected interface{}, actual interface{}) bool {
+	if fmt.Sprint(expected) == fmt.Sprint(actual) {
+		return true
+	}
+	expectedDuration, err := ParseTimeValue(fmt.Sprint(expected))
+	if err != nil {
+		return false
+	}
+	actualDuration, err := ParseTimeValue(fmt.Sprint(actual))
+	return err == nil && expectedDuration == actualDuration
+}
+
+// collectMappingMismatches reports every key of expected that is missing in actual or has another value. Keys only
+// present in actual, e.g. defaults added by OpenSearch, are ignored.
+func collectMappingMismatches(path string, expected interface{}, actual interface{}, redaction *Redaction, mismatches *[]string) {
+	expectedObject, ok := expected.(map[string]interface{})
+	if !ok {
+		if reflect.DeepEqual(expected, actual) || fmt.Sprint(expected) == fmt.Sprint(actual) {
+			return
+		}
+		if redaction.Covers("template.mappings." + path) {
+			*mismatches = append(*mismatches, fmt.Sprintf("mapping %s differs", path))
+		} else {
+			*mismatches = append(*mismatches, fmt.Sprintf("mapping %s is %v instead of %v", path, actual, expected))
+		}
+		return
+	}
+	actualObject, _ := actual.(map[string]interface{})
+	for key, expectedNested := range expectedObject {
+		nestedPath := joinJSONPath(path, key)
+		actualNested, ok := actualObject[key]
+		if !ok {
+			*mismatches = append(*mismatches, fmt.Sprintf("mapping %s is missing", nestedPath))
+			continue
+		}
+		collectMappingMismatches(nestedPath, expectedNested, actualNested, redaction, mismatches)
+	}
+}
@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = DescribeTable("index mismatches",
+	func(settings string, mappings string, expected []string) {
+		template := requests.Index{}
+		if settings != "" {
+			template.Settings = &apiextensionsv1.JSON{Raw: []byte(settings)}
+		}
+		if mappings != "" {
+			template.Mappings = &apiextensionsv1.JSON{Raw: []byte(mappings)}
+		}
+		indexSettings := map[string]interface{}{
+			"index.number_of_shards":   "2",
+			"index.number_of_replicas": "0",
+			"index.refresh_interval":   "30000ms",
+			"index.codec":              "best_compression",
+		}
+		indexMappings := &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"message":{"type":"text","norms":false},"count":{"type":"long"}}}`)}
+
+		mismatches, err := IndexMismatches(template, indexSettings, indexMappings, nil, "index.number_of_replicas")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mismatches).To(Equal(expected))
+	},
+	Entry("When the index matches the template", `{"index":{"number_of_shards":2,"number_of_replicas":1},"codec":"best_compression"}`,
+		`{"properties":{"message":{"type":"text"}}}`, nil),
+	Entry("When time values only differ in their unit", `{"index.refresh_interval":"30s"}`, "", nil),
+	Entry("When a setting differs", `{"index":{"number_of_shards":"1"}}`, "",
+		[]string{"setting index.number_of_shards is 2 instead of 1"}),
+	Entry("When a setting is missing", `{"index":{"hidden":true}}`, "",
+		[]string{"setting index.hidden is missing"}),
+	Entry("When mappings differ", "", `{"properties":{"message":{"type":"keyword"},"level":{"type":"keyword"}}}`,
+		[]string{"mapping properties.level is missing", "mapping properties.message.type is text instead of keyword"}),
+	Entry("When the template has no settings or mappings", "", "", nil),
+)
@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	DefaultZoneTopologyKey = "topology.kubernetes.io/zone"
+	DefaultZoneAttribute   = "zone"
+)
+
+// ZoneAwarenessEnabled returns true if the pods of the node pool are spread across zones
+func ZoneAwarenessEnabled(nodePool *opsterv1.NodePool) bool {
+	return nodePool.ZoneAwareness != nil && nodePool.ZoneAwareness.Enable
+}
+
+// ZoneTopologyKey returns the label of the Kubernetes nodes holding their zone
+func ZoneTopologyKey(config *opsterv1.ZoneAwarenessConfig) string {
+	if config.TopologyKey == "" {
+		return DefaultZoneTopologyKey
+	}
+	return config.TopologyKey
+}
+
+// ZoneAttribute returns the name of the OpenSearch node attribute holding the zone
+func ZoneAttribute(config *opsterv1.ZoneAwarenessConfig) string {
+	if config.Attribute == "" {
+		return DefaultZoneAttribute
+	}
+	return config.Attribute
+}
+
+// ZoneAwarenessSettings returns the allocation awareness settings of the cluster for the node pools with zone
+// awareness, no settings if no pool enables it. The forced zones of all pools using an attribute are merged
+func ZoneAwarenessSettings(nodePools []opsterv1.NodePool) map[string]string {
+	forcedZones := map[string][]string{}
+	for _, nodePool := range nodePools {
+		if !ZoneAwarenessEnabled(&nodePool) {
+			continue
+		}
+		attribute := ZoneAttribute(nodePool.ZoneAwareness)
+		forcedZones[attribute] = append(forcedZones[attribute], nodePool.ZoneAwareness.ForcedZones...)
+	}
+	if len(forcedZones) == 0 {
+		return map[string]string{}
+	}
+
+	settings := map[string]string{}
+	attributes := make([]string, 0, len(forcedZones))
+	for attribute := range forcedZones {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+	settings["cluster.routing.allocation.awareness.attributes"] = strings.Join(attributes, ",")
+	for _, attribute := range attributes {
+		zones := RemoveDuplicateStrings(forcedZones[attribute])
+		if len(zones) == 0 {
+			continue
+		}
+		sort.Strings(zones)
+		settings[fmt.Sprintf("cluster.routing.allocation.awareness.force.%s.values", attribute)] = strings.Join(zones, ",")
+	}
+	return settings
+}
+
+// ZoneTopologySpreadConstraints returns the constraints with one spreading the pods matching the labels across the
+// zones of the config added, unless the constraints already spread across the topology key
+func ZoneTopologySpreadConstraints(
+	constraints []corev1.TopologySpreadConstraint,
+	config *opsterv1.ZoneAwarenessConfig,
+	matchLabels map[string]string,
+) []corev1.TopologySpreadConstraint {
+	topologyKey := ZoneTopologyKey(config)
+	for _, constraint := range constraints {
+		if constraint.TopologyKey == topologyKey {
+			return constraints
+		}
+	}
+	maxSkew := config.MaxSkew
+	if maxSkew < 1 {
+		maxSkew = 1
+	}
+	whenUnsatisfiable := config.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.DoNotSchedule
+	}
+	result := append([]corev1.TopologySpreadConstraint{}, constraints...)
+	return append(result, corev1.TopologySpreadConstraint{
+		MaxSkew:           maxSkew,
+		TopologyKey:       topologyKey,
+		WhenUnsatisfiable: whenUnsatisfiable,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: matchLabels},
+	})
+}
@@ -0,0 +1,57 @@
+package helpers
+
+import (
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("zone awareness", func() {
+	It("sets no allocation awareness without zone aware node pools", func() {
+		nodePools := []opsterv1.NodePool{
+			{Component: "masters"},
+			{Component: "data", ZoneAwareness: &opsterv1.ZoneAwarenessConfig{Enable: false}},
+		}
+		Expect(ZoneAwarenessSettings(nodePools)).To(BeEmpty())
+	})
+
+	It("sets the awareness attributes of all zone aware node pools", func() {
+		nodePools := []opsterv1.NodePool{
+			{Component: "masters", ZoneAwareness: &opsterv1.ZoneAwarenessConfig{Enable: true}},
+			{Component: "data", ZoneAwareness: &opsterv1.ZoneAwarenessConfig{Enable: true, Attribute: "rack"}},
+		}
+		Expect(ZoneAwarenessSettings(nodePools)).To(Equal(map[string]string{
+			"cluster.routing.allocation.awareness.attributes": "rack,zone",
+		}))
+	})
+
+	It("merges the forced zones of the node pools", func() {
+		nodePools := []opsterv1.NodePool{
+			{Component: "masters", ZoneAwareness: &opsterv1.ZoneAwarenessConfig{Enable: true, ForcedZones: []string{"zone-b", "zone-a"}}},
+			{Component: "data", ZoneAwareness: &opsterv1.ZoneAwarenessConfig{Enable: true, ForcedZones: []string{"zone-c", "zone-a"}}},
+		}
+		Expect(ZoneAwarenessSettings(nodePools)).To(Equal(map[string]string{
+			"cluster.routing.allocation.awareness.attributes":        "zone",
+			"cluster.routing.allocation.awareness.force.zone.values": "zone-a,zone-b,zone-c",
+		}))
+	})
+
+	It("spreads the pods across the zones", func() {
+		labels := map[string]string{"opster.io/opensearch-nodepool": "data"}
+		constraints := ZoneTopologySpreadConstraints(nil, &opsterv1.ZoneAwarenessConfig{Enable: true}, labels)
+		Expect(constraints).To(HaveLen(1))
+		Expect(constraints[0].TopologyKey).To(Equal(DefaultZoneTopologyKey))
+		Expect(constraints[0].MaxSkew).To(Equal(int32(1)))
+		Expect(constraints[0].WhenUnsatisfiable).To(Equal(corev1.DoNotSchedule))
+		Expect(constraints[0].LabelSelector.MatchLabels).To(Equal(labels))
+	})
+
+	It("keeps a constraint of the node pool on the same topology key", func() {
+		existing := []corev1.TopologySpreadConstraint{
+			{MaxSkew: 2, TopologyKey: "example.com/rack", WhenUnsatisfiable: corev1.ScheduleAnyway},
+		}
+		config := &opsterv1.ZoneAwarenessConfig{Enable: true, TopologyKey: "example.com/rack"}
+		Expect(ZoneTopologySpreadConstraints(existing, config, nil)).To(Equal(existing))
+	})
+})
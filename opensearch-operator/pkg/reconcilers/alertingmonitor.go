@@ -0,0 +1,335 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchMonitorExists       = "monitor already exists in OpenSearch; not modifying"
+	opensearchMonitorNameMismatch = "OpensearchAlertingMonitorNameMismatch"
+	opensearchInvalidMonitor      = "OpensearchInvalidAlertingMonitor"
+)
+
+type AlertingMonitorReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchAlertingMonitor
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewAlertingMonitorReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchAlertingMonitor,
+	opts ...ReconcilerOption,
+) *AlertingMonitorReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &AlertingMonitorReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "alertingmonitor"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "alertingmonitor"),
+	}
+}
+
+func (r *AlertingMonitorReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var monitorName string
+	var monitorID string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchAlertingMonitor)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchAlertingMonitorError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchAlertingMonitorPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchAlertingMonitorDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchAlertingMonitorCreated
+				instance.Status.MonitorName = monitorName
+				instance.Status.MonitorID = monitorID
+			}
+			if reason == opensearchMonitorExists {
+				instance.Status.State = opsterv1.OpensearchAlertingMonitorIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a monitor refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchAlertingMonitor)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	monitorName = r.instance.Name
+	if r.instance.Spec.Name != "" {
+		monitorName = r.instance.Spec.Name
+	}
+
+	// Check monitor state to make sure we don't touch preexisting monitors
+	if r.instance.Status.ExistingMonitor == nil {
+		var existingID string
+		existingID, err = services.FindMonitorID(r.ctx, r.osClient, monitorName)
+		if err != nil {
+			reason = "failed to get monitor status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		exists := existingID != ""
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchAlertingMonitor)
+				instance.Status.ExistingMonitor = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If monitor is existing do nothing
+	if *r.instance.Status.ExistingMonitor {
+		reason = opensearchMonitorExists
+		return
+	}
+
+	// the monitor name is immutable, so check the old name (r.instance.Status.MonitorName) against the new
+	if r.instance.Status.MonitorName != "" && monitorName != r.instance.Status.MonitorName {
+		reason = "cannot change the monitor name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchMonitorNameMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource, err := helpers.TranslateMonitorToRequest(r.instance.Spec, monitorName)
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidMonitor, reason)
+		return
+	}
+
+	// Monitors are addressed by the id OpenSearch generates, fall back to the name if the id was not recorded
+	monitorID = r.instance.Status.MonitorID
+	if monitorID == "" {
+		monitorID, err = services.FindMonitorID(r.ctx, r.osClient, monitorName)
+		if err != nil {
+			reason = "failed to get monitor from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+	}
+
+	var existing *responses.GetMonitorResponse
+	if monitorID != "" {
+		existing, err = services.GetMonitor(r.ctx, r.osClient, monitorID)
+		if err != nil && !errors.Is(err, services.ErrNotFound) {
+			reason = "failed to get monitor from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+	}
+
+	if existing == nil {
+		r.logger.V(1).Info(fmt.Sprintf("monitor %s not found, creating", monitorName))
+		monitorID, err = services.CreateMonitor(r.ctx, r.osClient, resource)
+	} else {
+		var equal bool
+		equal, err = helpers.MonitorsEqual(resource, &existing.Monitor)
+		if err != nil {
+			reason = "failed to compare the monitor with the one in OpenSearch"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if equal {
+			r.logger.V(1).Info(fmt.Sprintf("monitor %s is in sync", r.instance.Name))
+			result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+			return
+		}
+		err = services.UpdateMonitor(r.ctx, r.osClient, monitorID, existing.SeqNo, existing.PrimaryTerm, resource)
+	}
+	if err != nil {
+		reason = "failed to update monitor with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "monitor updated in opensearch")
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+func (r *AlertingMonitorReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingMonitor == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingMonitor {
+		r.logger.Info("monitor was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	monitorName := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		monitorName = r.instance.Spec.Name
+	}
+
+	monitorID := r.instance.Status.MonitorID
+	if monitorID == "" {
+		monitorID, err = services.FindMonitorID(r.ctx, r.osClient, monitorName)
+		if err != nil {
+			return err
+		}
+	}
+	if monitorID == "" {
+		r.logger.V(1).Info("monitor already deleted from opensearch")
+		return nil
+	}
+
+	return services.DeleteMonitor(r.ctx, r.osClient, monitorID)
+}
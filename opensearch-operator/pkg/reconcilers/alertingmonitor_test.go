@@ -0,0 +1,528 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("alertingmonitor reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *AlertingMonitorReconciler
+		instance   *opsterv1.OpensearchAlertingMonitor
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		searchUrl  string
+		monitorUrl string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchAlertingMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-alertingmonitor",
+				Namespace: "test-alertingmonitor",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchAlertingMonitorSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Name:     "my-monitor",
+				Schedule: opsterv1.MonitorSchedule{Period: &opsterv1.MonitorPeriod{Interval: 1}},
+				Inputs: []apiextensionsv1.JSON{
+					{Raw: []byte(`{"search":{"indices":["logs-*"],"query":{"query":{"term":{"level":"error"}}}}}`)},
+				},
+				Triggers: []opsterv1.MonitorTrigger{{
+					Name:      "errors",
+					Condition: apiextensionsv1.JSON{Raw: []byte(`{"script":{"source":"ctx.results[0].hits.total.value > 0"}}`)},
+				}},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-alertingmonitor",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		searchUrl = fmt.Sprintf("%s_plugins/_alerting/monitors/_search", clusterUrl)
+		monitorUrl = fmt.Sprintf("%s_plugins/_alerting/monitors/abc123", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &AlertingMonitorReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a monitor refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+			})
+
+			When("a monitor with the name exists", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[{"_id":"abc123","_source":{"name":"my-monitor"}}]}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the monitor exists", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+				})
+			})
+
+			When("only a monitor containing the name exists", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[{"_id":"def456","_source":{"name":"my-monitor copy"}}]}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the monitor does not exist", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is false"}))
+				})
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingMonitor = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingMonitor = pointer.Bool(false)
+				instance.Status.MonitorID = "abc123"
+			})
+
+			When("monitor exists in opensearch and is the same", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						monitorUrl,
+						httpmock.NewStringResponder(200, `{"_id":"abc123","_seq_no":3,"_primary_term":1,"monitor":{
+							"type": "monitor",
+							"schema_version": 8,
+							"monitor_type": "query_level_monitor",
+							"name": "my-monitor",
+							"enabled": true,
+							"enabled_time": 1700000000000,
+							"schedule": {"period": {"interval": 1, "unit": "MINUTES"}},
+							"inputs": [{"search": {"indices": ["logs-*"], "query": {"query": {"term": {"level": {"value": "error", "boost": 1.0}}}}}}],
+							"triggers": [{"query_level_trigger": {
+								"id": "xyz",
+								"name": "errors",
+								"severity": "1",
+								"condition": {"script": {"source": "ctx.results[0].hits.total.value > 0", "lang": "painless"}},
+								"actions": []
+							}}]
+						}}`).Once(failMessage),
+					)
+				})
+
+				It("should do nothing", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				})
+			})
+
+			When("monitor exists in opensearch and is not the same", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						monitorUrl,
+						httpmock.NewStringResponder(200, `{"_id":"abc123","_seq_no":3,"_primary_term":1,"monitor":{
+							"type": "monitor",
+							"monitor_type": "query_level_monitor",
+							"name": "my-monitor",
+							"enabled": false,
+							"schedule": {"period": {"interval": 1, "unit": "MINUTES"}},
+							"inputs": [{"search": {"indices": ["logs-*"], "query": {"query": {"term": {"level": {"value": "error", "boost": 1.0}}}}}}],
+							"triggers": []
+						}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						monitorUrl+"?if_seq_no=3&if_primary_term=1",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"_id":"abc123"}`), nil
+						},
+					)
+				})
+
+				It("should update the monitor", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						// Confirm all responders have been called
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s monitor updated in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{
+						"type": "monitor",
+						"monitor_type": "query_level_monitor",
+						"name": "my-monitor",
+						"enabled": true,
+						"schedule": {"period": {"interval": 1, "unit": "MINUTES"}},
+						"inputs": [{"search": {"indices": ["logs-*"], "query": {"query": {"term": {"level": "error"}}}}}],
+						"triggers": [{"query_level_trigger": {
+							"name": "errors",
+							"severity": "1",
+							"condition": {"script": {"source": "ctx.results[0].hits.total.value > 0"}},
+							"actions": []
+						}}]
+					}`))
+				})
+			})
+
+			When("monitor was deleted in opensearch", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						monitorUrl,
+						httpmock.NewStringResponder(404, "{}").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						clusterUrl+"_plugins/_alerting/monitors",
+						httpmock.NewStringResponder(201, `{"_id":"ghi789","_seq_no":0,"_primary_term":1}`).Once(failMessage),
+					)
+				})
+
+				It("should create the monitor again", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s monitor updated in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("the id of the monitor was not recorded and it does not exist", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.MonitorID = ""
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(404, `{"error":{"type":"index_not_found_exception"}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						clusterUrl+"_plugins/_alerting/monitors",
+						httpmock.NewStringResponder(201, `{"_id":"ghi789","_seq_no":0,"_primary_term":1}`).Once(failMessage),
+					)
+				})
+
+				It("should create the monitor", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s monitor updated in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("the schedule is invalid", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Schedule.Cron = &opsterv1.CronSchedule{Expression: "0 * * * *"}
+				})
+
+				It("should fail without calling OpenSearch", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf(
+						"Warning %s exactly one of period and cron has to be set in the schedule", opensearchInvalidMonitor,
+					)}))
+				})
+			})
+
+			When("the name of the monitor has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.MonitorName = "my-monitor"
+					instance.Spec.Name = "new-monitor"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the monitor name", opensearchMonitorNameMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingMonitor = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingMonitor = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			Context("cluster exists", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				When("monitor does not exist", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodPost,
+							searchUrl,
+							httpmock.NewStringResponder(200, `{"hits":{"hits":[]}}`).Once(failMessage),
+						)
+					})
+
+					It("should do nothing and exit", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+
+				When("the id of the monitor was recorded", func() {
+					BeforeEach(func() {
+						instance.Status.MonitorID = "abc123"
+						transport.RegisterResponder(
+							http.MethodDelete,
+							monitorUrl,
+							httpmock.NewStringResponder(200, `{"_id":"abc123"}`).Once(failMessage),
+						)
+					})
+
+					It("should delete the monitor", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+			})
+		})
+	})
+})
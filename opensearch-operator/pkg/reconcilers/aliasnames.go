@@ -0,0 +1,45 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+)
+
+const opensearchAliasCollision = "OpensearchComponentTemplateAliasCollision"
+
+// checkAliasNames fails the reconcile if an alias of the template has the name of an existing index, as OpenSearch
+// would reject creating indices from the template with an invalid_alias_name_exception. Alias names using the
+// {index} placeholder depend on the created index and are not checked.
+func (r *ComponentTemplateReconciler) checkAliasNames(template requests.ComponentTemplate) (string, error) {
+	var names []string
+	for name := range template.Template.Aliases {
+		if !strings.Contains(name, "{index}") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	colliding, err := services.ExistingIndices(r.ctx, r.osClient, names)
+	if errors.Is(err, services.ErrForbidden) {
+		return r.forbidden(catIndicesPrivilege), err
+	}
+	if err != nil {
+		reason := "failed to get indices from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return reason, err
+	}
+	if len(colliding) == 0 {
+		return "", nil
+	}
+
+	reason := fmt.Sprintf("aliases of the component template have the names of existing indices: %s", strings.Join(colliding, ", "))
+	r.recorder.Event(r.instance, "Warning", opensearchAliasCollision, reason)
+	return reason, errors.New(reason)
+}
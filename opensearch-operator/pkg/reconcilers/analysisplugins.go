@@ -0,0 +1,53 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+const opensearchMissingAnalysisPlugin = "OpensearchComponentTemplateMissingAnalysisPlugin"
+
+// checkAnalysisPlugins verifies that the plugins providing the analysis components referenced in the template settings
+// are installed on all nodes. Unless the spec ignores missing plugins the reconcile fails if one is missing.
+func (r *ComponentTemplateReconciler) checkAnalysisPlugins(spec opsterv1.OpensearchComponentTemplateSpec, template requests.ComponentTemplate) (string, error) {
+	plugins, err := helpers.AnalysisPlugins(template.Template.Settings)
+	if err != nil {
+		reason := "failed to parse component template settings"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	if len(plugins) == 0 {
+		return "", nil
+	}
+	if serverlessEndpoint(r.cluster) {
+		r.logger.V(1).Info("serverless endpoint has no nodes API, not checking the analysis plugins", "plugins", plugins)
+		return "", nil
+	}
+
+	missing, err := services.MissingPlugins(r.ctx, r.osClient, plugins)
+	if errors.Is(err, services.ErrForbidden) {
+		return r.forbidden(nodesInfoPrivilege), err
+	}
+	if err != nil {
+		reason := "failed to get installed plugins from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return reason, err
+	}
+	if len(missing) == 0 {
+		return "", nil
+	}
+
+	reason := fmt.Sprintf("component template uses analysis components of plugins that are not installed on all nodes: %s", strings.Join(missing, ", "))
+	r.recorder.Event(r.instance, "Warning", opensearchMissingAnalysisPlugin, reason)
+	if spec.IgnoreMissingAnalysisPlugins {
+		return "", nil
+	}
+	return reason, errors.New(reason)
+}
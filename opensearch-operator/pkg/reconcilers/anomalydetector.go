@@ -0,0 +1,412 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchDetectorExists       = "detector already exists in OpenSearch; not modifying"
+	opensearchDetectorNameMismatch = "OpensearchAnomalyDetectorNameMismatch"
+	opensearchInvalidDetector      = "OpensearchInvalidAnomalyDetector"
+)
+
+type AnomalyDetectorReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchAnomalyDetector
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewAnomalyDetectorReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchAnomalyDetector,
+	opts ...ReconcilerOption,
+) *AnomalyDetectorReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &AnomalyDetectorReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "anomalydetector"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "anomalydetector"),
+	}
+}
+
+func (r *AnomalyDetectorReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var detectorName string
+	var detectorID string
+	var job *opsterv1.DetectorJobStatus
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchAnomalyDetector)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchAnomalyDetectorError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchAnomalyDetectorPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchAnomalyDetectorDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchAnomalyDetectorCreated
+				instance.Status.DetectorName = detectorName
+				instance.Status.DetectorID = detectorID
+				if job != nil {
+					instance.Status.Job = job
+				}
+			}
+			if reason == opensearchDetectorExists {
+				instance.Status.State = opsterv1.OpensearchAnomalyDetectorIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a detector refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchAnomalyDetector)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	detectorName = r.instance.Name
+	if r.instance.Spec.Name != "" {
+		detectorName = r.instance.Spec.Name
+	}
+
+	// Check detector state to make sure we don't touch preexisting detectors
+	if r.instance.Status.ExistingDetector == nil {
+		var existingID string
+		existingID, err = services.FindDetectorID(r.ctx, r.osClient, detectorName)
+		if err != nil {
+			reason = "failed to get detector status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		exists := existingID != ""
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchAnomalyDetector)
+				instance.Status.ExistingDetector = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If detector is existing do nothing
+	if *r.instance.Status.ExistingDetector {
+		reason = opensearchDetectorExists
+		return
+	}
+
+	// the detector name is immutable, so check the old name (r.instance.Status.DetectorName) against the new
+	if r.instance.Status.DetectorName != "" && detectorName != r.instance.Status.DetectorName {
+		reason = "cannot change the detector name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchDetectorNameMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource, err := helpers.TranslateDetectorToRequest(r.instance.Spec, detectorName)
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidDetector, reason)
+		return
+	}
+	enabled := pointer.BoolDeref(r.instance.Spec.Enabled, true)
+
+	// Detectors are addressed by the id OpenSearch generates, fall back to the name if the id was not recorded
+	detectorID = r.instance.Status.DetectorID
+	if detectorID == "" {
+		detectorID, err = services.FindDetectorID(r.ctx, r.osClient, detectorName)
+		if err != nil {
+			reason = "failed to get detector from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+	}
+
+	var existing *responses.GetDetectorResponse
+	if detectorID != "" {
+		existing, err = services.GetDetector(r.ctx, r.osClient, detectorID)
+		if err != nil && !errors.Is(err, services.ErrNotFound) {
+			reason = "failed to get detector from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+	}
+
+	running := false
+	if existing == nil {
+		r.logger.V(1).Info(fmt.Sprintf("detector %s not found, creating", detectorName))
+		detectorID, err = services.CreateDetector(r.ctx, r.osClient, resource)
+		if err != nil {
+			reason = "failed to create detector with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "detector created in opensearch")
+	} else {
+		running = existing.Job != nil && existing.Job.Enabled
+		var equal bool
+		equal, err = helpers.DetectorsEqual(resource, &existing.Detector)
+		if err != nil {
+			reason = "failed to compare the detector with the one in OpenSearch"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if !equal {
+			// OpenSearch rejects updates of running detectors, so the job is stopped first and started again below
+			if running {
+				err = services.StopDetector(r.ctx, r.osClient, detectorID)
+				if err != nil {
+					reason = "failed to stop detector with OpenSearch API"
+					r.logger.Error(err, reason)
+					r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+					return
+				}
+				running = false
+			}
+			err = services.UpdateDetector(r.ctx, r.osClient, detectorID, existing.SeqNo, existing.PrimaryTerm, resource)
+			if err != nil {
+				reason = "failed to update detector with OpenSearch API"
+				r.logger.Error(err, reason)
+				r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+				return
+			}
+			r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "detector updated in opensearch")
+		} else {
+			r.logger.V(1).Info(fmt.Sprintf("detector %s is in sync", r.instance.Name))
+		}
+	}
+
+	// Detectors are started and stopped through their own endpoints
+	if enabled != running {
+		if enabled {
+			err = services.StartDetector(r.ctx, r.osClient, detectorID)
+		} else {
+			err = services.StopDetector(r.ctx, r.osClient, detectorID)
+		}
+		if err != nil {
+			reason = "failed to start or stop detector with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		action := "stopped"
+		if enabled {
+			action = "started"
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, fmt.Sprintf("detector %s in opensearch", action))
+	}
+
+	// Report the state of the job, failing to fetch it does not fail the reconciliation
+	profile, profileErr := services.DetectorProfile(r.ctx, r.osClient, detectorID)
+	if profileErr != nil {
+		r.logger.Error(profileErr, "failed to get the profile of the detector from OpenSearch API")
+	} else {
+		job = detectorJobStatus(profile)
+	}
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+// detectorJobStatus converts the profile OpenSearch reports for a detector to the status of the resource
+func detectorJobStatus(profile *responses.DetectorProfileResponse) *opsterv1.DetectorJobStatus {
+	status := &opsterv1.DetectorJobStatus{
+		State: profile.State,
+		Error: profile.Error,
+	}
+	if profile.InitProgress != nil {
+		status.InitProgress = profile.InitProgress.Percentage
+	}
+	return status
+}
+
+func (r *AnomalyDetectorReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingDetector == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingDetector {
+		r.logger.Info("detector was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	detectorName := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		detectorName = r.instance.Spec.Name
+	}
+
+	detectorID := r.instance.Status.DetectorID
+	if detectorID == "" {
+		detectorID, err = services.FindDetectorID(r.ctx, r.osClient, detectorName)
+		if err != nil {
+			return err
+		}
+	}
+	if detectorID == "" {
+		r.logger.V(1).Info("detector already deleted from opensearch")
+		return nil
+	}
+
+	// OpenSearch rejects deleting running detectors
+	existing, err := services.GetDetector(r.ctx, r.osClient, detectorID)
+	if errors.Is(err, services.ErrNotFound) {
+		r.logger.V(1).Info("detector already deleted from opensearch")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if existing.Job != nil && existing.Job.Enabled {
+		if err := services.StopDetector(r.ctx, r.osClient, detectorID); err != nil {
+			return err
+		}
+	}
+
+	return services.DeleteDetector(r.ctx, r.osClient, detectorID)
+}
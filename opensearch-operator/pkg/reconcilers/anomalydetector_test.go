@@ -0,0 +1,638 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("anomalydetector reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *AnomalyDetectorReconciler
+		instance   *opsterv1.OpensearchAnomalyDetector
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster     *opsterv1.OpenSearchCluster
+		clusterUrl  string
+		searchUrl   string
+		detectorUrl string
+	)
+
+	// detector as OpenSearch returns it for the spec of the instance
+	const existingDetector = `{
+		"name": "bytes-per-host",
+		"description": "",
+		"time_field": "@timestamp",
+		"indices": ["logs-*"],
+		"filter_query": {"match_all": {"boost": 1.0}},
+		"feature_attributes": [{
+			"feature_id": "Zx6hMIwB",
+			"feature_name": "bytes",
+			"feature_enabled": true,
+			"aggregation_query": {"total_bytes": {"sum": {"field": "bytes"}}}
+		}],
+		"detection_interval": {"period": {"interval": 10, "unit": "Minutes"}},
+		"window_delay": {"period": {"interval": 1, "unit": "Minutes"}},
+		"category_field": ["host"],
+		"shingle_size": 8,
+		"schema_version": 0,
+		"last_update_time": 1700000000000
+	}`
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchAnomalyDetector{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-anomalydetector",
+				Namespace: "test-anomalydetector",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchAnomalyDetectorSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Name:      "bytes-per-host",
+				Indices:   []string{"logs-*"},
+				TimeField: "@timestamp",
+				Features: []opsterv1.DetectorFeature{{
+					Name:             "bytes",
+					AggregationQuery: apiextensionsv1.JSON{Raw: []byte(`{"total_bytes":{"sum":{"field":"bytes"}}}`)},
+				}},
+				DetectionInterval: "10m",
+				WindowDelay:       "1m",
+				CategoryFields:    []string{"host"},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-anomalydetector",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		searchUrl = fmt.Sprintf("%s_plugins/_anomaly_detection/detectors/_search", clusterUrl)
+		detectorUrl = fmt.Sprintf("%s_plugins/_anomaly_detection/detectors/abc123", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &AnomalyDetectorReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a detector refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+			})
+
+			When("a detector with the name exists", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[{"_id":"abc123","_source":{"name":"bytes-per-host"}}]}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the detector exists", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+				})
+			})
+
+			When("the index of the detectors does not exist", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(404, `{"error":{"type":"index_not_found_exception"}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the detector does not exist", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is false"}))
+				})
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDetector = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDetector = pointer.Bool(false)
+				instance.Status.DetectorID = "abc123"
+			})
+
+			When("detector is running and the same", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl+"?job=true",
+						httpmock.NewStringResponder(200, `{"_id":"abc123","_seq_no":3,"_primary_term":1,"anomaly_detector":`+existingDetector+`,
+							"anomaly_detector_job":{"name":"abc123","enabled":true}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl+"/_profile/state,error,init_progress",
+						httpmock.NewStringResponder(200, `{"state":"INIT","init_progress":{"percentage":"70%","estimated_minutes_left":30,"needed_shingles":3}}`).Once(failMessage),
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+						f(obj)
+						return nil
+					})
+				})
+
+				It("should only report the state of the job", func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchAnomalyDetectorCreated))
+					Expect(instance.Status.DetectorID).To(Equal("abc123"))
+					Expect(instance.Status.Job).To(Equal(&opsterv1.DetectorJobStatus{State: "INIT", InitProgress: "70%"}))
+				})
+			})
+
+			When("detector is running and not the same", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(3)
+					instance.Spec.ShingleSize = pointer.Int(4)
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl+"?job=true",
+						httpmock.NewStringResponder(200, `{"_id":"abc123","_seq_no":3,"_primary_term":1,"anomaly_detector":`+existingDetector+`,
+							"anomaly_detector_job":{"name":"abc123","enabled":true}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						detectorUrl+"/_stop",
+						httpmock.NewStringResponder(200, `{"_id":"abc123"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						detectorUrl+"?if_seq_no=3&if_primary_term=1",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"_id":"abc123"}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						detectorUrl+"/_start",
+						httpmock.NewStringResponder(200, `{"_id":"abc123"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl+"/_profile/state,error,init_progress",
+						httpmock.NewStringResponder(200, `{"state":"INIT"}`).Once(failMessage),
+					)
+				})
+
+				It("should stop the detector, update it and start it again", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						// Confirm all responders have been called
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Normal %s detector updated in opensearch", opensearchAPIUpdated),
+						fmt.Sprintf("Normal %s detector started in opensearch", opensearchAPIUpdated),
+					}))
+					Expect(body).To(ContainSubstring(`"shingle_size":4`))
+				})
+			})
+
+			When("detector is running and gets disabled", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Enabled = pointer.Bool(false)
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl+"?job=true",
+						httpmock.NewStringResponder(200, `{"_id":"abc123","_seq_no":3,"_primary_term":1,"anomaly_detector":`+existingDetector+`,
+							"anomaly_detector_job":{"name":"abc123","enabled":true}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						detectorUrl+"/_stop",
+						httpmock.NewStringResponder(200, `{"_id":"abc123"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl+"/_profile/state,error,init_progress",
+						httpmock.NewStringResponder(200, `{"state":"DISABLED"}`).Once(failMessage),
+					)
+				})
+
+				It("should stop the detector", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s detector stopped in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("detector was never started", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl+"?job=true",
+						httpmock.NewStringResponder(200, `{"_id":"abc123","_seq_no":3,"_primary_term":1,"anomaly_detector":`+existingDetector+`}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						detectorUrl+"/_start",
+						httpmock.NewStringResponder(200, `{"_id":"abc123"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl+"/_profile/state,error,init_progress",
+						httpmock.NewStringResponder(500, `{}`).Once(failMessage),
+					)
+				})
+
+				It("should start the detector and ignore the failed profile", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s detector started in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("the id of the detector was not recorded and it does not exist", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Status.DetectorID = ""
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[]}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						clusterUrl+"_plugins/_anomaly_detection/detectors",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(201, `{"_id":"ghi789","_seq_no":0,"_primary_term":1}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						clusterUrl+"_plugins/_anomaly_detection/detectors/ghi789/_start",
+						httpmock.NewStringResponder(200, `{"_id":"ghi789"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl+"_plugins/_anomaly_detection/detectors/ghi789/_profile/state,error,init_progress",
+						httpmock.NewStringResponder(200, `{"state":"INIT"}`).Once(failMessage),
+					)
+				})
+
+				It("should create and start the detector", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Normal %s detector created in opensearch", opensearchAPIUpdated),
+						fmt.Sprintf("Normal %s detector started in opensearch", opensearchAPIUpdated),
+					}))
+					Expect(body).To(MatchJSON(`{
+						"name": "bytes-per-host",
+						"time_field": "@timestamp",
+						"indices": ["logs-*"],
+						"feature_attributes": [{
+							"feature_name": "bytes",
+							"feature_enabled": true,
+							"aggregation_query": {"total_bytes": {"sum": {"field": "bytes"}}}
+						}],
+						"detection_interval": {"period": {"interval": 10, "unit": "Minutes"}},
+						"window_delay": {"period": {"interval": 1, "unit": "Minutes"}},
+						"category_field": ["host"],
+						"shingle_size": 8
+					}`))
+				})
+			})
+
+			When("the detection interval is invalid", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.DetectionInterval = "30s"
+				})
+
+				It("should fail without calling OpenSearch", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf(
+						`Warning %s invalid detector: detectionInterval "30s" has to be a positive whole number of minutes, e.g. 10m`, opensearchInvalidDetector,
+					)}))
+				})
+			})
+
+			When("the name of the detector has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.DetectorName = "bytes-per-host"
+					instance.Spec.Name = "new-detector"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the detector name", opensearchDetectorNameMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDetector = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDetector = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			Context("cluster exists", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				When("detector does not exist", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodPost,
+							searchUrl,
+							httpmock.NewStringResponder(200, `{"hits":{"hits":[]}}`).Once(failMessage),
+						)
+					})
+
+					It("should do nothing and exit", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+
+				When("detector is running", func() {
+					BeforeEach(func() {
+						instance.Status.DetectorID = "abc123"
+						transport.RegisterResponder(
+							http.MethodGet,
+							detectorUrl+"?job=true",
+							httpmock.NewStringResponder(200, `{"_id":"abc123","_seq_no":3,"_primary_term":1,"anomaly_detector":`+existingDetector+`,
+								"anomaly_detector_job":{"name":"abc123","enabled":true}}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPost,
+							detectorUrl+"/_stop",
+							httpmock.NewStringResponder(200, `{"_id":"abc123"}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodDelete,
+							detectorUrl,
+							httpmock.NewStringResponder(200, `{"_id":"abc123"}`).Once(failMessage),
+						)
+					})
+
+					It("should stop and delete the detector", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package reconcilers
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ApplyQueue limits the number of concurrent applies to the same cluster.
+// Applies that have to wait are started in order of their priority, higher priorities first
+// and in arrival order for equal priorities.
+type ApplyQueue struct {
+	mu       sync.Mutex
+	limit    int
+	clusters map[types.UID]*clusterApplyQueue
+}
+
+type clusterApplyQueue struct {
+	running int
+	waiting applyWaiters
+	nextSeq uint64
+}
+
+type applyWaiter struct {
+	priority int32
+	seq      uint64
+	ready    chan struct{}
+	// index in the heap, -1 once the waiter has been started
+	index int
+}
+
+// applyWaiters is a heap of waiting applies ordered by priority and arrival
+type applyWaiters []*applyWaiter
+
+func (w applyWaiters) Len() int { return len(w) }
+
+func (w applyWaiters) Less(i, j int) bool {
+	if w[i].priority != w[j].priority {
+		return w[i].priority > w[j].priority
+	}
+	return w[i].seq < w[j].seq
+}
+
+func (w applyWaiters) Swap(i, j int) {
+	w[i], w[j] = w[j], w[i]
+	w[i].index = i
+	w[j].index = j
+}
+
+func (w *applyWaiters) Push(x interface{}) {
+	waiter := x.(*applyWaiter)
+	waiter.index = len(*w)
+	*w = append(*w, waiter)
+}
+
+func (w *applyWaiters) Pop() interface{} {
+	old := *w
+	waiter := old[len(old)-1]
+	old[len(old)-1] = nil
+	waiter.index = -1
+	*w = old[:len(old)-1]
+	return waiter
+}
+
+// NewApplyQueue returns a queue allowing limit concurrent applies per cluster
+func NewApplyQueue(limit int) *ApplyQueue {
+	if limit < 1 {
+		limit = 1
+	}
+	return &ApplyQueue{
+		limit:    limit,
+		clusters: map[types.UID]*clusterApplyQueue{},
+	}
+}
+
+func WithApplyQueue(queue *ApplyQueue) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.applyQueue = queue
+	}
+}
+
+// Acquire blocks until an apply to the cluster may start, the returned function must be called when the apply is done.
+// A nil queue does not limit applies.
+func (q *ApplyQueue) Acquire(ctx context.Context, cluster types.UID, priority int32) (func(), error) {
+	if q == nil {
+		return func() {}, nil
+	}
+
+	q.mu.Lock()
+	clusterQueue, ok := q.clusters[cluster]
+	if !ok {
+		clusterQueue = &clusterApplyQueue{}
+		q.clusters[cluster] = clusterQueue
+	}
+	if clusterQueue.running < q.limit && clusterQueue.waiting.Len() == 0 {
+		clusterQueue.running++
+		q.mu.Unlock()
+		return q.releaseFunc(cluster), nil
+	}
+	waiter := &applyWaiter{priority: priority, seq: clusterQueue.nextSeq, ready: make(chan struct{})}
+	clusterQueue.nextSeq++
+	heap.Push(&clusterQueue.waiting, waiter)
+	q.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return q.releaseFunc(cluster), nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		started := waiter.index < 0
+		if !started {
+			heap.Remove(&clusterQueue.waiting, waiter.index)
+		}
+		q.mu.Unlock()
+		if started {
+			// The apply was started concurrently with the cancellation, hand the slot on
+			q.release(cluster)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (q *ApplyQueue) releaseFunc(cluster types.UID) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { q.release(cluster) })
+	}
+}
+
+// release starts the waiting apply with the highest priority or frees the slot
+func (q *ApplyQueue) release(cluster types.UID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	clusterQueue := q.clusters[cluster]
+	if clusterQueue.waiting.Len() > 0 {
+		waiter := heap.Pop(&clusterQueue.waiting).(*applyWaiter)
+		close(waiter.ready)
+		return
+	}
+	clusterQueue.running--
+	if clusterQueue.running == 0 {
+		delete(q.clusters, cluster)
+	}
+}
+
+// waiting returns the number of applies waiting for the cluster
+func (q *ApplyQueue) waiting(cluster types.UID) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if clusterQueue, ok := q.clusters[cluster]; ok {
+		return clusterQueue.waiting.Len()
+	}
+	return 0
+}
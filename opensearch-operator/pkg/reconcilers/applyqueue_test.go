@@ -0,0 +1,96 @@
+package reconcilers
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("apply queue", func() {
+	const cluster = types.UID("test-cluster")
+
+	var queue *ApplyQueue
+
+	BeforeEach(func() {
+		queue = NewApplyQueue(1)
+	})
+
+	When("no queue is configured", func() {
+		It("should not limit applies", func() {
+			var nilQueue *ApplyQueue
+			release, err := nilQueue.Acquire(context.Background(), cluster, 0)
+			Expect(err).NotTo(HaveOccurred())
+			release()
+		})
+	})
+
+	When("applies to the same cluster have to wait", func() {
+		It("should start them in order of priority", func() {
+			release, err := queue.Acquire(context.Background(), cluster, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			var (
+				mu    sync.Mutex
+				order []string
+				wg    sync.WaitGroup
+			)
+			enqueue := func(name string, priority int32) {
+				waiting := queue.waiting(cluster)
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					release, err := queue.Acquire(context.Background(), cluster, priority)
+					Expect(err).NotTo(HaveOccurred())
+					mu.Lock()
+					order = append(order, name)
+					mu.Unlock()
+					release()
+				}()
+				// Wait until the apply is queued so the arrival order is deterministic
+				Eventually(func() int { return queue.waiting(cluster) }).Should(Equal(waiting + 1))
+			}
+			enqueue("low", 1)
+			enqueue("high-first", 10)
+			enqueue("medium", 5)
+			enqueue("high-second", 10)
+
+			release()
+			wg.Wait()
+			Expect(order).To(Equal([]string{"high-first", "high-second", "medium", "low"}))
+			Expect(queue.clusters).To(BeEmpty())
+		})
+
+		It("should not block applies to other clusters", func() {
+			release, err := queue.Acquire(context.Background(), cluster, 0)
+			Expect(err).NotTo(HaveOccurred())
+			defer release()
+
+			otherRelease, err := queue.Acquire(context.Background(), types.UID("other-cluster"), 0)
+			Expect(err).NotTo(HaveOccurred())
+			otherRelease()
+		})
+
+		It("should drop waiting applies whose context is cancelled", func() {
+			release, err := queue.Acquire(context.Background(), cluster, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() {
+				_, err := queue.Acquire(ctx, cluster, 10)
+				done <- err
+			}()
+			Eventually(func() int { return queue.waiting(cluster) }).Should(Equal(1))
+			cancel()
+			Expect(<-done).To(MatchError(context.Canceled))
+			Expect(queue.waiting(cluster)).To(Equal(0))
+
+			release()
+			Expect(queue.clusters).To(BeEmpty())
+		})
+	})
+})
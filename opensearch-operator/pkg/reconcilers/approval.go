@@ -0,0 +1,77 @@
+package reconcilers
+
+import (
+	"fmt"
+	"strconv"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// opensearchAwaitingApproval is the reason of component templates whose change is held back until it is approved
+const opensearchAwaitingApproval = "waiting for the change to be approved with the " + helpers.ApprovedGenerationAnnotation + " annotation"
+
+// awaitApproval holds back the change of a component template requiring approval until the approved-generation
+// annotation is set to the generation of the spec. While the change is held back it is listed in the status and
+// true is returned. An approval of another generation is stale, it was given for a spec that has changed since.
+func (r *ComponentTemplateReconciler) awaitApproval(resource requests.ComponentTemplate, live *requests.ComponentTemplate) (bool, error) {
+	generation := r.generation
+	approved, annotated := r.instance.Annotations[helpers.ApprovedGenerationAnnotation]
+	if annotated && approved == strconv.FormatInt(generation, 10) {
+		return false, nil
+	}
+	if annotated {
+		r.recorder.Event(r.instance, "Warning", opensearchApproval, fmt.Sprintf(
+			"approval of generation %s is stale, the spec is at generation %d and has to be approved again", approved, generation,
+		))
+	}
+
+	diff, err := componentTemplateDiff(resource, live, r.redaction)
+	if err != nil {
+		return false, err
+	}
+	pending := &opsterv1.ComponentTemplateApproval{Generation: generation}
+	for _, change := range diff {
+		pending.Diff = append(pending.Diff, opsterv1.ComponentTemplateChange{
+			Op:   change.Op,
+			Path: change.Path,
+			From: rawJSON(change.From),
+			To:   rawJSON(change.To),
+		})
+	}
+	if equality.Semantic.DeepEqual(r.instance.Status.AwaitingApproval, pending) {
+		return true, nil
+	}
+	if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		status.AwaitingApproval = pending
+	}); err != nil {
+		return false, err
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchApproval, fmt.Sprintf(
+		"change of generation %d waits for approval, the diff is listed in status.awaitingApproval", generation,
+	))
+	return true, nil
+}
+
+// componentTemplateDiff returns the redacted changes applying the component template makes to the live one
+func componentTemplateDiff(resource requests.ComponentTemplate, live *requests.ComponentTemplate, redaction *helpers.Redaction) ([]helpers.JSONChange, error) {
+	var from interface{} = map[string]interface{}{}
+	if live != nil {
+		from = helpers.NormalizeComponentTemplate(*live)
+	}
+	diff, err := helpers.JSONDiff(from, helpers.NormalizeComponentTemplate(resource))
+	if err != nil {
+		return nil, err
+	}
+	return redaction.RedactChanges(diff)
+}
+
+func rawJSON(raw []byte) *apiextensionsv1.JSON {
+	if len(raw) == 0 {
+		return nil
+	}
+	return &apiextensionsv1.JSON{Raw: raw}
+}
@@ -0,0 +1,225 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchAuditConfigReadOnly = "OpensearchAuditConfigReadOnly"
+	opensearchAuditConfigDrift    = "OpensearchAuditConfigDrift"
+)
+
+type AuditConfigReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchAuditConfig
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewAuditConfigReconciler(
+	client client.Client,
+	ctx context.Context,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchAuditConfig,
+	opts ...ReconcilerOption,
+) *AuditConfigReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &AuditConfigReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "auditconfig"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "auditconfig"),
+	}
+}
+
+func (r *AuditConfigReconciler) Reconcile() (retResult ctrl.Result, retErr error) {
+	var reason string
+	var contentHash string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource is
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchAuditConfig)
+			instance.Status.Reason = reason
+			if retErr != nil {
+				instance.Status.State = opsterv1.OpensearchAuditConfigStateError
+			}
+			if retResult.Requeue && retResult.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchAuditConfigStatePending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchAuditConfigStateDeferred
+			}
+			if retErr == nil && retResult.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchAuditConfigStateCreated
+				instance.Status.ContentHash = contentHash
+			}
+		})
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, retErr = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if retErr != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(retErr, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster an audit config refers to"
+			retErr = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			retErr = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchAuditConfig)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if retErr != nil {
+				reason = fmt.Sprintf("failed to update status: %s", retErr)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, retErr = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if retErr != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	desired := helpers.TranslateAuditConfigToRequest(r.instance.Spec)
+	raw, retErr := json.Marshal(desired)
+	if retErr != nil {
+		reason = "failed to hash audit config"
+		r.logger.Error(retErr, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+	desiredHash, retErr := util.GetSha1Sum(raw)
+	if retErr != nil {
+		reason = "failed to hash audit config"
+		r.logger.Error(retErr, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	existing, retErr := services.GetAuditConfig(r.ctx, r.osClient)
+	if retErr != nil {
+		reason = "failed to get audit config from Opensearch API"
+		r.logger.Error(retErr, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if helpers.AuditConfigEqual(desired, existing.Config) {
+		r.logger.V(1).Info(fmt.Sprintf("audit config %s is in sync", r.instance.Name))
+		contentHash = desiredHash
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if violations := helpers.AuditConfigReadOnlyViolations(desired, existing.Config, existing.ReadOnly); len(violations) > 0 {
+		reason = fmt.Sprintf("cannot change the read only audit settings %s", strings.Join(violations, ", "))
+		retErr = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAuditConfigReadOnly, reason)
+		return
+	}
+
+	// The spec did not change since it was applied last, so the config was changed through the API
+	if r.instance.Status.ContentHash == desiredHash {
+		r.recorder.Event(r.instance, "Warning", opensearchAuditConfigDrift, "audit config was changed outside of the operator, restoring it")
+	}
+
+	retErr = services.PutAuditConfig(r.ctx, r.osClient, helpers.MergeAuditConfig(existing.Config, desired))
+	if retErr != nil {
+		reason = "failed to update audit config with Opensearch API"
+		r.logger.Error(retErr, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "audit config updated in opensearch")
+
+	contentHash = desiredHash
+	return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+}
+
+// Delete leaves the audit config in OpenSearch as it is, the security plugin has no defaults to reset it to
+func (r *AuditConfigReconciler) Delete() error {
+	return nil
+}
@@ -0,0 +1,395 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("auditconfig reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *AuditConfigReconciler
+		instance   *opsterv1.OpensearchAuditConfig
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		auditUrl   string
+		configUrl  string
+	)
+
+	// The audit config of the spec as the operator hashes it
+	const specConfig = `{"audit":{"disabled_rest_categories":["AUTHENTICATED","GRANTED_PRIVILEGES"],"ignore_users":["kibanaserver","monitoring"]},"enabled":true}`
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchAuditConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-auditconfig",
+				Namespace: "test-auditconfig",
+				UID:       types.UID("testuid"),
+			},
+			Spec: opsterv1.OpensearchAuditConfigSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Enabled: pointer.Bool(true),
+				Audit: &opsterv1.AuditSpec{
+					DisabledRestCategories: []string{"AUTHENTICATED", "GRANTED_PRIVILEGES"},
+					IgnoreUsers:            []string{"kibanaserver", "monitoring"},
+				},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-auditconfig",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		auditUrl = fmt.Sprintf("%s_plugins/_security/api/audit", clusterUrl)
+		configUrl = fmt.Sprintf("%s_plugins/_security/api/audit/config", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &AuditConfigReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster an audit config refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("audit config is in sync", func() {
+			BeforeEach(func() {
+				transport.RegisterResponder(
+					http.MethodGet,
+					auditUrl,
+					httpmock.NewStringResponder(200, `{"_readonly":["/compliance/internal_config"],"config":{
+						"enabled": true,
+						"audit": {
+							"enable_rest": true,
+							"disabled_rest_categories": ["AUTHENTICATED", "GRANTED_PRIVILEGES"],
+							"ignore_users": ["kibanaserver", "monitoring"]
+						},
+						"compliance": {"enabled": true, "internal_config": true}
+					}}`).Once(failMessage),
+				)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("audit config differs", func() {
+			var body string
+
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					auditUrl,
+					httpmock.NewStringResponder(200, `{"_readonly":["/compliance/internal_config"],"config":{
+						"enabled": true,
+						"audit": {
+							"enable_rest": true,
+							"disabled_rest_categories": ["AUTHENTICATED"],
+							"ignore_users": ["kibanaserver"]
+						},
+						"compliance": {"enabled": true, "internal_config": true}
+					}}`).Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					configUrl,
+					func(req *http.Request) (*http.Response, error) {
+						raw, err := io.ReadAll(req.Body)
+						if err != nil {
+							return nil, err
+						}
+						body = string(raw)
+						return httpmock.NewStringResponse(200, `{"status":"OK"}`), nil
+					},
+				)
+			})
+
+			It("should update the audit config keeping the settings not in the spec", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s audit config updated in opensearch", opensearchAPIUpdated)}))
+				Expect(body).To(MatchJSON(`{
+					"enabled": true,
+					"audit": {
+						"enable_rest": true,
+						"disabled_rest_categories": ["AUTHENTICATED", "GRANTED_PRIVILEGES"],
+						"ignore_users": ["kibanaserver", "monitoring"]
+					},
+					"compliance": {"enabled": true, "internal_config": true}
+				}`))
+			})
+		})
+
+		When("audit config was changed outside of the operator", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(2)
+				transport.RegisterResponder(
+					http.MethodGet,
+					auditUrl,
+					httpmock.NewStringResponder(200, `{"config":{"enabled":false,"audit":{
+						"disabled_rest_categories": ["AUTHENTICATED", "GRANTED_PRIVILEGES"],
+						"ignore_users": ["kibanaserver", "monitoring"]
+					}}}`).Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					configUrl,
+					httpmock.NewStringResponder(200, `{"status":"OK"}`).Once(failMessage),
+				)
+			})
+
+			It("should report the drift and restore the audit config", func() {
+				hash, err := util.GetSha1Sum([]byte(specConfig))
+				Expect(err).ToNot(HaveOccurred())
+				instance.Status.ContentHash = hash
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{
+					fmt.Sprintf("Warning %s audit config was changed outside of the operator, restoring it", opensearchAuditConfigDrift),
+					fmt.Sprintf("Normal %s audit config updated in opensearch", opensearchAPIUpdated),
+				}))
+			})
+		})
+
+		When("the spec changes a read only setting", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				instance.Spec.Compliance = &opsterv1.ComplianceSpec{InternalConfig: pointer.Bool(false)}
+				transport.RegisterResponder(
+					http.MethodGet,
+					auditUrl,
+					httpmock.NewStringResponder(200, `{"_readonly":["/compliance/internal_config"],"config":{
+						"enabled": true,
+						"audit": {"ignore_users": ["kibanaserver"]},
+						"compliance": {"enabled": true, "internal_config": true}
+					}}`).Once(failMessage),
+				)
+			})
+
+			It("should fail without updating the audit config", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).To(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the read only audit settings /compliance/internal_config", opensearchAuditConfigReadOnly)}))
+			})
+		})
+
+		When("the audit config cannot be updated", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					auditUrl,
+					httpmock.NewStringResponder(200, `{"config":{"enabled":false}}`).Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					configUrl,
+					httpmock.NewStringResponder(400, `{"status":"error","reason":"Invalid configuration"}`).Once(failMessage),
+				)
+			})
+
+			It("should fail", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).To(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s failed to update audit config with Opensearch API", opensearchAPIError)}))
+			})
+		})
+
+		When("the audit config is applied", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					auditUrl,
+					httpmock.NewStringResponder(200, `{"config":{"enabled":false}}`).Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					configUrl,
+					httpmock.NewStringResponder(200, `{"status":"OK"}`).Once(failMessage),
+				)
+				mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+					f(obj)
+					return nil
+				})
+			})
+
+			It("should record the hash of the applied audit config", func() {
+				reconciler.updateStatus = pointer.Bool(true)
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				for range recorder.Events {
+				}
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchAuditConfigStateCreated))
+				hash, err := util.GetSha1Sum([]byte(specConfig))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.ContentHash).To(Equal(hash))
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		It("should leave the audit config as it is", func() {
+			Expect(reconciler.Delete()).To(Succeed())
+			Expect(transport.GetTotalCallCount()).To(Equal(0))
+		})
+	})
+})
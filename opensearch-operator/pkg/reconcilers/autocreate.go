@@ -0,0 +1,31 @@
+package reconcilers
+
+import (
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+const opensearchAutoCreate = "OpensearchComponentTemplateAutoCreate"
+
+// checkAutoCreate warns if the template allows auto-creating indices but the action.auto_create_index setting of the
+// cluster does not auto-create all of them, the template is applied regardless. The setting is not checked if it
+// cannot be read.
+func (r *ComponentTemplateReconciler) checkAutoCreate(template requests.ComponentTemplate) (string, error) {
+	if !template.AllowAutoCreate {
+		return "", nil
+	}
+	if serverlessEndpoint(r.cluster) {
+		r.logger.V(1).Info("serverless endpoint has no cluster settings API, not checking auto-create")
+		return "", nil
+	}
+	setting, err := services.ClusterSetting(r.ctx, r.osClient, helpers.AutoCreateIndexSetting)
+	if err != nil {
+		r.logger.Info("failed to get the cluster settings, not checking auto-create", "error", err.Error())
+		return "", nil
+	}
+	if warning := helpers.AutoCreateIndexWarning(setting); warning != "" {
+		r.recorder.Event(r.instance, "Warning", opensearchAutoCreate, warning)
+	}
+	return "", nil
+}
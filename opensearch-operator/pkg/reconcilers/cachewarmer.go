@@ -0,0 +1,135 @@
+package reconcilers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ComponentTemplateCacheWarmer reads all component templates of every cluster component templates refer to once when
+// the operator starts, and seeds the component template cache with them. Reconciles of component templates wait for it,
+// so they read their templates from the cache instead of each sending a request at the same time. The warmer only runs
+// on the leader and gives up after the timeout, reconciles then read the templates not seeded yet from OpenSearch.
+type ComponentTemplateCacheWarmer struct {
+	ReconcilerOptions
+	templateCache *services.ComponentTemplateCache
+	timeout       time.Duration
+	k8sClient     func(ctx context.Context) k8s.K8sClient
+	// done is closed once the warmer finished or gave up
+	done chan struct{}
+}
+
+// NewComponentTemplateCacheWarmer returns a warmer seeding the cache of component templates for maxAge, giving up after
+// timeout. The options configure the clients like those of the reconciles.
+func NewComponentTemplateCacheWarmer(k8sClient client.Client, timeout time.Duration, maxAge time.Duration, opts ...ReconcilerOption) *ComponentTemplateCacheWarmer {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &ComponentTemplateCacheWarmer{
+		ReconcilerOptions: options,
+		templateCache:     services.NewComponentTemplateCache(maxAge),
+		timeout:           timeout,
+		k8sClient: func(ctx context.Context) k8s.K8sClient {
+			return k8s.NewK8sClient(k8sClient, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "cache-warmer")))
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// WithComponentTemplateCacheWarmer reads component templates from the cache of the warmer, reconciles wait for the
+// warmer to finish first. nil reads all of them from OpenSearch.
+func WithComponentTemplateCacheWarmer(warmer *ComponentTemplateCacheWarmer) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.cacheWarmer = warmer
+	}
+}
+
+// NeedLeaderElection makes the warmer only run on the leader, which runs the reconciles, it implements
+// manager.LeaderElectionRunnable
+func (w *ComponentTemplateCacheWarmer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start seeds the cache of component templates once, it implements manager.Runnable. Failures are logged and leave
+// the cache of the cluster empty, they do not stop the operator.
+func (w *ComponentTemplateCacheWarmer) Start(ctx context.Context) error {
+	defer close(w.done)
+	logger := log.FromContext(ctx).WithName("cache-warmer")
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	k8sClient := w.k8sClient(ctx)
+	list, err := k8sClient.ListOpensearchComponentTemplates()
+	if err != nil {
+		logger.Error(err, "failed to list the component templates, not warming the cache")
+		return nil
+	}
+	clusters := map[types.NamespacedName]bool{}
+	for i := range list.Items {
+		name := componentTemplateClusterName(&list.Items[i])
+		if name != "" {
+			clusters[types.NamespacedName{Namespace: list.Items[i].Namespace, Name: name}] = true
+		}
+	}
+
+	var wg sync.WaitGroup
+	for ref := range clusters {
+		wg.Add(1)
+		go func(ref types.NamespacedName) {
+			defer wg.Done()
+			if err := w.warm(ctx, k8sClient, ref); err != nil {
+				logger.Error(err, "failed to warm the component template cache", "cluster", ref.String())
+			}
+		}(ref)
+	}
+	wg.Wait()
+	logger.Info("warmed the component template cache", "clusters", len(clusters))
+	return nil
+}
+
+// warm seeds the cache with the component templates of the cluster, clusters that are not running are skipped
+func (w *ComponentTemplateCacheWarmer) warm(ctx context.Context, k8sClient k8s.K8sClient, ref types.NamespacedName) error {
+	cluster, err := util.FetchOpensearchCluster(k8sClient, ctx, ref)
+	if err != nil || cluster == nil || cluster.Status.Phase != opsterv1.PhaseRunning {
+		return err
+	}
+	url := util.OpensearchClusterURL(cluster)
+	opts := w.clientOptions(services.WithETagCache(w.etagCache), services.WithComponentTemplateCache(w.templateCache))
+	var osClient *services.OsClusterClient
+	if w.clientFactory != nil {
+		osClient, err = w.clientFactory.Get(k8sClient, ctx, cluster, url, w.osClientTransport, opts...)
+	} else {
+		osClient, err = util.CreateClientForURL(k8sClient, ctx, cluster, url, w.osClientTransport, opts...)
+	}
+	if err != nil {
+		return err
+	}
+	return services.WarmComponentTemplateCache(ctx, osClient)
+}
+
+// cache returns the cache seeded by the warmer, nil without a warmer
+func (w *ComponentTemplateCacheWarmer) cache() *services.ComponentTemplateCache {
+	if w == nil {
+		return nil
+	}
+	return w.templateCache
+}
+
+// wait blocks until the warmer finished or the context is done, it returns right away without a warmer
+func (w *ComponentTemplateCacheWarmer) wait(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+	}
+}
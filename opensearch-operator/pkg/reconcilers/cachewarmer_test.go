@@ -0,0 +1,164 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	k8sclient "github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("component template cache warmer", func() {
+	var (
+		transport  *httpmock.MockTransport
+		mockClient *k8s.MockK8sClient
+		warmer     *ComponentTemplateCacheWarmer
+		cluster    *opsterv1.OpenSearchCluster
+		templates  []opsterv1.OpensearchComponentTemplate
+		clusterUrl string
+	)
+
+	componentTemplate := func(name string, clusterName string) opsterv1.OpensearchComponentTemplate {
+		return opsterv1.OpensearchComponentTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-cachewarmer", UID: "testuid"},
+			Spec: opsterv1.OpensearchComponentTemplateSpec{
+				OpensearchRef: corev1.LocalObjectReference{Name: clusterName},
+				Template: opsterv1.OpensearchIndexSpec{
+					Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":1}}`)},
+				},
+			},
+			Status: opsterv1.OpensearchComponentTemplateStatus{ExistingComponentTemplate: new(bool)},
+		}
+	}
+
+	inventory := func(names ...string) responses.GetComponentTemplatesResponse {
+		response := responses.GetComponentTemplatesResponse{}
+		for _, name := range names {
+			template := componentTemplate(name, "test-cluster")
+			response.ComponentTemplates = append(response.ComponentTemplates, responses.ComponentTemplate{
+				Name:              name,
+				ComponentTemplate: helpers.TranslateComponentTemplateToRequest(template.Spec),
+			})
+		}
+		return response
+	}
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-cachewarmer"},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{ServiceName: "test-cluster", HttpPort: 9200, Version: "2.8.0"},
+			},
+			Status: opsterv1.ClusterStatus{Phase: opsterv1.PhaseRunning},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		templates = []opsterv1.OpensearchComponentTemplate{
+			componentTemplate("template-a", "test-cluster"),
+			componentTemplate("template-b", "test-cluster"),
+			componentTemplate("template-c", "other-cluster"),
+		}
+
+		mockClient.EXPECT().GetOpenSearchCluster("test-cluster", "test-cachewarmer").Return(*cluster, nil)
+		mockClient.EXPECT().GetOpenSearchCluster("other-cluster", "test-cachewarmer").Return(opsterv1.OpenSearchCluster{}, NotFoundError()).Maybe()
+		transport.RegisterResponder(http.MethodHead, clusterUrl, httpmock.NewStringResponder(200, "OK"))
+		transport.RegisterResponder(http.MethodGet, clusterUrl, httpmock.NewStringResponder(200, "OK"))
+	})
+
+	JustBeforeEach(func() {
+		mockClient.EXPECT().ListOpensearchComponentTemplates().Return(opsterv1.OpensearchComponentTemplateList{Items: templates}, nil)
+		warmer = NewComponentTemplateCacheWarmer(nil, 200*time.Millisecond, time.Minute, WithOSClientTransport(transport))
+		warmer.k8sClient = func(context.Context) k8sclient.K8sClient { return mockClient }
+	})
+
+	reconcile := func(template opsterv1.OpensearchComponentTemplate) {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false), WithComponentTemplateCacheWarmer(warmer))
+		reconciler := &ComponentTemplateReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          record.NewFakeRecorder(10),
+			instance:          &template,
+			logger:            log.FromContext(context.Background()),
+		}
+		_, err := reconciler.Reconcile()
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	templateUrl := func(name string) string {
+		return fmt.Sprintf("%s_component_template/%s", clusterUrl, name)
+	}
+
+	When("the component templates are read in time", func() {
+		BeforeEach(func() {
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl+"_component_template",
+				httpmock.NewJsonResponderOrPanic(200, inventory("template-a", "unmanaged")).Once(failMessage),
+			)
+			transport.RegisterResponder(http.MethodGet, templateUrl("template-a"), httpmock.NewJsonResponderOrPanic(200, inventory("template-a")))
+			transport.RegisterResponder(http.MethodPut, templateUrl("template-b"), httpmock.NewStringResponder(200, "OK").Once(failMessage))
+		})
+
+		JustBeforeEach(func() {
+			Expect(warmer.Start(context.Background())).To(Succeed())
+		})
+
+		It("should read the component templates of every cluster once on the leader", func() {
+			Expect(warmer.NeedLeaderElection()).To(BeTrue())
+			Expect(transport.GetCallCountInfo()).To(HaveKeyWithValue("GET "+clusterUrl+"_component_template", 1))
+		})
+
+		It("should serve the first read of the reconciles from the cache", func() {
+			reconcile(templates[0])
+			reconcile(templates[1])
+			Expect(transport.GetCallCountInfo()).To(HaveKeyWithValue("GET "+templateUrl("template-a"), 0))
+			Expect(transport.GetCallCountInfo()).To(HaveKeyWithValue("PUT "+templateUrl("template-b"), 1))
+			Expect(transport.GetCallCountInfo()).ToNot(HaveKey("GET " + templateUrl("template-b")))
+		})
+
+		It("should read the component template from OpenSearch again afterwards", func() {
+			reconcile(templates[0])
+			reconcile(templates[0])
+			Expect(transport.GetCallCountInfo()).To(HaveKeyWithValue("GET "+templateUrl("template-a"), 1))
+		})
+	})
+
+	When("the component templates are not read in time", func() {
+		BeforeEach(func() {
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl+"_component_template",
+				func(req *http.Request) (*http.Response, error) {
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				},
+			)
+			transport.RegisterResponder(http.MethodGet, templateUrl("template-a"), httpmock.NewJsonResponderOrPanic(200, inventory("template-a")).Once(failMessage))
+		})
+
+		It("should give up and leave the reads to the reconciles", func() {
+			start := time.Now()
+			Expect(warmer.Start(context.Background())).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+			reconcile(templates[0])
+			Expect(transport.GetCallCountInfo()).To(HaveKeyWithValue("GET "+templateUrl("template-a"), 1))
+		})
+	})
+})
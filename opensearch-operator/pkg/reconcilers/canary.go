@@ -0,0 +1,124 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+const (
+	opensearchCanaryValidation = "OpensearchComponentTemplateCanaryValidation"
+
+	// canaryIndexPrefix is the prefix of the canary indices created to validate changes of component templates
+	canaryIndexPrefix = "opensearch-operator-canary-"
+)
+
+// validateWithCanary validates a change of the component template with the canary documents of the spec before it
+// is applied, unless this version was already validated. It creates a canary index with the settings and mappings
+// of the template, indexes the documents into it and compares the resulting field types with the expected ones. The
+// canary index uses a name reserved for the operator, an existing index is never modified, and it is deleted again.
+// The aliases of the template are not added to the canary index. The member is the reconciled instance or a member of
+// its transaction group, whose status records the validated version.
+func (r *ComponentTemplateReconciler) validateWithCanary(member *opsterv1.OpensearchComponentTemplate, templateName string, template requests.ComponentTemplate) (string, error) {
+	canary := member.Spec.CanaryValidation
+	if canary == nil {
+		return "", nil
+	}
+	hash, err := componentTemplateHash(template)
+	if err != nil {
+		reason := "failed to hash the component template"
+		r.logger.Error(err, reason)
+		return reason, err
+	}
+	if member.Status.CanaryValidatedHash == hash {
+		return "", nil
+	}
+
+	indexName := canaryIndexPrefix + strings.ToLower(templateName)
+	if err := helpers.ValidateIndexPattern(indexName); err != nil {
+		reason := fmt.Sprintf("cannot validate the component template with a canary index: %s", err)
+		r.recorder.Event(r.instance, "Warning", opensearchCanaryValidation, reason)
+		return reason, err
+	}
+
+	failures, err := r.runCanary(indexName, template, canary)
+	if errors.Is(err, services.ErrForbidden) {
+		reason := "operator user is not authorized to validate component templates with a canary index, check that it " +
+			"has the indices:admin/create, indices:admin/delete, indices:admin/mappings/get and indices:data/write/index privileges"
+		r.logger.Info(reason)
+		r.recorder.Event(r.instance, "Warning", opensearchForbidden, reason)
+		return reason, err
+	}
+	if err != nil {
+		reason := fmt.Sprintf("failed to validate the component template with a canary index: %s", err)
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchCanaryValidation, reason)
+		return reason, err
+	}
+	if len(failures) > 0 {
+		reason := fmt.Sprintf("canary validation of the component template failed, the change is not applied: %s", strings.Join(failures, "; "))
+		r.recorder.Event(r.instance, "Warning", opensearchCanaryValidation, reason)
+		return reason, errors.New(reason)
+	}
+
+	if err := r.updateComponentTemplateStatus(member, func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		status.CanaryValidatedHash = hash
+	}); err != nil {
+		reason := fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return reason, err
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchCanaryValidation,
+		fmt.Sprintf("component template validated with %d documents in canary index %s", len(canary.Documents), indexName))
+	return "", nil
+}
+
+// runCanary creates the canary index, indexes the documents into it and returns every rejected document and every
+// field that is not mapped as expected. The canary index is always deleted before returning.
+func (r *ComponentTemplateReconciler) runCanary(indexName string, template requests.ComponentTemplate, canary *opsterv1.CanaryValidation) (failures []string, err error) {
+	settings, err := helpers.CanaryIndexSettings(template.Template.Settings)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{"settings": settings}
+	if template.Template.Mappings.Size() > 0 {
+		body["mappings"] = template.Template.Mappings
+	}
+	if err := services.CreateNewIndex(r.ctx, r.osClient, indexName, body); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cleanupErr := services.DeleteIndexIfExists(r.ctx, r.osClient, indexName); cleanupErr != nil && err == nil {
+			err = fmt.Errorf("failed to delete the canary index: %w", cleanupErr)
+		}
+	}()
+
+	for i := range canary.Documents {
+		err := services.IndexDocument(r.ctx, r.osClient, indexName, &canary.Documents[i])
+		if errors.Is(err, services.ErrDocumentRejected) {
+			failures = append(failures, fmt.Sprintf("document %d: %s", i, err))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(canary.ExpectedFieldTypes) > 0 {
+		mappings, err := services.GetIndexMappings(r.ctx, r.osClient, indexName)
+		if err != nil {
+			return nil, err
+		}
+		mismatches, err := helpers.FieldTypeMismatches(mappings, canary.ExpectedFieldTypes)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, mismatches...)
+	}
+	return failures, nil
+}
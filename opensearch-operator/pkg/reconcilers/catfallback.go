@@ -0,0 +1,23 @@
+package reconcilers
+
+import (
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+)
+
+// existsFromCatTemplates derives whether the component template exists from _cat/templates, for operator users that
+// may read _cat/templates but not component templates. Only component templates an index template is composed of
+// are known to exist, other component templates are treated as new.
+func (r *ComponentTemplateReconciler) existsFromCatTemplates(templateName string) (bool, error) {
+	referenced, err := services.ComponentTemplateReferenced(r.ctx, r.osClient, templateName)
+	if err != nil {
+		return false, err
+	}
+	if referenced {
+		r.recorder.Event(r.instance, "Warning", opensearchCatFallback,
+			"component templates cannot be read from OpenSearch, the component template exists as an index template listed by _cat/templates is composed of it")
+	} else {
+		r.recorder.Event(r.instance, "Warning", opensearchCatFallback,
+			"component templates cannot be read from OpenSearch and no index template listed by _cat/templates is composed of the component template, it is treated as new")
+	}
+	return referenced, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
@@ -20,6 +21,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -114,6 +116,10 @@ func (r *ClusterReconciler) Reconcile() (ctrl.Result, error) {
 		result.Combine(r.client.ReconcileResource(headlessService, reconciler.StatePresent))
 
 		result.Combine(r.reconcileNodeStatefulSet(nodePool, username))
+
+		if helpers.ZoneAwarenessEnabled(&nodePool) {
+			result.Combine(r.annotateZones(&nodePool))
+		}
 	}
 
 	// if Version isn't set we set it now to check for upgrades later.
@@ -251,8 +257,9 @@ func (r *ClusterReconciler) reconcileNodeStatefulSet(nodePool opsterv1.NodePool,
 		} else {
 			// A failure is assumed if n PVCs exist but less than n-1 pods (one missing pod is allowed for rolling restart purposes)
 			// We can assume the cluster is in a failure state and cannot recover on its own
+			replicas := helpers.EffectiveReplicas(r.instance.Status, &nodePool)
 			if !helpers.UpgradeInProgress(r.instance.Status) &&
-				pvcCount >= int(nodePool.Replicas) && existing.Status.ReadyReplicas < nodePool.Replicas-1 {
+				pvcCount >= int(replicas) && existing.Status.ReadyReplicas < replicas-1 {
 				r.logger.Info(fmt.Sprintf("Detected recovery situation for nodepool %s: PVC count: %d, replicas: %d. Recreating STS with parallel mode", nodePool.Component, pvcCount, existing.Status.Replicas))
 				if existing.Spec.PodManagementPolicy != appsv1.ParallelPodManagement {
 					// Switch to Parallel to jumpstart the cluster
@@ -271,7 +278,7 @@ func (r *ClusterReconciler) reconcileNodeStatefulSet(nodePool opsterv1.NodePool,
 						return result, err
 					}
 					// Wait for pods to appear
-					err := helpers.WaitForSTSReplicas(r.client, &existing, nodePool.Replicas)
+					err := helpers.WaitForSTSReplicas(r.client, &existing, replicas)
 					// Abort normal logic and requeue
 					return &ctrl.Result{Requeue: true}, err
 				}
@@ -436,14 +443,15 @@ func (r *ClusterReconciler) checkForEmptyDirRecovery() (*ctrl.Result, error) {
 func (r *ClusterReconciler) handlePDB(nodePool *opsterv1.NodePool) (*ctrl.Result, error) {
 	pdb := policyv1.PodDisruptionBudget{}
 
-	if nodePool.Pdb != nil && nodePool.Pdb.Enable {
-		// Check if provided parameters are valid
-		if (nodePool.Pdb.MinAvailable != nil && nodePool.Pdb.MaxUnavailable != nil) || (nodePool.Pdb.MinAvailable == nil && nodePool.Pdb.MaxUnavailable == nil) {
-			r.logger.Info("Please provide only one parameter (minAvailable OR maxUnavailable) in order to configure a PodDisruptionBudget")
-			return &ctrl.Result{}, fmt.Errorf("please provide only one parameter (minAvailable OR maxUnavailable) in order to configure a PodDisruptionBudget")
-
-		}
-		pdb = helpers.ComposePDB(r.instance, nodePool)
+	pdbConfig, err := helpers.PdbConfigForNodePool(r.instance, nodePool)
+	if err != nil {
+		r.logger.Info("Please provide only one parameter (minAvailable OR maxUnavailable) in order to configure a PodDisruptionBudget")
+		return &ctrl.Result{}, err
+	}
+	if pdbConfig != nil {
+		withPdb := *nodePool
+		withPdb.Pdb = pdbConfig
+		pdb = helpers.ComposePDB(r.instance, &withPdb)
 		if err := ctrl.SetControllerReference(r.instance, &pdb, r.client.Scheme()); err != nil {
 			return &ctrl.Result{}, err
 		}
@@ -462,6 +470,56 @@ func (r *ClusterReconciler) handlePDB(nodePool *opsterv1.NodePool) (*ctrl.Result
 	}
 }
 
+// annotateZones annotates every scheduled pod of the node pool with the zone of its node. The pods wait for the
+// annotation before OpenSearch is started, see builders.NewSTSForNodePool
+func (r *ClusterReconciler) annotateZones(nodePool *opsterv1.NodePool) (*ctrl.Result, error) {
+	topologyKey := helpers.ZoneTopologyKey(nodePool.ZoneAwareness)
+	pods, err := r.client.ListPods(&client.ListOptions{
+		Namespace: r.instance.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			helpers.ClusterLabel:  r.instance.Name,
+			helpers.NodePoolLabel: nodePool.Component,
+		}),
+	})
+	if err != nil {
+		return &ctrl.Result{}, err
+	}
+
+	waiting := false
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Annotations[helpers.ZoneAnnotation] != "" {
+			continue
+		}
+		if pod.Spec.NodeName == "" {
+			waiting = true
+			continue
+		}
+		node, err := r.client.GetNode(pod.Spec.NodeName)
+		if err != nil {
+			return &ctrl.Result{}, err
+		}
+		zone := node.Labels[topologyKey]
+		if zone == "" {
+			waiting = true
+			r.logger.Info(fmt.Sprintf("Node %s of pod %s has no label %s", node.Name, pod.Name, topologyKey))
+			r.recorder.AnnotatedEventf(r.instance, map[string]string{"cluster-name": r.instance.GetName()}, "Warning", "ZoneAwareness", "Pod %s waits for its zone, node %s has no label %s", pod.Name, node.Name, topologyKey)
+			continue
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[helpers.ZoneAnnotation] = zone
+		if err := r.client.UpdatePod(&pod); err != nil {
+			return &ctrl.Result{}, err
+		}
+		r.logger.Info(fmt.Sprintf("Pod %s is in zone %s", pod.Name, zone))
+	}
+	if waiting {
+		return &ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+	}
+	return &ctrl.Result{}, nil
+}
+
 func (r *ClusterReconciler) maybeUpdateVolumes(existing *appsv1.StatefulSet, nodePool opsterv1.NodePool) error {
 	if nodePool.DiskSize == "" { // Default case
 		nodePool.DiskSize = builders.DefaultDiskSize
@@ -0,0 +1,156 @@
+package reconcilers
+
+import (
+	"context"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("cluster reconciler zone awareness", func() {
+	var (
+		reconciler *ClusterReconciler
+		instance   *opsterv1.OpenSearchCluster
+		nodePool   opsterv1.NodePool
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+		pods       []corev1.Pod
+		nodes      map[string]corev1.Node
+		// The zones of the updated pods by pod name
+		updated map[string]string
+	)
+
+	pod := func(name string, nodeName string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-zones"},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	node := func(name string, zone string) corev1.Node {
+		labels := map[string]string{}
+		if zone != "" {
+			labels[helpers.DefaultZoneTopologyKey] = zone
+		}
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		recorder = record.NewFakeRecorder(20)
+		nodePool = opsterv1.NodePool{
+			Component:     "data",
+			Replicas:      3,
+			Roles:         []string{"data"},
+			ZoneAwareness: &opsterv1.ZoneAwarenessConfig{Enable: true},
+		}
+		instance = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "test-zones"},
+			Spec: opsterv1.ClusterSpec{
+				NodePools: []opsterv1.NodePool{nodePool},
+			},
+		}
+		pods = []corev1.Pod{pod("cluster-data-0", "node-a"), pod("cluster-data-1", "node-b")}
+		nodes = map[string]corev1.Node{
+			"node-a": node("node-a", "zone-a"),
+			"node-b": node("node-b", "zone-b"),
+		}
+		updated = map[string]string{}
+
+		mockClient.EXPECT().ListPods(mock.Anything).RunAndReturn(func(opts *client.ListOptions) (corev1.PodList, error) {
+			Expect(opts.Namespace).To(Equal("test-zones"))
+			Expect(opts.LabelSelector.String()).To(Equal("opster.io/opensearch-cluster=cluster,opster.io/opensearch-nodepool=data"))
+			return corev1.PodList{Items: pods}, nil
+		})
+		mockClient.EXPECT().GetNode(mock.Anything).RunAndReturn(func(name string) (corev1.Node, error) {
+			return nodes[name], nil
+		}).Maybe()
+		mockClient.EXPECT().UpdatePod(mock.Anything).RunAndReturn(func(pod *corev1.Pod) error {
+			updated[pod.Name] = pod.Annotations[helpers.ZoneAnnotation]
+			return nil
+		}).Maybe()
+	})
+
+	JustBeforeEach(func() {
+		reconciler = &ClusterReconciler{
+			client:   mockClient,
+			ctx:      context.Background(),
+			recorder: recorder,
+			instance: instance,
+			logger:   log.FromContext(context.Background()),
+		}
+	})
+
+	It("should annotate the pods with the zone of their node", func() {
+		result, err := reconciler.annotateZones(&nodePool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+		Expect(updated).To(Equal(map[string]string{
+			"cluster-data-0": "zone-a",
+			"cluster-data-1": "zone-b",
+		}))
+	})
+
+	It("should read the zone from the configured topology key", func() {
+		nodePool.ZoneAwareness.TopologyKey = "example.com/rack"
+		nodes["node-a"].Labels["example.com/rack"] = "rack-1"
+		nodes["node-b"].Labels["example.com/rack"] = "rack-2"
+
+		_, err := reconciler.annotateZones(&nodePool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(Equal(map[string]string{
+			"cluster-data-0": "rack-1",
+			"cluster-data-1": "rack-2",
+		}))
+	})
+
+	When("a pod is annotated already", func() {
+		BeforeEach(func() {
+			pods[0].Annotations = map[string]string{helpers.ZoneAnnotation: "zone-a"}
+		})
+
+		It("should leave it alone", func() {
+			_, err := reconciler.annotateZones(&nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated).To(Equal(map[string]string{"cluster-data-1": "zone-b"}))
+			mockClient.AssertNotCalled(GinkgoT(), "GetNode", "node-a")
+		})
+	})
+
+	When("a pod is not scheduled yet", func() {
+		BeforeEach(func() {
+			pods = append(pods, pod("cluster-data-2", ""))
+		})
+
+		It("should requeue until it is scheduled", func() {
+			result, err := reconciler.annotateZones(&nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(updated).To(HaveLen(2))
+			Expect(updated).ToNot(HaveKey("cluster-data-2"))
+		})
+	})
+
+	When("the node of a pod has no zone label", func() {
+		BeforeEach(func() {
+			nodes["node-b"] = node("node-b", "")
+		})
+
+		It("should report the pod as waiting for its zone", func() {
+			result, err := reconciler.annotateZones(&nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(updated).To(Equal(map[string]string{"cluster-data-0": "zone-a"}))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Pod cluster-data-1 waits for its zone, node node-b has no label topology.kubernetes.io/zone")))
+		})
+	})
+})
@@ -0,0 +1,62 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+)
+
+// opensearchClusterHealthDeferred starts the reasons of changes deferred until the cluster has the required health
+const opensearchClusterHealthDeferred = "deferring changes while the cluster health is"
+
+// clusterHealthRank orders the health statuses, unknown statuses rank below red
+var clusterHealthRank = map[opsterv1.OpenSearchHealth]int{
+	opsterv1.OpenSearchRedHealth:    1,
+	opsterv1.OpenSearchYellowHealth: 2,
+	opsterv1.OpenSearchGreenHealth:  3,
+}
+
+// deferForClusterHealth returns the reason to defer a change if the health of the cluster is below the required
+// health. It returns an empty reason without calling OpenSearch if no health is required.
+func deferForClusterHealth(ctx context.Context, osClient *services.OsClusterClient, required opsterv1.OpenSearchHealth) (string, error) {
+	if required == "" {
+		return "", nil
+	}
+	health, err := services.ClusterHealth(ctx, osClient)
+	if err != nil {
+		return "", err
+	}
+	status := opsterv1.OpenSearchHealth(health.Status)
+	if clusterHealthRank[status] >= clusterHealthRank[required] {
+		return "", nil
+	}
+	return fmt.Sprintf("%s %s, %s is required", opensearchClusterHealthDeferred, status, required), nil
+}
+
+// deferForRequiredHealth returns the reason to defer a change of the component template until the cluster has the
+// required health, and the reason of a failure to get the health. Serverless endpoints are never deferred.
+func (r *ComponentTemplateReconciler) deferForRequiredHealth(required opsterv1.OpenSearchHealth) (deferred string, reason string, err error) {
+	deferred, err = deferForClusterHealth(r.ctx, r.osClient, r.requiredClusterHealth(required))
+	if errors.Is(err, services.ErrForbidden) {
+		return "", r.forbidden(clusterHealthPrivilege), err
+	}
+	if err != nil {
+		reason = "failed to get cluster health from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return "", reason, err
+	}
+	return deferred, "", nil
+}
+
+// requiredClusterHealth returns the health required before changes are made, none for serverless endpoints as they
+// have no cluster health API
+func (r *ComponentTemplateReconciler) requiredClusterHealth(health opsterv1.OpenSearchHealth) opsterv1.OpenSearchHealth {
+	if serverlessEndpoint(r.cluster) {
+		return ""
+	}
+	return health
+}
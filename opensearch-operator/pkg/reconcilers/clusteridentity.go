@@ -0,0 +1,62 @@
+package reconcilers
+
+import (
+	"fmt"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"k8s.io/utils/pointer"
+)
+
+// The cluster the component template was applied to was replaced by a new one
+const opensearchClusterReplaced = "OpensearchComponentTemplateClusterReplaced"
+
+// checkClusterUUID compares the UUID of the OpenSearch cluster with the one the component template was applied to.
+// A changed UUID means the cluster was replaced, e.g. by restoring a snapshot into a fresh cluster, even though the
+// OpenSearchCluster object stayed the same. Clusters that do not report a UUID are not checked.
+func (r *ComponentTemplateReconciler) checkClusterUUID() error {
+	uuid := r.osClient.MainPage.ClusterUuid
+	previous := r.instance.Status.ClusterUUID
+	if uuid == "" || uuid == previous {
+		return nil
+	}
+	if previous == "" {
+		return r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+			status.ClusterUUID = uuid
+		})
+	}
+	return r.readoptCluster(fmt.Sprintf(
+		"OpenSearch cluster UUID changed from %s to %s, the cluster was replaced and the component template is applied to the new cluster",
+		previous, uuid,
+	))
+}
+
+// readoptCluster adopts the cluster the component template refers to after it was replaced. Everything recorded
+// about the component template in the previous cluster is reset, so it is applied to the new cluster as if it was
+// new: a template restored from a snapshot is not taken for an external edit, and a template that was left alone
+// because it existed before is checked again.
+func (r *ComponentTemplateReconciler) readoptCluster(message string) error {
+	r.logger.Info(message)
+	r.recorder.Event(r.instance, "Warning", opensearchClusterReplaced, message)
+	uid := r.cluster.UID
+	uuid := ""
+	if r.osClient != nil {
+		uuid = r.osClient.MainPage.ClusterUuid
+	}
+	return r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		status.ManagedCluster = &uid
+		status.ManagedClusterName = r.cluster.Name
+		status.ClusterUUID = uuid
+		if pointer.BoolDeref(status.ExistingComponentTemplate, false) {
+			status.ExistingComponentTemplate = nil
+		}
+		status.CreatedOnce = false
+		status.LastAppliedHash = ""
+		status.LastAppliedGeneration = 0
+		status.LastAppliedGitRevision = ""
+		status.ExternalEditDetectedAt = nil
+		status.PreviousApplied = nil
+		status.RollbackGeneration = 0
+		status.VerifiedHash = ""
+		status.AwaitingApproval = nil
+	})
+}
@@ -0,0 +1,261 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchClusterSettingDenied = "OpensearchClusterSettingDenied"
+	opensearchClusterSettingsDrift = "OpensearchClusterSettingsDrift"
+)
+
+type ClusterSettingsReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchClusterSettings
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewClusterSettingsReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchClusterSettings,
+	opts ...ReconcilerOption,
+) *ClusterSettingsReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &ClusterSettingsReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "clustersettings"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "clustersettings"),
+	}
+}
+
+func (r *ClusterSettingsReconciler) denyList() []string {
+	if r.clusterSettingsDenyList == nil {
+		return helpers.DefaultClusterSettingsDenyList
+	}
+	return r.clusterSettingsDenyList
+}
+
+func (r *ClusterSettingsReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchClusterSettings)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchClusterSettingsError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchClusterSettingsPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchClusterSettingsDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchClusterSettingsCreated
+				instance.Status.Persistent = r.instance.Spec.Persistent
+				instance.Status.Transient = r.instance.Spec.Transient
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster cluster settings refer to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchClusterSettings)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Settings in the deny list are refused before anything is written
+	err = helpers.ValidateClusterSettings(r.instance.Spec, r.denyList())
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchClusterSettingDenied, reason)
+		return
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	existing, err := services.GetFlatClusterSettingsMap(r.ctx, r.osClient)
+	if err != nil {
+		reason = "failed to get cluster settings from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	persistent, persistentDrift := helpers.ClusterSettingsChanges(r.instance.Spec.Persistent, existing.Persistent, r.instance.Status.Persistent)
+	transient, transientDrift := helpers.ClusterSettingsChanges(r.instance.Spec.Transient, existing.Transient, r.instance.Status.Transient)
+	if drifted := append(persistentDrift, transientDrift...); len(drifted) > 0 {
+		r.recorder.Event(r.instance, "Warning", opensearchClusterSettingsDrift,
+			fmt.Sprintf("cluster settings %s were changed outside of the operator, restoring them", strings.Join(drifted, ", ")))
+	}
+
+	if len(persistent) == 0 && len(transient) == 0 {
+		r.logger.V(1).Info(fmt.Sprintf("cluster settings %s are in sync", r.instance.Name))
+		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		return
+	}
+
+	err = services.PutFlatClusterSettings(r.ctx, r.osClient, responses.ClusterSettingsResponse{
+		Persistent: persistent,
+		Transient:  transient,
+	})
+	if err != nil {
+		reason = "failed to update cluster settings with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "cluster settings updated in opensearch")
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+// Delete resets the settings last applied by the operator to their defaults
+func (r *ClusterSettingsReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if len(r.instance.Status.Persistent) == 0 && len(r.instance.Status.Transient) == 0 {
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	reset := responses.ClusterSettingsResponse{
+		Persistent: map[string]interface{}{},
+		Transient:  map[string]interface{}{},
+	}
+	for name := range r.instance.Status.Persistent {
+		reset.Persistent[name] = nil
+	}
+	for name := range r.instance.Status.Transient {
+		reset.Transient[name] = nil
+	}
+	return services.PutFlatClusterSettings(r.ctx, r.osClient, reset)
+}
@@ -0,0 +1,499 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("clustersettings reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *ClusterSettingsReconciler
+		instance   *opsterv1.OpensearchClusterSettings
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+		denyList   []string
+
+		// Objects
+		cluster     *opsterv1.OpenSearchCluster
+		clusterUrl  string
+		settingsUrl string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		denyList = nil
+		instance = &opsterv1.OpensearchClusterSettings{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-clustersettings",
+				Namespace: "test-clustersettings",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchClusterSettingsSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Persistent: map[string]string{
+					"cluster.max_shards_per_node": "2000",
+				},
+				Transient: map[string]string{
+					"indices.recovery.max_bytes_per_sec": "100mb",
+				},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-clustersettings",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		settingsUrl = fmt.Sprintf("%s_cluster/settings?flat_settings=true", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false), WithClusterSettingsDenyList(denyList))
+		reconciler = &ClusterSettingsReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster cluster settings refer to", opensearchRefMismatch)))
+		})
+	})
+
+	When("a setting is in the deny list", func() {
+		BeforeEach(func() {
+			instance.Spec.Persistent["cluster.routing.allocation.enable"] = "primaries"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should refuse the settings without calling OpenSearch", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(0))
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s invalid cluster settings: persistent setting "+
+				"cluster.routing.allocation.enable is denied by cluster.routing.allocation.enable", opensearchClusterSettingDenied)}))
+		})
+	})
+
+	When("the operator is started with another deny list", func() {
+		BeforeEach(func() {
+			denyList = []string{"plugins.security.*"}
+			instance.Spec.Persistent["cluster.routing.allocation.enable"] = "primaries"
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodGet,
+				settingsUrl,
+				httpmock.NewStringResponder(200, `{"persistent":{
+					"cluster.max_shards_per_node":"2000",
+					"cluster.routing.allocation.enable":"primaries"
+				},"transient":{"indices.recovery.max_bytes_per_sec":"100mb"}}`).Once(failMessage),
+			)
+		})
+
+		It("should accept the setting", func() {
+			_, err := reconciler.Reconcile()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("settings are in sync", func() {
+			BeforeEach(func() {
+				transport.RegisterResponder(
+					http.MethodGet,
+					settingsUrl,
+					httpmock.NewStringResponder(200, `{"persistent":{"cluster.max_shards_per_node":"2000"},
+						"transient":{"indices.recovery.max_bytes_per_sec":"100mb"}}`).Once(failMessage),
+				)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("settings differ", func() {
+			var body string
+
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					settingsUrl,
+					httpmock.NewStringResponder(200, `{"persistent":{"cluster.max_shards_per_node":"1000"},"transient":{}}`).Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					settingsUrl,
+					func(req *http.Request) (*http.Response, error) {
+						raw, err := io.ReadAll(req.Body)
+						if err != nil {
+							return nil, err
+						}
+						body = string(raw)
+						return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+					},
+				)
+			})
+
+			It("should update the settings", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s cluster settings updated in opensearch", opensearchAPIUpdated)}))
+				Expect(body).To(MatchJSON(`{"persistent":{"cluster.max_shards_per_node":"2000"},
+					"transient":{"indices.recovery.max_bytes_per_sec":"100mb"}}`))
+			})
+		})
+
+		When("a setting was removed from the spec", func() {
+			var body string
+
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				instance.Status.Persistent = map[string]string{
+					"cluster.max_shards_per_node": "2000",
+					"search.max_buckets":          "20000",
+				}
+				instance.Status.Transient = map[string]string{
+					"indices.recovery.max_bytes_per_sec": "100mb",
+				}
+				transport.RegisterResponder(
+					http.MethodGet,
+					settingsUrl,
+					httpmock.NewStringResponder(200, `{"persistent":{"cluster.max_shards_per_node":"2000","search.max_buckets":"20000"},
+						"transient":{"indices.recovery.max_bytes_per_sec":"100mb"}}`).Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					settingsUrl,
+					func(req *http.Request) (*http.Response, error) {
+						raw, err := io.ReadAll(req.Body)
+						if err != nil {
+							return nil, err
+						}
+						body = string(raw)
+						return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+					},
+				)
+			})
+
+			It("should reset the setting", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s cluster settings updated in opensearch", opensearchAPIUpdated)}))
+				Expect(body).To(MatchJSON(`{"persistent":{"search.max_buckets":null}}`))
+			})
+		})
+
+		When("a setting was changed outside of the operator", func() {
+			var body string
+
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(2)
+				instance.Status.Persistent = map[string]string{
+					"cluster.max_shards_per_node": "2000",
+				}
+				instance.Status.Transient = map[string]string{
+					"indices.recovery.max_bytes_per_sec": "100mb",
+				}
+				transport.RegisterResponder(
+					http.MethodGet,
+					settingsUrl,
+					httpmock.NewStringResponder(200, `{"persistent":{"cluster.max_shards_per_node":"5000"},
+						"transient":{"indices.recovery.max_bytes_per_sec":"100mb"}}`).Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					settingsUrl,
+					func(req *http.Request) (*http.Response, error) {
+						raw, err := io.ReadAll(req.Body)
+						if err != nil {
+							return nil, err
+						}
+						body = string(raw)
+						return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+					},
+				)
+			})
+
+			It("should report the drift and restore the setting", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{
+					fmt.Sprintf("Warning %s cluster settings cluster.max_shards_per_node were changed outside of the operator, restoring them", opensearchClusterSettingsDrift),
+					fmt.Sprintf("Normal %s cluster settings updated in opensearch", opensearchAPIUpdated),
+				}))
+				Expect(body).To(MatchJSON(`{"persistent":{"cluster.max_shards_per_node":"2000"}}`))
+			})
+		})
+
+		When("the settings are applied", func() {
+			BeforeEach(func() {
+				transport.RegisterResponder(
+					http.MethodGet,
+					settingsUrl,
+					httpmock.NewStringResponder(200, `{"persistent":{"cluster.max_shards_per_node":"2000"},
+						"transient":{"indices.recovery.max_bytes_per_sec":"100mb"}}`).Once(failMessage),
+				)
+				mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+					f(obj)
+					return nil
+				})
+			})
+
+			It("should record the applied settings", func() {
+				reconciler.updateStatus = pointer.Bool(true)
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchClusterSettingsCreated))
+				Expect(instance.Status.Persistent).To(Equal(map[string]string{"cluster.max_shards_per_node": "2000"}))
+				Expect(instance.Status.Transient).To(Equal(map[string]string{"indices.recovery.max_bytes_per_sec": "100mb"}))
+			})
+		})
+
+		When("the settings cannot be updated", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					settingsUrl,
+					httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					settingsUrl,
+					httpmock.NewStringResponder(400, `{"error":{"type":"illegal_argument_exception"}}`).Once(failMessage),
+				)
+			})
+
+			It("should fail", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).To(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s failed to update cluster settings with OpenSearch API", opensearchAPIError)}))
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("no settings were applied", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("settings were applied", func() {
+			BeforeEach(func() {
+				instance.Status.Persistent = map[string]string{"cluster.max_shards_per_node": "2000"}
+				instance.Status.Transient = map[string]string{"indices.recovery.max_bytes_per_sec": "100mb"}
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			When("cluster exists", func() {
+				var body string
+
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						settingsUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+				})
+
+				It("should reset the applied settings", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					Expect(body).To(MatchJSON(`{"persistent":{"cluster.max_shards_per_node":null},
+						"transient":{"indices.recovery.max_bytes_per_sec":null}}`))
+				})
+			})
+		})
+	})
+})
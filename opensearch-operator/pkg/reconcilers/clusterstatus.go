@@ -0,0 +1,51 @@
+package reconcilers
+
+import (
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// componentTemplateClusterStates is the template by cluster by state matrix, 1 for the current state of a component
+// template on a cluster
+var componentTemplateClusterStates = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "opensearch_operator_component_template_cluster_state",
+	Help: "State of the component templates per targeted cluster, 1 for the current state of a component template on a cluster.",
+}, []string{"namespace", "name", "cluster", "state"})
+
+func init() {
+	metrics.Registry.MustRegister(componentTemplateClusterStates)
+}
+
+// setClusterStatus records the state and reason of the component template on the cluster it targets, together with
+// the phase of the cluster. Entries of clusters no longer targeted are dropped, and the transition time only moves
+// when the state on the cluster changes.
+func setClusterStatus(status *opsterv1.OpensearchComponentTemplateStatus, clusterName string, clusterPhase string, now metav1.Time) {
+	entry := opsterv1.ComponentTemplateClusterStatus{
+		Name:               clusterName,
+		ClusterPhase:       clusterPhase,
+		State:              status.State,
+		Reason:             status.Reason,
+		LastTransitionTime: now,
+	}
+	for _, previous := range status.Clusters {
+		if previous.Name == clusterName && previous.State == status.State {
+			entry.LastTransitionTime = previous.LastTransitionTime
+		}
+	}
+	status.Clusters = []opsterv1.ComponentTemplateClusterStatus{entry}
+}
+
+// reportClusterStates exports the per-cluster states of the component template as metrics
+func reportClusterStates(namespace string, name string, clusters []opsterv1.ComponentTemplateClusterStatus) {
+	forgetClusterStates(namespace, name)
+	for _, cluster := range clusters {
+		componentTemplateClusterStates.WithLabelValues(namespace, name, cluster.Name, string(cluster.State)).Set(1)
+	}
+}
+
+// forgetClusterStates removes the per-cluster states of a deleted component template from the metrics
+func forgetClusterStates(namespace string, name string) {
+	componentTemplateClusterStates.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
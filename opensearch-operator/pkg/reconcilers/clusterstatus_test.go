@@ -0,0 +1,94 @@
+package reconcilers
+
+import (
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("per-cluster component template status", func() {
+	var (
+		status  *opsterv1.OpensearchComponentTemplateStatus
+		started metav1.Time
+	)
+
+	BeforeEach(func() {
+		started = metav1.NewTime(time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+		status = &opsterv1.OpensearchComponentTemplateStatus{
+			State: opsterv1.OpensearchComponentTemplateCreated,
+		}
+		setClusterStatus(status, "test-cluster", opsterv1.PhaseRunning, started)
+	})
+
+	It("should record the state on the targeted cluster", func() {
+		Expect(status.Clusters).To(Equal([]opsterv1.ComponentTemplateClusterStatus{{
+			Name:               "test-cluster",
+			ClusterPhase:       opsterv1.PhaseRunning,
+			State:              opsterv1.OpensearchComponentTemplateCreated,
+			LastTransitionTime: started,
+		}}))
+	})
+
+	It("should keep the transition time while the state does not change", func() {
+		setClusterStatus(status, "test-cluster", opsterv1.PhaseRunning, metav1.NewTime(started.Add(time.Minute)))
+		Expect(status.Clusters[0].LastTransitionTime).To(Equal(started))
+	})
+
+	It("should follow the cluster out of and back into running", func() {
+		left := metav1.NewTime(started.Add(time.Minute))
+		status.State = opsterv1.OpensearchComponentTemplatePending
+		status.Reason = "waiting for opensearch cluster status to be running"
+		setClusterStatus(status, "test-cluster", opsterv1.PhasePending, left)
+		Expect(status.Clusters).To(HaveLen(1))
+		Expect(status.Clusters[0].ClusterPhase).To(Equal(opsterv1.PhasePending))
+		Expect(status.Clusters[0].State).To(Equal(opsterv1.OpensearchComponentTemplatePending))
+		Expect(status.Clusters[0].Reason).To(Equal("waiting for opensearch cluster status to be running"))
+		Expect(status.Clusters[0].LastTransitionTime).To(Equal(left))
+
+		returned := metav1.NewTime(started.Add(2 * time.Minute))
+		status.State = opsterv1.OpensearchComponentTemplateCreated
+		status.Reason = ""
+		setClusterStatus(status, "test-cluster", opsterv1.PhaseRunning, returned)
+		Expect(status.Clusters[0].ClusterPhase).To(Equal(opsterv1.PhaseRunning))
+		Expect(status.Clusters[0].State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+		Expect(status.Clusters[0].Reason).To(BeEmpty())
+		Expect(status.Clusters[0].LastTransitionTime).To(Equal(returned))
+	})
+
+	It("should drop clusters that are no longer targeted", func() {
+		setClusterStatus(status, "other-cluster", "", started)
+		Expect(status.Clusters).To(HaveLen(1))
+		Expect(status.Clusters[0].Name).To(Equal("other-cluster"))
+		Expect(status.Clusters[0].ClusterPhase).To(BeEmpty())
+	})
+
+	It("should export one current state per template and cluster", func() {
+		reportClusterStates("status-namespace", "status-template", status.Clusters)
+		Expect(testutil.ToFloat64(componentTemplateClusterStates.WithLabelValues(
+			"status-namespace", "status-template", "test-cluster", string(opsterv1.OpensearchComponentTemplateCreated),
+		))).To(Equal(1.0))
+
+		status.State = opsterv1.OpensearchComponentTemplateError
+		setClusterStatus(status, "test-cluster", opsterv1.PhaseRunning, started)
+		reportClusterStates("status-namespace", "status-template", status.Clusters)
+		Expect(componentTemplateClusterStates.DeleteLabelValues(
+			"status-namespace", "status-template", "test-cluster", string(opsterv1.OpensearchComponentTemplateCreated),
+		)).To(BeFalse())
+		Expect(componentTemplateClusterStates.DeleteLabelValues(
+			"status-namespace", "status-template", "test-cluster", string(opsterv1.OpensearchComponentTemplateError),
+		)).To(BeTrue())
+	})
+
+	It("should remove the states of deleted component templates", func() {
+		reportClusterStates("status-namespace", "status-template", status.Clusters)
+		forgetClusterStates("status-namespace", "status-template")
+		Expect(componentTemplateClusterStates.DeletePartialMatch(map[string]string{
+			"namespace": "status-namespace",
+			"name":      "status-template",
+		})).To(BeZero())
+	})
+})
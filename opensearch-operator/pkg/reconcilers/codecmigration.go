@@ -0,0 +1,232 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	opensearchCodecMigration = "OpensearchComponentTemplateCodecMigration"
+
+	// defaultCodecMigrationMaxIndices is the number of indices a codec migration is limited to if the spec sets none
+	defaultCodecMigrationMaxIndices = 10
+)
+
+// migrateCodec migrates the existing indices matching the index patterns of the codec migration to the codec of the
+// applied component template. The indices using another codec are enumerated once per codec and recorded in the
+// status. With the ForceMerge mode one index is migrated per reconcile while the cluster is green, and the force merge
+// is followed with the tasks API until it completes. Switching from the Enumerate to the ForceMerge mode migrates the
+// indices enumerated before.
+func (r *ComponentTemplateReconciler) migrateCodec(template requests.ComponentTemplate) (ctrl.Result, string, error) {
+	migration := r.instance.Spec.CodecMigration
+	codec, err := helpers.IndexCodec(template.Template.Settings)
+	if err != nil {
+		reason := "failed to parse component template settings"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if codec == "" {
+		codec = helpers.DefaultIndexCodec
+	}
+
+	status := r.instance.Status.CodecMigration
+	if status == nil || status.Codec != codec {
+		return r.enumerateCodecMigration(migration, codec)
+	}
+	if migration.Mode != opsterv1.CodecMigrationForceMerge || status.CompletedAt != nil {
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+	}
+
+	for _, index := range status.Indices {
+		if index.State == opsterv1.CodecMigrationIndexMerging {
+			return r.followForceMerge(index)
+		}
+	}
+	for _, index := range status.Indices {
+		if index.State == opsterv1.CodecMigrationIndexPending {
+			return r.migrateIndexCodec(index.Name, codec)
+		}
+	}
+
+	var migrated, failed int
+	for _, index := range status.Indices {
+		if index.State == opsterv1.CodecMigrationIndexMigrated {
+			migrated++
+		} else {
+			failed++
+		}
+	}
+	if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		if status.CodecMigration != nil {
+			now := metav1.Now()
+			status.CodecMigration.CompletedAt = &now
+		}
+	}); err != nil {
+		reason := fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchCodecMigration,
+		fmt.Sprintf("migration to the %s codec completed, %d indices migrated, %d failed", codec, migrated, failed))
+	return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+}
+
+// enumerateCodecMigration records the indices matching the index patterns that use another codec than codec as
+// pending. It fails if there are more of them than the migration is limited to.
+func (r *ComponentTemplateReconciler) enumerateCodecMigration(migration *opsterv1.CodecMigration, codec string) (ctrl.Result, string, error) {
+	codecs, err := services.IndexCodecs(r.ctx, r.osClient, migration.IndexPatterns)
+	if errors.Is(err, services.ErrForbidden) {
+		reason := "operator user is not authorized to enumerate the indices of the codec migration, check that it has the indices:monitor/settings/get privilege"
+		r.recorder.Event(r.instance, "Warning", opensearchForbidden, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if err != nil {
+		reason := "failed to get the codecs of the indices from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return ctrl.Result{}, reason, err
+	}
+
+	var names []string
+	for name, indexCodec := range codecs {
+		if indexCodec != codec {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	maxIndices := defaultCodecMigrationMaxIndices
+	if migration.MaxIndices != nil {
+		maxIndices = int(*migration.MaxIndices)
+	}
+	if len(names) > maxIndices {
+		reason := fmt.Sprintf("%d indices use another codec than %s, more than the %d indices the codec migration is limited to", len(names), codec, maxIndices)
+		r.recorder.Event(r.instance, "Warning", opensearchCodecMigration, reason)
+		return ctrl.Result{}, reason, errors.New(reason)
+	}
+
+	now := metav1.Now()
+	status := &opsterv1.CodecMigrationStatus{Codec: codec, StartedAt: now}
+	for _, name := range names {
+		status.Indices = append(status.Indices, opsterv1.CodecMigrationIndex{Name: name, State: opsterv1.CodecMigrationIndexPending})
+	}
+	if len(names) == 0 {
+		status.CompletedAt = &now
+	}
+	if err := r.updateTemplateStatus(func(s *opsterv1.OpensearchComponentTemplateStatus) {
+		s.CodecMigration = status
+	}); err != nil {
+		reason := fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchCodecMigration,
+		fmt.Sprintf("%d indices use another codec than %s", len(names), codec))
+	return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, "", nil
+}
+
+// migrateIndexCodec changes the codec of the index and starts force merging it. The index is closed while its codec
+// is changed and opened again even if changing it fails.
+func (r *ComponentTemplateReconciler) migrateIndexCodec(name string, codec string) (ctrl.Result, string, error) {
+	deferred, reason, err := r.deferForRequiredHealth(opsterv1.OpenSearchGreenHealth)
+	if err != nil {
+		return ctrl.Result{}, reason, err
+	}
+	if deferred != "" {
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, deferred)
+		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, deferred, nil
+	}
+
+	// The index may have been migrated or deleted since it was enumerated
+	codecs, err := services.IndexCodecs(r.ctx, r.osClient, []string{name})
+	if err != nil {
+		reason := "failed to get the codecs of the indices from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	indexCodec, exists := codecs[name]
+	switch {
+	case !exists:
+		return r.setCodecMigrationIndex(opsterv1.CodecMigrationIndex{Name: name, State: opsterv1.CodecMigrationIndexFailed, Reason: "index does not exist"})
+	case indexCodec == codec:
+		return r.setCodecMigrationIndex(opsterv1.CodecMigrationIndex{Name: name, State: opsterv1.CodecMigrationIndexMigrated})
+	}
+
+	task, err := r.changeIndexCodec(name, codec)
+	if err != nil {
+		reason := fmt.Sprintf("failed to migrate index %s to the %s codec: %s", name, codec, err)
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchCodecMigration, reason)
+		return r.setCodecMigrationIndex(opsterv1.CodecMigrationIndex{Name: name, State: opsterv1.CodecMigrationIndexFailed, Reason: err.Error()})
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchCodecMigration, fmt.Sprintf("migrating index %s to the %s codec", name, codec))
+	return r.setCodecMigrationIndex(opsterv1.CodecMigrationIndex{Name: name, State: opsterv1.CodecMigrationIndexMerging, Task: task})
+}
+
+// changeIndexCodec closes the index, sets its codec, opens it again and returns the task force merging it
+func (r *ComponentTemplateReconciler) changeIndexCodec(name string, codec string) (string, error) {
+	if err := services.CloseIndex(r.ctx, r.osClient, name); err != nil {
+		return "", err
+	}
+	err := services.SetIndexCodec(r.ctx, r.osClient, name, codec)
+	if openErr := services.OpenIndex(r.ctx, r.osClient, name); openErr != nil {
+		if err != nil {
+			return "", fmt.Errorf("%s, index is left closed: %w", err, openErr)
+		}
+		return "", fmt.Errorf("index is left closed: %w", openErr)
+	}
+	if err != nil {
+		return "", err
+	}
+	return services.ForceMergeIndex(r.ctx, r.osClient, name)
+}
+
+// followForceMerge records the index as migrated once its force merge completed
+func (r *ComponentTemplateReconciler) followForceMerge(index opsterv1.CodecMigrationIndex) (ctrl.Result, string, error) {
+	task, err := services.GetTask(r.ctx, r.osClient, index.Task)
+	if err != nil {
+		reason := "failed to get the force merge task from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if !task.Completed {
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+	}
+	if task.Error != nil {
+		reason := fmt.Sprintf("failed to force merge index %s: %s", index.Name, task.Error.Reason)
+		r.recorder.Event(r.instance, "Warning", opensearchCodecMigration, reason)
+		return r.setCodecMigrationIndex(opsterv1.CodecMigrationIndex{Name: index.Name, State: opsterv1.CodecMigrationIndexFailed, Reason: task.Error.Reason})
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchCodecMigration, fmt.Sprintf("index %s migrated to the %s codec", index.Name, r.instance.Status.CodecMigration.Codec))
+	return r.setCodecMigrationIndex(opsterv1.CodecMigrationIndex{Name: index.Name, State: opsterv1.CodecMigrationIndexMigrated})
+}
+
+// setCodecMigrationIndex replaces the state of the index in the codec migration and requeues for the next index
+func (r *ComponentTemplateReconciler) setCodecMigrationIndex(index opsterv1.CodecMigrationIndex) (ctrl.Result, string, error) {
+	if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		if status.CodecMigration == nil {
+			return
+		}
+		for i := range status.CodecMigration.Indices {
+			if status.CodecMigration.Indices[i].Name == index.Name {
+				status.CodecMigration.Indices[i] = index
+			}
+		}
+	}); err != nil {
+		reason := fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, "", nil
+}
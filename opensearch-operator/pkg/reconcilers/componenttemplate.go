@@ -2,16 +2,20 @@ package reconcilers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
@@ -22,7 +26,18 @@ import (
 
 const (
 	opensearchComponentTemplateExists       = "component template already exists in OpenSearch; not modifying"
+	opensearchComponentTemplateRolledBack   = "component template is rolled back to the previously applied version"
 	opensearchComponentTemplateNameMismatch = "OpensearchComponentTemplateNameMismatch"
+	opensearchApproval                      = "OpensearchComponentTemplateApproval"
+	opensearchCatFallback                   = "OpensearchComponentTemplateCatFallback"
+
+	// OpenSearch privileges required by the operator user to manage component templates
+	componentTemplateGetPrivilege    = "cluster:admin/component_template/get"
+	componentTemplatePutPrivilege    = "cluster:admin/component_template/put"
+	componentTemplateDeletePrivilege = "cluster:admin/component_template/delete"
+	nodesInfoPrivilege               = "cluster:monitor/nodes/info"
+	catIndicesPrivilege              = "indices:monitor/settings/get"
+	clusterHealthPrivilege           = "cluster:monitor/health"
 )
 
 type ComponentTemplateReconciler struct {
@@ -34,6 +49,9 @@ type ComponentTemplateReconciler struct {
 	instance *opsterv1.OpensearchComponentTemplate
 	cluster  *opsterv1.OpenSearchCluster
 	logger   logr.Logger
+	// Generation of the spec the reconcile started from. Status writes refresh the instance, which then may hold a
+	// newer spec than the one being reconciled
+	generation int64
 }
 
 func NewComponentTemplateReconciler(
@@ -46,10 +64,10 @@ func NewComponentTemplateReconciler(
 	options := ReconcilerOptions{}
 	options.apply(opts...)
 	return &ComponentTemplateReconciler{
-		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "role"))),
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "componenttemplate"))),
 		ReconcilerOptions: options,
 		ctx:               ctx,
-		recorder:          recorder,
+		recorder:          newVerbosityRecorder(recorder, options.eventVerbosity),
 		instance:          instance,
 		logger:            log.FromContext(ctx).WithValues("reconciler", "componenttemplate"),
 	}
@@ -57,6 +75,12 @@ func NewComponentTemplateReconciler(
 
 func (r *ComponentTemplateReconciler) Reconcile() (result ctrl.Result, err error) {
 	var reason string
+	r.generation = r.instance.Generation
+
+	// Deferred before the status update so it runs after it, the state is derived from the exact interval
+	defer func() {
+		result.RequeueAfter = helpers.Jitter(result.RequeueAfter, r.requeueJitter)
+	}()
 
 	defer func() {
 		if !pointer.BoolDeref(r.updateStatus, true) {
@@ -64,37 +88,95 @@ func (r *ComponentTemplateReconciler) Reconcile() (result ctrl.Result, err error
 		}
 		// When the reconciler is done, figure out what the state of the resource
 		// is and set it in the state field accordingly.
+		var previousState, state string
+		var clusters []opsterv1.ComponentTemplateClusterStatus
+		var stale bool
+		reconcileErr := err
 		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
 			instance := object.(*opsterv1.OpensearchComponentTemplate)
+			// The state of a spec that was changed during the reconcile is not recorded, the new spec is reconciled
+			// right away instead
+			if instance.Generation != r.generation && reconcileErr == nil && r.staleSpecPolicy != StaleSpecPolicyRecord {
+				stale = true
+				return
+			}
+			previousState = string(instance.Status.State)
 			instance.Status.Reason = reason
 			if err != nil {
 				instance.Status.State = opsterv1.OpensearchComponentTemplateError
+				if errors.Is(err, services.ErrForbidden) {
+					instance.Status.State = opsterv1.OpensearchComponentTemplateForbidden
+				}
 			}
 			if result.Requeue && result.RequeueAfter == 10*time.Second {
 				instance.Status.State = opsterv1.OpensearchComponentTemplatePending
 			}
+			if reason == opensearchClusterFrozen || strings.HasPrefix(reason, opensearchAwaitingSnapshot) ||
+				strings.HasPrefix(reason, opensearchClusterUpgrading) || strings.HasPrefix(reason, opensearchClusterHealthDeferred) {
+				instance.Status.State = opsterv1.OpensearchComponentTemplateDeferred
+			}
 			if err == nil && result.RequeueAfter == 30*time.Second {
 				instance.Status.State = opsterv1.OpensearchComponentTemplateCreated
+				if instance.Spec.ApplyMode == opsterv1.TemplateApplyModeCreateOnly {
+					instance.Status.CreatedOnce = true
+				}
 			}
 			if reason == opensearchComponentTemplateExists {
 				instance.Status.State = opsterv1.OpensearchComponentTemplateIgnored
 			}
+			if err == nil && reason == opensearchComponentTemplateRolledBack {
+				instance.Status.State = opsterv1.OpensearchComponentTemplateRolledBack
+			}
+			if err == nil && reason == opensearchAwaitingApproval {
+				instance.Status.State = opsterv1.OpensearchComponentTemplateAwaitingApproval
+			}
+			if err == nil && reason == opensearchComponentTemplateExpired {
+				instance.Status.State = opsterv1.OpensearchComponentTemplateExpired
+			}
+			state = string(instance.Status.State)
+			clusterPhase := ""
+			if r.cluster != nil {
+				clusterPhase = r.cluster.Status.Phase
+			}
+			setClusterStatus(&instance.Status, componentTemplateClusterName(instance), clusterPhase, metav1.Now())
+			clusters = instance.Status.Clusters
 		})
 
 		if err != nil {
 			r.logger.Error(err, "failed to update status")
+			return
+		}
+		if stale {
+			r.logger.Info(fmt.Sprintf("spec of component template %s changed from generation %d to %d during the reconcile, reconciling it again", r.instance.Name, r.generation, r.instance.Generation))
+			result = ctrl.Result{Requeue: true}
+			return
+		}
+		r.statusPusher.Report(r.instance.Namespace, r.instance.Name, state)
+		reportClusterStates(r.instance.Namespace, r.instance.Name, clusters)
+		r.reportDeadLetter(state, reason, clusters)
+
+		err = recordReconcileTransition(r.client, r.reconcileLog, r.instance, "OpensearchComponentTemplate", previousState, state, reason)
+		if err != nil {
+			r.logger.Error(err, "failed to write reconcile log")
 		}
 	}()
 
-	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
-		Name:      r.instance.Spec.OpensearchRef.Name,
-		Namespace: r.instance.Namespace,
-	})
-	if err != nil {
-		reason = "error fetching opensearch cluster"
-		r.logger.Error(err, "failed to fetch opensearch cluster")
-		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
-		return
+	if r.instance.Spec.RegionAffinity != nil {
+		r.cluster, reason, err = r.selectRegionCluster()
+		if err != nil {
+			return
+		}
+	} else {
+		r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+			Name:      r.instance.Spec.OpensearchRef.Name,
+			Namespace: r.instance.Namespace,
+		})
+		if err != nil {
+			reason = "error fetching opensearch cluster"
+			r.logger.Error(err, "failed to fetch opensearch cluster")
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
 	}
 
 	if r.cluster == nil {
@@ -111,9 +193,22 @@ func (r *ComponentTemplateReconciler) Reconcile() (result ctrl.Result, err error
 	// Check cluster ref has not changed
 	if r.instance.Status.ManagedCluster != nil {
 		if *r.instance.Status.ManagedCluster != r.cluster.UID {
-			reason = "cannot change the cluster a component template refers to"
-			err = fmt.Errorf("%s", reason)
-			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			if r.instance.Status.ManagedClusterName != r.cluster.Name {
+				reason = "cannot change the cluster a component template refers to"
+				err = fmt.Errorf("%s", reason)
+				r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+				return
+			}
+			err = r.readoptCluster(fmt.Sprintf("OpenSearchCluster %s was recreated, the component template is applied to the new cluster", r.cluster.Name))
+		} else if r.instance.Status.ManagedClusterName == "" {
+			// Recorded for component templates managed before the name was kept
+			err = r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+				status.ManagedClusterName = r.cluster.Name
+			})
+		}
+		if err != nil {
+			reason = fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
 			return
 		}
 	} else {
@@ -121,6 +216,7 @@ func (r *ComponentTemplateReconciler) Reconcile() (result ctrl.Result, err error
 			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
 				instance := object.(*opsterv1.OpensearchComponentTemplate)
 				instance.Status.ManagedCluster = &r.cluster.UID
+				instance.Status.ManagedClusterName = r.cluster.Name
 			})
 			if err != nil {
 				reason = fmt.Sprintf("failed to update status: %s", err)
@@ -130,8 +226,8 @@ func (r *ComponentTemplateReconciler) Reconcile() (result ctrl.Result, err error
 		}
 	}
 
-	// Check cluster is ready
-	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+	// Check cluster is ready, serverless endpoints are checked with a ping once the client is created
+	if !serverlessEndpoint(r.cluster) && r.cluster.Status.Phase != opsterv1.PhaseRunning {
 		r.logger.Info("opensearch cluster is not running, requeueing")
 		reason = "waiting for opensearch cluster status to be running"
 		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
@@ -142,13 +238,96 @@ func (r *ComponentTemplateReconciler) Reconcile() (result ctrl.Result, err error
 		return
 	}
 
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterUpgrading(r.upgradeHandling, r.cluster) {
+		r.logger.Info("opensearch cluster is being upgraded, requeueing")
+		reason = opensearchClusterUpgrading
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: time.Minute,
+		}
+		return
+	}
+
+	// Right after the operator started, the templates are read from the cache once it is warmed
+	r.cacheWarmer.wait(r.ctx)
+
+	r.requestLog.Activate(r.instance.Namespace, r.instance.Name, string(r.instance.UID), r.instance.Annotations[helpers.LogRequestsAnnotation] == "true")
+	r.osClient, err = r.createClient(util.OpensearchClusterURL(r.cluster), services.WithETagCache(r.etagCache))
+	if errors.Is(err, services.ErrClientCertificate) {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchClientCertificate, reason)
+		return
+	}
+	if errors.Is(err, services.ErrBearerToken) {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchBearerToken, reason)
+		return
+	}
+	if errors.Is(err, services.ErrProxy) {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchProxy, reason)
+		return
+	}
 	if err != nil {
 		reason = "error creating opensearch client"
 		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
 		return
 	}
 
+	if serverlessEndpoint(r.cluster) {
+		if pingErr := services.Ping(r.ctx, r.osClient); pingErr != nil {
+			r.logger.Info("serverless endpoint does not respond, requeueing", "error", pingErr.Error())
+			reason = "waiting for the serverless endpoint to respond"
+			r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+			result = ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: 10 * time.Second,
+			}
+			return
+		}
+	}
+
+	if err = r.checkClusterUUID(); err != nil {
+		reason = fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return
+	}
+
+	var skew string
+	skew, err = nodeVersionSkew(r.ctx, r.upgradeHandling, r.cluster, r.osClient)
+	if errors.Is(err, services.ErrForbidden) {
+		reason = r.forbidden(nodesInfoPrivilege)
+		return
+	}
+	if err != nil {
+		reason = "failed to get the versions of the nodes from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+	if skew != "" {
+		r.logger.Info("opensearch cluster nodes run different versions, requeueing")
+		reason = skew
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: time.Minute,
+		}
+		return
+	}
+
 	templateName := r.instance.Name
 	if r.instance.Spec.Name != "" {
 		templateName = r.instance.Spec.Name
@@ -158,6 +337,13 @@ func (r *ComponentTemplateReconciler) Reconcile() (result ctrl.Result, err error
 	if r.instance.Status.ExistingComponentTemplate == nil {
 		var exists bool
 		exists, err = services.ComponentTemplateExists(r.ctx, r.osClient, templateName)
+		if errors.Is(err, services.ErrForbidden) {
+			exists, err = r.existsFromCatTemplates(templateName)
+		}
+		if errors.Is(err, services.ErrForbidden) {
+			reason = r.forbidden(componentTemplateGetPrivilege)
+			return
+		}
 		if err != nil {
 			reason = "failed to get component template status from OpenSearch API"
 			r.logger.Error(err, reason)
@@ -195,37 +381,270 @@ func (r *ComponentTemplateReconciler) Reconcile() (result ctrl.Result, err error
 		return
 	}
 
+	var expired bool
+	expired, result, reason, err = r.checkExpiry(templateName)
+	if expired || err != nil {
+		return
+	}
+
+	if r.instance.Spec.TransactionGroup != "" {
+		if reason = unsupportedInTransactionGroup(r.instance); reason != "" {
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+	}
+
+	if r.instance.Spec.ApplyMode == opsterv1.TemplateApplyModeCreateOnly {
+		if r.instance.Status.CreatedOnce {
+			var exists bool
+			exists, err = services.ComponentTemplateExists(r.ctx, r.osClient, templateName)
+			if errors.Is(err, services.ErrForbidden) {
+				reason = r.forbidden(componentTemplateGetPrivilege)
+				return
+			}
+			if err != nil {
+				reason = "failed to get component template status from OpenSearch API"
+				r.logger.Error(err, reason)
+				r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+				return
+			}
+			if exists {
+				r.logger.V(1).Info(fmt.Sprintf("component template %s was already created, not updating it in CreateOnly mode", r.instance.Name))
+				result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+				return
+			}
+			r.recorder.Event(r.instance, "Normal", opensearchPending, "component template was deleted from opensearch, creating it again")
+		}
+	}
+
+	if rollbackRequested(r.instance, r.generation) {
+		result, reason, err = r.rollback(templateName)
+		return
+	}
+	if r.instance.Annotations[helpers.RollbackAnnotation] != "true" && r.instance.Status.RollbackGeneration != 0 {
+		if err = r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+			status.RollbackGeneration = 0
+		}); err != nil {
+			reason = fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return
+		}
+	}
+
+	if r.instance.Spec.TransactionGroup != "" {
+		result, reason, err = r.reconcileTransactionGroup()
+		return
+	}
+
 	// rewrite the CRD format to the gateway format
-	resource := helpers.TranslateComponentTemplateToRequest(r.instance.Spec)
+	resource := translateComponentTemplate(r.instance)
+	if reason, err = r.checkComponentTemplate(r.instance, &resource); err != nil {
+		return
+	}
 
-	shouldUpdate, err := services.ShouldUpdateComponentTemplate(r.ctx, r.osClient, templateName, resource)
-	if err != nil {
+	live, err := services.GetComponentTemplate(r.ctx, r.osClient, templateName)
+	// Without read access the component template is written whenever the spec changes instead of comparing it
+	unreadable := errors.Is(err, services.ErrForbidden)
+	if unreadable {
+		var applied bool
+		if applied, err = r.specApplied(resource); err != nil {
+			reason = "failed to hash the component template"
+			return
+		}
+		if applied {
+			r.logger.V(1).Info(fmt.Sprintf("component template %s cannot be read, it was applied for the current spec", r.instance.Name))
+			result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+			return
+		}
+		r.recorder.Event(r.instance, "Warning", opensearchCatFallback,
+			"component templates cannot be read from OpenSearch, applying the spec without comparing it with the component template in OpenSearch")
+	}
+	if err != nil && !unreadable {
 		reason = "failed to get component template status from OpenSearch API"
 		r.logger.Error(err, reason)
 		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
 		return
 	}
 
-	if !shouldUpdate {
+	if live != nil && helpers.ComponentTemplatesEqual(resource, *live) {
 		r.logger.V(1).Info(fmt.Sprintf("component template %s is in sync", r.instance.Name))
-		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		result, reason, err = r.completeInSync(templateName, resource, *live)
+		return
+	}
+
+	if live != nil {
+		var keep bool
+		keep, reason, err = r.handleExternalEdit(*live)
+		if err != nil {
+			return
+		}
+		if keep {
+			result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+			return
+		}
+	}
+
+	if reason, err = r.checkBeforeWrite(r.instance, resource); err != nil {
 		return
 	}
+	r.checkFieldCount(resource)
 
-	err = services.CreateOrUpdateComponentTemplate(r.ctx, r.osClient, templateName, resource)
+	if r.instance.Spec.RequireApproval {
+		var awaiting bool
+		awaiting, err = r.awaitApproval(resource, live)
+		if err != nil {
+			reason = fmt.Sprintf("failed to record the change awaiting approval: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return
+		}
+		if awaiting {
+			reason = opensearchAwaitingApproval
+			result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+			return
+		}
+	}
+
+	waiting, err := r.deferForSyncWave()
+	if err != nil {
+		reason = fmt.Sprintf("failed to order the component template by its sync wave: %s", err)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+	if waiting != "" {
+		reason = waiting
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+		return
+	}
+
+	deferred, reason, err := r.deferForRequiredHealth(r.instance.Spec.RequiredClusterHealth)
+	if err != nil {
+		return
+	}
+	if deferred != "" {
+		reason = deferred
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+		return
+	}
+	if deferred, reason, err = r.deferForSnapshot(r.instance.Spec.RequireRecentSnapshot); err != nil {
+		return
+	}
+	if deferred != "" {
+		reason = deferred
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{Requeue: true, RequeueAfter: time.Minute}
+		return
+	}
+	if reason, err = r.validateWithCanary(r.instance, templateName, resource); err != nil {
+		return
+	}
+
+	release, err := r.applyQueue.Acquire(r.ctx, r.cluster.UID, r.instance.Spec.ApplyPriority)
+	if err != nil {
+		reason = "failed to wait for other applies to the cluster"
+		return
+	}
+	var deprecations []string
+	writeCtx, trace := r.traceWrite()
+	if unreadable {
+		// Conditional writes need the live template, which cannot be read
+		deprecations, err = services.CreateOrUpdateComponentTemplate(writeCtx, r.osClient, templateName, resource)
+	} else {
+		live, deprecations, err = r.writeComponentTemplate(writeCtx, templateName, resource, live)
+	}
+	release()
+	if errors.Is(err, services.ErrForbidden) {
+		reason = r.forbidden(componentTemplatePutPrivilege)
+		return
+	}
+	if errors.Is(err, services.ErrConcurrentModification) {
+		reason = "component template is modified concurrently by another writer"
+		return
+	}
 	if err != nil {
 		reason = "failed to update component template with OpenSearch API"
 		r.logger.Error(err, reason)
 		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+	if err = r.recordRequestTrace(trace); err != nil {
+		reason = fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return
 	}
 
-	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "component template updated in opensearch")
+	if reason, err = r.reportDeprecations(templateName, deprecations, r.instance.Spec.FailOnDeprecatedSettings); err != nil {
+		// The replaced component template is unknown if it could not be read, it must not be deleted
+		if !unreadable {
+			r.revertWrite(templateName, live)
+		}
+		return
+	}
 
-	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	// Without read access the node pools cannot be asked for the component template either
+	if result, reason, err = r.completeWrite(templateName, resource, live, !unreadable); err != nil || reason != "" {
+		return
+	}
+	result, reason, err = r.afterApply(templateName, resource)
 	return
 }
 
-func (r *ComponentTemplateReconciler) Delete() error {
+// translateComponentTemplate rewrites the component template to the gateway format, with the Git revision of the
+// opensearch.opster.io/git-revision annotation recorded in its _meta
+func translateComponentTemplate(instance *opsterv1.OpensearchComponentTemplate) requests.ComponentTemplate {
+	template := helpers.TranslateComponentTemplateToRequest(instance.Spec)
+	if revision := instance.Annotations[helpers.GitRevisionAnnotation]; revision != "" {
+		template.Meta = helpers.WithProvenance(template.Meta, revision)
+	}
+	return template
+}
+
+// createClient creates the client sending the requests of the component template to url of the cluster. With a client
+// factory the client is shared between component templates, their requests are identified by the context instead.
+func (r *ComponentTemplateReconciler) createClient(url string, opts ...services.OsClusterClientOption) (*services.OsClusterClient, error) {
+	opts = r.clientOptions(opts...)
+	// Previews are not of a stored component template and not identified
+	identified := r.instance.UID != ""
+	if r.clientFactory != nil {
+		if identified {
+			r.ctx = util.ContextForObject(r.ctx, r.instance)
+		}
+		return r.clientFactory.Get(r.client, r.ctx, r.cluster, url, r.osClientTransport, opts...)
+	}
+	if identified {
+		opts = append(opts, util.ClientOptionForObject(r.instance))
+	}
+	return util.CreateClientForURL(r.client, r.ctx, r.cluster, url, r.osClientTransport, opts...)
+}
+
+// updateTemplateStatus changes the status of the instance, only in memory if status updates are disabled
+func (r *ComponentTemplateReconciler) updateTemplateStatus(f func(status *opsterv1.OpensearchComponentTemplateStatus)) error {
+	return r.updateComponentTemplateStatus(r.instance, f)
+}
+
+// updateComponentTemplateStatus changes the status of the instance or a member of its transaction group, only in
+// memory if status updates are disabled
+func (r *ComponentTemplateReconciler) updateComponentTemplateStatus(instance *opsterv1.OpensearchComponentTemplate, f func(status *opsterv1.OpensearchComponentTemplateStatus)) error {
+	if !pointer.BoolDeref(r.updateStatus, true) {
+		f(&instance.Status)
+		return nil
+	}
+	return r.client.UdateObjectStatus(instance, func(object client.Object) {
+		f(&object.(*opsterv1.OpensearchComponentTemplate).Status)
+	})
+}
+
+func (r *ComponentTemplateReconciler) Delete() (err error) {
+	defer func() {
+		if err == nil {
+			r.statusPusher.Forget(r.instance.Namespace, r.instance.Name)
+			forgetClusterStates(r.instance.Namespace, r.instance.Name)
+			forgetDeadLetter(r.deadLetters, "OpensearchComponentTemplate", r.instance.Namespace, r.instance.Name)
+		}
+	}()
+
 	// If we have never successfully reconciled we can just exit
 	if r.instance.Status.ExistingComponentTemplate == nil {
 		return nil
@@ -236,10 +655,8 @@ func (r *ComponentTemplateReconciler) Delete() error {
 		return nil
 	}
 
-	var err error
-
 	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
-		Name:      r.instance.Spec.OpensearchRef.Name,
+		Name:      componentTemplateClusterName(r.instance),
 		Namespace: r.instance.Namespace,
 	})
 	if err != nil {
@@ -250,18 +667,44 @@ func (r *ComponentTemplateReconciler) Delete() error {
 		// If the opensearch cluster doesn't exist, we don't need to delete anything
 		return nil
 	}
-
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
-	if err != nil {
-		return err
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+	if clusterUpgrading(r.upgradeHandling, r.cluster) {
+		return errClusterUpgrading
 	}
 
 	templateName := r.instance.Name
 	if r.instance.Spec.Name != "" {
 		templateName = r.instance.Spec.Name
 	}
+	if err = r.checkComposingIndexTemplates(templateName); err != nil {
+		return err
+	}
+
+	r.requestLog.Activate(r.instance.Namespace, r.instance.Name, string(r.instance.UID), r.instance.Annotations[helpers.LogRequestsAnnotation] == "true")
+	r.osClient, err = r.createClient(util.OpensearchClusterURL(r.cluster), services.WithETagCache(r.etagCache))
+	if err != nil {
+		return err
+	}
+	skew, err := nodeVersionSkew(r.ctx, r.upgradeHandling, r.cluster, r.osClient)
+	if err != nil {
+		return err
+	}
+	if skew != "" {
+		return errors.New(skew)
+	}
+
+	return r.deleteComponentTemplate(templateName)
+}
 
+// deleteComponentTemplate deletes the component template from OpenSearch, recording a tombstone first if enabled
+func (r *ComponentTemplateReconciler) deleteComponentTemplate(templateName string) error {
 	exist, err := services.ComponentTemplateExists(r.ctx, r.osClient, templateName)
+	if errors.Is(err, services.ErrForbidden) {
+		r.forbidden(componentTemplateGetPrivilege)
+		return err
+	}
 	if err != nil {
 		return err
 	}
@@ -270,5 +713,42 @@ func (r *ComponentTemplateReconciler) Delete() error {
 		return nil
 	}
 
-	return services.DeleteComponentTemplate(r.ctx, r.osClient, templateName)
+	if r.tombstones.Enabled {
+		// Record the tombstone before deleting so a failure to write it is retried instead of losing the body
+		if err := r.recordTombstone(templateName); err != nil {
+			return err
+		}
+	}
+
+	err = services.DeleteComponentTemplate(r.ctx, r.osClient, templateName)
+	if errors.Is(err, services.ErrForbidden) {
+		r.forbidden(componentTemplateDeletePrivilege)
+	}
+	return err
+}
+
+// recordTombstone stores the component template as it is applied in OpenSearch, falling back to the spec
+func (r *ComponentTemplateReconciler) recordTombstone(templateName string) error {
+	body, err := services.GetComponentTemplate(r.ctx, r.osClient, templateName)
+	if errors.Is(err, services.ErrForbidden) {
+		r.forbidden(componentTemplateGetPrivilege)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		translated := translateComponentTemplate(r.instance)
+		body = &translated
+	}
+	return recordTombstone(r.client, r.tombstones, r.redaction, r.instance, "OpensearchComponentTemplate", templateName, body)
+}
+
+// forbidden emits an event naming the OpenSearch privilege the operator user is most likely missing
+// and returns the reason to be stored in the status
+func (r *ComponentTemplateReconciler) forbidden(privilege string) string {
+	reason := fmt.Sprintf("operator user is not authorized to manage component templates, check that it has the %s privilege", privilege)
+	r.logger.Info(reason)
+	r.recorder.Event(r.instance, "Warning", opensearchForbidden, reason)
+	return reason
 }
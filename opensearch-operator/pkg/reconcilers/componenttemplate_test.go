@@ -1,17 +1,28 @@
 package reconcilers
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
 	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -19,9 +30,21 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// requireReplicasPolicy is a template policy denying component templates that do not set the number of replicas
+const requireReplicasPolicy = `
+package templates.replicas
+
+deny[msg] {
+	not input.template.settings.index.number_of_replicas
+	msg := "all templates must set index.number_of_replicas"
+}
+`
+
 var _ = Describe("componenttemplate reconciler", func() {
 	var (
 		transport  *httpmock.MockTransport
@@ -69,6 +92,7 @@ var _ = Describe("componenttemplate reconciler", func() {
 				General: opsterv1.GeneralConfig{
 					ServiceName: "test-cluster",
 					HttpPort:    9200,
+					Version:     "2.8.0",
 				},
 				NodePools: []opsterv1.NodePool{
 					{
@@ -145,9 +169,34 @@ var _ = Describe("componenttemplate reconciler", func() {
 		})
 	})
 
+	When("the component template was applied to another cluster", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			instance.Status.ManagedClusterName = "other-cluster"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should not adopt the cluster", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the cluster a component template refers to", opensearchRefMismatch)}))
+		})
+	})
+
 	When("cluster is not ready", func() {
 		BeforeEach(func() {
 			recorder = record.NewFakeRecorder(1)
+			cluster.Status.Phase = opsterv1.PhasePending
 			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
 		})
 
@@ -166,15 +215,178 @@ var _ = Describe("componenttemplate reconciler", func() {
 			Expect(len(events)).To(Equal(1))
 			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster status to be running", opensearchPending)))
 		})
+
+		It("should report the component template as pending on the cluster", func() {
+			mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+				RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+					f(obj)
+					return nil
+				})
+			reconciler.updateStatus = pointer.Bool(true)
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+			}()
+			for range recorder.Events {
+			}
+			Expect(instance.Status.Clusters).To(HaveLen(1))
+			Expect(instance.Status.Clusters[0].Name).To(Equal("test-cluster"))
+			Expect(instance.Status.Clusters[0].ClusterPhase).To(Equal(opsterv1.PhasePending))
+			Expect(instance.Status.Clusters[0].State).To(Equal(opsterv1.OpensearchComponentTemplatePending))
+			Expect(instance.Status.Clusters[0].Reason).To(Equal("waiting for opensearch cluster status to be running"))
+			Expect(testutil.ToFloat64(componentTemplateClusterStates.WithLabelValues(
+				instance.Namespace, instance.Name, "test-cluster", string(opsterv1.OpensearchComponentTemplatePending),
+			))).To(Equal(1.0))
+		})
 	})
 
-	Context("cluster is ready", func() {
-		extraContextCalls := 1
+	When("the client certificate of the operator is invalid", func() {
 		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(1)
 			cluster.Status.Phase = opsterv1.PhaseRunning
-			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			cluster.Spec.Security = &opsterv1.Security{
+				Config: &opsterv1.SecurityConfig{
+					OperatorAuthMode:         opsterv1.OperatorAuthModeClientCertificate,
+					OperatorClientCertSecret: corev1.LocalObjectReference{Name: "operator-client-cert"},
+				},
+			}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			mockClient.EXPECT().GetSecret("operator-client-cert", cluster.Namespace).Return(corev1.Secret{
+				Data: map[string][]byte{"tls.crt": []byte("not a certificate"), "tls.key": []byte("not a key")},
+			}, nil)
+		})
+
+		It("should report the client certificate", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(MatchError(services.ErrClientCertificate))
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(events).To(ConsistOf(HavePrefix(fmt.Sprintf(
+				"Warning %s client certificate authentication failed: invalid client certificate in secret operator-client-cert", opensearchClientCertificate,
+			))))
+			Expect(transport.GetTotalCallCount()).To(BeZero())
+		})
+	})
+
+	When("the proxy of the cluster is invalid", func() {
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(1)
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Annotations = map[string]string{helpers.ProxyURLAnnotation: "proxy.example.com:3128"}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+		})
+
+		It("should report the proxy", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(MatchError(services.ErrProxy))
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(events).To(ConsistOf(HavePrefix(fmt.Sprintf(
+				"Warning %s proxy connection failed, invalid proxy URL", opensearchProxy,
+			))))
+			Expect(transport.GetTotalCallCount()).To(BeZero())
+		})
+	})
+
+	Context("cluster is frozen", func() {
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(1)
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Annotations = map[string]string{helpers.FreezeAnnotation: "true"}
+			instance.Status.ExistingComponentTemplate = pointer.Bool(true)
+		})
+
+		JustBeforeEach(func() {
 			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+		})
+
+		reconcileEvents := func() []string {
+			mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+				RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+					f(obj)
+					return nil
+				})
+			reconciler.updateStatus = pointer.Bool(true)
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			return events
+		}
+
+		It("should defer changes without calling OpenSearch", func() {
+			Expect(reconcileEvents()).To(Equal([]string{fmt.Sprintf("Normal %s %s", opensearchDeferred, opensearchClusterFrozen)}))
+			Expect(transport.GetTotalCallCount()).To(BeZero())
+			Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateDeferred))
+		})
+
+		It("should not delete the component template", func() {
+			instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+			Expect(reconciler.Delete()).To(MatchError(errClusterFrozen))
+			Expect(transport.GetTotalCallCount()).To(BeZero())
+		})
+
+		When("the freeze annotation is removed", func() {
+			BeforeEach(func() {
+				cluster.Annotations[helpers.FreezeAnnotation] = "false"
+				transport.RegisterResponder(
+					http.MethodGet,
+					clusterUrl,
+					httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodHead,
+					clusterUrl,
+					httpmock.NewStringResponder(200, "OK").Once(failMessage),
+				)
+			})
+
+			It("should reconcile the component template again", func() {
+				Expect(reconcileEvents()).To(BeEmpty())
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateIgnored))
+			})
+		})
+	})
 
+	Context("cluster is being upgraded", func() {
+		var nodes []responses.CatNodesResponse
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(1)
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			instance.Status.ExistingComponentTemplate = pointer.Bool(true)
+			nodes = []responses.CatNodesResponse{
+				{Name: "node-0", Version: "2.9.0"},
+				{Name: "node-1", Version: "2.8.0"},
+				{Name: "node-2", Version: "2.8.0"},
+			}
+			transport.RegisterResponder(
+				http.MethodGet,
+				fmt.Sprintf("%s_cat/nodes?format=json&h=name,version", clusterUrl),
+				func(req *http.Request) (*http.Response, error) {
+					return httpmock.NewJsonResponse(200, nodes)
+				},
+			)
 			transport.RegisterResponder(
 				http.MethodGet,
 				clusterUrl,
@@ -187,136 +399,5432 @@ var _ = Describe("componenttemplate reconciler", func() {
 			)
 		})
 
-		When("existing status is true", func() {
+		JustBeforeEach(func() {
+			reconciler.upgradeHandling = UpgradeHandlingVersionSkew
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+		})
+
+		reconcileEvents := func() []string {
+			mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+				RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+					f(obj)
+					return nil
+				})
+			reconciler.updateStatus = pointer.Bool(true)
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			return events
+		}
+
+		When("the nodes run different versions", func() {
+			It("should defer changes", func() {
+				reason := opensearchClusterUpgrading + ", its nodes run versions 2.8.0, 2.9.0"
+				Expect(reconcileEvents()).To(Equal([]string{fmt.Sprintf("Normal %s %s", opensearchDeferred, reason)}))
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateDeferred))
+				Expect(instance.Status.Reason).To(Equal(reason))
+				Expect(transport.GetCallCountInfo()).To(HaveKeyWithValue(fmt.Sprintf("GET %s_cat/nodes?format=json&h=name,version", clusterUrl), 1))
+			})
+
+			It("should not delete the component template", func() {
+				instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+				mockClient.EXPECT().ListOpensearchIndexTemplates(mock.Anything).Return(opsterv1.OpensearchIndexTemplateList{}, nil)
+				Expect(reconciler.Delete()).To(MatchError(ContainSubstring(opensearchClusterUpgrading)))
+				Expect(transport.GetCallCountInfo()).ToNot(HaveKey(fmt.Sprintf("DELETE %s_component_template/my-template", clusterUrl)))
+			})
+
+			It("should not check the versions of the nodes if upgrades are ignored", func() {
+				reconciler.upgradeHandling = UpgradeHandlingIgnore
+				Expect(reconcileEvents()).To(BeEmpty())
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateIgnored))
+				Expect(transport.GetCallCountInfo()).To(HaveKeyWithValue(fmt.Sprintf("GET %s_cat/nodes?format=json&h=name,version", clusterUrl), 0))
+			})
+		})
+
+		When("the nodes run the same version again", func() {
 			BeforeEach(func() {
-				instance.Status.ExistingComponentTemplate = pointer.Bool(true)
+				nodes[0].Version = "2.8.0"
 			})
 
-			It("should do nothing", func() {
+			It("should reconcile the component template again", func() {
+				Expect(reconcileEvents()).To(BeEmpty())
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateIgnored))
+			})
+		})
+
+		When("the cluster status reports an upgrade", func() {
+			BeforeEach(func() {
+				cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{{
+					Component:   "Upgrader",
+					Status:      "Upgrading",
+					Description: "node",
+				}}
+			})
+
+			It("should defer changes without calling OpenSearch", func() {
+				Expect(reconcileEvents()).To(Equal([]string{fmt.Sprintf("Normal %s %s", opensearchDeferred, opensearchClusterUpgrading)}))
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateDeferred))
+				Expect(transport.GetTotalCallCount()).To(BeZero())
+			})
+
+			It("should not delete the component template", func() {
+				instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+				Expect(reconciler.Delete()).To(MatchError(errClusterUpgrading))
+				Expect(transport.GetTotalCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Context("cluster is a serverless endpoint", func() {
+		var componentTemplateUrl string
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(2)
+			cluster.Annotations = map[string]string{helpers.EndpointFlavorAnnotation: helpers.EndpointFlavorServerless}
+			instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+			instance.Spec.RequiredClusterHealth = opsterv1.OpenSearchGreenHealth
+			componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+		})
+
+		reconcileEvents := func() []string {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
 				_, err := reconciler.Reconcile()
 				Expect(err).ToNot(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			return events
+		}
+
+		When("the endpoint responds to a ping", func() {
+			BeforeEach(func() {
+				instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"analysis":{"analyzer":{"japanese":{"type":"custom","tokenizer":"kuromoji_tokenizer"}}}}`)}
+				transport.RegisterResponder(
+					http.MethodHead,
+					clusterUrl,
+					httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodGet,
+					clusterUrl,
+					httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodGet,
+					componentTemplateUrl,
+					httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					componentTemplateUrl,
+					httpmock.NewStringResponder(200, "OK").Once(failMessage),
+				)
+			})
+
+			It("should apply the component template without the phase, health, version and plugin checks", func() {
+				reconciler.upgradeHandling = UpgradeHandlingVersionSkew
+				Expect(reconcileEvents()).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+				// Unregistered calls like _cluster/health, _cat/nodes and _nodes/plugins fail the test
+				Expect(transport.GetTotalCallCount()).To(Equal(6))
 			})
 		})
 
-		When("existing status is nil", func() {
-			localExtraCalls := 4
+		When("the endpoint does not respond to a ping", func() {
 			BeforeEach(func() {
-				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodHead,
+					clusterUrl,
+					httpmock.NewStringResponder(500, "internal error").Times(2, failMessage),
+				)
 				transport.RegisterResponder(
 					http.MethodGet,
 					clusterUrl,
-					httpmock.NewStringResponder(200, "OK").Times(4, failMessage),
+					httpmock.NewStringResponder(200, "OK").Once(failMessage),
 				)
+			})
+
+			It("should wait for the endpoint", func() {
+				Expect(reconcileEvents()).To(Equal([]string{fmt.Sprintf("Normal %s waiting for the serverless endpoint to respond", opensearchPending)}))
+				Expect(transport.GetCallCountInfo()["GET "+componentTemplateUrl]).To(BeZero())
+			})
+		})
+
+		When("the component template is allocated to a tier", func() {
+			BeforeEach(func() {
+				instance.Spec.Tier = opsterv1.IndexTierHot
 				transport.RegisterResponder(
 					http.MethodHead,
 					clusterUrl,
 					httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
 				)
 				transport.RegisterResponder(
-					http.MethodHead,
-					fmt.Sprintf("%s_component_template/my-template", clusterUrl),
-					httpmock.NewJsonResponderOrPanic(200, "OK").Then(
-						httpmock.NewStringResponder(404, "does not exist"),
-					).Then(
-						httpmock.NewNotFoundResponder(failMessage),
-					),
+					http.MethodGet,
+					clusterUrl,
+					httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
 				)
 			})
 
-			It("should do nothing and emit a unit test event", func() {
+			It("should not apply it", func() {
 				go func() {
 					defer GinkgoRecover()
 					defer close(recorder.Events)
 					_, err := reconciler.Reconcile()
-					Expect(err).ToNot(HaveOccurred())
-					_, err = reconciler.Reconcile()
-					Expect(err).ToNot(HaveOccurred())
-					// Confirm all responders have been called
-					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls + localExtraCalls))
+					Expect(err).To(HaveOccurred())
 				}()
 				var events []string
 				for msg := range recorder.Events {
 					events = append(events, msg)
 				}
-				Expect(len(events)).To(Equal(2))
-				Expect(events[0]).To(Equal("Normal UnitTest exists is true"))
-				Expect(events[1]).To(Equal("Normal UnitTest exists is false"))
+				Expect(events).To(Equal([]string{
+					fmt.Sprintf("Warning %s tiers cannot be used with serverless endpoints, which do not allocate shards to nodes", opensearchMissingTier),
+				}))
+				Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
 			})
 		})
+	})
 
-		When("existing status is true", func() {
-			BeforeEach(func() {
-				instance.Status.ExistingComponentTemplate = pointer.Bool(true)
-			})
+	Context("component template is confirmed on all node pools", func() {
+		var (
+			componentTemplateUrl string
+			nodePoolUrls         map[string]string
+			result               ctrl.Result
+		)
 
-			It("should do nothing", func() {
-				_, err := reconciler.Reconcile()
-				Expect(err).ToNot(HaveOccurred())
-			})
-		})
+		templateResponse := func(version int) responses.GetComponentTemplatesResponse {
+			return responses.GetComponentTemplatesResponse{
+				ComponentTemplates: []responses.ComponentTemplate{{
+					Name: "my-template",
+					ComponentTemplate: requests.ComponentTemplate{
+						Template: requests.Index{
+							Settings: &apiextensionsv1.JSON{},
+							Mappings: &apiextensionsv1.JSON{},
+							Aliases:  make(map[string]requests.IndexAlias),
+						},
+						Version: version,
+						Meta:    &apiextensionsv1.JSON{},
+					},
+				}},
+			}
+		}
 
-		When("existing status is false", func() {
-			BeforeEach(func() {
-				instance.Status.ExistingComponentTemplate = pointer.Bool(false)
-			})
+		registerNodePool := func(component string, responder httpmock.Responder) {
+			url := nodePoolUrls[component]
+			transport.RegisterResponder(http.MethodHead, url, httpmock.NewStringResponder(200, "OK").Once(failMessage))
+			transport.RegisterResponder(http.MethodGet, url, httpmock.NewStringResponder(200, "OK").Times(2, failMessage))
+			transport.RegisterResponder(http.MethodGet, url+"_component_template/my-template", responder)
+		}
 
-			When("componenttemplate exists in opensearch and is the same", func() {
-				BeforeEach(func() {
-					response := responses.GetComponentTemplatesResponse{
-						ComponentTemplates: make([]responses.ComponentTemplate, 1),
-					}
-					response.ComponentTemplates[0] = responses.ComponentTemplate{
-						Name: "my-template",
-						ComponentTemplate: requests.ComponentTemplate{
-							Template: requests.Index{
-								Settings: &apiextensionsv1.JSON{},
-								Mappings: &apiextensionsv1.JSON{},
-								Aliases:  make(map[string]requests.IndexAlias),
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(2)
+			instance.Spec.ConfirmOnAllNodePools = true
+			instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Spec.NodePools = []opsterv1.NodePool{
+				{Component: "masters", Roles: []string{"cluster_manager"}},
+				{Component: "coordinators", Roles: []string{}},
+			}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+			nodePoolUrls = map[string]string{}
+			for _, component := range []string{"masters", "coordinators"} {
+				nodePoolUrls[component] = fmt.Sprintf("https://test-cluster-%s.%s.svc.cluster.local:9200/", component, cluster.Namespace)
+			}
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			registerNodePool("masters", httpmock.NewJsonResponderOrPanic(200, templateResponse(0)).Once(failMessage))
+		})
+
+		JustBeforeEach(func() {
+			reconciler.nodePoolConfirmRetries = 1
+			reconciler.nodePoolConfirmInterval = time.Millisecond
+		})
+
+		reconcile := func() []string {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				var err error
+				result, err = reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			return events
+		}
+
+		Context("the component template is written", func() {
+			BeforeEach(func() {
+				transport.RegisterResponder(
+					http.MethodGet,
+					componentTemplateUrl,
+					httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					componentTemplateUrl,
+					httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+				)
+			})
+
+			When("all node pools return the component template", func() {
+				BeforeEach(func() {
+					registerNodePool("coordinators", httpmock.NewJsonResponderOrPanic(200, templateResponse(0)).Once(failMessage))
+				})
+
+				It("should report the component template as updated", func() {
+					Expect(reconcile()).To(Equal([]string{
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+					Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+				})
+			})
+
+			When("a node pool returns the component template after a retry", func() {
+				BeforeEach(func() {
+					registerNodePool("coordinators", httpmock.NewStringResponder(404, "does not exist").Once(failMessage).Then(
+						httpmock.NewJsonResponderOrPanic(200, templateResponse(0)).Once(failMessage),
+					))
+				})
+
+				It("should report the component template as updated", func() {
+					Expect(reconcile()).To(Equal([]string{
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+					Expect(transport.GetCallCountInfo()["GET "+nodePoolUrls["coordinators"]+"_component_template/my-template"]).To(Equal(2))
+				})
+			})
+
+			When("a node pool keeps returning an outdated component template", func() {
+				BeforeEach(func() {
+					registerNodePool("coordinators", httpmock.NewJsonResponderOrPanic(200, templateResponse(1)).Times(2, failMessage))
+				})
+
+				It("should not report the component template as updated and requeue", func() {
+					Expect(reconcile()).To(Equal([]string{
+						fmt.Sprintf("Warning %s component template is not returned through the node pools coordinators yet, the write is confirmed again later", opensearchUnconfirmedWrite),
+					}))
+					Expect(result).To(Equal(ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}))
+				})
+			})
+
+			When("a node pool cannot be reached", func() {
+				BeforeEach(func() {
+					url := nodePoolUrls["coordinators"]
+					for _, method := range []string{http.MethodHead, http.MethodGet} {
+						transport.RegisterResponder(method, url, httpmock.NewErrorResponder(errors.New("connection refused")))
+					}
+				})
+
+				It("should not report the component template as updated and requeue", func() {
+					Expect(reconcile()).To(Equal([]string{
+						fmt.Sprintf("Warning %s component template is not returned through the node pools coordinators yet, the write is confirmed again later", opensearchUnconfirmedWrite),
+					}))
+					Expect(result).To(Equal(ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}))
+				})
+			})
+		})
+
+		When("a written component template was not confirmed on a node pool yet", func() {
+			BeforeEach(func() {
+				transport.RegisterResponder(
+					http.MethodGet,
+					componentTemplateUrl,
+					httpmock.NewJsonResponderOrPanic(200, templateResponse(0)).Once(failMessage),
+				)
+				registerNodePool("coordinators", httpmock.NewStringResponder(404, "does not exist").Times(2, failMessage))
+			})
+
+			It("should confirm the write again without writing the component template", func() {
+				Expect(reconcile()).To(Equal([]string{
+					fmt.Sprintf("Warning %s component template is not returned through the node pools coordinators yet, the write is confirmed again later", opensearchUnconfirmedWrite),
+				}))
+				Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
+			})
+		})
+	})
+
+	Context("cluster is selected by region", func() {
+		var clusters []opsterv1.OpenSearchCluster
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(1)
+			instance.Labels = map[string]string{defaultRegionLabel: "eu-west-1"}
+			instance.Spec.RegionAffinity = &opsterv1.RegionAffinity{}
+			instance.Spec.OpensearchRef.Name = ""
+			instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+			cluster.Labels = map[string]string{defaultRegionLabel: "eu-west-1"}
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			clusters = []opsterv1.OpenSearchCluster{*cluster}
+			mockClient.EXPECT().ListOpenSearchClusters(mock.Anything, mock.Anything).RunAndReturn(func(...client.ListOption) (opsterv1.OpenSearchClusterList, error) {
+				return opsterv1.OpenSearchClusterList{Items: clusters}, nil
+			})
+		})
+
+		reconcile := func(succeed bool) []string {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				if succeed {
+					Expect(err).ToNot(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			return events
+		}
+
+		When("the running cluster of the region is selected", func() {
+			BeforeEach(func() {
+				transport.RegisterResponder(http.MethodGet, clusterUrl, httpmock.NewStringResponder(200, "OK"))
+				transport.RegisterResponder(http.MethodHead, clusterUrl, httpmock.NewStringResponder(200, "OK"))
+				transport.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+					httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodPut,
+					fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+					httpmock.NewStringResponder(200, "OK").Once(failMessage),
+				)
+			})
+
+			It("should record the region and apply the component template to the cluster", func() {
+				Expect(reconcile(true)).To(Equal([]string{
+					fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+				}))
+				Expect(instance.Status.Region).To(Equal("eu-west-1"))
+			})
+		})
+
+		When("the named cluster is in another region", func() {
+			BeforeEach(func() {
+				instance.Spec.OpensearchRef.Name = "other-cluster"
+			})
+
+			It("should fail without touching the component template", func() {
+				Expect(reconcile(false)).To(Equal([]string{
+					fmt.Sprintf("Warning %s OpenSearchCluster other-cluster is not in region eu-west-1 of the component template", opensearchRegionMismatch),
+				}))
+				Expect(transport.GetTotalCallCount()).To(BeZero())
+			})
+		})
+
+		When("no cluster of the region is running", func() {
+			BeforeEach(func() {
+				clusters[0].Status.Phase = opsterv1.PhasePending
+			})
+
+			It("should fail naming the region", func() {
+				Expect(reconcile(false)).To(Equal([]string{
+					fmt.Sprintf("Warning %s no running OpenSearchCluster in region eu-west-1 of the component template", opensearchRegionMismatch),
+				}))
+			})
+		})
+
+		When("several clusters of the region are running", func() {
+			BeforeEach(func() {
+				other := cluster.DeepCopy()
+				other.Name = "test-cluster-2"
+				clusters = append(clusters, *other)
+			})
+
+			It("should fail asking for the cluster to be named", func() {
+				Expect(reconcile(false)).To(Equal([]string{
+					fmt.Sprintf("Warning %s OpenSearchClusters test-cluster, test-cluster-2 are all running in region eu-west-1, set opensearchCluster.name to select one", opensearchRegionMismatch),
+				}))
+			})
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingComponentTemplate = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		When("existing status is nil", func() {
+			localExtraCalls := 4
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					clusterUrl,
+					httpmock.NewStringResponder(200, "OK").Times(4, failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodHead,
+					clusterUrl,
+					httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodHead,
+					fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+					httpmock.NewJsonResponderOrPanic(200, "OK").Then(
+						httpmock.NewStringResponder(404, "does not exist"),
+					).Then(
+						httpmock.NewNotFoundResponder(failMessage),
+					),
+				)
+			})
+
+			It("should do nothing and emit a unit test event", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					_, err = reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					// Confirm all responders have been called
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls + localExtraCalls))
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(len(events)).To(Equal(2))
+				Expect(events[0]).To(Equal("Normal UnitTest exists is true"))
+				Expect(events[1]).To(Equal("Normal UnitTest exists is false"))
+			})
+		})
+
+		Context("the operator user may not read component templates", func() {
+			var catTemplates []responses.CatTemplatesResponse
+
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(2)
+				transport.RegisterResponder(
+					http.MethodHead,
+					fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+					httpmock.NewStringResponder(403, "").Once(failMessage),
+				)
+			})
+
+			reconcile := func(expectErr bool) []string {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					if expectErr {
+						Expect(errors.Is(err, services.ErrForbidden)).To(BeTrue())
+					} else {
+						Expect(err).ToNot(HaveOccurred())
+					}
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				return events
+			}
+
+			When("an index template is composed of the component template", func() {
+				BeforeEach(func() {
+					catTemplates = []responses.CatTemplatesResponse{
+						{Name: "logs", ComposedOf: "[base, my-template]"},
+					}
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/_cat/templates\?`),
+						httpmock.NewJsonResponderOrPanic(200, catTemplates).Once(failMessage),
+					)
+				})
+
+				It("should find the component template through the cat API", func() {
+					Expect(reconcile(false)).To(Equal([]string{
+						fmt.Sprintf("Warning %s component templates cannot be read from OpenSearch, the component template exists as an index template listed by _cat/templates is composed of it", opensearchCatFallback),
+						"Normal UnitTest exists is true",
+					}))
+				})
+			})
+
+			When("no index template is composed of the component template", func() {
+				BeforeEach(func() {
+					catTemplates = []responses.CatTemplatesResponse{
+						{Name: "logs", ComposedOf: "[base, my-template-2]"},
+						{Name: "legacy", ComposedOf: "[]"},
+					}
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/_cat/templates\?`),
+						httpmock.NewJsonResponderOrPanic(200, catTemplates).Once(failMessage),
+					)
+				})
+
+				It("should treat the component template as new", func() {
+					Expect(reconcile(false)).To(Equal([]string{
+						fmt.Sprintf("Warning %s component templates cannot be read from OpenSearch and no index template listed by _cat/templates is composed of the component template, it is treated as new", opensearchCatFallback),
+						"Normal UnitTest exists is false",
+					}))
+				})
+			})
+
+			When("the cat API is forbidden as well", func() {
+				BeforeEach(func() {
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/_cat/templates\?`),
+						httpmock.NewStringResponder(403, "").Once(failMessage),
+					)
+				})
+
+				It("should return a forbidden error and name the missing privilege", func() {
+					Expect(reconcile(true)).To(Equal([]string{
+						fmt.Sprintf("Warning %s operator user is not authorized to manage component templates, check that it has the %s privilege", opensearchForbidden, componentTemplateGetPrivilege),
+					}))
+				})
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingComponentTemplate = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+			})
+
+			When("componenttemplate exists in opensearch and is the same", func() {
+				BeforeEach(func() {
+					response := responses.GetComponentTemplatesResponse{
+						ComponentTemplates: make([]responses.ComponentTemplate, 1),
+					}
+					response.ComponentTemplates[0] = responses.ComponentTemplate{
+						Name: "my-template",
+						ComponentTemplate: requests.ComponentTemplate{
+							Template: requests.Index{
+								Settings: &apiextensionsv1.JSON{},
+								Mappings: &apiextensionsv1.JSON{},
+								Aliases:  make(map[string]requests.IndexAlias),
+							},
+							Version: 0,
+							Meta:    &apiextensionsv1.JSON{},
+						},
+					}
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+						httpmock.NewJsonResponderOrPanic(200, response).Once(failMessage),
+					)
+				})
+
+				It("should do nothing", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				})
+			})
+
+			Context("componenttemplate exists in opensearch and is fetched twice", func() {
+				var (
+					etag          string
+					ifNoneMatches []string
+				)
+
+				BeforeEach(func() {
+					etag = ""
+					ifNoneMatches = nil
+					response := responses.GetComponentTemplatesResponse{
+						ComponentTemplates: []responses.ComponentTemplate{{
+							Name: "my-template",
+							ComponentTemplate: requests.ComponentTemplate{
+								Template: requests.Index{
+									Settings: &apiextensionsv1.JSON{},
+									Mappings: &apiextensionsv1.JSON{},
+									Aliases:  make(map[string]requests.IndexAlias),
+								},
+								Version: 0,
+								Meta:    &apiextensionsv1.JSON{},
+							},
+						}},
+					}
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(4, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+						func(req *http.Request) (*http.Response, error) {
+							ifNoneMatch := req.Header.Get("If-None-Match")
+							ifNoneMatches = append(ifNoneMatches, ifNoneMatch)
+							if etag != "" && ifNoneMatch == etag {
+								return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+							}
+							resp, err := httpmock.NewJsonResponse(200, response)
+							if err != nil {
+								return nil, err
+							}
+							if etag != "" {
+								resp.Header.Set("ETag", etag)
+							}
+							return resp, nil
+						},
+					)
+				})
+
+				JustBeforeEach(func() {
+					reconciler.etagCache = services.NewETagCache()
+				})
+
+				reconcileTwice := func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					_, err = reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				When("opensearch returns an ETag", func() {
+					BeforeEach(func() {
+						etag = `"v1"`
+					})
+
+					It("should request it conditionally and accept the unchanged template", func() {
+						reconcileTwice()
+						Expect(ifNoneMatches).To(Equal([]string{"", `"v1"`}))
+					})
+				})
+
+				When("opensearch returns no ETag", func() {
+					It("should fall back to unconditional requests", func() {
+						reconcileTwice()
+						Expect(ifNoneMatches).To(Equal([]string{"", ""}))
+					})
+				})
+			})
+
+			When("componenttemplate exists in opensearch and is not the same", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+
+					response := responses.GetComponentTemplatesResponse{
+						ComponentTemplates: make([]responses.ComponentTemplate, 1),
+					}
+					response.ComponentTemplates[0] = responses.ComponentTemplate{
+						Name: "my-template",
+						ComponentTemplate: requests.ComponentTemplate{
+							Template: requests.Index{
+								Settings: &apiextensionsv1.JSON{},
+								Mappings: &apiextensionsv1.JSON{},
+								Aliases:  make(map[string]requests.IndexAlias),
+							},
+							Version: 100,
+							Meta:    &apiextensionsv1.JSON{},
+						},
+					}
+
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewJsonResponderOrPanic(200, response).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				It("should update the componenttemplate", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						// Confirm all responders have been called
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(len(events)).To(Equal(1))
+					Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+				})
+
+				When("the component template only asks for warning events", func() {
+					BeforeEach(func() {
+						instance.Annotations = map[string]string{helpers.EventVerbosityAnnotation: string(EventVerbosityWarning)}
+					})
+
+					JustBeforeEach(func() {
+						reconciler.recorder = newVerbosityRecorder(recorder, EventVerbosityNormal)
+					})
+
+					It("should update the componenttemplate without emitting normal events", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(BeEmpty())
+					})
+				})
+			})
+
+			Context("the number of shards of the component template changes", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					response := responses.GetComponentTemplatesResponse{
+						ComponentTemplates: []responses.ComponentTemplate{{
+							Name: "my-template",
+							ComponentTemplate: requests.ComponentTemplate{
+								Template: requests.Index{
+									Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"1","number_of_replicas":"1"}}`)},
+									Mappings: &apiextensionsv1.JSON{},
+									Aliases:  make(map[string]requests.IndexAlias),
+								},
+								Meta: &apiextensionsv1.JSON{},
+							},
+						}},
+					}
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewJsonResponderOrPanic(200, response).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				reconcile := func() []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("the spec sets another number of shards", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":3,"number_of_replicas":1}}`)}
+					})
+
+					It("should explain that only new indices get the number of shards", func() {
+						Expect(reconcile()).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+							fmt.Sprintf("Normal %s index.number_of_shards changes from 1 to 3, only indices created from the template afterwards get the new number of shards. "+
+								"Existing indices keep theirs, reindex them or roll over their aliases or data streams to use it", opensearchShardChangeInfo),
+						}))
+					})
+				})
+
+				When("the spec no longer sets the number of shards", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_replicas":1}}`)}
+					})
+
+					It("should explain that only new indices get the default number of shards", func() {
+						Expect(reconcile()).To(ContainElement(ContainSubstring("index.number_of_shards changes from 1 to the default")))
+					})
+				})
+
+				When("only other settings change", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":1,"number_of_replicas":2}}`)}
+					})
+
+					It("should not report a shard change", func() {
+						Expect(reconcile()).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+			})
+
+			Context("the component template is applied from a git revision", func() {
+				var (
+					componentTemplateUrl string
+					written              requests.ComponentTemplate
+				)
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Annotations = map[string]string{helpers.GitRevisionAnnotation: "4f2c9e1"}
+					instance.Spec.Meta = &apiextensionsv1.JSON{Raw: []byte(`{"owner":"search-team"}`)}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					written = requests.ComponentTemplate{}
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(json.Unmarshal(body, &written)).To(Succeed())
+							return httpmock.NewStringResponse(200, "OK"), nil
+						},
+					)
+				})
+
+				registerLive := func(live requests.ComponentTemplate) {
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+							ComponentTemplates: []responses.ComponentTemplate{{Name: "my-template", ComponentTemplate: live}},
+						}).Once(failMessage),
+					)
+				}
+
+				reconcile := func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					for range recorder.Events {
+					}
+				}
+
+				When("the spec changes", func() {
+					JustBeforeEach(func() {
+						registerLive(requests.ComponentTemplate{
+							Template: requests.Index{Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_replicas":"5"}}`)}},
+						})
+					})
+
+					It("should stamp the revision into the _meta and the status", func() {
+						reconcile()
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+						Expect(string(written.Meta.Raw)).To(MatchJSON(`{"owner":"search-team","opensearch_operator":{"managed_by":"opensearch-operator","git_revision":"4f2c9e1"}}`))
+						Expect(instance.Status.LastAppliedGitRevision).To(Equal("4f2c9e1"))
+					})
+				})
+
+				When("only the revision changes", func() {
+					JustBeforeEach(func() {
+						live := helpers.TranslateComponentTemplateToRequest(instance.Spec)
+						live.Meta = helpers.WithProvenance(live.Meta, "0b7d3aa")
+						registerLive(live)
+					})
+
+					It("should not apply the component template and keep the revision it was applied from", func() {
+						reconcile()
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
+						Expect(instance.Status.LastAppliedGitRevision).To(Equal("0b7d3aa"))
+					})
+				})
+			})
+
+			Context("componenttemplate was edited in opensearch since it was last applied", func() {
+				var (
+					appliedHash string
+					editedHash  string
+					putCalls    int
+				)
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(10)
+					putCalls = 0
+
+					var err error
+					appliedHash, err = componentTemplateHash(helpers.TranslateComponentTemplateToRequest(instance.Spec))
+					Expect(err).ToNot(HaveOccurred())
+					instance.Generation = 2
+					instance.Status.LastAppliedHash = appliedHash
+					instance.Status.LastAppliedGeneration = 2
+
+					edited := requests.ComponentTemplate{
+						Template: requests.Index{
+							Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_replicas":"2"}}`)},
+						},
+						Version: 7,
+					}
+					editedHash, err = componentTemplateHash(edited)
+					Expect(err).ToNot(HaveOccurred())
+
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+							ComponentTemplates: []responses.ComponentTemplate{{Name: "my-template", ComponentTemplate: edited}},
+						}).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							putCalls++
+							return httpmock.NewStringResponse(200, "OK"), nil
+						},
+					)
+				})
+
+				reconcile := func() []string {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					close(recorder.Events)
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("the edit is reverted", func() {
+					It("should apply the spec again", func() {
+						events := reconcile()
+						Expect(putCalls).To(Equal(1))
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template was edited in OpenSearch, reverting the edit", opensearchExternalEdit),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+						Expect(instance.Status.LastAppliedHash).To(Equal(appliedHash))
+					})
+				})
+
+				When("the edit is adopted", func() {
+					BeforeEach(func() {
+						instance.Spec.ExternalEditPolicy = opsterv1.ExternalEditPolicyAdopt
+					})
+
+					It("should keep the edit and record it as last applied", func() {
+						events := reconcile()
+						Expect(putCalls).To(Equal(0))
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Normal %s adopted the edit made to the component template in OpenSearch", opensearchExternalEdit),
+						}))
+						Expect(instance.Status.LastAppliedHash).To(Equal(editedHash))
+						Expect(instance.Status.LastAppliedGeneration).To(BeEquivalentTo(2))
+					})
+
+					It("should apply the spec once it changed", func() {
+						instance.Generation = 3
+						reconcile()
+						Expect(putCalls).To(Equal(1))
+						Expect(instance.Status.LastAppliedHash).To(Equal(appliedHash))
+						Expect(instance.Status.LastAppliedGeneration).To(BeEquivalentTo(3))
+					})
+				})
+
+				When("the edit is only warned about", func() {
+					BeforeEach(func() {
+						instance.Spec.ExternalEditPolicy = opsterv1.ExternalEditPolicyWarn
+						instance.Spec.ExternalEditGracePeriod = &metav1.Duration{Duration: 5 * time.Minute}
+					})
+
+					It("should warn and keep the edit when it is first detected", func() {
+						events := reconcile()
+						Expect(putCalls).To(Equal(0))
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Warning %s component template was edited in OpenSearch, the edit is reverted in 5m0s", opensearchExternalEdit),
+						}))
+						Expect(instance.Status.ExternalEditDetectedAt).ToNot(BeNil())
+					})
+
+					It("should keep the edit during the grace period", func() {
+						detectedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+						instance.Status.ExternalEditDetectedAt = &detectedAt
+						events := reconcile()
+						Expect(putCalls).To(Equal(0))
+						Expect(events).To(BeEmpty())
+					})
+
+					It("should revert the edit after the grace period", func() {
+						detectedAt := metav1.NewTime(time.Now().Add(-6 * time.Minute))
+						instance.Status.ExternalEditDetectedAt = &detectedAt
+						events := reconcile()
+						Expect(putCalls).To(Equal(1))
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Warning %s grace period of the edit made in OpenSearch is over, reverting it", opensearchExternalEdit),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+						Expect(instance.Status.ExternalEditDetectedAt).To(BeNil())
+					})
+				})
+			})
+
+			When("indextemplate exists in opensearch but the name has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+
+					instance.Status.ComponentTemplateName = "my-template" // old template name
+					instance.Spec.Name = "new-template"                   // new template name
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(len(events)).To(Equal(1))
+					Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the component template name", opensearchComponentTemplateNameMismatch)))
+				})
+			})
+
+			When("componenttemplate doesn't exist in opensearch", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				It("should create the componenttemplate", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						// Confirm all responders have been called
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(len(events)).To(Equal(1))
+					Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+				})
+			})
+			Context("the operator user may not read component templates", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(403, `{"error":{"type":"security_exception"}}`).Once(failMessage),
+					)
+				})
+
+				reconcile := func() []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("the spec was not applied yet", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+						)
+					})
+
+					It("should apply the spec without comparing it", func() {
+						Expect(reconcile()).To(Equal([]string{
+							fmt.Sprintf("Warning %s component templates cannot be read from OpenSearch, applying the spec without comparing it with the component template in OpenSearch", opensearchCatFallback),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+
+				When("the spec was already applied", func() {
+					BeforeEach(func() {
+						var err error
+						instance.Status.LastAppliedHash, err = componentTemplateHash(helpers.TranslateComponentTemplateToRequest(instance.Spec))
+						Expect(err).ToNot(HaveOccurred())
+						instance.Status.LastAppliedGeneration = instance.Generation
+					})
+
+					It("should not write the component template again", func() {
+						Expect(reconcile()).To(BeEmpty())
+					})
+				})
+			})
+
+			Context("the cluster the component template was applied to was replaced", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Status.LastAppliedHash = "applied-to-previous-cluster"
+					instance.Status.LastAppliedGeneration = instance.Generation
+					instance.Status.PreviousApplied = &apiextensionsv1.JSON{Raw: []byte(`{"version":1}`)}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+				})
+
+				reconcile := func() []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				expectReadopted := func() {
+					Expect(*instance.Status.ManagedCluster).To(Equal(cluster.UID))
+					Expect(instance.Status.ManagedClusterName).To(Equal(cluster.Name))
+					Expect(instance.Status.PreviousApplied).To(BeNil())
+					hash, err := componentTemplateHash(helpers.TranslateComponentTemplateToRequest(instance.Spec))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(instance.Status.LastAppliedHash).To(Equal(hash))
+					Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+				}
+
+				When("the OpenSearchCluster was recreated under the same name", func() {
+					BeforeEach(func() {
+						uid := types.UID("previous-cluster-uid")
+						instance.Status.ManagedCluster = &uid
+						instance.Status.ManagedClusterName = cluster.Name
+					})
+
+					It("should apply the component template to the new cluster", func() {
+						Expect(reconcile()).To(Equal([]string{
+							fmt.Sprintf("Warning %s OpenSearchCluster test-cluster was recreated, the component template is applied to the new cluster", opensearchClusterReplaced),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+						expectReadopted()
+					})
+				})
+
+				When("the OpenSearch cluster UUID changed", func() {
+					BeforeEach(func() {
+						instance.Status.ClusterUUID = "previous-uuid"
+						transport.RegisterResponder(
+							http.MethodGet,
+							clusterUrl,
+							httpmock.NewStringResponder(200, `{"cluster_name":"test-cluster","cluster_uuid":"restored-uuid","version":{"number":"2.8.0"}}`).Times(2, failMessage),
+						)
+					})
+
+					It("should apply the component template to the new cluster", func() {
+						Expect(reconcile()).To(Equal([]string{
+							fmt.Sprintf("Warning %s OpenSearch cluster UUID changed from previous-uuid to restored-uuid, the cluster was replaced and the component template is applied to the new cluster", opensearchClusterReplaced),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+						expectReadopted()
+						Expect(instance.Status.ClusterUUID).To(Equal("restored-uuid"))
+					})
+
+					When("no cluster UUID was recorded yet", func() {
+						BeforeEach(func() {
+							instance.Status.ClusterUUID = ""
+							instance.Status.PreviousApplied = nil
+							instance.Status.LastAppliedHash = ""
+						})
+
+						It("should record the cluster UUID without reporting a replaced cluster", func() {
+							Expect(reconcile()).To(Equal([]string{
+								fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+							}))
+							Expect(instance.Status.ClusterUUID).To(Equal("restored-uuid"))
+						})
+					})
+				})
+			})
+
+			Context("requests are logged for debugging", func() {
+				var (
+					sink       *bytes.Buffer
+					all        bool
+					ttl        time.Duration
+					maxEntries int
+				)
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					sink = &bytes.Buffer{}
+					all = false
+					ttl = time.Minute
+					maxEntries = 100
+					instance.Annotations = map[string]string{helpers.LogRequestsAnnotation: "true"}
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"analysis":{"filter":{"synonyms":{"type":"synonym","synonyms_path":"analysis/synonyms.txt"}}}}}`)}
+					instance.Spec.Meta = &apiextensionsv1.JSON{Raw: []byte(`{"owner":"search","token":"s3cr3t"}`)}
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+				})
+
+				JustBeforeEach(func() {
+					reconciler.requestLog = services.NewRequestLog(sink, all, ttl, maxEntries,
+						helpers.NewRedaction([]string{"template.settings.index.analysis.filter.*.synonyms_path"}))
+				})
+
+				loggedEntries := func() []map[string]interface{} {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					for range recorder.Events {
+					}
+					var entries []map[string]interface{}
+					decoder := json.NewDecoder(sink)
+					for decoder.More() {
+						entry := map[string]interface{}{}
+						Expect(decoder.Decode(&entry)).To(Succeed())
+						entries = append(entries, entry)
+					}
+					return entries
+				}
+
+				It("should log the requests of the component template with redacted bodies", func() {
+					entries := loggedEntries()
+					Expect(entries).To(HaveLen(transport.GetTotalCallCount()))
+					put := entries[len(entries)-1]
+					Expect(put).To(HaveKeyWithValue("method", http.MethodPut))
+					Expect(put).To(HaveKeyWithValue("object", "OpensearchComponentTemplate test-componenttemplate/test-componenttemplate"))
+					Expect(put).To(HaveKeyWithValue("status", BeNumerically("==", 200)))
+					Expect(put).To(HaveKeyWithValue("response", `{"acknowledged":true}`))
+					Expect(put["request"]).To(ContainSubstring(`"owner":"search"`))
+					Expect(put["request"]).To(ContainSubstring(`"token":"REDACTED"`))
+					Expect(put["request"]).To(ContainSubstring(`"synonyms_path":"REDACTED"`))
+					Expect(sink.String()).ToNot(ContainSubstring("s3cr3t"))
+					Expect(sink.String()).ToNot(ContainSubstring("analysis/synonyms.txt"))
+				})
+
+				When("the component template does not ask for it", func() {
+					BeforeEach(func() {
+						instance.Annotations = nil
+					})
+
+					It("should not log the requests", func() {
+						Expect(loggedEntries()).To(BeEmpty())
+					})
+
+					When("all requests are logged", func() {
+						BeforeEach(func() {
+							all = true
+						})
+
+						It("should log the requests", func() {
+							Expect(loggedEntries()).To(HaveLen(transport.GetTotalCallCount()))
+						})
+					})
+				})
+
+				When("the TTL has passed", func() {
+					BeforeEach(func() {
+						ttl = time.Nanosecond
+					})
+
+					It("should not log the requests anymore", func() {
+						Expect(loggedEntries()).To(BeEmpty())
+					})
+				})
+
+				When("the requests exceed the maximum number of entries", func() {
+					BeforeEach(func() {
+						maxEntries = 2
+					})
+
+					It("should stop logging after announcing the limit", func() {
+						entries := loggedEntries()
+						Expect(entries).To(HaveLen(3))
+						Expect(entries[2]).To(HaveKeyWithValue("message", "request log limit of 2 entries reached, further requests are not logged"))
+					})
+				})
+			})
+
+			Context("component template requires approval", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(10)
+					instance.Generation = 3
+					instance.Spec.RequireApproval = true
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+							ComponentTemplates: []responses.ComponentTemplate{{
+								Name: "my-template",
+								ComponentTemplate: requests.ComponentTemplate{
+									Template: requests.Index{},
+									Version:  1,
+								},
+							}},
+						}).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				reconcile := func() []string {
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+					reconciler.updateStatus = pointer.Bool(true)
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				It("should list the change in the status without applying it", func() {
+					Expect(reconcile()).To(Equal([]string{
+						fmt.Sprintf("Normal %s change of generation 3 waits for approval, the diff is listed in status.awaitingApproval", opensearchApproval),
+					}))
+					Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateAwaitingApproval))
+					Expect(instance.Status.Reason).To(Equal(opensearchAwaitingApproval))
+					Expect(instance.Status.AwaitingApproval).ToNot(BeNil())
+					Expect(instance.Status.AwaitingApproval.Generation).To(BeEquivalentTo(3))
+					Expect(instance.Status.AwaitingApproval.Diff).To(Equal([]opsterv1.ComponentTemplateChange{{
+						Op:   helpers.JSONChangeRemove,
+						Path: "version",
+						From: &apiextensionsv1.JSON{Raw: []byte("1")},
+					}}))
+				})
+
+				When("an older generation is approved", func() {
+					BeforeEach(func() {
+						instance.Annotations = map[string]string{helpers.ApprovedGenerationAnnotation: "2"}
+					})
+
+					It("should reject the stale approval and keep waiting", func() {
+						Expect(reconcile()).To(Equal([]string{
+							fmt.Sprintf("Warning %s approval of generation 2 is stale, the spec is at generation 3 and has to be approved again", opensearchApproval),
+							fmt.Sprintf("Normal %s change of generation 3 waits for approval, the diff is listed in status.awaitingApproval", opensearchApproval),
+						}))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateAwaitingApproval))
+					})
+				})
+
+				When("the current generation is approved", func() {
+					BeforeEach(func() {
+						instance.Annotations = map[string]string{helpers.ApprovedGenerationAnnotation: "3"}
+						instance.Status.AwaitingApproval = &opsterv1.ComponentTemplateApproval{Generation: 3}
+					})
+
+					It("should apply the change", func() {
+						Expect(reconcile()).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+						Expect(instance.Status.AwaitingApproval).To(BeNil())
+						Expect(instance.Status.LastAppliedGeneration).To(BeEquivalentTo(3))
+					})
+				})
+			})
+
+			Context("component template is written conditionally", func() {
+				var (
+					componentTemplateUrl string
+					liveVersions         []int
+				)
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(10)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					// Every GET returns the next version of the template in OpenSearch, -1 if it does not exist
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							Expect(liveVersions).ToNot(BeEmpty(), "unexpected GET of the component template")
+							version := liveVersions[0]
+							liveVersions = liveVersions[1:]
+							if version < 0 {
+								return httpmock.NewStringResponse(404, "does not exist"), nil
+							}
+							return httpmock.NewJsonResponse(200, responses.GetComponentTemplatesResponse{
+								ComponentTemplates: []responses.ComponentTemplate{{
+									Name: "my-template",
+									ComponentTemplate: requests.ComponentTemplate{
+										Template: requests.Index{},
+										Version:  version,
+									},
+								}},
+							})
+						},
+					)
+				})
+
+				JustBeforeEach(func() {
+					reconciler.conditionalWrites = true
+					reconciler.conditionalWriteRetries = 3
+				})
+
+				reconcile := func(succeeds bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeeds {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("another writer creates the same component template concurrently", func() {
+					BeforeEach(func() {
+						liveVersions = []int{-1, 0}
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl+"?create=true",
+							httpmock.NewStringResponder(400, `{"error":{"type":"illegal_argument_exception","reason":"component template [my-template] already exists"}}`).Once(failMessage),
+						)
+					})
+
+					It("should detect the conflict and keep the component template", func() {
+						events := reconcile(true)
+						Expect(liveVersions).To(BeEmpty())
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl+"?create=true"]).To(Equal(1))
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Warning %s component template was modified concurrently by another writer", opensearchConcurrentModification),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+
+				When("another writer updates the component template concurrently", func() {
+					BeforeEach(func() {
+						liveVersions = []int{100, 200, 200, 200}
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should read the component template again and retry the write", func() {
+						events := reconcile(true)
+						Expect(liveVersions).To(BeEmpty())
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Warning %s component template was modified concurrently by another writer", opensearchConcurrentModification),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+
+				When("the retries are exhausted", func() {
+					BeforeEach(func() {
+						liveVersions = []int{100, 200, 200, 300}
+					})
+
+					JustBeforeEach(func() {
+						reconciler.conditionalWriteRetries = 1
+					})
+
+					It("should fail without writing the component template", func() {
+						events := reconcile(false)
+						Expect(liveVersions).To(BeEmpty())
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Warning %s component template was modified concurrently by another writer", opensearchConcurrentModification),
+							fmt.Sprintf("Warning %s component template was modified concurrently by another writer", opensearchConcurrentModification),
+						}))
+					})
+				})
+			})
+
+			Context("opensearch reports deprecated settings", func() {
+				var componentTemplateUrl string
+				const deprecation = "[index.merge.policy.max_merge_at_once_explicit] setting was deprecated in OpenSearch and will be removed in a future release!"
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(10)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							resp := httpmock.NewStringResponse(200, `{"acknowledged":true}`)
+							resp.Header.Add("Warning", `299 OpenSearch-2.11.0-abcdef "`+deprecation+`" "Mon, 01 Jan 2024 00:00:00 GMT"`)
+							resp.Header.Add("Warning", `299 OpenSearch-2.11.0-abcdef "[index.soft_deletes.enabled] is \"deprecated\""`)
+							return resp, nil
+						},
+					)
+				})
+
+				reconcile := func(succeeds bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeeds {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				It("should apply the component template and report the deprecations", func() {
+					events := reconcile(true)
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Warning %s OpenSearch reported a deprecation for component template my-template: %s", opensearchDeprecatedSetting, deprecation),
+						fmt.Sprintf(`Warning %s OpenSearch reported a deprecation for component template my-template: [index.soft_deletes.enabled] is "deprecated"`, opensearchDeprecatedSetting),
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+					Expect(transport.GetCallCountInfo()["DELETE "+componentTemplateUrl]).To(Equal(0))
+				})
+
+				When("deprecated settings fail the reconcile", func() {
+					BeforeEach(func() {
+						instance.Spec.FailOnDeprecatedSettings = true
+						transport.RegisterResponder(
+							http.MethodDelete,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+						)
+					})
+
+					It("should report the deprecations and remove the created component template", func() {
+						events := reconcile(false)
+						Expect(events).To(HaveLen(2))
+						Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s OpenSearch reported a deprecation for component template my-template: %s", opensearchDeprecatedSetting, deprecation)))
+						Expect(transport.GetCallCountInfo()["DELETE "+componentTemplateUrl]).To(Equal(1))
+					})
+				})
+			})
+
+			Context("component template is part of a transaction group", func() {
+				var otherMember opsterv1.OpensearchComponentTemplate
+				var componentTemplateUrl, otherComponentTemplateUrl, simulateUrl string
+
+				BeforeEach(func() {
+					instance.Spec.TransactionGroup = "logs"
+					otherMember = opsterv1.OpensearchComponentTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-componenttemplate-2",
+							Namespace: "test-componenttemplate",
+							UID:       "otheruid",
+						},
+						Spec: opsterv1.OpensearchComponentTemplateSpec{
+							OpensearchRef:    instance.Spec.OpensearchRef,
+							Name:             "my-template-2",
+							Template:         instance.Spec.Template,
+							TransactionGroup: "logs",
+						},
+						Status: opsterv1.OpensearchComponentTemplateStatus{
+							ExistingComponentTemplate: pointer.Bool(false),
+						},
+					}
+					// The members are listed when reconciling, so that the cases can change them
+					mockClient.EXPECT().ListOpensearchComponentTemplates(mock.Anything).
+						RunAndReturn(func(...client.ListOption) (opsterv1.OpensearchComponentTemplateList, error) {
+							return opsterv1.OpensearchComponentTemplateList{
+								Items: []opsterv1.OpensearchComponentTemplate{otherMember, *instance},
+							}, nil
+						})
+
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					otherComponentTemplateUrl = fmt.Sprintf("%s_component_template/my-template-2", clusterUrl)
+					simulateUrl = fmt.Sprintf("%s_index_template/_simulate", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						otherComponentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+				})
+
+				When("all members apply successfully", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(200, "{}").Times(2, failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							otherComponentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should apply every member of the group", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							result, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("POST %s", simulateUrl)]).To(Equal(2))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(
+							fmt.Sprintf("Normal %s transaction group logs applied 2 component templates", opensearchTransactionGroupApplied),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						))
+						Expect(instance.Status.LastAppliedHash).ToNot(BeEmpty())
+						Expect(instance.Status.LastAppliedGeneration).To(Equal(instance.Generation))
+					})
+				})
+
+				When("the writes of the members are traced", func() {
+					var opaqueIDs map[string]string
+					var lastRequestIDs map[string]string
+					var lastAppliedHashes map[string]string
+
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						instance.Spec.RequestID = "CHG-1234"
+						otherMember.Spec.RequestID = "CHG-5678"
+						opaqueIDs = map[string]string{}
+						lastRequestIDs = map[string]string{}
+						lastAppliedHashes = map[string]string{}
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(200, "{}").Times(2, failMessage),
+						)
+						for _, url := range []string{componentTemplateUrl, otherComponentTemplateUrl} {
+							url := url
+							transport.RegisterResponder(
+								http.MethodPut,
+								url,
+								func(req *http.Request) (*http.Response, error) {
+									opaqueIDs[url] = req.Header.Get("X-Opaque-Id")
+									return httpmock.NewStringResponse(200, "OK"), nil
+								},
+							)
+						}
+						mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+							RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+								f(obj)
+								member := obj.(*opsterv1.OpensearchComponentTemplate)
+								lastRequestIDs[member.Name] = member.Status.LastRequestID
+								lastAppliedHashes[member.Name] = member.Status.LastAppliedHash
+								return nil
+							})
+					})
+
+					It("should record the request id of every member in its status", func() {
+						reconciler.updateStatus = pointer.Bool(true)
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+						}()
+						for msg := range recorder.Events {
+							Expect(msg).To(BeElementOf(
+								fmt.Sprintf("Normal %s transaction group logs applied 2 component templates", opensearchTransactionGroupApplied),
+								fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+							))
+						}
+						Expect(opaqueIDs[componentTemplateUrl]).To(HaveSuffix("/CHG-1234"))
+						Expect(opaqueIDs[otherComponentTemplateUrl]).To(HaveSuffix("/CHG-5678"))
+						Expect(instance.Status.LastRequestID).To(Equal(opaqueIDs[componentTemplateUrl]))
+						Expect(lastRequestIDs["test-componenttemplate-2"]).To(Equal(opaqueIDs[otherComponentTemplateUrl]))
+						Expect(lastAppliedHashes["test-componenttemplate-2"]).ToNot(BeEmpty())
+					})
+				})
+
+				When("the second member fails to apply", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(2)
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(200, "{}").Times(2, failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							otherComponentTemplateUrl,
+							httpmock.NewStringResponder(500, "internal error").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodDelete,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should roll back the already applied member", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("DELETE %s", componentTemplateUrl)]).To(Equal(1))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Warning %s transaction group logs not applied, applying component template my-template-2 failed", opensearchTransactionGroupFailed),
+							fmt.Sprintf("Normal %s rolled back 1 component templates of transaction group logs", opensearchTransactionGroupRolledBack),
+						}))
+					})
+				})
+
+				When("a member fails validation", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(200, "{}").Then(
+								httpmock.NewStringResponder(400, "illegal_argument_exception"),
+							),
+						)
+					})
+
+					It("should not apply any member", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", componentTemplateUrl)]).To(Equal(0))
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", otherComponentTemplateUrl)]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s transaction group logs not applied, validation of component template my-template-2 failed", opensearchTransactionGroupFailed)))
+					})
+				})
+
+				When("a member requires a recent snapshot", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						otherMember.Spec.RequireRecentSnapshot = &opsterv1.RecentSnapshotRequirement{
+							Repository: "nightly",
+							MaxAge:     metav1.Duration{Duration: time.Hour},
+						}
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(200, "{}").Times(2, failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodGet,
+							fmt.Sprintf("%s_snapshot/nightly/_all", clusterUrl),
+							httpmock.NewStringResponder(200, `{"snapshots":[]}`).Once(failMessage),
+						)
+					})
+
+					It("should defer the whole group until the snapshot exists", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							result, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(result.RequeueAfter).To(Equal(time.Minute))
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", componentTemplateUrl)]).To(BeZero())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", otherComponentTemplateUrl)]).To(BeZero())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Normal %s deferring changes until a successful snapshot exists, none newer than 1h0m0s was found", opensearchDeferred)))
+					})
+				})
+
+				When("a member is validated with canary documents", func() {
+					var canaryUrl string
+
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						canaryUrl = fmt.Sprintf("%sopensearch-operator-canary-my-template-2", clusterUrl)
+						otherMember.Spec.Template.Mappings = &apiextensionsv1.JSON{Raw: []byte(`{"dynamic":"strict","properties":{"user":{"type":"keyword"}}}`)}
+						otherMember.Spec.CanaryValidation = &opsterv1.CanaryValidation{
+							Documents: []apiextensionsv1.JSON{{Raw: []byte(`{"name":"bob"}`)}},
+						}
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(200, "{}").Times(2, failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							canaryUrl,
+							httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPost,
+							canaryUrl+"/_doc",
+							httpmock.NewStringResponder(400, `{"error":{"type":"strict_dynamic_mapping_exception","reason":"mapping set to strict, dynamic introduction of [name] within [_doc] is not allowed"},"status":400}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodDelete,
+							canaryUrl,
+							httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+						)
+					})
+
+					It("should not apply any member if a canary document is rejected", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["DELETE "+canaryUrl]).To(Equal(1))
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", componentTemplateUrl)]).To(BeZero())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", otherComponentTemplateUrl)]).To(BeZero())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s canary validation of the component template failed, the change is not applied: document 0: document rejected: strict_dynamic_mapping_exception: mapping set to strict, dynamic introduction of [name] within [_doc] is not allowed", opensearchCanaryValidation)))
+					})
+				})
+
+				When("all members are in sync", func() {
+					BeforeEach(func() {
+						for _, name := range []string{"my-template", "my-template-2"} {
+							response := responses.GetComponentTemplatesResponse{
+								ComponentTemplates: []responses.ComponentTemplate{{
+									Name: name,
+									ComponentTemplate: requests.ComponentTemplate{
+										Template: requests.Index{
+											Settings: &apiextensionsv1.JSON{},
+											Mappings: &apiextensionsv1.JSON{},
+											Aliases:  make(map[string]requests.IndexAlias),
+										},
+										Meta: &apiextensionsv1.JSON{},
+									},
+								}},
+							}
+							transport.RegisterResponder(
+								http.MethodGet,
+								fmt.Sprintf("%s_component_template/%s", clusterUrl, name),
+								httpmock.NewJsonResponderOrPanic(200, response).Once(failMessage),
+							)
+						}
+					})
+
+					It("should record the component template of the instance as applied without writing", func() {
+						result, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+						Expect(transport.GetCallCountInfo()[fmt.Sprintf("POST %s", simulateUrl)]).To(BeZero())
+						Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", componentTemplateUrl)]).To(BeZero())
+						Expect(instance.Status.LastAppliedHash).ToNot(BeEmpty())
+					})
+				})
+
+				When("another member requires approval", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						otherMember.Spec.RequireApproval = true
+					})
+
+					It("should not apply any member", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", componentTemplateUrl)]).To(BeZero())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s component template test-componenttemplate-2 of transaction group logs cannot be applied: approvals cannot be required for members of a transaction group", opensearchTransactionGroupFailed)))
+					})
+				})
+
+				When("another member is rolled back", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(2)
+						otherMember.Annotations = map[string]string{helpers.RollbackAnnotation: "true"}
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(200, "{}").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should apply the other members only", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("GET %s", otherComponentTemplateUrl)]).To(BeZero())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(
+							fmt.Sprintf("Normal %s transaction group logs applied 1 component templates", opensearchTransactionGroupApplied),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						))
+					})
+				})
+
+				When("a member rejects unknown settings", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						otherMember.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"numbr_of_shards":2}}`)}
+						otherMember.Spec.UnknownSettings = opsterv1.UnknownSettingsReject
+					})
+
+					It("should not apply any member", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("POST %s", simulateUrl)]).To(BeZero())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", componentTemplateUrl)]).To(BeZero())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", otherComponentTemplateUrl)]).To(BeZero())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s unknown index settings: index.numbr_of_shards (did you mean index.number_of_shards?)", opensearchUnknownSetting)))
+					})
+				})
+
+				When("a member preloads files that are not preloaded", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(2)
+						otherMember.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"store":{"type":"niofs","preload":["nvd"]}}}`)}
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(200, "{}").Times(2, failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							otherComponentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should warn about the member and apply the group", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Warning %s index.store.preload has no effect with index.store.type niofs, only mmapfs and hybridfs preload files", opensearchStorePreload),
+							fmt.Sprintf("Normal %s transaction group logs applied 2 component templates", opensearchTransactionGroupApplied),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+
+				When("a member violates a template policy", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_replicas":1}}`)}
+						otherMember.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":1}}`)}
+						mockClient.EXPECT().GetConfigMap("template-policies", "opensearch-operator-system").Return(corev1.ConfigMap{
+							Data: map[string]string{
+								"replicas.rego": requireReplicasPolicy,
+							},
+						}, nil)
+					})
+
+					JustBeforeEach(func() {
+						reconciler.templatePolicies = types.NamespacedName{Namespace: "opensearch-operator-system", Name: "template-policies"}
+					})
+
+					It("should not apply any member", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("POST %s", simulateUrl)]).To(BeZero())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", componentTemplateUrl)]).To(BeZero())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", otherComponentTemplateUrl)]).To(BeZero())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(HavePrefix(fmt.Sprintf(
+							"Warning %s component template violates template policies: policy templates.replicas: all templates must set index.number_of_replicas",
+							opensearchPolicyViolation,
+						))))
+					})
+				})
+
+				When("a member sets index blocks that are not acknowledged", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						otherMember.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"blocks":{"write":true}}}`)}
+						otherMember.Spec.IndexBlocks = opsterv1.IndexBlocksRequireAcknowledgment
+					})
+
+					It("should not apply any member", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("POST %s", simulateUrl)]).To(BeZero())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", componentTemplateUrl)]).To(BeZero())
+							Expect(transport.GetCallCountInfo()[fmt.Sprintf("PUT %s", otherComponentTemplateUrl)]).To(BeZero())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s component template blocks every index created from it: index.blocks.write (documents cannot be written to indices), set the opensearch.opster.io/acknowledge-index-blocks annotation to true to apply it", opensearchIndexBlocks)))
+					})
+				})
+			})
+
+			When("the operator user is not allowed to write component templates", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(403, `{"error":{"type":"security_exception"}}`).Once(failMessage),
+					)
+				})
+
+				It("should return a forbidden error and name the missing privilege", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(errors.Is(err, services.ErrForbidden)).To(BeTrue())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(len(events)).To(Equal(1))
+					Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s operator user is not authorized to manage component templates, check that it has the %s privilege", opensearchForbidden, componentTemplatePutPrivilege)))
+				})
+			})
+
+			Context("component template uses a codec the cluster version does not support", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"codec":"zstd"}}`)}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+				})
+
+				When("unsupported codecs are not replaced", func() {
+					It("should fail without touching the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(len(events)).To(Equal(1))
+						Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s index codec zstd requires OpenSearch 2.9.0 or later, but the cluster runs 2.8.0", opensearchUnsupportedCodec)))
+					})
+				})
+
+				When("unsupported codecs are replaced", func() {
+					var putBody string
+
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(2)
+						instance.Spec.ReplaceUnsupportedCodec = true
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							func(req *http.Request) (*http.Response, error) {
+								body, err := io.ReadAll(req.Body)
+								if err != nil {
+									return nil, err
+								}
+								putBody = string(body)
+								return httpmock.NewStringResponse(200, "OK"), nil
+							},
+						)
+					})
+
+					It("should apply the component template with the default codec", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(len(events)).To(Equal(2))
+						Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s index codec zstd requires OpenSearch 2.9.0 or later, but the cluster runs 2.8.0, using the default codec instead", opensearchUnsupportedCodec)))
+						Expect(events[1]).To(Equal(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+						Expect(putBody).To(ContainSubstring(`"settings":{"index":{"codec":"default"}}`))
+					})
+				})
+			})
+
+			Context("component template uses time valued settings", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+				})
+
+				When("a time value has an invalid unit", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"refresh_interval":"30 seconds"}}`)}
+					})
+
+					It("should fail without touching the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(len(events)).To(Equal(1))
+						Expect(events[0]).To(HavePrefix(fmt.Sprintf("Warning %s invalid time settings: index.refresh_interval: invalid time value \"30 seconds\"", opensearchInvalidTimeSetting)))
+					})
+				})
+
+				When("opensearch returns the time value in another unit", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"refresh_interval":"30s"}}`)}
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+								ComponentTemplates: []responses.ComponentTemplate{{
+									Name: "my-template",
+									ComponentTemplate: helpers.TranslateComponentTemplateToRequest(opsterv1.OpensearchComponentTemplateSpec{
+										Template: opsterv1.OpensearchIndexSpec{
+											Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"refresh_interval":"30000ms"}}`)},
+										},
+									}),
+								}},
+							}).Once(failMessage),
+						)
+					})
+
+					It("should consider the component template in sync", func() {
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					})
+				})
+			})
+
+			Context("component template sets routing shards", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+				})
+
+				When("the routing shards are not a multiple of the shards", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":3,"number_of_routing_shards":16}}`)}
+					})
+
+					It("should fail without touching the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s index.number_of_routing_shards 16 is not a multiple of "+
+							"index.number_of_shards 3, the closest valid values are 15 and 18", opensearchInvalidRoutingShards)}))
+					})
+				})
+
+				When("the routing shards are a multiple of the shards", func() {
+					BeforeEach(func() {
+						settings := &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":3,"number_of_routing_shards":24}}`)}
+						instance.Spec.Template.Settings = settings
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+								ComponentTemplates: []responses.ComponentTemplate{{
+									Name: "my-template",
+									ComponentTemplate: helpers.TranslateComponentTemplateToRequest(opsterv1.OpensearchComponentTemplateSpec{
+										Template: opsterv1.OpensearchIndexSpec{Settings: settings},
+									}),
+								}},
+							}).Once(failMessage),
+						)
+					})
+
+					It("should reconcile the component template", func() {
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					})
+				})
+			})
+
+			Context("component template sets unknown settings", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"numbr_of_shards":2}}`)}
+				})
+
+				reconcile := func(succeed bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeed {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("unknown settings are warned about", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should name the misspelled setting and apply the component template", func() {
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Warning %s unknown index settings: index.numbr_of_shards (did you mean index.number_of_shards?)", opensearchUnknownSetting),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+
+				When("unknown settings are rejected", func() {
+					BeforeEach(func() {
+						instance.Spec.UnknownSettings = opsterv1.UnknownSettingsReject
+					})
+
+					It("should fail without touching the component template", func() {
+						Expect(reconcile(false)).To(Equal([]string{
+							fmt.Sprintf("Warning %s unknown index settings: index.numbr_of_shards (did you mean index.number_of_shards?)", opensearchUnknownSetting),
+						}))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+					})
+				})
+
+				When("unknown settings are ignored", func() {
+					BeforeEach(func() {
+						instance.Spec.UnknownSettings = opsterv1.UnknownSettingsIgnore
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should apply the component template without checking the settings", func() {
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+			})
+
+			Context("component template preloads files the store type does not", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"store":{"type":"niofs","preload":["nvd"]}}}`)}
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				It("should warn about the combination and apply the component template", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Warning %s index.store.preload has no effect with index.store.type niofs, only mmapfs and hybridfs preload files", opensearchStorePreload),
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+				})
+			})
+
+			Context("component template allows auto-creating indices", func() {
+				var componentTemplateUrl string
+				var autoCreateIndex string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Spec.AllowAutoCreate = true
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_cluster/settings", clusterUrl),
+						func(req *http.Request) (*http.Response, error) {
+							Expect(req.URL.Query().Get("include_defaults")).To(Equal("true"))
+							return httpmock.NewStringResponse(200, fmt.Sprintf(`{"persistent":{},"transient":{},"defaults":{"action.auto_create_index":%q}}`, autoCreateIndex)), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(string(body)).To(ContainSubstring(`"allow_auto_create":true`))
+							return httpmock.NewStringResponse(200, "OK"), nil
+						},
+					)
+				})
+
+				reconcile := func() []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				It("should warn and apply the component template if the cluster does not auto-create indices", func() {
+					autoCreateIndex = "false"
+					Expect(reconcile()).To(Equal([]string{
+						fmt.Sprintf("Warning %s component template allows auto-creating indices, but action.auto_create_index of the cluster is false, so indices are not created on their first write", opensearchAutoCreate),
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+				})
+
+				It("should not warn if the cluster auto-creates all indices", func() {
+					autoCreateIndex = "true"
+					Expect(reconcile()).To(Equal([]string{
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+				})
+			})
+
+			Context("component template write is traced", func() {
+				var componentTemplateUrl string
+				var opaqueID string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					opaqueID = ""
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							opaqueID = req.Header.Get("X-Opaque-Id")
+							resp := httpmock.NewStringResponse(200, "OK")
+							resp.Header.Set("X-Request-Id", "server-request-1")
+							return resp, nil
+						},
+					)
+				})
+
+				reconcile := func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					for msg := range recorder.Events {
+						Expect(msg).To(Equal(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+					}
+				}
+
+				It("should record the request id of the spec and the id OpenSearch returned", func() {
+					instance.Spec.RequestID = "CHG-1234"
+					reconcile()
+					Expect(opaqueID).To(Equal("test-componenttemplate/test-componenttemplate/testuid/CHG-1234"))
+					Expect(instance.Status.LastRequestID).To(Equal(opaqueID))
+					Expect(instance.Status.LastServerRequestID).To(Equal("server-request-1"))
+				})
+
+				It("should record a generated correlation id", func() {
+					reconcile()
+					Expect(opaqueID).To(MatchRegexp("^test-componenttemplate/test-componenttemplate/testuid/[0-9a-f-]{36}$"))
+					Expect(instance.Status.LastRequestID).To(Equal(opaqueID))
+				})
+			})
+
+			Context("component template requires a recent snapshot", func() {
+				var componentTemplateUrl string
+				var snapshots string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Spec.RequireRecentSnapshot = &opsterv1.RecentSnapshotRequirement{MaxAge: metav1.Duration{Duration: time.Hour}}
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_snapshot", clusterUrl),
+						httpmock.NewStringResponder(200, `{"nightly":{"type":"fs","settings":{}},"empty":{"type":"fs","settings":{}}}`),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_snapshot/empty/_all", clusterUrl),
+						httpmock.NewStringResponder(200, `{"snapshots":[]}`),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_snapshot/nightly/_all", clusterUrl),
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewStringResponse(200, snapshots), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+				})
+
+				snapshotsEndedAgo := func(succeeded time.Duration, failed time.Duration) string {
+					return fmt.Sprintf(`{"snapshots":[{"snapshot":"succeeded","state":"SUCCESS","end_time_in_millis":%d},{"snapshot":"failed","state":"FAILED","end_time_in_millis":%d}]}`,
+						time.Now().Add(-succeeded).UnixMilli(), time.Now().Add(-failed).UnixMilli())
+				}
+
+				reconcile := func() (ctrl.Result, []string) {
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+					reconciler.updateStatus = pointer.Bool(true)
+					var result ctrl.Result
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						var err error
+						result, err = reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return result, events
+				}
+
+				It("should apply the change if a successful snapshot is recent", func() {
+					snapshots = snapshotsEndedAgo(10*time.Minute, time.Minute)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					_, events := reconcile()
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+				})
+
+				It("should defer the change if the latest successful snapshot is stale", func() {
+					snapshots = snapshotsEndedAgo(2*time.Hour, time.Minute)
+					result, events := reconcile()
+					Expect(events).To(HaveLen(1))
+					Expect(events[0]).To(MatchRegexp(fmt.Sprintf("^Normal %s deferring changes until a successful snapshot newer than 1h0m0s exists, the latest ended 2h0m[0-9]+s ago$", opensearchDeferred)))
+					Expect(result.RequeueAfter).To(Equal(time.Minute))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateDeferred))
+				})
+
+				It("should defer the change if the repository holds no successful snapshot", func() {
+					snapshots = `{"snapshots":[{"snapshot":"failed","state":"FAILED","end_time_in_millis":1}]}`
+					instance.Spec.RequireRecentSnapshot.Repository = "nightly"
+					_, events := reconcile()
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Normal %s deferring changes until a successful snapshot exists, none newer than 1h0m0s was found", opensearchDeferred),
+					}))
+					Expect(transport.GetCallCountInfo()[fmt.Sprintf("GET %s_snapshot", clusterUrl)]).To(BeZero())
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateDeferred))
+				})
+			})
+
+			Context("component template blocks the indices created from it", func() {
+				var componentTemplateUrl string
+				blocked := fmt.Sprintf("Warning %s component template blocks every index created from it: index.blocks.write (documents cannot be written to indices)", opensearchIndexBlocks)
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"blocks":{"write":true,"read":false}}}`)}
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				reconcile := func(succeed bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeed {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				It("should warn about the blocks and apply the component template", func() {
+					Expect(reconcile(true)).To(Equal([]string{
+						blocked,
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+				})
+
+				It("should not apply the component template until the blocks are acknowledged", func() {
+					instance.Spec.IndexBlocks = opsterv1.IndexBlocksRequireAcknowledgment
+					Expect(reconcile(false)).To(Equal([]string{
+						blocked + ", set the opensearch.opster.io/acknowledge-index-blocks annotation to true to apply it",
+					}))
+					Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
+				})
+
+				It("should apply the component template once the blocks are acknowledged", func() {
+					instance.Spec.IndexBlocks = opsterv1.IndexBlocksRequireAcknowledgment
+					instance.Annotations = map[string]string{helpers.AcknowledgeIndexBlocks: "true"}
+					Expect(reconcile(true)).To(Equal([]string{
+						blocked,
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+				})
+			})
+
+			Context("component template is validated with canary documents", func() {
+				var componentTemplateUrl string
+				var canaryUrl string
+				var mappings string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					canaryUrl = fmt.Sprintf("%sopensearch-operator-canary-my-template", clusterUrl)
+					instance.Spec.Template.Mappings = &apiextensionsv1.JSON{Raw: []byte(`{"dynamic":"strict","properties":{"user":{"type":"keyword"}}}`)}
+					instance.Spec.CanaryValidation = &opsterv1.CanaryValidation{
+						Documents: []apiextensionsv1.JSON{
+							{Raw: []byte(`{"user":"alice"}`)},
+							{Raw: []byte(`{"user":"bob"}`)},
+						},
+						ExpectedFieldTypes: map[string]string{"user": "keyword"},
+					}
+					mappings = `{"opensearch-operator-canary-my-template":{"mappings":{"dynamic":"strict","properties":{"user":{"type":"keyword"}}}}}`
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						canaryUrl,
+						func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(string(body)).To(MatchJSON(`{"settings":{"index.number_of_replicas":"0"},"mappings":{"dynamic":"strict","properties":{"user":{"type":"keyword"}}}}`))
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						canaryUrl+"/_doc",
+						func(req *http.Request) (*http.Response, error) {
+							Expect(req.URL.Query().Get("refresh")).To(Equal("true"))
+							body, err := io.ReadAll(req.Body)
+							Expect(err).ToNot(HaveOccurred())
+							if strings.Contains(string(body), "user") {
+								return httpmock.NewStringResponse(201, `{"result":"created"}`), nil
+							}
+							return httpmock.NewStringResponse(400, `{"error":{"type":"strict_dynamic_mapping_exception","reason":"mapping set to strict, dynamic introduction of [name] within [_doc] is not allowed"},"status":400}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						canaryUrl+"/_mapping",
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewStringResponse(200, mappings), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						canaryUrl,
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+				})
+
+				reconcile := func(succeed bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeed {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				It("should apply the component template once the canary documents pass", func() {
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					Expect(reconcile(true)).To(Equal([]string{
+						fmt.Sprintf("Normal %s component template validated with 2 documents in canary index opensearch-operator-canary-my-template", opensearchCanaryValidation),
+						fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+					}))
+					Expect(transport.GetCallCountInfo()["POST "+canaryUrl+"/_doc"]).To(Equal(2))
+					Expect(transport.GetCallCountInfo()["DELETE "+canaryUrl]).To(Equal(1))
+					Expect(instance.Status.CanaryValidatedHash).ToNot(BeEmpty())
+				})
+
+				It("should not apply the component template if a canary document is rejected", func() {
+					instance.Spec.CanaryValidation.Documents[1] = apiextensionsv1.JSON{Raw: []byte(`{"name":"bob"}`)}
+					Expect(reconcile(false)).To(Equal([]string{
+						fmt.Sprintf("Warning %s canary validation of the component template failed, the change is not applied: document 1: document rejected: strict_dynamic_mapping_exception: mapping set to strict, dynamic introduction of [name] within [_doc] is not allowed", opensearchCanaryValidation),
+					}))
+					Expect(transport.GetCallCountInfo()["DELETE "+canaryUrl]).To(Equal(1))
+					Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
+					Expect(instance.Status.CanaryValidatedHash).To(BeEmpty())
+				})
+
+				It("should not apply the component template if a field is mapped differently", func() {
+					mappings = `{"opensearch-operator-canary-my-template":{"mappings":{"properties":{"user":{"type":"text"}}}}}`
+					Expect(reconcile(false)).To(Equal([]string{
+						fmt.Sprintf("Warning %s canary validation of the component template failed, the change is not applied: field user is mapped as text instead of keyword", opensearchCanaryValidation),
+					}))
+					Expect(transport.GetCallCountInfo()["DELETE "+canaryUrl]).To(Equal(1))
+					Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
+				})
+			})
+
+			Context("component template has a TTL", func() {
+				var componentTemplateUrl string
+				var indexTemplates []opsterv1.OpensearchIndexTemplate
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+					indexTemplates = nil
+					mockClient.EXPECT().ListOpensearchIndexTemplates(mock.Anything).RunAndReturn(func(...client.ListOption) (opsterv1.OpensearchIndexTemplateList, error) {
+						return opsterv1.OpensearchIndexTemplateList{Items: indexTemplates}, nil
+					}).Maybe()
+				})
+
+				reconcile := func(succeed bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeed {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				expiresAt := func() string {
+					return instance.CreationTimestamp.Add(time.Hour).UTC().Format(time.RFC3339)
+				}
+
+				When("the TTL has not passed", func() {
+					BeforeEach(func() {
+						instance.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should record the expiry time and apply the component template", func() {
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+						Expect(instance.Status.ExpiresAt).ToNot(BeNil())
+						Expect(instance.Status.ExpiresAt.Time).To(BeTemporally("==", instance.CreationTimestamp.Add(time.Hour)))
+					})
+				})
+
+				When("the TTL has passed by less than the allowed clock skew", func() {
+					BeforeEach(func() {
+						instance.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour - 10*time.Second))
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should not expire the component template yet", func() {
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+
+				When("the TTL has passed", func() {
+					BeforeEach(func() {
+						instance.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second))
+					})
+
+					It("should delete the resource without applying the component template", func() {
+						mockClient.EXPECT().DeleteOpensearchComponentTemplate(instance).Return(nil).Once()
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template expired at %s, deleting it", opensearchExpired, expiresAt()),
+						}))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+					})
+
+					It("should not expire the component template while index templates compose it", func() {
+						indexTemplates = []opsterv1.OpensearchIndexTemplate{{
+							ObjectMeta: metav1.ObjectMeta{Name: "logs", Namespace: instance.Namespace},
+							Spec: opsterv1.OpensearchIndexTemplateSpec{
+								OpensearchRef: instance.Spec.OpensearchRef,
+								ComposedOf:    []string{"my-template"},
+							},
+						}}
+						events := reconcile(false)
+						Expect(events).To(HaveLen(1))
+						Expect(events[0]).To(HavePrefix(fmt.Sprintf("Warning %s ", opensearchDeleteBlocked)))
+					})
+
+					When("the expiry policy is Retain", func() {
+						BeforeEach(func() {
+							instance.Spec.ExpiryPolicy = opsterv1.ExpiryPolicyRetain
+							transport.RegisterResponder(
+								http.MethodHead,
+								componentTemplateUrl,
+								httpmock.NewStringResponder(200, "").Once(failMessage),
+							)
+							transport.RegisterResponder(
+								http.MethodDelete,
+								componentTemplateUrl,
+								httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+							)
+						})
+
+						It("should delete the component template from opensearch and keep the resource", func() {
+							Expect(reconcile(true)).To(Equal([]string{
+								fmt.Sprintf("Normal %s component template expired at %s and was deleted from OpenSearch", opensearchExpired, expiresAt()),
+							}))
+							Expect(transport.GetCallCountInfo()["DELETE "+componentTemplateUrl]).To(Equal(1))
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						})
+					})
+				})
+			})
+
+			Context("component template is checked against template policies", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					mockClient.EXPECT().GetConfigMap("template-policies", "opensearch-operator-system").Return(corev1.ConfigMap{
+						Data: map[string]string{
+							"replicas.rego": requireReplicasPolicy,
+						},
+					}, nil)
+				})
+
+				JustBeforeEach(func() {
+					reconciler.templatePolicies = types.NamespacedName{Namespace: "opensearch-operator-system", Name: "template-policies"}
+				})
+
+				When("a required setting is missing", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":1}}`)}
+					})
+
+					It("should fail with the policy message without touching the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(HavePrefix(fmt.Sprintf(
+							"Warning %s component template violates template policies: policy templates.replicas: all templates must set index.number_of_replicas",
+							opensearchPolicyViolation,
+						))))
+					})
+				})
+
+				When("the required settings are set", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_replicas":1}}`)}
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should apply the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+					})
+				})
+			})
+
+			Context("component template recovers from an error", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					detectedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+					instance.Status.State = opsterv1.OpensearchComponentTemplateError
+					instance.Status.Reason = "failed to update component template with OpenSearch API"
+					instance.Status.ExternalEditDetectedAt = &detectedAt
+					instance.Status.AwaitingApproval = &opsterv1.ComponentTemplateApproval{Generation: 1}
+					instance.Status.Clusters = []opsterv1.ComponentTemplateClusterStatus{{
+						Name:   "test-cluster",
+						State:  opsterv1.OpensearchComponentTemplateError,
+						Reason: "failed to update component template with OpenSearch API",
+					}}
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+							ComponentTemplates: []responses.ComponentTemplate{{
+								Name:              "my-template",
+								ComponentTemplate: helpers.TranslateComponentTemplateToRequest(instance.Spec),
+							}},
+						}).Once(failMessage),
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+				})
+
+				JustBeforeEach(func() {
+					reconciler.updateStatus = pointer.Bool(true)
+				})
+
+				It("should clear the stale error fields once the component template is in sync", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+					Expect(instance.Status.Reason).To(BeEmpty())
+					Expect(instance.Status.ExternalEditDetectedAt).To(BeNil())
+					Expect(instance.Status.AwaitingApproval).To(BeNil())
+					Expect(instance.Status.LastAppliedHash).ToNot(BeEmpty())
+					Expect(instance.Status.Clusters).To(HaveLen(1))
+					Expect(instance.Status.Clusters[0].State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+					Expect(instance.Status.Clusters[0].Reason).To(BeEmpty())
+				})
+			})
+
+			Context("the spec is changed during the reconcile", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Generation = 1
+					instance.Status.State = opsterv1.OpensearchComponentTemplatePending
+					// Every status write reads the instance again, which now holds the next generation of the spec
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							obj.SetGeneration(2)
+							f(obj)
+							return nil
+						})
+				})
+
+				JustBeforeEach(func() {
+					reconciler.updateStatus = pointer.Bool(true)
+				})
+
+				When("the reconcile succeeds", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+								ComponentTemplates: []responses.ComponentTemplate{{
+									Name:              "my-template",
+									ComponentTemplate: helpers.TranslateComponentTemplateToRequest(instance.Spec),
+								}},
+							}).Once(failMessage),
+						)
+					})
+
+					It("should requeue immediately without recording the state of the stale spec", func() {
+						result, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result).To(Equal(ctrl.Result{Requeue: true}))
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplatePending))
+						Expect(instance.Status.Clusters).To(BeEmpty())
+						// The applied spec is recorded with the generation it was read at
+						Expect(instance.Status.LastAppliedHash).ToNot(BeEmpty())
+						Expect(instance.Status.LastAppliedGeneration).To(Equal(int64(1)))
+					})
+
+					When("the state of stale specs is recorded", func() {
+						JustBeforeEach(func() {
+							reconciler.staleSpecPolicy = StaleSpecPolicyRecord
+						})
+
+						It("should record the state", func() {
+							result, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+							Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+							Expect(instance.Status.LastAppliedGeneration).To(Equal(int64(1)))
+						})
+					})
+				})
+
+				When("the reconcile fails", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(500, "internal error").Once(failMessage),
+						)
+					})
+
+					It("should still record the error", func() {
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateError))
+						Expect(instance.Status.Reason).To(Equal("failed to get component template status from OpenSearch API"))
+					})
+				})
+			})
+
+			Context("component template is verified after apply", func() {
+				var componentTemplateUrl string
+				var verifyIndexTemplateUrl string
+				var verifyIndexUrl string
+				var health string
+				var indexSettings map[string]interface{}
+				var indexTemplateBody string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Spec.VerifyAfterApply = true
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":2,"refresh_interval":"30s"}}`)}
+					instance.Spec.Template.Mappings = &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"message":{"type":"text"}}}`)}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					verifyIndexTemplateUrl = fmt.Sprintf("%s_index_template/opensearch-operator-verify-my-template", clusterUrl)
+					verifyIndexUrl = fmt.Sprintf("%sopensearch-operator-verify-my-template", clusterUrl)
+					health = "green"
+					indexSettings = map[string]interface{}{
+						"index.number_of_shards":   "2",
+						"index.number_of_replicas": "0",
+						"index.refresh_interval":   "30000ms",
+						"index.uuid":               "abc",
+					}
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_cluster/health", clusterUrl),
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewJsonResponse(200, map[string]string{"status": health})
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						verifyIndexTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						verifyIndexTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							indexTemplateBody = string(body)
+							return httpmock.NewStringResponse(200, "OK"), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						verifyIndexTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						verifyIndexUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/opensearch-operator-verify-my-template/_settings\?flat_settings=true$`),
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewJsonResponse(200, map[string]interface{}{
+								"opensearch-operator-verify-my-template": map[string]interface{}{"settings": indexSettings},
+							})
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						verifyIndexUrl+"/_mapping",
+						httpmock.NewStringResponder(200, `{"opensearch-operator-verify-my-template":{"mappings":{"properties":{"message":{"type":"text"},"other":{"type":"keyword"}}}}}`),
+					)
+				})
+
+				reconcile := func(succeed bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeed {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("the test index is created", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodPut,
+							verifyIndexUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should verify the component template and clean up", func() {
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+							fmt.Sprintf("Normal %s component template verified with test index opensearch-operator-verify-my-template", opensearchVerification),
+						}))
+						Expect(indexTemplateBody).To(ContainSubstring(`"index_patterns":["opensearch-operator-verify-my-template"]`))
+						Expect(indexTemplateBody).To(ContainSubstring(`"composed_of":["my-template"]`))
+						Expect(transport.GetCallCountInfo()["DELETE "+verifyIndexUrl]).To(Equal(1))
+						Expect(transport.GetCallCountInfo()["DELETE "+verifyIndexTemplateUrl]).To(Equal(1))
+						Expect(instance.Status.VerifiedHash).ToNot(BeEmpty())
+						Expect(instance.Status.VerifiedHash).To(Equal(instance.Status.LastAppliedHash))
+					})
+
+					When("the test index does not have the settings of the template", func() {
+						BeforeEach(func() {
+							indexSettings["index.number_of_shards"] = "1"
+						})
+
+						It("should fail with the differences and clean up", func() {
+							Expect(reconcile(false)).To(Equal([]string{
+								fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+								fmt.Sprintf("Warning %s component template is not effective in test index opensearch-operator-verify-my-template: setting index.number_of_shards is 1 instead of 2", opensearchVerification),
+							}))
+							Expect(transport.GetCallCountInfo()["DELETE "+verifyIndexUrl]).To(Equal(1))
+							Expect(transport.GetCallCountInfo()["DELETE "+verifyIndexTemplateUrl]).To(Equal(1))
+							Expect(instance.Status.VerifiedHash).To(BeEmpty())
+						})
+					})
+
+					When("the cluster is red", func() {
+						BeforeEach(func() {
+							health = "red"
+						})
+
+						It("should defer the verification", func() {
+							Expect(reconcile(true)).To(Equal([]string{
+								fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+								fmt.Sprintf("Normal %s deferring changes while the cluster health is red, yellow is required", opensearchDeferred),
+							}))
+							Expect(transport.GetCallCountInfo()["PUT "+verifyIndexTemplateUrl]).To(Equal(0))
+							Expect(transport.GetCallCountInfo()["PUT "+verifyIndexUrl]).To(Equal(0))
+						})
+					})
+				})
+
+				When("the test index already exists", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodPut,
+							verifyIndexUrl,
+							httpmock.NewStringResponder(400, `{"error":{"type":"resource_already_exists_exception"}}`).Once(failMessage),
+						)
+					})
+
+					It("should fail without deleting the existing index", func() {
+						events := reconcile(false)
+						Expect(events).To(HaveLen(2))
+						Expect(events[1]).To(HavePrefix(fmt.Sprintf("Warning %s failed to verify the component template: failed to create index opensearch-operator-verify-my-template", opensearchVerification)))
+						Expect(transport.GetCallCountInfo()["DELETE "+verifyIndexUrl]).To(Equal(0))
+						Expect(transport.GetCallCountInfo()["DELETE "+verifyIndexTemplateUrl]).To(Equal(1))
+					})
+				})
+			})
+
+			Context("component template records a simulated index", func() {
+				var simulateUrl string
+				var simulated map[string]interface{}
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.SimulateIndexName = "logs-2024.01.01"
+					simulateUrl = fmt.Sprintf("%s_index_template/_simulate_index/logs-2024.01.01", clusterUrl)
+					simulated = map[string]interface{}{
+						"template": map[string]interface{}{
+							"settings": map[string]interface{}{"index": map[string]interface{}{
+								"number_of_shards": "2",
+								"analysis": map[string]interface{}{"filter": map[string]interface{}{
+									"synonyms": map[string]interface{}{"type": "synonym", "synonyms": []string{"secret, hidden"}},
+								}},
+							}},
+							"mappings": map[string]interface{}{"properties": map[string]interface{}{"message": map[string]interface{}{"type": "text"}}},
+							"aliases":  map[string]interface{}{"logs": map[string]interface{}{}},
+						},
+						"overlapping": []map[string]interface{}{{"name": "legacy-logs", "index_patterns": []string{"logs-*"}}},
+					}
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+						httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+							ComponentTemplates: []responses.ComponentTemplate{{
+								Name:              "my-template",
+								ComponentTemplate: helpers.TranslateComponentTemplateToRequest(instance.Spec),
+							}},
+						}),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						simulateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewJsonResponse(200, simulated)
+						},
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+				})
+
+				JustBeforeEach(func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					reconciler.redaction = helpers.NewRedaction([]string{"template.settings.index.analysis.filter.*.synonyms"})
+				})
+
+				It("should record the resolved template of the index", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetCallCountInfo()["POST "+simulateUrl]).To(Equal(1))
+					index := instance.Status.SimulatedIndex
+					Expect(index).ToNot(BeNil())
+					Expect(index.Name).To(Equal("logs-2024.01.01"))
+					Expect(index.Hash).To(Equal(instance.Status.LastAppliedHash))
+					Expect(index.Truncated).To(BeFalse())
+					Expect(index.Overlapping).To(Equal([]string{"legacy-logs"}))
+					Expect(index.Template).ToNot(BeNil())
+					Expect(string(index.Template.Raw)).To(MatchJSON(`{
+						"settings": {"index": {"number_of_shards": "2", "analysis": {"filter": {"synonyms": {"type": "synonym", "synonyms": "REDACTED"}}}}},
+						"mappings": {"properties": {"message": {"type": "text"}}},
+						"aliases": {"logs": {}}
+					}`))
+				})
+
+				When("the index was simulated with the applied component template", func() {
+					BeforeEach(func() {
+						hash, err := componentTemplateHash(translateComponentTemplate(instance))
+						Expect(err).ToNot(HaveOccurred())
+						instance.Status.SimulatedIndex = &opsterv1.ComponentTemplateSimulatedIndex{Name: "logs-2024.01.01", Hash: hash}
+					})
+
+					It("should not simulate it again", func() {
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetCallCountInfo()["POST "+simulateUrl]).To(Equal(0))
+					})
+				})
+
+				When("the resolved template is too large", func() {
+					BeforeEach(func() {
+						properties := map[string]interface{}{}
+						for i := 0; i < 2000; i++ {
+							properties[fmt.Sprintf("field_%d", i)] = map[string]interface{}{"type": "keyword"}
+						}
+						simulated["template"].(map[string]interface{})["mappings"] = map[string]interface{}{"properties": properties}
+					})
+
+					It("should only record that it was truncated", func() {
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(instance.Status.SimulatedIndex).ToNot(BeNil())
+						Expect(instance.Status.SimulatedIndex.Truncated).To(BeTrue())
+						Expect(instance.Status.SimulatedIndex.Template).To(BeNil())
+					})
+				})
+
+				When("the simulation fails", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(http.MethodPost, simulateUrl, httpmock.NewStringResponder(400, `{"error":"invalid_index_name_exception"}`))
+					})
+
+					It("should report it without failing the reconcile", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(HaveLen(1))
+						Expect(events[0]).To(HavePrefix(fmt.Sprintf("Warning %s cannot record the simulated index logs-2024.01.01", opensearchSimulatedIndex)))
+						Expect(instance.Status.SimulatedIndex).To(BeNil())
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+					})
+				})
+
+				When("the index is no longer simulated", func() {
+					BeforeEach(func() {
+						instance.Spec.SimulateIndexName = ""
+						instance.Status.SimulatedIndex = &opsterv1.ComponentTemplateSimulatedIndex{Name: "logs-2024.01.01"}
+					})
+
+					It("should remove the simulated index from the status", func() {
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetCallCountInfo()["POST "+simulateUrl]).To(Equal(0))
+						Expect(instance.Status.SimulatedIndex).To(BeNil())
+					})
+				})
+			})
+
+			Context("component template migrates the codec of existing indices", func() {
+				var health string
+				var codecs map[string]map[string]interface{}
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"codec":"best_compression"}}`)}
+					instance.Spec.CodecMigration = &opsterv1.CodecMigration{IndexPatterns: []string{"logs-*"}}
+					health = "green"
+					codecs = map[string]map[string]interface{}{
+						"logs-1": {},
+						"logs-2": {"index.codec": "best_compression"},
+						"logs-3": {"index.codec": "zstd"},
+					}
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+						httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+							ComponentTemplates: []responses.ComponentTemplate{{
+								Name:              "my-template",
+								ComponentTemplate: helpers.TranslateComponentTemplateToRequest(instance.Spec),
+							}},
+						}).Once(failMessage),
+					)
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/([^/]+)/_settings/index\.codec\?flat_settings=true&ignore_unavailable=true$`),
+						func(req *http.Request) (*http.Response, error) {
+							pattern := strings.Split(strings.TrimPrefix(req.URL.Path, "/"), "/")[0]
+							body := map[string]interface{}{}
+							for index, settings := range codecs {
+								if pattern == "logs-*" || pattern == index {
+									body[index] = map[string]interface{}{"settings": settings}
+								}
+							}
+							return httpmock.NewJsonResponse(200, body)
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_cluster/health", clusterUrl),
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewJsonResponse(200, map[string]string{"status": health})
+						},
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+				})
+
+				JustBeforeEach(func() {
+					reconciler.updateStatus = pointer.Bool(true)
+				})
+
+				reconcile := func(succeed bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeed {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				It("should enumerate the indices using another codec without migrating them", func() {
+					Expect(reconcile(true)).To(Equal([]string{
+						fmt.Sprintf("Normal %s 2 indices use another codec than best_compression", opensearchCodecMigration),
+					}))
+					Expect(instance.Status.CodecMigration).ToNot(BeNil())
+					Expect(instance.Status.CodecMigration.Codec).To(Equal("best_compression"))
+					Expect(instance.Status.CodecMigration.Indices).To(Equal([]opsterv1.CodecMigrationIndex{
+						{Name: "logs-1", State: opsterv1.CodecMigrationIndexPending},
+						{Name: "logs-3", State: opsterv1.CodecMigrationIndexPending},
+					}))
+					Expect(instance.Status.CodecMigration.CompletedAt).To(BeNil())
+					Expect(transport.GetCallCountInfo()).ToNot(HaveKey(HavePrefix("POST ")))
+				})
+
+				When("more indices use another codec than the migration is limited to", func() {
+					BeforeEach(func() {
+						instance.Spec.CodecMigration.MaxIndices = pointer.Int32(1)
+					})
+
+					It("should refuse the migration", func() {
+						Expect(reconcile(false)).To(Equal([]string{
+							fmt.Sprintf("Warning %s 2 indices use another codec than best_compression, more than the 1 indices the codec migration is limited to", opensearchCodecMigration),
+						}))
+						Expect(instance.Status.CodecMigration).To(BeNil())
+					})
+				})
+
+				When("the codec changes again", func() {
+					BeforeEach(func() {
+						instance.Status.CodecMigration = &opsterv1.CodecMigrationStatus{
+							Codec:   "zstd",
+							Indices: []opsterv1.CodecMigrationIndex{{Name: "logs-1", State: opsterv1.CodecMigrationIndexPending}},
+						}
+					})
+
+					It("should enumerate the indices again", func() {
+						Expect(reconcile(true)).To(HaveLen(1))
+						Expect(instance.Status.CodecMigration.Codec).To(Equal("best_compression"))
+						Expect(instance.Status.CodecMigration.Indices).To(HaveLen(2))
+					})
+				})
+
+				Context("indices are force merged", func() {
+					var closeUrl, settingsUrl, openUrl, forceMergeUrl string
+
+					BeforeEach(func() {
+						instance.Spec.CodecMigration.Mode = opsterv1.CodecMigrationForceMerge
+						instance.Status.CodecMigration = &opsterv1.CodecMigrationStatus{
+							Codec: "best_compression",
+							Indices: []opsterv1.CodecMigrationIndex{
+								{Name: "logs-1", State: opsterv1.CodecMigrationIndexPending},
+								{Name: "logs-3", State: opsterv1.CodecMigrationIndexPending},
 							},
-							Version: 0,
-							Meta:    &apiextensionsv1.JSON{},
+						}
+						closeUrl = fmt.Sprintf("%slogs-1/_close", clusterUrl)
+						settingsUrl = fmt.Sprintf("%slogs-1/_settings", clusterUrl)
+						openUrl = fmt.Sprintf("%slogs-1/_open", clusterUrl)
+						forceMergeUrl = fmt.Sprintf("%slogs-1/_forcemerge?max_num_segments=1&wait_for_completion=false", clusterUrl)
+						transport.RegisterResponder(http.MethodPost, closeUrl, httpmock.NewStringResponder(200, `{"acknowledged":true}`))
+						transport.RegisterResponder(http.MethodPost, openUrl, httpmock.NewStringResponder(200, `{"acknowledged":true}`))
+						transport.RegisterResponder(http.MethodPost, forceMergeUrl, httpmock.NewStringResponder(200, `{"task":"node-1:42"}`))
+					})
+
+					When("the codec of the index is changed", func() {
+						var settingsBody string
+
+						BeforeEach(func() {
+							transport.RegisterResponder(
+								http.MethodPut,
+								settingsUrl,
+								func(req *http.Request) (*http.Response, error) {
+									body, err := io.ReadAll(req.Body)
+									if err != nil {
+										return nil, err
+									}
+									settingsBody = string(body)
+									return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+								},
+							)
+						})
+
+						It("should migrate one index and track its force merge", func() {
+							Expect(reconcile(true)).To(Equal([]string{
+								fmt.Sprintf("Normal %s migrating index logs-1 to the best_compression codec", opensearchCodecMigration),
+							}))
+							Expect(settingsBody).To(MatchJSON(`{"index.codec":"best_compression"}`))
+							Expect(transport.GetCallCountInfo()["POST "+closeUrl]).To(Equal(1))
+							Expect(transport.GetCallCountInfo()["POST "+openUrl]).To(Equal(1))
+							Expect(transport.GetCallCountInfo()["POST "+forceMergeUrl]).To(Equal(1))
+							Expect(instance.Status.CodecMigration.Indices).To(Equal([]opsterv1.CodecMigrationIndex{
+								{Name: "logs-1", State: opsterv1.CodecMigrationIndexMerging, Task: "node-1:42"},
+								{Name: "logs-3", State: opsterv1.CodecMigrationIndexPending},
+							}))
+						})
+
+						When("the cluster is not green", func() {
+							BeforeEach(func() {
+								health = "yellow"
+							})
+
+							It("should defer the migration", func() {
+								Expect(reconcile(true)).To(Equal([]string{
+									fmt.Sprintf("Normal %s deferring changes while the cluster health is yellow, green is required", opensearchDeferred),
+								}))
+								Expect(transport.GetCallCountInfo()["POST "+closeUrl]).To(Equal(0))
+								Expect(instance.Status.CodecMigration.Indices[0].State).To(Equal(opsterv1.CodecMigrationIndexPending))
+							})
+						})
+
+						When("the index was migrated since it was enumerated", func() {
+							BeforeEach(func() {
+								codecs["logs-1"] = map[string]interface{}{"index.codec": "best_compression"}
+							})
+
+							It("should record it as migrated without closing it", func() {
+								Expect(reconcile(true)).To(BeEmpty())
+								Expect(transport.GetCallCountInfo()["POST "+closeUrl]).To(Equal(0))
+								Expect(instance.Status.CodecMigration.Indices[0].State).To(Equal(opsterv1.CodecMigrationIndexMigrated))
+							})
+						})
+					})
+
+					When("the codec of the index cannot be changed", func() {
+						BeforeEach(func() {
+							transport.RegisterResponder(http.MethodPut, settingsUrl, httpmock.NewStringResponder(400, `{"error":"illegal_argument_exception"}`))
+						})
+
+						It("should open the index again and record the failure", func() {
+							events := reconcile(true)
+							Expect(events).To(HaveLen(1))
+							Expect(events[0]).To(HavePrefix(fmt.Sprintf("Warning %s failed to migrate index logs-1 to the best_compression codec", opensearchCodecMigration)))
+							Expect(transport.GetCallCountInfo()["POST "+openUrl]).To(Equal(1))
+							Expect(transport.GetCallCountInfo()["POST "+forceMergeUrl]).To(Equal(0))
+							Expect(instance.Status.CodecMigration.Indices[0].State).To(Equal(opsterv1.CodecMigrationIndexFailed))
+							Expect(instance.Status.CodecMigration.Indices[0].Reason).To(ContainSubstring("failed to set the codec of index logs-1"))
+						})
+					})
+
+					When("an index is being force merged", func() {
+						var completed bool
+
+						BeforeEach(func() {
+							completed = false
+							instance.Status.CodecMigration.Indices[0] = opsterv1.CodecMigrationIndex{
+								Name: "logs-1", State: opsterv1.CodecMigrationIndexMerging, Task: "node-1:42",
+							}
+							transport.RegisterResponder(
+								http.MethodGet,
+								fmt.Sprintf("%s_tasks/node-1:42", clusterUrl),
+								func(req *http.Request) (*http.Response, error) {
+									return httpmock.NewJsonResponse(200, map[string]interface{}{"completed": completed})
+								},
+							)
+						})
+
+						It("should wait for the force merge", func() {
+							Expect(reconcile(true)).To(BeEmpty())
+							Expect(instance.Status.CodecMigration.Indices[0].State).To(Equal(opsterv1.CodecMigrationIndexMerging))
+							Expect(transport.GetCallCountInfo()["POST "+closeUrl]).To(Equal(0))
+						})
+
+						When("the force merge completed", func() {
+							BeforeEach(func() {
+								completed = true
+							})
+
+							It("should record the index as migrated", func() {
+								Expect(reconcile(true)).To(Equal([]string{
+									fmt.Sprintf("Normal %s index logs-1 migrated to the best_compression codec", opensearchCodecMigration),
+								}))
+								Expect(instance.Status.CodecMigration.Indices[0]).To(Equal(opsterv1.CodecMigrationIndex{
+									Name: "logs-1", State: opsterv1.CodecMigrationIndexMigrated,
+								}))
+								Expect(instance.Status.CodecMigration.Indices[1].State).To(Equal(opsterv1.CodecMigrationIndexPending))
+							})
+						})
+					})
+
+					When("all indices were migrated or failed", func() {
+						BeforeEach(func() {
+							instance.Status.CodecMigration.Indices = []opsterv1.CodecMigrationIndex{
+								{Name: "logs-1", State: opsterv1.CodecMigrationIndexMigrated},
+								{Name: "logs-3", State: opsterv1.CodecMigrationIndexFailed, Reason: "index does not exist"},
+							}
+						})
+
+						It("should complete the migration", func() {
+							Expect(reconcile(true)).To(Equal([]string{
+								fmt.Sprintf("Normal %s migration to the best_compression codec completed, 1 indices migrated, 1 failed", opensearchCodecMigration),
+							}))
+							Expect(instance.Status.CodecMigration.CompletedAt).ToNot(BeNil())
+						})
+					})
+				})
+			})
+
+			Context("component template migrates the write index to a new schema version", func() {
+				var health string
+				var aliasIndices map[string]interface{}
+				var existing []string
+				var counts map[string]int
+				var aliasesUrl, reindexUrl, targetUrl, closeUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"2"}}`)}
+					instance.Spec.SchemaVersion = 2
+					instance.Spec.SchemaMigration = &opsterv1.SchemaMigration{WriteAlias: "logs"}
+					instance.Status.SchemaVersion = 1
+					health = "green"
+					aliasIndices = map[string]interface{}{
+						"logs-v1": map[string]interface{}{"aliases": map[string]interface{}{"logs": map[string]interface{}{"is_write_index": true}}},
+					}
+					existing = []string{"logs-v1"}
+					counts = map[string]int{"logs-v1": 3, "logs-v2": 3}
+					aliasesUrl = fmt.Sprintf("%s_aliases", clusterUrl)
+					reindexUrl = fmt.Sprintf("%s_reindex?wait_for_completion=false&refresh=true", clusterUrl)
+					targetUrl = fmt.Sprintf("%slogs-v2", clusterUrl)
+					closeUrl = fmt.Sprintf("%slogs-v1/_close", clusterUrl)
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+						httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+							ComponentTemplates: []responses.ComponentTemplate{{
+								Name:              "my-template",
+								ComponentTemplate: helpers.TranslateComponentTemplateToRequest(instance.Spec),
+							}},
+						}).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_cluster/health", clusterUrl),
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewJsonResponse(200, map[string]string{"status": health})
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_alias/logs", clusterUrl),
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewJsonResponse(200, aliasIndices)
+						},
+					)
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/_cat/indices/([^/?]+)\?`),
+						func(req *http.Request) (*http.Response, error) {
+							requested := strings.Split(strings.TrimPrefix(req.URL.Path, "/_cat/indices/"), ",")
+							indices := []map[string]string{}
+							for _, index := range existing {
+								if helpers.ContainsString(requested, index) {
+									indices = append(indices, map[string]string{"index": index})
+								}
+							}
+							return httpmock.NewJsonResponse(200, indices)
+						},
+					)
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/(logs-v[12])/_count$`),
+						func(req *http.Request) (*http.Response, error) {
+							index := strings.Split(strings.TrimPrefix(req.URL.Path, "/"), "/")[0]
+							return httpmock.NewJsonResponse(200, map[string]int{"count": counts[index]})
+						},
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						}).Maybe()
+				})
+
+				JustBeforeEach(func() {
+					reconciler.updateStatus = pointer.Bool(true)
+				})
+
+				reconcile := func(succeed bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeed {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				migration := func(phase opsterv1.SchemaMigrationPhase) *opsterv1.SchemaMigrationStatus {
+					return &opsterv1.SchemaMigrationStatus{
+						SchemaVersion: 2,
+						Phase:         phase,
+						SourceIndex:   "logs-v1",
+						TargetIndex:   "logs-v2",
+					}
+				}
+
+				When("the schema version is recorded for the first time", func() {
+					BeforeEach(func() {
+						instance.Status.SchemaVersion = 0
+					})
+
+					It("should record it without migrating", func() {
+						Expect(reconcile(true)).To(BeEmpty())
+						Expect(instance.Status.SchemaVersion).To(Equal(int64(2)))
+						Expect(instance.Status.Migration).To(BeNil())
+						Expect(transport.GetCallCountInfo()).ToNot(HaveKey(HavePrefix("POST ")))
+					})
+				})
+
+				When("the schema version did not change", func() {
+					BeforeEach(func() {
+						instance.Status.SchemaVersion = 2
+					})
+
+					It("should not migrate", func() {
+						Expect(reconcile(true)).To(BeEmpty())
+						Expect(instance.Status.Migration).To(BeNil())
+						Expect(transport.GetCallCountInfo()["GET "+fmt.Sprintf("%s_alias/logs", clusterUrl)]).To(Equal(0))
+					})
+				})
+
+				It("should start the migration of the write index", func() {
+					Expect(reconcile(true)).To(Equal([]string{
+						fmt.Sprintf("Normal %s migrating index logs-v1 of write alias logs to index logs-v2 for schema version 2", opensearchSchemaMigration),
+					}))
+					Expect(instance.Status.Migration).ToNot(BeNil())
+					Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationCreatingIndex))
+					Expect(instance.Status.Migration.SourceIndex).To(Equal("logs-v1"))
+					Expect(instance.Status.Migration.TargetIndex).To(Equal("logs-v2"))
+					Expect(instance.Status.SchemaVersion).To(Equal(int64(1)))
+					Expect(transport.GetCallCountInfo()["PUT "+targetUrl]).To(Equal(0))
+				})
+
+				When("the new index already exists", func() {
+					BeforeEach(func() {
+						existing = append(existing, "logs-v2")
+					})
+
+					It("should refuse the migration", func() {
+						Expect(reconcile(false)).To(Equal([]string{
+							fmt.Sprintf("Warning %s index logs-v2 of the schema migration already exists", opensearchSchemaMigration),
+						}))
+						Expect(instance.Status.Migration).To(BeNil())
+					})
+				})
+
+				When("the alias has no write index", func() {
+					BeforeEach(func() {
+						aliasIndices["logs-v0"] = map[string]interface{}{"aliases": map[string]interface{}{"logs": map[string]interface{}{}}}
+						aliasIndices["logs-v1"] = map[string]interface{}{"aliases": map[string]interface{}{"logs": map[string]interface{}{}}}
+					})
+
+					It("should refuse the migration", func() {
+						Expect(reconcile(false)).To(Equal([]string{
+							fmt.Sprintf("Warning %s write alias logs of the schema migration has no write index", opensearchSchemaMigration),
+						}))
+						Expect(instance.Status.Migration).To(BeNil())
+					})
+				})
+
+				When("the cluster is not green", func() {
+					BeforeEach(func() {
+						health = "yellow"
+					})
+
+					It("should defer the migration", func() {
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s deferring changes while the cluster health is yellow, green is required", opensearchDeferred),
+						}))
+						Expect(instance.Status.Migration).To(BeNil())
+					})
+				})
+
+				When("the new index is created", func() {
+					var shards string
+
+					BeforeEach(func() {
+						shards = "2"
+						instance.Status.Migration = migration(opsterv1.SchemaMigrationCreatingIndex)
+						transport.RegisterResponder(http.MethodPut, targetUrl, func(req *http.Request) (*http.Response, error) {
+							existing = append(existing, "logs-v2")
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						})
+						transport.RegisterResponder(http.MethodDelete, targetUrl, httpmock.NewStringResponder(200, `{"acknowledged":true}`))
+						transport.RegisterRegexpResponder(
+							http.MethodGet,
+							regexp.MustCompile(`/logs-v2/_settings\?flat_settings=true$`),
+							func(req *http.Request) (*http.Response, error) {
+								return httpmock.NewJsonResponse(200, map[string]interface{}{
+									"logs-v2": map[string]interface{}{"settings": map[string]interface{}{"index.number_of_shards": shards}},
+								})
+							},
+						)
+						transport.RegisterResponder(http.MethodGet, targetUrl+"/_mapping", httpmock.NewStringResponder(200, `{"logs-v2":{"mappings":{}}}`))
+					})
+
+					It("should create it from the component template", func() {
+						Expect(reconcile(true)).To(BeEmpty())
+						Expect(transport.GetCallCountInfo()["PUT "+targetUrl]).To(Equal(1))
+						Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationSwappingAlias))
+					})
+
+					When("the component template is not effective in the new index", func() {
+						BeforeEach(func() {
+							shards = "1"
+						})
+
+						It("should delete the new index and fail the migration", func() {
+							events := reconcile(false)
+							Expect(events).To(HaveLen(1))
+							Expect(events[0]).To(HavePrefix(fmt.Sprintf("Warning %s index logs-v2 was not created from the component template", opensearchSchemaMigration)))
+							Expect(transport.GetCallCountInfo()["DELETE "+targetUrl]).To(Equal(1))
+							Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationFailed))
+							Expect(instance.Status.Migration.Reason).To(ContainSubstring("setting index.number_of_shards is 1 instead of 2"))
+							Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateError))
+						})
+					})
+
+					When("the ISM policy of the component template changed a setting of the new index", func() {
+						BeforeEach(func() {
+							instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"2","number_of_replicas":"1"}}`)}
+							instance.Spec.ISMPolicy = &opsterv1.ComponentTemplateISMPolicy{PolicyID: "logs-policy", ConflictPolicy: opsterv1.ISMConflictIgnore}
+							transport.RegisterResponder(
+								http.MethodGet,
+								fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+								httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+									ComponentTemplates: []responses.ComponentTemplate{{
+										Name:              "my-template",
+										ComponentTemplate: helpers.TranslateComponentTemplateToRequest(instance.Spec),
+									}},
+								}).Once(failMessage),
+							)
+							transport.RegisterRegexpResponder(
+								http.MethodGet,
+								regexp.MustCompile(`/logs-v2/_settings\?flat_settings=true$`),
+								httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+									"logs-v2": map[string]interface{}{"settings": map[string]interface{}{
+										"index.number_of_shards":   "2",
+										"index.number_of_replicas": "0",
+									}},
+								}),
+							)
+							transport.RegisterResponder(
+								http.MethodGet,
+								fmt.Sprintf("%s_plugins/_ism/policies/logs-policy", clusterUrl),
+								httpmock.NewStringResponder(200, `{"_id":"logs-policy","policy":{"default_state":"hot","states":[
+									{"name":"hot","actions":[{"replica_count":{"number_of_replicas":0}}]}
+								]}}`),
+							)
+						})
+
+						It("should ignore the setting the ISM policy manages", func() {
+							Expect(reconcile(true)).To(BeEmpty())
+							Expect(transport.GetCallCountInfo()["GET "+clusterUrl+"_plugins/_ism/policies/logs-policy"]).To(Equal(1))
+							Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationSwappingAlias))
+						})
+
+						When("the conflict policy compares the settings the ISM policy manages", func() {
+							BeforeEach(func() {
+								instance.Spec.ISMPolicy.ConflictPolicy = opsterv1.ISMConflictCompare
+							})
+
+							It("should fail the migration", func() {
+								Expect(reconcile(false)).To(HaveLen(1))
+								Expect(transport.GetCallCountInfo()).To(HaveKeyWithValue("GET "+clusterUrl+"_plugins/_ism/policies/logs-policy", 0))
+								Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationFailed))
+								Expect(instance.Status.Migration.Reason).To(ContainSubstring("setting index.number_of_replicas is 0 instead of 1"))
+							})
+						})
+					})
+				})
+
+				When("the write index is swapped", func() {
+					var aliasesBody string
+
+					BeforeEach(func() {
+						instance.Status.Migration = migration(opsterv1.SchemaMigrationSwappingAlias)
+						transport.RegisterResponder(http.MethodPost, aliasesUrl, func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							aliasesBody = string(body)
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						})
+					})
+
+					It("should make the new index the write index of the alias", func() {
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s index logs-v2 is the write index of alias logs, reindexing index logs-v1 into it", opensearchSchemaMigration),
+						}))
+						Expect(aliasesBody).To(MatchJSON(`{"actions":[
+							{"add":{"index":"logs-v1","alias":"logs","is_write_index":false}},
+							{"add":{"index":"logs-v2","alias":"logs","is_write_index":true}}
+						]}`))
+						Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationReindexing))
+					})
+				})
+
+				When("the documents are reindexed", func() {
+					var reindexBody string
+
+					BeforeEach(func() {
+						instance.Status.Migration = migration(opsterv1.SchemaMigrationReindexing)
+						transport.RegisterResponder(http.MethodPost, reindexUrl, func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							reindexBody = string(body)
+							return httpmock.NewStringResponse(200, `{"task":"node-1:7"}`), nil
+						})
+					})
+
+					It("should start the reindex and record its task", func() {
+						Expect(reconcile(true)).To(BeEmpty())
+						Expect(reindexBody).To(MatchJSON(`{"conflicts":"proceed","source":{"index":"logs-v1"},"dest":{"index":"logs-v2","op_type":"create"}}`))
+						Expect(instance.Status.Migration.Task).To(Equal("node-1:7"))
+						Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationReindexing))
+					})
+
+					When("the reindex is running", func() {
+						var task map[string]interface{}
+
+						BeforeEach(func() {
+							instance.Status.Migration.Task = "node-1:7"
+							task = map[string]interface{}{"completed": false}
+							transport.RegisterResponder(
+								http.MethodGet,
+								fmt.Sprintf("%s_tasks/node-1:7", clusterUrl),
+								func(req *http.Request) (*http.Response, error) {
+									return httpmock.NewJsonResponse(200, task)
+								},
+							)
+						})
+
+						It("should wait for the reindex", func() {
+							Expect(reconcile(true)).To(BeEmpty())
+							Expect(transport.GetCallCountInfo()["POST "+reindexUrl]).To(Equal(0))
+							Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationReindexing))
+						})
+
+						When("the reindex completed", func() {
+							BeforeEach(func() {
+								task = map[string]interface{}{"completed": true, "response": map[string]interface{}{"failures": []interface{}{}}}
+							})
+
+							It("should retire the old index next", func() {
+								Expect(reconcile(true)).To(BeEmpty())
+								Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationRetiring))
+								Expect(instance.Status.Migration.Task).To(BeEmpty())
+							})
+						})
+
+						When("documents failed to be reindexed", func() {
+							BeforeEach(func() {
+								task = map[string]interface{}{"completed": true, "response": map[string]interface{}{
+									"failures": []interface{}{map[string]interface{}{"index": "logs-v2", "id": "1"}},
+								}}
+							})
+
+							It("should fail the migration", func() {
+								Expect(reconcile(false)).To(Equal([]string{
+									fmt.Sprintf("Warning %s 1 documents of index logs-v1 failed to be reindexed", opensearchSchemaMigration),
+								}))
+								Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationFailed))
+								Expect(instance.Status.Migration.CompletedAt).ToNot(BeNil())
+							})
+						})
+					})
+				})
+
+				When("the old index is retired", func() {
+					var removeBody string
+
+					BeforeEach(func() {
+						instance.Status.Migration = migration(opsterv1.SchemaMigrationRetiring)
+						aliasIndices["logs-v2"] = map[string]interface{}{"aliases": map[string]interface{}{"logs": map[string]interface{}{"is_write_index": true}}}
+						transport.RegisterResponder(http.MethodPost, aliasesUrl, func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							removeBody = string(body)
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						})
+						transport.RegisterResponder(http.MethodPost, closeUrl, httpmock.NewStringResponder(200, `{"acknowledged":true}`))
+					})
+
+					It("should remove the old index from the alias, close it and complete the migration", func() {
+						Expect(reconcile(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s index logs-v1 of write alias logs migrated to index logs-v2 for schema version 2", opensearchSchemaMigration),
+						}))
+						Expect(removeBody).To(MatchJSON(`{"actions":[{"remove":{"index":"logs-v1","alias":"logs"}}]}`))
+						Expect(transport.GetCallCountInfo()["POST "+closeUrl]).To(Equal(1))
+						Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationCompleted))
+						Expect(instance.Status.Migration.CompletedAt).ToNot(BeNil())
+						Expect(instance.Status.SchemaVersion).To(Equal(int64(2)))
+					})
+
+					When("the old index is kept", func() {
+						BeforeEach(func() {
+							instance.Spec.SchemaMigration.RetirePolicy = opsterv1.SchemaMigrationRetireKeep
+						})
+
+						It("should only remove it from the alias", func() {
+							Expect(reconcile(true)).To(HaveLen(1))
+							Expect(transport.GetCallCountInfo()["POST "+aliasesUrl]).To(Equal(1))
+							Expect(transport.GetCallCountInfo()["POST "+closeUrl]).To(Equal(0))
+							Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationCompleted))
+						})
+					})
+
+					When("the new index holds fewer documents", func() {
+						BeforeEach(func() {
+							counts["logs-v2"] = 2
+						})
+
+						It("should fail the migration without retiring the old index", func() {
+							Expect(reconcile(false)).To(Equal([]string{
+								fmt.Sprintf("Warning %s index logs-v2 holds 2 documents, fewer than the 3 documents of index logs-v1", opensearchSchemaMigration),
+							}))
+							Expect(transport.GetCallCountInfo()["POST "+aliasesUrl]).To(Equal(0))
+							Expect(transport.GetCallCountInfo()["POST "+closeUrl]).To(Equal(0))
+							Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationFailed))
+							Expect(instance.Status.SchemaVersion).To(Equal(int64(1)))
+						})
+					})
+				})
+
+				When("the migration failed", func() {
+					BeforeEach(func() {
+						instance.Status.Migration = migration(opsterv1.SchemaMigrationFailed)
+						instance.Status.Migration.Reason = "1 documents of index logs-v1 failed to be reindexed"
+					})
+
+					It("should not retry it", func() {
+						Expect(reconcile(false)).To(BeEmpty())
+						Expect(instance.Status.Reason).To(Equal("schema migration to version 2 failed: 1 documents of index logs-v1 failed to be reindexed"))
+						Expect(transport.GetCallCountInfo()).ToNot(HaveKey(HavePrefix("POST ")))
+					})
+
+					When("the schema version is increased again", func() {
+						BeforeEach(func() {
+							instance.Spec.SchemaVersion = 3
+						})
+
+						It("should start a new migration", func() {
+							Expect(reconcile(true)).To(Equal([]string{
+								fmt.Sprintf("Normal %s migrating index logs-v1 of write alias logs to index logs-v3 for schema version 3", opensearchSchemaMigration),
+							}))
+							Expect(instance.Status.Migration.Phase).To(Equal(opsterv1.SchemaMigrationCreatingIndex))
+							Expect(instance.Status.Migration.TargetIndex).To(Equal("logs-v3"))
+						})
+					})
+				})
+			})
+
+			Context("component template is allocated to a tier", func() {
+				var componentTemplateUrl string
+				var nodeAttrs []responses.CatNodeAttrsResponse
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					nodeAttrs = []responses.CatNodeAttrsResponse{
+						{Node: "node-0", Attr: "temp", Value: "hot"},
+						{Node: "node-1", Attr: "temp", Value: "warm"},
+						{Node: "node-2", Attr: "temp", Value: "cold"},
+						{Node: "node-3", Attr: "temp", Value: "frozen"},
+						{Node: "node-3", Attr: "zone", Value: "a"},
+					}
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+				})
+
+				JustBeforeEach(func() {
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/_cat/nodeattrs\?`),
+						httpmock.NewJsonResponderOrPanic(200, nodeAttrs).Once(failMessage),
+					)
+				})
+
+				for _, tier := range []opsterv1.IndexTier{opsterv1.IndexTierHot, opsterv1.IndexTierWarm, opsterv1.IndexTierCold, opsterv1.IndexTierFrozen} {
+					tier := tier
+					When(fmt.Sprintf("the %s tier is requested", tier), func() {
+						var putBody string
+
+						BeforeEach(func() {
+							instance.Spec.Tier = tier
+							instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"1"}}`)}
+							transport.RegisterResponder(
+								http.MethodPut,
+								componentTemplateUrl,
+								func(req *http.Request) (*http.Response, error) {
+									body, err := io.ReadAll(req.Body)
+									if err != nil {
+										return nil, err
+									}
+									putBody = string(body)
+									return httpmock.NewStringResponse(200, "OK"), nil
+								},
+							)
+						})
+
+						It("should require the tier in the allocation settings", func() {
+							go func() {
+								defer GinkgoRecover()
+								defer close(recorder.Events)
+								_, err := reconciler.Reconcile()
+								Expect(err).ToNot(HaveOccurred())
+								Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+							}()
+							for range recorder.Events {
+							}
+							Expect(putBody).To(ContainSubstring(fmt.Sprintf(`"settings":{"index":{"number_of_shards":"1","routing":{"allocation":{"require":{"temp":"%s"}}}}}`, tier)))
+						})
+					})
+				}
+
+				When("the tier is identified by another node attribute", func() {
+					BeforeEach(func() {
+						instance.Spec.Tier = opsterv1.IndexTierHot
+						instance.Spec.TierAttribute = "zone"
+					})
+
+					It("should fail if no node has the attribute set to the tier", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(len(events)).To(Equal(1))
+						Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s no node of the cluster has the node attribute zone set to hot", opensearchMissingTier)))
+					})
+				})
+
+				When("the cluster lacks the requested tier", func() {
+					BeforeEach(func() {
+						instance.Spec.Tier = opsterv1.IndexTierFrozen
+						nodeAttrs = nodeAttrs[:3]
+					})
+
+					It("should fail without touching the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+							Expect(transport.GetCallCountInfo()["GET "+componentTemplateUrl]).To(Equal(1))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(len(events)).To(Equal(1))
+						Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s no node of the cluster has the node attribute temp set to frozen", opensearchMissingTier)))
+					})
+				})
+
+				When("the component template in opensearch is in sync", func() {
+					BeforeEach(func() {
+						instance.Spec.Tier = opsterv1.IndexTierHot
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"1"}}`)}
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, `{"component_templates":[{"name":"my-template","component_template":{"template":{"settings":{"index":{"number_of_shards":"1","routing":{"allocation":{"require":{"temp":"hot"}}}}}}}}]}`).Once(failMessage),
+						)
+					})
+
+					It("should not ask opensearch for the nodes of the tier", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						for range recorder.Events {
+						}
+						for call, count := range transport.GetCallCountInfo() {
+							if strings.Contains(call, "_cat/nodeattrs") {
+								Expect(count).To(Equal(0))
+							}
+						}
+					})
+				})
+			})
+
+			Context("component template has a replica policy", func() {
+				var (
+					componentTemplateUrl string
+					dataNodes            int
+					putBody              string
+				)
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Spec.ReplicaPolicy = &opsterv1.ReplicaPolicy{MaxReplicas: pointer.Int32(2)}
+					putBody = ""
+				})
+
+				JustBeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_cluster/health", clusterUrl),
+						httpmock.NewStringResponder(200, fmt.Sprintf(`{"status":"green","number_of_data_nodes":%d}`, dataNodes)).Once(failMessage),
+					)
+				})
+
+				for _, nodes := range []struct {
+					dataNodes int
+					replicas  string
+				}{{1, "0"}, {2, "1"}, {3, "2"}, {10, "2"}} {
+					nodes := nodes
+					When(fmt.Sprintf("the cluster has %d data nodes", nodes.dataNodes), func() {
+						BeforeEach(func() {
+							dataNodes = nodes.dataNodes
+							transport.RegisterResponder(
+								http.MethodGet,
+								componentTemplateUrl,
+								httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+							)
+							transport.RegisterResponder(
+								http.MethodPut,
+								componentTemplateUrl,
+								func(req *http.Request) (*http.Response, error) {
+									body, err := io.ReadAll(req.Body)
+									if err != nil {
+										return nil, err
+									}
+									putBody = string(body)
+									return httpmock.NewStringResponse(200, "OK"), nil
+								},
+							)
+						})
+
+						It(fmt.Sprintf("should apply the component template with %s replicas", nodes.replicas), func() {
+							go func() {
+								defer GinkgoRecover()
+								defer close(recorder.Events)
+								_, err := reconciler.Reconcile()
+								Expect(err).ToNot(HaveOccurred())
+								Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+							}()
+							for range recorder.Events {
+							}
+							Expect(putBody).To(ContainSubstring(fmt.Sprintf(`"settings":{"index":{"number_of_replicas":"%s"}}`, nodes.replicas)))
+						})
+					})
+				}
+
+				When("the settings set the number of replicas", func() {
+					BeforeEach(func() {
+						dataNodes = 3
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_replicas":"1"}}`)}
+					})
+
+					It("should fail without touching the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s failed to apply the replica policy: settings already set index.number_of_replicas, which conflicts with the replica policy", opensearchReplicaPolicy)))
+					})
+				})
+			})
+
+			Context("component template requires field security", func() {
+				var componentTemplateUrl string
+				var role *opsterv1.OpensearchRole
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Spec.FieldSecurity = &opsterv1.ComponentTemplateFieldSecurity{
+						RoleRef:       corev1.LocalObjectReference{Name: "customers-reader"},
+						IndexPatterns: []string{"customers-*"},
+						HiddenFields:  []string{"ssn"},
+					}
+					role = &opsterv1.OpensearchRole{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "customers-reader",
+							Namespace: "test-componenttemplate",
+						},
+						Spec: opsterv1.OpensearchRoleSpec{
+							IndexPermissions: []opsterv1.IndexPermissionSpec{{
+								IndexPatterns:      []string{"customers-*"},
+								FieldLevelSecurity: []string{"~ssn"},
+								AllowedActions:     []string{"read"},
+							}},
+						},
+						Status: opsterv1.OpensearchRoleStatus{
+							State: opsterv1.OpensearchRoleStateCreated,
+						},
+					}
+				})
+
+				reconcileEvents := func(succeeds bool) []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						if succeeds {
+							Expect(err).ToNot(HaveOccurred())
+						} else {
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("the role hides the fields", func() {
+					BeforeEach(func() {
+						mockClient.EXPECT().GetOpensearchRole("customers-reader", "test-componenttemplate").Return(*role, nil)
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should apply the component template", func() {
+						Expect(reconcileEvents(true)).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+					})
+				})
+
+				When("the role no longer hides a field", func() {
+					BeforeEach(func() {
+						role.Spec.IndexPermissions[0].FieldLevelSecurity = []string{"~email"}
+						mockClient.EXPECT().GetOpensearchRole("customers-reader", "test-componenttemplate").Return(*role, nil)
+					})
+
+					It("should report the drift and not apply the component template", func() {
+						Expect(reconcileEvents(false)).To(Equal([]string{fmt.Sprintf(
+							"Warning %s role customers-reader does not provide the field security of the component template: fields ssn are not hidden for customers-*",
+							opensearchFieldSecurityDrift,
+						)}))
+					})
+				})
+
+				When("the role no longer covers the index pattern", func() {
+					BeforeEach(func() {
+						role.Spec.IndexPermissions[0].IndexPatterns = []string{"orders-*"}
+						mockClient.EXPECT().GetOpensearchRole("customers-reader", "test-componenttemplate").Return(*role, nil)
+					})
+
+					It("should report the drift and not apply the component template", func() {
+						Expect(reconcileEvents(false)).To(Equal([]string{fmt.Sprintf(
+							"Warning %s role customers-reader does not provide the field security of the component template: no index permission for customers-*",
+							opensearchFieldSecurityDrift,
+						)}))
+					})
+				})
+
+				When("the role is not created in opensearch", func() {
+					BeforeEach(func() {
+						role.Status.State = opsterv1.OpensearchRoleStateError
+						mockClient.EXPECT().GetOpensearchRole("customers-reader", "test-componenttemplate").Return(*role, nil)
+					})
+
+					It("should not apply the component template", func() {
+						Expect(reconcileEvents(false)).To(Equal([]string{fmt.Sprintf(
+							"Warning %s role customers-reader referenced by the field security is not created in OpenSearch",
+							opensearchFieldSecurityDrift,
+						)}))
+					})
+				})
+
+				When("the role does not exist", func() {
+					BeforeEach(func() {
+						mockClient.EXPECT().GetOpensearchRole("customers-reader", "test-componenttemplate").Return(opsterv1.OpensearchRole{}, NotFoundError())
+					})
+
+					It("should not apply the component template", func() {
+						Expect(reconcileEvents(false)).To(Equal([]string{fmt.Sprintf(
+							"Warning %s role customers-reader referenced by the field security does not exist",
+							opensearchFieldSecurityDrift,
+						)}))
+					})
+				})
+			})
+
+			Context("component template requires a minimum cluster health", func() {
+				var componentTemplateUrl string
+				var healthUrl string
+				var health string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.RequiredClusterHealth = opsterv1.OpenSearchYellowHealth
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					healthUrl = fmt.Sprintf("%s_cluster/health", clusterUrl)
+				})
+
+				JustBeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						healthUrl,
+						func(req *http.Request) (*http.Response, error) {
+							return httpmock.NewJsonResponse(200, responses.ClusterHealthResponse{Status: health})
+						},
+					)
+				})
+
+				reconcileEvents := func() ([]string, time.Duration) {
+					var requeueAfter time.Duration
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						result, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						requeueAfter = result.RequeueAfter
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events, requeueAfter
+				}
+
+				// recordStatus updates the status of the instance when the reconcile is done
+				recordStatus := func() {
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+					reconciler.updateStatus = pointer.Bool(true)
+				}
+
+				When("the component template has changed", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK"),
+						)
+					})
+
+					for _, status := range []string{"green", "yellow"} {
+						status := status
+						It(fmt.Sprintf("should apply it if the cluster is %s", status), func() {
+							health = status
+							events, requeueAfter := reconcileEvents()
+							Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)}))
+							Expect(requeueAfter).To(Equal(30 * time.Second))
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+						})
+					}
+
+					It("should defer it if the cluster is red", func() {
+						health = "red"
+						recordStatus()
+						events, requeueAfter := reconcileEvents()
+						Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s deferring changes while the cluster health is red, yellow is required", opensearchDeferred)}))
+						Expect(requeueAfter).To(Equal(10 * time.Second))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateDeferred))
+						Expect(instance.Status.Reason).To(Equal("deferring changes while the cluster health is red, yellow is required"))
+					})
+
+					It("should name the missing privilege if the operator user may not get the cluster health", func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							healthUrl,
+							httpmock.NewStringResponder(403, `{"error":{"type":"security_exception"}}`),
+						)
+						reason := "operator user is not authorized to manage component templates, check that it has the cluster:monitor/health privilege"
+						recordStatus()
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(MatchError(services.ErrForbidden))
+						}()
+						Expect(<-recorder.Events).To(Equal(fmt.Sprintf("Warning %s %s", opensearchForbidden, reason)))
+						Eventually(recorder.Events).Should(BeClosed())
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateForbidden))
+						Expect(instance.Status.Reason).To(Equal(reason))
+					})
+
+					It("should defer it if the cluster is yellow and green is required", func() {
+						health = "yellow"
+						instance.Spec.RequiredClusterHealth = opsterv1.OpenSearchGreenHealth
+						events, requeueAfter := reconcileEvents()
+						Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s deferring changes while the cluster health is yellow, green is required", opensearchDeferred)}))
+						Expect(requeueAfter).To(Equal(10 * time.Second))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+					})
+				})
+
+				When("the component template is in sync", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+								ComponentTemplates: []responses.ComponentTemplate{{
+									Name:              "my-template",
+									ComponentTemplate: helpers.TranslateComponentTemplateToRequest(instance.Spec),
+								}},
+							}).Once(failMessage),
+						)
+					})
+
+					It("should still compare it while the cluster is red", func() {
+						health = "red"
+						events, requeueAfter := reconcileEvents()
+						Expect(events).To(BeEmpty())
+						Expect(requeueAfter).To(Equal(30 * time.Second))
+						Expect(transport.GetCallCountInfo()["GET "+componentTemplateUrl]).To(Equal(1))
+						Expect(transport.GetCallCountInfo()["GET "+healthUrl]).To(Equal(0))
+					})
+				})
+			})
+
+			Context("component template is rolled back", func() {
+				var (
+					componentTemplateUrl string
+					previous             requests.ComponentTemplate
+					current              requests.ComponentTemplate
+					live                 requests.ComponentTemplate
+					putBody              string
+				)
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(10)
+					putBody = ""
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Generation = 2
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_replicas":"2"}}`)}
+					current = helpers.TranslateComponentTemplateToRequest(instance.Spec)
+					previous = requests.ComponentTemplate{
+						Template: requests.Index{
+							Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_replicas":"1"}}`)},
+						},
+						Version: 1,
+					}
+					live = current
+				})
+
+				JustBeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewJsonResponderOrPanic(200, responses.GetComponentTemplatesResponse{
+							ComponentTemplates: []responses.ComponentTemplate{{Name: "my-template", ComponentTemplate: live}},
+						}).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							putBody = string(body)
+							return httpmock.NewStringResponse(200, "OK"), nil
+						},
+					)
+				})
+
+				reconcile := func(succeeds bool) []string {
+					result, err := reconciler.Reconcile()
+					if succeeds {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+					} else {
+						Expect(err).To(HaveOccurred())
+					}
+					close(recorder.Events)
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("the spec changes the component template", func() {
+					BeforeEach(func() {
+						live = previous
+					})
+
+					It("should keep the replaced component template", func() {
+						reconcile(true)
+						Expect(putBody).ToNot(BeEmpty())
+						Expect(instance.Status.PreviousApplied).ToNot(BeNil())
+						Expect(instance.Status.PreviousApplied.Raw).To(MatchJSON(`{"template":{"settings":{"index":{"number_of_replicas":"1"}}},"version":1}`))
+					})
+				})
+
+				When("the replaced component template holds sensitive values", func() {
+					BeforeEach(func() {
+						previous.Meta = &apiextensionsv1.JSON{Raw: []byte(`{"api_token":"abc"}`)}
+						live = previous
+						instance.Status.PreviousApplied = &apiextensionsv1.JSON{Raw: []byte(`{"version":0}`)}
+					})
+
+					It("should not keep it", func() {
+						reconcile(true)
+						Expect(putBody).ToNot(BeEmpty())
+						Expect(instance.Status.PreviousApplied).To(BeNil())
+					})
+				})
+
+				When("the replaced component template holds redacted values", func() {
+					BeforeEach(func() {
+						live = previous
+						instance.Status.PreviousApplied = &apiextensionsv1.JSON{Raw: []byte(`{"version":0}`)}
+					})
+
+					JustBeforeEach(func() {
+						reconciler.redaction = helpers.NewRedaction([]string{"template.settings.index.number_of_replicas"})
+					})
+
+					It("should apply the real values without keeping the replaced component template", func() {
+						reconcile(true)
+						Expect(putBody).To(MatchJSON(`{"template":{"settings":{"index":{"number_of_replicas":"2"}}}}`))
+						Expect(instance.Status.PreviousApplied).To(BeNil())
+						hash, err := componentTemplateHash(current)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(instance.Status.LastAppliedHash).To(Equal(hash))
+					})
+				})
+
+				When("the rollback annotation is set", func() {
+					BeforeEach(func() {
+						instance.Annotations = map[string]string{helpers.RollbackAnnotation: "true"}
+						body, err := json.Marshal(previous)
+						Expect(err).ToNot(HaveOccurred())
+						instance.Status.PreviousApplied = &apiextensionsv1.JSON{Raw: body}
+					})
+
+					It("should reapply the previous component template", func() {
+						events := reconcile(true)
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Normal %s rolled back the component template to the previously applied version", opensearchRollback),
+						}))
+						Expect(putBody).To(MatchJSON(`{"template":{"settings":{"index":{"number_of_replicas":"1"}}},"version":1}`))
+						Expect(instance.Status.RollbackGeneration).To(Equal(int64(2)))
+						hash, err := componentTemplateHash(previous)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(instance.Status.LastAppliedHash).To(Equal(hash))
+					})
+
+					When("the component template is already rolled back", func() {
+						BeforeEach(func() {
+							instance.Status.RollbackGeneration = 2
+							live = previous
+						})
+
+						It("should keep the previous component template", func() {
+							Expect(reconcile(true)).To(BeEmpty())
+							Expect(putBody).To(BeEmpty())
+						})
+					})
+
+					When("the spec changed after the rollback", func() {
+						BeforeEach(func() {
+							instance.Status.RollbackGeneration = 1
+							live = previous
+						})
+
+						It("should apply the spec", func() {
+							reconcile(true)
+							Expect(putBody).To(ContainSubstring(`"number_of_replicas":"2"`))
+							Expect(instance.Status.RollbackGeneration).To(Equal(int64(1)))
+						})
+					})
+
+					When("no previous component template is known", func() {
+						BeforeEach(func() {
+							instance.Status.PreviousApplied = nil
+						})
+
+						It("should fail without changing the component template", func() {
+							events := reconcile(false)
+							Expect(events).To(Equal([]string{
+								fmt.Sprintf("Warning %s no previously applied version of the component template is known, cannot roll back", opensearchRollback),
+							}))
+							Expect(putBody).To(BeEmpty())
+						})
+					})
+				})
+
+				When("the rollback annotation is removed", func() {
+					BeforeEach(func() {
+						instance.Status.RollbackGeneration = 2
+						live = previous
+					})
+
+					It("should apply the spec again", func() {
+						reconcile(true)
+						Expect(putBody).To(ContainSubstring(`"number_of_replicas":"2"`))
+						Expect(instance.Status.RollbackGeneration).To(BeZero())
+					})
+				})
+			})
+
+			Context("component template is created only once", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Spec.ApplyMode = opsterv1.TemplateApplyModeCreateOnly
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+				})
+
+				JustBeforeEach(func() {
+					reconciler.updateStatus = pointer.Bool(true)
+				})
+
+				reconcileEvents := func() []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					return events
+				}
+
+				When("it was not created yet", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should create it and record that it was created", func() {
+						Expect(reconcileEvents()).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						Expect(instance.Status.CreatedOnce).To(BeTrue())
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+					})
+				})
+
+				When("it was created and changed in opensearch", func() {
+					BeforeEach(func() {
+						instance.Status.CreatedOnce = true
+						transport.RegisterResponder(
+							http.MethodHead,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should not update it", func() {
+						Expect(reconcileEvents()).To(BeEmpty())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						Expect(transport.GetCallCountInfo()["GET "+componentTemplateUrl]).To(Equal(0))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+					})
+				})
+
+				When("it was created and deleted from opensearch", func() {
+					BeforeEach(func() {
+						instance.Status.CreatedOnce = true
+						transport.RegisterResponder(
+							http.MethodHead,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should create it again", func() {
+						Expect(reconcileEvents()).To(Equal([]string{
+							fmt.Sprintf("Normal %s component template was deleted from opensearch, creating it again", opensearchPending),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						Expect(instance.Status.CreatedOnce).To(BeTrue())
+					})
+				})
+			})
+
+			Context("component template requeues are jittered", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+				})
+
+				JustBeforeEach(func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					reconciler.requeueJitter = helpers.DefaultRequeueJitter
+				})
+
+				It("should requeue within the jitter bounds and still record the state", func() {
+					var result ctrl.Result
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						var err error
+						result, err = reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					for range recorder.Events {
+					}
+					Expect(result.Requeue).To(BeTrue())
+					Expect(result.RequeueAfter).To(BeNumerically(">=", 24*time.Second))
+					Expect(result.RequeueAfter).To(BeNumerically("<=", 36*time.Second))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchComponentTemplateCreated))
+				})
+			})
+
+			Context("component template is sent compressed", func() {
+				var (
+					componentTemplateUrl string
+					contentEncodings     []string
+					putResponses         []*http.Response
+				)
+
+				gzipped := func(data string) []byte {
+					var buf bytes.Buffer
+					writer := gzip.NewWriter(&buf)
+					_, err := writer.Write([]byte(data))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(writer.Close()).To(Succeed())
+					return buf.Bytes()
+				}
+
+				gzippedResponse := func(status int, data string) *http.Response {
+					resp := httpmock.NewBytesResponse(status, gzipped(data))
+					resp.Header.Set("Content-Encoding", "gzip")
+					return resp
+				}
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					contentEncodings = nil
+					putResponses = nil
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							encoding := req.Header.Get("Content-Encoding")
+							contentEncodings = append(contentEncodings, encoding)
+							var body io.Reader = req.Body
+							if encoding == "gzip" {
+								reader, err := gzip.NewReader(req.Body)
+								if err != nil {
+									return nil, err
+								}
+								body = reader
+							}
+							var template requests.ComponentTemplate
+							if err := json.NewDecoder(body).Decode(&template); err != nil {
+								return httpmock.NewStringResponse(400, err.Error()), nil
+							}
+							resp := putResponses[0]
+							putResponses = putResponses[1:]
+							return resp, nil
 						},
+					)
+				})
+
+				JustBeforeEach(func() {
+					reconciler.requestCompression = services.NewRequestCompression()
+				})
+
+				reconcileEvents := func() ([]string, error) {
+					var err error
+					go func() {
+						defer close(recorder.Events)
+						_, err = reconciler.Reconcile()
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
 					}
+					return events, err
+				}
 
-					transport.RegisterResponder(
-						http.MethodGet,
-						fmt.Sprintf("%s_component_template/my-template", clusterUrl),
-						httpmock.NewJsonResponderOrPanic(200, response).Once(failMessage),
-					)
+				When("the cluster accepts compressed requests", func() {
+					BeforeEach(func() {
+						putResponses = []*http.Response{gzippedResponse(200, `{"acknowledged":true}`)}
+					})
+
+					It("should send the component template gzipped", func() {
+						events, err := reconcileEvents()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(events).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+						Expect(contentEncodings).To(Equal([]string{"gzip"}))
+					})
 				})
 
-				It("should do nothing", func() {
-					_, err := reconciler.Reconcile()
-					Expect(err).ToNot(HaveOccurred())
-					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				When("the cluster rejects compressed requests", func() {
+					BeforeEach(func() {
+						putResponses = []*http.Response{
+							httpmock.NewStringResponse(http.StatusUnsupportedMediaType, "unsupported content encoding"),
+							httpmock.NewStringResponse(200, `{"acknowledged":true}`),
+						}
+					})
+
+					It("should send it again uncompressed", func() {
+						events, err := reconcileEvents()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(events).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+						Expect(contentEncodings).To(Equal([]string{"gzip", ""}))
+					})
+				})
+
+				When("the cluster returns a compressed error", func() {
+					BeforeEach(func() {
+						putResponses = []*http.Response{
+							gzippedResponse(400, `{"error":{"type":"mapper_parsing_exception","reason":"unknown field type"}}`),
+						}
+					})
+
+					It("should report the decompressed error", func() {
+						events, err := reconcileEvents()
+						Expect(err).To(MatchError(ContainSubstring("mapper_parsing_exception")))
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s failed to update component template with OpenSearch API", opensearchAPIError)))
+					})
 				})
 			})
 
-			When("componenttemplate exists in opensearch and is not the same", func() {
+			Context("component template mapping declares many fields", func() {
+				var componentTemplateUrl string
+
 				BeforeEach(func() {
-					recorder = record.NewFakeRecorder(1)
+					recorder = record.NewFakeRecorder(2)
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"mapping":{"total_fields":{"limit":"10"}}}}`)}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
 
-					response := responses.GetComponentTemplatesResponse{
-						ComponentTemplates: make([]responses.ComponentTemplate, 1),
+				mappingWithFields := func(count int) *apiextensionsv1.JSON {
+					properties := map[string]interface{}{}
+					for i := 0; i < count; i++ {
+						properties[fmt.Sprintf("field%d", i)] = map[string]string{"type": "keyword"}
 					}
-					response.ComponentTemplates[0] = responses.ComponentTemplate{
-						Name: "my-template",
-						ComponentTemplate: requests.ComponentTemplate{
-							Template: requests.Index{
-								Settings: &apiextensionsv1.JSON{},
-								Mappings: &apiextensionsv1.JSON{},
-								Aliases:  make(map[string]requests.IndexAlias),
-							},
-							Version: 100,
-							Meta:    &apiextensionsv1.JSON{},
-						},
+					raw, err := json.Marshal(map[string]interface{}{"properties": properties})
+					Expect(err).ToNot(HaveOccurred())
+					return &apiextensionsv1.JSON{Raw: raw}
+				}
+
+				reconcileEvents := func() []string {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
 					}
+					return events
+				}
 
-					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+				When("the field count is well below the limit", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Mappings = mappingWithFields(5)
+					})
+
+					It("should apply the component template without a warning", func() {
+						Expect(reconcileEvents()).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+					})
+				})
+
+				When("the field count is close to the limit", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Mappings = mappingWithFields(9)
+					})
+
+					It("should warn and apply the component template", func() {
+						Expect(reconcileEvents()).To(Equal([]string{
+							fmt.Sprintf("Warning %s mapping declares 9 fields which is close to the index.mapping.total_fields.limit of 10", opensearchFieldCountWarning),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+
+				When("the field count exceeds the limit", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Mappings = mappingWithFields(11)
+					})
+
+					It("should warn and apply the component template", func() {
+						Expect(reconcileEvents()).To(Equal([]string{
+							fmt.Sprintf("Warning %s mapping declares 11 fields which exceeds the index.mapping.total_fields.limit of 10", opensearchFieldCountWarning),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+			})
+
+			Context("component template mapping nests objects", func() {
+				var componentTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"mapping":{"depth":{"limit":"10"}}}}`)}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
 					transport.RegisterResponder(
 						http.MethodGet,
 						componentTemplateUrl,
-						httpmock.NewJsonResponderOrPanic(200, response).Once(failMessage),
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
 					)
 					transport.RegisterResponder(
 						http.MethodPut,
@@ -325,52 +5833,155 @@ var _ = Describe("componenttemplate reconciler", func() {
 					)
 				})
 
-				It("should update the componenttemplate", func() {
+				// mappingWithDepth nests objects so the mapping has the given depth, counting the root object
+				mappingWithDepth := func(depth int) *apiextensionsv1.JSON {
+					var mapping interface{} = map[string]interface{}{"properties": map[string]interface{}{"leaf": map[string]string{"type": "keyword"}}}
+					for i := 1; i < depth; i++ {
+						mapping = map[string]interface{}{"properties": map[string]interface{}{fmt.Sprintf("level%d", i): mapping}}
+					}
+					raw, err := json.Marshal(mapping)
+					Expect(err).ToNot(HaveOccurred())
+					return &apiextensionsv1.JSON{Raw: raw}
+				}
+
+				reconcileEvents := func(applied bool) []string {
 					go func() {
 						defer GinkgoRecover()
 						defer close(recorder.Events)
 						_, err := reconciler.Reconcile()
-						Expect(err).ToNot(HaveOccurred())
-						// Confirm all responders have been called
-						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						if applied {
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+						} else {
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}
 					}()
 					var events []string
 					for msg := range recorder.Events {
 						events = append(events, msg)
 					}
-					Expect(len(events)).To(Equal(1))
-					Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+					return events
+				}
+
+				When("the mapping is shallow", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Mappings = mappingWithDepth(3)
+					})
+
+					It("should apply the component template without a warning", func() {
+						Expect(reconcileEvents(true)).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+					})
+				})
+
+				When("the mapping is close to the depth limit", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Mappings = mappingWithDepth(9)
+					})
+
+					It("should warn and apply the component template", func() {
+						Expect(reconcileEvents(true)).To(Equal([]string{
+							fmt.Sprintf("Warning %s mapping has a depth of 9 which is close to the index.mapping.depth.limit of 10", opensearchDepthLimit),
+							fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+
+				When("the mapping exceeds the depth limit", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Mappings = mappingWithDepth(11)
+					})
+
+					It("should not apply the component template", func() {
+						Expect(reconcileEvents(false)).To(Equal([]string{
+							fmt.Sprintf("Warning %s mapping has a depth of 11 which exceeds the index.mapping.depth.limit of 10", opensearchDepthLimit),
+						}))
+					})
+				})
+
+				When("the mapping exceeds the default depth limit", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{}
+						instance.Spec.Template.Mappings = mappingWithDepth(helpers.DefaultDepthLimit + 1)
+					})
+
+					It("should not apply the component template", func() {
+						Expect(reconcileEvents(false)).To(Equal([]string{
+							fmt.Sprintf("Warning %s mapping has a depth of 21 which exceeds the index.mapping.depth.limit of 20", opensearchDepthLimit),
+						}))
+					})
 				})
 			})
 
-			When("indextemplate exists in opensearch but the name has changed", func() {
-				BeforeEach(func() {
-					recorder = record.NewFakeRecorder(1)
+			Context("component template sorts indices", func() {
+				var componentTemplateUrl string
 
-					instance.Status.ComponentTemplateName = "my-template" // old template name
-					instance.Spec.Name = "new-template"                   // new template name
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Spec.Template.Mappings = &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"timestamp":{"type":"date"},"message":{"type":"text","fields":{"raw":{"type":"keyword"}}}}}`)}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
 				})
 
-				It("should fail", func() {
+				reconcileEvents := func(applied bool) []string {
 					go func() {
 						defer GinkgoRecover()
 						defer close(recorder.Events)
 						_, err := reconciler.Reconcile()
-						Expect(err).To(HaveOccurred())
+						if applied {
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+						} else {
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}
 					}()
 					var events []string
 					for msg := range recorder.Events {
 						events = append(events, msg)
 					}
-					Expect(len(events)).To(Equal(1))
-					Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the component template name", opensearchComponentTemplateNameMismatch)))
+					return events
+				}
+
+				When("the sort fields are mapped", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"sort.field":["timestamp","message.raw"],"sort.order":["desc","asc"]}}`)}
+					})
+
+					It("should apply the component template", func() {
+						Expect(reconcileEvents(true)).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+					})
+				})
+
+				When("a sort field is not mapped", func() {
+					BeforeEach(func() {
+						instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"index":{"sort.field":["@timestamp","message.raw"],"sort.order":["desc","asc"]}}`)}
+					})
+
+					It("should not apply the component template", func() {
+						Expect(reconcileEvents(false)).To(Equal([]string{
+							fmt.Sprintf("Warning %s index.sort.field references fields that are not mapped: @timestamp", opensearchInvalidIndexSort),
+						}))
+					})
 				})
 			})
 
-			When("componenttemplate doesn't exist in opensearch", func() {
+			Context("component template has a sync wave", func() {
+				var componentTemplateUrl string
+
 				BeforeEach(func() {
 					recorder = record.NewFakeRecorder(1)
-					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					instance.Annotations = map[string]string{helpers.SyncWaveAnnotation: "1"}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
 					transport.RegisterResponder(
 						http.MethodGet,
 						componentTemplateUrl,
@@ -383,21 +5994,248 @@ var _ = Describe("componenttemplate reconciler", func() {
 					)
 				})
 
-				It("should create the componenttemplate", func() {
+				otherTemplate := func(name string, wave string, state opsterv1.OpensearchComponentTemplateState) opsterv1.OpensearchComponentTemplate {
+					return opsterv1.OpensearchComponentTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:        name,
+							Namespace:   instance.Namespace,
+							UID:         types.UID(name),
+							Annotations: map[string]string{helpers.SyncWaveAnnotation: wave},
+						},
+						Spec:   opsterv1.OpensearchComponentTemplateSpec{OpensearchRef: instance.Spec.OpensearchRef},
+						Status: opsterv1.OpensearchComponentTemplateStatus{State: state},
+					}
+				}
+
+				reconcileEvents := func() []string {
 					go func() {
 						defer GinkgoRecover()
 						defer close(recorder.Events)
 						_, err := reconciler.Reconcile()
 						Expect(err).ToNot(HaveOccurred())
-						// Confirm all responders have been called
-						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
 					}()
 					var events []string
 					for msg := range recorder.Events {
 						events = append(events, msg)
 					}
-					Expect(len(events)).To(Equal(1))
-					Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+					return events
+				}
+
+				When("a component template of a lower wave is not synced", func() {
+					BeforeEach(func() {
+						mockClient.EXPECT().ListOpensearchComponentTemplates(mock.Anything).Return(opsterv1.OpensearchComponentTemplateList{
+							Items: []opsterv1.OpensearchComponentTemplate{
+								otherTemplate("base-settings", "0", opsterv1.OpensearchComponentTemplatePending),
+								otherTemplate("later-mappings", "2", opsterv1.OpensearchComponentTemplatePending),
+								*instance,
+							},
+						}, nil)
+					})
+
+					It("should wait for it before applying", func() {
+						Expect(reconcileEvents()).To(Equal([]string{
+							fmt.Sprintf("Normal %s waiting for component templates of lower sync waves to be synced: base-settings", opensearchPending),
+						}))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(BeZero())
+					})
+				})
+
+				When("the component templates of lower waves are synced", func() {
+					BeforeEach(func() {
+						mockClient.EXPECT().ListOpensearchComponentTemplates(mock.Anything).Return(opsterv1.OpensearchComponentTemplateList{
+							Items: []opsterv1.OpensearchComponentTemplate{
+								otherTemplate("base-settings", "0", opsterv1.OpensearchComponentTemplateCreated),
+								otherTemplate("later-mappings", "2", opsterv1.OpensearchComponentTemplatePending),
+								*instance,
+							},
+						}, nil)
+					})
+
+					It("should apply the component template", func() {
+						Expect(reconcileEvents()).To(ConsistOf(fmt.Sprintf("Normal %s component template updated in opensearch", opensearchAPIUpdated)))
+						Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+					})
+				})
+			})
+
+			Context("component template declares aliases", func() {
+				var (
+					componentTemplateUrl string
+					catIndicesUrl        string
+				)
+
+				BeforeEach(func() {
+					instance.Spec.Template.Aliases = map[string]opsterv1.OpensearchIndexAliasSpec{
+						"logs":         {},
+						"{index}-read": {},
+					}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					// Only the alias names without placeholders are looked up
+					catIndicesUrl = fmt.Sprintf("%s_cat/indices/logs", clusterUrl)
+				})
+
+				When("an alias has the name of an existing index", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						transport.RegisterResponder(
+							http.MethodGet,
+							catIndicesUrl,
+							httpmock.NewStringResponder(200, `[{"index":"logs"}]`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+					})
+
+					It("should fail without touching the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s aliases of the component template have the names of existing indices: logs", opensearchAliasCollision)))
+					})
+				})
+
+				When("no alias has the name of an existing index", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						transport.RegisterResponder(
+							http.MethodGet,
+							catIndicesUrl,
+							// logs is an alias of the index, which is no collision
+							httpmock.NewStringResponder(200, `[{"index":"logs-000001"}]`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should apply the component template", func() {
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					})
+				})
+			})
+
+			Context("component template uses analysis components of plugins", func() {
+				var (
+					componentTemplateUrl string
+					nodesPluginsUrl      string
+				)
+
+				BeforeEach(func() {
+					instance.Spec.Template.Settings = &apiextensionsv1.JSON{Raw: []byte(`{"analysis":{"analyzer":{"japanese":{"type":"custom","tokenizer":"kuromoji_tokenizer","filter":["icu_folding"]}}}}`)}
+					componentTemplateUrl = fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+					nodesPluginsUrl = fmt.Sprintf("%s_nodes/plugins", clusterUrl)
+				})
+
+				When("the plugins are installed on all nodes", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						transport.RegisterResponder(
+							http.MethodGet,
+							nodesPluginsUrl,
+							httpmock.NewStringResponder(200, `{"nodes":{
+								"a":{"name":"node-a","plugins":[{"name":"analysis-icu"},{"name":"analysis-kuromoji"}]},
+								"b":{"name":"node-b","plugins":[{"name":"analysis-kuromoji"},{"name":"analysis-icu"}]}
+							}}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should apply the component template", func() {
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					})
+				})
+
+				When("a plugin is missing on a node", func() {
+					BeforeEach(func() {
+						recorder = record.NewFakeRecorder(1)
+						transport.RegisterResponder(
+							http.MethodGet,
+							nodesPluginsUrl,
+							httpmock.NewStringResponder(200, `{"nodes":{
+								"a":{"name":"node-a","plugins":[{"name":"analysis-icu"},{"name":"analysis-kuromoji"}]},
+								"b":{"name":"node-b","plugins":[{"name":"analysis-icu"}]}
+							}}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodGet,
+							componentTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+					})
+
+					It("should fail without touching the component template", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s component template uses analysis components of plugins that are not installed on all nodes: analysis-kuromoji", opensearchMissingAnalysisPlugin)))
+					})
+
+					When("missing plugins are ignored", func() {
+						BeforeEach(func() {
+							recorder = record.NewFakeRecorder(2)
+							instance.Spec.IgnoreMissingAnalysisPlugins = true
+							transport.RegisterResponder(
+								http.MethodPut,
+								componentTemplateUrl,
+								httpmock.NewStringResponder(200, "OK").Once(failMessage),
+							)
+						})
+
+						It("should warn and apply the component template", func() {
+							go func() {
+								defer GinkgoRecover()
+								defer close(recorder.Events)
+								_, err := reconciler.Reconcile()
+								Expect(err).ToNot(HaveOccurred())
+								Expect(transport.GetCallCountInfo()["PUT "+componentTemplateUrl]).To(Equal(1))
+							}()
+							var events []string
+							for msg := range recorder.Events {
+								events = append(events, msg)
+							}
+							Expect(events).To(HaveLen(2))
+							Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s component template uses analysis components of plugins that are not installed on all nodes: analysis-kuromoji", opensearchMissingAnalysisPlugin)))
+						})
+					})
 				})
 			})
 		})
@@ -420,10 +6258,26 @@ var _ = Describe("componenttemplate reconciler", func() {
 		})
 
 		Context("existing status is false", func() {
+			var indexTemplates []opsterv1.OpensearchIndexTemplate
+
 			BeforeEach(func() {
 				instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+				indexTemplates = nil
+				mockClient.EXPECT().ListOpensearchIndexTemplates(mock.Anything).RunAndReturn(func(...client.ListOption) (opsterv1.OpensearchIndexTemplateList, error) {
+					return opsterv1.OpensearchIndexTemplateList{Items: indexTemplates}, nil
+				}).Maybe()
 			})
 
+			composingIndexTemplate := func(name string, clusterName string) opsterv1.OpensearchIndexTemplate {
+				return opsterv1.OpensearchIndexTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace},
+					Spec: opsterv1.OpensearchIndexTemplateSpec{
+						OpensearchRef: corev1.LocalObjectReference{Name: clusterName},
+						ComposedOf:    []string{"base-template", "my-template"},
+					},
+				}
+			}
+
 			When("cluster does not exist", func() {
 				BeforeEach(func() {
 					instance.Spec.OpensearchRef.Name = "doesnotexist"
@@ -491,6 +6345,122 @@ var _ = Describe("componenttemplate reconciler", func() {
 					Expect(reconciler.Delete()).To(Succeed())
 					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
 				})
+
+				When("index templates composing it are deleted as well or target another cluster", func() {
+					BeforeEach(func() {
+						deleted := composingIndexTemplate("deleted-index-template", cluster.Name)
+						deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+						indexTemplates = []opsterv1.OpensearchIndexTemplate{
+							deleted,
+							composingIndexTemplate("other-cluster-index-template", "other-cluster"),
+						}
+					})
+
+					It("should delete the componenttemplate", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+			})
+
+			When("index templates compose the componenttemplate", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					unrelated := composingIndexTemplate("unrelated-index-template", cluster.Name)
+					unrelated.Spec.ComposedOf = []string{"base-template"}
+					indexTemplates = []opsterv1.OpensearchIndexTemplate{
+						composingIndexTemplate("logs-index-template", cluster.Name),
+						composingIndexTemplate("app-index-template", cluster.Name),
+						unrelated,
+					}
+				})
+
+				It("should not delete the componenttemplate and explain why", func() {
+					err := reconciler.Delete()
+					Expect(err).To(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(BeZero())
+					close(recorder.Events)
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s component template my-template is not deleted while the OpensearchIndexTemplates "+
+						"app-index-template, logs-index-template compose it, remove it from their composedOf or delete them", opensearchDeleteBlocked)))
+				})
+			})
+
+			When("componenttemplate is deleted concurrently", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(404, `{"error":{"type":"resource_not_found_exception"},"status":404}`).Once(failMessage),
+					)
+				})
+
+				It("should treat the delete as done", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+				})
+			})
+
+			When("the operator user is not allowed to delete component templates", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						componentTemplateUrl,
+						httpmock.NewStringResponder(403, `{"error":{"type":"security_exception"}}`).Once(failMessage),
+					)
+				})
+
+				It("should fail and name the missing privilege", func() {
+					err := reconciler.Delete()
+					Expect(errors.Is(err, services.ErrForbidden)).To(BeTrue())
+					close(recorder.Events)
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(ConsistOf(fmt.Sprintf("Warning %s operator user is not authorized to manage component templates, check that it has the %s privilege", opensearchForbidden, componentTemplateDeletePrivilege)))
+				})
 			})
 		})
 	})
@@ -0,0 +1,243 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	opensearchConcurrentModification = "OpensearchComponentTemplateConcurrentModification"
+	opensearchDeprecatedSetting      = "OpensearchComponentTemplateDeprecatedSetting"
+	opensearchShardChangeInfo        = "OpensearchComponentTemplateShardChangeInfo"
+)
+
+// writeComponentTemplate creates or updates the component template and returns the template it replaced and the
+// deprecation warnings OpenSearch reported. With
+// conditional writes it is only written while OpenSearch still has the live template the change was computed from.
+// If another writer changed it in the meantime, e.g. a second operator instance running without leader election, the
+// template is read again and the write retried, unless the other writer already wrote the same template.
+func (r *ComponentTemplateReconciler) writeComponentTemplate(ctx context.Context, templateName string, template requests.ComponentTemplate, live *requests.ComponentTemplate) (*requests.ComponentTemplate, []string, error) {
+	if !r.conditionalWrites {
+		deprecations, err := services.CreateOrUpdateComponentTemplate(ctx, r.osClient, templateName, template)
+		return live, deprecations, err
+	}
+	for attempt := 0; ; attempt++ {
+		deprecations, err := services.UpdateComponentTemplateIfUnchanged(ctx, r.osClient, templateName, template, live)
+		if !errors.Is(err, services.ErrConcurrentModification) {
+			return live, deprecations, err
+		}
+		r.logger.Info(fmt.Sprintf("component template %s was modified by another writer since it was read", templateName))
+		r.recorder.Event(r.instance, "Warning", opensearchConcurrentModification, "component template was modified concurrently by another writer")
+		if attempt >= r.conditionalWriteRetries {
+			return live, nil, err
+		}
+
+		live, err = services.GetComponentTemplate(r.ctx, r.osClient, templateName)
+		if err != nil {
+			return live, nil, err
+		}
+		if live != nil && helpers.ComponentTemplatesEqual(template, *live) {
+			// Nothing was replaced by this write, the template is kept as the other writer applied it
+			return nil, nil, nil
+		}
+	}
+}
+
+// reportDeprecations emits an event for every deprecation warning OpenSearch reported when the component template
+// was written. OpenSearch only reports them when a template is written, so if fail is set the reconcile fails and
+// the write is reverted by the caller.
+func (r *ComponentTemplateReconciler) reportDeprecations(templateName string, deprecations []string, fail bool) (string, error) {
+	for _, deprecation := range deprecations {
+		r.recorder.Event(r.instance, "Warning", opensearchDeprecatedSetting,
+			fmt.Sprintf("OpenSearch reported a deprecation for component template %s: %s", templateName, deprecation))
+	}
+	if len(deprecations) == 0 || !fail {
+		return "", nil
+	}
+	reason := fmt.Sprintf("component template %s uses deprecated settings: %s", templateName, strings.Join(deprecations, "; "))
+	return reason, errors.New(reason)
+}
+
+// reportShardChange explains with an event that a changed index.number_of_shards only applies to indices created
+// afterwards. OpenSearch cannot change the number of shards of existing indices, the event is only advisory.
+func (r *ComponentTemplateReconciler) reportShardChange(replaced *requests.ComponentTemplate, template requests.ComponentTemplate) {
+	if replaced == nil {
+		return
+	}
+	previous, err := helpers.NumberOfShards(replaced.Template.Settings)
+	if err != nil {
+		return
+	}
+	proposed, err := helpers.NumberOfShards(template.Template.Settings)
+	if err != nil || previous == proposed {
+		return
+	}
+	if previous == "" {
+		previous = "the default"
+	}
+	if proposed == "" {
+		proposed = "the default"
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchShardChangeInfo, fmt.Sprintf(
+		"index.number_of_shards changes from %s to %s, only indices created from the template afterwards get the new number of shards. "+
+			"Existing indices keep theirs, reindex them or roll over their aliases or data streams to use it",
+		previous, proposed,
+	))
+}
+
+// revertWrite restores the component template that was replaced by a write, or deletes the template if the write
+// created it. A failed revert is only logged, the reconcile fails either way.
+func (r *ComponentTemplateReconciler) revertWrite(templateName string, replaced *requests.ComponentTemplate) {
+	var err error
+	if replaced == nil {
+		err = services.DeleteComponentTemplate(r.ctx, r.osClient, templateName)
+	} else {
+		_, err = services.CreateOrUpdateComponentTemplate(r.ctx, r.osClient, templateName, *replaced)
+	}
+	if err != nil {
+		r.logger.Error(err, fmt.Sprintf("failed to revert component template %s", templateName))
+	}
+}
+
+// specApplied returns true if the component template was already applied for the current spec
+func (r *ComponentTemplateReconciler) specApplied(template requests.ComponentTemplate) (bool, error) {
+	hash, err := componentTemplateHash(template)
+	if err != nil {
+		return false, err
+	}
+	return r.instance.Status.LastAppliedHash == hash && r.instance.Status.LastAppliedGeneration == r.generation, nil
+}
+
+// setLastApplied records the component template as the state applied for the current generation of the spec, no
+// change awaits approval anymore. If the template replaced another one, the replaced template is kept for a rollback.
+func (r *ComponentTemplateReconciler) setLastApplied(template requests.ComponentTemplate, replaced *requests.ComponentTemplate) error {
+	hash, err := componentTemplateHash(template)
+	if err != nil {
+		return err
+	}
+	var previous *apiextensionsv1.JSON
+	if replaced != nil {
+		if previous, err = previousAppliedBody(*replaced, r.redaction); err != nil {
+			return err
+		}
+		if previous == nil {
+			r.logger.Info("replaced component template is too large or holds sensitive values, it is not kept for a rollback")
+		}
+	}
+	generation := r.generation
+	revision := helpers.ProvenanceRevision(template.Meta)
+	status := r.instance.Status
+	if replaced == nil && status.LastAppliedHash == hash && status.LastAppliedGeneration == generation && status.LastAppliedGitRevision == revision && status.ExternalEditDetectedAt == nil && status.AwaitingApproval == nil {
+		return nil
+	}
+	return r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		status.LastAppliedHash = hash
+		status.LastAppliedGeneration = generation
+		status.LastAppliedGitRevision = revision
+		status.ExternalEditDetectedAt = nil
+		status.AwaitingApproval = nil
+		if replaced != nil {
+			status.PreviousApplied = previous
+		}
+	})
+}
+
+// componentTemplateHash returns the SHA1 hash of the component template in its JSON form with sorted keys
+// and normalized time values
+func componentTemplateHash(template requests.ComponentTemplate) (string, error) {
+	raw, err := json.Marshal(helpers.NormalizeComponentTemplate(template))
+	if err != nil {
+		return "", err
+	}
+	var normalized interface{}
+	if err := helpers.UnmarshalPreservingNumbers(raw, &normalized); err != nil {
+		return "", err
+	}
+	raw, err = json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	return util.GetSha1Sum(raw)
+}
+
+// completeWrite confirms the written component template on all node pools, reports the change and records the
+// template as applied. A template that is not confirmed yet is not recorded, it is confirmed again once it is in sync.
+func (r *ComponentTemplateReconciler) completeWrite(templateName string, template requests.ComponentTemplate, replaced *requests.ComponentTemplate, confirm bool) (ctrl.Result, string, error) {
+	if confirm {
+		if reason := r.confirmOnNodePools(templateName, template); reason != "" {
+			return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, reason, nil
+		}
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "component template updated in opensearch")
+	r.reportShardChange(replaced, template)
+
+	if err := r.setLastApplied(template, replaced); err != nil {
+		reason := fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	return ctrl.Result{}, "", nil
+}
+
+// completeInSync records the component template that is in sync with OpenSearch as applied and runs the steps
+// following an apply. A write that was not confirmed on all node pools is confirmed again before it is recorded.
+func (r *ComponentTemplateReconciler) completeInSync(templateName string, template requests.ComponentTemplate, live requests.ComponentTemplate) (ctrl.Result, string, error) {
+	applied, err := r.specApplied(template)
+	if err != nil {
+		return ctrl.Result{}, "failed to hash the component template", err
+	}
+	if !applied {
+		if reason := r.confirmOnNodePools(templateName, template); reason != "" {
+			return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, reason, nil
+		}
+	}
+	// A changed provenance alone is not applied, the status keeps the revision the live component template has
+	inSync := template
+	inSync.Meta = live.Meta
+	if err := r.setLastApplied(inSync, nil); err != nil {
+		reason := fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	return r.afterApply(templateName, template)
+}
+
+// afterApply verifies the applied component template, records the simulated index, migrates the write index to a new
+// schema version and the existing indices to its codec once it is verified, if the spec asks for it
+func (r *ComponentTemplateReconciler) afterApply(templateName string, template requests.ComponentTemplate) (ctrl.Result, string, error) {
+	result := ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	if r.instance.Spec.VerifyAfterApply {
+		var reason string
+		var err error
+		if result, reason, err = r.verify(templateName, template); err != nil || reason != "" {
+			return result, reason, err
+		}
+	}
+	if err := r.simulateIndex(template); err != nil {
+		reason := fmt.Sprintf("failed to record the simulated index: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if r.instance.Spec.SchemaMigration != nil {
+		if migrating, result, reason, err := r.migrateSchema(template); migrating {
+			return result, reason, err
+		}
+	}
+	if r.instance.Spec.CodecMigration != nil {
+		return r.migrateCodec(template)
+	}
+	return result, "", nil
+}
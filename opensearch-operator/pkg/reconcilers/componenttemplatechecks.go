@@ -0,0 +1,256 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+const (
+	opensearchUnsupportedCodec  = "OpensearchComponentTemplateUnsupportedCodec"
+	opensearchFieldCountWarning = "OpensearchComponentTemplateFieldCountWarning"
+	opensearchDepthLimit        = "OpensearchComponentTemplateDepthLimit"
+	opensearchInvalidIndexSort  = "OpensearchComponentTemplateInvalidIndexSort"
+	opensearchUnknownSetting    = "OpensearchComponentTemplateUnknownSetting"
+	opensearchStorePreload      = "OpensearchComponentTemplateStorePreload"
+	opensearchIndexBlocks       = "OpensearchComponentTemplateIndexBlocks"
+)
+
+// checkComponentTemplate runs the checks of the translated template of the instance, or of a member of its
+// transaction group, and completes it with the settings derived from the spec. The events of all checks are emitted
+// for the reconciled instance. The replica policy asks OpenSearch for the number of data nodes, as the desired
+// template depends on it; the checks of the nodes and indices of the cluster run in checkBeforeWrite instead.
+func (r *ComponentTemplateReconciler) checkComponentTemplate(member *opsterv1.OpensearchComponentTemplate, template *requests.ComponentTemplate) (string, error) {
+	checks := []func() (string, error){
+		func() (string, error) { return r.checkIndexCodec(member.Spec, template) },
+		func() (string, error) { return r.checkTimeSettings(*template) },
+		func() (string, error) { return r.checkRoutingShards(*template) },
+		func() (string, error) { return r.checkUnknownSettings(member.Spec, *template) },
+		func() (string, error) { return r.checkStorePreload(*template) },
+		func() (string, error) { return r.checkIndexBlocks(member, *template) },
+		func() (string, error) { return r.checkMappingDepth(*template) },
+		func() (string, error) { return r.checkIndexSort(*template) },
+		func() (string, error) { return r.checkTier(member.Spec, template) },
+		func() (string, error) { return r.checkReplicaPolicy(member.Spec, template) },
+		func() (string, error) { return r.checkFieldSecurity(member.Spec) },
+		func() (string, error) { return r.checkTemplatePolicies(*template) },
+	}
+	return runChecks(checks)
+}
+
+// checkBeforeWrite runs the checks that ask OpenSearch about the nodes, indices and settings of the cluster. They
+// only run when the template differs from the one in OpenSearch and is about to be written.
+func (r *ComponentTemplateReconciler) checkBeforeWrite(member *opsterv1.OpensearchComponentTemplate, template requests.ComponentTemplate) (string, error) {
+	checks := []func() (string, error){
+		func() (string, error) { return r.checkAutoCreate(template) },
+		func() (string, error) { return r.checkTierNodes(member.Spec) },
+		func() (string, error) { return r.checkAnalysisPlugins(member.Spec, template) },
+		func() (string, error) { return r.checkAliasNames(template) },
+	}
+	return runChecks(checks)
+}
+
+// runChecks runs the checks in order until one fails
+func runChecks(checks []func() (string, error)) (string, error) {
+	for _, check := range checks {
+		if reason, err := check(); err != nil {
+			return reason, err
+		}
+	}
+	return "", nil
+}
+
+// checkIndexCodec validates the index.codec of the translated template against the version of the cluster.
+// If the spec allows it an unsupported codec is replaced by the default codec, otherwise the reconcile fails.
+func (r *ComponentTemplateReconciler) checkIndexCodec(spec opsterv1.OpensearchComponentTemplateSpec, template *requests.ComponentTemplate) (string, error) {
+	codec, err := helpers.IndexCodec(template.Template.Settings)
+	if err != nil {
+		reason := "failed to parse component template settings"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+
+	clusterVersion := r.cluster.Status.Version
+	if clusterVersion == "" {
+		clusterVersion = r.cluster.Spec.General.Version
+	}
+	unsupported := helpers.CheckIndexCodec(codec, clusterVersion)
+	if unsupported == nil {
+		return "", nil
+	}
+
+	if !spec.ReplaceUnsupportedCodec {
+		reason := unsupported.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchUnsupportedCodec, reason)
+		return reason, unsupported
+	}
+
+	template.Template.Settings, err = helpers.SetIndexCodec(template.Template.Settings, helpers.DefaultIndexCodec)
+	if err != nil {
+		reason := "failed to replace unsupported index codec"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	r.recorder.Event(r.instance, "Warning", opensearchUnsupportedCodec,
+		fmt.Sprintf("%s, using the %s codec instead", unsupported, helpers.DefaultIndexCodec))
+	return "", nil
+}
+
+// checkTimeSettings fails the reconcile if a time valued index setting has a value OpenSearch would reject,
+// e.g. "30 seconds" instead of "30s"
+func (r *ComponentTemplateReconciler) checkTimeSettings(template requests.ComponentTemplate) (string, error) {
+	if err := helpers.ValidateTimeSettings(template.Template.Settings); err != nil {
+		reason := err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidTimeSetting, reason)
+		return reason, err
+	}
+	return "", nil
+}
+
+// checkRoutingShards fails the reconcile if index.number_of_routing_shards is not a multiple of the number of shards,
+// OpenSearch would then fail to create every index matching the template
+func (r *ComponentTemplateReconciler) checkRoutingShards(template requests.ComponentTemplate) (string, error) {
+	if err := helpers.ValidateRoutingShards(template.Template.Settings); err != nil {
+		reason := err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidRoutingShards, reason)
+		return reason, err
+	}
+	return "", nil
+}
+
+// checkUnknownSettings reports the settings of the translated template that are not known index settings, which are
+// usually misspelled. With the Reject policy the reconcile fails.
+func (r *ComponentTemplateReconciler) checkUnknownSettings(spec opsterv1.OpensearchComponentTemplateSpec, template requests.ComponentTemplate) (string, error) {
+	if spec.UnknownSettings == opsterv1.UnknownSettingsIgnore {
+		return "", nil
+	}
+	unknown, err := helpers.UnknownIndexSettings(template.Template.Settings)
+	if err != nil {
+		reason := "failed to parse component template settings"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	if len(unknown) == 0 {
+		return "", nil
+	}
+	keys := make([]string, 0, len(unknown))
+	for _, setting := range unknown {
+		keys = append(keys, setting.String())
+	}
+	reason := fmt.Sprintf("unknown index settings: %s", strings.Join(keys, ", "))
+	r.recorder.Event(r.instance, "Warning", opensearchUnknownSetting, reason)
+	if spec.UnknownSettings == opsterv1.UnknownSettingsReject {
+		return reason, errors.New(reason)
+	}
+	return "", nil
+}
+
+// checkStorePreload warns about index.store.preload entries that are not file extensions or are not preloaded with
+// the store type of the template, the template is applied regardless
+func (r *ComponentTemplateReconciler) checkStorePreload(template requests.ComponentTemplate) (string, error) {
+	warnings, err := helpers.StorePreloadWarnings(template.Template.Settings)
+	if err != nil {
+		reason := "failed to parse component template settings"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	for _, warning := range warnings {
+		r.recorder.Event(r.instance, "Warning", opensearchStorePreload, warning)
+	}
+	return "", nil
+}
+
+// checkIndexBlocks emits an event naming the index.blocks settings the template sets to true, as they block every
+// index created from it. With the RequireAcknowledgment policy the reconcile fails until the blocks are acknowledged
+// with the opensearch.opster.io/acknowledge-index-blocks annotation on the component template setting them.
+func (r *ComponentTemplateReconciler) checkIndexBlocks(member *opsterv1.OpensearchComponentTemplate, template requests.ComponentTemplate) (string, error) {
+	blocks, err := helpers.IndexBlocks(template.Template.Settings)
+	if err != nil {
+		reason := "failed to parse component template settings"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	if len(blocks) == 0 {
+		return "", nil
+	}
+	reason := fmt.Sprintf("component template blocks every index created from it: %s", strings.Join(blocks, ", "))
+	if member.Spec.IndexBlocks == opsterv1.IndexBlocksRequireAcknowledgment && member.Annotations[helpers.AcknowledgeIndexBlocks] != "true" {
+		reason = fmt.Sprintf("%s, set the %s annotation to true to apply it", reason, helpers.AcknowledgeIndexBlocks)
+		r.recorder.Event(r.instance, "Warning", opensearchIndexBlocks, reason)
+		return reason, errors.New(reason)
+	}
+	r.recorder.Event(r.instance, "Warning", opensearchIndexBlocks, reason)
+	return "", nil
+}
+
+// checkMappingDepth fails the reconcile if the mapping of the template nests objects deeper than the
+// index.mapping.depth.limit of the template allows and warns if it comes close to the limit
+func (r *ComponentTemplateReconciler) checkMappingDepth(template requests.ComponentTemplate) (string, error) {
+	depth, err := helpers.MappingDepth(template.Template.Mappings)
+	if err != nil {
+		reason := "failed to parse component template mappings"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	limit, err := helpers.DepthLimit(template.Template.Settings)
+	if err != nil {
+		reason := "failed to get the depth limit of the component template"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+
+	if depth > limit {
+		reason := fmt.Sprintf("mapping has a depth of %d which exceeds the index.mapping.depth.limit of %d", depth, limit)
+		r.recorder.Event(r.instance, "Warning", opensearchDepthLimit, reason)
+		return reason, errors.New(reason)
+	}
+	if float64(depth) >= float64(limit)*helpers.DepthWarningRatio {
+		r.recorder.Event(r.instance, "Warning", opensearchDepthLimit,
+			fmt.Sprintf("mapping has a depth of %d which is close to the index.mapping.depth.limit of %d", depth, limit))
+	}
+	return "", nil
+}
+
+// checkIndexSort fails the reconcile if index.sort.field references fields the mapping of the template does not
+// declare, as OpenSearch would then fail to create indices from the template
+func (r *ComponentTemplateReconciler) checkIndexSort(template requests.ComponentTemplate) (string, error) {
+	unmapped, err := helpers.UnmappedSortFields(template.Template.Settings, template.Template.Mappings)
+	if err != nil {
+		reason := "failed to parse the index sort of the component template"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	if len(unmapped) == 0 {
+		return "", nil
+	}
+	reason := fmt.Sprintf("index.sort.field references fields that are not mapped: %s", strings.Join(unmapped, ", "))
+	r.recorder.Event(r.instance, "Warning", opensearchInvalidIndexSort, reason)
+	return reason, errors.New(reason)
+}
+
+// checkFieldCount warns if the mapping of the template declares nearly as many or more fields than the
+// index.mapping.total_fields.limit of the template allows. Indexing fails once the limit is exceeded.
+func (r *ComponentTemplateReconciler) checkFieldCount(template requests.ComponentTemplate) {
+	count, err := helpers.MappingFieldCount(template.Template.Mappings)
+	if err != nil {
+		r.logger.Error(err, "failed to count the fields of the component template mapping")
+		return
+	}
+	limit, err := helpers.TotalFieldsLimit(template.Template.Settings)
+	if err != nil {
+		r.logger.Error(err, "failed to get the total fields limit of the component template")
+		return
+	}
+
+	if count > limit {
+		r.recorder.Event(r.instance, "Warning", opensearchFieldCountWarning,
+			fmt.Sprintf("mapping declares %d fields which exceeds the index.mapping.total_fields.limit of %d", count, limit))
+	} else if float64(count) >= float64(limit)*helpers.FieldCountWarningRatio {
+		r.recorder.Event(r.instance, "Warning", opensearchFieldCountWarning,
+			fmt.Sprintf("mapping declares %d fields which is close to the index.mapping.total_fields.limit of %d", count, limit))
+	}
+}
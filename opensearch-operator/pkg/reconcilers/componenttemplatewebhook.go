@@ -0,0 +1,109 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// MappingValidationMode selects how the component template webhook handles incompatible mapping type changes
+type MappingValidationMode string
+
+const (
+	// MappingValidationWarn admits incompatible mapping type changes with a warning
+	MappingValidationWarn MappingValidationMode = "warn"
+	// MappingValidationReject rejects incompatible mapping type changes unless the component template has the
+	// opensearch.opster.io/allow-mapping-type-changes annotation set to true
+	MappingValidationReject MappingValidationMode = "reject"
+)
+
+const mappingTypeChangeNote = "indices that already exist keep the previous type, only indices created afterwards use the new one"
+
+// ComponentTemplateValidator compares the mappings of updated component templates against the version currently
+// applied and reports fields whose type changes incompatibly
+type ComponentTemplateValidator struct {
+	mode MappingValidationMode
+}
+
+var _ admission.CustomValidator = &ComponentTemplateValidator{}
+
+func NewComponentTemplateValidator(mode MappingValidationMode) (*ComponentTemplateValidator, error) {
+	switch mode {
+	case MappingValidationWarn, MappingValidationReject:
+		return &ComponentTemplateValidator{mode: mode}, nil
+	default:
+		return nil, fmt.Errorf("invalid mapping validation mode %q, must be %s or %s", mode, MappingValidationWarn, MappingValidationReject)
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-opensearch-opster-io-v1-opensearchcomponenttemplate,mutating=false,failurePolicy=ignore,sideEffects=None,groups=opensearch.opster.io,resources=opensearchcomponenttemplates,verbs=update,versions=v1,name=vopensearchcomponenttemplate.opensearch.opster.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager serves the validator on the webhook server of the manager
+func (v *ComponentTemplateValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&opsterv1.OpensearchComponentTemplate{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate admits all new component templates, there is no applied version to compare them against
+func (v *ComponentTemplateValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate compares the mappings against the ones of the previous spec, if it was applied
+func (v *ComponentTemplateValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	previous, ok := oldObj.(*opsterv1.OpensearchComponentTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected an OpensearchComponentTemplate but got %T", oldObj)
+	}
+	proposed, ok := newObj.(*opsterv1.OpensearchComponentTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected an OpensearchComponentTemplate but got %T", newObj)
+	}
+	// A spec that was never applied did not create any index yet
+	if previous.Status.LastAppliedHash == "" {
+		return nil, nil
+	}
+
+	changes, err := helpers.IncompatibleMappingTypeChanges(previous.Spec.Template.Mappings, proposed.Spec.Template.Mappings)
+	if err != nil || len(changes) == 0 {
+		// Invalid mappings are reported by the reconcile
+		return nil, nil
+	}
+	described := make([]string, 0, len(changes))
+	for _, change := range changes {
+		described = append(described, change.String())
+	}
+
+	if v.mode == MappingValidationWarn || proposed.GetAnnotations()[helpers.AllowMappingTypeChanges] == "true" {
+		warnings := make(admission.Warnings, 0, len(described))
+		for _, change := range described {
+			warnings = append(warnings, fmt.Sprintf("incompatible mapping type change: %s, %s", change, mappingTypeChangeNote))
+		}
+		return warnings, nil
+	}
+
+	path := field.NewPath("spec", "template", "mappings")
+	return nil, apierrors.NewInvalid(
+		opsterv1.GroupVersion.WithKind("OpensearchComponentTemplate").GroupKind(),
+		proposed.Name,
+		field.ErrorList{field.Invalid(path, strings.Join(described, ", "), fmt.Sprintf(
+			"incompatible mapping type changes, %s. Set the %s annotation to true to apply them anyway",
+			mappingTypeChangeNote, helpers.AllowMappingTypeChanges,
+		))},
+	)
+}
+
+// ValidateDelete admits all deletions
+func (v *ComponentTemplateValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
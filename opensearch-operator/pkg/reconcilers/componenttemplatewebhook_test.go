@@ -0,0 +1,121 @@
+package reconcilers
+
+import (
+	"context"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("component template mapping validation webhook", func() {
+	var (
+		mode      MappingValidationMode
+		validator *ComponentTemplateValidator
+		previous  *opsterv1.OpensearchComponentTemplate
+		proposed  *opsterv1.OpensearchComponentTemplate
+	)
+
+	withMappings := func(template *opsterv1.OpensearchComponentTemplate, mappings string) *opsterv1.OpensearchComponentTemplate {
+		template = template.DeepCopy()
+		template.Spec.Template.Mappings = &apiextensionsv1.JSON{Raw: []byte(mappings)}
+		return template
+	}
+
+	BeforeEach(func() {
+		mode = MappingValidationReject
+		previous = &opsterv1.OpensearchComponentTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-template",
+				Namespace: "test-namespace",
+			},
+			Spec: opsterv1.OpensearchComponentTemplateSpec{
+				Template: opsterv1.OpensearchIndexSpec{
+					Mappings: &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"id":{"type":"long"},"count":{"type":"integer"}}}`)},
+				},
+			},
+			Status: opsterv1.OpensearchComponentTemplateStatus{
+				LastAppliedHash: "applied",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		validator, err = NewComponentTemplateValidator(mode)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should not accept unknown modes", func() {
+		_, err := NewComponentTemplateValidator("block")
+		Expect(err).To(HaveOccurred())
+	})
+
+	When("the mapping types change compatibly", func() {
+		BeforeEach(func() {
+			proposed = withMappings(previous, `{"properties":{"id":{"type":"long"},"count":{"type":"long"},"name":{"type":"keyword"}}}`)
+		})
+
+		It("should admit the update without warnings", func() {
+			warnings, err := validator.ValidateUpdate(context.Background(), previous, proposed)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	When("the mapping type of an existing field changes incompatibly", func() {
+		BeforeEach(func() {
+			proposed = withMappings(previous, `{"properties":{"id":{"type":"keyword"},"count":{"type":"integer"}}}`)
+		})
+
+		It("should reject the update and explain existing indices are not affected", func() {
+			_, err := validator.ValidateUpdate(context.Background(), previous, proposed)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("id changes from long to keyword"))
+			Expect(err.Error()).To(ContainSubstring("indices that already exist keep the previous type"))
+		})
+
+		When("the change is allowed by annotation", func() {
+			BeforeEach(func() {
+				proposed.Annotations = map[string]string{helpers.AllowMappingTypeChanges: "true"}
+			})
+
+			It("should admit the update with a warning", func() {
+				warnings, err := validator.ValidateUpdate(context.Background(), previous, proposed)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(warnings).To(ConsistOf(ContainSubstring("id changes from long to keyword")))
+			})
+		})
+
+		When("the validator only warns", func() {
+			BeforeEach(func() {
+				mode = MappingValidationWarn
+			})
+
+			It("should admit the update with a warning", func() {
+				warnings, err := validator.ValidateUpdate(context.Background(), previous, proposed)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(warnings).To(ConsistOf(And(
+					ContainSubstring("id changes from long to keyword"),
+					ContainSubstring("only indices created afterwards use the new one"),
+				)))
+			})
+		})
+
+		When("the previous spec was never applied", func() {
+			BeforeEach(func() {
+				previous.Status.LastAppliedHash = ""
+			})
+
+			It("should admit the update", func() {
+				warnings, err := validator.ValidateUpdate(context.Background(), previous, proposed)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(warnings).To(BeEmpty())
+			})
+		})
+	})
+})
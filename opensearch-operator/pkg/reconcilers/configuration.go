@@ -49,6 +49,12 @@ func NewConfigurationReconciler(
 }
 
 func (r *ConfigurationReconciler) Reconcile() (ctrl.Result, error) {
+	// Allocation awareness is decided by the cluster manager, so every node gets the settings
+	awarenessSettings := helpers.ZoneAwarenessSettings(r.instance.Spec.NodePools)
+	for _, key := range helpers.SortedKeys(awarenessSettings) {
+		r.reconcilerContext.AddConfig(key, awarenessSettings[key])
+	}
+
 	if len(r.instance.Spec.General.AdditionalVolumes) == 0 &&
 		(r.reconcilerContext.OpenSearchConfig == nil || len(r.reconcilerContext.OpenSearchConfig) == 0) {
 		return ctrl.Result{}, nil
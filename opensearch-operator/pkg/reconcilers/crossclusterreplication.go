@@ -0,0 +1,430 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/builders"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchReplicationConnectionExists = "remote cluster connection already exists in OpenSearch; not modifying"
+	opensearchReplicationAliasMismatch    = "OpensearchReplicationAliasMismatch"
+	opensearchInvalidReplication          = "OpensearchInvalidReplication"
+)
+
+type CrossClusterReplicationReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchCrossClusterReplication
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewCrossClusterReplicationReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchCrossClusterReplication,
+	opts ...ReconcilerOption,
+) *CrossClusterReplicationReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &CrossClusterReplicationReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "crossclusterreplication"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "crossclusterreplication"),
+	}
+}
+
+func (r *CrossClusterReplicationReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var alias string
+	var rules []opsterv1.AutoFollowRuleStatus
+	var indices []opsterv1.ReplicatedIndexStatus
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchCrossClusterReplication)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchCrossClusterReplicationError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchCrossClusterReplicationPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchCrossClusterReplicationDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchCrossClusterReplicationCreated
+				instance.Status.ConnectionAlias = alias
+				instance.Status.AutoFollowRules = rules
+				instance.Status.Indices = indices
+			}
+			if reason == opensearchReplicationConnectionExists {
+				instance.Status.State = opsterv1.OpensearchCrossClusterReplicationIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a replication refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchCrossClusterReplication)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	alias = r.instance.Name
+	if r.instance.Spec.ConnectionAlias != "" {
+		alias = r.instance.Spec.ConnectionAlias
+	}
+
+	settings, err := services.GetFlatClusterSettingsMap(r.ctx, r.osClient)
+	if err != nil {
+		reason = "failed to get cluster settings from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	// Check connection state to make sure we don't touch preexisting connections
+	if r.instance.Status.ExistingConnection == nil {
+		_, exists := helpers.RemoteClusterSeeds(settings, alias)
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchCrossClusterReplication)
+				instance.Status.ExistingConnection = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If connection is existing do nothing
+	if *r.instance.Status.ExistingConnection {
+		reason = opensearchReplicationConnectionExists
+		return
+	}
+
+	// the connection alias is immutable, so check the old alias (r.instance.Status.ConnectionAlias) against the new
+	if r.instance.Status.ConnectionAlias != "" && alias != r.instance.Status.ConnectionAlias {
+		reason = "cannot change the connection alias"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchReplicationAliasMismatch, reason)
+		return
+	}
+
+	err = helpers.ValidateReplication(r.instance.Spec)
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidReplication, reason)
+		return
+	}
+
+	// A leader cluster managed by the operator is reached through the transport port of its service
+	seeds := r.instance.Spec.Seeds
+	if r.instance.Spec.LeaderCluster != nil {
+		var leader *opsterv1.OpenSearchCluster
+		leader, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+			Name:      r.instance.Spec.LeaderCluster.Name,
+			Namespace: r.instance.Namespace,
+		})
+		if err != nil {
+			reason = "error fetching leader cluster"
+			r.logger.Error(err, "failed to fetch leader cluster")
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if leader == nil {
+			reason = "waiting for leader cluster to exist"
+			r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+			result = ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: 10 * time.Second,
+			}
+			return
+		}
+		seeds = []string{builders.TransportAddressForCluster(leader)}
+	}
+
+	existingSeeds, _ := helpers.RemoteClusterSeeds(settings, alias)
+	if !reflect.DeepEqual(existingSeeds, seeds) {
+		err = services.PutFlatClusterSettings(r.ctx, r.osClient, responses.ClusterSettingsResponse{
+			Persistent: map[string]interface{}{helpers.RemoteClusterSeedsSetting(alias): seeds},
+		})
+		if err != nil {
+			reason = "failed to update remote cluster connection with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "remote cluster connection updated in opensearch")
+	}
+
+	existingRules, err := services.GetAutoFollowRules(r.ctx, r.osClient)
+	if err != nil {
+		reason = "failed to get auto-follow rules from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	create, remove := helpers.AutoFollowRuleChanges(r.instance.Spec.AutoFollowRules, existingRules, r.instance.Status.AutoFollowRules)
+	for _, name := range remove {
+		err = services.DeleteAutoFollowRule(r.ctx, r.osClient, alias, name)
+		if err != nil {
+			reason = "failed to delete auto-follow rule with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, fmt.Sprintf("auto-follow rule %s deleted in opensearch", name))
+	}
+	for _, rule := range create {
+		request := requests.AutoFollowRule{
+			LeaderAlias: alias,
+			Name:        rule.Name,
+			Pattern:     rule.Pattern,
+		}
+		if roles := r.instance.Spec.UseRoles; roles != nil {
+			request.UseRoles = &requests.ReplicationRoles{
+				LeaderClusterRole:   roles.LeaderClusterRole,
+				FollowerClusterRole: roles.FollowerClusterRole,
+			}
+		}
+		err = services.CreateAutoFollowRule(r.ctx, r.osClient, request)
+		if err != nil {
+			reason = "failed to create auto-follow rule with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, fmt.Sprintf("auto-follow rule %s created in opensearch", rule.Name))
+	}
+
+	failedIndices := map[string][]string{}
+	for _, rule := range existingRules {
+		failedIndices[rule.Name] = rule.FailedIndices
+	}
+	recreated := map[string]bool{}
+	for _, rule := range create {
+		recreated[rule.Name] = true
+	}
+	for _, rule := range r.instance.Spec.AutoFollowRules {
+		ruleStatus := opsterv1.AutoFollowRuleStatus{Name: rule.Name, Pattern: rule.Pattern}
+		if !recreated[rule.Name] {
+			ruleStatus.FailedIndices = failedIndices[rule.Name]
+		}
+		rules = append(rules, ruleStatus)
+	}
+
+	// Report the state of the replicated indices, failing to fetch it does not fail the reconciliation
+	indices = r.replicatedIndices(alias)
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+// replicatedIndices returns the state of the indices replicated from the connection with the passed alias
+func (r *CrossClusterReplicationReconciler) replicatedIndices(alias string) []opsterv1.ReplicatedIndexStatus {
+	followerIndices, err := services.GetFollowerIndices(r.ctx, r.osClient)
+	if err != nil {
+		r.logger.Error(err, "failed to get the follower indices from OpenSearch API")
+		return nil
+	}
+	var indices []opsterv1.ReplicatedIndexStatus
+	for _, index := range followerIndices {
+		status, err := services.GetReplicationStatus(r.ctx, r.osClient, index)
+		if err != nil {
+			r.logger.Error(err, fmt.Sprintf("failed to get the replication status of index %s from OpenSearch API", index))
+			continue
+		}
+		if status.LeaderAlias != alias {
+			continue
+		}
+		if status.FollowerIndex == "" {
+			status.FollowerIndex = index
+		}
+		indices = append(indices, helpers.ReplicatedIndexStatus(status))
+	}
+	return indices
+}
+
+func (r *CrossClusterReplicationReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingConnection == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingConnection {
+		r.logger.Info("remote cluster connection was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	alias := r.instance.Status.ConnectionAlias
+	if alias == "" {
+		alias = r.instance.Name
+		if r.instance.Spec.ConnectionAlias != "" {
+			alias = r.instance.Spec.ConnectionAlias
+		}
+	}
+
+	for _, rule := range r.instance.Status.AutoFollowRules {
+		if err := services.DeleteAutoFollowRule(r.ctx, r.osClient, alias, rule.Name); err != nil {
+			return err
+		}
+	}
+
+	// Indices keep following the leader after their rule is deleted, stopping the replication turns them into
+	// regular indices before the connection goes away
+	followerIndices, err := services.GetFollowerIndices(r.ctx, r.osClient)
+	if err != nil {
+		return err
+	}
+	for _, index := range followerIndices {
+		status, err := services.GetReplicationStatus(r.ctx, r.osClient, index)
+		if err != nil {
+			return err
+		}
+		if status.LeaderAlias != alias {
+			continue
+		}
+		if err := services.StopReplication(r.ctx, r.osClient, index); err != nil {
+			return err
+		}
+	}
+
+	return services.PutFlatClusterSettings(r.ctx, r.osClient, responses.ClusterSettingsResponse{
+		Persistent: map[string]interface{}{helpers.RemoteClusterSeedsSetting(alias): nil},
+	})
+}
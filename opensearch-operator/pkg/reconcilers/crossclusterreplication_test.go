@@ -0,0 +1,582 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("crossclusterreplication reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *CrossClusterReplicationReconciler
+		instance   *opsterv1.OpensearchCrossClusterReplication
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster        *opsterv1.OpenSearchCluster
+		clusterUrl     string
+		settingsUrl    string
+		replicationUrl string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchCrossClusterReplication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-replication",
+				Namespace: "test-replication",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchCrossClusterReplicationSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				ConnectionAlias: "leader",
+				Seeds:           []string{"leader.example.com:9300"},
+				AutoFollowRules: []opsterv1.AutoFollowRule{{Name: "logs", Pattern: "logs-*"}},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-replication",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		settingsUrl = fmt.Sprintf("%s_cluster/settings?flat_settings=true", clusterUrl)
+		replicationUrl = fmt.Sprintf("%s_plugins/_replication", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &CrossClusterReplicationReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a replication refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(cluster.Name, cluster.Namespace).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+			})
+
+			When("a connection with the alias exists", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{"cluster.remote.leader.seeds":["other:9300"]},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the connection exists", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+				})
+			})
+
+			When("the connection does not exist", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the connection does not exist", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is false"}))
+				})
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingConnection = pointer.Bool(true)
+				transport.RegisterResponder(
+					http.MethodGet,
+					settingsUrl,
+					httpmock.NewStringResponder(200, `{"persistent":{"cluster.remote.leader.seeds":["other:9300"]},"transient":{}}`).Once(failMessage),
+				)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingConnection = pointer.Bool(false)
+			})
+
+			When("replication is in sync", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{"cluster.remote.leader.seeds":["leader.example.com:9300"]},"transient":{}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/autofollow_stats",
+						httpmock.NewStringResponder(200, `{"num_success_start_replication":2,"autofollow_stats":[
+							{"name":"logs","pattern":"logs-*","num_success_start_replication":2,"failed_indices":["logs-broken"]}
+						]}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/follower_stats",
+						httpmock.NewStringResponder(200, `{"num_syncing_indices":2,"index_stats":{"logs-2":{},"logs-1":{}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/logs-1/_status",
+						httpmock.NewStringResponder(200, `{"status":"SYNCING","reason":"User initiated","leader_alias":"leader",
+							"leader_index":"logs-1","follower_index":"logs-1",
+							"syncing_details":{"leader_checkpoint":25,"follower_checkpoint":19,"seq_no":0}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/logs-2/_status",
+						httpmock.NewStringResponder(200, `{"status":"SYNCING","leader_alias":"other","leader_index":"logs-2","follower_index":"logs-2"}`).Once(failMessage),
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+						f(obj)
+						return nil
+					})
+				})
+
+				It("should only report the state of the replication", func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchCrossClusterReplicationCreated))
+					Expect(instance.Status.ConnectionAlias).To(Equal("leader"))
+					Expect(instance.Status.AutoFollowRules).To(Equal([]opsterv1.AutoFollowRuleStatus{
+						{Name: "logs", Pattern: "logs-*", FailedIndices: []string{"logs-broken"}},
+					}))
+					Expect(instance.Status.Indices).To(Equal([]opsterv1.ReplicatedIndexStatus{
+						{FollowerIndex: "logs-1", LeaderIndex: "logs-1", Status: "SYNCING", Reason: "User initiated", Lag: 6},
+					}))
+				})
+			})
+
+			When("the leader cluster is managed by the operator and the rules changed", func() {
+				var settingsBody string
+				var ruleBodies []string
+				var deletedBodies []string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(4)
+					ruleBodies = nil
+					deletedBodies = nil
+					instance.Spec.Seeds = nil
+					instance.Spec.LeaderCluster = &corev1.LocalObjectReference{Name: "leader-cluster"}
+					instance.Spec.AutoFollowRules = []opsterv1.AutoFollowRule{{Name: "logs", Pattern: "logs-v2-*"}}
+					instance.Spec.UseRoles = &opsterv1.ReplicationRoles{LeaderClusterRole: "leader_role", FollowerClusterRole: "follower_role"}
+					instance.Status.AutoFollowRules = []opsterv1.AutoFollowRuleStatus{{Name: "logs", Pattern: "logs-*"}, {Name: "metrics", Pattern: "metrics-*"}}
+					leader := opsterv1.OpenSearchCluster{
+						ObjectMeta: metav1.ObjectMeta{Name: "leader-cluster", Namespace: "test-replication"},
+						Spec:       opsterv1.ClusterSpec{General: opsterv1.GeneralConfig{ServiceName: "leader-cluster"}},
+					}
+					mockClient.EXPECT().GetOpenSearchCluster("leader-cluster", instance.Namespace).Return(leader, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						settingsUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							settingsBody = string(raw)
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/autofollow_stats",
+						httpmock.NewStringResponder(200, `{"autofollow_stats":[
+							{"name":"logs","pattern":"logs-*","failed_indices":[]},
+							{"name":"metrics","pattern":"metrics-*","failed_indices":[]}
+						]}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						replicationUrl+"/_autofollow",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							deletedBodies = append(deletedBodies, string(raw))
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						replicationUrl+"/_autofollow",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							ruleBodies = append(ruleBodies, string(raw))
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/follower_stats",
+						httpmock.NewStringResponder(200, `{"index_stats":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should connect to the leader service and replace the rules", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Normal %s remote cluster connection updated in opensearch", opensearchAPIUpdated),
+						fmt.Sprintf("Normal %s auto-follow rule logs deleted in opensearch", opensearchAPIUpdated),
+						fmt.Sprintf("Normal %s auto-follow rule metrics deleted in opensearch", opensearchAPIUpdated),
+						fmt.Sprintf("Normal %s auto-follow rule logs created in opensearch", opensearchAPIUpdated),
+					}))
+					Expect(settingsBody).To(MatchJSON(`{"persistent":{"cluster.remote.leader.seeds":["leader-cluster.test-replication.svc.cluster.local:9300"]}}`))
+					Expect(deletedBodies).To(HaveLen(2))
+					Expect(deletedBodies[0]).To(MatchJSON(`{"leader_alias":"leader","name":"logs"}`))
+					Expect(deletedBodies[1]).To(MatchJSON(`{"leader_alias":"leader","name":"metrics"}`))
+					Expect(ruleBodies).To(HaveLen(1))
+					Expect(ruleBodies[0]).To(MatchJSON(`{
+						"leader_alias": "leader",
+						"name": "logs",
+						"pattern": "logs-v2-*",
+						"use_roles": {"leader_cluster_role": "leader_role", "follower_cluster_role": "follower_role"}
+					}`))
+				})
+			})
+
+			When("the leader cluster does not exist", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Seeds = nil
+					instance.Spec.LeaderCluster = &corev1.LocalObjectReference{Name: "leader-cluster"}
+					mockClient.EXPECT().GetOpenSearchCluster("leader-cluster", instance.Namespace).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should wait for the leader cluster", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						result, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.Requeue).To(BeTrue())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s waiting for leader cluster to exist", opensearchPending)}))
+				})
+			})
+
+			When("the spec is invalid", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Seeds = nil
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should fail without changing OpenSearch", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf(
+						"Warning %s invalid replication: either leaderCluster or seeds has to be set", opensearchInvalidReplication,
+					)}))
+				})
+			})
+
+			When("the connection alias has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.ConnectionAlias = "old-leader"
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the connection alias", opensearchReplicationAliasMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingConnection = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingConnection = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			When("cluster exists", func() {
+				var settingsBody string
+
+				BeforeEach(func() {
+					instance.Status.ConnectionAlias = "leader"
+					instance.Status.AutoFollowRules = []opsterv1.AutoFollowRuleStatus{{Name: "logs", Pattern: "logs-*"}}
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						replicationUrl+"/_autofollow",
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/follower_stats",
+						httpmock.NewStringResponder(200, `{"index_stats":{"logs-1":{},"other-1":{}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/logs-1/_status",
+						httpmock.NewStringResponder(200, `{"status":"SYNCING","leader_alias":"leader","leader_index":"logs-1","follower_index":"logs-1"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						replicationUrl+"/other-1/_status",
+						httpmock.NewStringResponder(200, `{"status":"SYNCING","leader_alias":"other","leader_index":"other-1","follower_index":"other-1"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						replicationUrl+"/logs-1/_stop",
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						settingsUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							settingsBody = string(raw)
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+				})
+
+				It("should delete the rules, stop the replication and remove the connection", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					Expect(settingsBody).To(MatchJSON(`{"persistent":{"cluster.remote.leader.seeds":null}}`))
+				})
+			})
+		})
+	})
+})
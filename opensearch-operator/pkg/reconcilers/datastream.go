@@ -0,0 +1,322 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchDataStreamExists          = "data stream already exists in OpenSearch; not modifying"
+	opensearchDataStreamNameMismatch    = "OpensearchDataStreamNameMismatch"
+	opensearchInvalidDataStream         = "OpensearchInvalidDataStream"
+	opensearchDataStreamMissingTemplate = "OpensearchDataStreamMissingTemplate"
+)
+
+type DataStreamReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchDataStream
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewDataStreamReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchDataStream,
+	opts ...ReconcilerOption,
+) *DataStreamReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &DataStreamReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "datastream"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "datastream"),
+	}
+}
+
+func (r *DataStreamReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var dataStreamName string
+	var templateName string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchDataStream)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchDataStreamError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchDataStreamPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchDataStreamDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchDataStreamCreated
+				instance.Status.DataStreamName = dataStreamName
+				instance.Status.IndexTemplate = templateName
+			}
+			if reason == opensearchDataStreamExists {
+				instance.Status.State = opsterv1.OpensearchDataStreamIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a data stream refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchDataStream)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	dataStreamName = r.instance.Name
+	if r.instance.Spec.Name != "" {
+		dataStreamName = r.instance.Spec.Name
+	}
+
+	if err = helpers.ValidateDataStreamName(dataStreamName); err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidDataStream, reason)
+		return
+	}
+
+	// Check data stream state to make sure we don't touch preexisting data streams
+	if r.instance.Status.ExistingDataStream == nil {
+		var exists bool
+		exists, err = services.DataStreamExists(r.ctx, r.osClient, dataStreamName)
+		if err != nil {
+			reason = "failed to get data stream status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchDataStream)
+				instance.Status.ExistingDataStream = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If data stream is existing do nothing
+	if *r.instance.Status.ExistingDataStream {
+		reason = opensearchDataStreamExists
+		return
+	}
+
+	// the data stream name is immutable, so check the old name (r.instance.Status.DataStreamName) against the new
+	if r.instance.Status.DataStreamName != "" && dataStreamName != r.instance.Status.DataStreamName {
+		reason = "cannot change the data stream name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchDataStreamNameMismatch, reason)
+		return
+	}
+
+	templateName = r.instance.Status.IndexTemplate
+
+	exists, err := services.DataStreamExists(r.ctx, r.osClient, dataStreamName)
+	if err != nil {
+		reason = "failed to get data stream status from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if exists {
+		r.logger.V(1).Info(fmt.Sprintf("data stream %s exists", r.instance.Name))
+		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		return
+	}
+
+	// OpenSearch creates the backing indices of the data stream from the index template matching its name, which has
+	// to enable data streams
+	templates, err := services.IndexTemplates(r.ctx, r.osClient)
+	if err != nil {
+		reason = "failed to get index templates from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+	templateName, found := helpers.MatchingIndexTemplate(dataStreamName, templates)
+	if !found {
+		reason = fmt.Sprintf("no index template matches the data stream %s", dataStreamName)
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchDataStreamMissingTemplate, reason)
+		return
+	}
+	if templates[templateName].DataStream == nil {
+		reason = fmt.Sprintf("index template %s matching the data stream %s has no data_stream section", templateName, dataStreamName)
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchDataStreamMissingTemplate, reason)
+		return
+	}
+
+	err = services.CreateDataStream(r.ctx, r.osClient, dataStreamName)
+	if err != nil {
+		reason = "failed to create data stream with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, fmt.Sprintf("data stream created in opensearch from index template %s", templateName))
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+func (r *DataStreamReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingDataStream == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingDataStream {
+		r.logger.Info("data stream was pre-existing; not deleting")
+		return nil
+	}
+
+	if r.instance.Spec.DeletionPolicy != opsterv1.IndexDeletionPolicyDelete {
+		r.logger.Info("deletion policy of the data stream is not Delete; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	dataStreamName := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		dataStreamName = r.instance.Spec.Name
+	}
+
+	return services.DeleteDataStream(r.ctx, r.osClient, dataStreamName)
+}
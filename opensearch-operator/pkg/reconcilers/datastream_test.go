@@ -0,0 +1,423 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("datastream reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *DataStreamReconciler
+		instance   *opsterv1.OpensearchDataStream
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		streamUrl  string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchDataStream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-datastream",
+				Namespace: "test-datastream",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchDataStreamSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Name: "logs-nginx",
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-datastream",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		streamUrl = fmt.Sprintf("%s_data_stream/logs-nginx", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &DataStreamReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a data stream refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("the name is not a valid data stream name", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				instance.Spec.Name = "logs-*"
+			})
+
+			It("should fail", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).To(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{fmt.Sprintf(`Warning %s data stream name "logs-*" must not contain *`, opensearchInvalidDataStream)}))
+			})
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					streamUrl,
+					httpmock.NewStringResponder(200, `{"data_streams":[{"name":"logs-nginx"}]}`).Once(failMessage),
+				)
+			})
+
+			It("should record that the data stream exists", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDataStream = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDataStream = pointer.Bool(false)
+			})
+
+			When("data stream exists in opensearch", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						streamUrl,
+						httpmock.NewStringResponder(200, `{"data_streams":[{"name":"logs-nginx"}]}`).Once(failMessage),
+					)
+				})
+
+				It("should do nothing", func() {
+					result, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				})
+			})
+
+			When("data stream does not exist in opensearch", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						streamUrl,
+						httpmock.NewStringResponder(404, "{}").Once(failMessage),
+					)
+				})
+
+				When("an index template with a data stream section matches", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							clusterUrl+"_index_template",
+							httpmock.NewStringResponder(200, `{"index_templates":[
+								{"name":"logs","index_template":{"index_patterns":["logs-*"],"priority":100,"data_stream":{"timestamp_field":{"name":"@timestamp"}}}},
+								{"name":"other","index_template":{"index_patterns":["other-*"],"priority":200}}
+							]}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							streamUrl,
+							httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+						)
+					})
+
+					It("should create the data stream", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s data stream created in opensearch from index template logs", opensearchAPIUpdated)}))
+					})
+				})
+
+				When("the matching index template has no data stream section", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							clusterUrl+"_index_template",
+							httpmock.NewStringResponder(200, `{"index_templates":[
+								{"name":"logs","index_template":{"index_patterns":["logs-*"],"priority":100,"data_stream":{"timestamp_field":{"name":"@timestamp"}}}},
+								{"name":"logs-nginx","index_template":{"index_patterns":["logs-nginx*"],"priority":200}}
+							]}`).Once(failMessage),
+						)
+					})
+
+					It("should fail without creating the data stream", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{fmt.Sprintf(
+							"Warning %s index template logs-nginx matching the data stream logs-nginx has no data_stream section",
+							opensearchDataStreamMissingTemplate,
+						)}))
+					})
+				})
+
+				When("no index template matches", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							clusterUrl+"_index_template",
+							httpmock.NewStringResponder(200, `{"index_templates":[]}`).Once(failMessage),
+						)
+					})
+
+					It("should fail without creating the data stream", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s no index template matches the data stream logs-nginx", opensearchDataStreamMissingTemplate)}))
+					})
+				})
+			})
+
+			When("the name of the data stream has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.DataStreamName = "logs-nginx"
+					instance.Spec.Name = "logs-apache"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the data stream name", opensearchDataStreamNameMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDataStream = pointer.Bool(true)
+				instance.Spec.DeletionPolicy = opsterv1.IndexDeletionPolicyDelete
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDataStream = pointer.Bool(false)
+			})
+
+			When("the deletion policy is Retain", func() {
+				BeforeEach(func() {
+					instance.Spec.DeletionPolicy = opsterv1.IndexDeletionPolicyRetain
+				})
+				It("should keep the data stream", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(BeZero())
+				})
+			})
+
+			When("the deletion policy is Delete", func() {
+				BeforeEach(func() {
+					instance.Spec.DeletionPolicy = opsterv1.IndexDeletionPolicyDelete
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						streamUrl,
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+				})
+
+				It("should delete the data stream with its backing indices", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+				})
+			})
+		})
+	})
+})
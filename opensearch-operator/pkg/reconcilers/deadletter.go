@@ -0,0 +1,267 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// deadLetterRetryInterval is the interval a failed write of the dead-letter ConfigMap is retried in
+const deadLetterRetryInterval = time.Minute
+
+// deadLetters is the managed object by terminal failure state matrix, 1 for every object stuck in a failure state
+var deadLetters = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "opensearch_operator_dead_letter",
+	Help: "Managed objects stuck in a terminal failure state, 1 for every such object.",
+}, []string{"kind", "namespace", "name", "cluster", "state"})
+
+func init() {
+	metrics.Registry.MustRegister(deadLetters)
+}
+
+// DeadLetter is a managed object that stayed in a failure state for longer than the dead-letter threshold
+type DeadLetter struct {
+	Kind      string      `json:"kind"`
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	Cluster   string      `json:"cluster"`
+	State     string      `json:"state"`
+	Reason    string      `json:"reason"`
+	Since     metav1.Time `json:"since"`
+}
+
+// deadLetterKey is the key of a dead letter in the dead-letter ConfigMap
+func deadLetterKey(kind string, namespace string, name string) string {
+	return fmt.Sprintf("%s.%s.%s", strings.ToLower(kind), namespace, name)
+}
+
+// WithDeadLetters reports reconciled objects that stay in a failure state for longer than after as dead letters in a
+// metric and in the report, a nil report only exports the metric. An after of 0 reports none
+func WithDeadLetters(after time.Duration, report *DeadLetterReport) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.deadLetterAfter = after
+		o.deadLetters = report
+	}
+}
+
+// ParseDeadLetterConfigMap parses the namespace/name of the dead-letter ConfigMap, an empty value names none
+func ParseDeadLetterConfigMap(value string) (types.NamespacedName, error) {
+	if value == "" {
+		return types.NamespacedName{}, nil
+	}
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid dead-letter ConfigMap %q, must be namespace/name", value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// recordDeadLetter exports the dead letter as metric and adds it to the report
+func recordDeadLetter(report *DeadLetterReport, letter DeadLetter) {
+	deadLetters.DeletePartialMatch(prometheus.Labels{"kind": letter.Kind, "namespace": letter.Namespace, "name": letter.Name})
+	deadLetters.WithLabelValues(letter.Kind, letter.Namespace, letter.Name, letter.Cluster, letter.State).Set(1)
+	report.Report(letter)
+}
+
+// forgetDeadLetter removes an object that left its failure state, or was deleted, from the metric and the report
+func forgetDeadLetter(report *DeadLetterReport, kind string, namespace string, name string) {
+	deadLetters.DeletePartialMatch(prometheus.Labels{"kind": kind, "namespace": namespace, "name": name})
+	report.Forget(kind, namespace, name)
+}
+
+// reportDeadLetter records the component template as dead letter once its failure state lasted for longer than the
+// dead-letter threshold, and forgets it once it left the state
+func (r *ComponentTemplateReconciler) reportDeadLetter(state string, reason string, clusters []opsterv1.ComponentTemplateClusterStatus) {
+	if r.deadLetterAfter <= 0 {
+		return
+	}
+	failed := state == string(opsterv1.OpensearchComponentTemplateError) || state == string(opsterv1.OpensearchComponentTemplateForbidden)
+	if !failed || len(clusters) == 0 || time.Since(clusters[0].LastTransitionTime.Time) < r.deadLetterAfter {
+		forgetDeadLetter(r.deadLetters, "OpensearchComponentTemplate", r.instance.Namespace, r.instance.Name)
+		return
+	}
+	recordDeadLetter(r.deadLetters, DeadLetter{
+		Kind:      "OpensearchComponentTemplate",
+		Namespace: r.instance.Namespace,
+		Name:      r.instance.Name,
+		Cluster:   clusters[0].Name,
+		State:     state,
+		Reason:    reason,
+		Since:     clusters[0].LastTransitionTime,
+	})
+}
+
+// DeadLetterReport lists the dead letters of all reconciled objects in a ConfigMap, one key per object. Reconciles
+// only update the dead letters in memory, the ConfigMap is written in the background and a failed write is retried
+// without blocking or failing a reconcile. The dead letters already in the ConfigMap are kept when the operator
+// starts, until their objects are reconciled again.
+type DeadLetterReport struct {
+	configMap types.NamespacedName
+	newClient func(ctx context.Context) k8s.K8sClient
+	trigger   chan struct{}
+
+	mu sync.Mutex
+	// letters holds nil for objects forgotten before the ConfigMap was loaded, so they are not loaded again
+	letters map[string]*DeadLetter
+	loaded  bool
+}
+
+func NewDeadLetterReport(c client.Client, configMap types.NamespacedName) *DeadLetterReport {
+	return &DeadLetterReport{
+		configMap: configMap,
+		newClient: func(ctx context.Context) k8s.K8sClient { return k8s.NewK8sClient(c, ctx) },
+		trigger:   make(chan struct{}, 1),
+		letters:   map[string]*DeadLetter{},
+	}
+}
+
+// Report records the dead letter to be written
+func (p *DeadLetterReport) Report(letter DeadLetter) {
+	if p == nil {
+		return
+	}
+	key := deadLetterKey(letter.Kind, letter.Namespace, letter.Name)
+	p.mu.Lock()
+	if previous := p.letters[key]; previous != nil && *previous == letter {
+		p.mu.Unlock()
+		return
+	}
+	p.letters[key] = &letter
+	p.mu.Unlock()
+	p.schedule()
+}
+
+// Forget removes the dead letter of an object
+func (p *DeadLetterReport) Forget(kind string, namespace string, name string) {
+	if p == nil {
+		return
+	}
+	key := deadLetterKey(kind, namespace, name)
+	p.mu.Lock()
+	previous, ok := p.letters[key]
+	switch {
+	case !p.loaded:
+		p.letters[key] = nil
+	case ok:
+		delete(p.letters, key)
+	}
+	p.mu.Unlock()
+	if previous != nil {
+		p.schedule()
+	}
+}
+
+// schedule requests a write without waiting for it, a write already requested covers the new dead letter as well
+func (p *DeadLetterReport) schedule() {
+	select {
+	case p.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start writes the dead letters whenever they change until the context is done, it implements manager.Runnable
+func (p *DeadLetterReport) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("dead-letter-report")
+	k8sClient := p.newClient(ctx)
+	retry := time.NewTicker(deadLetterRetryInterval)
+	defer retry.Stop()
+	failed := false
+	// The dead letters the ConfigMap held are loaded and written once right away
+	p.schedule()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.trigger:
+		case <-retry.C:
+			if !failed {
+				continue
+			}
+		}
+		err := p.flush(k8sClient)
+		failed = err != nil
+		if failed {
+			logger.Error(err, "failed to update the dead-letter ConfigMap, retrying")
+		}
+	}
+}
+
+// flush writes the current dead letters to the ConfigMap, after loading the ones it held when the operator started
+func (p *DeadLetterReport) flush(k8sClient k8s.K8sClient) error {
+	p.mu.Lock()
+	loaded := p.loaded
+	p.mu.Unlock()
+	if !loaded {
+		if err := p.load(k8sClient); err != nil {
+			return err
+		}
+	}
+	return p.write(k8sClient)
+}
+
+// load adds the dead letters of the ConfigMap that were neither reported nor forgotten since the operator started
+func (p *DeadLetterReport) load(k8sClient k8s.K8sClient) error {
+	cm, err := k8sClient.GetConfigMap(p.configMap.Name, p.configMap.Namespace)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, value := range cm.Data {
+		if _, ok := p.letters[key]; ok {
+			continue
+		}
+		letter := DeadLetter{}
+		if err := json.Unmarshal([]byte(value), &letter); err != nil {
+			continue
+		}
+		p.letters[key] = &letter
+	}
+	for key, letter := range p.letters {
+		if letter == nil {
+			delete(p.letters, key)
+		}
+	}
+	p.loaded = true
+	return nil
+}
+
+// write replaces the dead letters in the ConfigMap with the current ones
+func (p *DeadLetterReport) write(k8sClient k8s.K8sClient) error {
+	data := map[string]string{}
+	p.mu.Lock()
+	for key, letter := range p.letters {
+		raw, err := json.Marshal(letter)
+		if err != nil {
+			p.mu.Unlock()
+			return err
+		}
+		data[key] = string(raw)
+	}
+	p.mu.Unlock()
+
+	cm := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.configMap.Name,
+			Namespace: p.configMap.Namespace,
+		},
+		Data: data,
+	}
+	_, err := k8sClient.ReconcileResource(&cm, reconciler.StatePresent)
+	return err
+}
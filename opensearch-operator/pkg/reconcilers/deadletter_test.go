@@ -0,0 +1,208 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	k8sclient "github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("dead letters", func() {
+	var (
+		mockClient *k8s.MockK8sClient
+		report     *DeadLetterReport
+		existing   map[string]string
+		written    chan map[string]DeadLetter
+		cancel     context.CancelFunc
+	)
+
+	letter := func(name string) DeadLetter {
+		return DeadLetter{
+			Kind:      "OpensearchComponentTemplate",
+			Namespace: "test-deadletter",
+			Name:      name,
+			Cluster:   "test-cluster",
+			State:     string(opsterv1.OpensearchComponentTemplateError),
+			Reason:    "failed to apply component template",
+			Since:     metav1.NewTime(time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)),
+		}
+	}
+
+	marshal := func(letter DeadLetter) string {
+		raw, err := json.Marshal(letter)
+		Expect(err).ToNot(HaveOccurred())
+		return string(raw)
+	}
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		existing = nil
+		written = make(chan map[string]DeadLetter, 10)
+		report = NewDeadLetterReport(nil, types.NamespacedName{Namespace: "operator", Name: "dead-letters"})
+		report.newClient = func(context.Context) k8sclient.K8sClient { return mockClient }
+		mockClient.EXPECT().GetConfigMap("dead-letters", "operator").RunAndReturn(func(string, string) (corev1.ConfigMap, error) {
+			return corev1.ConfigMap{Data: existing}, nil
+		}).Maybe()
+		mockClient.EXPECT().ReconcileResource(mock.Anything, reconciler.StatePresent).
+			RunAndReturn(func(obj runtime.Object, _ reconciler.DesiredState) (*ctrl.Result, error) {
+				cm := obj.(*corev1.ConfigMap)
+				Expect(cm.Namespace).To(Equal("operator"))
+				Expect(cm.Name).To(Equal("dead-letters"))
+				letters := map[string]DeadLetter{}
+				for key, value := range cm.Data {
+					letter := DeadLetter{}
+					Expect(json.Unmarshal([]byte(value), &letter)).To(Succeed())
+					letter.Since = metav1.NewTime(letter.Since.UTC())
+					letters[key] = letter
+				}
+				written <- letters
+				return &ctrl.Result{}, nil
+			}).Maybe()
+	})
+
+	start := func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go func(report *DeadLetterReport) {
+			defer GinkgoRecover()
+			Expect(report.Start(ctx)).To(Succeed())
+		}(report)
+	}
+
+	AfterEach(func() {
+		if cancel != nil {
+			cancel()
+			cancel = nil
+		}
+	})
+
+	When("no report is configured", func() {
+		It("should ignore the dead letters", func() {
+			var nilReport *DeadLetterReport
+			nilReport.Report(letter("test-template"))
+			nilReport.Forget("OpensearchComponentTemplate", "test-deadletter", "test-template")
+		})
+	})
+
+	When("the operator starts", func() {
+		BeforeEach(func() {
+			existing = map[string]string{
+				deadLetterKey("OpensearchComponentTemplate", "test-deadletter", "kept"):      marshal(letter("kept")),
+				deadLetterKey("OpensearchComponentTemplate", "test-deadletter", "recovered"): marshal(letter("recovered")),
+			}
+		})
+
+		It("should keep the dead letters of the ConfigMap that were not reconciled since", func() {
+			report.Report(letter("failed"))
+			report.Forget("OpensearchComponentTemplate", "test-deadletter", "recovered")
+			start()
+			Eventually(written).Should(Receive(Equal(map[string]DeadLetter{
+				"opensearchcomponenttemplate.test-deadletter.kept":   letter("kept"),
+				"opensearchcomponenttemplate.test-deadletter.failed": letter("failed"),
+			})))
+		})
+	})
+
+	When("the dead letters change", func() {
+		BeforeEach(func() {
+			start()
+			Eventually(written).Should(Receive(BeEmpty()))
+		})
+
+		It("should list a reported object and drop it once it is forgotten", func() {
+			report.Report(letter("test-template"))
+			Eventually(written).Should(Receive(Equal(map[string]DeadLetter{
+				"opensearchcomponenttemplate.test-deadletter.test-template": letter("test-template"),
+			})))
+
+			report.Forget("OpensearchComponentTemplate", "test-deadletter", "test-template")
+			Eventually(written).Should(Receive(BeEmpty()))
+		})
+
+		It("should not write a dead letter that did not change again", func() {
+			report.Report(letter("test-template"))
+			Eventually(written).Should(Receive(HaveLen(1)))
+			report.Report(letter("test-template"))
+			Consistently(written, 100*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Describe("component templates", func() {
+		var (
+			reconciler *ComponentTemplateReconciler
+			clusters   []opsterv1.ComponentTemplateClusterStatus
+		)
+
+		BeforeEach(func() {
+			// The report is not started, as if the ConfigMap had been loaded already
+			report.loaded = true
+			options := ReconcilerOptions{}
+			options.apply(WithDeadLetters(time.Hour, report))
+			reconciler = &ComponentTemplateReconciler{
+				ReconcilerOptions: options,
+				instance: &opsterv1.OpensearchComponentTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "test-deadletter"},
+				},
+				logger: log.FromContext(context.Background()),
+			}
+			clusters = []opsterv1.ComponentTemplateClusterStatus{{
+				Name:               "test-cluster",
+				State:              opsterv1.OpensearchComponentTemplateError,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second)),
+			}}
+		})
+
+		AfterEach(func() {
+			forgetDeadLetter(nil, "OpensearchComponentTemplate", "test-deadletter", "test-template")
+		})
+
+		deadLetterMetric := func(state opsterv1.OpensearchComponentTemplateState) float64 {
+			return testutil.ToFloat64(deadLetters.WithLabelValues("OpensearchComponentTemplate", "test-deadletter", "test-template", "test-cluster", string(state)))
+		}
+
+		It("should report a component template failing for longer than the threshold", func() {
+			reconciler.reportDeadLetter(string(opsterv1.OpensearchComponentTemplateError), "failed to apply component template", clusters)
+			Expect(report.letters).To(HaveKeyWithValue("opensearchcomponenttemplate.test-deadletter.test-template", &DeadLetter{
+				Kind:      "OpensearchComponentTemplate",
+				Namespace: "test-deadletter",
+				Name:      "test-template",
+				Cluster:   "test-cluster",
+				State:     string(opsterv1.OpensearchComponentTemplateError),
+				Reason:    "failed to apply component template",
+				Since:     clusters[0].LastTransitionTime,
+			}))
+			Expect(deadLetterMetric(opsterv1.OpensearchComponentTemplateError)).To(Equal(1.0))
+		})
+
+		It("should not report a component template that only started failing", func() {
+			clusters[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+			reconciler.reportDeadLetter(string(opsterv1.OpensearchComponentTemplateError), "failed to apply component template", clusters)
+			Expect(report.letters).ToNot(HaveKey("opensearchcomponenttemplate.test-deadletter.test-template"))
+			Expect(testutil.CollectAndCount(deadLetters)).To(BeZero())
+		})
+
+		It("should forget a component template that recovered", func() {
+			reconciler.reportDeadLetter(string(opsterv1.OpensearchComponentTemplateForbidden), "operator user is not authorized", clusters)
+			Expect(deadLetterMetric(opsterv1.OpensearchComponentTemplateForbidden)).To(Equal(1.0))
+
+			clusters[0].State = opsterv1.OpensearchComponentTemplateCreated
+			reconciler.reportDeadLetter(string(opsterv1.OpensearchComponentTemplateCreated), "", clusters)
+			Expect(report.letters).ToNot(HaveKey("opensearchcomponenttemplate.test-deadletter.test-template"))
+			Expect(testutil.CollectAndCount(deadLetters)).To(BeZero())
+		})
+	})
+})
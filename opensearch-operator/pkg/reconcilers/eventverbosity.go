@@ -0,0 +1,87 @@
+package reconcilers
+
+import (
+	"fmt"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventVerbosity selects which events are emitted for component templates
+type EventVerbosity string
+
+const (
+	// EventVerbosityNormal emits all events
+	EventVerbosityNormal EventVerbosity = "normal"
+	// EventVerbosityWarning only emits Warning events, informational Normal events are dropped
+	EventVerbosityWarning EventVerbosity = "warning"
+)
+
+// ParseEventVerbosity returns the event verbosity named by value, empty is EventVerbosityNormal
+func ParseEventVerbosity(value string) (EventVerbosity, error) {
+	switch EventVerbosity(value) {
+	case "", EventVerbosityNormal:
+		return EventVerbosityNormal, nil
+	case EventVerbosityWarning:
+		return EventVerbosityWarning, nil
+	default:
+		return "", fmt.Errorf("invalid event verbosity %q, must be %s or %s", value, EventVerbosityNormal, EventVerbosityWarning)
+	}
+}
+
+// WithEventVerbosity sets the events emitted for reconciled objects without the
+// opensearch.opster.io/event-verbosity annotation
+func WithEventVerbosity(verbosity EventVerbosity) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.eventVerbosity = verbosity
+	}
+}
+
+// verbosityRecorder drops Normal events of objects whose event verbosity only asks for warnings. Warning events
+// are always emitted.
+type verbosityRecorder struct {
+	recorder  record.EventRecorder
+	verbosity EventVerbosity
+}
+
+func newVerbosityRecorder(recorder record.EventRecorder, verbosity EventVerbosity) record.EventRecorder {
+	return &verbosityRecorder{recorder: recorder, verbosity: verbosity}
+}
+
+// emits returns true if the event is emitted, the annotation of the object takes precedence over the verbosity
+// of the operator. An invalid annotation is ignored.
+func (v *verbosityRecorder) emits(object runtime.Object, eventtype string) bool {
+	if eventtype != corev1.EventTypeNormal {
+		return true
+	}
+	verbosity := v.verbosity
+	if accessor, err := meta.Accessor(object); err == nil {
+		if value, ok := accessor.GetAnnotations()[helpers.EventVerbosityAnnotation]; ok {
+			if annotated, err := ParseEventVerbosity(value); err == nil {
+				verbosity = annotated
+			}
+		}
+	}
+	return verbosity != EventVerbosityWarning
+}
+
+func (v *verbosityRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if v.emits(object, eventtype) {
+		v.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (v *verbosityRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if v.emits(object, eventtype) {
+		v.recorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (v *verbosityRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if v.emits(object, eventtype) {
+		v.recorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
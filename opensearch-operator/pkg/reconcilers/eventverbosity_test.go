@@ -0,0 +1,59 @@
+package reconcilers
+
+import (
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("event verbosity", func() {
+	var (
+		fake     *record.FakeRecorder
+		instance *opsterv1.OpensearchComponentTemplate
+	)
+
+	BeforeEach(func() {
+		fake = record.NewFakeRecorder(10)
+		instance = &opsterv1.OpensearchComponentTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "test-namespace"},
+		}
+	})
+
+	emitted := func(verbosity EventVerbosity) []string {
+		recorder := newVerbosityRecorder(fake, verbosity)
+		recorder.Event(instance, "Normal", opensearchAPIUpdated, "component template updated in opensearch")
+		recorder.Eventf(instance, "Warning", opensearchAPIError, "failed to %s", "update")
+		close(fake.Events)
+		var events []string
+		for event := range fake.Events {
+			events = append(events, event)
+		}
+		return events
+	}
+
+	It("should not accept unknown verbosities", func() {
+		_, err := ParseEventVerbosity("debug")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should emit all events by default", func() {
+		Expect(emitted("")).To(HaveLen(2))
+	})
+
+	It("should only emit warning events with the warning verbosity", func() {
+		Expect(emitted(EventVerbosityWarning)).To(Equal([]string{"Warning OpensearchAPIError failed to update"}))
+	})
+
+	It("should follow the annotation of the object", func() {
+		instance.Annotations = map[string]string{helpers.EventVerbosityAnnotation: string(EventVerbosityNormal)}
+		Expect(emitted(EventVerbosityWarning)).To(HaveLen(2))
+	})
+
+	It("should ignore an invalid annotation", func() {
+		instance.Annotations = map[string]string{helpers.EventVerbosityAnnotation: "debug"}
+		Expect(emitted(EventVerbosityWarning)).To(HaveLen(1))
+	})
+})
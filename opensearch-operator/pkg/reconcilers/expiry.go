@@ -0,0 +1,74 @@
+package reconcilers
+
+import (
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchExpired = "OpensearchComponentTemplateExpired"
+
+	// opensearchComponentTemplateExpired is the reason of component templates whose TTL has passed
+	opensearchComponentTemplateExpired = "component template expired"
+
+	// expiryClockSkew is how long after its expiry time a component template is expired, so that an operator clock
+	// running ahead of the API server clock does not expire it early
+	expiryClockSkew = 30 * time.Second
+)
+
+// checkExpiry records when the TTL of the spec passes in the status and expires the component template once it has.
+// The expiry time is counted from the creation timestamp set by the API server and only acted on expiryClockSkew
+// after it, changing or removing the TTL moves or clears it. It returns whether the component template is expired.
+func (r *ComponentTemplateReconciler) checkExpiry(templateName string) (bool, ctrl.Result, string, error) {
+	var expiresAt *metav1.Time
+	if ttl := r.instance.Spec.TTL; ttl != nil {
+		// The status keeps whole seconds only
+		at := metav1.NewTime(r.instance.CreationTimestamp.Add(ttl.Duration).Truncate(time.Second))
+		expiresAt = &at
+	}
+	if !r.instance.Status.ExpiresAt.Equal(expiresAt) {
+		if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+			status.ExpiresAt = expiresAt
+		}); err != nil {
+			reason := fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return false, ctrl.Result{}, reason, err
+		}
+	}
+	if expiresAt == nil || time.Now().Before(expiresAt.Add(expiryClockSkew)) {
+		return false, ctrl.Result{}, "", nil
+	}
+
+	if err := r.checkComposingIndexTemplates(templateName); err != nil {
+		return true, ctrl.Result{}, err.Error(), err
+	}
+
+	if r.instance.Spec.ExpiryPolicy == opsterv1.ExpiryPolicyRetain {
+		if err := r.deleteComponentTemplate(templateName); err != nil {
+			reason := fmt.Sprintf("failed to delete expired component template from OpenSearch: %s", err)
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return true, ctrl.Result{}, reason, err
+		}
+		if r.instance.Status.State != opsterv1.OpensearchComponentTemplateExpired {
+			r.recorder.Event(r.instance, "Normal", opensearchExpired,
+				fmt.Sprintf("component template expired at %s and was deleted from OpenSearch", expiresAt.UTC().Format(time.RFC3339)))
+		}
+		return true, ctrl.Result{}, opensearchComponentTemplateExpired, nil
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchExpired,
+		fmt.Sprintf("component template expired at %s, deleting it", expiresAt.UTC().Format(time.RFC3339)))
+	if err := r.client.DeleteOpensearchComponentTemplate(r.instance); client.IgnoreNotFound(err) != nil {
+		reason := fmt.Sprintf("failed to delete expired component template: %s", err)
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return true, ctrl.Result{}, reason, err
+	}
+	return true, ctrl.Result{}, opensearchComponentTemplateExpired, nil
+}
@@ -0,0 +1,76 @@
+package reconcilers
+
+import (
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	opensearchExternalEdit = "OpensearchComponentTemplateExternalEdit"
+
+	// defaultExternalEditGracePeriod is how long an edit made in OpenSearch is kept with the Warn external edit policy
+	defaultExternalEditGracePeriod = 10 * time.Minute
+)
+
+// handleExternalEdit applies the external edit policy if the live component template was edited in OpenSearch since
+// the operator last applied it. It returns true if the edit is kept and the spec must not be applied. Edits are only
+// considered external while the spec is unchanged, a changed spec is always applied.
+func (r *ComponentTemplateReconciler) handleExternalEdit(live requests.ComponentTemplate) (bool, string, error) {
+	status := r.instance.Status
+	if status.LastAppliedHash == "" || status.LastAppliedGeneration != r.generation {
+		return false, "", nil
+	}
+	liveHash, err := componentTemplateHash(live)
+	if err != nil {
+		reason := "failed to hash the component template"
+		r.logger.Error(err, reason)
+		return false, reason, err
+	}
+	if liveHash == status.LastAppliedHash {
+		return false, "", nil
+	}
+
+	switch r.instance.Spec.ExternalEditPolicy {
+	case opsterv1.ExternalEditPolicyWarn:
+		gracePeriod := defaultExternalEditGracePeriod
+		if r.instance.Spec.ExternalEditGracePeriod != nil {
+			gracePeriod = r.instance.Spec.ExternalEditGracePeriod.Duration
+		}
+		if status.ExternalEditDetectedAt == nil {
+			reason := fmt.Sprintf("component template was edited in OpenSearch, the edit is reverted in %s", gracePeriod)
+			r.recorder.Event(r.instance, "Warning", opensearchExternalEdit, reason)
+			now := metav1.Now()
+			if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+				status.ExternalEditDetectedAt = &now
+			}); err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return false, reason, err
+			}
+			return true, reason, nil
+		}
+		if time.Since(status.ExternalEditDetectedAt.Time) < gracePeriod {
+			return true, "component template was edited in OpenSearch, waiting for the grace period to revert it", nil
+		}
+		r.recorder.Event(r.instance, "Warning", opensearchExternalEdit, "grace period of the edit made in OpenSearch is over, reverting it")
+		return false, "", nil
+	case opsterv1.ExternalEditPolicyAdopt:
+		if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+			status.LastAppliedHash = liveHash
+			status.ExternalEditDetectedAt = nil
+		}); err != nil {
+			reason := fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return false, reason, err
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchExternalEdit, "adopted the edit made to the component template in OpenSearch")
+		return true, "", nil
+	default:
+		r.recorder.Event(r.instance, "Normal", opensearchExternalEdit, "component template was edited in OpenSearch, reverting the edit")
+		return false, "", nil
+	}
+}
@@ -0,0 +1,49 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const opensearchFieldSecurityDrift = "OpensearchComponentTemplateFieldSecurityDrift"
+
+// checkFieldSecurity fails the reconcile unless the OpensearchRole referenced by the spec is created and hides the
+// fields the indices created from the template require to be hidden. It runs on every reconcile, so a later change
+// of the role is reported as drift.
+func (r *ComponentTemplateReconciler) checkFieldSecurity(spec opsterv1.OpensearchComponentTemplateSpec) (string, error) {
+	if spec.FieldSecurity == nil {
+		return "", nil
+	}
+	roleName := spec.FieldSecurity.RoleRef.Name
+
+	role, err := r.client.GetOpensearchRole(roleName, r.instance.Namespace)
+	if k8serrors.IsNotFound(err) {
+		reason := fmt.Sprintf("role %s referenced by the field security does not exist", roleName)
+		r.recorder.Event(r.instance, "Warning", opensearchFieldSecurityDrift, reason)
+		return reason, errors.New(reason)
+	}
+	if err != nil {
+		reason := "error fetching role"
+		r.logger.Error(err, "failed to fetch role")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	if role.Status.State != opsterv1.OpensearchRoleStateCreated && role.Status.State != opsterv1.OpensearchRoleIgnored {
+		reason := fmt.Sprintf("role %s referenced by the field security is not created in OpenSearch", roleName)
+		r.recorder.Event(r.instance, "Warning", opensearchFieldSecurityDrift, reason)
+		return reason, errors.New(reason)
+	}
+
+	drift := helpers.FieldSecurityDrift(*spec.FieldSecurity, role.Spec)
+	if len(drift) == 0 {
+		return "", nil
+	}
+	reason := fmt.Sprintf("role %s does not provide the field security of the component template: %s", roleName, strings.Join(drift, "; "))
+	r.recorder.Event(r.instance, "Warning", opensearchFieldSecurityDrift, reason)
+	return reason, errors.New(reason)
+}
@@ -0,0 +1,13 @@
+package reconcilers
+
+import (
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+// serverlessEndpoint returns true if the cluster has the endpoint flavor annotation set to serverless. Such endpoints
+// lack the cluster health and nodes APIs and their phase is not maintained by the operator, reconcilers therefore
+// only require them to answer a ping and skip the calls they do not support.
+func serverlessEndpoint(cluster *opsterv1.OpenSearchCluster) bool {
+	return cluster.Annotations[helpers.EndpointFlavorAnnotation] == helpers.EndpointFlavorServerless
+}
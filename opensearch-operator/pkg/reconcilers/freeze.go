@@ -0,0 +1,20 @@
+package reconcilers
+
+import (
+	"errors"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+// opensearchClusterFrozen is the reason set while changes are deferred because the cluster is frozen
+const opensearchClusterFrozen = "deferring changes while the cluster is frozen with the " + helpers.FreezeAnnotation + " annotation"
+
+// errClusterFrozen is returned by deletes while the cluster is frozen so they are retried
+var errClusterFrozen = errors.New(opensearchClusterFrozen)
+
+// clusterFrozen returns true if the cluster has the freeze annotation set, reconcilers of objects targeting it then
+// defer all changes in OpenSearch until the annotation is removed
+func clusterFrozen(cluster *opsterv1.OpenSearchCluster) bool {
+	return cluster.Annotations[helpers.FreezeAnnotation] == "true"
+}
@@ -0,0 +1,395 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchIndexExists              = "index already exists in OpenSearch; not modifying"
+	opensearchIndexNameMismatch        = "OpensearchIndexNameMismatch"
+	opensearchIndexStaticSettingChange = "OpensearchIndexStaticSettingChange"
+)
+
+type IndexReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchIndex
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewIndexReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchIndex,
+	opts ...ReconcilerOption,
+) *IndexReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &IndexReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "index"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "index"),
+	}
+}
+
+func (r *IndexReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var indexName string
+	managedAliases := r.instance.Status.Aliases
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchIndex)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchIndexError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchIndexPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchIndexDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchIndexCreated
+				instance.Status.IndexName = indexName
+				instance.Status.Aliases = managedAliases
+			}
+			if reason == opensearchIndexExists {
+				instance.Status.State = opsterv1.OpensearchIndexIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster an index refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchIndex)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	indexName = r.instance.Name
+	if r.instance.Spec.Name != "" {
+		indexName = r.instance.Spec.Name
+	}
+
+	// Check index state to make sure we don't touch preexisting indices
+	if r.instance.Status.ExistingIndex == nil {
+		var exists bool
+		exists, err = services.IndexExists(r.ctx, r.osClient, indexName)
+		if err != nil {
+			reason = "failed to get index status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchIndex)
+				instance.Status.ExistingIndex = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If index is existing do nothing
+	if *r.instance.Status.ExistingIndex {
+		reason = opensearchIndexExists
+		return
+	}
+
+	// the index name is immutable, so check the old name (r.instance.Status.IndexName) against the new
+	if r.instance.Status.IndexName != "" && indexName != r.instance.Status.IndexName {
+		reason = "cannot change the index name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchIndexNameMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource := helpers.TranslateOpensearchIndexToRequest(r.instance.Spec)
+
+	exists, err := services.IndexExists(r.ctx, r.osClient, indexName)
+	if err != nil {
+		reason = "failed to get index status from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if !exists {
+		err = services.CreateNewIndex(r.ctx, r.osClient, indexName, resource)
+		if err != nil {
+			reason = "failed to create index with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		managedAliases = aliasNames(resource.Aliases)
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "index created in opensearch")
+		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		return
+	}
+
+	updated, err := r.updateIndex(indexName, resource)
+	if err != nil {
+		var static staticSettingChangeError
+		if errors.As(err, &static) {
+			reason = static.Error()
+			r.recorder.Event(r.instance, "Warning", opensearchIndexStaticSettingChange, reason)
+			return
+		}
+		reason = "failed to update index with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+	managedAliases = aliasNames(resource.Aliases)
+
+	if updated {
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "index updated in opensearch")
+	} else {
+		r.logger.V(1).Info(fmt.Sprintf("index %s is in sync", r.instance.Name))
+	}
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+// staticSettingChangeError lists the static settings of the spec that differ from the settings of the existing index
+type staticSettingChangeError []string
+
+func (e staticSettingChangeError) Error() string {
+	return fmt.Sprintf("static settings cannot be changed on an existing index: %s", strings.Join(e, ", "))
+}
+
+// updateIndex applies the dynamic settings, the mappings and the aliases of the resource to the existing index and
+// returns true if it changed anything. Changes of static settings fail with a staticSettingChangeError before
+// anything is changed
+func (r *IndexReconciler) updateIndex(indexName string, resource requests.Index) (bool, error) {
+	settings, err := services.GetIndexSettings(r.ctx, r.osClient, indexName)
+	if err != nil {
+		return false, err
+	}
+	dynamic, static, err := helpers.IndexSettingChanges(resource.Settings, settings)
+	if err != nil {
+		return false, err
+	}
+	if len(static) > 0 {
+		return false, staticSettingChangeError(static)
+	}
+
+	var mappingMismatches []string
+	if resource.Mappings.Size() > 0 {
+		mappings, err := services.GetIndexMappings(r.ctx, r.osClient, indexName)
+		if err != nil {
+			return false, err
+		}
+		mappingMismatches, err = helpers.IndexMismatches(requests.Index{Mappings: resource.Mappings}, nil, mappings, nil)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	aliases, err := services.IndexAliases(r.ctx, r.osClient, indexName)
+	if err != nil {
+		return false, err
+	}
+	add, remove, err := helpers.IndexAliasChanges(resource.Aliases, aliases, r.instance.Status.Aliases)
+	if err != nil {
+		return false, err
+	}
+
+	if len(dynamic) > 0 {
+		r.logger.Info(fmt.Sprintf("updating %d settings of index %s", len(dynamic), indexName))
+		if err := services.PutIndexSettings(r.ctx, r.osClient, indexName, dynamic); err != nil {
+			return false, err
+		}
+	}
+	if len(mappingMismatches) > 0 {
+		r.logger.Info(fmt.Sprintf("updating the mappings of index %s: %s", indexName, strings.Join(mappingMismatches, ", ")))
+		if err := services.PutIndexMappings(r.ctx, r.osClient, indexName, resource.Mappings); err != nil {
+			return false, err
+		}
+	}
+	if len(add) > 0 || len(remove) > 0 {
+		added := make(map[string]requests.IndexAlias, len(add))
+		for _, name := range add {
+			added[name] = resource.Aliases[name]
+		}
+		if err := services.UpdateIndexAliases(r.ctx, r.osClient, indexName, added, remove); err != nil {
+			return false, err
+		}
+	}
+
+	return len(dynamic) > 0 || len(mappingMismatches) > 0 || len(add) > 0 || len(remove) > 0, nil
+}
+
+// aliasNames returns the sorted names of the aliases
+func aliasNames(aliases map[string]requests.IndexAlias) []string {
+	var names []string
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *IndexReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingIndex == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingIndex {
+		r.logger.Info("index was pre-existing; not deleting")
+		return nil
+	}
+
+	if r.instance.Spec.DeletionPolicy != opsterv1.IndexDeletionPolicyDelete {
+		r.logger.Info("deletion policy of the index is not Delete; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	indexName := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		indexName = r.instance.Spec.Name
+	}
+
+	return services.DeleteIndexIfExists(r.ctx, r.osClient, indexName)
+}
@@ -0,0 +1,493 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("index reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *IndexReconciler
+		instance   *opsterv1.OpensearchIndex
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		indexUrl   string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchIndex{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-index",
+				Namespace: "test-index",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchIndexResourceSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Name: "my-index",
+				OpensearchIndexSpec: opsterv1.OpensearchIndexSpec{
+					Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":1,"refresh_interval":"1s"}}`)},
+					Mappings: &apiextensionsv1.JSON{Raw: []byte(`{"properties":{"message":{"type":"text"}}}`)},
+					Aliases: map[string]opsterv1.OpensearchIndexAliasSpec{
+						"logs": {IsWriteIndex: true},
+					},
+				},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-index",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		indexUrl = fmt.Sprintf("%smy-index", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &IndexReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster an index refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodHead,
+					indexUrl,
+					httpmock.NewStringResponder(200, "").Once(failMessage),
+				)
+			})
+
+			It("should record that the index exists", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingIndex = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingIndex = pointer.Bool(false)
+			})
+
+			When("index does not exist in opensearch", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodHead,
+						indexUrl,
+						httpmock.NewStringResponder(404, "").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						indexUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+				})
+
+				It("should create the index", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s index created in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{
+						"settings": {"index":{"number_of_shards":1,"refresh_interval":"1s"}},
+						"mappings": {"properties":{"message":{"type":"text"}}},
+						"aliases": {"logs":{"is_write_index":true}}
+					}`))
+				})
+			})
+
+			When("index exists in opensearch and is the same", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodHead,
+						indexUrl,
+						httpmock.NewStringResponder(200, "").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexUrl+"/_settings?flat_settings=true",
+						httpmock.NewStringResponder(200, `{"my-index":{"settings":{
+							"index.number_of_shards":"1",
+							"index.number_of_replicas":"1",
+							"index.refresh_interval":"1s"
+						}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexUrl+"/_mapping",
+						httpmock.NewStringResponder(200, `{"my-index":{"mappings":{"properties":{"message":{"type":"text"}}}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexUrl+"/_alias",
+						httpmock.NewStringResponder(200, `{"my-index":{"aliases":{"logs":{"is_write_index":true}}}}`).Once(failMessage),
+					)
+				})
+
+				It("should do nothing", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				})
+			})
+
+			When("index exists in opensearch with other dynamic settings, mappings and aliases", func() {
+				var settingsBody, mappingsBody, aliasesBody string
+
+				capture := func(body *string) httpmock.Responder {
+					return func(req *http.Request) (*http.Response, error) {
+						raw, err := io.ReadAll(req.Body)
+						if err != nil {
+							return nil, err
+						}
+						*body = string(raw)
+						return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+					}
+				}
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.Aliases = []string{"logs", "old-logs"}
+					transport.RegisterResponder(
+						http.MethodHead,
+						indexUrl,
+						httpmock.NewStringResponder(200, "").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexUrl+"/_settings?flat_settings=true",
+						httpmock.NewStringResponder(200, `{"my-index":{"settings":{
+							"index.number_of_shards":"1",
+							"index.refresh_interval":"30s"
+						}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexUrl+"/_mapping",
+						httpmock.NewStringResponder(200, `{"my-index":{"mappings":{}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexUrl+"/_alias",
+						httpmock.NewStringResponder(200, `{"my-index":{"aliases":{"logs":{},"old-logs":{},"other":{}}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(http.MethodPut, indexUrl+"/_settings", capture(&settingsBody))
+					transport.RegisterResponder(http.MethodPut, indexUrl+"/_mapping", capture(&mappingsBody))
+					transport.RegisterResponder(http.MethodPost, clusterUrl+"_aliases", capture(&aliasesBody))
+				})
+
+				It("should update the index", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s index updated in opensearch", opensearchAPIUpdated)}))
+					Expect(settingsBody).To(MatchJSON(`{"index.refresh_interval":"1s"}`))
+					Expect(mappingsBody).To(MatchJSON(`{"properties":{"message":{"type":"text"}}}`))
+					Expect(aliasesBody).To(MatchJSON(`{"actions":[
+						{"add":{"index":"my-index","alias":"logs","is_write_index":true}},
+						{"remove":{"index":"my-index","alias":"old-logs"}}
+					]}`))
+				})
+			})
+
+			When("a static setting of the index has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodHead,
+						indexUrl,
+						httpmock.NewStringResponder(200, "").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexUrl+"/_settings?flat_settings=true",
+						httpmock.NewStringResponder(200, `{"my-index":{"settings":{
+							"index.number_of_shards":"3",
+							"index.refresh_interval":"30s"
+						}}}`).Once(failMessage),
+					)
+				})
+
+				It("should reject the change without updating the index", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf(
+						"Warning %s static settings cannot be changed on an existing index: index.number_of_shards",
+						opensearchIndexStaticSettingChange,
+					)}))
+				})
+			})
+
+			When("the name of the index has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.IndexName = "my-index"
+					instance.Spec.Name = "new-index"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the index name", opensearchIndexNameMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingIndex = pointer.Bool(true)
+				instance.Spec.DeletionPolicy = opsterv1.IndexDeletionPolicyDelete
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingIndex = pointer.Bool(false)
+			})
+
+			When("the deletion policy is Retain", func() {
+				BeforeEach(func() {
+					instance.Spec.DeletionPolicy = opsterv1.IndexDeletionPolicyRetain
+				})
+				It("should keep the index", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(BeZero())
+				})
+			})
+
+			When("the deletion policy is Delete", func() {
+				BeforeEach(func() {
+					instance.Spec.DeletionPolicy = opsterv1.IndexDeletionPolicyDelete
+				})
+
+				When("cluster does not exist", func() {
+					BeforeEach(func() {
+						instance.Spec.OpensearchRef.Name = "doesnotexist"
+						mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+					})
+					It("should do nothing and exit", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+					})
+				})
+
+				When("cluster exists", func() {
+					BeforeEach(func() {
+						mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+						transport.RegisterResponder(
+							http.MethodGet,
+							clusterUrl,
+							httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodHead,
+							clusterUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodDelete,
+							indexUrl,
+							httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+						)
+					})
+
+					It("should delete the index", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,327 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchIndexAliasExists       = "alias already exists in OpenSearch; not modifying"
+	opensearchIndexAliasNameMismatch = "OpensearchIndexAliasNameMismatch"
+	opensearchInvalidIndexAlias      = "OpensearchInvalidIndexAlias"
+)
+
+type IndexAliasReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchIndexAlias
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewIndexAliasReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchIndexAlias,
+	opts ...ReconcilerOption,
+) *IndexAliasReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &IndexAliasReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "indexalias"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "indexalias"),
+	}
+}
+
+func (r *IndexAliasReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var aliasName string
+	var indices []string
+	var writeIndex string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchIndexAlias)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchIndexAliasError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchIndexAliasPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchIndexAliasDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchIndexAliasCreated
+				instance.Status.AliasName = aliasName
+				instance.Status.Indices = indices
+				instance.Status.WriteIndex = writeIndex
+			}
+			if reason == opensearchIndexAliasExists {
+				instance.Status.State = opsterv1.OpensearchIndexAliasIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster an alias refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchIndexAlias)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	aliasName = r.instance.Name
+	if r.instance.Spec.Name != "" {
+		aliasName = r.instance.Spec.Name
+	}
+
+	if err = helpers.ValidateIndexAlias(aliasName, r.instance.Spec); err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidIndexAlias, reason)
+		return
+	}
+
+	// Check alias state to make sure we don't touch preexisting aliases
+	if r.instance.Status.ExistingAlias == nil {
+		var existing map[string]*apiextensionsv1.JSON
+		existing, err = services.AliasDefinitions(r.ctx, r.osClient, aliasName)
+		if err != nil {
+			reason = "failed to get alias status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		exists := len(existing) > 0
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchIndexAlias)
+				instance.Status.ExistingAlias = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If alias is existing do nothing
+	if *r.instance.Status.ExistingAlias {
+		reason = opensearchIndexAliasExists
+		return
+	}
+
+	// the alias name is immutable, so check the old name (r.instance.Status.AliasName) against the new
+	if r.instance.Status.AliasName != "" && aliasName != r.instance.Status.AliasName {
+		reason = "cannot change the alias name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchIndexAliasNameMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	desired := helpers.TranslateIndexAliasToRequest(r.instance.Spec)
+	for index, definition := range desired {
+		indices = append(indices, index)
+		if definition.IsWriteIndex {
+			writeIndex = index
+		}
+	}
+	sort.Strings(indices)
+
+	existing, err := services.AliasDefinitions(r.ctx, r.osClient, aliasName)
+	if err != nil {
+		reason = "failed to get alias from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+	add, remove, err := helpers.AliasIndexChanges(desired, existing)
+	if err != nil {
+		reason = "failed to compare the alias with OpenSearch"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if len(add) == 0 && len(remove) == 0 {
+		r.logger.V(1).Info(fmt.Sprintf("alias %s is in sync", r.instance.Name))
+		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		return
+	}
+
+	added := make(map[string]requests.IndexAlias, len(add))
+	for _, index := range add {
+		added[index] = desired[index]
+	}
+	err = services.UpdateAlias(r.ctx, r.osClient, aliasName, added, remove)
+	if err != nil {
+		reason = "failed to update alias with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	var changes []string
+	if len(add) > 0 {
+		changes = append(changes, fmt.Sprintf("added to %s", strings.Join(add, ", ")))
+	}
+	if len(remove) > 0 {
+		changes = append(changes, fmt.Sprintf("removed from %s", strings.Join(remove, ", ")))
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, fmt.Sprintf("alias updated in opensearch: %s", strings.Join(changes, "; ")))
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+func (r *IndexAliasReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingAlias == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingAlias {
+		r.logger.Info("alias was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	aliasName := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		aliasName = r.instance.Spec.Name
+	}
+
+	// Only the alias is removed, the indices it points to are kept
+	return services.DeleteAlias(r.ctx, r.osClient, aliasName)
+}
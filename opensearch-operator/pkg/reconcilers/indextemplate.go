@@ -2,7 +2,9 @@ package reconcilers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
@@ -23,6 +25,8 @@ import (
 const (
 	opensearchIndexTemplateExists       = "index template already exists in OpenSearch; not modifying"
 	opensearchIndexTemplateNameMismatch = "OpensearchIndexTemplateNameMismatch"
+	opensearchInvalidComposition        = "OpensearchIndexTemplateInvalidComposition"
+	opensearchInvalidIndexPattern       = "OpensearchIndexTemplateInvalidIndexPattern"
 )
 
 type IndexTemplateReconciler struct {
@@ -74,9 +78,15 @@ func (r *IndexTemplateReconciler) Reconcile() (result ctrl.Result, err error) {
 			if result.Requeue && result.RequeueAfter == 10*time.Second {
 				instance.Status.State = opsterv1.OpensearchIndexTemplatePending
 			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchIndexTemplateDeferred
+			}
 			if err == nil && result.RequeueAfter == 30*time.Second {
 				instance.Status.State = opsterv1.OpensearchIndexTemplateCreated
 				instance.Status.IndexTemplateName = templateName
+				if instance.Spec.ApplyMode == opsterv1.TemplateApplyModeCreateOnly {
+					instance.Status.CreatedOnce = true
+				}
 			}
 			if reason == opensearchIndexTemplateExists {
 				instance.Status.State = opsterv1.OpensearchIndexTemplateIgnored
@@ -144,7 +154,18 @@ func (r *IndexTemplateReconciler) Reconcile() (result ctrl.Result, err error) {
 		return
 	}
 
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
 	if err != nil {
 		reason = "error creating opensearch client"
 		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
@@ -197,8 +218,37 @@ func (r *IndexTemplateReconciler) Reconcile() (result ctrl.Result, err error) {
 		return
 	}
 
+	if r.instance.Spec.ApplyMode == opsterv1.TemplateApplyModeCreateOnly && r.instance.Status.CreatedOnce {
+		var exists bool
+		exists, err = services.IndexTemplateExists(r.ctx, r.osClient, templateName)
+		if err != nil {
+			reason = "failed to get index template status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if exists {
+			r.logger.V(1).Info(fmt.Sprintf("index template %s was already created, not updating it in CreateOnly mode", r.instance.Name))
+			result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchPending, "index template was deleted from opensearch, creating it again")
+	}
+
 	// rewrite the CRD format to the gateway format
 	resource := helpers.TranslateIndexTemplateToRequest(r.instance.Spec)
+	if r.instance.Spec.GenerateIndexPatterns {
+		if resource.IndexPatterns, err = r.generateIndexPatterns(); err != nil {
+			reason = err.Error()
+			r.recorder.Event(r.instance, "Warning", opensearchInvalidIndexPattern, reason)
+			return
+		}
+	}
+	if err = helpers.ValidateTimeSettings(resource.Template.Settings); err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidTimeSetting, reason)
+		return
+	}
 
 	shouldUpdate, err := services.ShouldUpdateIndexTemplate(r.ctx, r.osClient, templateName, resource)
 	if err != nil {
@@ -214,7 +264,41 @@ func (r *IndexTemplateReconciler) Reconcile() (result ctrl.Result, err error) {
 		return
 	}
 
+	// validate the template composed with its component templates before applying it, so an index
+	// template that would compose to something invalid is never written
+	err = services.SimulateIndexTemplate(r.ctx, r.osClient, templateName, resource)
+	if errors.Is(err, services.ErrForbidden) {
+		reason = "operator user is not authorized to simulate index templates, check that it has the indices:admin/index_template/simulate privilege"
+		r.recorder.Event(r.instance, "Warning", opensearchForbidden, reason)
+		return
+	}
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidComposition, reason)
+		return
+	}
+
+	deferred, err := deferForClusterHealth(r.ctx, r.osClient, r.instance.Spec.RequiredClusterHealth)
+	if err != nil {
+		reason = "failed to get cluster health from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+	if deferred != "" {
+		reason = deferred
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+		return
+	}
+
+	release, err := r.applyQueue.Acquire(r.ctx, r.cluster.UID, r.instance.Spec.ApplyPriority)
+	if err != nil {
+		reason = "failed to wait for other applies to the cluster"
+		return
+	}
 	err = services.CreateOrUpdateIndexTemplate(r.ctx, r.osClient, templateName, resource)
+	release()
 	if err != nil {
 		reason = "failed to update index template with OpenSearch API"
 		r.logger.Error(err, reason)
@@ -252,8 +336,11 @@ func (r *IndexTemplateReconciler) Delete() error {
 		// If the opensearch cluster doesn't exist, we don't need to delete anything
 		return nil
 	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
 
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
 	if err != nil {
 		return err
 	}
@@ -274,3 +361,30 @@ func (r *IndexTemplateReconciler) Delete() error {
 
 	return services.DeleteIndexTemplate(r.ctx, r.osClient, templateName)
 }
+
+// generateIndexPatterns generates the index patterns from the labels and annotations of the instance with the
+// index pattern template of the operator and records them in the status
+func (r *IndexTemplateReconciler) generateIndexPatterns() ([]string, error) {
+	if r.indexPatternTemplate == nil {
+		return nil, fmt.Errorf("generateIndexPatterns is set but the operator has no index pattern template configured")
+	}
+	patterns, err := helpers.GenerateIndexPatterns(r.indexPatternTemplate, r.instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate index patterns: %w", err)
+	}
+
+	if !pointer.BoolDeref(r.updateStatus, true) {
+		r.instance.Status.GeneratedIndexPatterns = patterns
+		return patterns, nil
+	}
+	if !reflect.DeepEqual(r.instance.Status.GeneratedIndexPatterns, patterns) {
+		err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchIndexTemplate)
+			instance.Status.GeneratedIndexPatterns = patterns
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update status: %w", err)
+		}
+	}
+	return patterns, nil
+}
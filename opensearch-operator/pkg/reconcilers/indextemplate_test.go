@@ -3,12 +3,15 @@ package reconcilers
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
 	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -327,6 +330,11 @@ var _ = Describe("indextemplate reconciler", func() {
 						indexTemplateUrl,
 						httpmock.NewJsonResponderOrPanic(200, response).Once(failMessage),
 					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						fmt.Sprintf("%s_index_template/_simulate/my-template", clusterUrl),
+						httpmock.NewStringResponder(200, "{}").Once(failMessage),
+					)
 					transport.RegisterResponder(
 						http.MethodPut,
 						indexTemplateUrl,
@@ -376,6 +384,315 @@ var _ = Describe("indextemplate reconciler", func() {
 				})
 			})
 
+			Context("indextemplate is created only once", func() {
+				var indexTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					instance.Spec.ApplyMode = opsterv1.TemplateApplyModeCreateOnly
+					instance.Status.CreatedOnce = true
+					indexTemplateUrl = fmt.Sprintf("%s_index_template/my-template", clusterUrl)
+				})
+
+				When("it exists in opensearch", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodHead,
+							indexTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should not update it", func() {
+						result, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						Expect(transport.GetCallCountInfo()["PUT "+indexTemplateUrl]).To(Equal(0))
+					})
+				})
+
+				When("it was deleted from opensearch", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodHead,
+							indexTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodGet,
+							indexTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPost,
+							fmt.Sprintf("%s_index_template/_simulate/my-template", clusterUrl),
+							httpmock.NewStringResponder(200, "{}").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							indexTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should create it again", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{
+							fmt.Sprintf("Normal %s index template was deleted from opensearch, creating it again", opensearchPending),
+							fmt.Sprintf("Normal %s index template updated in opensearch", opensearchAPIUpdated),
+						}))
+					})
+				})
+			})
+
+			Context("indextemplate is composed of component templates", func() {
+				var indexTemplateUrl string
+				var simulateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.ComposedOf = []string{"my-settings", "my-mappings"}
+					indexTemplateUrl = fmt.Sprintf("%s_index_template/my-template", clusterUrl)
+					simulateUrl = fmt.Sprintf("%s_index_template/_simulate/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+				})
+
+				When("the composition is valid", func() {
+					var simulateBody string
+
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							func(req *http.Request) (*http.Response, error) {
+								body, err := io.ReadAll(req.Body)
+								if err != nil {
+									return nil, err
+								}
+								simulateBody = string(body)
+								return httpmock.NewStringResponse(200, "{}"), nil
+							},
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							indexTemplateUrl,
+							httpmock.NewStringResponder(200, "OK").Once(failMessage),
+						)
+					})
+
+					It("should simulate the composition before applying the indextemplate", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["POST "+simulateUrl]).To(Equal(1))
+							Expect(transport.GetCallCountInfo()["PUT "+indexTemplateUrl]).To(Equal(1))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s index template updated in opensearch", opensearchAPIUpdated)}))
+						Expect(simulateBody).To(ContainSubstring(`"composed_of":["my-settings","my-mappings"]`))
+					})
+				})
+
+				When("the composition is invalid", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodPost,
+							simulateUrl,
+							httpmock.NewStringResponder(400, `{"error":"component template [my-mappings] does not exist"}`).Once(failMessage),
+						)
+					})
+
+					It("should fail without applying the indextemplate", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+indexTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{fmt.Sprintf(
+							"Warning %s index template does not compose to a valid template: [400 Bad Request] {\"error\":\"component template [my-mappings] does not exist\"}",
+							opensearchInvalidComposition,
+						)}))
+					})
+				})
+			})
+
+			Context("indextemplate generates its index patterns", func() {
+				var indexTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.IndexPatterns = nil
+					instance.Spec.GenerateIndexPatterns = true
+					instance.Labels = map[string]string{"team": "payments", "app": "checkout"}
+					indexTemplateUrl = fmt.Sprintf("%s_index_template/my-template", clusterUrl)
+				})
+
+				JustBeforeEach(func() {
+					tmpl, err := helpers.ParseIndexPatternTemplate("{{ .Labels.team }}-{{ .Labels.app }}-logs-*")
+					Expect(err).ToNot(HaveOccurred())
+					reconciler.indexPatternTemplate = tmpl
+				})
+
+				When("the labels are set", func() {
+					var putBody string
+
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							indexTemplateUrl,
+							httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPost,
+							fmt.Sprintf("%s_index_template/_simulate/my-template", clusterUrl),
+							httpmock.NewStringResponder(200, "{}").Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							indexTemplateUrl,
+							func(req *http.Request) (*http.Response, error) {
+								body, err := io.ReadAll(req.Body)
+								if err != nil {
+									return nil, err
+								}
+								putBody = string(body)
+								return httpmock.NewStringResponse(200, "OK"), nil
+							},
+						)
+					})
+
+					It("should apply the generated index patterns", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).ToNot(HaveOccurred())
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s index template updated in opensearch", opensearchAPIUpdated)}))
+						Expect(putBody).To(ContainSubstring(`"index_patterns":["payments-checkout-logs-*"]`))
+						Expect(instance.Status.GeneratedIndexPatterns).To(Equal([]string{"payments-checkout-logs-*"}))
+					})
+				})
+
+				When("a label is missing", func() {
+					BeforeEach(func() {
+						delete(instance.Labels, "app")
+					})
+
+					It("should fail without applying the indextemplate", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+indexTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(HaveLen(1))
+						Expect(events[0]).To(HavePrefix(fmt.Sprintf("Warning %s failed to generate index patterns", opensearchInvalidIndexPattern)))
+						Expect(events[0]).To(ContainSubstring(`map has no entry for key "app"`))
+					})
+				})
+
+				When("a label generates an invalid index pattern", func() {
+					BeforeEach(func() {
+						instance.Labels["app"] = "Checkout"
+					})
+
+					It("should fail without applying the indextemplate", func() {
+						go func() {
+							defer GinkgoRecover()
+							defer close(recorder.Events)
+							_, err := reconciler.Reconcile()
+							Expect(err).To(HaveOccurred())
+							Expect(transport.GetCallCountInfo()["PUT "+indexTemplateUrl]).To(Equal(0))
+						}()
+						var events []string
+						for msg := range recorder.Events {
+							events = append(events, msg)
+						}
+						Expect(events).To(Equal([]string{fmt.Sprintf(
+							"Warning %s failed to generate index patterns: index pattern \"payments-Checkout-logs-*\" must be lowercase",
+							opensearchInvalidIndexPattern,
+						)}))
+					})
+				})
+			})
+
+			When("indextemplate requires a healthy cluster and the cluster is red", func() {
+				var indexTemplateUrl string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.RequiredClusterHealth = opsterv1.OpenSearchYellowHealth
+					indexTemplateUrl = fmt.Sprintf("%s_index_template/my-template", clusterUrl)
+					transport.RegisterResponder(
+						http.MethodGet,
+						indexTemplateUrl,
+						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						fmt.Sprintf("%s_index_template/_simulate/my-template", clusterUrl),
+						httpmock.NewStringResponder(200, "{}").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf("%s_cluster/health", clusterUrl),
+						httpmock.NewStringResponder(200, `{"status":"red"}`).Once(failMessage),
+					)
+				})
+
+				It("should defer creating the indextemplate", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						result, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+						Expect(transport.GetCallCountInfo()["PUT "+indexTemplateUrl]).To(Equal(0))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s deferring changes while the cluster health is red, yellow is required", opensearchDeferred)}))
+				})
+			})
+
 			When("indextemplate doesn't exist in opensearch", func() {
 				BeforeEach(func() {
 					recorder = record.NewFakeRecorder(1)
@@ -385,6 +702,11 @@ var _ = Describe("indextemplate reconciler", func() {
 						indexTemplateUrl,
 						httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
 					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						fmt.Sprintf("%s_index_template/_simulate/my-template", clusterUrl),
+						httpmock.NewStringResponder(200, "{}").Once(failMessage),
+					)
 					transport.RegisterResponder(
 						http.MethodPut,
 						indexTemplateUrl,
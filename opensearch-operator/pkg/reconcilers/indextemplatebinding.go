@@ -0,0 +1,53 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// opensearchDeleteBlocked is the event reason of a delete held back by index templates composing the component template
+const opensearchDeleteBlocked = "OpensearchComponentTemplateDeleteBlocked"
+
+// composingIndexTemplates returns the OpensearchIndexTemplates of the same cluster composing the component template.
+// Index templates that are deleted as well do not hold back the delete, OpenSearch rejects deleting the component
+// template until they are deleted from it and the delete is retried.
+func (r *ComponentTemplateReconciler) composingIndexTemplates(templateName string) ([]string, error) {
+	list, err := r.client.ListOpensearchIndexTemplates(client.InNamespace(r.instance.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	var composing []string
+	for _, item := range list.Items {
+		if item.Spec.OpensearchRef.Name != componentTemplateClusterName(r.instance) || !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		for _, name := range item.Spec.ComposedOf {
+			if name == templateName {
+				composing = append(composing, item.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(composing)
+	return composing, nil
+}
+
+// checkComposingIndexTemplates returns an error holding back the delete while OpensearchIndexTemplates compose the
+// component template, so deleting it does not leave them referencing a component template that no longer exists
+func (r *ComponentTemplateReconciler) checkComposingIndexTemplates(templateName string) error {
+	composing, err := r.composingIndexTemplates(templateName)
+	if err != nil {
+		return err
+	}
+	if len(composing) == 0 {
+		return nil
+	}
+	reason := fmt.Sprintf("component template %s is not deleted while the OpensearchIndexTemplates %s compose it, "+
+		"remove it from their composedOf or delete them", templateName, strings.Join(composing, ", "))
+	r.recorder.Event(r.instance, "Warning", opensearchDeleteBlocked, reason)
+	return errors.New(reason)
+}
@@ -0,0 +1,294 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchIngestPipelineExists       = "ingest pipeline already exists in OpenSearch; not modifying"
+	opensearchIngestPipelineNameMismatch = "OpensearchIngestPipelineNameMismatch"
+)
+
+type IngestPipelineReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchIngestPipeline
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewIngestPipelineReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchIngestPipeline,
+	opts ...ReconcilerOption,
+) *IngestPipelineReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &IngestPipelineReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "ingestpipeline"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "ingestpipeline"),
+	}
+}
+
+func (r *IngestPipelineReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var pipelineName string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchIngestPipeline)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchIngestPipelineError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchIngestPipelinePending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchIngestPipelineDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchIngestPipelineCreated
+				instance.Status.IngestPipelineName = pipelineName
+			}
+			if reason == opensearchIngestPipelineExists {
+				instance.Status.State = opsterv1.OpensearchIngestPipelineIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster an ingest pipeline refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchIngestPipeline)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	pipelineName = r.instance.Name
+	if r.instance.Spec.Name != "" {
+		pipelineName = r.instance.Spec.Name
+	}
+
+	// Check ingest pipeline state to make sure we don't touch preexisting ingest pipelines
+	if r.instance.Status.ExistingIngestPipeline == nil {
+		var exists bool
+		exists, err = services.IngestPipelineExists(r.ctx, r.osClient, pipelineName)
+		if err != nil {
+			reason = "failed to get ingest pipeline status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchIngestPipeline)
+				instance.Status.ExistingIngestPipeline = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If ingest pipeline is existing do nothing
+	if *r.instance.Status.ExistingIngestPipeline {
+		reason = opensearchIngestPipelineExists
+		return
+	}
+
+	// the pipeline name is immutable, so check the old name (r.instance.Status.IngestPipelineName) against the new
+	if r.instance.Status.IngestPipelineName != "" && pipelineName != r.instance.Status.IngestPipelineName {
+		reason = "cannot change the ingest pipeline name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchIngestPipelineNameMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource := helpers.TranslateIngestPipelineToRequest(r.instance.Spec)
+
+	shouldUpdate, err := services.ShouldUpdateIngestPipeline(r.ctx, r.osClient, pipelineName, resource)
+	if err != nil {
+		reason = "failed to get ingest pipeline status from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if !shouldUpdate {
+		r.logger.V(1).Info(fmt.Sprintf("ingest pipeline %s is in sync", r.instance.Name))
+		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		return
+	}
+
+	err = services.CreateOrUpdateIngestPipeline(r.ctx, r.osClient, pipelineName, resource)
+	if err != nil {
+		reason = "failed to update ingest pipeline with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "ingest pipeline updated in opensearch")
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+func (r *IngestPipelineReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingIngestPipeline == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingIngestPipeline {
+		r.logger.Info("ingest pipeline was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	pipelineName := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		pipelineName = r.instance.Spec.Name
+	}
+
+	exist, err := services.IngestPipelineExists(r.ctx, r.osClient, pipelineName)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		r.logger.V(1).Info("ingest pipeline already deleted from opensearch")
+		return nil
+	}
+
+	return services.DeleteIngestPipeline(r.ctx, r.osClient, pipelineName)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
@@ -74,6 +77,9 @@ func (r *IsmPolicyReconciler) Reconcile() (retResult ctrl.Result, retErr error)
 			if retResult.Requeue && retResult.RequeueAfter == 10*time.Second {
 				instance.Status.State = opsterv1.OpensearchISMPolicyPending
 			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchISMPolicyDeferred
+			}
 			// Requeue is after 30 seconds for normal reconciliation after creation/update
 			if retErr == nil && retResult.RequeueAfter == 30*time.Second {
 				instance.Status.State = opsterv1.OpensearchISMPolicyCreated
@@ -142,7 +148,18 @@ func (r *IsmPolicyReconciler) Reconcile() (retResult ctrl.Result, retErr error)
 		return
 	}
 
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
 	if err != nil {
 		reason := "error creating opensearch client"
 		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
@@ -211,6 +228,9 @@ func (r *IsmPolicyReconciler) Reconcile() (retResult ctrl.Result, retErr error)
 			return
 		}
 		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "policy created in opensearch")
+		if reason, retErr = r.reconcileExistingIndices(policyId); retErr != nil {
+			return
+		}
 		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, retErr
 	}
 	if err != nil {
@@ -231,6 +251,9 @@ func (r *IsmPolicyReconciler) Reconcile() (retResult ctrl.Result, retErr error)
 
 	if !shouldUpdate {
 		r.logger.V(1).Info(fmt.Sprintf("policy %s is in sync", r.instance.Spec.PolicyID))
+		if reason, retErr = r.reconcileExistingIndices(policyId); retErr != nil {
+			return
+		}
 		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, retErr
 	}
 
@@ -249,10 +272,142 @@ func (r *IsmPolicyReconciler) Reconcile() (retResult ctrl.Result, retErr error)
 	}
 
 	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "policy updated in opensearch")
+	if retErr == nil {
+		if reason, retErr = r.reconcileExistingIndices(policyId); retErr != nil {
+			return
+		}
+	}
 
 	return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, retErr
 }
 
+// reconcileExistingIndices attaches the policy to the existing indices matching the ISM template if the spec asks for it,
+// and detaches it from the indices it was attached to otherwise
+func (r *IsmPolicyReconciler) reconcileExistingIndices(policyId string) (string, error) {
+	attached := r.instance.Status.AttachedIndices
+	if !r.instance.Spec.ApplyToExistingIndices || r.instance.Spec.ISMTemplate == nil {
+		if len(attached) == 0 {
+			return "", nil
+		}
+		remaining, err := r.detachFromIndices(attached)
+		if err != nil {
+			reason := "failed to detach policy from existing indices"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return reason, err
+		}
+		return "", r.setAttachedIndices(remaining)
+	}
+
+	matching, err := services.ListIndicesMatching(r.ctx, r.osClient, r.instance.Spec.ISMTemplate.IndexPatterns)
+	if err != nil {
+		reason := "failed to list indices matching the ism template"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return reason, err
+	}
+
+	// Indices that were deleted in the meantime are no longer tracked
+	isAttached := map[string]bool{}
+	for _, index := range attached {
+		isAttached[index] = true
+	}
+	var tracked, toAttach []string
+	for _, index := range matching {
+		if isAttached[index] {
+			tracked = append(tracked, index)
+		} else {
+			toAttach = append(toAttach, index)
+		}
+	}
+
+	if len(toAttach) > 0 {
+		failed, err := services.AddISMPolicyToIndices(r.ctx, r.osClient, policyId, toAttach)
+		if err != nil {
+			reason := "failed to attach policy to existing indices"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return reason, err
+		}
+		for _, index := range toAttach {
+			if _, ok := failed[index]; !ok {
+				tracked = append(tracked, index)
+			}
+		}
+		if attachedCount := len(toAttach) - len(failed); attachedCount > 0 {
+			r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, fmt.Sprintf("policy attached to %d existing indices", attachedCount))
+		}
+		if len(failed) > 0 {
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, fmt.Sprintf("policy could not be attached to %d existing indices: %s", len(failed), describeFailedIndices(failed)))
+		}
+	}
+
+	sort.Strings(tracked)
+	if reflect.DeepEqual(tracked, attached) || (len(tracked) == 0 && len(attached) == 0) {
+		return "", nil
+	}
+	return "", r.setAttachedIndices(tracked)
+}
+
+// detachFromIndices removes the policy from those of the passed indices that still exist and returns the indices
+// it could not be removed from
+func (r *IsmPolicyReconciler) detachFromIndices(indices []string) ([]string, error) {
+	// Only pass existing indices, removing the policy fails for the whole batch if one index is missing.
+	// Listing the indices by name would fail the same way, so all indices are listed.
+	existing, err := services.ListIndicesMatching(r.ctx, r.osClient, []string{"*"})
+	if err != nil {
+		return nil, err
+	}
+	exists := map[string]bool{}
+	for _, index := range existing {
+		exists[index] = true
+	}
+	var toDetach []string
+	for _, index := range indices {
+		if exists[index] {
+			toDetach = append(toDetach, index)
+		}
+	}
+	if len(toDetach) == 0 {
+		return nil, nil
+	}
+
+	failed, err := services.RemoveISMPolicyFromIndices(r.ctx, r.osClient, toDetach)
+	if err != nil {
+		return nil, err
+	}
+	var remaining []string
+	for _, index := range toDetach {
+		if _, ok := failed[index]; ok {
+			remaining = append(remaining, index)
+		}
+	}
+	if len(failed) > 0 {
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, fmt.Sprintf("policy could not be detached from %d indices: %s", len(failed), describeFailedIndices(failed)))
+	}
+	return remaining, nil
+}
+
+func (r *IsmPolicyReconciler) setAttachedIndices(indices []string) error {
+	if !pointer.BoolDeref(r.updateStatus, true) {
+		return nil
+	}
+	return r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+		instance := object.(*opsterv1.OpenSearchISMPolicy)
+		instance.Status.AttachedIndices = indices
+	})
+}
+
+// describeFailedIndices returns a short description of the failed indices for events
+func describeFailedIndices(failed map[string]string) string {
+	indices := make([]string, 0, len(failed))
+	for index := range failed {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	return fmt.Sprintf("%s (%s)", strings.Join(indices, ", "), failed[indices[0]])
+}
+
 func (r *IsmPolicyReconciler) CreateISMPolicyRequest() (*requests.Policy, error) {
 	policy := requests.ISMPolicy{
 		DefaultState: r.instance.Spec.DefaultState,
@@ -534,7 +689,10 @@ func (r *IsmPolicyReconciler) Delete() error {
 		// If the opensearch cluster doesn't exist, we don't need to delete anything
 		return nil
 	}
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
 	if err != nil {
 		return err
 	}
@@ -543,6 +701,11 @@ func (r *IsmPolicyReconciler) Delete() error {
 	if r.instance.Spec.PolicyID == "" {
 		policyId = r.instance.Name
 	}
+	if len(r.instance.Status.AttachedIndices) > 0 {
+		if _, err := r.detachFromIndices(r.instance.Status.AttachedIndices); err != nil {
+			return err
+		}
+	}
 	err = services.DeleteISMPolicy(r.ctx, r.osClient, policyId)
 	if err != nil {
 		return err
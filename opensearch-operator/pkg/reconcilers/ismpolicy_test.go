@@ -3,12 +3,18 @@ package reconcilers
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
 	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -18,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -134,6 +141,30 @@ var _ = Describe("ism policy reconciler", func() {
 		})
 	})
 
+	When("cluster is frozen", func() {
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(1)
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Annotations = map[string]string{helpers.FreezeAnnotation: "true"}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+		})
+		It("should defer changes without calling OpenSearch", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s %s", opensearchDeferred, opensearchClusterFrozen)}))
+			Expect(transport.GetTotalCallCount()).To(BeZero())
+		})
+	})
+
 	Context("cluster is ready", func() {
 		extraContextCalls := 1
 		BeforeEach(func() {
@@ -331,6 +362,98 @@ var _ = Describe("ism policy reconciler", func() {
 					Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s policy updated in opensearch", opensearchAPIUpdated)))
 				})
 			})
+			When("policy is applied to existing indices", func() {
+				var (
+					addPaths  []string
+					addBodies []string
+				)
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(3)
+					addPaths = nil
+					addBodies = nil
+					instance.Spec.ApplyToExistingIndices = true
+					instance.Spec.ISMTemplate = &opsterv1.ISMTemplate{IndexPatterns: []string{"logs-*"}}
+					instance.Status.AttachedIndices = []string{"logs-00"}
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf(
+							"https://%s.%s.svc.cluster.local:9200/_plugins/_ism/policies/%s",
+							cluster.Spec.General.ServiceName,
+							cluster.Namespace,
+							instance.Name,
+						),
+						httpmock.NewJsonResponderOrPanic(200, responses.GetISMPoliciesResponse{
+							Policy: requests.ISMPolicy{
+								ISMTemplate: &requests.ISMTemplate{IndexPatterns: []string{"logs-*"}},
+							},
+							SequenceNumber: seqno,
+							PrimaryTerm:    seqno,
+						}).Once(failMessage),
+					)
+					var indices []responses.CatIndicesResponse
+					for i := 0; i < 60; i++ {
+						indices = append(indices, responses.CatIndicesResponse{Index: fmt.Sprintf("logs-%02d", i)})
+					}
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/_cat/indices/logs-\*\?`),
+						httpmock.NewJsonResponderOrPanic(200, indices).Once(failMessage),
+					)
+					transport.RegisterRegexpResponder(
+						http.MethodPost,
+						regexp.MustCompile(`/_plugins/_ism/add/`),
+						func(req *http.Request) (*http.Response, error) {
+							body, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							addPaths = append(addPaths, req.URL.Path)
+							addBodies = append(addBodies, string(body))
+							response := responses.ISMChangeIndicesResponse{}
+							if strings.Contains(req.URL.Path, "logs-59") {
+								response.Failures = true
+								response.FailedIndices = []responses.ISMFailedIndexInfo{{IndexName: "logs-59", Reason: "This index already has a policy"}}
+							}
+							return httpmock.NewJsonResponse(200, response)
+						},
+					)
+				})
+
+				It("should attach the policy in batches and track the attached indices", func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Normal %s policy attached to 58 existing indices", opensearchAPIUpdated),
+						fmt.Sprintf("Warning %s policy could not be attached to 1 existing indices: logs-59 (This index already has a policy)", opensearchAPIError),
+					}))
+
+					Expect(addPaths).To(HaveLen(2))
+					Expect(strings.Split(strings.TrimPrefix(addPaths[0], "/_plugins/_ism/add/"), ",")).To(HaveLen(services.ISMIndexBatchSize))
+					Expect(addPaths[0]).NotTo(ContainSubstring("logs-00"))
+					Expect(strings.Split(strings.TrimPrefix(addPaths[1], "/_plugins/_ism/add/"), ",")).To(HaveLen(9))
+					Expect(addBodies[0]).To(MatchJSON(`{"policy_id":"test-policy"}`))
+
+					Expect(instance.Status.AttachedIndices).To(HaveLen(59))
+					Expect(instance.Status.AttachedIndices).To(ContainElement("logs-00"))
+					Expect(instance.Status.AttachedIndices).NotTo(ContainElement("logs-59"))
+				})
+			})
 			When("policy doesn't exist in opensearch", func() {
 				BeforeEach(func() {
 					recorder = record.NewFakeRecorder(1)
@@ -452,6 +575,68 @@ var _ = Describe("ism policy reconciler", func() {
 					Expect(reconciler.Delete()).To(Succeed())
 				})
 			})
+			When("policy is attached to existing indices", func() {
+				var removePath string
+
+				BeforeEach(func() {
+					instance.Status.AttachedIndices = []string{"logs-01", "logs-02", "logs-deleted"}
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						fmt.Sprintf(
+							"https://%s.%s.svc.cluster.local:9200/",
+							cluster.Spec.General.ServiceName,
+							cluster.Namespace,
+						),
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						fmt.Sprintf(
+							"https://%s.%s.svc.cluster.local:9200/",
+							cluster.Spec.General.ServiceName,
+							cluster.Namespace,
+						),
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterRegexpResponder(
+						http.MethodGet,
+						regexp.MustCompile(`/_cat/indices/\*\?`),
+						httpmock.NewJsonResponderOrPanic(200, []responses.CatIndicesResponse{
+							{Index: "logs-01"}, {Index: "logs-02"}, {Index: "other"},
+						}).Once(failMessage),
+					)
+					transport.RegisterRegexpResponder(
+						http.MethodPost,
+						regexp.MustCompile(`/_plugins/_ism/remove/`),
+						func(req *http.Request) (*http.Response, error) {
+							removePath = req.URL.Path
+							return httpmock.NewJsonResponse(200, responses.ISMChangeIndicesResponse{UpdatedIndices: 2})
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						fmt.Sprintf(
+							"https://%s.%s.svc.cluster.local:9200/_plugins/_ism/policies/%s",
+							cluster.Spec.General.ServiceName,
+							cluster.Namespace,
+							instance.Name,
+						),
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				It("should detach the policy from the existing indices before deleting it", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(removePath).To(Equal("/_plugins/_ism/remove/logs-01,logs-02"))
+					Expect(transport.GetCallCountInfo()[fmt.Sprintf(
+						"DELETE https://%s.%s.svc.cluster.local:9200/_plugins/_ism/policies/%s",
+						cluster.Spec.General.ServiceName,
+						cluster.Namespace,
+						instance.Name,
+					)]).To(Equal(1))
+				})
+			})
 			When("policy does exist", func() {
 				BeforeEach(func() {
 					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
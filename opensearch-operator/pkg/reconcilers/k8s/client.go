@@ -33,16 +33,28 @@ type K8sClient interface {
 	CreateDeployment(deployment *appsv1.Deployment) (*ctrl.Result, error)
 	DeleteDeployment(deployment *appsv1.Deployment, orphan bool) error
 	GetService(name, namespace string) (corev1.Service, error)
+	GetEndpoints(name, namespace string) (corev1.Endpoints, error)
 	CreateService(svc *corev1.Service) (*ctrl.Result, error)
 	GetOpenSearchCluster(name, namespace string) (opsterv1.OpenSearchCluster, error)
+	ListOpenSearchClusters(listOptions ...client.ListOption) (opsterv1.OpenSearchClusterList, error)
+	ListOpensearchComponentTemplates(listOptions ...client.ListOption) (opsterv1.OpensearchComponentTemplateList, error)
+	DeleteOpensearchComponentTemplate(template *opsterv1.OpensearchComponentTemplate) error
+	ListOpensearchIndexTemplates(listOptions ...client.ListOption) (opsterv1.OpensearchIndexTemplateList, error)
+	GetOpensearchReconcileLog(name, namespace string) (opsterv1.OpensearchReconcileLog, error)
+	GetOpensearchTenant(name, namespace string) (opsterv1.OpensearchTenant, error)
+	GetOpensearchRole(name, namespace string) (opsterv1.OpensearchRole, error)
+	GetOpensearchSecurityAnalyticsRule(name, namespace string) (opsterv1.OpensearchSecurityAnalyticsRule, error)
 	UpdateOpenSearchClusterStatus(key client.ObjectKey, f func(*opsterv1.OpenSearchCluster)) error
 	UdateObjectStatus(instance client.Object, f func(client.Object)) error
 	ReconcileResource(runtime.Object, reconciler.DesiredState) (*ctrl.Result, error)
 	GetPod(name, namespace string) (corev1.Pod, error)
 	DeletePod(pod *corev1.Pod) error
+	UpdatePod(pod *corev1.Pod) error
+	GetNode(name string) (corev1.Node, error)
 	ListPods(listOptions *client.ListOptions) (corev1.PodList, error)
 	GetPVC(name, namespace string) (corev1.PersistentVolumeClaim, error)
 	UpdatePVC(pvc *corev1.PersistentVolumeClaim) error
+	DeletePVC(pvc *corev1.PersistentVolumeClaim) error
 	ListPVCs(listOptions *client.ListOptions) (corev1.PersistentVolumeClaimList, error)
 	Scheme() *runtime.Scheme
 	Context() context.Context
@@ -161,6 +173,12 @@ func (c K8sClientImpl) GetService(name, namespace string) (corev1.Service, error
 	return svc, err
 }
 
+func (c K8sClientImpl) GetEndpoints(name, namespace string) (corev1.Endpoints, error) {
+	endpoints := corev1.Endpoints{}
+	err := c.Get(c.ctx, client.ObjectKey{Name: name, Namespace: namespace}, &endpoints)
+	return endpoints, err
+}
+
 func (c K8sClientImpl) CreateService(svc *corev1.Service) (*ctrl.Result, error) {
 	return c.ReconcileResource(svc, reconciler.StatePresent)
 }
@@ -171,6 +189,53 @@ func (c K8sClientImpl) GetOpenSearchCluster(name, namespace string) (opsterv1.Op
 	return cluster, err
 }
 
+func (c K8sClientImpl) ListOpenSearchClusters(listOptions ...client.ListOption) (opsterv1.OpenSearchClusterList, error) {
+	list := opsterv1.OpenSearchClusterList{}
+	err := c.List(c.ctx, &list, listOptions...)
+	return list, err
+}
+
+func (c K8sClientImpl) ListOpensearchComponentTemplates(listOptions ...client.ListOption) (opsterv1.OpensearchComponentTemplateList, error) {
+	list := opsterv1.OpensearchComponentTemplateList{}
+	err := c.List(c.ctx, &list, listOptions...)
+	return list, err
+}
+
+// DeleteOpensearchComponentTemplate deletes the component template only if it was not replaced by one with the same name
+func (c K8sClientImpl) DeleteOpensearchComponentTemplate(template *opsterv1.OpensearchComponentTemplate) error {
+	return c.Delete(c.ctx, template, client.Preconditions{UID: &template.UID})
+}
+
+func (c K8sClientImpl) ListOpensearchIndexTemplates(listOptions ...client.ListOption) (opsterv1.OpensearchIndexTemplateList, error) {
+	list := opsterv1.OpensearchIndexTemplateList{}
+	err := c.List(c.ctx, &list, listOptions...)
+	return list, err
+}
+
+func (c K8sClientImpl) GetOpensearchReconcileLog(name, namespace string) (opsterv1.OpensearchReconcileLog, error) {
+	reconcileLog := opsterv1.OpensearchReconcileLog{}
+	err := c.Get(c.ctx, client.ObjectKey{Name: name, Namespace: namespace}, &reconcileLog)
+	return reconcileLog, err
+}
+
+func (c K8sClientImpl) GetOpensearchTenant(name, namespace string) (opsterv1.OpensearchTenant, error) {
+	tenant := opsterv1.OpensearchTenant{}
+	err := c.Get(c.ctx, client.ObjectKey{Name: name, Namespace: namespace}, &tenant)
+	return tenant, err
+}
+
+func (c K8sClientImpl) GetOpensearchRole(name, namespace string) (opsterv1.OpensearchRole, error) {
+	role := opsterv1.OpensearchRole{}
+	err := c.Get(c.ctx, client.ObjectKey{Name: name, Namespace: namespace}, &role)
+	return role, err
+}
+
+func (c K8sClientImpl) GetOpensearchSecurityAnalyticsRule(name, namespace string) (opsterv1.OpensearchSecurityAnalyticsRule, error) {
+	rule := opsterv1.OpensearchSecurityAnalyticsRule{}
+	err := c.Get(c.ctx, client.ObjectKey{Name: name, Namespace: namespace}, &rule)
+	return rule, err
+}
+
 func (c K8sClientImpl) UpdateOpenSearchClusterStatus(key client.ObjectKey, f func(*opsterv1.OpenSearchCluster)) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		instance := opsterv1.OpenSearchCluster{}
@@ -208,6 +273,16 @@ func (c K8sClientImpl) DeletePod(pod *corev1.Pod) error {
 	return c.Delete(c.ctx, pod)
 }
 
+func (c K8sClientImpl) UpdatePod(pod *corev1.Pod) error {
+	return c.Update(c.ctx, pod)
+}
+
+func (c K8sClientImpl) GetNode(name string) (corev1.Node, error) {
+	node := corev1.Node{}
+	err := c.Get(c.ctx, client.ObjectKey{Name: name}, &node)
+	return node, err
+}
+
 func (c K8sClientImpl) ListPods(listOptions *client.ListOptions) (corev1.PodList, error) {
 	list := corev1.PodList{}
 	err := c.List(c.ctx, &list, listOptions)
@@ -224,6 +299,10 @@ func (c K8sClientImpl) UpdatePVC(pvc *corev1.PersistentVolumeClaim) error {
 	return c.Update(c.ctx, pvc)
 }
 
+func (c K8sClientImpl) DeletePVC(pvc *corev1.PersistentVolumeClaim) error {
+	return c.Delete(c.ctx, pvc)
+}
+
 func (c K8sClientImpl) ListPVCs(listOptions *client.ListOptions) (corev1.PersistentVolumeClaimList, error) {
 	list := corev1.PersistentVolumeClaimList{}
 	err := c.List(c.ctx, &list, listOptions)
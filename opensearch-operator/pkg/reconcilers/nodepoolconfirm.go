@@ -0,0 +1,81 @@
+package reconcilers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+)
+
+const (
+	// A written component template is not returned through the service of every node pool yet
+	opensearchUnconfirmedWrite = "OpensearchComponentTemplateUnconfirmedWrite"
+)
+
+// WithNodePoolConfirmation sets how often node pools not returning a written component template are asked again and
+// the interval in between
+func WithNodePoolConfirmation(retries int, interval time.Duration) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.nodePoolConfirmRetries = retries
+		o.nodePoolConfirmInterval = interval
+	}
+}
+
+// confirmOnNodePools checks that the component template is returned through the service of every node pool if the
+// spec asks for it. During a network partition some coordinating nodes may still serve an outdated cluster state, so
+// a write reaching the cluster manager through one of them is not yet visible through the others. It returns the
+// reason the write is not confirmed, empty once all node pools return the template.
+func (r *ComponentTemplateReconciler) confirmOnNodePools(templateName string, template requests.ComponentTemplate) string {
+	urls := util.NodePoolURLs(r.cluster)
+	// A single node pool is reached through the service of the cluster as well
+	if !r.instance.Spec.ConfirmOnAllNodePools || serverlessEndpoint(r.cluster) || len(urls) < 2 {
+		return ""
+	}
+	clients := map[string]*services.OsClusterClient{}
+confirm:
+	for attempt := 0; ; attempt++ {
+		for component, url := range urls {
+			if clients[component] == nil {
+				client, err := r.createClient(url)
+				if err != nil {
+					r.logger.Info(fmt.Sprintf("node pool %s cannot be reached", component), "error", err.Error())
+					continue
+				}
+				clients[component] = client
+			}
+			live, err := services.GetComponentTemplate(r.ctx, clients[component], templateName)
+			if err != nil {
+				r.logger.Info(fmt.Sprintf("failed to get component template %s through node pool %s", templateName, component), "error", err.Error())
+				continue
+			}
+			if live != nil && helpers.ComponentTemplatesEqual(template, *live) {
+				delete(urls, component)
+			}
+		}
+		if len(urls) == 0 {
+			return ""
+		}
+		if attempt >= r.nodePoolConfirmRetries {
+			break
+		}
+		select {
+		case <-r.ctx.Done():
+			break confirm
+		case <-time.After(r.nodePoolConfirmInterval):
+		}
+	}
+
+	pending := make([]string, 0, len(urls))
+	for component := range urls {
+		pending = append(pending, component)
+	}
+	sort.Strings(pending)
+	reason := fmt.Sprintf("component template is not returned through the node pools %s yet, the write is confirmed again later", strings.Join(pending, ", "))
+	r.recorder.Event(r.instance, "Warning", opensearchUnconfirmedWrite, reason)
+	return reason
+}
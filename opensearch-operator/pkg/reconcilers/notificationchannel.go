@@ -0,0 +1,332 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchNotificationChannelExists     = "notification channel already exists in OpenSearch; not modifying"
+	opensearchNotificationChannelIDMismatch = "OpensearchNotificationChannelIDMismatch"
+	opensearchInvalidNotificationChannel    = "OpensearchInvalidNotificationChannel"
+)
+
+type NotificationChannelReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchNotificationChannel
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewNotificationChannelReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchNotificationChannel,
+	opts ...ReconcilerOption,
+) *NotificationChannelReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &NotificationChannelReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "notificationchannel"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "notificationchannel"),
+	}
+}
+
+func (r *NotificationChannelReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var channelID string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchNotificationChannel)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchNotificationChannelError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchNotificationChannelPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchNotificationChannelDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchNotificationChannelCreated
+				instance.Status.ChannelID = channelID
+			}
+			if reason == opensearchNotificationChannelExists {
+				instance.Status.State = opsterv1.OpensearchNotificationChannelIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a notification channel refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchNotificationChannel)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	channelID = r.instance.Name
+	if r.instance.Spec.ChannelID != "" {
+		channelID = r.instance.Spec.ChannelID
+	}
+
+	// Check notification channel state to make sure we don't touch preexisting notification channels
+	if r.instance.Status.ExistingChannel == nil {
+		var exists bool
+		exists, err = services.NotificationChannelExists(r.ctx, r.osClient, channelID)
+		if err != nil {
+			reason = "failed to get notification channel status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchNotificationChannel)
+				instance.Status.ExistingChannel = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If notification channel is existing do nothing
+	if *r.instance.Status.ExistingChannel {
+		reason = opensearchNotificationChannelExists
+		return
+	}
+
+	// the channel id is immutable, so check the old id (r.instance.Status.ChannelID) against the new
+	if r.instance.Status.ChannelID != "" && channelID != r.instance.Status.ChannelID {
+		reason = "cannot change the notification channel id"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchNotificationChannelIDMismatch, reason)
+		return
+	}
+
+	name := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		name = r.instance.Spec.Name
+	}
+
+	// rewrite the CRD format to the gateway format, reading the credentials from the secrets
+	resource, err := helpers.TranslateNotificationChannelToRequest(r.instance.Spec, name, r.secretValue)
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidNotificationChannel, reason)
+		return
+	}
+
+	existing, err := services.GetNotificationChannel(r.ctx, r.osClient, channelID)
+	if err != nil && !errors.Is(err, services.ErrNotFound) {
+		reason = "failed to get notification channel from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if errors.Is(err, services.ErrNotFound) {
+		r.logger.V(1).Info(fmt.Sprintf("notification channel %s not found, creating", channelID))
+		err = services.CreateNotificationChannel(r.ctx, r.osClient, channelID, resource)
+	} else {
+		var equal bool
+		equal, err = helpers.NotificationChannelsEqual(resource, existing)
+		if err != nil {
+			reason = "failed to compare the notification channel with the one in OpenSearch"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if equal {
+			r.logger.V(1).Info(fmt.Sprintf("notification channel %s is in sync", r.instance.Name))
+			result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+			return
+		}
+		err = services.UpdateNotificationChannel(r.ctx, r.osClient, channelID, resource)
+	}
+	if err != nil {
+		reason = "failed to update notification channel with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "notification channel updated in opensearch")
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+// secretValue reads a key of a secret in the namespace of the channel
+func (r *NotificationChannelReconciler) secretValue(selector corev1.SecretKeySelector) (string, error) {
+	secret, err := r.client.GetSecret(selector.Name, r.instance.Namespace)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %w", selector.Name, err)
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s does not exist in secret %s", selector.Key, selector.Name)
+	}
+	return string(value), nil
+}
+
+func (r *NotificationChannelReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingChannel == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingChannel {
+		r.logger.Info("notification channel was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	channelID := r.instance.Name
+	if r.instance.Spec.ChannelID != "" {
+		channelID = r.instance.Spec.ChannelID
+	}
+
+	exist, err := services.NotificationChannelExists(r.ctx, r.osClient, channelID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		r.logger.V(1).Info("notification channel already deleted from opensearch")
+		return nil
+	}
+
+	return services.DeleteNotificationChannel(r.ctx, r.osClient, channelID)
+}
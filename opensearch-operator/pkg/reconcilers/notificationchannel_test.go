@@ -0,0 +1,469 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("notificationchannel reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *NotificationChannelReconciler
+		instance   *opsterv1.OpensearchNotificationChannel
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		channelUrl string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchNotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-notificationchannel",
+				Namespace: "test-notificationchannel",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchNotificationChannelSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				ChannelID: "oncall",
+				Name:      "On-call",
+				Slack: &opsterv1.SlackChannel{URLFrom: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "slack"},
+					Key:                  "url",
+				}},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-notificationchannel",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		channelUrl = fmt.Sprintf("%s_plugins/_notifications/configs/oncall", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &NotificationChannelReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a notification channel refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					channelUrl,
+					httpmock.NewStringResponder(200, `{"config_list":[{"config_id":"oncall","config":{"name":"On-call"}}]}`).Once(failMessage),
+				)
+			})
+
+			It("should record that the notification channel exists", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingChannel = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingChannel = pointer.Bool(false)
+				mockClient.EXPECT().GetSecret("slack", "test-notificationchannel").Return(corev1.Secret{
+					Data: map[string][]byte{"url": []byte("https://hooks.slack.com/services/T0/B0/token")},
+				}, nil).Maybe()
+			})
+
+			When("notification channel exists in opensearch and is the same", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						channelUrl,
+						httpmock.NewStringResponder(200, `{"config_list":[{
+							"config_id": "oncall",
+							"last_updated_time_ms": 1700000000000,
+							"created_time_ms": 1700000000000,
+							"config": {
+								"name": "On-call",
+								"description": "",
+								"config_type": "slack",
+								"is_enabled": true,
+								"slack": {"url": "https://hooks.slack.com/services/T0/B0/token"}
+							}
+						}],"total_hits":1}`).Once(failMessage),
+					)
+				})
+
+				It("should do nothing", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				})
+			})
+
+			When("the secret changed since the notification channel was created", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						channelUrl,
+						httpmock.NewStringResponder(200, `{"config_list":[{
+							"config_id": "oncall",
+							"config": {
+								"name": "On-call",
+								"config_type": "slack",
+								"is_enabled": true,
+								"slack": {"url": "https://hooks.slack.com/services/T0/B0/old-token"}
+							}
+						}],"total_hits":1}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						channelUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"config_id":"oncall"}`), nil
+						},
+					)
+				})
+
+				It("should update the notification channel", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						// Confirm all responders have been called
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s notification channel updated in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{"config":{
+						"name": "On-call",
+						"config_type": "slack",
+						"is_enabled": true,
+						"slack": {"url": "https://hooks.slack.com/services/T0/B0/token"}
+					}}`))
+				})
+			})
+
+			When("notification channel does not exist in opensearch", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						channelUrl,
+						httpmock.NewStringResponder(404, "{}").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						clusterUrl+"_plugins/_notifications/configs",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"config_id":"oncall"}`), nil
+						},
+					)
+				})
+
+				It("should create the notification channel with the id", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s notification channel updated in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{"config_id":"oncall","config":{
+						"name": "On-call",
+						"config_type": "slack",
+						"is_enabled": true,
+						"slack": {"url": "https://hooks.slack.com/services/T0/B0/token"}
+					}}`))
+				})
+			})
+
+			When("the secret is missing the key", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Slack.URLFrom.Key = "webhook"
+				})
+
+				It("should fail without calling OpenSearch", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf(
+						"Warning %s key webhook does not exist in secret slack", opensearchInvalidNotificationChannel,
+					)}))
+				})
+			})
+
+			When("the id of the notification channel has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.ChannelID = "oncall"
+					instance.Spec.ChannelID = "new-oncall"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the notification channel id", opensearchNotificationChannelIDMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingChannel = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingChannel = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			Context("cluster exists", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				When("notification channel does not exist", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							channelUrl,
+							httpmock.NewStringResponder(404, "{}").Once(failMessage),
+						)
+					})
+
+					It("should do nothing and exit", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+
+				When("notification channel does exist", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							channelUrl,
+							httpmock.NewStringResponder(200, `{"config_list":[{"config_id":"oncall","config":{"name":"On-call"}}]}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodDelete,
+							channelUrl,
+							httpmock.NewStringResponder(200, `{"delete_response_list":{"oncall":"OK"}}`).Once(failMessage),
+						)
+					})
+
+					It("should delete the notification channel", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+			})
+		})
+	})
+})
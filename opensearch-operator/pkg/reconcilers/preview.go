@@ -0,0 +1,234 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ComponentTemplatePreviewPath is the path the component template preview endpoint is served at
+const ComponentTemplatePreviewPath = "/preview/componenttemplate"
+
+// maxPreviewRequestSize limits the size of the body accepted by the preview endpoint
+const maxPreviewRequestSize = 1 << 20
+
+var (
+	errPreviewClusterNotFound   = errors.New("opensearch cluster does not exist")
+	errPreviewClusterNotRunning = errors.New("opensearch cluster is not running")
+)
+
+// ComponentTemplatePreviewRequest is a proposed component template to be validated against a cluster
+type ComponentTemplatePreviewRequest struct {
+	// Namespace of the referenced OpenSearch cluster
+	Namespace string                                   `json:"namespace"`
+	Spec      opsterv1.OpensearchComponentTemplateSpec `json:"spec"`
+}
+
+// ComponentTemplatePreview is the result of validating a proposed component template without applying it
+type ComponentTemplatePreview struct {
+	Name string `json:"name"`
+	// Valid is true if neither the static validation nor the simulation in OpenSearch failed
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	// Exists is true if a component template of the same name exists in OpenSearch
+	Exists bool `json:"exists"`
+	// Changed is true if applying the template would change OpenSearch
+	Changed bool `json:"changed"`
+	// Diff lists the changes applying the template would make to the live component template
+	Diff []helpers.JSONChange `json:"diff,omitempty"`
+}
+
+type componentTemplatePreviewHandler struct {
+	ReconcilerOptions
+	k8sClient func(ctx context.Context) k8s.K8sClient
+}
+
+// NewComponentTemplatePreviewHandler returns a handler validating component templates posted as
+// ComponentTemplatePreviewRequest against the referenced cluster, without creating an OpensearchComponentTemplate.
+// The handler does no authorization itself and must only be served behind an authorizing proxy.
+func NewComponentTemplatePreviewHandler(k8sClient client.Client, opts ...ReconcilerOption) http.Handler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &componentTemplatePreviewHandler{
+		ReconcilerOptions: options,
+		k8sClient: func(ctx context.Context) k8s.K8sClient {
+			return k8s.NewK8sClient(k8sClient, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "preview")))
+		},
+	}
+}
+
+func (h *componentTemplatePreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writePreviewError(w, http.StatusMethodNotAllowed, "only POST is supported")
+		return
+	}
+
+	var request ComponentTemplatePreviewRequest
+	decoder := json.NewDecoder(io.LimitReader(req.Body, maxPreviewRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writePreviewError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return
+	}
+	if request.Namespace == "" || request.Spec.OpensearchRef.Name == "" || request.Spec.Name == "" {
+		writePreviewError(w, http.StatusBadRequest, "namespace, spec.opensearchCluster.name and spec.name are required")
+		return
+	}
+
+	ctx := req.Context()
+	r := &ComponentTemplateReconciler{
+		client:            h.k8sClient(ctx),
+		ReconcilerOptions: h.ReconcilerOptions,
+		ctx:               ctx,
+		recorder:          &previewRecorder{},
+		instance: &opsterv1.OpensearchComponentTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: request.Spec.Name, Namespace: request.Namespace},
+			Spec:       request.Spec,
+		},
+		logger: log.FromContext(ctx).WithValues("reconciler", "componenttemplate-preview"),
+	}
+
+	preview, err := r.Preview()
+	switch {
+	case errors.Is(err, errPreviewClusterNotFound):
+		writePreviewError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, errPreviewClusterNotRunning):
+		writePreviewError(w, http.StatusServiceUnavailable, err.Error())
+	case err != nil:
+		r.logger.Error(err, "failed to preview component template")
+		writePreviewError(w, http.StatusBadGateway, err.Error())
+	default:
+		writePreviewResponse(w, http.StatusOK, preview)
+	}
+}
+
+func writePreviewError(w http.ResponseWriter, status int, message string) {
+	writePreviewResponse(w, status, map[string]string{"error": message})
+}
+
+func writePreviewResponse(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// Preview runs the validation of a reconcile, simulates the template in OpenSearch and compares it to the
+// live component template, without changing anything. Failed checks are reported in the preview, the
+// returned error is only set if the preview could not be done.
+func (r *ComponentTemplateReconciler) Preview() (ComponentTemplatePreview, error) {
+	preview := ComponentTemplatePreview{Name: r.instance.Spec.Name}
+
+	var err error
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return preview, err
+	}
+	if r.cluster == nil {
+		return preview, errPreviewClusterNotFound
+	}
+	if !serverlessEndpoint(r.cluster) && r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		return preview, errPreviewClusterNotRunning
+	}
+
+	r.osClient, err = r.createClient(util.OpensearchClusterURL(r.cluster), services.WithETagCache(r.etagCache))
+	if err != nil {
+		return preview, err
+	}
+
+	// The checks report warnings as events, collect them instead of emitting them
+	recorder, ok := r.recorder.(*previewRecorder)
+	if !ok {
+		recorder = &previewRecorder{}
+		r.recorder = recorder
+	}
+	check := func(f func() (string, error)) {
+		recorder.warnings = nil
+		if reason, err := f(); err != nil {
+			preview.Errors = append(preview.Errors, reason)
+			return
+		}
+		preview.Warnings = append(preview.Warnings, recorder.warnings...)
+	}
+
+	resource := helpers.TranslateComponentTemplateToRequest(r.instance.Spec)
+	check(func() (string, error) { return r.checkIndexCodec(r.instance.Spec, &resource) })
+	check(func() (string, error) { return r.checkTimeSettings(resource) })
+	check(func() (string, error) { return r.checkRoutingShards(resource) })
+	check(func() (string, error) { return r.checkUnknownSettings(r.instance.Spec, resource) })
+	check(func() (string, error) { return r.checkStorePreload(resource) })
+	check(func() (string, error) { return r.checkAutoCreate(resource) })
+	check(func() (string, error) { return r.checkIndexBlocks(r.instance, resource) })
+	check(func() (string, error) { return r.checkMappingDepth(resource) })
+	check(func() (string, error) { return r.checkIndexSort(resource) })
+	check(func() (string, error) { return r.checkTier(r.instance.Spec, &resource) })
+	check(func() (string, error) { return r.checkTierNodes(r.instance.Spec) })
+	check(func() (string, error) { return r.checkReplicaPolicy(r.instance.Spec, &resource) })
+	check(func() (string, error) { return r.checkFieldSecurity(r.instance.Spec) })
+	check(func() (string, error) { return r.checkAnalysisPlugins(r.instance.Spec, resource) })
+	check(func() (string, error) { return r.checkAliasNames(resource) })
+	check(func() (string, error) {
+		r.checkFieldCount(resource)
+		return "", nil
+	})
+
+	err = services.SimulateComponentTemplate(r.ctx, r.osClient, preview.Name, resource)
+	if errors.Is(err, services.ErrForbidden) {
+		preview.Errors = append(preview.Errors, fmt.Sprintf("operator user is not authorized to simulate index templates: %s", err))
+	} else if err != nil {
+		preview.Errors = append(preview.Errors, err.Error())
+	}
+
+	live, err := services.GetComponentTemplate(r.ctx, r.osClient, preview.Name)
+	if err != nil {
+		return preview, err
+	}
+	preview.Exists = live != nil
+	preview.Changed = live == nil || !helpers.ComponentTemplatesEqual(resource, *live)
+	if preview.Changed {
+		if preview.Diff, err = componentTemplateDiff(resource, live, r.redaction); err != nil {
+			return preview, err
+		}
+	}
+
+	preview.Valid = len(preview.Errors) == 0
+	return preview, nil
+}
+
+// previewRecorder collects the messages of warning events instead of emitting them
+type previewRecorder struct {
+	warnings []string
+}
+
+func (p *previewRecorder) Event(_ runtime.Object, eventtype, _, message string) {
+	if eventtype == "Warning" {
+		p.warnings = append(p.warnings, message)
+	}
+}
+
+func (p *previewRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	p.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (p *previewRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	p.Eventf(object, eventtype, reason, messageFmt, args...)
+}
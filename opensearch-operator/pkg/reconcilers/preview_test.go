@@ -0,0 +1,196 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	k8sclient "github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("component template preview endpoint", func() {
+	var (
+		transport  *httpmock.MockTransport
+		mockClient *k8s.MockK8sClient
+		handler    http.Handler
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		body       string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-preview",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+					Version:     "2.8.0",
+				},
+			},
+			Status: opsterv1.ClusterStatus{
+				Phase: opsterv1.PhaseRunning,
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		body = `{"namespace":"test-preview","spec":{"opensearchCluster":{"name":"test-cluster"},"name":"my-template",` +
+			`"template":{"settings":{"index":{"number_of_shards":"2"}}}}}`
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport))
+		handler = &componentTemplatePreviewHandler{
+			ReconcilerOptions: options,
+			k8sClient: func(context.Context) k8sclient.K8sClient {
+				return mockClient
+			},
+		}
+	})
+
+	post := func() (int, map[string]interface{}) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, ComponentTemplatePreviewPath, strings.NewReader(body)))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		response := map[string]interface{}{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		return recorder.Code, response
+	}
+
+	It("should only accept POST requests", func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ComponentTemplatePreviewPath, nil))
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(recorder.Header().Get("Allow")).To(Equal(http.MethodPost))
+	})
+
+	When("the request is missing the cluster reference", func() {
+		BeforeEach(func() {
+			body = `{"namespace":"test-preview","spec":{"name":"my-template"}}`
+		})
+
+		It("should reject it", func() {
+			code, response := post()
+			Expect(code).To(Equal(http.StatusBadRequest))
+			Expect(response).To(HaveKey("error"))
+		})
+	})
+
+	When("the cluster does not exist", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+		})
+
+		It("should respond with not found", func() {
+			code, response := post()
+			Expect(code).To(Equal(http.StatusNotFound))
+			Expect(response).To(Equal(map[string]interface{}{"error": errPreviewClusterNotFound.Error()}))
+		})
+	})
+
+	Context("the cluster is running", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("the template does not exist and is valid", func() {
+			BeforeEach(func() {
+				transport.RegisterResponder(
+					http.MethodPost,
+					fmt.Sprintf("%s_index_template/_simulate", clusterUrl),
+					httpmock.NewStringResponder(200, "{}").Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+					httpmock.NewStringResponder(404, "does not exist").Once(failMessage),
+				)
+			})
+
+			It("should report the template as valid and list the changes", func() {
+				code, response := post()
+				Expect(code).To(Equal(http.StatusOK))
+				Expect(response).To(Equal(map[string]interface{}{
+					"name":    "my-template",
+					"valid":   true,
+					"exists":  false,
+					"changed": true,
+					"diff": []interface{}{
+						map[string]interface{}{
+							"op":   helpers.JSONChangeAdd,
+							"path": "template",
+							"to":   map[string]interface{}{"settings": map[string]interface{}{"index": map[string]interface{}{"number_of_shards": "2"}}},
+						},
+					},
+				}))
+			})
+		})
+
+		When("the template is unchanged but fails the simulation", func() {
+			BeforeEach(func() {
+				transport.RegisterResponder(
+					http.MethodPost,
+					fmt.Sprintf("%s_index_template/_simulate", clusterUrl),
+					httpmock.NewStringResponder(400, `{"error":"unknown setting"}`).Once(failMessage),
+				)
+				live := responses.GetComponentTemplatesResponse{
+					ComponentTemplates: []responses.ComponentTemplate{{
+						Name: "my-template",
+						ComponentTemplate: requests.ComponentTemplate{
+							Template: requests.Index{
+								Settings: &apiextensionsv1.JSON{Raw: []byte(`{"index":{"number_of_shards":"2"}}`)},
+							},
+						},
+					}},
+				}
+				transport.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf("%s_component_template/my-template", clusterUrl),
+					httpmock.NewJsonResponderOrPanic(200, live).Once(failMessage),
+				)
+			})
+
+			It("should report the error and no changes", func() {
+				code, response := post()
+				Expect(code).To(Equal(http.StatusOK))
+				Expect(response).To(Equal(map[string]interface{}{
+					"name":    "my-template",
+					"valid":   false,
+					"errors":  []interface{}{`component template is not valid: [400 Bad Request] {"error":"unknown setting"}`},
+					"exists":  true,
+					"changed": false,
+				}))
+			})
+		})
+	})
+})
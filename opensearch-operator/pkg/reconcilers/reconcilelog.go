@@ -0,0 +1,103 @@
+package reconcilers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultReconcileLogMaxEntries = 50
+
+// ReconcileLogConfig controls if and how reconcile transitions are written to OpensearchReconcileLog objects
+type ReconcileLogConfig struct {
+	Enabled bool
+	// Maximum number of entries kept per object, defaults to 50
+	MaxEntries int
+	// Entries older than this are dropped, zero keeps entries regardless of their age
+	MaxAge time.Duration
+}
+
+func WithReconcileLog(config ReconcileLogConfig) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.reconcileLog = config
+	}
+}
+
+func reconcileLogName(kind string, name string) string {
+	return fmt.Sprintf("%s-%s", strings.ToLower(kind), name)
+}
+
+// recordReconcileTransition appends a state change of object to its reconcile log.
+// Nothing is written if the reconcile log is disabled or the state did not change.
+func recordReconcileTransition(
+	k8sClient k8s.K8sClient,
+	config ReconcileLogConfig,
+	object client.Object,
+	kind string,
+	previousState string,
+	state string,
+	reason string,
+) error {
+	if !config.Enabled || previousState == state {
+		return nil
+	}
+
+	name := reconcileLogName(kind, object.GetName())
+	reconcileLog, err := k8sClient.GetOpensearchReconcileLog(name, object.GetNamespace())
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		// The log has no owner reference so the history is kept after the object is deleted
+		reconcileLog = opsterv1.OpensearchReconcileLog{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: object.GetNamespace(),
+			},
+		}
+	}
+
+	now := time.Now()
+	reconcileLog.Spec.Target = opsterv1.ReconcileLogTarget{
+		Kind: kind,
+		Name: object.GetName(),
+		UID:  object.GetUID(),
+	}
+	reconcileLog.Spec.Entries = trimReconcileLogEntries(append(reconcileLog.Spec.Entries, opsterv1.ReconcileLogEntry{
+		Time:          metav1.NewTime(now),
+		Generation:    object.GetGeneration(),
+		PreviousState: previousState,
+		State:         state,
+		Reason:        reason,
+	}), config, now)
+
+	_, err = k8sClient.ReconcileResource(&reconcileLog, reconciler.StatePresent)
+	return err
+}
+
+// trimReconcileLogEntries drops entries that are older than the configured maximum age
+// and keeps at most the configured number of the newest entries
+func trimReconcileLogEntries(entries []opsterv1.ReconcileLogEntry, config ReconcileLogConfig, now time.Time) []opsterv1.ReconcileLogEntry {
+	if config.MaxAge > 0 {
+		cutoff := now.Add(-config.MaxAge)
+		for len(entries) > 0 && entries[0].Time.Time.Before(cutoff) {
+			entries = entries[1:]
+		}
+	}
+
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultReconcileLogMaxEntries
+	}
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+	return entries
+}
@@ -0,0 +1,169 @@
+package reconcilers
+
+import (
+	"context"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("reconcile log", func() {
+	var (
+		mockClient *k8s.MockK8sClient
+		instance   *opsterv1.OpensearchComponentTemplate
+		config     ReconcileLogConfig
+		written    []*opsterv1.OpensearchReconcileLog
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		instance = &opsterv1.OpensearchComponentTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "test-componenttemplate",
+				Namespace:  "test-reconcilelog",
+				UID:        "testuid",
+				Generation: 2,
+			},
+			Spec: opsterv1.OpensearchComponentTemplateSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+			},
+		}
+		config = ReconcileLogConfig{Enabled: true, MaxEntries: 3}
+		written = nil
+	})
+
+	expectWrite := func() {
+		mockClient.EXPECT().ReconcileResource(mock.Anything, reconciler.StatePresent).
+			RunAndReturn(func(obj runtime.Object, _ reconciler.DesiredState) (*ctrl.Result, error) {
+				written = append(written, obj.(*opsterv1.OpensearchReconcileLog))
+				return &ctrl.Result{}, nil
+			})
+	}
+
+	When("the reconcile log is disabled", func() {
+		It("should not write anything", func() {
+			err := recordReconcileTransition(mockClient, ReconcileLogConfig{}, instance, "OpensearchComponentTemplate", "", string(opsterv1.OpensearchComponentTemplatePending), "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("the state did not change", func() {
+		It("should not write anything", func() {
+			err := recordReconcileTransition(mockClient, config, instance, "OpensearchComponentTemplate", string(opsterv1.OpensearchComponentTemplateCreated), string(opsterv1.OpensearchComponentTemplateCreated), "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("no reconcile log exists yet", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().GetOpensearchReconcileLog("opensearchcomponenttemplate-test-componenttemplate", "test-reconcilelog").
+				Return(opsterv1.OpensearchReconcileLog{}, NotFoundError())
+			expectWrite()
+		})
+
+		It("should create it without an owner reference", func() {
+			err := recordReconcileTransition(mockClient, config, instance, "OpensearchComponentTemplate", "", string(opsterv1.OpensearchComponentTemplatePending), "waiting")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(HaveLen(1))
+			reconcileLog := written[0]
+			Expect(reconcileLog.Name).To(Equal("opensearchcomponenttemplate-test-componenttemplate"))
+			Expect(reconcileLog.Namespace).To(Equal("test-reconcilelog"))
+			Expect(reconcileLog.OwnerReferences).To(BeEmpty())
+			Expect(reconcileLog.Spec.Target).To(Equal(opsterv1.ReconcileLogTarget{
+				Kind: "OpensearchComponentTemplate",
+				Name: "test-componenttemplate",
+				UID:  "testuid",
+			}))
+			Expect(reconcileLog.Spec.Entries).To(HaveLen(1))
+			Expect(reconcileLog.Spec.Entries[0].PreviousState).To(Equal(""))
+			Expect(reconcileLog.Spec.Entries[0].State).To(Equal(string(opsterv1.OpensearchComponentTemplatePending)))
+			Expect(reconcileLog.Spec.Entries[0].Reason).To(Equal("waiting"))
+			Expect(reconcileLog.Spec.Entries[0].Generation).To(BeEquivalentTo(2))
+		})
+	})
+
+	When("the reconcile log is full", func() {
+		BeforeEach(func() {
+			config.MaxAge = time.Hour
+			now := time.Now()
+			existing := opsterv1.OpensearchReconcileLog{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "opensearchcomponenttemplate-test-componenttemplate",
+					Namespace: "test-reconcilelog",
+				},
+				Spec: opsterv1.OpensearchReconcileLogSpec{
+					Entries: []opsterv1.ReconcileLogEntry{
+						{Time: metav1.NewTime(now.Add(-2 * time.Hour)), State: "expired"},
+						{Time: metav1.NewTime(now.Add(-3 * time.Minute)), State: "first"},
+						{Time: metav1.NewTime(now.Add(-2 * time.Minute)), State: "second"},
+						{Time: metav1.NewTime(now.Add(-1 * time.Minute)), State: "third"},
+					},
+				},
+			}
+			mockClient.EXPECT().GetOpensearchReconcileLog(mock.Anything, mock.Anything).Return(existing, nil)
+			expectWrite()
+		})
+
+		It("should drop expired and the oldest entries", func() {
+			err := recordReconcileTransition(mockClient, config, instance, "OpensearchComponentTemplate", "third", "fourth", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(HaveLen(1))
+			var states []string
+			for _, entry := range written[0].Spec.Entries {
+				states = append(states, entry.State)
+			}
+			Expect(states).To(Equal([]string{"second", "third", "fourth"}))
+		})
+	})
+
+	When("a component template changes state", func() {
+		var recorder *record.FakeRecorder
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(1)
+			instance.Status.State = opsterv1.OpensearchComponentTemplateCreated
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+				RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+					f(obj)
+					return nil
+				})
+			mockClient.EXPECT().GetOpensearchReconcileLog(mock.Anything, mock.Anything).Return(opsterv1.OpensearchReconcileLog{}, NotFoundError())
+			expectWrite()
+		})
+
+		It("should write the transition to the reconcile log", func() {
+			options := ReconcilerOptions{}
+			options.apply(WithUpdateStatus(true), WithReconcileLog(config))
+			reconciler := &ComponentTemplateReconciler{
+				client:            mockClient,
+				ctx:               context.Background(),
+				ReconcilerOptions: options,
+				recorder:          recorder,
+				instance:          instance,
+				logger:            log.FromContext(context.Background()),
+			}
+			_, err := reconciler.Reconcile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(HaveLen(1))
+			Expect(written[0].Spec.Entries).To(HaveLen(1))
+			Expect(written[0].Spec.Entries[0].PreviousState).To(Equal(string(opsterv1.OpensearchComponentTemplateCreated)))
+			Expect(written[0].Spec.Entries[0].State).To(Equal(string(opsterv1.OpensearchComponentTemplatePending)))
+			Expect(written[0].Spec.Entries[0].Reason).To(Equal("waiting for opensearch cluster to exist"))
+		})
+	})
+})
@@ -3,12 +3,18 @@ package reconcilers
 import (
 	"fmt"
 	"net/http"
+	"text/template"
+	"time"
 
 	"k8s.io/client-go/tools/record"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -19,8 +25,22 @@ const (
 	opensearchAPIError    = "OpensearchAPIError"
 	opensearchRefMismatch = "OpensearchRefMismatch"
 	opensearchAPIUpdated  = "OpensearchAPIUpdated"
+	opensearchForbidden   = "OpensearchForbidden"
 	passwordError         = "PasswordError"
 	statusError           = "StatusUpdateError"
+
+	// A time valued index setting has a value OpenSearch would reject
+	opensearchInvalidTimeSetting = "OpensearchInvalidTimeSetting"
+	// index.number_of_routing_shards of a component template is not compatible with its number of shards
+	opensearchInvalidRoutingShards = "OpensearchInvalidRoutingShards"
+	// A change is not applied because the health of the cluster is below the required health
+	opensearchDeferred = "OpensearchDeferred"
+	// The client certificate the operator authenticates with is invalid or was not accepted by the cluster
+	opensearchClientCertificate = "OpensearchClientCertificate"
+	// The bearer token the operator authenticates with could not be read or requested
+	opensearchBearerToken = "OpensearchBearerToken"
+	// The proxy requests to the cluster are sent through is invalid or unreachable
+	opensearchProxy = "OpensearchProxy"
 )
 
 type ComponentReconciler func() (reconcile.Result, error)
@@ -28,6 +48,46 @@ type ComponentReconciler func() (reconcile.Result, error)
 type ReconcilerOptions struct {
 	osClientTransport http.RoundTripper
 	updateStatus      *bool
+	reconcileLog      ReconcileLogConfig
+	tombstones        TombstoneConfig
+	applyQueue        *ApplyQueue
+	etagCache         *services.ETagCache
+	// Compression of the request bodies sent to OpenSearch, nil sends them uncompressed
+	requestCompression *services.RequestCompression
+	// Debug log of the requests sent to OpenSearch, nil does not log them
+	requestLog *services.RequestLog
+	// Shares the OpenSearch clients between reconciles, nil creates a client in every reconcile
+	clientFactory *util.ClientFactory
+	// Events emitted for reconciled objects without an event verbosity annotation, empty emits all events
+	eventVerbosity EventVerbosity
+	// Factor the requeue intervals are randomly moved by, 0 requeues at fixed intervals
+	requeueJitter float64
+	// Pushgateway the states of reconciled objects are pushed to, nil does not push them
+	statusPusher *StatusPusher
+	// Template generating the index patterns of index templates with generateIndexPatterns set
+	indexPatternTemplate *template.Template
+	// Only write component templates OpenSearch still has as they were read, retrying this often on a conflict
+	conditionalWrites       bool
+	conditionalWriteRetries int
+	// Paths of template bodies whose values are redacted wherever template content is surfaced
+	redaction *helpers.Redaction
+	// How often node pools not returning a written component template are asked again, and the interval in between
+	nodePoolConfirmRetries  int
+	nodePoolConfirmInterval time.Duration
+	// ConfigMap with the policies component templates are checked against, an empty name checks none
+	templatePolicies types.NamespacedName
+	// What happens to the outcome of a reconcile of a spec that was changed while it ran, empty is requeue
+	staleSpecPolicy StaleSpecPolicy
+	// Warmer of the cache component templates are read from, nil reads them from OpenSearch
+	cacheWarmer *ComponentTemplateCacheWarmer
+	// How an upgrade of the cluster is detected to defer changes while it runs, empty does not defer them
+	upgradeHandling UpgradeHandling
+	// How long an object stays in a failure state before it is reported as dead letter, 0 reports none
+	deadLetterAfter time.Duration
+	// Report the dead letters are listed in besides the metric, nil only exports the metric
+	deadLetters *DeadLetterReport
+	// Patterns of the cluster settings OpensearchClusterSettings may not manage, nil uses the default deny list
+	clusterSettingsDenyList []string
 }
 
 type ReconcilerOption func(*ReconcilerOptions)
@@ -38,12 +98,87 @@ func (o *ReconcilerOptions) apply(opts ...ReconcilerOption) {
 	}
 }
 
+// clientOptions returns the options all clients of component templates are created with, followed by opts. Shared
+// clients are created with the options of the first caller, so they have to be the same for all callers.
+func (o *ReconcilerOptions) clientOptions(opts ...services.OsClusterClientOption) []services.OsClusterClientOption {
+	return append([]services.OsClusterClientOption{
+		services.WithRequestCompression(o.requestCompression),
+		services.WithRequestLog(o.requestLog),
+		services.WithComponentTemplateCache(o.cacheWarmer.cache()),
+	}, opts...)
+}
+
 func WithOSClientTransport(transport http.RoundTripper) ReconcilerOption {
 	return func(o *ReconcilerOptions) {
 		o.osClientTransport = transport
 	}
 }
 
+// WithETagCache shares the cache of conditional requests to OpenSearch between reconciles
+func WithETagCache(cache *services.ETagCache) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.etagCache = cache
+	}
+}
+
+// WithRequestCompression gzips the request bodies sent to OpenSearch, sharing the negotiated support between reconciles
+func WithRequestCompression(compression *services.RequestCompression) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.requestCompression = compression
+	}
+}
+
+// WithRequestLog logs the requests sent to OpenSearch to the request log while it is active for the reconciled object
+func WithRequestLog(log *services.RequestLog) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.requestLog = log
+	}
+}
+
+// WithClientFactory shares the OpenSearch clients of a cluster between reconciles
+func WithClientFactory(factory *util.ClientFactory) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.clientFactory = factory
+	}
+}
+
+// WithRequeueJitter moves requeue intervals randomly by up to ±factor, so reconciles do not run in sync
+func WithRequeueJitter(factor float64) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.requeueJitter = factor
+	}
+}
+
+// WithConditionalWrites only writes component templates that were not changed by another writer since they were
+// read if enabled, a conflict is retried up to retries times
+func WithConditionalWrites(enabled bool, retries int) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.conditionalWrites = enabled
+		o.conditionalWriteRetries = retries
+	}
+}
+
+// WithRedaction redacts the values of the paths of the redaction in events, the status, diffs and tombstones
+func WithRedaction(redaction *helpers.Redaction) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.redaction = redaction
+	}
+}
+
+// WithIndexPatternTemplate sets the template index patterns are generated from
+func WithIndexPatternTemplate(tmpl *template.Template) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.indexPatternTemplate = tmpl
+	}
+}
+
+// WithClusterSettingsDenyList sets the patterns of the cluster settings OpensearchClusterSettings may not manage
+func WithClusterSettingsDenyList(denyList []string) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.clusterSettingsDenyList = denyList
+	}
+}
+
 func WithUpdateStatus(update bool) ReconcilerOption {
 	return func(o *ReconcilerOptions) {
 		o.updateStatus = &update
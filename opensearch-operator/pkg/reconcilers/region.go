@@ -0,0 +1,95 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchRegionMismatch = "OpensearchComponentTemplateRegionMismatch"
+
+	// defaultRegionLabel is the label holding the region if the region affinity names none
+	defaultRegionLabel = "topology.kubernetes.io/region"
+)
+
+// componentTemplateClusterName returns the name of the cluster the component template refers to, which is the
+// cluster selected by the region affinity if the spec names none
+func componentTemplateClusterName(template *opsterv1.OpensearchComponentTemplate) string {
+	if template.Spec.OpensearchRef.Name != "" {
+		return template.Spec.OpensearchRef.Name
+	}
+	return template.Status.ManagedClusterName
+}
+
+// selectRegionCluster returns the cluster in the region of the component template. A cluster named by the spec, or
+// selected before, has to be in the region, otherwise the single running cluster of the region is selected. The
+// region is recorded in the status.
+func (r *ComponentTemplateReconciler) selectRegionCluster() (*opsterv1.OpenSearchCluster, string, error) {
+	label := r.instance.Spec.RegionAffinity.Label
+	if label == "" {
+		label = defaultRegionLabel
+	}
+	region := r.instance.Labels[label]
+	if region == "" {
+		reason := fmt.Sprintf("component template has no %s label to select the cluster of its region", label)
+		r.recorder.Event(r.instance, "Warning", opensearchRegionMismatch, reason)
+		return nil, reason, errors.New(reason)
+	}
+
+	list, err := r.client.ListOpenSearchClusters(client.InNamespace(r.instance.Namespace), client.MatchingLabels{label: region})
+	if err != nil {
+		reason := "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to list opensearch clusters")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return nil, reason, err
+	}
+
+	name := componentTemplateClusterName(r.instance)
+	var selected *opsterv1.OpenSearchCluster
+	var running []string
+	for i := range list.Items {
+		cluster := &list.Items[i]
+		if !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if name != "" {
+			if cluster.Name == name {
+				selected = cluster
+			}
+			continue
+		}
+		if cluster.Status.Phase == opsterv1.PhaseRunning || serverlessEndpoint(cluster) {
+			selected = cluster
+			running = append(running, cluster.Name)
+		}
+	}
+
+	var reason string
+	switch {
+	case name != "" && selected == nil:
+		reason = fmt.Sprintf("OpenSearchCluster %s is not in region %s of the component template", name, region)
+	case name == "" && len(running) == 0:
+		reason = fmt.Sprintf("no running OpenSearchCluster in region %s of the component template", region)
+	case len(running) > 1:
+		reason = fmt.Sprintf("OpenSearchClusters %s are all running in region %s, set opensearchCluster.name to select one", strings.Join(running, ", "), region)
+	}
+	if reason != "" {
+		r.recorder.Event(r.instance, "Warning", opensearchRegionMismatch, reason)
+		return nil, reason, errors.New(reason)
+	}
+
+	if r.instance.Status.Region != region {
+		if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+			status.Region = region
+		}); err != nil {
+			reason := fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return nil, reason, err
+		}
+	}
+	return selected, "", nil
+}
@@ -0,0 +1,338 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/builders"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchRemoteClusterExists        = "remote cluster already exists in OpenSearch; not modifying"
+	opensearchRemoteClusterAliasMismatch = "OpensearchRemoteClusterAliasMismatch"
+	opensearchInvalidRemoteCluster       = "OpensearchInvalidRemoteCluster"
+)
+
+type RemoteClusterReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchRemoteCluster
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewRemoteClusterReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchRemoteCluster,
+	opts ...ReconcilerOption,
+) *RemoteClusterReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &RemoteClusterReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "remotecluster"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "remotecluster"),
+	}
+}
+
+func (r *RemoteClusterReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var alias string
+	var info *responses.RemoteClusterInfo
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchRemoteCluster)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchRemoteClusterError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchRemoteClusterPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchRemoteClusterDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchRemoteClusterCreated
+				instance.Status.Alias = alias
+				if info != nil {
+					instance.Status.Connected = &info.Connected
+					instance.Status.NumNodesConnected = info.NumNodesConnected
+				}
+			}
+			if reason == opensearchRemoteClusterExists {
+				instance.Status.State = opsterv1.OpensearchRemoteClusterIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a remote cluster refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchRemoteCluster)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	alias = r.instance.Name
+	if r.instance.Spec.Alias != "" {
+		alias = r.instance.Spec.Alias
+	}
+
+	settings, err := services.GetFlatClusterSettingsMap(r.ctx, r.osClient)
+	if err != nil {
+		reason = "failed to get cluster settings from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	// Check connection state to make sure we don't touch preexisting connections
+	if r.instance.Status.ExistingConnection == nil {
+		exists := helpers.RemoteClusterExists(settings, alias)
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchRemoteCluster)
+				instance.Status.ExistingConnection = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If connection is existing do nothing
+	if *r.instance.Status.ExistingConnection {
+		reason = opensearchRemoteClusterExists
+		return
+	}
+
+	// the alias is immutable, so check the old alias (r.instance.Status.Alias) against the new
+	if r.instance.Status.Alias != "" && alias != r.instance.Status.Alias {
+		reason = "cannot change the remote cluster alias"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchRemoteClusterAliasMismatch, reason)
+		return
+	}
+
+	err = helpers.ValidateRemoteCluster(r.instance.Spec)
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidRemoteCluster, reason)
+		return
+	}
+
+	// A remote cluster managed by the operator is reached through the transport port of its service, it may live in
+	// another namespace
+	var remoteAddress string
+	if ref := r.instance.Spec.RemoteCluster; ref != nil {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = r.instance.Namespace
+		}
+		var remote *opsterv1.OpenSearchCluster
+		remote, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+			Name:      ref.Name,
+			Namespace: namespace,
+		})
+		if err != nil {
+			reason = "error fetching remote cluster"
+			r.logger.Error(err, "failed to fetch remote cluster")
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if remote == nil {
+			reason = "waiting for remote cluster to exist"
+			r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+			result = ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: 10 * time.Second,
+			}
+			return
+		}
+		remoteAddress = builders.TransportAddressForCluster(remote)
+	}
+
+	desired := helpers.DesiredRemoteClusterSettings(r.instance.Spec, alias, remoteAddress)
+	changes := helpers.RemoteClusterSettingsChanges(desired, settings)
+	if len(changes) > 0 {
+		err = services.PutFlatClusterSettings(r.ctx, r.osClient, responses.ClusterSettingsResponse{Persistent: changes})
+		if err != nil {
+			reason = "failed to update remote cluster with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "remote cluster updated in opensearch")
+	}
+
+	// Report the state of the connection, failing to fetch it does not fail the reconciliation
+	remoteInfo, infoErr := services.GetRemoteClusterInfo(r.ctx, r.osClient)
+	if infoErr != nil {
+		r.logger.Error(infoErr, "failed to get the state of the remote cluster from OpenSearch API")
+	} else if remote, ok := remoteInfo[alias]; ok {
+		info = &remote
+	}
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+func (r *RemoteClusterReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingConnection == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingConnection {
+		r.logger.Info("remote cluster was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	alias := r.instance.Status.Alias
+	if alias == "" {
+		alias = r.instance.Name
+		if r.instance.Spec.Alias != "" {
+			alias = r.instance.Spec.Alias
+		}
+	}
+
+	settings, err := services.GetFlatClusterSettingsMap(r.ctx, r.osClient)
+	if err != nil {
+		return err
+	}
+	reset := helpers.RemoteClusterResetSettings(settings, alias)
+	if len(reset) == 0 {
+		r.logger.V(1).Info("remote cluster already deleted from opensearch")
+		return nil
+	}
+	return services.PutFlatClusterSettings(r.ctx, r.osClient, responses.ClusterSettingsResponse{Persistent: reset})
+}
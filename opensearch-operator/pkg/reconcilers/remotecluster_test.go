@@ -0,0 +1,523 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("remotecluster reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *RemoteClusterReconciler
+		instance   *opsterv1.OpensearchRemoteCluster
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster     *opsterv1.OpenSearchCluster
+		clusterUrl  string
+		settingsUrl string
+		infoUrl     string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchRemoteCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-remotecluster",
+				Namespace: "test-remotecluster",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchRemoteClusterSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Alias: "remote",
+				Seeds: []string{"remote.example.com:9300"},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-remotecluster",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		settingsUrl = fmt.Sprintf("%s_cluster/settings?flat_settings=true", clusterUrl)
+		infoUrl = fmt.Sprintf("%s_remote/info", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &RemoteClusterReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a remote cluster refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(cluster.Name, cluster.Namespace).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+			})
+
+			When("a connection with the alias exists", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{"cluster.remote.remote.proxy_address":"other:9300"}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the connection exists", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+				})
+			})
+
+			When("the connection does not exist", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{"cluster.remote.remote-2.seeds":["other:9300"]},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the connection does not exist", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is false"}))
+				})
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingConnection = pointer.Bool(true)
+				transport.RegisterResponder(
+					http.MethodGet,
+					settingsUrl,
+					httpmock.NewStringResponder(200, `{"persistent":{"cluster.remote.remote.seeds":["other:9300"]},"transient":{}}`).Once(failMessage),
+				)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingConnection = pointer.Bool(false)
+			})
+
+			When("connection is in sync", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{
+							"cluster.remote.remote.mode":"sniff",
+							"cluster.remote.remote.seeds":["remote.example.com:9300"],
+							"cluster.remote.remote.skip_unavailable":"false"
+						},"transient":{}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						infoUrl,
+						httpmock.NewStringResponder(200, `{"remote":{"connected":true,"mode":"sniff","seeds":["remote.example.com:9300"],
+							"num_nodes_connected":3,"max_connections_per_cluster":3,"initial_connect_timeout":"30s","skip_unavailable":false}}`).Once(failMessage),
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+						f(obj)
+						return nil
+					})
+				})
+
+				It("should only report the state of the connection", func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchRemoteClusterCreated))
+					Expect(instance.Status.Alias).To(Equal("remote"))
+					Expect(instance.Status.Connected).To(Equal(pointer.Bool(true)))
+					Expect(instance.Status.NumNodesConnected).To(Equal(pointer.Int(3)))
+				})
+			})
+
+			When("the remote cluster in another namespace is connected through a proxy", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Seeds = nil
+					instance.Spec.Mode = "proxy"
+					instance.Spec.RemoteCluster = &opsterv1.RemoteClusterReference{Name: "remote-cluster", Namespace: "other-namespace"}
+					instance.Spec.SkipUnavailable = pointer.Bool(true)
+					remote := opsterv1.OpenSearchCluster{
+						ObjectMeta: metav1.ObjectMeta{Name: "remote-cluster", Namespace: "other-namespace"},
+						Spec:       opsterv1.ClusterSpec{General: opsterv1.GeneralConfig{ServiceName: "remote-cluster"}},
+					}
+					mockClient.EXPECT().GetOpenSearchCluster("remote-cluster", "other-namespace").Return(remote, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{
+							"cluster.remote.remote.mode":"sniff",
+							"cluster.remote.remote.seeds":["remote.example.com:9300"]
+						},"transient":{}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						settingsUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						infoUrl,
+						httpmock.NewStringResponder(200, `{"remote":{"connected":false,"mode":"proxy"}}`).Once(failMessage),
+					)
+				})
+
+				It("should switch the connection to the proxy mode", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s remote cluster updated in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{"persistent":{
+						"cluster.remote.remote.mode": "proxy",
+						"cluster.remote.remote.seeds": null,
+						"cluster.remote.remote.proxy_address": "remote-cluster.other-namespace.svc.cluster.local:9300",
+						"cluster.remote.remote.skip_unavailable": "true"
+					}}`))
+				})
+			})
+
+			When("the remote cluster does not exist", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Seeds = nil
+					instance.Spec.RemoteCluster = &opsterv1.RemoteClusterReference{Name: "remote-cluster"}
+					mockClient.EXPECT().GetOpenSearchCluster("remote-cluster", instance.Namespace).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should wait for the remote cluster", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						result, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.Requeue).To(BeTrue())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s waiting for remote cluster to exist", opensearchPending)}))
+				})
+			})
+
+			When("the spec is invalid", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.ProxyAddress = "remote-lb.example.com:9300"
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should fail without changing OpenSearch", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf(
+						"Warning %s invalid remote cluster: proxyAddress and serverName can only be set in proxy mode", opensearchInvalidRemoteCluster,
+					)}))
+				})
+			})
+
+			When("the alias has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.Alias = "old-remote"
+					transport.RegisterResponder(
+						http.MethodGet,
+						settingsUrl,
+						httpmock.NewStringResponder(200, `{"persistent":{},"transient":{}}`).Once(failMessage),
+					)
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the remote cluster alias", opensearchRemoteClusterAliasMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingConnection = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingConnection = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			Context("cluster exists", func() {
+				BeforeEach(func() {
+					instance.Status.Alias = "remote"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				When("the connection was already removed", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							settingsUrl,
+							httpmock.NewStringResponder(200, `{"persistent":{"cluster.remote.other.seeds":["other:9300"]},"transient":{}}`).Once(failMessage),
+						)
+					})
+
+					It("should do nothing and exit", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+
+				When("the connection exists", func() {
+					var body string
+
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodGet,
+							settingsUrl,
+							httpmock.NewStringResponder(200, `{"persistent":{
+								"cluster.remote.remote.mode":"sniff",
+								"cluster.remote.remote.seeds":["remote.example.com:9300"],
+								"cluster.remote.other.seeds":["other:9300"]
+							},"transient":{}}`).Once(failMessage),
+						)
+						transport.RegisterResponder(
+							http.MethodPut,
+							settingsUrl,
+							func(req *http.Request) (*http.Response, error) {
+								raw, err := io.ReadAll(req.Body)
+								if err != nil {
+									return nil, err
+								}
+								body = string(raw)
+								return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+							},
+						)
+					})
+
+					It("should reset the settings of the connection", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+						Expect(body).To(MatchJSON(`{"persistent":{"cluster.remote.remote.mode":null,"cluster.remote.remote.seeds":null}}`))
+					})
+				})
+			})
+		})
+	})
+})
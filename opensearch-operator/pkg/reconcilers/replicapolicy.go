@@ -0,0 +1,50 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+const opensearchReplicaPolicy = "OpensearchComponentTemplateReplicaPolicy"
+
+// checkReplicaPolicy sets the number of replicas of the translated template to the value the replica policy of the
+// spec computes from the current number of data nodes. As it runs on every reconcile, a scaled cluster changes the
+// desired template and the new number of replicas is applied like any other change of the spec.
+func (r *ComponentTemplateReconciler) checkReplicaPolicy(spec opsterv1.OpensearchComponentTemplateSpec, template *requests.ComponentTemplate) (string, error) {
+	if spec.ReplicaPolicy == nil {
+		return "", nil
+	}
+	if serverlessEndpoint(r.cluster) {
+		reason := "replica policies cannot be used with serverless endpoints, which do not report their data nodes"
+		r.recorder.Event(r.instance, "Warning", opensearchReplicaPolicy, reason)
+		return reason, errors.New(reason)
+	}
+
+	health, err := services.ClusterHealth(r.ctx, r.osClient)
+	if errors.Is(err, services.ErrForbidden) {
+		return r.forbidden(clusterHealthPrivilege), err
+	}
+	if err != nil {
+		reason := "failed to get cluster health from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return reason, err
+	}
+
+	replicas := helpers.ReplicaCount(*spec.ReplicaPolicy, health.NumberOfDataNodes)
+	settings, err := helpers.SetNumberOfReplicas(template.Template.Settings, replicas)
+	if err != nil {
+		reason := fmt.Sprintf("failed to apply the replica policy: %s", err)
+		r.recorder.Event(r.instance, "Warning", opensearchReplicaPolicy, reason)
+		return reason, err
+	}
+	r.logger.V(1).Info(fmt.Sprintf("replica policy sets %d replicas for %d data nodes", replicas, health.NumberOfDataNodes))
+
+	template.Template.Settings = settings
+	return "", nil
+}
@@ -0,0 +1,44 @@
+package reconcilers
+
+import (
+	"context"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// traceWrite returns the context to write the component template with, which appends the request id of the spec, or
+// a generated correlation id, to the X-Opaque-Id of the write
+func (r *ComponentTemplateReconciler) traceWrite() (context.Context, *services.RequestTrace) {
+	return r.traceMemberWrite(r.instance)
+}
+
+// traceMemberWrite returns the context to write the instance or a member of its transaction group with, tracing the
+// write with the request id of the spec of the member
+func (r *ComponentTemplateReconciler) traceMemberWrite(member *opsterv1.OpensearchComponentTemplate) (context.Context, *services.RequestTrace) {
+	id := member.Spec.RequestID
+	if id == "" {
+		id = string(uuid.NewUUID())
+	}
+	return services.ContextWithRequestTrace(r.ctx, id)
+}
+
+// recordRequestTrace records the ids of the last write of the component template in the status
+func (r *ComponentTemplateReconciler) recordRequestTrace(trace *services.RequestTrace) error {
+	return r.recordMemberRequestTrace(r.instance, trace)
+}
+
+// recordMemberRequestTrace records the ids of the last write of the instance or a member of its transaction group in
+// the status of the member
+func (r *ComponentTemplateReconciler) recordMemberRequestTrace(member *opsterv1.OpensearchComponentTemplate, trace *services.RequestTrace) error {
+	opaqueID := trace.OpaqueID()
+	serverRequestID := trace.ServerRequestID()
+	if member.Status.LastRequestID == opaqueID && member.Status.LastServerRequestID == serverRequestID {
+		return nil
+	}
+	return r.updateComponentTemplateStatus(member, func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		status.LastRequestID = opaqueID
+		status.LastServerRequestID = serverRequestID
+	})
+}
@@ -72,6 +72,9 @@ func (r *RoleReconciler) Reconcile() (retResult ctrl.Result, retErr error) {
 			if retResult.Requeue && retResult.RequeueAfter == 10*time.Second {
 				instance.Status.State = opsterv1.OpensearchRoleStatePending
 			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchRoleStateDeferred
+			}
 			if retErr == nil && retResult.Requeue {
 				instance.Status.State = opsterv1.OpensearchRoleStateCreated
 			}
@@ -139,7 +142,18 @@ func (r *RoleReconciler) Reconcile() (retResult ctrl.Result, retErr error) {
 		return
 	}
 
-	r.osClient, retErr = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, retErr = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
 	if retErr != nil {
 		reason = "error creating opensearch client"
 		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
@@ -247,8 +261,11 @@ func (r *RoleReconciler) Delete() error {
 		// If the opensearch cluster doesn't exist, we don't need to delete anything
 		return nil
 	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
 
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
 	if err != nil {
 		return err
 	}
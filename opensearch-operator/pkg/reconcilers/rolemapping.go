@@ -0,0 +1,291 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchRoleMappingExists       = "role mapping already exists in Opensearch; not modifying"
+	opensearchRoleMappingNameMismatch = "OpensearchRoleMappingNameMismatch"
+)
+
+type RoleMappingReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchRoleMapping
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewRoleMappingReconciler(
+	client client.Client,
+	ctx context.Context,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchRoleMapping,
+	opts ...ReconcilerOption,
+) *RoleMappingReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &RoleMappingReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "rolemapping"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "rolemapping"),
+	}
+}
+
+// roleName returns the name of the role that is mapped, which defaults to the name of the resource
+func (r *RoleMappingReconciler) roleName() string {
+	if r.instance.Spec.RoleName != "" {
+		return r.instance.Spec.RoleName
+	}
+	return r.instance.Name
+}
+
+func (r *RoleMappingReconciler) Reconcile() (retResult ctrl.Result, retErr error) {
+	var reason string
+	roleName := r.roleName()
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource is
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchRoleMapping)
+			instance.Status.Reason = reason
+			if retErr != nil {
+				instance.Status.State = opsterv1.OpensearchRoleMappingStateError
+			}
+			if retResult.Requeue && retResult.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchRoleMappingStatePending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchRoleMappingStateDeferred
+			}
+			if retErr == nil && retResult.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchRoleMappingStateCreated
+				instance.Status.RoleName = roleName
+			}
+			if reason == opensearchRoleMappingExists {
+				instance.Status.State = opsterv1.OpensearchRoleMappingIgnored
+			}
+		})
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, retErr = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if retErr != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(retErr, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a role mapping refers to"
+			retErr = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			retErr = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchRoleMapping)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if retErr != nil {
+				reason = fmt.Sprintf("failed to update status: %s", retErr)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, retErr = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if retErr != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	// Check role mapping state to make sure we don't touch preexisting role mappings
+	if r.instance.Status.ExistingRoleMapping == nil {
+		var exists bool
+		exists, retErr = services.RoleMappingExists(r.ctx, r.osClient, roleName)
+		if retErr != nil {
+			reason = "failed to get role mapping status from Opensearch API"
+			r.logger.Error(retErr, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if pointer.BoolDeref(r.updateStatus, true) {
+			retErr = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchRoleMapping)
+				instance.Status.ExistingRoleMapping = &exists
+			})
+			if retErr != nil {
+				reason = fmt.Sprintf("failed to update status: %s", retErr)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If role mapping is existing do nothing
+	if *r.instance.Status.ExistingRoleMapping {
+		reason = opensearchRoleMappingExists
+		return
+	}
+
+	// The mapped role is immutable, so check the old name (r.instance.Status.RoleName) against the new
+	if r.instance.Status.RoleName != "" && roleName != r.instance.Status.RoleName {
+		reason = "cannot change the role of a role mapping"
+		retErr = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchRoleMappingNameMismatch, reason)
+		return
+	}
+
+	mapping := requests.RoleMapping{
+		BackendRoles: r.instance.Spec.BackendRoles,
+		Hosts:        r.instance.Spec.Hosts,
+		Users:        r.instance.Spec.Users,
+	}
+
+	shouldUpdate, retErr := services.ShouldUpdateRoleMapping(r.ctx, r.osClient, roleName, mapping)
+	if retErr != nil {
+		reason = "failed to get role mapping status from Opensearch API"
+		r.logger.Error(retErr, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if !shouldUpdate {
+		r.logger.V(1).Info(fmt.Sprintf("role mapping %s is in sync", r.instance.Name))
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, retErr
+	}
+
+	retErr = services.CreateOrUpdateRoleMapping(r.ctx, r.osClient, roleName, mapping)
+	if retErr != nil {
+		reason = "failed to update role mapping with Opensearch API"
+		r.logger.Error(retErr, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "role mapping updated in opensearch")
+
+	return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, retErr
+}
+
+func (r *RoleMappingReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingRoleMapping == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingRoleMapping {
+		r.logger.Info("role mapping was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	exist, err := services.RoleMappingExists(r.ctx, r.osClient, r.roleName())
+	if err != nil {
+		return err
+	}
+	if !exist {
+		r.logger.V(1).Info("role mapping already deleted from opensearch")
+		return nil
+	}
+
+	return services.DeleteRoleMapping(r.ctx, r.osClient, r.roleName())
+}
@@ -0,0 +1,130 @@
+package reconcilers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	opensearchRollback = "OpensearchComponentTemplateRollback"
+
+	// maxPreviousAppliedSize limits the size of the component template kept in the status for a rollback
+	maxPreviousAppliedSize = 32 * 1024
+)
+
+// previousAppliedBody returns the component template as it is kept in the status for a rollback, nil if it is
+// larger than maxPreviousAppliedSize or holds values that would be redacted in a tombstone
+func previousAppliedBody(template requests.ComponentTemplate, redaction *helpers.Redaction) (*apiextensionsv1.JSON, error) {
+	raw, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	var parsed interface{}
+	if err := helpers.UnmarshalPreservingNumbers(raw, &parsed); err != nil {
+		return nil, err
+	}
+	if raw, err = json.Marshal(parsed); err != nil {
+		return nil, err
+	}
+	redacted, err := redactJSON(template, redaction)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxPreviousAppliedSize || !bytes.Equal(raw, redacted) {
+		return nil, nil
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+// rollback applies the component template kept in the status instead of the spec and records the generation of
+// the spec it was rolled back at
+func (r *ComponentTemplateReconciler) rollback(templateName string) (ctrl.Result, string, error) {
+	if r.instance.Status.PreviousApplied.Size() == 0 {
+		reason := "no previously applied version of the component template is known, cannot roll back"
+		r.recorder.Event(r.instance, "Warning", opensearchRollback, reason)
+		return ctrl.Result{}, reason, errors.New(reason)
+	}
+	var previous requests.ComponentTemplate
+	if err := json.Unmarshal(r.instance.Status.PreviousApplied.Raw, &previous); err != nil {
+		reason := "failed to parse the previously applied component template"
+		r.recorder.Event(r.instance, "Warning", opensearchRollback, reason)
+		return ctrl.Result{}, reason, err
+	}
+
+	live, err := services.GetComponentTemplate(r.ctx, r.osClient, templateName)
+	if errors.Is(err, services.ErrForbidden) {
+		return ctrl.Result{}, r.forbidden(componentTemplateGetPrivilege), err
+	}
+	if err != nil {
+		reason := "failed to get component template status from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return ctrl.Result{}, reason, err
+	}
+
+	if live == nil || !helpers.ComponentTemplatesEqual(previous, *live) {
+		release, err := r.applyQueue.Acquire(r.ctx, r.cluster.UID, r.instance.Spec.ApplyPriority)
+		if err != nil {
+			return ctrl.Result{}, "failed to wait for other applies to the cluster", err
+		}
+		var deprecations []string
+		writeCtx, trace := r.traceWrite()
+		deprecations, err = services.CreateOrUpdateComponentTemplate(writeCtx, r.osClient, templateName, previous)
+		release()
+		if errors.Is(err, services.ErrForbidden) {
+			return ctrl.Result{}, r.forbidden(componentTemplatePutPrivilege), err
+		}
+		if err != nil {
+			reason := "failed to roll back component template with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return ctrl.Result{}, reason, err
+		}
+		if err := r.recordRequestTrace(trace); err != nil {
+			reason := fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return ctrl.Result{}, reason, err
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchRollback, "rolled back the component template to the previously applied version")
+		// The rolled back version was applied before, a rollback is never reverted for its deprecations
+		_, _ = r.reportDeprecations(templateName, deprecations, false)
+	}
+
+	if r.instance.Status.RollbackGeneration != r.generation {
+		hash, err := componentTemplateHash(previous)
+		if err != nil {
+			return ctrl.Result{}, "failed to hash the component template", err
+		}
+		generation := r.generation
+		revision := helpers.ProvenanceRevision(previous.Meta)
+		if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+			status.RollbackGeneration = generation
+			status.LastAppliedHash = hash
+			status.LastAppliedGeneration = generation
+			status.LastAppliedGitRevision = revision
+			status.ExternalEditDetectedAt = nil
+		}); err != nil {
+			reason := fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return ctrl.Result{}, reason, err
+		}
+	}
+	return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, opensearchComponentTemplateRolledBack, nil
+}
+
+// rollbackRequested returns true if the rollback annotation asks to roll back the component template at the given
+// generation of its spec. A rollback is kept until the annotation is removed or the spec is changed.
+func rollbackRequested(instance *opsterv1.OpensearchComponentTemplate, generation int64) bool {
+	return instance.Annotations[helpers.RollbackAnnotation] == "true" &&
+		(instance.Status.RollbackGeneration == 0 || instance.Status.RollbackGeneration == generation)
+}
@@ -0,0 +1,568 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("rollupjob reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *RollupJobReconciler
+		instance   *opsterv1.OpensearchRollupJob
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		jobUrl     string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchRollupJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-rollupjob",
+				Namespace: "test-rollupjob",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchRollupJobSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				JobID:       "logs-hourly",
+				Schedule:    opsterv1.RollupSchedule{Interval: &opsterv1.RollupInterval{Period: 1, Unit: "Hours"}},
+				SourceIndex: "logs-*",
+				TargetIndex: "rollup-logs",
+				PageSize:    1000,
+				Dimensions: []opsterv1.RollupDimension{
+					{DateHistogram: &opsterv1.RollupDateHistogram{SourceField: "@timestamp", FixedInterval: "1h"}},
+					{Terms: &opsterv1.RollupTerms{SourceField: "service"}},
+				},
+				Metrics: []opsterv1.RollupMetric{{SourceField: "bytes", Metrics: []opsterv1.RollupMetricType{"sum"}}},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-rollupjob",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		jobUrl = fmt.Sprintf("%s_plugins/_rollup/jobs/logs-hourly", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &RollupJobReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a rollup job refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+			})
+
+			When("the rollup job exists", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, rollupJobResponse(true, 1000)).Once(failMessage),
+					)
+				})
+
+				It("should record that the rollup job exists", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+				})
+			})
+
+			When("the rollup job does not exist", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(404, `{"error":"Rollup not found"}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the rollup job does not exist", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is false"}))
+				})
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingJob = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingJob = pointer.Bool(false)
+			})
+
+			When("rollup job does not exist in opensearch", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(404, `{"error":"Rollup not found"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						jobUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(201, `{"_id":"logs-hourly"}`), nil
+						},
+					)
+				})
+
+				It("should create the rollup job", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s rollup job created in opensearch", opensearchAPIUpdated)}))
+					job := requests.RollupJob{}
+					Expect(json.Unmarshal([]byte(body), &job)).To(Succeed())
+					Expect(job.Rollup.Enabled).To(BeTrue())
+					Expect(job.Rollup.Schedule.Interval.StartTime).ToNot(BeNil())
+					Expect(job.Rollup.TargetIndex).To(Equal("rollup-logs"))
+				})
+			})
+
+			When("rollup job exists in opensearch and is the same", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, rollupJobResponse(true, 1000)).Once(failMessage),
+					)
+				})
+
+				It("should do nothing", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				})
+			})
+
+			When("rollup job exists in opensearch and is not the same", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, rollupJobResponse(true, 500)).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						jobUrl+"?if_seq_no=3&if_primary_term=1",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"_id":"logs-hourly"}`), nil
+						},
+					)
+				})
+
+				It("should update the rollup job keeping its start time", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s rollup job updated in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{"rollup": {
+						"enabled": true,
+						"schedule": {"interval": {"start_time": 1700000000000, "period": 1, "unit": "Hours"}},
+						"source_index": "logs-*",
+						"target_index": "rollup-logs",
+						"page_size": 1000,
+						"continuous": false,
+						"dimensions": [
+							{"date_histogram": {"source_field": "@timestamp", "fixed_interval": "1h", "timezone": "UTC"}},
+							{"terms": {"source_field": "service"}}
+						],
+						"metrics": [{"source_field": "bytes", "metrics": [{"sum": {}}]}]
+					}}`))
+				})
+			})
+
+			When("the rollup job is disabled in the spec", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Enabled = pointer.Bool(false)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, rollupJobResponse(true, 1000)).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						jobUrl+"/_stop",
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+				})
+
+				It("should stop the rollup job", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s rollup job stopped in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("the rollup job is enabled again in the spec", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, rollupJobResponse(false, 1000)).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						jobUrl+"/_start",
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+				})
+
+				It("should start the rollup job", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s rollup job started in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("an immutable field has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.TargetIndex = "rollup-logs-v2"
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, rollupJobResponse(true, 1000)).Once(failMessage),
+					)
+				})
+
+				It("should fail without updating the rollup job", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change targetIndex of an existing rollup job", opensearchRollupJobImmutableChange)}))
+				})
+			})
+
+			When("the first dimension is not a date histogram", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Dimensions = instance.Spec.Dimensions[1:]
+				})
+
+				It("should fail without calling OpenSearch", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s invalid rollup job: the first dimension has to be a date histogram", opensearchInvalidRollupJob)}))
+				})
+			})
+
+			When("the id of the rollup job has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.JobID = "logs-daily"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the rollup job id", opensearchRollupJobIDMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingJob = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingJob = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			When("cluster exists", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						jobUrl,
+						httpmock.NewStringResponder(200, `{"_id":"logs-hourly","result":"deleted"}`).Once(failMessage),
+					)
+				})
+
+				It("should delete the rollup job", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+				})
+			})
+		})
+	})
+})
+
+// rollupJobResponse is the rollup job of the tests as OpenSearch returns it, with the fields it adds
+func rollupJobResponse(enabled bool, pageSize int) string {
+	return fmt.Sprintf(`{"_id":"logs-hourly","_seq_no":3,"_primary_term":1,"rollup":{
+	"rollup_id": "logs-hourly",
+	"enabled": %t,
+	"schedule": {"interval": {"start_time": 1700000000000, "period": 1, "unit": "Hours"}},
+	"source_index": "logs-*",
+	"target_index": "rollup-logs",
+	"page_size": %d,
+	"delay": null,
+	"continuous": false,
+	"dimensions": [
+		{"date_histogram": {"source_field": "@timestamp", "target_field": "@timestamp", "fixed_interval": "1h", "timezone": "UTC"}},
+		{"terms": {"source_field": "service", "target_field": "service"}}
+	],
+	"metrics": [{"source_field": "bytes", "target_field": "bytes", "metrics": [{"sum": {}}]}]
+}}`, enabled, pageSize)
+}
@@ -0,0 +1,361 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// Error type reported by the Dashboards import API for objects that already exist
+	savedObjectConflict = "conflict"
+)
+
+type SavedObjectsReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx              context.Context
+	dashboardsClient *services.DashboardsClient
+	recorder         record.EventRecorder
+	instance         *opsterv1.OpensearchSavedObjects
+	cluster          *opsterv1.OpenSearchCluster
+	logger           logr.Logger
+}
+
+func NewSavedObjectsReconciler(
+	client client.Client,
+	ctx context.Context,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchSavedObjects,
+	opts ...ReconcilerOption,
+) *SavedObjectsReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &SavedObjectsReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "savedobjects"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "savedobjects"),
+	}
+}
+
+func (r *SavedObjectsReconciler) Reconcile() (retResult ctrl.Result, retErr error) {
+	var reason string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchSavedObjects)
+			instance.Status.Reason = reason
+			if retErr != nil {
+				instance.Status.State = opsterv1.OpensearchSavedObjectsError
+			}
+			// Requeue after is 10 seconds if waiting for OpenSearch cluster or tenant
+			if retResult.Requeue && retResult.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchSavedObjectsPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchSavedObjectsDeferred
+			}
+			// Requeue is after 30 seconds for normal reconciliation after creation/update
+			if retErr == nil && retResult.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchSavedObjectsCreated
+			}
+		})
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, retErr = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if retErr != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(retErr, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster saved objects refer to"
+			retErr = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			retErr = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSavedObjects)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if retErr != nil {
+				reason = fmt.Sprintf("failed to update status: %s", retErr)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if !r.cluster.Spec.Dashboards.Enable {
+		reason = "dashboards are not enabled for the opensearch cluster"
+		retErr = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	// Saved objects can only be imported once the tenant exists
+	tenant, err := r.client.GetOpensearchTenant(r.instance.Spec.TenantRef.Name, r.instance.Namespace)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		retErr = err
+		reason = "error fetching tenant"
+		r.logger.Error(retErr, "failed to fetch tenant")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+	if k8serrors.IsNotFound(err) ||
+		(tenant.Status.State != opsterv1.OpensearchTenantCreated && tenant.Status.State != opsterv1.OpensearchTenantIgnored) {
+		r.logger.Info("tenant is not created, requeueing")
+		reason = "waiting for tenant to be created"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	objects, retErr := parseSavedObjects(r.instance.Spec.Objects)
+	if retErr != nil {
+		reason = "failed to parse saved objects"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	hash, retErr := util.GetSha1Sum([]byte(fmt.Sprintf("%s\n%t\n%s", tenant.Name, r.instance.Spec.Overwrite, r.instance.Spec.Objects)))
+	if retErr != nil {
+		reason = "failed to hash saved objects"
+		return
+	}
+	if hash == r.instance.Status.ObjectsHash {
+		r.logger.V(1).Info(fmt.Sprintf("saved objects %s are in sync", r.instance.Name))
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	}
+
+	r.dashboardsClient, retErr = util.CreateDashboardsClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if retErr != nil {
+		reason = "error creating dashboards client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	response, retErr := services.ImportSavedObjects(r.ctx, r.dashboardsClient, tenant.Name, r.instance.Spec.Objects, r.instance.Spec.Overwrite)
+	if errors.Is(retErr, services.ErrForbidden) {
+		reason = "operator user is not authorized to import saved objects"
+		r.recorder.Event(r.instance, "Warning", opensearchForbidden, reason)
+		return
+	}
+	if retErr != nil {
+		reason = "failed to import saved objects with Dashboards API"
+		r.logger.Error(retErr, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	// Objects that were imported before stay tracked, even if the import now skips them as conflicts
+	var imported []opsterv1.SavedObjectReference
+	for _, object := range r.instance.Status.ImportedObjects {
+		if containsSavedObject(objects, object) {
+			imported = append(imported, object)
+		}
+	}
+	for _, result := range response.SuccessResults {
+		object := opsterv1.SavedObjectReference{Type: result.Type, ID: result.ID}
+		if !containsSavedObject(imported, object) {
+			imported = append(imported, object)
+		}
+	}
+
+	var skipped int
+	var failed []string
+	for _, importErr := range response.Errors {
+		if importErr.Error.Type == savedObjectConflict {
+			skipped++
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("%s/%s: %s", importErr.Type, importErr.ID, importErr.Error.Type))
+	}
+
+	// Remove objects imported before that are no longer part of the spec
+	var deleteErr error
+	for _, object := range r.instance.Status.ImportedObjects {
+		if containsSavedObject(objects, object) {
+			continue
+		}
+		if err := services.DeleteSavedObject(r.ctx, r.dashboardsClient, tenant.Name, object.Type, object.ID); err != nil {
+			deleteErr = err
+			imported = append(imported, object)
+		}
+	}
+
+	if pointer.BoolDeref(r.updateStatus, true) {
+		retErr = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchSavedObjects)
+			instance.Status.ImportedObjects = imported
+			if len(failed) == 0 && deleteErr == nil {
+				instance.Status.ObjectsHash = hash
+			}
+		})
+		if retErr != nil {
+			reason = fmt.Sprintf("failed to update status: %s", retErr)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return
+		}
+	}
+
+	if deleteErr != nil {
+		retErr = deleteErr
+		reason = "failed to delete removed saved objects with Dashboards API"
+		r.logger.Error(retErr, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if len(failed) > 0 {
+		reason = fmt.Sprintf("failed to import saved objects: %s", strings.Join(failed, ", "))
+		retErr = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated,
+		fmt.Sprintf("imported %d saved objects into tenant %s, skipped %d existing saved objects", response.SuccessCount, tenant.Name, skipped))
+
+	return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+}
+
+func (r *SavedObjectsReconciler) Delete() error {
+	// If nothing was imported we can just exit
+	if len(r.instance.Status.ImportedObjects) == 0 {
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.dashboardsClient, err = util.CreateDashboardsClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	for _, object := range r.instance.Status.ImportedObjects {
+		err = services.DeleteSavedObject(r.ctx, r.dashboardsClient, r.instance.Spec.TenantRef.Name, object.Type, object.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSavedObjects returns the type and id of the saved objects in an NDJSON export
+func parseSavedObjects(bundle string) ([]opsterv1.SavedObjectReference, error) {
+	var objects []opsterv1.SavedObjectReference
+	for _, line := range strings.Split(bundle, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		object := opsterv1.SavedObjectReference{}
+		if err := json.Unmarshal([]byte(line), &object); err != nil {
+			return nil, err
+		}
+		// The export summary at the end of a bundle is not a saved object
+		if object.Type == "" || object.ID == "" {
+			continue
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func containsSavedObject(objects []opsterv1.SavedObjectReference, object opsterv1.SavedObjectReference) bool {
+	for _, o := range objects {
+		if o == object {
+			return true
+		}
+	}
+	return false
+}
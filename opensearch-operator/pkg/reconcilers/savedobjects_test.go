@@ -0,0 +1,341 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("savedobjects reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *SavedObjectsReconciler
+		instance   *opsterv1.OpensearchSavedObjects
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster       *opsterv1.OpenSearchCluster
+		tenant        *opsterv1.OpensearchTenant
+		dashboardsUrl string
+		importUrl     string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		recorder = record.NewFakeRecorder(5)
+		instance = &opsterv1.OpensearchSavedObjects{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-savedobjects",
+				Namespace: "test-savedobjects",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchSavedObjectsSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				TenantRef: corev1.LocalObjectReference{
+					Name: "test-tenant",
+				},
+				Objects: `{"type":"index-pattern","id":"logs","attributes":{"title":"logs-*"}}
+{"type":"dashboard","id":"overview","attributes":{"title":"Overview"}}
+{"exportedCount":2,"missingRefCount":0,"missingReferences":[]}
+`,
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-savedobjects",
+				UID:       "clusteruid",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				Dashboards: opsterv1.DashboardsConfig{
+					Enable: true,
+				},
+			},
+			Status: opsterv1.ClusterStatus{
+				Phase: opsterv1.PhaseRunning,
+			},
+		}
+		tenant = &opsterv1.OpensearchTenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-tenant",
+				Namespace: "test-savedobjects",
+			},
+			Status: opsterv1.OpensearchTenantStatus{
+				State: opsterv1.OpensearchTenantCreated,
+			},
+		}
+		instance.Status.ManagedCluster = &cluster.UID
+		dashboardsUrl = fmt.Sprintf("http://%s-dashboards.%s.svc.cluster.local:5601", cluster.Spec.General.ServiceName, cluster.Namespace)
+		importUrl = fmt.Sprintf("%s/api/saved_objects/_import", dashboardsUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(true))
+		reconciler = &SavedObjectsReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	expectStatusUpdates := func() {
+		mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+			RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+				f(obj)
+				return nil
+			})
+	}
+
+	Context("reconcile", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			expectStatusUpdates()
+		})
+
+		When("the tenant is not created yet", func() {
+			BeforeEach(func() {
+				mockClient.EXPECT().GetOpensearchTenant("test-tenant", "test-savedobjects").Return(opsterv1.OpensearchTenant{}, NotFoundError())
+			})
+
+			It("should wait for the tenant", func() {
+				result, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchSavedObjectsPending))
+				Expect(recorder.Events).To(Receive(Equal(fmt.Sprintf("Normal %s waiting for tenant to be created", opensearchPending))))
+			})
+		})
+
+		Context("the tenant exists", func() {
+			var importRequest *http.Request
+
+			BeforeEach(func() {
+				mockClient.EXPECT().GetOpensearchTenant("test-tenant", "test-savedobjects").Return(*tenant, nil)
+				importRequest = nil
+			})
+
+			importResponder := func(response responses.SavedObjectsImportResponse) httpmock.Responder {
+				return func(req *http.Request) (*http.Response, error) {
+					importRequest = req
+					return httpmock.NewJsonResponse(200, response)
+				}
+			}
+
+			When("all saved objects are imported", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						importUrl,
+						importResponder(responses.SavedObjectsImportResponse{
+							Success:      true,
+							SuccessCount: 2,
+							SuccessResults: []responses.SavedObjectImportResult{
+								{Type: "index-pattern", ID: "logs"},
+								{Type: "dashboard", ID: "overview"},
+							},
+						}),
+					)
+				})
+
+				It("should import them into the tenant and track them", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetCallCountInfo()["POST "+importUrl]).To(Equal(1))
+					Expect(importRequest.Header.Get("securitytenant")).To(Equal("test-tenant"))
+					Expect(importRequest.Header.Get("osd-xsrf")).To(Equal("true"))
+					Expect(importRequest.Header.Get("X-Opaque-Id")).To(Equal("test-savedobjects/test-savedobjects/testuid"))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchSavedObjectsCreated))
+					Expect(instance.Status.ImportedObjects).To(ConsistOf(
+						opsterv1.SavedObjectReference{Type: "index-pattern", ID: "logs"},
+						opsterv1.SavedObjectReference{Type: "dashboard", ID: "overview"},
+					))
+					Expect(instance.Status.ObjectsHash).ToNot(BeEmpty())
+					Expect(recorder.Events).To(Receive(Equal(fmt.Sprintf("Normal %s imported 2 saved objects into tenant test-tenant, skipped 0 existing saved objects", opensearchAPIUpdated))))
+				})
+			})
+
+			When("a saved object already exists and overwrite is disabled", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						importUrl,
+						importResponder(responses.SavedObjectsImportResponse{
+							Success:      false,
+							SuccessCount: 1,
+							SuccessResults: []responses.SavedObjectImportResult{
+								{Type: "dashboard", ID: "overview"},
+							},
+							Errors: []responses.SavedObjectImportError{
+								{Type: "index-pattern", ID: "logs", Error: responses.SavedObjectImportErrorType{Type: "conflict"}},
+							},
+						}),
+					)
+				})
+
+				It("should skip it and not track it", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(importRequest.URL.Query().Get("overwrite")).To(BeEmpty())
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchSavedObjectsCreated))
+					Expect(instance.Status.ImportedObjects).To(ConsistOf(
+						opsterv1.SavedObjectReference{Type: "dashboard", ID: "overview"},
+					))
+					Expect(recorder.Events).To(Receive(Equal(fmt.Sprintf("Normal %s imported 1 saved objects into tenant test-tenant, skipped 1 existing saved objects", opensearchAPIUpdated))))
+				})
+			})
+
+			When("a saved object already exists and overwrite is enabled", func() {
+				BeforeEach(func() {
+					instance.Spec.Overwrite = true
+					transport.RegisterResponder(
+						http.MethodPost,
+						importUrl+"?overwrite=true",
+						importResponder(responses.SavedObjectsImportResponse{
+							Success:      true,
+							SuccessCount: 2,
+							SuccessResults: []responses.SavedObjectImportResult{
+								{Type: "index-pattern", ID: "logs"},
+								{Type: "dashboard", ID: "overview"},
+							},
+						}),
+					)
+				})
+
+				It("should overwrite it", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(importRequest.URL.Query().Get("overwrite")).To(Equal("true"))
+					Expect(instance.Status.ImportedObjects).To(HaveLen(2))
+				})
+			})
+
+			When("a saved object fails to import", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						importUrl,
+						importResponder(responses.SavedObjectsImportResponse{
+							Success:      false,
+							SuccessCount: 1,
+							SuccessResults: []responses.SavedObjectImportResult{
+								{Type: "index-pattern", ID: "logs"},
+							},
+							Errors: []responses.SavedObjectImportError{
+								{Type: "dashboard", ID: "overview", Error: responses.SavedObjectImportErrorType{Type: "missing_references"}},
+							},
+						}),
+					)
+				})
+
+				It("should fail and retry the import", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).To(HaveOccurred())
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchSavedObjectsError))
+					Expect(instance.Status.ImportedObjects).To(ConsistOf(
+						opsterv1.SavedObjectReference{Type: "index-pattern", ID: "logs"},
+					))
+					Expect(instance.Status.ObjectsHash).To(BeEmpty())
+					Expect(recorder.Events).To(Receive(Equal(fmt.Sprintf("Warning %s failed to import saved objects: dashboard/overview: missing_references", opensearchAPIError))))
+				})
+			})
+
+			When("a tracked saved object was removed from the spec", func() {
+				BeforeEach(func() {
+					instance.Status.ImportedObjects = []opsterv1.SavedObjectReference{
+						{Type: "index-pattern", ID: "logs"},
+						{Type: "visualization", ID: "removed"},
+					}
+					transport.RegisterResponder(
+						http.MethodPost,
+						importUrl,
+						importResponder(responses.SavedObjectsImportResponse{
+							Success:      false,
+							SuccessCount: 1,
+							SuccessResults: []responses.SavedObjectImportResult{
+								{Type: "dashboard", ID: "overview"},
+							},
+							Errors: []responses.SavedObjectImportError{
+								{Type: "index-pattern", ID: "logs", Error: responses.SavedObjectImportErrorType{Type: "conflict"}},
+							},
+						}),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						fmt.Sprintf("%s/api/saved_objects/visualization/removed", dashboardsUrl),
+						httpmock.NewStringResponder(200, "{}").Once(failMessage),
+					)
+				})
+
+				It("should delete it and keep tracking previously imported objects", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders()))
+					Expect(instance.Status.ImportedObjects).To(ConsistOf(
+						opsterv1.SavedObjectReference{Type: "index-pattern", ID: "logs"},
+						opsterv1.SavedObjectReference{Type: "dashboard", ID: "overview"},
+					))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("nothing was imported", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("saved objects were imported", func() {
+			BeforeEach(func() {
+				instance.Status.ImportedObjects = []opsterv1.SavedObjectReference{
+					{Type: "index-pattern", ID: "logs"},
+					{Type: "dashboard", ID: "overview"},
+				}
+				mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+				transport.RegisterResponder(
+					http.MethodDelete,
+					fmt.Sprintf("%s/api/saved_objects/index-pattern/logs", dashboardsUrl),
+					httpmock.NewStringResponder(200, "{}").Once(failMessage),
+				)
+				transport.RegisterResponder(
+					http.MethodDelete,
+					fmt.Sprintf("%s/api/saved_objects/dashboard/overview", dashboardsUrl),
+					httpmock.NewStringResponder(404, "not found").Once(failMessage),
+				)
+			})
+
+			It("should delete them from the tenant", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders()))
+			})
+		})
+	})
+})
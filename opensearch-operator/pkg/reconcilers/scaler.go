@@ -3,6 +3,9 @@ package reconcilers
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/http"
+	"reflect"
 	"time"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
@@ -10,8 +13,12 @@ import (
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/builders"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -25,6 +32,8 @@ type ScalerReconciler struct {
 	recorder          record.EventRecorder
 	reconcilerContext *ReconcilerContext
 	instance          *opsterv1.OpenSearchCluster
+	// osClientTransport replaces the transport of the clients for the cluster, used in tests
+	osClientTransport http.RoundTripper
 }
 
 func NewScalerReconciler(
@@ -80,12 +89,26 @@ func (r *ScalerReconciler) reconcileNodePool(nodePool *opsterv1.NodePool) (bool,
 	comp := r.instance.Status.ComponentsStatus
 	currentStatus, found := helpers.FindFirstPartial(comp, componentStatus, helpers.GetByDescriptionAndGroup)
 
-	desireReplicaDiff := *currentSts.Spec.Replicas - nodePool.Replicas
+	if len(nodePool.ScalingSchedules) > 0 {
+		nodePool = r.applyScalingSchedules(nodePool)
+	}
+	replicas := nodePool.Replicas
+	if nodePool.Autoscaling != nil {
+		replicas = r.autoscaledReplicas(nodePool, currentSts, found)
+	}
+	drainNodes := r.drainBeforeScaleDown(nodePool)
+
+	desireReplicaDiff := *currentSts.Spec.Replicas - replicas
+	// The scale down was called off while its node was excluded, e.g. because a scaling schedule ended during the drain
+	if found && desireReplicaDiff <= 0 && (currentStatus.Status == "Excluded" || currentStatus.Status == "Drained" || currentStatus.Status == "DrainFailed") {
+		err := r.cancelScaleDown(currentStatus, currentSts, nodePool.Component)
+		return true, err
+	}
 	if desireReplicaDiff == 0 {
 		// If a scaling operation was started before for this nodePool
 		if found {
 			err := r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
-				if currentSts.Status.ReadyReplicas != nodePool.Replicas {
+				if currentSts.Status.ReadyReplicas != replicas {
 					// Change the status to waiting while the pods are coming up or getting deleted
 					componentStatus.Status = "Waiting"
 					instance.Status.ComponentsStatus = helpers.Replace(currentStatus, componentStatus, r.instance.Status.ComponentsStatus)
@@ -117,9 +140,9 @@ func (r *ScalerReconciler) reconcileNodePool(nodePool *opsterv1.NodePool) (bool,
 
 		if desireReplicaDiff > 0 {
 			r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Scaler", "Starting to scaling")
-			if !r.instance.Spec.ConfMgmt.SmartScaler {
+			if !drainNodes {
 				lg.Info(fmt.Sprintf("SmartScaler is disabled, removing nodes from nodegroup %s without draining", nodePool.Component))
-				requeue, err := r.decreaseOneNode(currentStatus, currentSts, nodePool.Component, r.instance.Spec.ConfMgmt.SmartScaler)
+				requeue, err := r.decreaseOneNode(currentStatus, currentSts, nodePool, drainNodes)
 				r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Scaler", "Notice - your SmartScaler is not enabled")
 				r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Scaler", "Starting to decrease node")
 				return requeue, err
@@ -138,15 +161,228 @@ func (r *ScalerReconciler) reconcileNodePool(nodePool *opsterv1.NodePool) (bool,
 		err := r.drainNode(currentStatus, currentSts, nodePool.Component)
 		return true, err
 	}
+	if currentStatus.Status == "DrainFailed" {
+		return false, r.retryFailedDrain(currentStatus, nodePool.Component)
+	}
 	if currentStatus.Status == "Drained" {
 		r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Scaler", "Start to Drain %s/%s", r.instance.Namespace, r.instance.Name)
 
-		requeue, err := r.decreaseOneNode(currentStatus, currentSts, nodePool.Component, r.instance.Spec.ConfMgmt.SmartScaler)
+		requeue, err := r.decreaseOneNode(currentStatus, currentSts, nodePool, drainNodes)
 		return requeue, err
 	}
 	return false, nil
 }
 
+// cancelScaleDown takes the node excluded for a scale down that was called off back into the cluster and removes the
+// scale down and its drain from the status, so that the node holds shards again
+func (r *ScalerReconciler) cancelScaleDown(currentStatus opsterv1.ComponentStatus, currentSts appsv1.StatefulSet, nodePoolGroupName string) error {
+	lg := log.FromContext(r.ctx)
+	annotations := map[string]string{"cluster-name": r.instance.GetName()}
+	excludedNodeName := helpers.ReplicaHostName(currentSts, *currentSts.Spec.Replicas-1)
+	if drain, found := helpers.FindDrainStatus(r.instance.Status, nodePoolGroupName); found {
+		excludedNodeName = drain.Node
+	}
+	clusterClient, err := r.newClusterClient()
+	if err != nil {
+		lg.Error(err, "failed to create os client")
+		return err
+	}
+	// The status is kept until the exclusion is removed, so that it is retried
+	if _, err := services.RemoveExcludeNodeHost(clusterClient, excludedNodeName); err != nil {
+		lg.Error(err, fmt.Sprintf("failed to remove exclude node %s", excludedNodeName))
+		r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Scaler", "Failed to remove node exclude - Group-%s , node  %s", nodePoolGroupName, excludedNodeName)
+		return err
+	}
+	lg.Info(fmt.Sprintf("Group: %s, Scale down was called off, node %s is no longer excluded", nodePoolGroupName, excludedNodeName))
+	r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Scaler", "Scale down of %s was called off, node %s is no longer excluded", nodePoolGroupName, excludedNodeName)
+	err = r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
+		instance.Status.ComponentsStatus = helpers.RemoveIt(currentStatus, instance.Status.ComponentsStatus)
+		instance.Status.Drains = helpers.RemoveDrainStatus(instance.Status.Drains, nodePoolGroupName)
+	})
+	if err != nil {
+		lg.Error(err, "failed to update status")
+	}
+	return err
+}
+
+// newClusterClient returns a client for the cluster authenticating like the clients of the other reconcilers, with
+// the client certificate, bearer token or admin credentials the cluster configures
+func (r *ScalerReconciler) newClusterClient() (*services.OsClusterClient, error) {
+	return util.CreateClientForURL(r.client, r.ctx, r.instance, builders.URLForCluster(r.instance), r.osClientTransport, util.ClientOptionForObject(r.instance))
+}
+
+// drainBeforeScaleDown returns true if the nodes of the pool have to be drained before they are removed. Data nodes
+// are drained if the cluster opts in, data nodes of autoscaled or scheduled pools are always drained, as they are
+// removed without anyone watching
+func (r *ScalerReconciler) drainBeforeScaleDown(nodePool *opsterv1.NodePool) bool {
+	unattended := nodePool.Autoscaling != nil || len(nodePool.ScalingSchedules) > 0
+	drain := r.instance.Spec.General.DrainDataNodes || unattended
+	return r.instance.Spec.ConfMgmt.SmartScaler || (helpers.HasDataRole(nodePool) && drain)
+}
+
+// applyScalingSchedules returns the node pool with the replicas of its active scaling schedule and records the active
+// schedule in the status. Invalid schedules are reported and ignored
+func (r *ScalerReconciler) applyScalingSchedules(nodePool *opsterv1.NodePool) *opsterv1.NodePool {
+	lg := log.FromContext(r.ctx)
+	annotations := map[string]string{"cluster-name": r.instance.GetName()}
+	if err := helpers.ValidateScalingSchedules(nodePool); err != nil {
+		lg.Error(err, "ignoring scaling schedules of node pool", "nodePool", nodePool.Component)
+		r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Scaler", "Ignoring scaling schedules: %s", err)
+		return nodePool
+	}
+
+	now := time.Now()
+	active, found := helpers.FindActiveScalingSchedule(nodePool, now)
+	if !found {
+		return nodePool
+	}
+	scheduled := helpers.ScheduledNodePool(*nodePool, now)
+	if lastActive, ok := helpers.FindScalingScheduleStatus(r.instance.Status, nodePool.Component); ok && lastActive.Name == active.Name && lastActive.Since.Equal(&active.Since) {
+		return &scheduled
+	}
+
+	lg.Info(fmt.Sprintf("Group: %s, scaling schedule %s is active, %d replicas", nodePool.Component, active.Name, active.Replicas))
+	r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Scaler", "Scaling schedule %s of %s is active, %d replicas", active.Name, nodePool.Component, active.Replicas)
+	err := r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
+		instance.Status.ScalingSchedules = helpers.SetScalingScheduleStatus(instance.Status.ScalingSchedules, active)
+	})
+	if err != nil {
+		lg.Error(err, "failed to update status")
+	}
+	return &scheduled
+}
+
+// autoscaledReplicas returns the number of replicas the autoscaler wants for the node pool. A new decision is only
+// made while the pool is stable, i.e. no scaling operation is in progress and all pods are ready, otherwise the
+// last decision is kept. A scale down in progress can still be called off if the usage no longer allows it. Scaling
+// down waits for the stabilization window and, for data nodes, for relocating shards. With vertical autoscaling, the
+// resources of the nodes are scaled once the replicas reached their bounds, they are recorded in the status for the
+// cluster reconciler
+func (r *ScalerReconciler) autoscaledReplicas(nodePool *opsterv1.NodePool, currentSts appsv1.StatefulSet, scaling bool) int32 {
+	lg := log.FromContext(r.ctx)
+	annotations := map[string]string{"cluster-name": r.instance.GetName()}
+	lastReplicas := helpers.EffectiveReplicas(r.instance.Status, nodePool)
+	if err := helpers.ValidateAutoscaling(nodePool); err != nil {
+		lg.Error(err, "ignoring autoscaling of node pool", "nodePool", nodePool.Component)
+		r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Autoscaler", "Ignoring autoscaling: %s", err)
+		return lastReplicas
+	}
+	current := pointer.Int32Deref(currentSts.Spec.Replicas, 1)
+	scalingDown := scaling && lastReplicas < current
+	if (scaling && !scalingDown) || currentSts.Status.ReadyReplicas != current || !r.instance.Status.Initialized {
+		return lastReplicas
+	}
+
+	clusterClient, err := r.newClusterClient()
+	if err != nil {
+		lg.Error(err, "failed to create os client for autoscaling")
+		return lastReplicas
+	}
+	stats, err := clusterClient.NodesStats()
+	if err != nil {
+		lg.Error(err, "failed to get node stats for autoscaling")
+		return lastReplicas
+	}
+
+	usage := helpers.NodePoolUsageFromStats(stats, currentSts.Name)
+	desired, reason := helpers.DesiredReplicas(*nodePool.Autoscaling, current, usage)
+	if scalingDown && desired < current {
+		// The scale down decided before goes on
+		return lastReplicas
+	}
+	lastStatus, found := helpers.FindAutoscalingStatus(r.instance.Status, nodePool.Component)
+	if desired < current {
+		window, _ := helpers.ScaleDownStabilizationWindow(nodePool.Autoscaling)
+		if found && lastStatus.LastScaleTime != nil && time.Since(lastStatus.LastScaleTime.Time) < window {
+			desired = current
+			reason = fmt.Sprintf("%s, scale down stabilization window has not passed", reason)
+		} else if helpers.HasDataRole(nodePool) {
+			health, err := clusterClient.GetClusterHealth()
+			if err != nil {
+				lg.Error(err, "failed to get cluster health for autoscaling")
+				return lastReplicas
+			}
+			if health.RelocatingShards > 0 {
+				desired = current
+				reason = fmt.Sprintf("%s, waiting for %d relocating shards", reason, health.RelocatingShards)
+			}
+		}
+	}
+
+	// The resources are only scaled once the replicas reached their bounds
+	resources := lastStatus.Resources
+	if nodePool.Autoscaling.Vertical != nil && desired == current && !scalingDown {
+		effective := helpers.EffectiveResources(r.instance.Status, nodePool)
+		if next, nextReason, changed := helpers.DesiredResources(*nodePool.Autoscaling, current, effective, usage); changed {
+			window, _ := helpers.ScaleDownStabilizationWindow(nodePool.Autoscaling)
+			shrinking := false
+			for name, quantity := range next.Requests {
+				if quantity.Cmp(effective.Requests[name]) < 0 {
+					shrinking = true
+				}
+			}
+			switch {
+			case currentSts.Status.UpdateRevision != currentSts.Status.CurrentRevision:
+				reason = fmt.Sprintf("%s, waiting for the nodes to be restarted before scaling their resources", reason)
+			case shrinking && found && lastStatus.LastScaleTime != nil && time.Since(lastStatus.LastScaleTime.Time) < window:
+				reason = fmt.Sprintf("%s, scale down stabilization window has not passed", reason)
+			default:
+				resources = &next
+				reason = nextReason
+			}
+		}
+	}
+
+	status := opsterv1.NodePoolAutoscalingStatus{
+		Component:       nodePool.Component,
+		DesiredReplicas: desired,
+		CPUUtilization:  percentage(usage.CPUUtilization),
+		JvmHeapPressure: percentage(usage.JvmHeapPressure),
+		DiskUsage:       percentage(usage.DiskUsage),
+		Reason:          reason,
+		LastScaleTime:   lastStatus.LastScaleTime,
+		Resources:       resources,
+	}
+	if desired != current {
+		now := metav1.Now()
+		status.LastScaleTime = &now
+		lg.Info(fmt.Sprintf("Group: %s, autoscaling from %d to %d replicas: %s", nodePool.Component, current, desired, reason))
+		r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Autoscaler", "Scaling %s from %d to %d replicas: %s", nodePool.Component, current, desired, reason)
+	} else if resources != lastStatus.Resources {
+		// The nodes are restarted one after another with the new resources
+		now := metav1.Now()
+		status.LastScaleTime = &now
+		lg.Info(fmt.Sprintf("Group: %s, autoscaling the resources of the nodes: %s", nodePool.Component, reason))
+		r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Autoscaler", "Scaling the resources of %s: %s", nodePool.Component, reason)
+	} else if scalingDown {
+		now := metav1.Now()
+		status.LastScaleTime = &now
+		lg.Info(fmt.Sprintf("Group: %s, calling off autoscaling down to %d replicas: %s", nodePool.Component, lastReplicas, reason))
+		r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Autoscaler", "Calling off scaling %s down to %d replicas: %s", nodePool.Component, lastReplicas, reason)
+	}
+	if found && reflect.DeepEqual(lastStatus, status) {
+		return desired
+	}
+	err = r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
+		instance.Status.Autoscaling = helpers.SetAutoscalingStatus(instance.Status.Autoscaling, status)
+	})
+	if err != nil {
+		// Without the decision in the status the cluster reconciler would not know about the new replicas
+		lg.Error(err, "failed to update autoscaling status")
+		return lastReplicas
+	}
+	r.instance.Status.Autoscaling = helpers.SetAutoscalingStatus(r.instance.Status.Autoscaling, status)
+	return desired
+}
+
+// percentage rounds a usage to a whole percentage
+func percentage(usage *float64) *int32 {
+	if usage == nil {
+		return nil
+	}
+	return pointer.Int32(int32(math.Round(*usage)))
+}
+
 func (r *ScalerReconciler) increaseOneNode(currentSts appsv1.StatefulSet, nodePoolGroupName string) (bool, error) {
 	lg := log.FromContext(r.ctx)
 	*currentSts.Spec.Replicas++
@@ -163,11 +399,13 @@ func (r *ScalerReconciler) increaseOneNode(currentSts appsv1.StatefulSet, nodePo
 	return false, nil
 }
 
-func (r *ScalerReconciler) decreaseOneNode(currentStatus opsterv1.ComponentStatus, currentSts appsv1.StatefulSet, nodePoolGroupName string, smartDecrease bool) (bool, error) {
+func (r *ScalerReconciler) decreaseOneNode(currentStatus opsterv1.ComponentStatus, currentSts appsv1.StatefulSet, nodePool *opsterv1.NodePool, smartDecrease bool) (bool, error) {
 	lg := log.FromContext(r.ctx)
+	nodePoolGroupName := nodePool.Component
 	*currentSts.Spec.Replicas--
 	annotations := map[string]string{"cluster-name": r.instance.GetName()}
 	lastReplicaNodeName := helpers.ReplicaHostName(currentSts, *currentSts.Spec.Replicas)
+	drain, _ := helpers.FindDrainStatus(r.instance.Status, nodePoolGroupName)
 	r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Scaler", "Start to decreaseing node %s on %s ", lastReplicaNodeName, nodePoolGroupName)
 	_, err := r.client.ReconcileResource(&currentSts, reconciler.StatePresent)
 	if err != nil {
@@ -178,6 +416,7 @@ func (r *ScalerReconciler) decreaseOneNode(currentStatus opsterv1.ComponentStatu
 	lg.Info(fmt.Sprintf("Group: %s, Removed node %s", nodePoolGroupName, lastReplicaNodeName))
 	err = r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
 		instance.Status.ComponentsStatus = helpers.RemoveIt(currentStatus, instance.Status.ComponentsStatus)
+		instance.Status.Drains = helpers.RemoveDrainStatus(instance.Status.Drains, nodePoolGroupName)
 	})
 	if err != nil {
 		lg.Error(err, "failed to update status")
@@ -187,11 +426,20 @@ func (r *ScalerReconciler) decreaseOneNode(currentStatus opsterv1.ComponentStatu
 	if !smartDecrease {
 		return false, err
 	}
-	username, password, err := helpers.UsernameAndPassword(r.client, r.instance)
-	if err != nil {
-		return true, err
+
+	// The node is empty, its data must not be picked up by a node added later. The PVC of a node removed after its
+	// drain timed out is kept, it holds the only copy of the remaining shards
+	if drain.RemainingShards == 0 && (nodePool.Persistence == nil || nodePool.Persistence.PersistenceSource.PVC != nil) {
+		pvc := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      builders.DataPVCName(lastReplicaNodeName),
+			Namespace: currentSts.Namespace,
+		}}
+		if err := r.client.DeletePVC(&pvc); err != nil && !k8serrors.IsNotFound(err) {
+			lg.Error(err, fmt.Sprintf("failed to delete pvc %s", pvc.Name))
+			r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Scaler", "Failed to delete pvc %s of removed node %s", pvc.Name, lastReplicaNodeName)
+		}
 	}
-	clusterClient, err := services.NewOsClusterClient(builders.URLForCluster(r.instance), username, password)
+	clusterClient, err := r.newClusterClient()
 	if err != nil {
 		lg.Error(err, "failed to create os client")
 		r.recorder.AnnotatedEventf(r.instance, annotations, "WARN", "failed to remove node exclude", "Group-%s . failed to remove node exclude %s", nodePoolGroupName, lastReplicaNodeName)
@@ -209,13 +457,9 @@ func (r *ScalerReconciler) decreaseOneNode(currentStatus opsterv1.ComponentStatu
 
 func (r *ScalerReconciler) excludeNode(currentStatus opsterv1.ComponentStatus, currentSts appsv1.StatefulSet, nodePoolGroupName string) error {
 	lg := log.FromContext(r.ctx)
-	username, password, err := helpers.UsernameAndPassword(r.client, r.instance)
 	annotations := map[string]string{"cluster-name": r.instance.GetName()}
-	if err != nil {
-		return err
-	}
 
-	clusterClient, err := services.NewOsClusterClient(builders.URLForCluster(r.instance), username, password)
+	clusterClient, err := r.newClusterClient()
 	if err != nil {
 		lg.Error(err, "failed to create os client")
 		r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Scaler", "Failed to create os client for scaling")
@@ -237,8 +481,14 @@ func (r *ScalerReconciler) excludeNode(currentStatus opsterv1.ComponentStatus, c
 		}
 		r.recorder.AnnotatedEventf(r.instance, annotations, "Normal", "Scaler", "Finished to Exclude %s/%s", r.instance.Namespace, r.instance.Name)
 		lg.Info(fmt.Sprintf("Group: %s, Excluded node: %s", nodePoolGroupName, lastReplicaNodeName))
+		drain := opsterv1.NodeDrainStatus{
+			Component: nodePoolGroupName,
+			Node:      lastReplicaNodeName,
+			StartTime: metav1.Now(),
+		}
 		err = r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
 			instance.Status.ComponentsStatus = helpers.Replace(currentStatus, componentStatus, instance.Status.ComponentsStatus)
+			instance.Status.Drains = helpers.SetDrainStatus(instance.Status.Drains, drain)
 		})
 		if err != nil {
 			lg.Error(err, "failed to update status")
@@ -272,19 +522,39 @@ func (r *ScalerReconciler) drainNode(currentStatus opsterv1.ComponentStatus, cur
 	lg := log.FromContext(r.ctx)
 	annotations := map[string]string{"cluster-name": r.instance.GetName()}
 	lastReplicaNodeName := helpers.ReplicaHostName(currentSts, *currentSts.Spec.Replicas-1)
-	username, password, err := helpers.UsernameAndPassword(r.client, r.instance)
+
+	clusterClient, err := r.newClusterClient()
 	if err != nil {
 		return err
 	}
-
-	clusterClient, err := services.NewOsClusterClient(builders.URLForCluster(r.instance), username, password)
+	shards, err := services.ShardsOnNode(clusterClient, lastReplicaNodeName)
 	if err != nil {
+		// Never remove a node without knowing it is empty
+		lg.Error(err, fmt.Sprintf("failed to check shards on node %s", lastReplicaNodeName))
 		return err
 	}
-	nodeNotEmpty, err := services.HasShardsOnNode(clusterClient, lastReplicaNodeName)
-	if nodeNotEmpty {
-		lg.Info(fmt.Sprintf("Group: %s, Waiting for node %s to drain", nodePoolGroupName, lastReplicaNodeName))
-		return err
+
+	drain, found := helpers.FindDrainStatus(r.instance.Status, nodePoolGroupName)
+	if !found || drain.Node != lastReplicaNodeName {
+		// The node was excluded before the drain was tracked in the status
+		drain = opsterv1.NodeDrainStatus{Component: nodePoolGroupName, Node: lastReplicaNodeName, StartTime: metav1.Now()}
+	}
+	drain.RemainingShards = int32(shards)
+
+	if shards > 0 {
+		lg.Info(fmt.Sprintf("Group: %s, Waiting for %d shards to move off node %s", nodePoolGroupName, shards, lastReplicaNodeName))
+		timeout, err := helpers.DrainTimeout(r.instance.Spec.ConfMgmt)
+		if err != nil {
+			lg.Error(err, "using the default drain timeout")
+			timeout = helpers.DefaultDrainTimeout
+		}
+		if time.Since(drain.StartTime.Time) > timeout {
+			drain.TimedOut = true
+			return r.drainTimedOut(currentStatus, drain, timeout)
+		}
+		return r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
+			instance.Status.Drains = helpers.SetDrainStatus(instance.Status.Drains, drain)
+		})
 	}
 
 	componentStatus := opsterv1.ComponentStatus{
@@ -295,6 +565,7 @@ func (r *ScalerReconciler) drainNode(currentStatus opsterv1.ComponentStatus, cur
 	lg.Info(fmt.Sprintf("Group: %s, Node %s is drained", nodePoolGroupName, lastReplicaNodeName))
 	err = r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
 		instance.Status.ComponentsStatus = helpers.Replace(currentStatus, componentStatus, instance.Status.ComponentsStatus)
+		instance.Status.Drains = helpers.SetDrainStatus(instance.Status.Drains, drain)
 	})
 	if err != nil {
 		lg.Error(err, "failed to update status")
@@ -304,6 +575,67 @@ func (r *ScalerReconciler) drainNode(currentStatus opsterv1.ComponentStatus, cur
 	return err
 }
 
+// drainTimedOut takes the drain timeout action for a drain that has not finished within the drain timeout. Proceed
+// removes the node with its remaining shards, Fail takes the node back into the cluster, the failed drain stays in the
+// status until the scale down is retried
+func (r *ScalerReconciler) drainTimedOut(currentStatus opsterv1.ComponentStatus, drain opsterv1.NodeDrainStatus, timeout time.Duration) error {
+	lg := log.FromContext(r.ctx)
+	annotations := map[string]string{"cluster-name": r.instance.GetName()}
+	componentStatus := opsterv1.ComponentStatus{
+		Component:   "Scaler",
+		Status:      "Drained",
+		Description: drain.Component,
+	}
+	if r.instance.Spec.ConfMgmt.DrainTimeoutAction == opsterv1.DrainTimeoutActionProceed {
+		lg.Info(fmt.Sprintf("Group: %s, Drain of node %s timed out, removing it with %d shards", drain.Component, drain.Node, drain.RemainingShards))
+		r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Scaler", "Drain of node %s has not finished after %s, removing it with %d shards remaining", drain.Node, timeout, drain.RemainingShards)
+	} else {
+		clusterClient, err := r.newClusterClient()
+		if err != nil {
+			lg.Error(err, "failed to create os client")
+			return err
+		}
+		// The drain stays running until the node is taken back, so that it is retried
+		if _, err := services.RemoveExcludeNodeHost(clusterClient, drain.Node); err != nil {
+			lg.Error(err, fmt.Sprintf("failed to remove exclude node %s", drain.Node))
+			r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Scaler", "Failed to remove node exclude - Group-%s , node  %s", drain.Component, drain.Node)
+			return err
+		}
+		componentStatus.Status = "DrainFailed"
+		retryTime := metav1.NewTime(time.Now().Add(timeout))
+		drain.RetryTime = &retryTime
+		lg.Info(fmt.Sprintf("Group: %s, Drain of node %s timed out, the node is no longer excluded", drain.Component, drain.Node))
+		r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Scaler", "Drain of node %s has not finished after %s, %d shards remaining, scale down of %s failed and is retried in %s", drain.Node, timeout, drain.RemainingShards, drain.Component, timeout)
+	}
+	err := r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
+		instance.Status.ComponentsStatus = helpers.Replace(currentStatus, componentStatus, instance.Status.ComponentsStatus)
+		instance.Status.Drains = helpers.SetDrainStatus(instance.Status.Drains, drain)
+	})
+	if err != nil {
+		lg.Error(err, "failed to update status")
+	}
+	return err
+}
+
+// retryFailedDrain removes a scale down whose drain failed from the status once its retry time has passed, so that
+// the scale down starts over with a new drain
+func (r *ScalerReconciler) retryFailedDrain(currentStatus opsterv1.ComponentStatus, nodePoolGroupName string) error {
+	lg := log.FromContext(r.ctx)
+	drain, found := helpers.FindDrainStatus(r.instance.Status, nodePoolGroupName)
+	if found && drain.RetryTime != nil && time.Now().Before(drain.RetryTime.Time) {
+		return nil
+	}
+	lg.Info(fmt.Sprintf("Group: %s, Retrying the scale down after its drain failed", nodePoolGroupName))
+	err := r.client.UpdateOpenSearchClusterStatus(client.ObjectKeyFromObject(r.instance), func(instance *opsterv1.OpenSearchCluster) {
+		instance.Status.ComponentsStatus = helpers.RemoveIt(currentStatus, instance.Status.ComponentsStatus)
+		instance.Status.Drains = helpers.RemoveDrainStatus(instance.Status.Drains, nodePoolGroupName)
+	})
+	if err != nil {
+		lg.Error(err, "failed to update status")
+	}
+	return err
+}
+
 func (r *ScalerReconciler) cleanupStatefulSets(result *reconciler.CombinedResult) {
 	stsList, err := r.client.ListStatefulSets(client.InNamespace(r.instance.Name),
 		client.MatchingLabels{helpers.ClusterLabel: r.instance.Name})
@@ -328,12 +660,8 @@ func (r *ScalerReconciler) removeStatefulSet(sts appsv1.StatefulSet) (*ctrl.Resu
 	}
 
 	// Gracefully remove nodes
-	username, password, err := helpers.UsernameAndPassword(r.client, r.instance)
-	if err != nil {
-		return nil, err
-	}
 	annotations := map[string]string{"cluster-name": r.instance.GetName()}
-	clusterClient, err := services.NewOsClusterClient(builders.URLForCluster(r.instance), username, password)
+	clusterClient, err := r.newClusterClient()
 	if err != nil {
 		lg.Error(err, "failed to create os client")
 		r.recorder.AnnotatedEventf(r.instance, annotations, "Warning", "Scaler", "Failed to create os client")
@@ -0,0 +1,525 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("scaler reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		scaler     *ScalerReconciler
+		instance   *opsterv1.OpenSearchCluster
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+		sts        appsv1.StatefulSet
+
+		clusterUrl string
+		// The value of cluster.routing.allocation.exclude._name, nil if it is not set
+		excluded *string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		recorder = record.NewFakeRecorder(20)
+		instance = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster",
+				Namespace: "test-scaler",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "data",
+						Replicas:  3,
+						Roles:     []string{"data"},
+					},
+				},
+			},
+			Status: opsterv1.ClusterStatus{
+				Initialized: true,
+			},
+		}
+		sts = appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-data",
+				Namespace: "test-scaler",
+			},
+			Spec:   appsv1.StatefulSetSpec{Replicas: pointer.Int32(3)},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 3},
+		}
+		excluded = nil
+
+		clusterUrl = "https://cluster.test-scaler.svc.cluster.local:9200/"
+		transport.RegisterResponder(http.MethodHead, clusterUrl, httpmock.NewStringResponder(200, "OK"))
+		transport.RegisterResponder(http.MethodGet, clusterUrl, httpmock.NewStringResponder(200, "{}"))
+		transport.RegisterResponder(
+			http.MethodGet,
+			clusterUrl+"_cluster/settings",
+			func(req *http.Request) (*http.Response, error) {
+				settings := map[string]interface{}{}
+				if excluded != nil {
+					settings["cluster"] = map[string]interface{}{"routing": map[string]interface{}{"allocation": map[string]interface{}{"exclude": map[string]interface{}{"_name": *excluded}}}}
+				}
+				return httpmock.NewJsonResponse(200, map[string]interface{}{"persistent": map[string]interface{}{}, "transient": settings})
+			},
+		)
+		transport.RegisterResponder(
+			http.MethodPut,
+			clusterUrl+"_cluster/settings",
+			func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				Expect(err).ToNot(HaveOccurred())
+				var settings struct {
+					Transient struct {
+						Cluster struct {
+							Routing struct {
+								Allocation struct {
+									Exclude struct {
+										Name *string `json:"_name"`
+									} `json:"exclude"`
+								} `json:"allocation"`
+							} `json:"routing"`
+						} `json:"cluster"`
+					} `json:"transient"`
+				}
+				Expect(json.Unmarshal(body, &settings)).To(Succeed())
+				excluded = settings.Transient.Cluster.Routing.Allocation.Exclude.Name
+				return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+			},
+		)
+
+		mockClient.EXPECT().UpdateOpenSearchClusterStatus(mock.Anything, mock.Anything).
+			RunAndReturn(func(key types.NamespacedName, f func(*opsterv1.OpenSearchCluster)) error {
+				f(instance)
+				return nil
+			}).Maybe()
+		mockClient.EXPECT().ListStatefulSets(mock.Anything, mock.Anything).Return(appsv1.StatefulSetList{}, nil).Maybe()
+	})
+
+	JustBeforeEach(func() {
+		mockClient.EXPECT().GetStatefulSet("cluster-data", "test-scaler").RunAndReturn(func(string, string) (appsv1.StatefulSet, error) {
+			return *sts.DeepCopy(), nil
+		})
+		scaler = &ScalerReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			recorder:          recorder,
+			instance:          instance,
+			osClientTransport: transport,
+		}
+	})
+
+	events := func() []string {
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		return events
+	}
+
+	scalerStatus := func(status string) opsterv1.ComponentStatus {
+		return opsterv1.ComponentStatus{Component: "Scaler", Status: status, Description: "data"}
+	}
+
+	Context("a node is excluded for a scale down", func() {
+		BeforeEach(func() {
+			excluded = pointer.String("cluster-data-2")
+			instance.Status.ComponentsStatus = []opsterv1.ComponentStatus{scalerStatus("Excluded")}
+			instance.Status.Drains = []opsterv1.NodeDrainStatus{{Component: "data", Node: "cluster-data-2", StartTime: metav1.Now(), RemainingShards: 4}}
+		})
+
+		When("the desired replicas return to the current count", func() {
+			It("should take the node back into the cluster", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(excluded).To(BeNil())
+				Expect(instance.Status.ComponentsStatus).To(BeEmpty())
+				Expect(instance.Status.Drains).To(BeEmpty())
+				Expect(events()).To(ContainElement(ContainSubstring("Scale down of data was called off, node cluster-data-2 is no longer excluded")))
+			})
+		})
+
+		When("the desired replicas turn into a scale up", func() {
+			BeforeEach(func() {
+				instance.Spec.NodePools[0].Replicas = 4
+			})
+
+			It("should take the node back into the cluster before adding nodes", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(excluded).To(BeNil())
+				Expect(instance.Status.ComponentsStatus).To(BeEmpty())
+				Expect(instance.Status.Drains).To(BeEmpty())
+				// The node is added in the next reconcile, once the scale down is gone from the status
+				mockClient.AssertNotCalled(GinkgoT(), "ReconcileResource", mock.Anything, mock.Anything)
+			})
+		})
+
+		When("other nodes are excluded as well", func() {
+			BeforeEach(func() {
+				excluded = pointer.String("cluster-masters-2,cluster-data-2")
+			})
+
+			It("should only take the node of the scale down back", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(excluded).To(Equal(pointer.String("cluster-masters-2")))
+			})
+		})
+	})
+
+	When("the node of a called off scale down is drained", func() {
+		BeforeEach(func() {
+			excluded = pointer.String("cluster-data-2")
+			instance.Status.ComponentsStatus = []opsterv1.ComponentStatus{scalerStatus("Drained")}
+			instance.Status.Drains = []opsterv1.NodeDrainStatus{{Component: "data", Node: "cluster-data-2", StartTime: metav1.Now()}}
+		})
+
+		It("should not remove the node", func() {
+			_, err := scaler.Reconcile()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(excluded).To(BeNil())
+			Expect(instance.Status.ComponentsStatus).To(BeEmpty())
+			Expect(instance.Status.Drains).To(BeEmpty())
+			mockClient.AssertNotCalled(GinkgoT(), "ReconcileResource", mock.Anything, mock.Anything)
+			mockClient.AssertNotCalled(GinkgoT(), "DeletePVC", mock.Anything)
+		})
+	})
+
+	When("a data node pool is scaled down without opting into draining", func() {
+		BeforeEach(func() {
+			instance.Spec.NodePools[0].Replicas = 2
+		})
+
+		It("should remove the node without draining it and keep its pvc", func() {
+			mockClient.EXPECT().ReconcileResource(mock.Anything, mock.Anything).
+				RunAndReturn(func(obj runtime.Object, _ reconciler.DesiredState) (*ctrl.Result, error) {
+					Expect(obj.(*appsv1.StatefulSet).Spec.Replicas).To(Equal(pointer.Int32(2)))
+					return nil, nil
+				})
+
+			_, err := scaler.Reconcile()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(excluded).To(BeNil())
+			Expect(instance.Status.Drains).To(BeEmpty())
+			mockClient.AssertNotCalled(GinkgoT(), "DeletePVC", mock.Anything)
+		})
+	})
+
+	Context("the node pool is autoscaled", func() {
+		var (
+			// The cpu usage reported by the nodes of the pool
+			cpu float64
+			// The relocating shards reported by the cluster health
+			relocating int
+		)
+
+		BeforeEach(func() {
+			instance.Spec.NodePools[0].Autoscaling = &opsterv1.NodePoolAutoscaling{
+				MinReplicas:          1,
+				MaxReplicas:          6,
+				TargetCPUUtilization: pointer.Int32(50),
+			}
+			cpu = 20
+			relocating = 0
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl+"_nodes/stats",
+				func(req *http.Request) (*http.Response, error) {
+					nodes := map[string]interface{}{}
+					for _, name := range []string{"cluster-data-0", "cluster-data-1", "cluster-data-2"} {
+						nodes[name] = map[string]interface{}{
+							"name": name,
+							"os":   map[string]interface{}{"cpu": map[string]interface{}{"percent": cpu}},
+						}
+					}
+					return httpmock.NewJsonResponse(200, map[string]interface{}{"nodes": nodes})
+				},
+			)
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl+"_cluster/health",
+				func(req *http.Request) (*http.Response, error) {
+					return httpmock.NewJsonResponse(200, map[string]interface{}{"status": "green", "relocating_shards": relocating})
+				},
+			)
+		})
+
+		When("the usage is below the target", func() {
+			It("should record the decision and exclude the last node", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.Autoscaling).To(HaveLen(1))
+				status := instance.Status.Autoscaling[0]
+				Expect(status.Component).To(Equal("data"))
+				Expect(status.DesiredReplicas).To(Equal(int32(2)))
+				Expect(status.CPUUtilization).To(Equal(pointer.Int32(20)))
+				Expect(status.Reason).To(Equal("cpu utilization is 20%, target 50%"))
+				Expect(status.LastScaleTime).ToNot(BeNil())
+				Expect(excluded).To(Equal(pointer.String("cluster-data-2")))
+				Expect(events()).To(ContainElement(ContainSubstring("Scaling data from 3 to 2 replicas")))
+			})
+		})
+
+		When("shards are relocating", func() {
+			BeforeEach(func() {
+				relocating = 2
+			})
+
+			It("should wait before scaling down", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.Autoscaling).To(HaveLen(1))
+				Expect(instance.Status.Autoscaling[0].DesiredReplicas).To(Equal(int32(3)))
+				Expect(instance.Status.Autoscaling[0].Reason).To(ContainSubstring("waiting for 2 relocating shards"))
+				Expect(instance.Status.Autoscaling[0].LastScaleTime).To(BeNil())
+				Expect(excluded).To(BeNil())
+			})
+		})
+
+		When("the pool was scaled within the stabilization window", func() {
+			var lastScaleTime metav1.Time
+
+			BeforeEach(func() {
+				lastScaleTime = metav1.NewTime(time.Now().Add(-time.Minute))
+				instance.Status.Autoscaling = []opsterv1.NodePoolAutoscalingStatus{
+					{Component: "data", DesiredReplicas: 3, LastScaleTime: &lastScaleTime},
+				}
+			})
+
+			It("should not scale down", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.Autoscaling).To(HaveLen(1))
+				Expect(instance.Status.Autoscaling[0].DesiredReplicas).To(Equal(int32(3)))
+				Expect(instance.Status.Autoscaling[0].Reason).To(ContainSubstring("scale down stabilization window has not passed"))
+				Expect(instance.Status.Autoscaling[0].LastScaleTime).To(Equal(&lastScaleTime))
+				Expect(excluded).To(BeNil())
+			})
+		})
+
+		When("the usage rises during a scale down", func() {
+			BeforeEach(func() {
+				cpu = 50
+				excluded = pointer.String("cluster-data-2")
+				instance.Status.ComponentsStatus = []opsterv1.ComponentStatus{scalerStatus("Excluded")}
+				instance.Status.Drains = []opsterv1.NodeDrainStatus{{Component: "data", Node: "cluster-data-2", StartTime: metav1.Now(), RemainingShards: 4}}
+				lastScaleTime := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+				instance.Status.Autoscaling = []opsterv1.NodePoolAutoscalingStatus{
+					{Component: "data", DesiredReplicas: 2, LastScaleTime: &lastScaleTime},
+				}
+			})
+
+			It("should call off the scale down", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.Autoscaling[0].DesiredReplicas).To(Equal(int32(3)))
+				Expect(excluded).To(BeNil())
+				Expect(instance.Status.ComponentsStatus).To(BeEmpty())
+				Expect(instance.Status.Drains).To(BeEmpty())
+				Expect(events()).To(ContainElements(
+					ContainSubstring("Calling off scaling data down to 2 replicas"),
+					ContainSubstring("Scale down of data was called off, node cluster-data-2 is no longer excluded"),
+				))
+			})
+		})
+
+		When("the pool is at its max replicas and scales vertically", func() {
+			BeforeEach(func() {
+				cpu = 75
+				instance.Spec.NodePools[0].Autoscaling.MaxReplicas = 3
+				instance.Spec.NodePools[0].Autoscaling.Vertical = &opsterv1.NodePoolVerticalAutoscaling{
+					MinResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					MaxResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				}
+				instance.Spec.NodePools[0].Resources = corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				}
+			})
+
+			It("should record the resources of the nodes", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.Autoscaling).To(HaveLen(1))
+				status := instance.Status.Autoscaling[0]
+				Expect(status.DesiredReplicas).To(Equal(int32(3)))
+				Expect(status.Resources).ToNot(BeNil())
+				Expect(status.Resources.Requests.Cpu().String()).To(Equal("3"))
+				Expect(status.LastScaleTime).ToNot(BeNil())
+				Expect(events()).To(ContainElement(ContainSubstring("Scaling the resources of data: cpu utilization is 75%, target 50%, cpu request 3")))
+			})
+
+			It("should wait for the nodes to be restarted", func() {
+				sts.Status.CurrentRevision = "cluster-data-1"
+				sts.Status.UpdateRevision = "cluster-data-2"
+
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.Autoscaling[0].Resources).To(BeNil())
+				Expect(instance.Status.Autoscaling[0].Reason).To(ContainSubstring("waiting for the nodes to be restarted before scaling their resources"))
+			})
+		})
+	})
+
+	Context("the node of a scale down is drained", func() {
+		var (
+			// The shards returned by _cat/shards
+			shards []map[string]string
+			// The calls that remove the node, in order
+			calls []string
+		)
+
+		BeforeEach(func() {
+			instance.Spec.General.DrainDataNodes = true
+			instance.Spec.NodePools[0].Replicas = 2
+			excluded = pointer.String("cluster-data-2")
+			instance.Status.ComponentsStatus = []opsterv1.ComponentStatus{scalerStatus("Excluded")}
+			instance.Status.Drains = []opsterv1.NodeDrainStatus{{Component: "data", Node: "cluster-data-2", StartTime: metav1.Now()}}
+			shards = []map[string]string{
+				{"index": "logs", "shard": "0", "prirep": "p", "state": "STARTED", "node": "cluster-data-0"},
+				{"index": "logs", "shard": "1", "prirep": "p", "state": "STARTED", "node": "cluster-data-2"},
+				{"index": "logs", "shard": "2", "prirep": "r", "state": "RELOCATING", "node": "cluster-data-2 -> 10.0.0.1 aBcD cluster-data-1"},
+			}
+			calls = nil
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl+"_cat/shards",
+				func(req *http.Request) (*http.Response, error) {
+					return httpmock.NewJsonResponse(200, shards)
+				},
+			)
+			mockClient.EXPECT().ReconcileResource(mock.Anything, mock.Anything).
+				RunAndReturn(func(obj runtime.Object, _ reconciler.DesiredState) (*ctrl.Result, error) {
+					Expect(obj.(*appsv1.StatefulSet).Spec.Replicas).To(Equal(pointer.Int32(2)))
+					calls = append(calls, "ReconcileResource")
+					return nil, nil
+				}).Maybe()
+			mockClient.EXPECT().DeletePVC(mock.Anything).
+				RunAndReturn(func(pvc *corev1.PersistentVolumeClaim) error {
+					Expect(pvc.Name).To(Equal("data-cluster-data-2"))
+					Expect(pvc.Namespace).To(Equal("test-scaler"))
+					calls = append(calls, "DeletePVC")
+					return nil
+				}).Maybe()
+		})
+
+		When("the node still holds shards", func() {
+			It("should keep the node excluded", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.ComponentsStatus).To(Equal([]opsterv1.ComponentStatus{scalerStatus("Excluded")}))
+				Expect(instance.Status.Drains).To(HaveLen(1))
+				Expect(instance.Status.Drains[0].RemainingShards).To(Equal(int32(2)))
+				Expect(instance.Status.Drains[0].TimedOut).To(BeFalse())
+				Expect(excluded).To(Equal(pointer.String("cluster-data-2")))
+				Expect(calls).To(BeEmpty())
+			})
+		})
+
+		When("the drain takes longer than the drain timeout", func() {
+			BeforeEach(func() {
+				instance.Status.Drains[0].StartTime = metav1.NewTime(time.Now().Add(-time.Hour))
+			})
+
+			It("should fail the scale down and take the node back", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.ComponentsStatus).To(Equal([]opsterv1.ComponentStatus{scalerStatus("DrainFailed")}))
+				Expect(instance.Status.Drains).To(HaveLen(1))
+				Expect(instance.Status.Drains[0].TimedOut).To(BeTrue())
+				Expect(instance.Status.Drains[0].RetryTime).ToNot(BeNil())
+				Expect(excluded).To(BeNil())
+				Expect(events()).To(ContainElement(ContainSubstring("Drain of node cluster-data-2 has not finished after 30m0s, 2 shards remaining, scale down of data failed and is retried in 30m0s")))
+				Expect(calls).To(BeEmpty())
+
+				// The scale down is not retried before the drain timeout has passed again
+				_, err = scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.ComponentsStatus).To(Equal([]opsterv1.ComponentStatus{scalerStatus("DrainFailed")}))
+				Expect(excluded).To(BeNil())
+			})
+
+			It("should retry the scale down once the drain timeout has passed again", func() {
+				retryTime := metav1.NewTime(time.Now().Add(-time.Minute))
+				instance.Status.Drains[0].TimedOut = true
+				instance.Status.Drains[0].RetryTime = &retryTime
+				instance.Status.ComponentsStatus = []opsterv1.ComponentStatus{scalerStatus("DrainFailed")}
+				excluded = nil
+
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.ComponentsStatus).To(BeEmpty())
+				Expect(instance.Status.Drains).To(BeEmpty())
+
+				_, err = scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.ComponentsStatus).To(Equal([]opsterv1.ComponentStatus{scalerStatus("Excluded")}))
+				Expect(excluded).To(Equal(pointer.String("cluster-data-2")))
+			})
+
+			It("should remove the node and keep its pvc if the scale down proceeds", func() {
+				instance.Spec.ConfMgmt.DrainTimeoutAction = opsterv1.DrainTimeoutActionProceed
+
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.ComponentsStatus).To(Equal([]opsterv1.ComponentStatus{scalerStatus("Drained")}))
+				Expect(instance.Status.Drains[0].TimedOut).To(BeTrue())
+				Expect(events()).To(ContainElement(ContainSubstring("Drain of node cluster-data-2 has not finished after 30m0s, removing it with 2 shards remaining")))
+
+				_, err = scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(calls).To(Equal([]string{"ReconcileResource"}))
+				Expect(instance.Status.Drains).To(BeEmpty())
+				Expect(excluded).To(BeNil())
+			})
+		})
+
+		When("the node holds no shards", func() {
+			BeforeEach(func() {
+				shards = shards[:1]
+			})
+
+			It("should remove the node and only then delete its pvc", func() {
+				_, err := scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(instance.Status.ComponentsStatus).To(Equal([]opsterv1.ComponentStatus{scalerStatus("Drained")}))
+				Expect(instance.Status.Drains[0].RemainingShards).To(BeZero())
+				Expect(calls).To(BeEmpty())
+
+				_, err = scaler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(calls).To(Equal([]string{"ReconcileResource", "DeletePVC"}))
+				Expect(instance.Status.ComponentsStatus).To(BeEmpty())
+				Expect(instance.Status.Drains).To(BeEmpty())
+				Expect(excluded).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,339 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	opensearchSchemaMigration = "OpensearchComponentTemplateSchemaMigration"
+
+	schemaMigrationPrivileges = "indices:admin/aliases/get, indices:admin/aliases, indices:admin/create, indices:admin/get, " +
+		"indices:admin/mappings/get, indices:data/write/reindex, indices:data/read/search, indices:data/write/index, " +
+		"cluster:monitor/task/get and indices:admin/close or indices:admin/delete"
+)
+
+// migrateSchema migrates the write index of the alias of the schema migration to a new index created from the
+// applied component template once the schema version of the spec is increased. The first schema version is recorded
+// without a migration. The migration takes one step per reconcile and is tracked in the status: the new index is
+// created and checked to have the settings and mappings of the template, it becomes the write index of the alias, the
+// documents of the old index are reindexed into it and the old index is removed from the alias and retired once the
+// new index holds at least as many documents. The write index is swapped before the reindex, so no document written
+// during the migration is lost, while searches through the alias may return documents of both indices until the old
+// one is retired. A failed migration is not retried, increasing the schema version again starts a new one. It returns
+// whether a migration is in progress or failed.
+func (r *ComponentTemplateReconciler) migrateSchema(template requests.ComponentTemplate) (bool, ctrl.Result, string, error) {
+	version := r.instance.Spec.SchemaVersion
+	status := r.instance.Status.Migration
+	if status != nil {
+		switch status.Phase {
+		case opsterv1.SchemaMigrationCompleted:
+		case opsterv1.SchemaMigrationFailed:
+			if status.SchemaVersion == version {
+				reason := fmt.Sprintf("schema migration to version %d failed: %s", version, status.Reason)
+				return true, ctrl.Result{}, reason, errors.New(reason)
+			}
+		default:
+			result, reason, err := r.stepSchemaMigration(*status, template)
+			return true, result, reason, err
+		}
+	}
+
+	if r.instance.Status.SchemaVersion != 0 && version <= r.instance.Status.SchemaVersion {
+		return false, ctrl.Result{}, "", nil
+	}
+	if r.instance.Status.SchemaVersion == 0 {
+		if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+			status.SchemaVersion = version
+		}); err != nil {
+			reason := fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return true, ctrl.Result{}, reason, err
+		}
+		return false, ctrl.Result{}, "", nil
+	}
+	result, reason, err := r.startSchemaMigration(version)
+	return true, result, reason, err
+}
+
+// startSchemaMigration records the migration of the write index of the alias to version once the cluster is green,
+// the alias has a write index and the new index does not exist yet
+func (r *ComponentTemplateReconciler) startSchemaMigration(version int64) (ctrl.Result, string, error) {
+	if result, deferred, err := r.deferSchemaMigration(); err != nil || deferred != "" {
+		return result, deferred, err
+	}
+
+	alias := r.instance.Spec.SchemaMigration.WriteAlias
+	indices, err := services.AliasIndices(r.ctx, r.osClient, alias)
+	if err != nil {
+		return r.schemaMigrationError("get the indices of the write alias", err)
+	}
+	source := aliasWriteIndex(indices)
+	target := fmt.Sprintf("%s-v%d", alias, version)
+	var reason string
+	if source == "" {
+		reason = fmt.Sprintf("write alias %s of the schema migration has no write index", alias)
+	} else if err := helpers.ValidateIndexPattern(target); err != nil || strings.ToLower(target) != target {
+		reason = fmt.Sprintf("index %s of the schema migration is not a valid index name", target)
+	} else if _, ok := indices[target]; ok || source == target {
+		reason = fmt.Sprintf("index %s of the schema migration is already in write alias %s", target, alias)
+	}
+	if reason == "" {
+		existing, err := services.ExistingIndices(r.ctx, r.osClient, []string{target})
+		if err != nil {
+			return r.schemaMigrationError("check the index of the schema migration", err)
+		}
+		if len(existing) > 0 {
+			reason = fmt.Sprintf("index %s of the schema migration already exists", target)
+		}
+	}
+	if reason != "" {
+		r.recorder.Event(r.instance, "Warning", opensearchSchemaMigration, reason)
+		return ctrl.Result{}, reason, errors.New(reason)
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchSchemaMigration,
+		fmt.Sprintf("migrating index %s of write alias %s to index %s for schema version %d", source, alias, target, version))
+	return r.setSchemaMigration(opsterv1.SchemaMigrationStatus{
+		SchemaVersion: version,
+		Phase:         opsterv1.SchemaMigrationCreatingIndex,
+		SourceIndex:   source,
+		TargetIndex:   target,
+		StartedAt:     metav1.Now(),
+	})
+}
+
+// aliasWriteIndex returns the index the alias marks as write index, or else its only index
+func aliasWriteIndex(indices map[string]bool) string {
+	for index, isWriteIndex := range indices {
+		if isWriteIndex {
+			return index
+		}
+	}
+	if len(indices) == 1 {
+		for index := range indices {
+			return index
+		}
+	}
+	return ""
+}
+
+// stepSchemaMigration takes the next step of the running schema migration
+func (r *ComponentTemplateReconciler) stepSchemaMigration(status opsterv1.SchemaMigrationStatus, template requests.ComponentTemplate) (ctrl.Result, string, error) {
+	alias := r.instance.Spec.SchemaMigration.WriteAlias
+	switch status.Phase {
+	case opsterv1.SchemaMigrationCreatingIndex:
+		if result, deferred, err := r.deferSchemaMigration(); err != nil || deferred != "" {
+			return result, deferred, err
+		}
+		mismatches, err := r.createSchemaMigrationIndex(status.TargetIndex, template)
+		if err != nil {
+			return r.schemaMigrationError(fmt.Sprintf("create index %s", status.TargetIndex), err)
+		}
+		if len(mismatches) > 0 {
+			// The new index is still empty and not in the alias
+			if err := services.DeleteIndexIfExists(r.ctx, r.osClient, status.TargetIndex); err != nil {
+				return r.schemaMigrationError(fmt.Sprintf("delete index %s", status.TargetIndex), err)
+			}
+			return r.failSchemaMigration(status, fmt.Sprintf("index %s was not created from the component template, check that an index template composed of it matches the index: %s",
+				status.TargetIndex, strings.Join(mismatches, "; ")))
+		}
+		status.Phase = opsterv1.SchemaMigrationSwappingAlias
+
+	case opsterv1.SchemaMigrationSwappingAlias:
+		if err := services.SwapWriteIndex(r.ctx, r.osClient, alias, status.SourceIndex, status.TargetIndex); err != nil {
+			return r.schemaMigrationError(fmt.Sprintf("make index %s the write index of alias %s", status.TargetIndex, alias), err)
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchSchemaMigration,
+			fmt.Sprintf("index %s is the write index of alias %s, reindexing index %s into it", status.TargetIndex, alias, status.SourceIndex))
+		status.Phase = opsterv1.SchemaMigrationReindexing
+
+	case opsterv1.SchemaMigrationReindexing:
+		if status.Task == "" {
+			task, err := services.Reindex(r.ctx, r.osClient, status.SourceIndex, status.TargetIndex)
+			if err != nil {
+				return r.schemaMigrationError(fmt.Sprintf("reindex index %s into %s", status.SourceIndex, status.TargetIndex), err)
+			}
+			status.Task = task
+			break
+		}
+		task, err := services.GetTask(r.ctx, r.osClient, status.Task)
+		if err != nil {
+			return r.schemaMigrationError("get the reindex task", err)
+		}
+		if !task.Completed {
+			return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+		}
+		if task.Error != nil {
+			return r.failSchemaMigration(status, fmt.Sprintf("failed to reindex index %s: %s", status.SourceIndex, task.Error.Reason))
+		}
+		if task.Response != nil && len(task.Response.Failures) > 0 {
+			return r.failSchemaMigration(status, fmt.Sprintf("%d documents of index %s failed to be reindexed", len(task.Response.Failures), status.SourceIndex))
+		}
+		status.Task = ""
+		status.Phase = opsterv1.SchemaMigrationRetiring
+
+	case opsterv1.SchemaMigrationRetiring:
+		if result, deferred, err := r.deferSchemaMigration(); err != nil || deferred != "" {
+			return result, deferred, err
+		}
+		sourceCount, err := services.CountDocuments(r.ctx, r.osClient, status.SourceIndex)
+		if err != nil {
+			return r.schemaMigrationError(fmt.Sprintf("count the documents of index %s", status.SourceIndex), err)
+		}
+		targetCount, err := services.CountDocuments(r.ctx, r.osClient, status.TargetIndex)
+		if err != nil {
+			return r.schemaMigrationError(fmt.Sprintf("count the documents of index %s", status.TargetIndex), err)
+		}
+		if targetCount < sourceCount {
+			return r.failSchemaMigration(status, fmt.Sprintf("index %s holds %d documents, fewer than the %d documents of index %s",
+				status.TargetIndex, targetCount, sourceCount, status.SourceIndex))
+		}
+		if err := r.retireSchemaMigrationIndex(alias, status.SourceIndex); err != nil {
+			return r.schemaMigrationError(fmt.Sprintf("retire index %s", status.SourceIndex), err)
+		}
+		now := metav1.Now()
+		status.Phase = opsterv1.SchemaMigrationCompleted
+		status.CompletedAt = &now
+		r.recorder.Event(r.instance, "Normal", opensearchSchemaMigration,
+			fmt.Sprintf("index %s of write alias %s migrated to index %s for schema version %d", status.SourceIndex, alias, status.TargetIndex, status.SchemaVersion))
+	}
+	return r.setSchemaMigration(status)
+}
+
+// createSchemaMigrationIndex creates the new index, unless a previous attempt created it, and returns how its
+// settings and mappings differ from the component template
+func (r *ComponentTemplateReconciler) createSchemaMigrationIndex(indexName string, template requests.ComponentTemplate) ([]string, error) {
+	existing, err := services.ExistingIndices(r.ctx, r.osClient, []string{indexName})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		if err := services.CreateNewIndex(r.ctx, r.osClient, indexName, map[string]interface{}{}); err != nil {
+			return nil, err
+		}
+	}
+	settings, err := services.GetIndexSettings(r.ctx, r.osClient, indexName)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := services.GetIndexMappings(r.ctx, r.osClient, indexName)
+	if err != nil {
+		return nil, err
+	}
+	managed, err := r.ismManagedSettings(template)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.IndexMismatches(template.Template, settings, mappings, r.redaction, managed...)
+}
+
+// ismManagedSettings returns the settings of the template the ISM policy of the component template changes on its
+// indices, which are not compared with the template unless the conflict policy is Compare. A policy that does not
+// exist (yet) manages no settings.
+func (r *ComponentTemplateReconciler) ismManagedSettings(template requests.ComponentTemplate) ([]string, error) {
+	ismPolicy := r.instance.Spec.ISMPolicy
+	if ismPolicy == nil || ismPolicy.PolicyID == "" || ismPolicy.ConflictPolicy == opsterv1.ISMConflictCompare {
+		return nil, nil
+	}
+	policy, err := services.GetPolicy(r.ctx, r.osClient, ismPolicy.PolicyID)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get ISM policy %s: %w", ismPolicy.PolicyID, err)
+	}
+	return helpers.ISMManagedSettings(policy.Policy, template.Template.Settings)
+}
+
+// retireSchemaMigrationIndex removes the old index from the alias, unless a previous attempt removed it, and
+// closes or deletes it as selected by the retire policy
+func (r *ComponentTemplateReconciler) retireSchemaMigrationIndex(alias string, indexName string) error {
+	indices, err := services.AliasIndices(r.ctx, r.osClient, alias)
+	if err != nil {
+		return err
+	}
+	if _, ok := indices[indexName]; ok {
+		if err := services.RemoveIndexFromAlias(r.ctx, r.osClient, alias, indexName); err != nil {
+			return err
+		}
+	}
+	switch r.instance.Spec.SchemaMigration.RetirePolicy {
+	case opsterv1.SchemaMigrationRetireKeep:
+		return nil
+	case opsterv1.SchemaMigrationRetireDelete:
+		return services.DeleteIndexIfExists(r.ctx, r.osClient, indexName)
+	default:
+		return services.CloseIndex(r.ctx, r.osClient, indexName)
+	}
+}
+
+// deferSchemaMigration defers the steps of the schema migration that add load or remove data while the cluster is
+// not green
+func (r *ComponentTemplateReconciler) deferSchemaMigration() (ctrl.Result, string, error) {
+	deferred, reason, err := r.deferForRequiredHealth(opsterv1.OpenSearchGreenHealth)
+	if err != nil {
+		return ctrl.Result{}, reason, err
+	}
+	if deferred != "" {
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, deferred)
+		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, deferred, nil
+	}
+	return ctrl.Result{}, "", nil
+}
+
+// schemaMigrationError reports a step of the schema migration that failed and is taken again in the next reconcile
+func (r *ComponentTemplateReconciler) schemaMigrationError(action string, err error) (ctrl.Result, string, error) {
+	if errors.Is(err, services.ErrForbidden) {
+		reason := fmt.Sprintf("operator user is not authorized to migrate the schema, check that it has the %s privileges", schemaMigrationPrivileges)
+		r.logger.Info(reason)
+		r.recorder.Event(r.instance, "Warning", opensearchForbidden, reason)
+		return ctrl.Result{}, reason, err
+	}
+	reason := fmt.Sprintf("schema migration failed to %s: %s", action, err)
+	r.logger.Error(err, reason)
+	r.recorder.Event(r.instance, "Warning", opensearchSchemaMigration, reason)
+	return ctrl.Result{}, reason, err
+}
+
+// failSchemaMigration records the schema migration as failed, it is not retried
+func (r *ComponentTemplateReconciler) failSchemaMigration(status opsterv1.SchemaMigrationStatus, reason string) (ctrl.Result, string, error) {
+	now := metav1.Now()
+	status.Phase = opsterv1.SchemaMigrationFailed
+	status.Task = ""
+	status.Reason = reason
+	status.CompletedAt = &now
+	r.recorder.Event(r.instance, "Warning", opensearchSchemaMigration, reason)
+	if _, statusReason, err := r.setSchemaMigration(status); err != nil {
+		return ctrl.Result{}, statusReason, err
+	}
+	reason = fmt.Sprintf("schema migration to version %d failed: %s", status.SchemaVersion, reason)
+	return ctrl.Result{}, reason, errors.New(reason)
+}
+
+// setSchemaMigration records the progress of the schema migration, and the migrated schema version once it
+// completed, and requeues for the next step
+func (r *ComponentTemplateReconciler) setSchemaMigration(migration opsterv1.SchemaMigrationStatus) (ctrl.Result, string, error) {
+	if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		status.Migration = &migration
+		if migration.Phase == opsterv1.SchemaMigrationCompleted {
+			status.SchemaVersion = migration.SchemaVersion
+		}
+	}); err != nil {
+		reason := fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if migration.Phase == opsterv1.SchemaMigrationCompleted {
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+	}
+	return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, "", nil
+}
@@ -0,0 +1,335 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchSearchTemplateExists      = "search template already exists in OpenSearch; not modifying"
+	opensearchSearchTemplateIDMismatch  = "OpensearchSearchTemplateIdMismatch"
+	opensearchSearchTemplateDrift       = "OpensearchSearchTemplateDrift"
+	opensearchSearchTemplateRenderError = "OpensearchSearchTemplateRenderError"
+)
+
+type SearchTemplateReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchSearchTemplate
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewSearchTemplateReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchSearchTemplate,
+	opts ...ReconcilerOption,
+) *SearchTemplateReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &SearchTemplateReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "searchtemplate"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "searchtemplate"),
+	}
+}
+
+func (r *SearchTemplateReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var templateID string
+	var contentHash string
+	var renderError string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchSearchTemplate)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchSearchTemplateError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchSearchTemplatePending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchSearchTemplateDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchSearchTemplateCreated
+				instance.Status.TemplateID = templateID
+				instance.Status.ContentHash = contentHash
+				instance.Status.RenderError = ""
+			}
+			if renderError != "" {
+				instance.Status.RenderError = renderError
+			}
+			if reason == opensearchSearchTemplateExists {
+				instance.Status.State = opsterv1.OpensearchSearchTemplateIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a search template refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSearchTemplate)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	templateID = r.instance.Name
+	if r.instance.Spec.TemplateID != "" {
+		templateID = r.instance.Spec.TemplateID
+	}
+
+	// Check search template state to make sure we don't touch preexisting search templates
+	if r.instance.Status.ExistingTemplate == nil {
+		_, err = services.GetStoredScript(r.ctx, r.osClient, templateID)
+		exists := err == nil
+		if err != nil && !errors.Is(err, services.ErrNotFound) {
+			reason = "failed to get search template status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		err = nil
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSearchTemplate)
+				instance.Status.ExistingTemplate = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If search template is existing do nothing
+	if *r.instance.Status.ExistingTemplate {
+		reason = opensearchSearchTemplateExists
+		return
+	}
+
+	// the template id is immutable, so check the old id (r.instance.Status.TemplateID) against the new
+	if r.instance.Status.TemplateID != "" && templateID != r.instance.Status.TemplateID {
+		reason = "cannot change the search template id"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchSearchTemplateIDMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource := helpers.TranslateSearchTemplateToRequest(r.instance.Spec)
+	desiredHash, err := storedScriptHash(resource)
+	if err != nil {
+		reason = "failed to hash search template"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	existing, err := services.GetStoredScript(r.ctx, r.osClient, templateID)
+	if err != nil && !errors.Is(err, services.ErrNotFound) {
+		reason = "failed to get search template from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	message := "search template created in opensearch"
+	if existing != nil {
+		var existingHash string
+		existingHash, err = storedScriptHash(*existing)
+		if err != nil {
+			reason = "failed to hash search template"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if existingHash == desiredHash {
+			r.logger.V(1).Info(fmt.Sprintf("search template %s is in sync", r.instance.Name))
+			contentHash = desiredHash
+			result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+			return
+		}
+		message = "search template updated in opensearch"
+		// The template differs from the one the operator applied last, so it was changed through the API
+		if r.instance.Status.ContentHash != "" && existingHash != r.instance.Status.ContentHash {
+			r.recorder.Event(r.instance, "Warning", opensearchSearchTemplateDrift, "search template was changed outside of the operator, restoring it")
+		}
+	}
+
+	// Render the template before storing it, OpenSearch only compiles it when a search uses it
+	err = services.RenderSearchTemplate(r.ctx, r.osClient, resource.Source, r.instance.Spec.Params)
+	if errors.Is(err, services.ErrTemplateRender) {
+		renderError = err.Error()
+		reason = "search template does not render"
+		r.recorder.Event(r.instance, "Warning", opensearchSearchTemplateRenderError, renderError)
+		return
+	} else if err != nil {
+		reason = "failed to render search template with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	err = services.PutStoredScript(r.ctx, r.osClient, templateID, resource)
+	if err != nil {
+		reason = "failed to update search template with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, message)
+
+	contentHash = desiredHash
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+func (r *SearchTemplateReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingTemplate == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingTemplate {
+		r.logger.Info("search template was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	templateID := r.instance.Name
+	if r.instance.Spec.TemplateID != "" {
+		templateID = r.instance.Spec.TemplateID
+	}
+
+	return services.DeleteStoredScript(r.ctx, r.osClient, templateID)
+}
@@ -0,0 +1,513 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("searchtemplate reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *SearchTemplateReconciler
+		instance   *opsterv1.OpensearchSearchTemplate
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster     *opsterv1.OpenSearchCluster
+		clusterUrl  string
+		templateUrl string
+		renderUrl   string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchSearchTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-searchtemplate",
+				Namespace: "test-searchtemplate",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchSearchTemplateSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				TemplateID: "my-template",
+				Source:     `{"query":{"match":{"title":"{{query}}"}}}`,
+				Params:     &apiextensionsv1.JSON{Raw: []byte(`{"query":"opensearch"}`)},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-searchtemplate",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		templateUrl = fmt.Sprintf("%s_scripts/my-template", clusterUrl)
+		renderUrl = fmt.Sprintf("%s_render/template", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &SearchTemplateReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a search template refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodGet,
+					templateUrl,
+					httpmock.NewStringResponder(404, `{"_id":"my-template","found":false}`).Once(failMessage),
+				)
+			})
+
+			It("should record that the search template does not exist", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{"Normal UnitTest exists is false"}))
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingTemplate = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingTemplate = pointer.Bool(false)
+			})
+
+			When("search template exists in opensearch and is the same", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						templateUrl,
+						httpmock.NewStringResponder(200, `{"_id":"my-template","found":true,"script":{
+							"lang": "mustache",
+							"source": "{\"query\":{\"match\":{\"title\":\"{{query}}\"}}}"
+						}}`).Once(failMessage),
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+						f(obj)
+						return nil
+					})
+				})
+
+				It("should only record the hash of the template", func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchSearchTemplateCreated))
+					Expect(instance.Status.TemplateID).To(Equal("my-template"))
+					Expect(instance.Status.ContentHash).ToNot(BeEmpty())
+				})
+			})
+
+			When("the spec of the search template changed", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					existingHash, err := storedScriptHash(requests.StoredScript{Lang: "mustache", Source: `{"query":{"match":{"name":"{{query}}"}}}`})
+					Expect(err).ToNot(HaveOccurred())
+					instance.Status.ContentHash = existingHash
+					transport.RegisterResponder(
+						http.MethodGet,
+						templateUrl,
+						httpmock.NewStringResponder(200, `{"_id":"my-template","found":true,"script":{
+							"lang": "mustache",
+							"source": "{\"query\":{\"match\":{\"name\":\"{{query}}\"}}}"
+						}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						renderUrl,
+						httpmock.NewStringResponder(200, `{"template_output":{"query":{"match":{"title":"opensearch"}}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						templateUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+						},
+					)
+				})
+
+				It("should update the search template", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						// Confirm all responders have been called
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s search template updated in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{"script":{"lang":"mustache","source":"{\"query\":{\"match\":{\"title\":\"{{query}}\"}}}"}}`))
+				})
+			})
+
+			When("the search template was changed outside of the operator", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(2)
+					appliedHash, err := storedScriptHash(requests.StoredScript{Lang: "mustache", Source: `{"query":{"match":{"title":"{{query}}"}}}`})
+					Expect(err).ToNot(HaveOccurred())
+					instance.Status.ContentHash = appliedHash
+					transport.RegisterResponder(
+						http.MethodGet,
+						templateUrl,
+						httpmock.NewStringResponder(200, `{"_id":"my-template","found":true,"script":{
+							"lang": "mustache",
+							"source": "{\"query\":{\"match_all\":{}}}"
+						}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						renderUrl,
+						httpmock.NewStringResponder(200, `{"template_output":{"query":{"match":{"title":"opensearch"}}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						templateUrl,
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+				})
+
+				It("should restore the search template", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{
+						fmt.Sprintf("Warning %s search template was changed outside of the operator, restoring it", opensearchSearchTemplateDrift),
+						fmt.Sprintf("Normal %s search template updated in opensearch", opensearchAPIUpdated),
+					}))
+				})
+			})
+
+			When("search template does not exist in opensearch", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						templateUrl,
+						httpmock.NewStringResponder(404, `{"_id":"my-template","found":false}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						renderUrl,
+						httpmock.NewStringResponder(200, `{"template_output":{"query":{"match":{"title":"opensearch"}}}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						templateUrl,
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+				})
+
+				It("should create the search template", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s search template created in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("the template does not render", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Source = `{"query":{"match":{"title":"{{query}"}}}`
+					transport.RegisterResponder(
+						http.MethodGet,
+						templateUrl,
+						httpmock.NewStringResponder(404, `{"_id":"my-template","found":false}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						renderUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(400, `{"error":{"type":"general_script_exception","reason":"Failed to compile inline script"},"status":400}`), nil
+						},
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+						f(obj)
+						return nil
+					})
+				})
+
+				It("should report the error in the status without storing the template", func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(MatchError(services.ErrTemplateRender))
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					renderError := "template does not render: general_script_exception: Failed to compile inline script"
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s %s", opensearchSearchTemplateRenderError, renderError)}))
+					Expect(body).To(MatchJSON(`{"source":"{\"query\":{\"match\":{\"title\":\"{{query}\"}}}","params":{"query":"opensearch"}}`))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchSearchTemplateError))
+					Expect(instance.Status.RenderError).To(Equal(renderError))
+				})
+			})
+
+			When("the id of the search template has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.TemplateID = "my-template"
+					instance.Spec.TemplateID = "new-template"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the search template id", opensearchSearchTemplateIDMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingTemplate = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingTemplate = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			When("cluster exists", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				It("should delete the search template", func() {
+					transport.RegisterResponder(
+						http.MethodDelete,
+						templateUrl,
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+				})
+
+				It("should ignore a search template that was already deleted", func() {
+					transport.RegisterResponder(
+						http.MethodDelete,
+						templateUrl,
+						httpmock.NewStringResponder(404, `{"_id":"my-template","result":"not_found"}`).Once(failMessage),
+					)
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,378 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchSecurityAnalyticsDetectorExists       = "security analytics detector already exists in OpenSearch; not modifying"
+	opensearchSecurityAnalyticsDetectorNameMismatch = "OpensearchSecurityAnalyticsDetectorNameMismatch"
+	opensearchInvalidSecurityAnalyticsDetector      = "OpensearchInvalidSecurityAnalyticsDetector"
+)
+
+type SecurityAnalyticsDetectorReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchSecurityAnalyticsDetector
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewSecurityAnalyticsDetectorReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchSecurityAnalyticsDetector,
+	opts ...ReconcilerOption,
+) *SecurityAnalyticsDetectorReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &SecurityAnalyticsDetectorReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "securityanalyticsdetector"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "securityanalyticsdetector"),
+	}
+}
+
+func (r *SecurityAnalyticsDetectorReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var detectorName string
+	var detectorID string
+	var enabled *bool
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchSecurityAnalyticsDetector)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchSecurityAnalyticsDetectorError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchSecurityAnalyticsDetectorPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchSecurityAnalyticsDetectorDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchSecurityAnalyticsDetectorCreated
+				instance.Status.DetectorName = detectorName
+				instance.Status.DetectorID = detectorID
+				instance.Status.Enabled = enabled
+			}
+			if reason == opensearchSecurityAnalyticsDetectorExists {
+				instance.Status.State = opsterv1.OpensearchSecurityAnalyticsDetectorIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a detector refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSecurityAnalyticsDetector)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	detectorName = r.instance.Name
+	if r.instance.Spec.Name != "" {
+		detectorName = r.instance.Spec.Name
+	}
+
+	// Check detector state to make sure we don't touch preexisting detectors
+	if r.instance.Status.ExistingDetector == nil {
+		var existingID string
+		existingID, err = services.FindSecurityAnalyticsDetectorID(r.ctx, r.osClient, detectorName)
+		if err != nil {
+			reason = "failed to get detector status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		exists := existingID != ""
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSecurityAnalyticsDetector)
+				instance.Status.ExistingDetector = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If detector is existing do nothing
+	if *r.instance.Status.ExistingDetector {
+		reason = opensearchSecurityAnalyticsDetectorExists
+		return
+	}
+
+	// the detector name is immutable, so check the old name (r.instance.Status.DetectorName) against the new
+	if r.instance.Status.DetectorName != "" && detectorName != r.instance.Status.DetectorName {
+		reason = "cannot change the detector name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchSecurityAnalyticsDetectorNameMismatch, reason)
+		return
+	}
+
+	// Custom rules are referenced by resource, OpenSearch needs the ids it generated for them
+	var customRuleIDs []string
+	for _, ref := range r.instance.Spec.CustomRules {
+		rule, getErr := r.client.GetOpensearchSecurityAnalyticsRule(ref.Name, r.instance.Namespace)
+		if getErr != nil && !k8serrors.IsNotFound(getErr) {
+			err = getErr
+			reason = fmt.Sprintf("failed to get security analytics rule %s", ref.Name)
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if k8serrors.IsNotFound(getErr) || rule.Status.RuleID == "" {
+			reason = fmt.Sprintf("waiting for security analytics rule %s to be created", ref.Name)
+			r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+			result = ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: 10 * time.Second,
+			}
+			return
+		}
+		customRuleIDs = append(customRuleIDs, rule.Status.RuleID)
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource, err := helpers.TranslateSecurityAnalyticsDetectorToRequest(r.instance.Spec, detectorName, customRuleIDs)
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidSecurityAnalyticsDetector, reason)
+		return
+	}
+
+	// Detectors are addressed by the id OpenSearch generates, fall back to the name if the id was not recorded
+	detectorID = r.instance.Status.DetectorID
+	if detectorID == "" {
+		detectorID, err = services.FindSecurityAnalyticsDetectorID(r.ctx, r.osClient, detectorName)
+		if err != nil {
+			reason = "failed to get detector from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+	}
+
+	var existing *responses.GetSecurityAnalyticsDetectorResponse
+	if detectorID != "" {
+		existing, err = services.GetSecurityAnalyticsDetector(r.ctx, r.osClient, detectorID)
+		if err != nil && !errors.Is(err, services.ErrNotFound) {
+			reason = "failed to get detector from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+	}
+
+	if existing == nil {
+		r.logger.V(1).Info(fmt.Sprintf("detector %s not found, creating", detectorName))
+		detectorID, err = services.CreateSecurityAnalyticsDetector(r.ctx, r.osClient, resource)
+		if err != nil {
+			reason = "failed to create detector with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "detector created in opensearch")
+		enabled = &resource.Enabled
+	} else {
+		var equal bool
+		equal, err = helpers.SecurityAnalyticsDetectorsEqual(resource, &existing.Detector)
+		if err != nil {
+			reason = "failed to compare the detector with the one in OpenSearch"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if !equal {
+			// The detector is replaced as a whole, which also enables or disables it
+			err = services.UpdateSecurityAnalyticsDetector(r.ctx, r.osClient, detectorID, resource)
+			if err != nil {
+				reason = "failed to update detector with OpenSearch API"
+				r.logger.Error(err, reason)
+				r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+				return
+			}
+			r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "detector updated in opensearch")
+			enabled = &resource.Enabled
+		} else {
+			r.logger.V(1).Info(fmt.Sprintf("detector %s is in sync", r.instance.Name))
+			var existingEnabled bool
+			existingEnabled, err = helpers.SecurityAnalyticsDetectorEnabled(&existing.Detector)
+			if err != nil {
+				reason = "failed to read the state of the detector in OpenSearch"
+				r.logger.Error(err, reason)
+				r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+				return
+			}
+			enabled = &existingEnabled
+		}
+	}
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+func (r *SecurityAnalyticsDetectorReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingDetector == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingDetector {
+		r.logger.Info("detector was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	detectorName := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		detectorName = r.instance.Spec.Name
+	}
+
+	detectorID := r.instance.Status.DetectorID
+	if detectorID == "" {
+		detectorID, err = services.FindSecurityAnalyticsDetectorID(r.ctx, r.osClient, detectorName)
+		if err != nil {
+			return err
+		}
+	}
+	if detectorID == "" {
+		r.logger.V(1).Info("detector already deleted from opensearch")
+		return nil
+	}
+
+	return services.DeleteSecurityAnalyticsDetector(r.ctx, r.osClient, detectorID)
+}
@@ -0,0 +1,536 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("securityanalyticsdetector reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *SecurityAnalyticsDetectorReconciler
+		instance   *opsterv1.OpensearchSecurityAnalyticsDetector
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster     *opsterv1.OpenSearchCluster
+		clusterUrl  string
+		searchUrl   string
+		detectorUrl string
+	)
+
+	// detector as OpenSearch returns it for the spec of the instance
+	const existingDetector = `{
+		"type": "detector",
+		"name": "windows-detector",
+		"detector_type": "windows",
+		"enabled": true,
+		"enabled_time": 1700000000000,
+		"schedule": {"period": {"interval": 1, "unit": "MINUTES"}},
+		"inputs": [{"detector_input": {
+			"description": "",
+			"indices": ["windows-*"],
+			"custom_rules": [],
+			"pre_packaged_rules": [{"id": "06724b9a-52fc-11ed-bdc3-0242ac120002"}]
+		}}],
+		"triggers": [],
+		"monitor_id": ["ZRnFMIwB"],
+		"rule_topic_index": ".opensearch-sap-windows-detectors-queries",
+		"last_update_time": 1700000000000
+	}`
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchSecurityAnalyticsDetector{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-securityanalyticsdetector",
+				Namespace: "test-securityanalyticsdetector",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchSecurityAnalyticsDetectorSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Name:             "windows-detector",
+				DetectorType:     "windows",
+				Schedule:         "1m",
+				Indices:          []string{"windows-*"},
+				PrePackagedRules: []string{"06724b9a-52fc-11ed-bdc3-0242ac120002"},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-securityanalyticsdetector",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		searchUrl = fmt.Sprintf("%s_plugins/_security_analytics/detectors/_search", clusterUrl)
+		detectorUrl = fmt.Sprintf("%s_plugins/_security_analytics/detectors/abc123", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &SecurityAnalyticsDetectorReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a detector refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+			})
+
+			When("a detector with the name exists", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[
+							{"_id":"def456","_source":{"name":"windows-detector-2"}},
+							{"_id":"abc123","_source":{"name":"windows-detector"}}
+						]}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the detector exists", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+				})
+			})
+
+			When("the index of the detectors does not exist", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(404, `{"error":{"type":"index_not_found_exception"}}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the detector does not exist", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is false"}))
+				})
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDetector = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDetector = pointer.Bool(false)
+				instance.Status.DetectorID = "abc123"
+			})
+
+			When("detector is the same", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl,
+						httpmock.NewStringResponder(200, `{"_id":"abc123","_version":2,"detector":`+existingDetector+`}`).Once(failMessage),
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+						f(obj)
+						return nil
+					})
+				})
+
+				It("should only report the state of the detector", func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchSecurityAnalyticsDetectorCreated))
+					Expect(instance.Status.DetectorID).To(Equal("abc123"))
+					Expect(instance.Status.Enabled).To(Equal(pointer.Bool(true)))
+				})
+			})
+
+			When("detector gets disabled", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Enabled = pointer.Bool(false)
+					transport.RegisterResponder(
+						http.MethodGet,
+						detectorUrl,
+						httpmock.NewStringResponder(200, `{"_id":"abc123","_version":2,"detector":`+existingDetector+`}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						detectorUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"_id":"abc123","_version":3}`), nil
+						},
+					)
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+						f(obj)
+						return nil
+					})
+				})
+
+				It("should update the detector and report it disabled", func() {
+					reconciler.updateStatus = pointer.Bool(true)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					Expect(<-recorder.Events).To(Equal(fmt.Sprintf("Normal %s detector updated in opensearch", opensearchAPIUpdated)))
+					Expect(body).To(ContainSubstring(`"enabled":false`))
+					Expect(instance.Status.Enabled).To(Equal(pointer.Bool(false)))
+				})
+			})
+
+			When("a custom rule is not created yet", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.CustomRules = []corev1.LocalObjectReference{{Name: "suspicious-login"}}
+					mockClient.EXPECT().GetOpensearchSecurityAnalyticsRule("suspicious-login", instance.Namespace).
+						Return(opsterv1.OpensearchSecurityAnalyticsRule{}, NotFoundError())
+				})
+
+				It("should wait for the rule", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						result, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.Requeue).To(BeTrue())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s waiting for security analytics rule suspicious-login to be created", opensearchPending)}))
+				})
+			})
+
+			When("the id of the detector was not recorded and it does not exist", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.DetectorID = ""
+					instance.Spec.CustomRules = []corev1.LocalObjectReference{{Name: "suspicious-login"}}
+					mockClient.EXPECT().GetOpensearchSecurityAnalyticsRule("suspicious-login", instance.Namespace).
+						Return(opsterv1.OpensearchSecurityAnalyticsRule{Status: opsterv1.OpensearchSecurityAnalyticsRuleStatus{RuleID: "rule123"}}, nil)
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[]}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						clusterUrl+"_plugins/_security_analytics/detectors",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(201, `{"_id":"ghi789","_version":1,"detector":{}}`), nil
+						},
+					)
+				})
+
+				It("should create the detector with the ids of the custom rules", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s detector created in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{
+						"type": "detector",
+						"name": "windows-detector",
+						"detector_type": "windows",
+						"enabled": true,
+						"schedule": {"period": {"interval": 1, "unit": "MINUTES"}},
+						"inputs": [{"detector_input": {
+							"description": "",
+							"indices": ["windows-*"],
+							"custom_rules": [{"id": "rule123"}],
+							"pre_packaged_rules": [{"id": "06724b9a-52fc-11ed-bdc3-0242ac120002"}]
+						}}],
+						"triggers": []
+					}`))
+				})
+			})
+
+			When("the schedule is invalid", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Schedule = "30s"
+				})
+
+				It("should fail without calling OpenSearch", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf(
+						`Warning %s invalid detector: schedule "30s" has to be a positive whole number of minutes, e.g. 5m`, opensearchInvalidSecurityAnalyticsDetector,
+					)}))
+				})
+			})
+
+			When("the name of the detector has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.DetectorName = "windows-detector"
+					instance.Spec.Name = "new-detector"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the detector name", opensearchSecurityAnalyticsDetectorNameMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDetector = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingDetector = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			Context("cluster exists", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+				})
+
+				When("detector does not exist", func() {
+					BeforeEach(func() {
+						transport.RegisterResponder(
+							http.MethodPost,
+							searchUrl,
+							httpmock.NewStringResponder(200, `{"hits":{"hits":[]}}`).Once(failMessage),
+						)
+					})
+
+					It("should do nothing and exit", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+
+				When("detector exists", func() {
+					BeforeEach(func() {
+						instance.Status.DetectorID = "abc123"
+						transport.RegisterResponder(
+							http.MethodDelete,
+							detectorUrl,
+							httpmock.NewStringResponder(200, `{"_id":"abc123","_version":4}`).Once(failMessage),
+						)
+					})
+
+					It("should delete the detector", func() {
+						Expect(reconciler.Delete()).To(Succeed())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+					})
+				})
+			})
+		})
+	})
+})
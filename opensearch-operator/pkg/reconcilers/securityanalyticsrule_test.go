@@ -0,0 +1,393 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("securityanalyticsrule reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *SecurityAnalyticsRuleReconciler
+		instance   *opsterv1.OpensearchSecurityAnalyticsRule
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		searchUrl  string
+		rulesUrl   string
+	)
+
+	const rule = `title: Suspicious login
+logsource:
+  product: windows
+detection:
+  selection:
+    EventID: 4625
+  condition: selection
+level: high
+`
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchSecurityAnalyticsRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-securityanalyticsrule",
+				Namespace: "test-securityanalyticsrule",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchSecurityAnalyticsRuleSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Category: "windows",
+				Rule:     rule,
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-securityanalyticsrule",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		searchUrl = fmt.Sprintf("%s_plugins/_security_analytics/rules/_search?pre_packaged=false", clusterUrl)
+		rulesUrl = fmt.Sprintf("%s_plugins/_security_analytics/rules", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &SecurityAnalyticsRuleReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a rule refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("the rule was not created yet", func() {
+			var body string
+
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodPost,
+					rulesUrl+"?category=windows",
+					func(req *http.Request) (*http.Response, error) {
+						raw, err := io.ReadAll(req.Body)
+						if err != nil {
+							return nil, err
+						}
+						body = string(raw)
+						return httpmock.NewStringResponse(201, `{"_id":"rule123","_version":1}`), nil
+					},
+				)
+				mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+					f(obj)
+					return nil
+				})
+			})
+
+			It("should create the rule and record its id", func() {
+				reconciler.updateStatus = pointer.Bool(true)
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				Expect(<-recorder.Events).To(Equal(fmt.Sprintf("Normal %s rule created in opensearch", opensearchAPIUpdated)))
+				Expect(body).To(Equal(rule))
+				Expect(instance.Status.State).To(Equal(opsterv1.OpensearchSecurityAnalyticsRuleCreated))
+				Expect(instance.Status.RuleID).To(Equal("rule123"))
+			})
+		})
+
+		When("the rule is rejected", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+				transport.RegisterResponder(
+					http.MethodPost,
+					rulesUrl+"?category=windows",
+					httpmock.NewStringResponder(400, `{"error":{"reason":"Sigma rule must have a detection definitions"}}`).Once(failMessage),
+				)
+			})
+
+			It("should fail", func() {
+				go func() {
+					defer GinkgoRecover()
+					defer close(recorder.Events)
+					_, err := reconciler.Reconcile()
+					Expect(err).To(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				}()
+				var events []string
+				for msg := range recorder.Events {
+					events = append(events, msg)
+				}
+				Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s failed to create rule with OpenSearch API", opensearchAPIError)}))
+			})
+		})
+
+		When("the rule was created", func() {
+			BeforeEach(func() {
+				instance.Status.RuleID = "rule123"
+			})
+
+			When("rule is the same", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[{"_id":"rule123","_source":{
+							"category":"windows","title":"Suspicious login","level":"high","rule":"title: Suspicious login\nlogsource:\n  product: windows\ndetection:\n  selection:\n    EventID: 4625\n  condition: selection\nlevel: high"
+						}}]}}`).Once(failMessage),
+					)
+				})
+
+				It("should do nothing", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(BeEmpty())
+				})
+			})
+
+			When("rule is not the same", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[{"_id":"rule123","_source":{
+							"category":"windows","rule":"title: Suspicious login\nlevel: low"
+						}}]}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						rulesUrl+"/rule123?category=windows&forced=true",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"_id":"rule123","_version":2}`), nil
+						},
+					)
+				})
+
+				It("should update the rule", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s rule updated in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(Equal(rule))
+				})
+			})
+
+			When("rule was deleted in OpenSearch", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodPost,
+						searchUrl,
+						httpmock.NewStringResponder(200, `{"hits":{"hits":[]}}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						rulesUrl+"?category=windows",
+						httpmock.NewStringResponder(201, `{"_id":"rule456","_version":1}`).Once(failMessage),
+					)
+				})
+
+				It("should create the rule again", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s rule created in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("the rule was never created", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("the rule was created", func() {
+			BeforeEach(func() {
+				instance.Status.RuleID = "rule123"
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			When("cluster exists", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						rulesUrl+"/rule123?forced=true",
+						httpmock.NewStringResponder(200, `{"_id":"rule123","_version":3}`).Once(failMessage),
+					)
+				})
+
+				It("should delete the rule", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package reconcilers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const (
+	opensearchSimulatedIndex = "OpensearchComponentTemplateSimulatedIndex"
+
+	// maxSimulatedIndexSize limits the size of the resolved template of the simulated index kept in the status
+	maxSimulatedIndexSize = 32 * 1024
+)
+
+// simulateIndex records the index simulated for the simulateIndexName of the spec in the status, unless it was
+// already simulated with the applied component template. A failed simulation is only reported, the component template
+// is applied regardless.
+func (r *ComponentTemplateReconciler) simulateIndex(template requests.ComponentTemplate) error {
+	name := r.instance.Spec.SimulateIndexName
+	current := r.instance.Status.SimulatedIndex
+	if name == "" {
+		if current == nil {
+			return nil
+		}
+		return r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+			status.SimulatedIndex = nil
+		})
+	}
+
+	hash, err := componentTemplateHash(template)
+	if err != nil {
+		return err
+	}
+	if current != nil && current.Name == name && current.Hash == hash {
+		return nil
+	}
+
+	simulated, err := services.SimulateIndex(r.ctx, r.osClient, name)
+	if err != nil {
+		r.logger.Error(err, "failed to simulate index")
+		r.recorder.Event(r.instance, "Warning", opensearchSimulatedIndex, fmt.Sprintf("cannot record the simulated index %s: %s", name, err))
+		return nil
+	}
+	index := &opsterv1.ComponentTemplateSimulatedIndex{Name: name, Hash: hash}
+	for _, overlapping := range simulated.Overlapping {
+		index.Overlapping = append(index.Overlapping, overlapping.Name)
+	}
+	if simulated.Template.Size() > 0 {
+		raw, err := redactSimulatedTemplate(simulated.Template, r.redaction)
+		if err != nil {
+			return err
+		}
+		if len(raw) > maxSimulatedIndexSize {
+			index.Truncated = true
+		} else {
+			index.Template = &apiextensionsv1.JSON{Raw: raw}
+		}
+	}
+	return r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		status.SimulatedIndex = index
+	})
+}
+
+// redactSimulatedTemplate redacts the resolved template of a simulated index, which has the same layout as the
+// template of a component template so the redacted paths apply to it
+func redactSimulatedTemplate(template *apiextensionsv1.JSON, redaction *helpers.Redaction) ([]byte, error) {
+	var parsed interface{}
+	if err := helpers.UnmarshalPreservingNumbers(template.Raw, &parsed); err != nil {
+		return nil, err
+	}
+	redacted := redactValue(redaction.RedactValue("", map[string]interface{}{"template": parsed}))
+	return json.Marshal(redacted.(map[string]interface{})["template"])
+}
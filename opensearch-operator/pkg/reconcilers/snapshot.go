@@ -0,0 +1,73 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+)
+
+const (
+	// opensearchAwaitingSnapshot starts the reasons of changes deferred until a recent snapshot exists
+	opensearchAwaitingSnapshot = "deferring changes until a successful snapshot"
+
+	snapshotGetPrivilege   = "cluster:admin/snapshot/get"
+	repositoryGetPrivilege = "cluster:admin/repository/get"
+)
+
+// deferForSnapshot returns why changes are deferred if the requirement of the spec asks for a recent snapshot and
+// no successful snapshot newer than the max age exists, an empty string if the change can be applied. Failures to
+// read the snapshots fail the reconcile and are reported with the returned reason.
+func (r *ComponentTemplateReconciler) deferForSnapshot(requirement *opsterv1.RecentSnapshotRequirement) (deferred string, reason string, err error) {
+	if requirement == nil {
+		return "", "", nil
+	}
+	if serverlessEndpoint(r.cluster) {
+		reason = "a recent snapshot cannot be required with serverless endpoints, which have no snapshot API"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return "", reason, errors.New(reason)
+	}
+
+	repositories := []string{requirement.Repository}
+	if requirement.Repository == "" {
+		repositories, err = services.SnapshotRepositories(r.ctx, r.osClient)
+		if errors.Is(err, services.ErrForbidden) {
+			return "", r.forbidden(repositoryGetPrivilege), err
+		}
+		if err != nil {
+			reason = "failed to get snapshot repositories from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return "", reason, err
+		}
+	}
+
+	var latest time.Time
+	for _, repository := range repositories {
+		ended, err := services.LatestSuccessfulSnapshot(r.ctx, r.osClient, repository)
+		if errors.Is(err, services.ErrForbidden) {
+			return "", r.forbidden(snapshotGetPrivilege), err
+		}
+		if err != nil {
+			reason = fmt.Sprintf("failed to get snapshots of repository %s from OpenSearch API", repository)
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return "", reason, err
+		}
+		if ended.After(latest) {
+			latest = ended
+		}
+	}
+
+	maxAge := requirement.MaxAge.Duration
+	if latest.IsZero() {
+		return fmt.Sprintf("%s exists, none newer than %s was found", opensearchAwaitingSnapshot, maxAge), "", nil
+	}
+	if age := time.Since(latest); age > maxAge {
+		return fmt.Sprintf("%s newer than %s exists, the latest ended %s ago",
+			opensearchAwaitingSnapshot, maxAge, age.Truncate(time.Second)), "", nil
+	}
+	return "", "", nil
+}
@@ -0,0 +1,298 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchSnapshotPolicyExists       = "snapshot policy already exists in OpenSearch; not modifying"
+	opensearchSnapshotPolicyNameMismatch = "OpensearchSnapshotPolicyNameMismatch"
+)
+
+type SnapshotPolicyReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchSnapshotPolicy
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewSnapshotPolicyReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchSnapshotPolicy,
+	opts ...ReconcilerOption,
+) *SnapshotPolicyReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &SnapshotPolicyReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "snapshotpolicy"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "snapshotpolicy"),
+	}
+}
+
+func (r *SnapshotPolicyReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var policyName string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchSnapshotPolicy)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchSnapshotPolicyError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchSnapshotPolicyPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchSnapshotPolicyDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchSnapshotPolicyCreated
+				instance.Status.PolicyName = policyName
+			}
+			if reason == opensearchSnapshotPolicyExists {
+				instance.Status.State = opsterv1.OpensearchSnapshotPolicyIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster an snapshot policy refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSnapshotPolicy)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	policyName = r.instance.Name
+	if r.instance.Spec.PolicyName != "" {
+		policyName = r.instance.Spec.PolicyName
+	}
+
+	// Check snapshot policy state to make sure we don't touch preexisting snapshot policies
+	if r.instance.Status.ExistingSnapshotPolicy == nil {
+		var exists bool
+		exists, err = services.SnapshotPolicyExists(r.ctx, r.osClient, policyName)
+		if err != nil {
+			reason = "failed to get snapshot policy status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSnapshotPolicy)
+				instance.Status.ExistingSnapshotPolicy = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If snapshot policy is existing do nothing
+	if *r.instance.Status.ExistingSnapshotPolicy {
+		reason = opensearchSnapshotPolicyExists
+		return
+	}
+
+	// the policy name is immutable, so check the old name (r.instance.Status.PolicyName) against the new
+	if r.instance.Status.PolicyName != "" && policyName != r.instance.Status.PolicyName {
+		reason = "cannot change the snapshot policy name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchSnapshotPolicyNameMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource := helpers.TranslateSnapshotPolicyToRequest(r.instance.Spec)
+
+	existing, err := services.GetSnapshotPolicy(r.ctx, r.osClient, policyName)
+	if err != nil && !errors.Is(err, services.ErrNotFound) {
+		reason = "failed to get snapshot policy from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if errors.Is(err, services.ErrNotFound) {
+		r.logger.V(1).Info(fmt.Sprintf("snapshot policy %s not found, creating", policyName))
+		err = services.CreateSnapshotPolicy(r.ctx, r.osClient, resource, policyName)
+	} else if helpers.SnapshotPoliciesEqual(resource, existing.Policy) {
+		r.logger.V(1).Info(fmt.Sprintf("snapshot policy %s is in sync", r.instance.Name))
+		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		return
+	} else {
+		err = services.UpdateSnapshotPolicy(r.ctx, r.osClient, resource, existing.SeqNo, existing.PrimaryTerm, policyName)
+	}
+	if err != nil {
+		reason = "failed to update snapshot policy with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "snapshot policy updated in opensearch")
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+func (r *SnapshotPolicyReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingSnapshotPolicy == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingSnapshotPolicy {
+		r.logger.Info("snapshot policy was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	policyName := r.instance.Name
+	if r.instance.Spec.PolicyName != "" {
+		policyName = r.instance.Spec.PolicyName
+	}
+
+	exist, err := services.SnapshotPolicyExists(r.ctx, r.osClient, policyName)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		r.logger.V(1).Info("snapshot policy already deleted from opensearch")
+		return nil
+	}
+
+	return services.DeleteSnapshotPolicy(r.ctx, r.osClient, policyName)
+}
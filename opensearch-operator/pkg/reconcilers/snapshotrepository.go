@@ -0,0 +1,341 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchSnapshotRepositoryExists  = "snapshot repository already exists in OpenSearch; not modifying"
+	opensearchRepositoryNameMismatch    = "OpensearchSnapshotRepositoryNameMismatch"
+	opensearchInvalidSnapshotRepository = "OpensearchInvalidSnapshotRepository"
+)
+
+type SnapshotRepositoryReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchSnapshotRepository
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewSnapshotRepositoryReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchSnapshotRepository,
+	opts ...ReconcilerOption,
+) *SnapshotRepositoryReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &SnapshotRepositoryReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "snapshotrepository"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "snapshotrepository"),
+	}
+}
+
+func (r *SnapshotRepositoryReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var repositoryName string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchSnapshotRepository)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchSnapshotRepositoryError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchSnapshotRepositoryPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchSnapshotRepositoryDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchSnapshotRepositoryCreated
+				instance.Status.RepositoryName = repositoryName
+			}
+			if reason == opensearchSnapshotRepositoryExists {
+				instance.Status.State = opsterv1.OpensearchSnapshotRepositoryIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a snapshot repository refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSnapshotRepository)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	repositoryName = r.instance.Name
+	if r.instance.Spec.Name != "" {
+		repositoryName = r.instance.Spec.Name
+	}
+
+	// Check snapshot repository state to make sure we don't touch preexisting snapshot repositorys
+	if r.instance.Status.ExistingSnapshotRepository == nil {
+		var exists bool
+		exists, err = services.SnapshotRepositoryExists(r.ctx, r.osClient, repositoryName)
+		if err != nil {
+			reason = "failed to get snapshot repository status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchSnapshotRepository)
+				instance.Status.ExistingSnapshotRepository = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If snapshot repository is existing do nothing
+	if *r.instance.Status.ExistingSnapshotRepository {
+		reason = opensearchSnapshotRepositoryExists
+		return
+	}
+
+	// the repository name is immutable, so check the old name (r.instance.Status.RepositoryName) against the new
+	if r.instance.Status.RepositoryName != "" && repositoryName != r.instance.Status.RepositoryName {
+		reason = "cannot change the snapshot repository name"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchRepositoryNameMismatch, reason)
+		return
+	}
+
+	// validate the settings and credentials before the repository is registered
+	reason, err = r.validateRepository()
+	if err != nil {
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidSnapshotRepository, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource := helpers.TranslateSnapshotRepositoryToRequest(r.instance.Spec)
+
+	shouldUpdate, err := services.ShouldUpdateSnapshotRepository(r.ctx, r.osClient, repositoryName, resource)
+	if err != nil {
+		reason = "failed to get snapshot repository status from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	if !shouldUpdate {
+		r.logger.V(1).Info(fmt.Sprintf("snapshot repository %s is in sync", r.instance.Name))
+		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		return
+	}
+
+	err = services.CreateOrUpdateSnapshotRepository(r.ctx, r.osClient, repositoryName, resource, pointer.BoolDeref(r.instance.Spec.Verify, true))
+	if err != nil {
+		reason = "failed to register snapshot repository with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "snapshot repository registered in opensearch")
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+// validateRepository checks the spec and that the credentials secret holds the keys and is added to the keystore of the
+// cluster, which the repository plugins read the credentials from
+func (r *SnapshotRepositoryReconciler) validateRepository() (string, error) {
+	if err := helpers.ValidateSnapshotRepository(r.instance.Spec); err != nil {
+		return err.Error(), err
+	}
+
+	secretName, keys := helpers.SnapshotRepositoryCredentials(r.instance.Spec)
+	if secretName == "" {
+		return "", nil
+	}
+	secret, err := r.client.GetSecret(secretName, r.instance.Namespace)
+	if err != nil {
+		return fmt.Sprintf("failed to get credentials secret %s", secretName), err
+	}
+
+	var missing []string
+	var unmapped []string
+	for _, secretKey := range helpers.SortedKeys(keys) {
+		if _, ok := secret.Data[secretKey]; !ok {
+			missing = append(missing, secretKey)
+		}
+		if !helpers.KeystoreProvides(r.cluster.Spec.General.Keystore, secretName, secretKey, keys[secretKey]) {
+			unmapped = append(unmapped, fmt.Sprintf("%s to %s", secretKey, keys[secretKey]))
+		}
+	}
+	if len(missing) > 0 {
+		reason := fmt.Sprintf("credentials secret %s is missing the keys %s", secretName, strings.Join(missing, ", "))
+		return reason, fmt.Errorf("%s", reason)
+	}
+	if len(unmapped) > 0 {
+		reason := fmt.Sprintf("the keystore of the cluster does not map the keys of secret %s: %s", secretName, strings.Join(unmapped, ", "))
+		return reason, fmt.Errorf("%s", reason)
+	}
+	return "", nil
+}
+
+func (r *SnapshotRepositoryReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingSnapshotRepository == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingSnapshotRepository {
+		r.logger.Info("snapshot repository was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	repositoryName := r.instance.Name
+	if r.instance.Spec.Name != "" {
+		repositoryName = r.instance.Spec.Name
+	}
+
+	exist, err := services.SnapshotRepositoryExists(r.ctx, r.osClient, repositoryName)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		r.logger.V(1).Info("snapshot repository already deleted from opensearch")
+		return nil
+	}
+
+	return services.DeleteSnapshotRepository(r.ctx, r.osClient, repositoryName)
+}
@@ -0,0 +1,33 @@
+package reconcilers
+
+import "fmt"
+
+// StaleSpecPolicy selects what happens to the outcome of a reconcile when the spec was changed while it ran
+type StaleSpecPolicy string
+
+const (
+	// StaleSpecPolicyRequeue does not record the state of a reconcile of a stale spec and reconciles the new spec
+	// immediately. A failed reconcile is still recorded, so its error is not lost
+	StaleSpecPolicyRequeue StaleSpecPolicy = "requeue"
+	// StaleSpecPolicyRecord records the state anyway, the new spec is reconciled when the spec update is handled
+	StaleSpecPolicyRecord StaleSpecPolicy = "record"
+)
+
+// ParseStaleSpecPolicy returns the stale spec policy named by value, empty is StaleSpecPolicyRequeue
+func ParseStaleSpecPolicy(value string) (StaleSpecPolicy, error) {
+	switch StaleSpecPolicy(value) {
+	case "", StaleSpecPolicyRequeue:
+		return StaleSpecPolicyRequeue, nil
+	case StaleSpecPolicyRecord:
+		return StaleSpecPolicyRecord, nil
+	default:
+		return "", fmt.Errorf("invalid stale spec policy %q, must be %s or %s", value, StaleSpecPolicyRequeue, StaleSpecPolicyRecord)
+	}
+}
+
+// WithStaleSpecPolicy sets what happens to the outcome of a reconcile when the spec was changed while it ran
+func WithStaleSpecPolicy(policy StaleSpecPolicy) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.staleSpecPolicy = policy
+	}
+}
@@ -0,0 +1,129 @@
+package reconcilers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/expfmt"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultStatusPushJob is the job the states are pushed to the Pushgateway as
+	DefaultStatusPushJob = "opensearch-operator"
+	// statusPushRetryInterval is the interval a failed push is retried in
+	statusPushRetryInterval = time.Minute
+)
+
+// StatusPusher pushes the state of the component templates to a Prometheus Pushgateway, so operator instances the
+// metrics endpoint cannot be scraped from still report them. Reconciles only update the state in memory, the push
+// happens in the background and a failed push is retried without blocking or failing a reconcile.
+type StatusPusher struct {
+	pusher  *push.Pusher
+	states  *prometheus.GaugeVec
+	trigger chan struct{}
+
+	mu      sync.Mutex
+	current map[statusPushKey]string
+}
+
+type statusPushKey struct {
+	namespace string
+	name      string
+}
+
+func NewStatusPusher(url string, job string) *StatusPusher {
+	states := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opensearch_operator_component_template_state",
+		Help: "State of the component templates, 1 for the current state of a component template.",
+	}, []string{"namespace", "name", "state"})
+	return &StatusPusher{
+		pusher:  push.New(url, job).Collector(states).Format(expfmt.FmtText),
+		states:  states,
+		trigger: make(chan struct{}, 1),
+		current: map[statusPushKey]string{},
+	}
+}
+
+// WithStatusPusher pushes the state of reconciled objects to a Pushgateway, nil does not push them
+func WithStatusPusher(pusher *StatusPusher) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.statusPusher = pusher
+	}
+}
+
+// Report records the state of a component template to be pushed
+func (p *StatusPusher) Report(namespace string, name string, state string) {
+	if p == nil {
+		return
+	}
+	key := statusPushKey{namespace: namespace, name: name}
+	p.mu.Lock()
+	previous, ok := p.current[key]
+	if ok && previous == state {
+		p.mu.Unlock()
+		return
+	}
+	if ok {
+		p.states.DeleteLabelValues(namespace, name, previous)
+	}
+	p.states.WithLabelValues(namespace, name, state).Set(1)
+	p.current[key] = state
+	p.mu.Unlock()
+	p.schedule()
+}
+
+// Forget removes a deleted component template from the pushed states
+func (p *StatusPusher) Forget(namespace string, name string) {
+	if p == nil {
+		return
+	}
+	key := statusPushKey{namespace: namespace, name: name}
+	p.mu.Lock()
+	previous, ok := p.current[key]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	p.states.DeleteLabelValues(namespace, name, previous)
+	delete(p.current, key)
+	p.mu.Unlock()
+	p.schedule()
+}
+
+// schedule requests a push without waiting for it, a push already requested covers the new state as well
+func (p *StatusPusher) schedule() {
+	select {
+	case p.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start pushes the states whenever they change until the context is done, it implements manager.Runnable
+func (p *StatusPusher) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("status-pusher")
+	retry := time.NewTicker(statusPushRetryInterval)
+	defer retry.Stop()
+	failed := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.trigger:
+		case <-retry.C:
+			if !failed {
+				continue
+			}
+		}
+		// The gauges are safe to gather while they are updated. A state changed during the push schedules
+		// another push, so a state pushed halfway is corrected right after.
+		err := p.pusher.PushContext(ctx)
+		failed = err != nil
+		if failed {
+			logger.Error(err, "failed to push component template states to the pushgateway, retrying")
+		}
+	}
+}
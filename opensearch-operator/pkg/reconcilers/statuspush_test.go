@@ -0,0 +1,94 @@
+package reconcilers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("status pusher", func() {
+	var (
+		server  *httptest.Server
+		pushes  chan string
+		release chan struct{}
+		pusher  *StatusPusher
+		cancel  context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		pushes = make(chan string, 10)
+		release = make(chan struct{})
+		// The handler keeps the channels of its spec, a push still running when the spec ends must not pick up the next ones
+		received, released := pushes, release
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			received <- req.Method + " " + req.URL.Path + "\n" + string(body)
+			<-released
+			w.WriteHeader(http.StatusOK)
+		}))
+		pusher = NewStatusPusher(server.URL, DefaultStatusPushJob)
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go func(pusher *StatusPusher) {
+			defer GinkgoRecover()
+			Expect(pusher.Start(ctx)).To(Succeed())
+		}(pusher)
+	})
+
+	AfterEach(func() {
+		close(release)
+		cancel()
+		server.Close()
+	})
+
+	When("no pusher is configured", func() {
+		It("should ignore the states", func() {
+			var nilPusher *StatusPusher
+			nilPusher.Report("test-namespace", "test-template", "CREATED")
+			nilPusher.Forget("test-namespace", "test-template")
+		})
+	})
+
+	When("a state is reported", func() {
+		It("should push the current state of the component template", func() {
+			pusher.Report("test-namespace", "test-template", "PENDING")
+			var push string
+			Eventually(pushes).Should(Receive(&push))
+			Expect(push).To(HavePrefix("PUT /metrics/job/" + DefaultStatusPushJob))
+			Expect(push).To(ContainSubstring(`opensearch_operator_component_template_state{name="test-template",namespace="test-namespace",state="PENDING"} 1`))
+			release <- struct{}{}
+
+			pusher.Report("test-namespace", "test-template", "CREATED")
+			Eventually(pushes).Should(Receive(&push))
+			Expect(push).To(ContainSubstring(`opensearch_operator_component_template_state{name="test-template",namespace="test-namespace",state="CREATED"} 1`))
+			Expect(push).ToNot(ContainSubstring(`state="PENDING"`))
+			release <- struct{}{}
+
+			pusher.Forget("test-namespace", "test-template")
+			Eventually(pushes).Should(Receive(&push))
+			Expect(push).ToNot(ContainSubstring(`name="test-template"`))
+		})
+	})
+
+	When("the pushgateway does not respond", func() {
+		It("should not block reporting states", func() {
+			pusher.Report("test-namespace", "test-template", "PENDING")
+			Eventually(pushes).Should(Receive())
+
+			reported := make(chan struct{})
+			go func() {
+				defer close(reported)
+				for i := 0; i < 100; i++ {
+					pusher.Report("test-namespace", "test-template", "ERROR")
+					pusher.Report("test-namespace", "test-template", "CREATED")
+				}
+			}()
+			Eventually(reported, time.Second).Should(BeClosed())
+		})
+	})
+})
@@ -0,0 +1,325 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchStoredScriptExists     = "stored script already exists in OpenSearch; not modifying"
+	opensearchStoredScriptIDMismatch = "OpensearchStoredScriptIdMismatch"
+	opensearchStoredScriptDrift      = "OpensearchStoredScriptDrift"
+)
+
+type StoredScriptReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchStoredScript
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewStoredScriptReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchStoredScript,
+	opts ...ReconcilerOption,
+) *StoredScriptReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &StoredScriptReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "storedscript"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "storedscript"),
+	}
+}
+
+func (r *StoredScriptReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var scriptID string
+	var contentHash string
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchStoredScript)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchStoredScriptError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchStoredScriptPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchStoredScriptDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchStoredScriptCreated
+				instance.Status.ScriptID = scriptID
+				instance.Status.ContentHash = contentHash
+			}
+			if reason == opensearchStoredScriptExists {
+				instance.Status.State = opsterv1.OpensearchStoredScriptIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a stored script refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchStoredScript)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	scriptID = r.instance.Name
+	if r.instance.Spec.ScriptID != "" {
+		scriptID = r.instance.Spec.ScriptID
+	}
+
+	// Check stored script state to make sure we don't touch preexisting stored scripts
+	if r.instance.Status.ExistingScript == nil {
+		_, err = services.GetStoredScript(r.ctx, r.osClient, scriptID)
+		exists := err == nil
+		if err != nil && !errors.Is(err, services.ErrNotFound) {
+			reason = "failed to get stored script status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		err = nil
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchStoredScript)
+				instance.Status.ExistingScript = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			return
+		}
+	}
+
+	// If stored script is existing do nothing
+	if *r.instance.Status.ExistingScript {
+		reason = opensearchStoredScriptExists
+		return
+	}
+
+	// the script id is immutable, so check the old id (r.instance.Status.ScriptID) against the new
+	if r.instance.Status.ScriptID != "" && scriptID != r.instance.Status.ScriptID {
+		reason = "cannot change the stored script id"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchStoredScriptIDMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource := helpers.TranslateStoredScriptToRequest(r.instance.Spec)
+	desiredHash, err := storedScriptHash(resource)
+	if err != nil {
+		reason = "failed to hash stored script"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	existing, err := services.GetStoredScript(r.ctx, r.osClient, scriptID)
+	if err != nil && !errors.Is(err, services.ErrNotFound) {
+		reason = "failed to get stored script from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	message := "stored script created in opensearch"
+	if existing != nil {
+		var existingHash string
+		existingHash, err = storedScriptHash(*existing)
+		if err != nil {
+			reason = "failed to hash stored script"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+			return
+		}
+		if existingHash == desiredHash {
+			r.logger.V(1).Info(fmt.Sprintf("stored script %s is in sync", r.instance.Name))
+			contentHash = desiredHash
+			result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+			return
+		}
+		message = "stored script updated in opensearch"
+		// The script differs from the one the operator applied last, so it was changed through the API
+		if r.instance.Status.ContentHash != "" && existingHash != r.instance.Status.ContentHash {
+			r.recorder.Event(r.instance, "Warning", opensearchStoredScriptDrift, "stored script was changed outside of the operator, restoring it")
+		}
+	}
+	err = services.PutStoredScript(r.ctx, r.osClient, scriptID, resource)
+	if err != nil {
+		reason = "failed to update stored script with OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, message)
+
+	contentHash = desiredHash
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+// storedScriptHash returns the SHA1 hash of the language, source and options of the stored script
+func storedScriptHash(script requests.StoredScript) (string, error) {
+	raw, err := json.Marshal(script)
+	if err != nil {
+		return "", err
+	}
+	return util.GetSha1Sum(raw)
+}
+
+func (r *StoredScriptReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingScript == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingScript {
+		r.logger.Info("stored script was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	scriptID := r.instance.Name
+	if r.instance.Spec.ScriptID != "" {
+		scriptID = r.instance.Spec.ScriptID
+	}
+
+	return services.DeleteStoredScript(r.ctx, r.osClient, scriptID)
+}
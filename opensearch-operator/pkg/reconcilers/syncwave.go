@@ -0,0 +1,64 @@
+package reconcilers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncWave returns the sync wave set by the annotation of the object and whether it is set
+func syncWave(object metav1.Object) (int, bool, error) {
+	value, ok := object.GetAnnotations()[helpers.SyncWaveAnnotation]
+	if !ok {
+		return 0, false, nil
+	}
+	wave, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s annotation %q, it must be an integer", helpers.SyncWaveAnnotation, value)
+	}
+	return wave, true, nil
+}
+
+// deferForSyncWave returns the reason to defer an apply while component templates of a lower sync wave targeting the
+// same cluster are not synced yet. Only component templates with the sync wave annotation are ordered, templates
+// without it neither wait nor are waited for.
+func (r *ComponentTemplateReconciler) deferForSyncWave() (string, error) {
+	wave, ok, err := syncWave(r.instance)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	list, err := r.client.ListOpensearchComponentTemplates(client.InNamespace(r.instance.Namespace))
+	if err != nil {
+		return "", err
+	}
+	var waiting []string
+	for _, item := range list.Items {
+		if item.UID == r.instance.UID || componentTemplateClusterName(&item) != componentTemplateClusterName(r.instance) {
+			continue
+		}
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		// An invalid annotation is reported by the reconcile of the template carrying it
+		itemWave, ok, err := syncWave(&item)
+		if err != nil || !ok || itemWave >= wave {
+			continue
+		}
+		if item.Status.State == opsterv1.OpensearchComponentTemplateCreated || item.Status.State == opsterv1.OpensearchComponentTemplateIgnored {
+			continue
+		}
+		waiting = append(waiting, item.Name)
+	}
+	if len(waiting) == 0 {
+		return "", nil
+	}
+	sort.Strings(waiting)
+	return fmt.Sprintf("waiting for component templates of lower sync waves to be synced: %s", strings.Join(waiting, ", ")), nil
+}
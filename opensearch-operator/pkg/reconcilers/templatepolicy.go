@@ -0,0 +1,115 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// A component template violates a template policy
+const opensearchPolicyViolation = "OpensearchComponentTemplatePolicyViolation"
+
+// compiledTemplatePolicies keeps the policies compiled from the ConfigMap, they are only compiled again when the
+// data of the ConfigMap changes
+var compiledTemplatePolicies struct {
+	sync.Mutex
+	hash     string
+	policies *helpers.TemplatePolicies
+}
+
+// ParseTemplatePolicyConfigMap returns the ConfigMap named by value as namespace/name, empty names no ConfigMap
+func ParseTemplatePolicyConfigMap(value string) (types.NamespacedName, error) {
+	if value == "" {
+		return types.NamespacedName{}, nil
+	}
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid template policy ConfigMap %q, must be namespace/name", value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// WithTemplatePolicies checks component templates against the template policies of the ConfigMap before they are
+// applied, an empty name checks none
+func WithTemplatePolicies(configMap types.NamespacedName) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.templatePolicies = configMap
+	}
+}
+
+// checkTemplatePolicies fails the reconcile if a deny rule of the template policies reports the translated template,
+// and warns about the messages of their warn rules. The policies are read in every reconcile, from the cache of the
+// manager, so changed policies apply right away. Policies that cannot be read or parsed fail the
+// reconcile, so no template is applied unchecked.
+func (r *ComponentTemplateReconciler) checkTemplatePolicies(template requests.ComponentTemplate) (string, error) {
+	if r.templatePolicies.Name == "" {
+		return "", nil
+	}
+	configMap, err := r.client.GetConfigMap(r.templatePolicies.Name, r.templatePolicies.Namespace)
+	if err != nil {
+		reason := fmt.Sprintf("failed to get the template policies from ConfigMap %s", r.templatePolicies)
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return reason, err
+	}
+	policies, err := templatePolicies(r.ctx, configMap.Data)
+	if err != nil {
+		reason := err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchPolicyViolation, reason)
+		return reason, err
+	}
+	violations, err := helpers.EvaluateTemplatePolicies(r.ctx, policies, template)
+	if err != nil {
+		reason := err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchPolicyViolation, reason)
+		return reason, err
+	}
+
+	var denied []string
+	for _, violation := range violations {
+		if violation.Enforcement == helpers.PolicyEnforcementWarn {
+			r.recorder.Event(r.instance, "Warning", opensearchPolicyViolation, violation.String())
+			continue
+		}
+		denied = append(denied, violation.String())
+	}
+	if len(denied) == 0 {
+		return "", nil
+	}
+	reason := fmt.Sprintf("component template violates template policies: %s", strings.Join(denied, "; "))
+	r.recorder.Event(r.instance, "Warning", opensearchPolicyViolation, reason)
+	return reason, errors.New(reason)
+}
+
+// templatePolicies returns the policies compiled from the data of the ConfigMap
+func templatePolicies(ctx context.Context, data map[string]string) (*helpers.TemplatePolicies, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := util.GetSha1Sum(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	compiledTemplatePolicies.Lock()
+	defer compiledTemplatePolicies.Unlock()
+	if compiledTemplatePolicies.policies != nil && compiledTemplatePolicies.hash == hash {
+		return compiledTemplatePolicies.policies, nil
+	}
+	policies, err := helpers.ParseTemplatePolicies(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	compiledTemplatePolicies.hash = hash
+	compiledTemplatePolicies.policies = policies
+	return policies, nil
+}
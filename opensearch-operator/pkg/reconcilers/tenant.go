@@ -73,6 +73,9 @@ func (r *TenantReconciler) Reconcile() (retResult ctrl.Result, retErr error) {
 			if retResult.Requeue && retResult.RequeueAfter == 10*time.Second {
 				instance.Status.State = opsterv1.OpensearchTenantPending
 			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchTenantDeferred
+			}
 			// Requeue is after 30 seconds for normal reconciliation after creation/update
 			if retErr == nil && retResult.RequeueAfter == 30*time.Second {
 				instance.Status.State = opsterv1.OpensearchTenantCreated
@@ -141,7 +144,18 @@ func (r *TenantReconciler) Reconcile() (retResult ctrl.Result, retErr error) {
 		return
 	}
 
-	r.osClient, retErr = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		retResult = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, retErr = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
 	if retErr != nil {
 		reason = "error creating opensearch client"
 		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
@@ -235,8 +249,11 @@ func (r *TenantReconciler) Delete() error {
 		// If the opensearch cluster doesn't exist, we don't need to delete anything
 		return nil
 	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
 
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport)
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
 	if err != nil {
 		return err
 	}
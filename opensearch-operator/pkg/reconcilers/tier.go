@@ -0,0 +1,68 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+const opensearchMissingTier = "OpensearchComponentTemplateMissingTier"
+
+// checkTier adds the allocation settings of the tier requested by the spec to the translated template. Whether a node
+// of the cluster belongs to that tier is only checked before the template is written, by checkTierNodes.
+func (r *ComponentTemplateReconciler) checkTier(spec opsterv1.OpensearchComponentTemplateSpec, template *requests.ComponentTemplate) (string, error) {
+	if spec.Tier == "" {
+		return "", nil
+	}
+	if serverlessEndpoint(r.cluster) {
+		reason := "tiers cannot be used with serverless endpoints, which do not allocate shards to nodes"
+		r.recorder.Event(r.instance, "Warning", opensearchMissingTier, reason)
+		return reason, errors.New(reason)
+	}
+
+	settings, err := helpers.SetAllocationTier(template.Template.Settings, tierAttribute(spec), string(spec.Tier))
+	if err != nil {
+		reason := fmt.Sprintf("failed to add the allocation settings of the %s tier: %s", spec.Tier, err)
+		r.recorder.Event(r.instance, "Warning", opensearchMissingTier, reason)
+		return reason, err
+	}
+	template.Template.Settings = settings
+	return "", nil
+}
+
+// checkTierNodes fails the reconcile if no node of the cluster belongs to the tier requested by the spec, as indices
+// created from the template could not be allocated
+func (r *ComponentTemplateReconciler) checkTierNodes(spec opsterv1.OpensearchComponentTemplateSpec) (string, error) {
+	if spec.Tier == "" {
+		return "", nil
+	}
+	attribute := tierAttribute(spec)
+	nodes, err := services.NodesWithAttribute(r.ctx, r.osClient, attribute, string(spec.Tier))
+	if errors.Is(err, services.ErrForbidden) {
+		return r.forbidden(nodesInfoPrivilege), err
+	}
+	if err != nil {
+		reason := "failed to get node attributes from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return reason, err
+	}
+	if len(nodes) == 0 {
+		reason := fmt.Sprintf("no node of the cluster has the node attribute %s set to %s", attribute, spec.Tier)
+		r.recorder.Event(r.instance, "Warning", opensearchMissingTier, reason)
+		return reason, errors.New(reason)
+	}
+	return "", nil
+}
+
+// tierAttribute returns the node attribute identifying the tier of the spec
+func tierAttribute(spec opsterv1.OpensearchComponentTemplateSpec) string {
+	if spec.TierAttribute == "" {
+		return helpers.DefaultTierAttribute
+	}
+	return spec.TierAttribute
+}
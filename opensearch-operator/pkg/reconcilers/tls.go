@@ -366,7 +366,8 @@ func (r *TLSReconciler) handleTransportGeneratePerNode() error {
 
 	// Generate node cert and put it into secret
 	for _, nodePool := range r.instance.Spec.NodePools {
-		for i := 0; i < int(nodePool.Replicas); i++ {
+		// Autoscaled pools get certificates for all of their possible nodes
+		for i := 0; i < int(helpers.MaxReplicas(&nodePool)); i++ {
 			podName := fmt.Sprintf("%s-%s-%d", clusterName, nodePool.Component, i)
 			certName := fmt.Sprintf("%s.crt", podName)
 			keyName := fmt.Sprintf("%s.key", podName)
@@ -0,0 +1,192 @@
+package reconcilers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	tombstoneConfigMapName     = "opensearch-tombstones"
+	defaultTombstoneMaxEntries = 20
+)
+
+// redactedKeySuffixes are the (lowercase) key suffixes whose values are replaced before a body is stored in a tombstone
+var redactedKeySuffixes = []string{"password", "secret", "token", "credentials", "_key"}
+
+// TombstoneConfig controls if and how the last applied body of deleted resources is kept
+type TombstoneConfig struct {
+	Enabled bool
+	// Maximum number of tombstones kept per namespace, defaults to 20
+	MaxEntries int
+	// Tombstones older than this are dropped, zero keeps tombstones regardless of their age
+	MaxAge time.Duration
+}
+
+// Tombstone records the last applied state of a resource that was deleted from OpenSearch
+type Tombstone struct {
+	Kind      string          `json:"kind"`
+	Name      string          `json:"name"`
+	UID       string          `json:"uid"`
+	Target    string          `json:"target"`
+	DeletedAt metav1.Time     `json:"deletedAt"`
+	Hash      string          `json:"hash"`
+	Body      json.RawMessage `json:"body"`
+}
+
+func WithTombstones(config TombstoneConfig) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.tombstones = config
+	}
+}
+
+// recordTombstone stores the redacted body that was applied for object in the tombstone ConfigMap of its namespace.
+// The ConfigMap has no owner reference so tombstones outlive the deleted object.
+func recordTombstone(
+	k8sClient k8s.K8sClient,
+	config TombstoneConfig,
+	redaction *helpers.Redaction,
+	object client.Object,
+	kind string,
+	target string,
+	body interface{},
+) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	raw, err := redactJSON(body, redaction)
+	if err != nil {
+		return err
+	}
+	hash, err := util.GetSha1Sum(raw)
+	if err != nil {
+		return err
+	}
+
+	cm, err := k8sClient.GetConfigMap(tombstoneConfigMapName, object.GetNamespace())
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		cm = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tombstoneConfigMapName,
+				Namespace: object.GetNamespace(),
+			},
+		}
+	}
+
+	now := time.Now()
+	tombstone, err := json.Marshal(Tombstone{
+		Kind:      kind,
+		Name:      object.GetName(),
+		UID:       string(object.GetUID()),
+		Target:    target,
+		DeletedAt: metav1.NewTime(now),
+		Hash:      hash,
+		Body:      raw,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[fmt.Sprintf("%s-%s-%d", strings.ToLower(kind), object.GetName(), now.UnixNano())] = string(tombstone)
+	trimTombstones(cm.Data, config, now)
+
+	_, err = k8sClient.ReconcileResource(&cm, reconciler.StatePresent)
+	return err
+}
+
+// trimTombstones drops tombstones that are older than the configured maximum age
+// and keeps at most the configured number of the newest tombstones.
+// Entries that cannot be parsed are treated as expired.
+func trimTombstones(data map[string]string, config TombstoneConfig, now time.Time) {
+	type entry struct {
+		key       string
+		deletedAt time.Time
+	}
+	var entries []entry
+	for key, value := range data {
+		tombstone := Tombstone{}
+		if err := json.Unmarshal([]byte(value), &tombstone); err != nil {
+			delete(data, key)
+			continue
+		}
+		if config.MaxAge > 0 && tombstone.DeletedAt.Time.Before(now.Add(-config.MaxAge)) {
+			delete(data, key)
+			continue
+		}
+		entries = append(entries, entry{key: key, deletedAt: tombstone.DeletedAt.Time})
+	}
+
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultTombstoneMaxEntries
+	}
+	if len(entries) <= maxEntries {
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].deletedAt.Before(entries[j].deletedAt)
+	})
+	for _, e := range entries[:len(entries)-maxEntries] {
+		delete(data, e.key)
+	}
+}
+
+// redactJSON marshals body with the values of all keys that look like credentials or whose paths the redaction
+// redacts replaced
+func redactJSON(body interface{}, redaction *helpers.Redaction) ([]byte, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var parsed interface{}
+	if err := helpers.UnmarshalPreservingNumbers(raw, &parsed); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(redaction.RedactValue("", parsed)))
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if isRedactedKey(key) {
+				v[key] = helpers.RedactedValue
+			} else {
+				v[key] = redactValue(nested)
+			}
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactValue(nested)
+		}
+	}
+	return value
+}
+
+func isRedactedKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, suffix := range redactedKeySuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
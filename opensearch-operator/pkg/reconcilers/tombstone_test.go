@@ -0,0 +1,247 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("tombstones", func() {
+	var (
+		mockClient *k8s.MockK8sClient
+		instance   *opsterv1.OpensearchComponentTemplate
+		config     TombstoneConfig
+		written    []*corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		instance = &opsterv1.OpensearchComponentTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-componenttemplate",
+				Namespace: "test-tombstone",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchComponentTemplateSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				Name: "my-template",
+			},
+		}
+		config = TombstoneConfig{Enabled: true, MaxEntries: 2}
+		written = nil
+	})
+
+	expectWrite := func() {
+		mockClient.EXPECT().ReconcileResource(mock.Anything, reconciler.StatePresent).
+			RunAndReturn(func(obj runtime.Object, _ reconciler.DesiredState) (*ctrl.Result, error) {
+				written = append(written, obj.(*corev1.ConfigMap))
+				return &ctrl.Result{}, nil
+			})
+	}
+
+	parseTombstones := func(cm *corev1.ConfigMap) []Tombstone {
+		var tombstones []Tombstone
+		for _, value := range cm.Data {
+			tombstone := Tombstone{}
+			Expect(json.Unmarshal([]byte(value), &tombstone)).To(Succeed())
+			tombstones = append(tombstones, tombstone)
+		}
+		return tombstones
+	}
+
+	When("tombstones are disabled", func() {
+		It("should not write anything", func() {
+			err := recordTombstone(mockClient, TombstoneConfig{}, nil, instance, "OpensearchComponentTemplate", "my-template", map[string]string{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("no tombstone ConfigMap exists yet", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().GetConfigMap(tombstoneConfigMapName, "test-tombstone").Return(corev1.ConfigMap{}, NotFoundError())
+			expectWrite()
+		})
+
+		It("should create it with the redacted body and its hash", func() {
+			body := map[string]interface{}{
+				"template": map[string]interface{}{
+					"settings": map[string]interface{}{"index": map[string]interface{}{"number_of_shards": "1"}},
+				},
+				"_meta": map[string]interface{}{"owner": "team-a", "api_token": "abc", "nested": []interface{}{map[string]interface{}{"password": "secret"}}},
+			}
+			err := recordTombstone(mockClient, config, nil, instance, "OpensearchComponentTemplate", "my-template", body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(HaveLen(1))
+			cm := written[0]
+			Expect(cm.Name).To(Equal(tombstoneConfigMapName))
+			Expect(cm.Namespace).To(Equal("test-tombstone"))
+			Expect(cm.OwnerReferences).To(BeEmpty())
+
+			tombstones := parseTombstones(cm)
+			Expect(tombstones).To(HaveLen(1))
+			tombstone := tombstones[0]
+			Expect(tombstone.Kind).To(Equal("OpensearchComponentTemplate"))
+			Expect(tombstone.Name).To(Equal("test-componenttemplate"))
+			Expect(tombstone.UID).To(Equal("testuid"))
+			Expect(tombstone.Target).To(Equal("my-template"))
+			Expect(string(tombstone.Body)).To(ContainSubstring(`"number_of_shards":"1"`))
+			Expect(string(tombstone.Body)).To(ContainSubstring(`"owner":"team-a"`))
+			Expect(string(tombstone.Body)).To(ContainSubstring(`"api_token":"REDACTED"`))
+			Expect(string(tombstone.Body)).To(ContainSubstring(`"password":"REDACTED"`))
+			Expect(string(tombstone.Body)).NotTo(ContainSubstring("abc"))
+			Expect(tombstone.Hash).To(HaveLen(40))
+		})
+
+		It("should redact the configured paths", func() {
+			body := map[string]interface{}{
+				"template": map[string]interface{}{
+					"settings": map[string]interface{}{"index.routing.allocation.require.host": "node-1.internal", "index.number_of_shards": "1"},
+				},
+			}
+			redaction := helpers.NewRedaction([]string{"template.settings.index.routing.allocation.require.host"})
+			err := recordTombstone(mockClient, config, redaction, instance, "OpensearchComponentTemplate", "my-template", body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(HaveLen(1))
+			tombstones := parseTombstones(written[0])
+			Expect(tombstones).To(HaveLen(1))
+			Expect(tombstones[0].Body).To(MatchJSON(`{"template":{"settings":{"index.routing.allocation.require.host":"REDACTED","index.number_of_shards":"1"}}}`))
+		})
+	})
+
+	When("the tombstone ConfigMap is full", func() {
+		BeforeEach(func() {
+			config.MaxAge = time.Hour
+			now := time.Now()
+			tombstoneData := func(name string, deletedAt time.Time) string {
+				raw, err := json.Marshal(Tombstone{Name: name, DeletedAt: metav1.NewTime(deletedAt), Body: json.RawMessage("{}")})
+				Expect(err).NotTo(HaveOccurred())
+				return string(raw)
+			}
+			existing := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tombstoneConfigMapName,
+					Namespace: "test-tombstone",
+				},
+				Data: map[string]string{
+					"expired": tombstoneData("expired", now.Add(-2*time.Hour)),
+					"first":   tombstoneData("first", now.Add(-3*time.Minute)),
+					"second":  tombstoneData("second", now.Add(-2*time.Minute)),
+					"invalid": "not json",
+				},
+			}
+			mockClient.EXPECT().GetConfigMap(mock.Anything, mock.Anything).Return(existing, nil)
+			expectWrite()
+		})
+
+		It("should drop expired, invalid and the oldest tombstones", func() {
+			err := recordTombstone(mockClient, config, nil, instance, "OpensearchComponentTemplate", "my-template", map[string]string{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(HaveLen(1))
+			var names []string
+			for _, tombstone := range parseTombstones(written[0]) {
+				names = append(names, tombstone.Name)
+			}
+			Expect(names).To(ConsistOf("second", "test-componenttemplate"))
+		})
+	})
+
+	When("a component template is deleted", func() {
+		var (
+			transport *httpmock.MockTransport
+			cluster   *opsterv1.OpenSearchCluster
+		)
+
+		BeforeEach(func() {
+			instance.Status.ExistingComponentTemplate = pointer.Bool(false)
+			instance.Spec.Template = opsterv1.OpensearchIndexSpec{Settings: &apiextensionsv1.JSON{}}
+			mockClient.EXPECT().ListOpensearchIndexTemplates(mock.Anything).Return(opsterv1.OpensearchIndexTemplateList{}, nil)
+			cluster = &opsterv1.OpenSearchCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-tombstone",
+				},
+				Spec: opsterv1.ClusterSpec{
+					General: opsterv1.GeneralConfig{
+						ServiceName: "test-cluster",
+						HttpPort:    9200,
+					},
+				},
+			}
+			clusterUrl := fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+			componentTemplateUrl := fmt.Sprintf("%s_component_template/my-template", clusterUrl)
+
+			transport = httpmock.NewMockTransport()
+			transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				componentTemplateUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodGet,
+				componentTemplateUrl,
+				httpmock.NewStringResponder(200, `{"component_templates":[{"name":"my-template","component_template":{"template":{"settings":{"index":{"number_of_replicas":"2"}}}}}]}`).Once(failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodDelete,
+				componentTemplateUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			mockClient.EXPECT().GetConfigMap(tombstoneConfigMapName, "test-tombstone").Return(corev1.ConfigMap{}, NotFoundError())
+			expectWrite()
+		})
+
+		It("should record the applied template before deleting it", func() {
+			options := ReconcilerOptions{}
+			options.apply(WithOSClientTransport(transport), WithUpdateStatus(false), WithTombstones(config))
+			reconciler := &ComponentTemplateReconciler{
+				client:            mockClient,
+				ctx:               context.Background(),
+				ReconcilerOptions: options,
+				recorder:          record.NewFakeRecorder(1),
+				instance:          instance,
+				logger:            log.FromContext(context.Background()),
+			}
+			Expect(reconciler.Delete()).To(Succeed())
+			Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+			Expect(written).To(HaveLen(1))
+			tombstones := parseTombstones(written[0])
+			Expect(tombstones).To(HaveLen(1))
+			Expect(tombstones[0].Target).To(Equal("my-template"))
+			Expect(string(tombstones[0].Body)).To(ContainSubstring(`"number_of_replicas":"2"`))
+		})
+	})
+})
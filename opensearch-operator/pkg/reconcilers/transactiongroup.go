@@ -0,0 +1,305 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchTransactionGroupApplied    = "OpensearchTransactionGroupApplied"
+	opensearchTransactionGroupFailed     = "OpensearchTransactionGroupFailed"
+	opensearchTransactionGroupRolledBack = "OpensearchTransactionGroupRolledBack"
+)
+
+// componentTemplateTransaction is a pending change of one member of a transaction group
+type componentTemplateTransaction struct {
+	member                   *opsterv1.OpensearchComponentTemplate
+	name                     string
+	desired                  requests.ComponentTemplate
+	previous                 *requests.ComponentTemplate
+	failOnDeprecatedSettings bool
+}
+
+// reconcileTransactionGroup applies the pending changes of all component templates sharing the transaction group
+// of the instance. OpenSearch has no transactions, so this is best-effort: every change is simulated before
+// anything is written, and already applied changes are reverted if a later one fails. Every written member is
+// confirmed and recorded as applied like a component template outside of a group, the steps following an apply run
+// for the instance only, the other members run them when they are reconciled themselves.
+func (r *ComponentTemplateReconciler) reconcileTransactionGroup() (ctrl.Result, string, error) {
+	group := r.instance.Spec.TransactionGroup
+
+	list, err := r.client.ListOpensearchComponentTemplates(client.InNamespace(r.instance.Namespace))
+	if err != nil {
+		reason := "failed to list the members of the transaction group"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return ctrl.Result{}, reason, err
+	}
+
+	var members []*opsterv1.OpensearchComponentTemplate
+	for i := range list.Items {
+		item := &list.Items[i]
+		if item.Spec.TransactionGroup != group || componentTemplateClusterName(item) != componentTemplateClusterName(r.instance) {
+			continue
+		}
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if item.UID == r.instance.UID {
+			members = append(members, r.instance)
+			continue
+		}
+		if item.Status.ExistingComponentTemplate == nil {
+			reason := fmt.Sprintf("waiting for all members of transaction group %s to be initialized", group)
+			r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+			return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, reason, nil
+		}
+		// Pre-existing component templates are never modified by the operator
+		if *item.Status.ExistingComponentTemplate {
+			continue
+		}
+		if reason := unsupportedInTransactionGroup(item); reason != "" {
+			reason = fmt.Sprintf("component template %s of transaction group %s cannot be applied: %s", item.Name, group, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchTransactionGroupFailed, reason)
+			return ctrl.Result{}, reason, errors.New(reason)
+		}
+		// A member being rolled back applies its previous version itself
+		if rollbackRequested(item, item.Generation) {
+			continue
+		}
+		members = append(members, item)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+
+	var pending []componentTemplateTransaction
+	var inSync *componentTemplateTransaction
+	for _, member := range members {
+		name := member.Name
+		if member.Spec.Name != "" {
+			name = member.Spec.Name
+		}
+		desired := translateComponentTemplate(member)
+		if reason, err := r.checkComponentTemplate(member, &desired); err != nil {
+			return ctrl.Result{}, reason, err
+		}
+		// The previous state of every member is needed to revert the group, members cannot fall back to _cat
+		previous, err := services.GetComponentTemplate(r.ctx, r.osClient, name)
+		if err != nil {
+			reason := "failed to get component template status from OpenSearch API"
+			if errors.Is(err, services.ErrForbidden) {
+				reason = r.forbidden(componentTemplateGetPrivilege)
+			} else {
+				r.logger.Error(err, reason)
+				r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			}
+			return ctrl.Result{}, reason, err
+		}
+		change := componentTemplateTransaction{
+			member:                   member,
+			name:                     name,
+			desired:                  desired,
+			previous:                 previous,
+			failOnDeprecatedSettings: member.Spec.FailOnDeprecatedSettings,
+		}
+		if previous != nil && helpers.ComponentTemplatesEqual(desired, *previous) {
+			if member == r.instance {
+				inSync = &change
+			}
+			continue
+		}
+		if previous != nil {
+			keep, reason, err := r.forMember(member).handleExternalEdit(*previous)
+			if err != nil {
+				return ctrl.Result{}, reason, err
+			}
+			// The group is written at once, so it waits for the grace period of an edit of any of its members
+			if keep && reason != "" {
+				return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, reason, nil
+			}
+			if keep {
+				continue
+			}
+		}
+		if reason, err := r.checkBeforeWrite(member, desired); err != nil {
+			return ctrl.Result{}, reason, err
+		}
+		r.checkFieldCount(desired)
+		pending = append(pending, change)
+	}
+
+	if len(pending) == 0 {
+		r.logger.V(1).Info(fmt.Sprintf("transaction group %s is in sync", group))
+		if inSync != nil {
+			return r.completeInSync(inSync.name, inSync.desired, *inSync.previous)
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+	}
+
+	for _, change := range pending {
+		if err := services.SimulateComponentTemplate(r.ctx, r.osClient, change.name, change.desired); err != nil {
+			reason := fmt.Sprintf("transaction group %s not applied, validation of component template %s failed", group, change.name)
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchTransactionGroupFailed, reason)
+			return ctrl.Result{}, reason, err
+		}
+	}
+
+	waiting, err := r.deferForSyncWave()
+	if err != nil {
+		reason := fmt.Sprintf("failed to order the component template by its sync wave: %s", err)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if waiting != "" {
+		r.recorder.Event(r.instance, "Normal", opensearchPending, waiting)
+		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, waiting, nil
+	}
+
+	if deferred, reason, err := r.deferForRequiredHealth(r.instance.Spec.RequiredClusterHealth); err != nil {
+		return ctrl.Result{}, reason, err
+	} else if deferred != "" {
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, deferred)
+		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, deferred, nil
+	}
+	// The group is written at once, so it waits for the recent snapshot any of its changed members requires
+	for _, change := range pending {
+		deferred, reason, err := r.deferForSnapshot(change.member.Spec.RequireRecentSnapshot)
+		if err != nil {
+			return ctrl.Result{}, reason, err
+		}
+		if deferred != "" {
+			r.recorder.Event(r.instance, "Normal", opensearchDeferred, deferred)
+			return ctrl.Result{Requeue: true, RequeueAfter: time.Minute}, deferred, nil
+		}
+	}
+	for _, change := range pending {
+		if reason, err := r.validateWithCanary(change.member, change.name, change.desired); err != nil {
+			return ctrl.Result{}, reason, err
+		}
+	}
+
+	release, err := r.applyQueue.Acquire(r.ctx, r.cluster.UID, r.instance.Spec.ApplyPriority)
+	if err != nil {
+		return ctrl.Result{}, "failed to wait for other applies to the cluster", err
+	}
+	defer release()
+
+	traces := make([]*services.RequestTrace, len(pending))
+	for i := range pending {
+		change := &pending[i]
+		var writeCtx context.Context
+		writeCtx, traces[i] = r.traceMemberWrite(change.member)
+		var deprecations []string
+		change.previous, deprecations, err = r.forMember(change.member).writeComponentTemplate(writeCtx, change.name, change.desired, change.previous)
+		if err == nil {
+			var reason string
+			if reason, err = r.reportDeprecations(change.name, deprecations, change.failOnDeprecatedSettings); err == nil {
+				continue
+			}
+			reason = fmt.Sprintf("transaction group %s not applied, %s", group, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchTransactionGroupFailed, reason)
+			r.rollbackTransactionGroup(pending[:i+1])
+			return ctrl.Result{}, reason, err
+		}
+		reason := fmt.Sprintf("transaction group %s not applied, applying component template %s failed", group, change.name)
+		if errors.Is(err, services.ErrConcurrentModification) {
+			reason = fmt.Sprintf("transaction group %s not applied, component template %s is modified concurrently by another writer", group, change.name)
+		}
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchTransactionGroupFailed, reason)
+		r.rollbackTransactionGroup(pending[:i])
+		return ctrl.Result{}, reason, err
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchTransactionGroupApplied, fmt.Sprintf("transaction group %s applied %d component templates", group, len(pending)))
+
+	var written *componentTemplateTransaction
+	var result ctrl.Result
+	var reason string
+	for i := range pending {
+		change := &pending[i]
+		if err := r.recordMemberRequestTrace(change.member, traces[i]); err != nil {
+			reason := fmt.Sprintf("failed to update status: %s", err)
+			r.recorder.Event(r.instance, "Warning", statusError, reason)
+			return ctrl.Result{}, reason, err
+		}
+		// A member that is not confirmed on all node pools yet is confirmed again once it is in sync
+		memberResult, memberReason, err := r.forMember(change.member).completeWrite(change.name, change.desired, change.previous, true)
+		if err != nil {
+			return memberResult, memberReason, err
+		}
+		if change.member == r.instance {
+			written, result, reason = change, memberResult, memberReason
+		}
+	}
+	switch {
+	case reason != "":
+		return result, reason, nil
+	case written != nil:
+		return r.afterApply(written.name, written.desired)
+	case inSync != nil:
+		return r.completeInSync(inSync.name, inSync.desired, *inSync.previous)
+	}
+	return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+}
+
+// forMember returns a reconciler for a member of the transaction group of the instance, which records the status of
+// the member and emits its events for it
+func (r *ComponentTemplateReconciler) forMember(member *opsterv1.OpensearchComponentTemplate) *ComponentTemplateReconciler {
+	if member == r.instance {
+		return r
+	}
+	memberReconciler := *r
+	memberReconciler.instance = member
+	memberReconciler.generation = member.Generation
+	return &memberReconciler
+}
+
+// unsupportedInTransactionGroup returns why the component template cannot be a member of a transaction group, which
+// writes all of its members at once and needs to be able to revert them
+func unsupportedInTransactionGroup(member *opsterv1.OpensearchComponentTemplate) string {
+	if member.Spec.ApplyMode == opsterv1.TemplateApplyModeCreateOnly {
+		return "the CreateOnly apply mode cannot be used for members of a transaction group"
+	}
+	if member.Spec.RequireApproval {
+		return "approvals cannot be required for members of a transaction group"
+	}
+	return ""
+}
+
+// rollbackTransactionGroup restores the previous state of already applied members of a transaction group
+func (r *ComponentTemplateReconciler) rollbackTransactionGroup(applied []componentTemplateTransaction) {
+	var failed []string
+	for i := len(applied) - 1; i >= 0; i-- {
+		change := applied[i]
+		var err error
+		if change.previous == nil {
+			err = services.DeleteComponentTemplate(r.ctx, r.osClient, change.name)
+		} else {
+			_, err = services.CreateOrUpdateComponentTemplate(r.ctx, r.osClient, change.name, *change.previous)
+		}
+		if err != nil {
+			r.logger.Error(err, fmt.Sprintf("failed to roll back component template %s", change.name))
+			failed = append(failed, change.name)
+		}
+	}
+
+	if len(failed) > 0 {
+		r.recorder.Event(r.instance, "Warning", opensearchTransactionGroupFailed, fmt.Sprintf("rollback of transaction group %s failed for component templates %v", r.instance.Spec.TransactionGroup, failed))
+		return
+	}
+	if len(applied) > 0 {
+		r.recorder.Event(r.instance, "Normal", opensearchTransactionGroupRolledBack, fmt.Sprintf("rolled back %d component templates of transaction group %s", len(applied), r.instance.Spec.TransactionGroup))
+	}
+}
@@ -0,0 +1,388 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/responses"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/util"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	opensearchTransformJobExists          = "transform job already exists in OpenSearch; not modifying"
+	opensearchTransformJobIDMismatch      = "OpensearchTransformJobIdMismatch"
+	opensearchInvalidTransformJob         = "OpensearchInvalidTransformJob"
+	opensearchTransformJobImmutableChange = "OpensearchTransformJobImmutableChange"
+)
+
+type TransformJobReconciler struct {
+	client k8s.K8sClient
+	ReconcilerOptions
+	ctx      context.Context
+	osClient *services.OsClusterClient
+	recorder record.EventRecorder
+	instance *opsterv1.OpensearchTransformJob
+	cluster  *opsterv1.OpenSearchCluster
+	logger   logr.Logger
+}
+
+func NewTransformJobReconciler(
+	ctx context.Context,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *opsterv1.OpensearchTransformJob,
+	opts ...ReconcilerOption,
+) *TransformJobReconciler {
+	options := ReconcilerOptions{}
+	options.apply(opts...)
+	return &TransformJobReconciler{
+		client:            k8s.NewK8sClient(client, ctx, reconciler.WithLog(log.FromContext(ctx).WithValues("reconciler", "transformjob"))),
+		ReconcilerOptions: options,
+		ctx:               ctx,
+		recorder:          recorder,
+		instance:          instance,
+		logger:            log.FromContext(ctx).WithValues("reconciler", "transformjob"),
+	}
+}
+
+func (r *TransformJobReconciler) Reconcile() (result ctrl.Result, err error) {
+	var reason string
+	var jobID string
+	var lastRun *opsterv1.TransformRunStatus
+
+	defer func() {
+		if !pointer.BoolDeref(r.updateStatus, true) {
+			return
+		}
+		// When the reconciler is done, figure out what the state of the resource
+		// is and set it in the state field accordingly.
+		err := r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+			instance := object.(*opsterv1.OpensearchTransformJob)
+			instance.Status.Reason = reason
+			if err != nil {
+				instance.Status.State = opsterv1.OpensearchTransformJobError
+			}
+			if result.Requeue && result.RequeueAfter == 10*time.Second {
+				instance.Status.State = opsterv1.OpensearchTransformJobPending
+			}
+			if reason == opensearchClusterFrozen {
+				instance.Status.State = opsterv1.OpensearchTransformJobDeferred
+			}
+			if err == nil && result.RequeueAfter == 30*time.Second {
+				instance.Status.State = opsterv1.OpensearchTransformJobCreated
+				instance.Status.JobID = jobID
+				if lastRun != nil {
+					instance.Status.LastRun = lastRun
+				}
+			}
+			if reason == opensearchTransformJobExists {
+				instance.Status.State = opsterv1.OpensearchTransformJobIgnored
+			}
+		})
+
+		if err != nil {
+			r.logger.Error(err, "failed to update status")
+		}
+	}()
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		reason = "error fetching opensearch cluster"
+		r.logger.Error(err, "failed to fetch opensearch cluster")
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if r.cluster == nil {
+		r.logger.Info("opensearch cluster does not exist, requeueing")
+		reason = "waiting for opensearch cluster to exist"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	// Check cluster ref has not changed
+	if r.instance.Status.ManagedCluster != nil {
+		if *r.instance.Status.ManagedCluster != r.cluster.UID {
+			reason = "cannot change the cluster a transform job refers to"
+			err = fmt.Errorf("%s", reason)
+			r.recorder.Event(r.instance, "Warning", opensearchRefMismatch, reason)
+			return
+		}
+	} else {
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchTransformJob)
+				instance.Status.ManagedCluster = &r.cluster.UID
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		}
+	}
+
+	// Check cluster is ready
+	if r.cluster.Status.Phase != opsterv1.PhaseRunning {
+		r.logger.Info("opensearch cluster is not running, requeueing")
+		reason = "waiting for opensearch cluster status to be running"
+		r.recorder.Event(r.instance, "Normal", opensearchPending, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	if clusterFrozen(r.cluster) {
+		r.logger.Info("opensearch cluster is frozen, requeueing")
+		reason = opensearchClusterFrozen
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, reason)
+		result = ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: 10 * time.Second,
+		}
+		return
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		reason = "error creating opensearch client"
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	jobID = r.instance.Name
+	if r.instance.Spec.JobID != "" {
+		jobID = r.instance.Spec.JobID
+	}
+
+	// Check transform job state to make sure we don't touch preexisting jobs
+	if r.instance.Status.ExistingJob == nil {
+		var exists bool
+		_, err = services.GetTransformJob(r.ctx, r.osClient, jobID)
+		if err == nil {
+			exists = true
+		} else if !errors.Is(err, services.ErrNotFound) {
+			reason = "failed to get transform job status from OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		if pointer.BoolDeref(r.updateStatus, true) {
+			err = r.client.UdateObjectStatus(r.instance, func(object client.Object) {
+				instance := object.(*opsterv1.OpensearchTransformJob)
+				instance.Status.ExistingJob = &exists
+			})
+			if err != nil {
+				reason = fmt.Sprintf("failed to update status: %s", err)
+				r.recorder.Event(r.instance, "Warning", statusError, reason)
+				return
+			}
+		} else {
+			// Emit an event for unit testing assertion
+			r.recorder.Event(r.instance, "Normal", "UnitTest", fmt.Sprintf("exists is %t", exists))
+			err = nil
+			return
+		}
+	}
+
+	// If transform job is existing do nothing
+	if *r.instance.Status.ExistingJob {
+		reason = opensearchTransformJobExists
+		return
+	}
+
+	// the job id is immutable, so check the old id (r.instance.Status.JobID) against the new
+	if r.instance.Status.JobID != "" && jobID != r.instance.Status.JobID {
+		reason = "cannot change the transform job id"
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchTransformJobIDMismatch, reason)
+		return
+	}
+
+	// rewrite the CRD format to the gateway format
+	resource, err := helpers.TranslateTransformJobToRequest(r.instance.Spec)
+	if err != nil {
+		reason = err.Error()
+		r.recorder.Event(r.instance, "Warning", opensearchInvalidTransformJob, reason)
+		return
+	}
+
+	existing, err := services.GetTransformJob(r.ctx, r.osClient, jobID)
+	if errors.Is(err, services.ErrNotFound) {
+		r.logger.V(1).Info(fmt.Sprintf("transform job %s not found, creating", jobID))
+		if resource.Schedule.Interval != nil {
+			resource.Schedule.Interval.StartTime = pointer.Int64(time.Now().UnixMilli())
+		}
+		err = services.CreateTransformJob(r.ctx, r.osClient, jobID, requests.TransformJob{Transform: resource})
+		if err != nil {
+			reason = "failed to create transform job with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "transform job created in opensearch")
+		result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+		return
+	} else if err != nil {
+		reason = "failed to get transform job from OpenSearch API"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+		return
+	}
+
+	immutable, err := helpers.TransformImmutableChanges(resource, &existing.Transform)
+	if err != nil {
+		reason = "failed to compare the transform job with the one in OpenSearch"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+	if len(immutable) > 0 {
+		reason = fmt.Sprintf("cannot change %s of an existing transform job", strings.Join(immutable, ", "))
+		err = fmt.Errorf("%s", reason)
+		r.recorder.Event(r.instance, "Warning", opensearchTransformJobImmutableChange, reason)
+		return
+	}
+
+	update, enabled, err := helpers.TransformJobUpdate(resource, &existing.Transform)
+	if err != nil {
+		reason = "failed to compare the transform job with the one in OpenSearch"
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchError, reason)
+		return
+	}
+
+	if update != nil {
+		if update.Schedule.Interval != nil && update.Schedule.Interval.StartTime == nil {
+			update.Schedule.Interval.StartTime = pointer.Int64(time.Now().UnixMilli())
+		}
+		err = services.UpdateTransformJob(r.ctx, r.osClient, jobID, existing.SeqNo, existing.PrimaryTerm, requests.TransformJob{Transform: *update})
+		if err != nil {
+			reason = "failed to update transform job with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, "transform job updated in opensearch")
+	}
+
+	// Jobs are started and stopped through their own endpoints
+	if resource.Enabled != enabled {
+		if resource.Enabled {
+			err = services.StartTransformJob(r.ctx, r.osClient, jobID)
+		} else {
+			err = services.StopTransformJob(r.ctx, r.osClient, jobID)
+		}
+		if err != nil {
+			reason = "failed to start or stop transform job with OpenSearch API"
+			r.logger.Error(err, reason)
+			r.recorder.Event(r.instance, "Warning", opensearchAPIError, reason)
+			return
+		}
+		action := "stopped"
+		if resource.Enabled {
+			action = "started"
+		}
+		r.recorder.Event(r.instance, "Normal", opensearchAPIUpdated, fmt.Sprintf("transform job %s in opensearch", action))
+	}
+
+	if update == nil && resource.Enabled == enabled {
+		r.logger.V(1).Info(fmt.Sprintf("transform job %s is in sync", r.instance.Name))
+	}
+
+	// Report the last run of the job, failing to fetch it does not fail the reconciliation
+	metadata, explainErr := services.ExplainTransformJob(r.ctx, r.osClient, jobID)
+	if explainErr != nil {
+		r.logger.Error(explainErr, "failed to get the state of the transform job from OpenSearch API")
+	} else if metadata != nil {
+		lastRun = transformRunStatus(metadata)
+	}
+
+	result = ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	return
+}
+
+// transformRunStatus converts the state OpenSearch reports for a transform job to the status of the resource
+func transformRunStatus(metadata *responses.TransformMetadata) *opsterv1.TransformRunStatus {
+	status := &opsterv1.TransformRunStatus{
+		Status:             metadata.Status,
+		FailureReason:      pointer.StringDeref(metadata.FailureReason, ""),
+		PagesProcessed:     metadata.Stats.PagesProcessed,
+		DocumentsProcessed: metadata.Stats.DocumentsProcessed,
+		DocumentsIndexed:   metadata.Stats.DocumentsIndexed,
+	}
+	if metadata.LastUpdatedAt > 0 {
+		lastUpdatedAt := metav1.NewTime(time.UnixMilli(metadata.LastUpdatedAt))
+		status.LastUpdatedAt = &lastUpdatedAt
+	}
+	return status
+}
+
+func (r *TransformJobReconciler) Delete() error {
+	// If we have never successfully reconciled we can just exit
+	if r.instance.Status.ExistingJob == nil {
+		return nil
+	}
+
+	if *r.instance.Status.ExistingJob {
+		r.logger.Info("transform job was pre-existing; not deleting")
+		return nil
+	}
+
+	var err error
+
+	r.cluster, err = util.FetchOpensearchCluster(r.client, r.ctx, types.NamespacedName{
+		Name:      r.instance.Spec.OpensearchRef.Name,
+		Namespace: r.instance.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil || !r.cluster.DeletionTimestamp.IsZero() {
+		// If the opensearch cluster doesn't exist, we don't need to delete anything
+		return nil
+	}
+	if clusterFrozen(r.cluster) {
+		return errClusterFrozen
+	}
+
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.cluster, r.osClientTransport, util.ClientOptionForObject(r.instance))
+	if err != nil {
+		return err
+	}
+
+	jobID := r.instance.Name
+	if r.instance.Spec.JobID != "" {
+		jobID = r.instance.Spec.JobID
+	}
+
+	// The target index holds the transformed documents and is kept
+	return services.DeleteTransformJob(r.ctx, r.osClient, jobID)
+}
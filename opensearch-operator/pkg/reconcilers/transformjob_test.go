@@ -0,0 +1,619 @@
+package reconcilers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("transformjob reconciler", func() {
+	var (
+		transport  *httpmock.MockTransport
+		reconciler *TransformJobReconciler
+		instance   *opsterv1.OpensearchTransformJob
+		recorder   *record.FakeRecorder
+		mockClient *k8s.MockK8sClient
+
+		// Objects
+		cluster    *opsterv1.OpenSearchCluster
+		clusterUrl string
+		jobUrl     string
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		transport.RegisterNoResponder(httpmock.NewNotFoundResponder(failMessage))
+		instance = &opsterv1.OpensearchTransformJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-transformjob",
+				Namespace: "test-transformjob",
+				UID:       "testuid",
+			},
+			Spec: opsterv1.OpensearchTransformJobSpec{
+				OpensearchRef: corev1.LocalObjectReference{
+					Name: "test-cluster",
+				},
+				JobID:       "logs-hourly",
+				Schedule:    opsterv1.RollupSchedule{Interval: &opsterv1.RollupInterval{Period: 1, Unit: "Hours"}},
+				SourceIndex: "logs-*",
+				TargetIndex: "logs-by-service",
+				PageSize:    1000,
+				Groups: []opsterv1.TransformGroup{
+					{Terms: &opsterv1.TransformTerms{RollupTerms: opsterv1.RollupTerms{SourceField: "service"}}},
+					{DateHistogram: &opsterv1.TransformDateHistogram{
+						RollupDateHistogram: opsterv1.RollupDateHistogram{SourceField: "@timestamp", FixedInterval: "1h"},
+						TargetField:         "hour",
+					}},
+				},
+				Aggregations: &apiextensionsv1.JSON{Raw: []byte(`{"bytes":{"sum":{"field":"bytes"}}}`)},
+			},
+		}
+
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-transformjob",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				NodePools: []opsterv1.NodePool{
+					{
+						Component: "node",
+						Roles: []string{
+							"master",
+							"data",
+						},
+					},
+				},
+			},
+		}
+		clusterUrl = fmt.Sprintf("https://%s.%s.svc.cluster.local:9200/", cluster.Spec.General.ServiceName, cluster.Namespace)
+		jobUrl = fmt.Sprintf("%s_plugins/_transform/logs-hourly", clusterUrl)
+	})
+
+	JustBeforeEach(func() {
+		options := ReconcilerOptions{}
+		options.apply(WithOSClientTransport(transport), WithUpdateStatus(false))
+		reconciler = &TransformJobReconciler{
+			client:            mockClient,
+			ctx:               context.Background(),
+			ReconcilerOptions: options,
+			recorder:          recorder,
+			instance:          instance,
+			logger:            log.FromContext(context.Background()),
+		}
+	})
+
+	When("cluster doesn't exist", func() {
+		BeforeEach(func() {
+			instance.Spec.OpensearchRef.Name = "doesnotexist"
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should wait for the cluster to exist", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				result, err := reconciler.Reconcile()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Normal %s waiting for opensearch cluster to exist", opensearchPending)))
+		})
+	})
+
+	When("cluster doesn't match status", func() {
+		BeforeEach(func() {
+			uid := types.UID("someuid")
+			instance.Status.ManagedCluster = &uid
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+			recorder = record.NewFakeRecorder(1)
+		})
+
+		It("should error", func() {
+			go func() {
+				defer GinkgoRecover()
+				defer close(recorder.Events)
+				_, err := reconciler.Reconcile()
+				Expect(err).To(HaveOccurred())
+			}()
+			var events []string
+			for msg := range recorder.Events {
+				events = append(events, msg)
+			}
+			Expect(len(events)).To(Equal(1))
+			Expect(events[0]).To(Equal(fmt.Sprintf("Warning %s cannot change the cluster a transform job refers to", opensearchRefMismatch)))
+		})
+	})
+
+	Context("cluster is ready", func() {
+		extraContextCalls := 1
+		BeforeEach(func() {
+			cluster.Status.Phase = opsterv1.PhaseRunning
+			cluster.Status.ComponentsStatus = []opsterv1.ComponentStatus{}
+			mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+
+			transport.RegisterResponder(
+				http.MethodGet,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+			)
+			transport.RegisterResponder(
+				http.MethodHead,
+				clusterUrl,
+				httpmock.NewStringResponder(200, "OK").Once(failMessage),
+			)
+		})
+
+		When("existing status is nil", func() {
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(1)
+			})
+
+			When("the transform job exists", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, transformJobResponse(true, 1000)).Once(failMessage),
+					)
+				})
+
+				It("should record that the transform job exists", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is true"}))
+				})
+			})
+
+			When("the transform job does not exist", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(404, `{"error":"Transform not found"}`).Once(failMessage),
+					)
+				})
+
+				It("should record that the transform job does not exist", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{"Normal UnitTest exists is false"}))
+				})
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingJob = pointer.Bool(true)
+			})
+
+			It("should do nothing", func() {
+				_, err := reconciler.Reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+			})
+		})
+
+		When("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingJob = pointer.Bool(false)
+			})
+
+			When("transform job does not exist in opensearch", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(404, `{"error":"Transform not found"}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						jobUrl,
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(201, `{"_id":"logs-hourly"}`), nil
+						},
+					)
+				})
+
+				It("should create the transform job", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s transform job created in opensearch", opensearchAPIUpdated)}))
+					job := requests.TransformJob{}
+					Expect(json.Unmarshal([]byte(body), &job)).To(Succeed())
+					Expect(job.Transform.Enabled).To(BeTrue())
+					Expect(job.Transform.Schedule.Interval.StartTime).ToNot(BeNil())
+					Expect(job.Transform.TargetIndex).To(Equal("logs-by-service"))
+				})
+			})
+
+			When("transform job exists in opensearch and is the same", func() {
+				BeforeEach(func() {
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, transformJobResponse(true, 1000)).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl+"/_explain",
+						httpmock.NewStringResponder(200, `{"logs-hourly":{"metadata_id":"abc","transform_metadata":{
+							"transform_id": "logs-hourly",
+							"last_updated_at": 1700000360000,
+							"status": "failed",
+							"failure_reason": "target index mapping conflict",
+							"stats": {"pages_processed": 4, "documents_processed": 1200, "documents_indexed": 96}
+						}}}`).Once(failMessage),
+					)
+				})
+
+				It("should do nothing", func() {
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+				})
+
+				It("should report the last run of the job", func() {
+					mockClient.EXPECT().UdateObjectStatus(mock.Anything, mock.Anything).
+						RunAndReturn(func(obj client.Object, f func(client.Object)) error {
+							f(obj)
+							return nil
+						})
+					reconciler.updateStatus = pointer.Bool(true)
+					_, err := reconciler.Reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(instance.Status.State).To(Equal(opsterv1.OpensearchTransformJobCreated))
+					Expect(instance.Status.JobID).To(Equal("logs-hourly"))
+					Expect(instance.Status.LastRun).ToNot(BeNil())
+					Expect(instance.Status.LastRun.Status).To(Equal("failed"))
+					Expect(instance.Status.LastRun.FailureReason).To(Equal("target index mapping conflict"))
+					Expect(instance.Status.LastRun.LastUpdatedAt.UnixMilli()).To(Equal(int64(1700000360000)))
+					Expect(instance.Status.LastRun.PagesProcessed).To(Equal(int64(4)))
+					Expect(instance.Status.LastRun.DocumentsProcessed).To(Equal(int64(1200)))
+					Expect(instance.Status.LastRun.DocumentsIndexed).To(Equal(int64(96)))
+				})
+			})
+
+			When("transform job exists in opensearch and is not the same", func() {
+				var body string
+
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, transformJobResponse(true, 500)).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPut,
+						jobUrl+"?if_seq_no=3&if_primary_term=1",
+						func(req *http.Request) (*http.Response, error) {
+							raw, err := io.ReadAll(req.Body)
+							if err != nil {
+								return nil, err
+							}
+							body = string(raw)
+							return httpmock.NewStringResponse(200, `{"_id":"logs-hourly"}`), nil
+						},
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl+"/_explain",
+						httpmock.NewStringResponder(200, `{"logs-hourly":{"metadata_id":null,"transform_metadata":null}}`).Once(failMessage),
+					)
+				})
+
+				It("should update the transform job keeping its start time", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s transform job updated in opensearch", opensearchAPIUpdated)}))
+					Expect(body).To(MatchJSON(`{"transform": {
+						"enabled": true,
+						"schedule": {"interval": {"start_time": 1700000000000, "period": 1, "unit": "Hours"}},
+						"source_index": "logs-*",
+						"target_index": "logs-by-service",
+						"page_size": 1000,
+						"continuous": false,
+						"groups": [
+							{"terms": {"source_field": "service"}},
+							{"date_histogram": {"source_field": "@timestamp", "target_field": "hour", "fixed_interval": "1h", "timezone": "UTC"}}
+						],
+						"aggregations": {"bytes": {"sum": {"field": "bytes"}}}
+					}}`))
+				})
+			})
+
+			When("the transform job is disabled in the spec", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Enabled = pointer.Bool(false)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, transformJobResponse(true, 1000)).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						jobUrl+"/_stop",
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl+"/_explain",
+						httpmock.NewStringResponder(200, `{"logs-hourly":{"metadata_id":null,"transform_metadata":null}}`).Once(failMessage),
+					)
+				})
+
+				It("should stop the transform job", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s transform job stopped in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("the transform job is enabled again in the spec", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, transformJobResponse(false, 1000)).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodPost,
+						jobUrl+"/_start",
+						httpmock.NewStringResponder(200, `{"acknowledged":true}`).Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl+"/_explain",
+						httpmock.NewStringResponder(200, `{"logs-hourly":{"metadata_id":null,"transform_metadata":null}}`).Once(failMessage),
+					)
+				})
+
+				It("should start the transform job", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Normal %s transform job started in opensearch", opensearchAPIUpdated)}))
+				})
+			})
+
+			When("an immutable field has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.TargetIndex = "logs-by-service-v2"
+					transport.RegisterResponder(
+						http.MethodGet,
+						jobUrl,
+						httpmock.NewStringResponder(200, transformJobResponse(true, 1000)).Once(failMessage),
+					)
+				})
+
+				It("should fail without updating the transform job", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change targetIndex of an existing transform job", opensearchTransformJobImmutableChange)}))
+				})
+			})
+
+			When("a group sets two types", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Spec.Groups[1].Terms = &opsterv1.TransformTerms{RollupTerms: opsterv1.RollupTerms{SourceField: "host"}}
+				})
+
+				It("should fail without calling OpenSearch", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+						Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + extraContextCalls))
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s invalid transform job: group 1 has to set exactly one of dateHistogram, terms and histogram, got 2", opensearchInvalidTransformJob)}))
+				})
+			})
+
+			When("the id of the transform job has changed", func() {
+				BeforeEach(func() {
+					recorder = record.NewFakeRecorder(1)
+					instance.Status.JobID = "logs-daily"
+				})
+
+				It("should fail", func() {
+					go func() {
+						defer GinkgoRecover()
+						defer close(recorder.Events)
+						_, err := reconciler.Reconcile()
+						Expect(err).To(HaveOccurred())
+					}()
+					var events []string
+					for msg := range recorder.Events {
+						events = append(events, msg)
+					}
+					Expect(events).To(Equal([]string{fmt.Sprintf("Warning %s cannot change the transform job id", opensearchTransformJobIDMismatch)}))
+				})
+			})
+		})
+	})
+
+	Context("deletions", func() {
+		When("existing status is nil", func() {
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		When("existing status is true", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingJob = pointer.Bool(true)
+			})
+			It("should do nothing and exit", func() {
+				Expect(reconciler.Delete()).To(Succeed())
+			})
+		})
+
+		Context("existing status is false", func() {
+			BeforeEach(func() {
+				instance.Status.ExistingJob = pointer.Bool(false)
+			})
+
+			When("cluster does not exist", func() {
+				BeforeEach(func() {
+					instance.Spec.OpensearchRef.Name = "doesnotexist"
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(opsterv1.OpenSearchCluster{}, NotFoundError())
+				})
+				It("should do nothing and exit", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+				})
+			})
+
+			When("cluster exists", func() {
+				BeforeEach(func() {
+					mockClient.EXPECT().GetOpenSearchCluster(mock.Anything, mock.Anything).Return(*cluster, nil)
+					transport.RegisterResponder(
+						http.MethodGet,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Times(2, failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodHead,
+						clusterUrl,
+						httpmock.NewStringResponder(200, "OK").Once(failMessage),
+					)
+					transport.RegisterResponder(
+						http.MethodDelete,
+						jobUrl,
+						httpmock.NewStringResponder(200, `{"_id":"logs-hourly","result":"deleted"}`).Once(failMessage),
+					)
+				})
+
+				It("should delete the transform job", func() {
+					Expect(reconciler.Delete()).To(Succeed())
+					Expect(transport.GetTotalCallCount()).To(Equal(transport.NumResponders() + 1))
+				})
+			})
+		})
+	})
+})
+
+// transformJobResponse is the transform job of the tests as OpenSearch returns it, with the fields it adds
+func transformJobResponse(enabled bool, pageSize int) string {
+	return fmt.Sprintf(`{"_id":"logs-hourly","_seq_no":3,"_primary_term":1,"transform":{
+	"transform_id": "logs-hourly",
+	"enabled": %t,
+	"schedule": {"interval": {"start_time": 1700000000000, "period": 1, "unit": "Hours"}},
+	"source_index": "logs-*",
+	"target_index": "logs-by-service",
+	"data_selection_query": {"match_all": {"boost": 1.0}},
+	"page_size": %d,
+	"continuous": false,
+	"groups": [
+		{"terms": {"source_field": "service", "target_field": "service"}},
+		{"date_histogram": {"source_field": "@timestamp", "target_field": "hour", "fixed_interval": "1h", "timezone": "UTC"}}
+	],
+	"aggregations": {"bytes": {"sum": {"field": "bytes"}}}
+}}`, enabled, pageSize)
+}
@@ -83,7 +83,7 @@ func (r *UpgradeReconciler) Reconcile() (ctrl.Result, error) {
 
 	var err error
 
-	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.instance, nil)
+	r.osClient, err = util.CreateClientForCluster(r.client, r.ctx, r.instance, nil, util.ClientOptionForObject(r.instance))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -0,0 +1,75 @@
+package reconcilers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+// UpgradeHandling selects how an upgrade of the cluster is detected, changes of component templates in OpenSearch are
+// deferred while it runs. The other objects are still changed during an upgrade.
+type UpgradeHandling string
+
+const (
+	// UpgradeHandlingIgnore does not defer changes during an upgrade
+	UpgradeHandlingIgnore UpgradeHandling = "ignore"
+	// UpgradeHandlingStatus defers changes while the status of the cluster reports an upgrade by the operator
+	UpgradeHandlingStatus UpgradeHandling = "status"
+	// UpgradeHandlingVersionSkew defers changes while the status of the cluster reports an upgrade, or its nodes run
+	// different versions, which also covers rolling upgrades not done by the operator
+	UpgradeHandlingVersionSkew UpgradeHandling = "version-skew"
+)
+
+// opensearchClusterUpgrading is the reason set while changes are deferred because the cluster is being upgraded
+const opensearchClusterUpgrading = "deferring changes while the cluster is being upgraded"
+
+// errClusterUpgrading is returned by deletes while the cluster is being upgraded so they are retried
+var errClusterUpgrading = errors.New(opensearchClusterUpgrading)
+
+// ParseUpgradeHandling returns the upgrade handling named by value, empty is UpgradeHandlingIgnore
+func ParseUpgradeHandling(value string) (UpgradeHandling, error) {
+	switch UpgradeHandling(value) {
+	case "", UpgradeHandlingIgnore:
+		return UpgradeHandlingIgnore, nil
+	case UpgradeHandlingStatus:
+		return UpgradeHandlingStatus, nil
+	case UpgradeHandlingVersionSkew:
+		return UpgradeHandlingVersionSkew, nil
+	default:
+		return "", fmt.Errorf("invalid upgrade handling %q, must be %s, %s or %s", value, UpgradeHandlingIgnore, UpgradeHandlingStatus, UpgradeHandlingVersionSkew)
+	}
+}
+
+// WithUpgradeHandling sets how an upgrade of the cluster is detected to defer changes in OpenSearch while it runs
+func WithUpgradeHandling(handling UpgradeHandling) ReconcilerOption {
+	return func(o *ReconcilerOptions) {
+		o.upgradeHandling = handling
+	}
+}
+
+// clusterUpgrading returns true if the status of the cluster reports an upgrade and the upgrade handling defers
+// changes during one
+func clusterUpgrading(handling UpgradeHandling, cluster *opsterv1.OpenSearchCluster) bool {
+	if handling != UpgradeHandlingStatus && handling != UpgradeHandlingVersionSkew {
+		return false
+	}
+	return helpers.UpgradeInProgress(cluster.Status)
+}
+
+// nodeVersionSkew returns the reason to defer changes if the upgrade handling checks the versions of the nodes and
+// they run more than one, empty if they do not. Serverless endpoints are not checked as they have no nodes API.
+func nodeVersionSkew(ctx context.Context, handling UpgradeHandling, cluster *opsterv1.OpenSearchCluster, osClient *services.OsClusterClient) (string, error) {
+	if handling != UpgradeHandlingVersionSkew || serverlessEndpoint(cluster) {
+		return "", nil
+	}
+	versions, err := services.NodeVersions(ctx, osClient)
+	if err != nil || len(versions) < 2 {
+		return "", err
+	}
+	return fmt.Sprintf("%s, its nodes run versions %s", opensearchClusterUpgrading, strings.Join(versions, ", ")), nil
+}
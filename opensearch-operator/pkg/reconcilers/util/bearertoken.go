@@ -0,0 +1,133 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+)
+
+// oidcRequestTimeout bounds the time requesting a token from an OIDC provider may take
+const oidcRequestTimeout = 10 * time.Second
+
+// oidcTokenSources keeps the OIDC token source of every cluster, so its token is reused between reconciles. The
+// sources of a cluster are removed with ForgetOIDCTokenSources once it is deleted
+var oidcTokenSources = struct {
+	sync.Mutex
+	sources map[string]oidcTokenSourceEntry
+}{sources: map[string]oidcTokenSourceEntry{}}
+
+type oidcTokenSourceEntry struct {
+	fingerprint string
+	source      *services.OIDCTokenSource
+}
+
+// secretTokenSource reads the bearer token from a Secret for every request, so a rotated token is picked up
+type secretTokenSource struct {
+	k8sClient k8s.K8sClient
+	name      string
+	namespace string
+}
+
+func (s *secretTokenSource) Token(_ context.Context) (string, error) {
+	secret, err := s.k8sClient.GetSecret(s.name, s.namespace)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(secret.Data["token"]))
+	if token == "" {
+		return "", fmt.Errorf("token field missing in secret %s", s.name)
+	}
+	return token, nil
+}
+
+// Invalidate does nothing, the Secret is read again for the next request anyway
+func (s *secretTokenSource) Invalidate(string) {}
+
+// bearerTokenSource returns the source of the bearer token the operator authenticates to the cluster with in the
+// bearerToken auth mode, and a fingerprint that changes with its configuration. The token is requested right away,
+// so a token that cannot be had is reported before the cluster is contacted.
+func bearerTokenSource(k8sClient k8s.K8sClient, ctx context.Context, cluster *opsterv1.OpenSearchCluster, transport http.RoundTripper) (services.TokenSource, string, error) {
+	config := cluster.Spec.Security.Config.OperatorBearerToken
+	var source services.TokenSource
+	var fingerprint string
+	switch {
+	case config != nil && config.TokenSecret != nil && config.TokenSecret.Name != "":
+		source = &secretTokenSource{k8sClient: k8sClient, name: config.TokenSecret.Name, namespace: cluster.Namespace}
+		fingerprint = "secret:" + config.TokenSecret.Name
+	case config != nil && config.OIDC != nil:
+		var err error
+		if source, fingerprint, err = oidcTokenSource(k8sClient, cluster, config.OIDC, transport); err != nil {
+			return nil, "", err
+		}
+	default:
+		return nil, "", errors.New("operatorBearerToken must set tokenSecret or oidc for the bearerToken auth mode")
+	}
+	if _, err := source.Token(ctx); err != nil {
+		return nil, "", err
+	}
+	return source, fingerprint, nil
+}
+
+// oidcTokenSource returns the OIDC token source of the cluster, replacing it if the provider, the client or the proxy
+// changed. The tokens are requested with the transport and through the proxy of the cluster client.
+func oidcTokenSource(
+	k8sClient k8s.K8sClient,
+	cluster *opsterv1.OpenSearchCluster,
+	provider *opsterv1.OperatorOIDCProvider,
+	transport http.RoundTripper,
+) (*services.OIDCTokenSource, string, error) {
+	secret, err := k8sClient.GetSecret(provider.ClientSecret.Name, cluster.Namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	clientID, idExists := secret.Data["clientID"]
+	clientSecret, secretExists := secret.Data["clientSecret"]
+	if !idExists || !secretExists {
+		return nil, "", fmt.Errorf("clientID or clientSecret field missing in secret %s", provider.ClientSecret.Name)
+	}
+	proxy, proxyFingerprint, err := clusterProxy(cluster)
+	if err != nil {
+		return nil, "", err
+	}
+	hash := sha256.New()
+	for _, part := range []string{provider.TokenURL, string(clientID), string(clientSecret), strings.Join(provider.Scopes, " "), proxyFingerprint} {
+		fmt.Fprintf(hash, "%d:%s", len(part), part)
+	}
+	fingerprint := "oidc:" + hex.EncodeToString(hash.Sum(nil))
+
+	key := fmt.Sprintf("%s/%s/%s", cluster.Namespace, cluster.Name, cluster.UID)
+	oidcTokenSources.Lock()
+	defer oidcTokenSources.Unlock()
+	if entry, ok := oidcTokenSources.sources[key]; ok && entry.fingerprint == fingerprint {
+		return entry.source, fingerprint, nil
+	}
+	if transport != nil {
+		proxy = append(proxy, services.WithTransport(transport))
+	}
+	source := services.NewOIDCTokenSource(provider.TokenURL, string(clientID), string(clientSecret), provider.Scopes,
+		services.NewOIDCClient(oidcRequestTimeout, proxy...))
+	oidcTokenSources.sources[key] = oidcTokenSourceEntry{fingerprint: fingerprint, source: source}
+	return source, fingerprint, nil
+}
+
+// ForgetOIDCTokenSources removes the OIDC token sources of a deleted cluster, of every UID it had
+func ForgetOIDCTokenSources(namespace string, name string) {
+	prefix := fmt.Sprintf("%s/%s/", namespace, name)
+	oidcTokenSources.Lock()
+	defer oidcTokenSources.Unlock()
+	for key := range oidcTokenSources.sources {
+		if strings.HasPrefix(key, prefix) {
+			delete(oidcTokenSources.sources, key)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package util
+
+import (
+	"context"
+	cryptotls "crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Client for cluster with a bearer token", Ordered, func() {
+	var (
+		mockClient *k8s.MockK8sClient
+		server     *httptest.Server
+		provider   *httptest.Server
+		transport  *http.Transport
+		cluster    *opsterv1.OpenSearchCluster
+
+		mu sync.Mutex
+		// accepted are the tokens the fake cluster accepts
+		accepted map[string]bool
+		// authorized are the tokens of the requests the fake cluster accepted
+		authorized []string
+		rejected   int
+		// issued counts the tokens the fake OIDC provider issued
+		issued    int
+		expiresIn int
+		tokenForm []string
+	)
+
+	BeforeAll(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, _, ok := req.BasicAuth(); ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if !accepted[token] {
+				rejected++
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			authorized = append(authorized, token)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("{}"))
+		}))
+		// The client connects to the service of the cluster, which is the fake server here, and to the OIDC provider
+		transport = &http.Transport{
+			DialContext: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+				if addr == "test-cluster.test-namespace.svc.cluster.local:9200" {
+					addr = server.Listener.Addr().String()
+				}
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+			TLSClientConfig: &cryptotls.Config{InsecureSkipVerify: true},
+		}
+		provider = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			clientID, clientSecret, _ := req.BasicAuth()
+			if clientID != "operator" || clientSecret != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = req.ParseForm()
+			tokenForm = []string{req.PostForm.Get("grant_type"), req.PostForm.Get("scope")}
+			issued++
+			token := fmt.Sprintf("oidc-%d", issued)
+			accepted[token] = true
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": token,
+				"token_type":   "Bearer",
+				"expires_in":   expiresIn,
+			})
+		}))
+	})
+
+	AfterAll(func() {
+		server.Close()
+		provider.Close()
+	})
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		accepted = map[string]bool{}
+		authorized = nil
+		rejected = 0
+		issued = 0
+		expiresIn = 3600
+		oidcTokenSources.Lock()
+		oidcTokenSources.sources = map[string]oidcTokenSourceEntry{}
+		oidcTokenSources.Unlock()
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+				UID:       "clusteruid",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				Security: &opsterv1.Security{
+					Config: &opsterv1.SecurityConfig{
+						OperatorAuthMode: opsterv1.OperatorAuthModeBearerToken,
+					},
+				},
+			},
+		}
+	})
+
+	Context("the token is read from a secret", func() {
+		var token string
+
+		BeforeEach(func() {
+			cluster.Spec.Security.Config.OperatorBearerToken = &opsterv1.OperatorBearerToken{
+				TokenSecret: &v1.LocalObjectReference{Name: "operator-token"},
+			}
+			token = "static-token"
+			mockClient.EXPECT().GetSecret("operator-token", "test-namespace").RunAndReturn(func(string, string) (v1.Secret, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				return v1.Secret{Data: map[string][]byte{"token": []byte(token + "\n")}}, nil
+			})
+		})
+
+		It("should authenticate with the token instead of basic auth", func() {
+			accepted["static-token"] = true
+			_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authorized).ToNot(BeEmpty())
+			Expect(authorized).To(HaveEach("static-token"))
+		})
+
+		It("should send a request rejected with the old token again with the rotated token", func() {
+			accepted["static-token"] = true
+			client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+
+			mu.Lock()
+			accepted = map[string]bool{"rotated-token": true}
+			token = "rotated-token"
+			authorized = nil
+			mu.Unlock()
+			// The secret is read for every request, the token is only rotated after it was sent
+			Expect(services.Ping(context.Background(), client)).To(Succeed())
+			Expect(authorized).To(HaveEach("rotated-token"))
+		})
+
+		It("should report a secret without a token", func() {
+			token = " "
+			_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(errors.Is(err, services.ErrBearerToken)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("token field missing in secret operator-token"))
+			Expect(authorized).To(BeEmpty())
+		})
+	})
+
+	Context("the token is requested from an OIDC provider", func() {
+		var clientSecret string
+
+		BeforeEach(func() {
+			cluster.Spec.Security.Config.OperatorBearerToken = &opsterv1.OperatorBearerToken{
+				OIDC: &opsterv1.OperatorOIDCProvider{
+					TokenURL:     provider.URL + "/token",
+					ClientSecret: v1.LocalObjectReference{Name: "operator-oidc"},
+					Scopes:       []string{"openid", "opensearch"},
+				},
+			}
+			clientSecret = "secret"
+			mockClient.EXPECT().GetSecret("operator-oidc", "test-namespace").RunAndReturn(func(string, string) (v1.Secret, error) {
+				return v1.Secret{Data: map[string][]byte{
+					"clientID":     []byte("operator"),
+					"clientSecret": []byte(clientSecret),
+				}}, nil
+			})
+		})
+
+		It("should reuse the token until it expires", func() {
+			client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(services.Ping(context.Background(), client)).To(Succeed())
+			Expect(issued).To(Equal(1))
+			Expect(tokenForm).To(Equal([]string{"client_credentials", "openid opensearch"}))
+			Expect(authorized).To(HaveEach("oidc-1"))
+		})
+
+		It("should refresh the token before it expires", func() {
+			expiresIn = 1
+			client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() []string {
+				Expect(services.Ping(context.Background(), client)).To(Succeed())
+				mu.Lock()
+				defer mu.Unlock()
+				return authorized
+			}).Should(ContainElement("oidc-2"))
+			Expect(rejected).To(BeZero())
+		})
+
+		It("should request a new token when the cluster rejects it", func() {
+			client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+
+			mu.Lock()
+			delete(accepted, "oidc-1")
+			authorized = nil
+			mu.Unlock()
+			Expect(services.Ping(context.Background(), client)).To(Succeed())
+			Expect(rejected).To(Equal(1))
+			Expect(issued).To(Equal(2))
+			Expect(authorized).To(HaveEach("oidc-2"))
+		})
+
+		It("should forget the token of a deleted cluster", func() {
+			_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+			other := cluster.DeepCopy()
+			other.Name = "other-cluster"
+			other.UID = "otheruid"
+			_, err = CreateClientForCluster(mockClient, context.Background(), other, transport)
+			Expect(err).ToNot(HaveOccurred())
+
+			ForgetOIDCTokenSources("test-namespace", "test-cluster")
+			oidcTokenSources.Lock()
+			Expect(oidcTokenSources.sources).To(HaveLen(1))
+			Expect(oidcTokenSources.sources).To(HaveKey("test-namespace/other-cluster/otheruid"))
+			oidcTokenSources.Unlock()
+
+			// A cluster created again with the same name requests a new token
+			_, err = CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(issued).To(Equal(3))
+		})
+
+		It("should request the token through the proxy of the cluster", func() {
+			providerURL, err := url.Parse(provider.URL)
+			Expect(err).ToNot(HaveOccurred())
+			forward := httputil.NewSingleHostReverseProxy(providerURL)
+			var proxied []string
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				mu.Lock()
+				proxied = append(proxied, req.Host)
+				mu.Unlock()
+				forward.ServeHTTP(w, req)
+			}))
+			defer proxy.Close()
+			// The provider is named by a host that is not proxied otherwise, unlike localhost
+			cluster.Spec.Security.Config.OperatorBearerToken.OIDC.TokenURL = "http://oidc.example.com/token"
+			cluster.Annotations = map[string]string{
+				helpers.ProxyURLAnnotation: proxy.URL,
+				helpers.NoProxyAnnotation:  "test-cluster.test-namespace.svc.cluster.local",
+			}
+
+			_, err = CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(proxied).To(Equal([]string{"oidc.example.com"}))
+			Expect(issued).To(Equal(1))
+		})
+
+		It("should report a client the provider does not accept", func() {
+			clientSecret = "wrong"
+			_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+			Expect(errors.Is(err, services.ErrBearerToken)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("returned 401"))
+			Expect(authorized).To(BeEmpty())
+		})
+	})
+})
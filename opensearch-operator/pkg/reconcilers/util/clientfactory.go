@@ -0,0 +1,172 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ClientFactory shares the clients of a cluster between reconciles, so they reuse the connections of the client
+// instead of creating, pinging and dropping a client in every reconcile. Concurrent reconciles asking for a client
+// that does not exist yet wait for the one reconcile creating it. A client is created again when the credentials of
+// the operator change and after maxAge, and closed when it was not used for idleTimeout. Clients are shared, so the
+// requests made with them are identified by the context they are made with, see ContextForObject.
+type ClientFactory struct {
+	idleTimeout time.Duration
+	maxAge      time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	clients map[clientFactoryKey]*clientFactoryEntry
+}
+
+type clientFactoryKey struct {
+	cluster string
+	url     string
+}
+
+type clientFactoryEntry struct {
+	// ready is closed once the client is created or failed to be
+	ready       chan struct{}
+	client      *services.OsClusterClient
+	err         error
+	fingerprint string
+	created     time.Time
+	lastUsed    time.Time
+}
+
+// NewClientFactory returns a factory closing clients unused for idleTimeout and creating clients older than maxAge
+// again, zero keeps them
+func NewClientFactory(idleTimeout time.Duration, maxAge time.Duration) *ClientFactory {
+	return &ClientFactory{
+		idleTimeout: idleTimeout,
+		maxAge:      maxAge,
+		now:         time.Now,
+		clients:     map[clientFactoryKey]*clientFactoryEntry{},
+	}
+}
+
+// Get returns the client sending its requests to url of the cluster, like CreateClientForURL. The options only apply
+// when the client is created, they must be the same for all callers of a cluster.
+func (f *ClientFactory) Get(
+	k8sClient k8s.K8sClient,
+	ctx context.Context,
+	cluster *opsterv1.OpenSearchCluster,
+	url string,
+	transport http.RoundTripper,
+	opts ...services.OsClusterClientOption,
+) (*services.OsClusterClient, error) {
+	proxy, proxyFingerprint, err := clusterProxy(cluster)
+	if err != nil {
+		return nil, err
+	}
+	// The credentials are read in every call, they are served from the cache of the manager
+	credentials, err := loadClientCredentials(k8sClient, ctx, cluster, transport)
+	if err != nil {
+		return nil, err
+	}
+	discovery, discoveryService := endpointDiscovery(k8sClient, cluster, url)
+	// A client is created again when its proxy or endpoint discovery changes as well
+	fingerprint := credentials.fingerprint() + "/" + proxyFingerprint + "/" + discoveryService
+	key := clientFactoryKey{cluster: string(cluster.UID), url: url}
+
+	f.mu.Lock()
+	entry, ok := f.clients[key]
+	now := f.now()
+	if ok && (entry.fingerprint != fingerprint || f.expired(entry, now)) {
+		log.FromContext(ctx).V(1).Info("recreating the client of the cluster", "url", url)
+		f.remove(key, entry)
+		ok = false
+	}
+	if ok {
+		entry.lastUsed = now
+		f.mu.Unlock()
+		select {
+		case <-entry.ready:
+			return entry.client, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	entry = &clientFactoryEntry{
+		ready:       make(chan struct{}),
+		fingerprint: fingerprint,
+		created:     now,
+		lastUsed:    now,
+	}
+	f.clients[key] = entry
+	f.mu.Unlock()
+
+	entry.client, entry.err = credentials.newClient(url, transport, append(append(opts, proxy...), discovery...)...)
+	if entry.err != nil {
+		// A failed client is not shared, the next reconcile tries again
+		f.mu.Lock()
+		if f.clients[key] == entry {
+			delete(f.clients, key)
+		}
+		f.mu.Unlock()
+	}
+	close(entry.ready)
+	return entry.client, entry.err
+}
+
+func (f *ClientFactory) expired(entry *clientFactoryEntry, now time.Time) bool {
+	return f.maxAge > 0 && now.Sub(entry.created) > f.maxAge
+}
+
+// remove drops the entry, its client is closed once it is created. f.mu must be held.
+func (f *ClientFactory) remove(key clientFactoryKey, entry *clientFactoryEntry) {
+	delete(f.clients, key)
+	go func() {
+		<-entry.ready
+		if entry.client != nil {
+			entry.client.Close()
+		}
+	}()
+}
+
+// evictIdle closes the clients that were not used for the idle timeout or are too old
+func (f *ClientFactory) evictIdle() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	for key, entry := range f.clients {
+		if now.Sub(entry.lastUsed) > f.idleTimeout || f.expired(entry, now) {
+			f.remove(key, entry)
+		}
+	}
+}
+
+// Close closes all clients. Reconciles still using them complete, the next Get creates a new client.
+func (f *ClientFactory) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key, entry := range f.clients {
+		f.remove(key, entry)
+	}
+}
+
+// Start closes idle clients until the context is done and all clients then, it implements manager.Runnable
+func (f *ClientFactory) Start(ctx context.Context) error {
+	defer f.Close()
+	if f.idleTimeout <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(f.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			f.evictIdle()
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// factoryTransport answers all requests, counts the pings of new clients and the closed idle connections
+type factoryTransport struct {
+	pings  atomic.Int32
+	closed atomic.Int32
+	// gate holds back the pings until it is closed
+	gate chan struct{}
+	fail atomic.Bool
+
+	mu       sync.Mutex
+	opaqueID []string
+}
+
+func (t *factoryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodHead {
+		t.pings.Add(1)
+		<-t.gate
+	}
+	if t.fail.Load() {
+		return nil, errors.New("connection refused")
+	}
+	t.mu.Lock()
+	t.opaqueID = append(t.opaqueID, req.Header.Get("X-Opaque-Id"))
+	t.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func (t *factoryTransport) CloseIdleConnections() {
+	t.closed.Add(1)
+}
+
+var _ = Describe("Client factory", func() {
+	var (
+		mockClient *k8s.MockK8sClient
+		transport  *factoryTransport
+		factory    *ClientFactory
+		now        time.Time
+		password   atomic.Value
+	)
+
+	cluster := &opsterv1.OpenSearchCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			UID:       "clusteruid",
+		},
+		Spec: opsterv1.ClusterSpec{
+			General: opsterv1.GeneralConfig{
+				ServiceName: "test-cluster",
+				HttpPort:    9200,
+			},
+			Security: &opsterv1.Security{
+				Config: &opsterv1.SecurityConfig{
+					AdminCredentialsSecret: v1.LocalObjectReference{Name: "admin-credentials"},
+				},
+			},
+		},
+	}
+	url := OpensearchClusterURL(cluster)
+
+	get := func(ctx context.Context) (*services.OsClusterClient, error) {
+		return factory.Get(mockClient, ctx, cluster, url, transport)
+	}
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		mockClient.EXPECT().GetSecret("admin-credentials", "test-namespace").RunAndReturn(func(string, string) (v1.Secret, error) {
+			return v1.Secret{Data: map[string][]byte{
+				"username": []byte("admin"),
+				"password": []byte(password.Load().(string)),
+			}}, nil
+		}).Maybe()
+		password.Store("admin")
+		transport = &factoryTransport{gate: make(chan struct{})}
+		close(transport.gate)
+		now = time.Now()
+		factory = NewClientFactory(time.Minute, time.Hour)
+		// The clock is only moved by the specs, the factory reads it while the specs do not
+		factory.now = func() time.Time { return now }
+	})
+
+	It("should create a client once for concurrent reconciles", func() {
+		transport.gate = make(chan struct{})
+		clients := make(chan *services.OsClusterClient, 20)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				client, err := get(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				clients <- client
+			}()
+		}
+		Eventually(transport.pings.Load).Should(BeEquivalentTo(1))
+		close(transport.gate)
+		wg.Wait()
+		close(clients)
+
+		first := <-clients
+		Expect(first).ToNot(BeNil())
+		for client := range clients {
+			Expect(client).To(BeIdenticalTo(first))
+		}
+		Expect(transport.pings.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should stop waiting for a client when the context is done", func() {
+		transport.gate = make(chan struct{})
+		defer close(transport.gate)
+		go func() {
+			defer GinkgoRecover()
+			_, _ = get(context.Background())
+		}()
+		Eventually(transport.pings.Load).Should(BeEquivalentTo(1))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := get(ctx)
+		Expect(err).To(MatchError(context.Canceled))
+	})
+
+	It("should create the client again when the credentials change", func() {
+		first, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		second, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).To(BeIdenticalTo(first))
+
+		password.Store("rotated")
+		third, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(third).ToNot(BeIdenticalTo(first))
+		Expect(transport.pings.Load()).To(BeEquivalentTo(2))
+		Eventually(transport.closed.Load).Should(BeEquivalentTo(1))
+	})
+
+	It("should create the client again when the proxy changes", func() {
+		first, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster.Annotations = map[string]string{helpers.ProxyURLAnnotation: "http://proxy.example.com:3128"}
+		DeferCleanup(func() { cluster.Annotations = nil })
+		second, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).ToNot(BeIdenticalTo(first))
+	})
+
+	It("should create the client again once it is too old", func() {
+		first, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		now = now.Add(2 * time.Hour)
+		second, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).ToNot(BeIdenticalTo(first))
+	})
+
+	It("should not share a client that failed to be created", func() {
+		transport.fail.Store(true)
+		_, err := get(context.Background())
+		Expect(err).To(HaveOccurred())
+
+		transport.fail.Store(false)
+		client, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client).ToNot(BeNil())
+	})
+
+	It("should close clients that are not used", func() {
+		first, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		now = now.Add(30 * time.Second)
+		factory.evictIdle()
+		Expect(transport.closed.Load()).To(BeZero())
+
+		now = now.Add(2 * time.Minute)
+		factory.evictIdle()
+		Eventually(transport.closed.Load).Should(BeEquivalentTo(1))
+		second, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).ToNot(BeIdenticalTo(first))
+	})
+
+	It("should close all clients when the manager stops", func() {
+		_, err := get(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = factory.Get(mockClient, context.Background(), cluster, "https://test-cluster-masters.test-namespace.svc.cluster.local:9200", transport)
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(stopped)
+			Expect(factory.Start(ctx)).To(Succeed())
+		}()
+		cancel()
+		Eventually(stopped).Should(BeClosed())
+		Eventually(transport.closed.Load).Should(BeEquivalentTo(2))
+	})
+
+	It("should identify the requests of every object sharing the client", func() {
+		for _, name := range []string{"first-template", "second-template"} {
+			instance := &opsterv1.OpensearchComponentTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace", UID: "testuid"},
+			}
+			ctx := ContextForObject(context.Background(), instance)
+			client, err := get(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(services.Ping(ctx, client)).To(Succeed())
+		}
+		Expect(transport.opaqueID).To(ContainElements(
+			"test-namespace/first-template/testuid",
+			"test-namespace/second-template/testuid",
+		))
+	})
+})
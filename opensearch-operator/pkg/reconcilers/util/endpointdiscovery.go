@@ -0,0 +1,78 @@
+package util
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// serviceEndpointResolver resolves the ready endpoints of a Service from its Endpoints object, which is read for every
+// request from the cache of the manager
+type serviceEndpointResolver struct {
+	k8sClient k8s.K8sClient
+	name      string
+	namespace string
+	httpPort  int32
+}
+
+func (r *serviceEndpointResolver) Endpoints(_ context.Context) ([]string, error) {
+	endpoints, err := r.k8sClient.GetEndpoints(r.name, r.namespace)
+	if err != nil {
+		return nil, err
+	}
+	var addresses []string
+	for _, subset := range endpoints.Subsets {
+		port := endpointPort(subset.Ports, r.httpPort)
+		if port == 0 {
+			continue
+		}
+		// NotReadyAddresses are left out, e.g. nodes that are restarting
+		for _, address := range subset.Addresses {
+			addresses = append(addresses, net.JoinHostPort(address.IP, strconv.Itoa(int(port))))
+		}
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+// endpointPort returns the port of the endpoints OpenSearch serves HTTP on: the one named http, else the HTTP port of
+// the cluster, else the only one
+func endpointPort(ports []corev1.EndpointPort, httpPort int32) int32 {
+	for _, port := range ports {
+		if port.Name == "http" {
+			return port.Port
+		}
+	}
+	for _, port := range ports {
+		if port.Port == httpPort {
+			return port.Port
+		}
+	}
+	if len(ports) == 1 {
+		return ports[0].Port
+	}
+	return 0
+}
+
+// endpointDiscovery returns the option balancing the requests sent to the service of the cluster across the ready
+// endpoints of the Service named by the opensearch.opster.io/endpoint-discovery-service annotation, and the name of
+// that Service. Requests sent to other URLs, e.g. the services of node pools, are sent as is.
+func endpointDiscovery(k8sClient k8s.K8sClient, cluster *opsterv1.OpenSearchCluster, url string) ([]services.OsClusterClientOption, string) {
+	service := cluster.Annotations[helpers.EndpointDiscoveryAnnotation]
+	if service == "" || url != OpensearchClusterURL(cluster) {
+		return nil, ""
+	}
+	return []services.OsClusterClientOption{services.WithEndpointDiscovery(&serviceEndpointResolver{
+		k8sClient: k8sClient,
+		name:      service,
+		namespace: cluster.Namespace,
+		httpPort:  cluster.Spec.General.HttpPort,
+	})}, service
+}
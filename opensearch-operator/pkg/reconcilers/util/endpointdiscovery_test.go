@@ -0,0 +1,132 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Client for cluster with endpoint discovery", func() {
+	var (
+		mockClient  *k8s.MockK8sClient
+		transport   *httpmock.MockTransport
+		endpoints   corev1.Endpoints
+		hosts       []string
+		unreachable map[string]bool
+		cluster     *opsterv1.OpenSearchCluster
+	)
+
+	subset := func(ready []string, notReady []string) corev1.EndpointSubset {
+		subset := corev1.EndpointSubset{
+			Ports: []corev1.EndpointPort{{Name: "transport", Port: 9300}, {Name: "http", Port: 9200}},
+		}
+		for _, ip := range ready {
+			subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+		}
+		for _, ip := range notReady {
+			subset.NotReadyAddresses = append(subset.NotReadyAddresses, corev1.EndpointAddress{IP: ip})
+		}
+		return subset
+	}
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		hosts = nil
+		unreachable = map[string]bool{}
+		transport.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			if unreachable[req.URL.Host] {
+				return nil, errors.New("connection refused")
+			}
+			hosts = append(hosts, req.URL.Host)
+			// Requests sent to an endpoint keep the host of the service
+			Expect(req.Host).To(Or(BeEmpty(), Equal("test-cluster.test-namespace.svc.cluster.local:9200")))
+			return httpmock.NewStringResponse(200, "{}"), nil
+		})
+		endpoints = corev1.Endpoints{Subsets: []corev1.EndpointSubset{
+			subset([]string{"10.0.0.1", "10.0.0.2"}, []string{"10.0.0.3"}),
+		}}
+		mockClient.EXPECT().GetEndpoints("test-cluster-discovery", "test-namespace").RunAndReturn(func(string, string) (corev1.Endpoints, error) {
+			return endpoints, nil
+		}).Maybe()
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-cluster",
+				Namespace:   "test-namespace",
+				Annotations: map[string]string{helpers.EndpointDiscoveryAnnotation: "test-cluster-discovery"},
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+			},
+		}
+	})
+
+	ping := func(client *services.OsClusterClient, times int) {
+		hosts = nil
+		for i := 0; i < times; i++ {
+			Expect(services.Ping(context.Background(), client)).To(Succeed())
+		}
+	}
+
+	It("should balance the requests across the ready endpoints", func() {
+		client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(err).ToNot(HaveOccurred())
+		ping(client, 4)
+		Expect(hosts).To(ConsistOf("10.0.0.1:9200", "10.0.0.2:9200", "10.0.0.1:9200", "10.0.0.2:9200"))
+	})
+
+	It("should follow the endpoints as they change", func() {
+		client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(err).ToNot(HaveOccurred())
+
+		// The first node restarts, a new node becomes ready
+		endpoints.Subsets = []corev1.EndpointSubset{subset([]string{"10.0.0.2", "10.0.0.4"}, []string{"10.0.0.1"})}
+		ping(client, 4)
+		Expect(hosts).To(ConsistOf("10.0.0.2:9200", "10.0.0.4:9200", "10.0.0.2:9200", "10.0.0.4:9200"))
+	})
+
+	It("should send a request failing to connect to the next endpoint", func() {
+		client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(err).ToNot(HaveOccurred())
+
+		unreachable["10.0.0.1:9200"] = true
+		ping(client, 2)
+		Expect(hosts).To(Equal([]string{"10.0.0.2:9200", "10.0.0.2:9200"}))
+	})
+
+	It("should send the requests to the service without ready endpoints", func() {
+		endpoints.Subsets = []corev1.EndpointSubset{subset(nil, []string{"10.0.0.1"})}
+		client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(err).ToNot(HaveOccurred())
+		ping(client, 1)
+		Expect(hosts).To(Equal([]string{"test-cluster.test-namespace.svc.cluster.local:9200"}))
+	})
+
+	It("should send the requests to the service without the annotation", func() {
+		delete(cluster.Annotations, helpers.EndpointDiscoveryAnnotation)
+		client, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(err).ToNot(HaveOccurred())
+		ping(client, 1)
+		Expect(hosts).To(Equal([]string{"test-cluster.test-namespace.svc.cluster.local:9200"}))
+	})
+
+	It("should not balance requests sent to the services of node pools", func() {
+		client, err := CreateClientForURL(mockClient, context.Background(), cluster, "https://test-cluster-masters.test-namespace.svc.cluster.local:9200", transport)
+		Expect(err).ToNot(HaveOccurred())
+		ping(client, 1)
+		Expect(hosts).To(Equal([]string{"test-cluster-masters.test-namespace.svc.cluster.local:9200"}))
+	})
+})
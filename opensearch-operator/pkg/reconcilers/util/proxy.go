@@ -0,0 +1,43 @@
+package util
+
+import (
+	"fmt"
+	"net/url"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+)
+
+// clusterProxy returns the option sending the requests to the cluster through the proxy named by the
+// opensearch.opster.io/proxy-url annotation, except the requests to the hosts of the opensearch.opster.io/no-proxy
+// annotation, and a fingerprint of both. Without the annotation the proxy environment variables of the operator apply.
+func clusterProxy(cluster *opsterv1.OpenSearchCluster) ([]services.OsClusterClientOption, string, error) {
+	value := cluster.Annotations[helpers.ProxyURLAnnotation]
+	if value == "" {
+		return nil, "", nil
+	}
+	proxyURL, err := parseProxyURL(value)
+	if err != nil {
+		return nil, "", services.ErrInvalidProxy(err.Error())
+	}
+	noProxy := cluster.Annotations[helpers.NoProxyAnnotation]
+	return []services.OsClusterClientOption{services.WithProxy(proxyURL, noProxy)}, value + "|" + noProxy, nil
+}
+
+// parseProxyURL parses a proxy URL, which needs an http, https or socks5 scheme and a host
+func parseProxyURL(value string) (*url.URL, error) {
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("%q must have an http, https or socks5 scheme", value)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("%q has no host", value)
+	}
+	return proxyURL, nil
+}
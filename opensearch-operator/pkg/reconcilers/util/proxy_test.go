@@ -0,0 +1,150 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeProxy is an HTTP proxy that tunnels every CONNECT request to the cluster, whichever host it names, and records
+// the hosts
+type fakeProxy struct {
+	server  *httptest.Server
+	cluster *httptest.Server
+
+	mu    sync.Mutex
+	hosts []string
+}
+
+func newFakeProxy() *fakeProxy {
+	proxy := &fakeProxy{}
+	proxy.cluster = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	proxy.server = httptest.NewServer(http.HandlerFunc(proxy.connect))
+	return proxy
+}
+
+func (p *fakeProxy) connect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	p.mu.Lock()
+	p.hosts = append(p.hosts, r.Host)
+	p.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", p.cluster.Listener.Addr().String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	go func() {
+		defer upstream.Close()
+		defer conn.Close()
+		_, _ = io.Copy(upstream, conn)
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+	}()
+}
+
+func (p *fakeProxy) tunneled() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.hosts...)
+}
+
+func (p *fakeProxy) Close() {
+	p.server.CloseClientConnections()
+	p.server.Close()
+	p.cluster.CloseClientConnections()
+	p.cluster.Close()
+}
+
+var _ = Describe("Client for cluster behind a proxy", func() {
+	var (
+		mockClient *k8s.MockK8sClient
+		proxy      *fakeProxy
+		cluster    *opsterv1.OpenSearchCluster
+	)
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		proxy = newFakeProxy()
+		DeferCleanup(proxy.Close)
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-cluster",
+				Namespace:   "test-namespace",
+				Annotations: map[string]string{helpers.ProxyURLAnnotation: proxy.server.URL},
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+			},
+		}
+	})
+
+	It("should send the requests through the proxy of the annotation", func() {
+		client, err := CreateClientForCluster(mockClient, context.Background(), cluster, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(services.Ping(context.Background(), client)).To(Succeed())
+		Expect(proxy.tunneled()).ToNot(BeEmpty())
+		Expect(proxy.tunneled()).To(HaveEach("test-cluster.test-namespace.svc.cluster.local:9200"))
+	})
+
+	It("should not send the requests to the hosts of the no-proxy annotation through the proxy", func() {
+		cluster.Annotations[helpers.NoProxyAnnotation] = "example.com,.svc.cluster.local"
+		// The service does not resolve outside of the cluster
+		_, err := CreateClientForCluster(mockClient, context.Background(), cluster, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, services.ErrProxy)).To(BeFalse())
+		Expect(proxy.tunneled()).To(BeEmpty())
+	})
+
+	It("should report a proxy that is unreachable", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		cluster.Annotations[helpers.ProxyURLAnnotation] = "http://" + listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		_, err = CreateClientForCluster(mockClient, context.Background(), cluster, nil)
+		Expect(errors.Is(err, services.ErrProxy)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("the proxy is unreachable"))
+	})
+
+	DescribeTable("should report an invalid proxy URL",
+		func(value string, message string) {
+			cluster.Annotations[helpers.ProxyURLAnnotation] = value
+			_, err := CreateClientForCluster(mockClient, context.Background(), cluster, nil)
+			Expect(errors.Is(err, services.ErrProxy)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("without scheme", "proxy.example.com:3128", "must have an http, https or socks5 scheme"),
+		Entry("with another scheme", "ftp://proxy.example.com", "must have an http, https or socks5 scheme"),
+		Entry("without host", "http://", "has no host"),
+		Entry("that does not parse", "http://proxy example.com", "invalid proxy URL"),
+	)
+})
@@ -3,9 +3,12 @@ package util
 import (
 	"context"
 	"crypto/sha1"
+	"crypto/sha256"
+	cryptotls "crypto/tls"
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -24,6 +27,7 @@ import (
 	"k8s.io/kube-openapi/pkg/validation/errors"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -205,37 +209,186 @@ func OpensearchClusterURL(cluster *opsterv1.OpenSearchCluster) string {
 	)
 }
 
+// NodePoolURLs returns the URLs of the services of the node pools of the cluster by component, each of them only
+// reaches the nodes of its node pool
+func NodePoolURLs(cluster *opsterv1.OpenSearchCluster) map[string]string {
+	urls := make(map[string]string, len(cluster.Spec.NodePools))
+	for _, nodePool := range cluster.Spec.NodePools {
+		urls[nodePool.Component] = fmt.Sprintf(
+			"https://%s-%s.%s.svc.%s:%v",
+			cluster.Spec.General.ServiceName,
+			nodePool.Component,
+			cluster.Namespace,
+			helpers.ClusterDnsBase(),
+			cluster.Spec.General.HttpPort,
+		)
+	}
+	return urls
+}
+
 func CreateClientForCluster(
 	k8sClient k8s.K8sClient,
 	ctx context.Context,
 	cluster *opsterv1.OpenSearchCluster,
 	transport http.RoundTripper,
+	opts ...services.OsClusterClientOption,
+) (*services.OsClusterClient, error) {
+	return CreateClientForURL(k8sClient, ctx, cluster, OpensearchClusterURL(cluster), transport, opts...)
+}
+
+// CreateClientForURL creates a client authenticating to the cluster like CreateClientForCluster, which sends its
+// requests to url instead of the service of the cluster
+func CreateClientForURL(
+	k8sClient k8s.K8sClient,
+	ctx context.Context,
+	cluster *opsterv1.OpenSearchCluster,
+	url string,
+	transport http.RoundTripper,
+	opts ...services.OsClusterClientOption,
 ) (*services.OsClusterClient, error) {
+	proxy, _, err := clusterProxy(cluster)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := loadClientCredentials(k8sClient, ctx, cluster, transport)
+	if err != nil {
+		return nil, err
+	}
+	discovery, _ := endpointDiscovery(k8sClient, cluster, url)
+	return credentials.newClient(url, transport, append(append(opts, proxy...), discovery...)...)
+}
+
+// clientCredentials are the credentials the operator authenticates to a cluster with, either its client certificate,
+// a bearer token or the username and password of its admin user
+type clientCredentials struct {
+	certificate      *cryptotls.Certificate
+	tokenSource      services.TokenSource
+	tokenFingerprint string
+	username         string
+	password         string
+}
+
+func loadClientCredentials(k8sClient k8s.K8sClient, ctx context.Context, cluster *opsterv1.OpenSearchCluster, transport http.RoundTripper) (clientCredentials, error) {
 	lg := log.FromContext(ctx)
-	var osClient *services.OsClusterClient
 
+	if cluster.Spec.Security != nil && cluster.Spec.Security.Config != nil && cluster.Spec.Security.Config.OperatorAuthMode == opsterv1.OperatorAuthModeBearerToken {
+		source, fingerprint, err := bearerTokenSource(k8sClient, ctx, cluster, transport)
+		if err != nil {
+			lg.Error(err, "failed to get the operator bearer token")
+			return clientCredentials{}, services.ErrBearerTokenUnavailable(err)
+		}
+		return clientCredentials{tokenSource: source, tokenFingerprint: fingerprint}, nil
+	}
+
+	certificate, err := helpers.OperatorClientCertificate(k8sClient, cluster)
+	if err != nil {
+		lg.Error(err, "failed to load the operator client certificate")
+		return clientCredentials{}, services.ErrInvalidClientCertificate(err)
+	}
+	if certificate != nil {
+		return clientCredentials{certificate: certificate}, nil
+	}
 	username, password, err := helpers.UsernameAndPassword(k8sClient, cluster)
 	if err != nil {
 		lg.Error(err, "failed to fetch opensearch credentials")
-		return nil, err
+		return clientCredentials{}, err
 	}
+	return clientCredentials{username: username, password: password}, nil
+}
 
-	if transport == nil {
-		osClient, err = services.NewOsClusterClient(
-			OpensearchClusterURL(cluster),
-			username,
-			password,
-		)
-	} else {
-		osClient, err = services.NewOsClusterClient(
-			OpensearchClusterURL(cluster),
-			username,
-			password,
-			services.WithTransport(transport),
-		)
+// fingerprint changes whenever the credentials change, without revealing them
+func (c clientCredentials) fingerprint() string {
+	hash := sha256.New()
+	switch {
+	case c.certificate != nil:
+		for _, der := range c.certificate.Certificate {
+			hash.Write(der)
+		}
+	case c.tokenSource != nil:
+		// The token itself is refreshed by its source, only another source needs another client
+		hash.Write([]byte(c.tokenFingerprint))
+	default:
+		fmt.Fprintf(hash, "%d:%s:%s", len(c.username), c.username, c.password)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c clientCredentials) newClient(
+	url string,
+	transport http.RoundTripper,
+	opts ...services.OsClusterClientOption,
+) (*services.OsClusterClient, error) {
+	if c.certificate != nil {
+		opts = append(opts, services.WithClientCertificate(c.certificate))
+	}
+	if c.tokenSource != nil {
+		opts = append(opts, services.WithBearerToken(c.tokenSource))
+	}
+	if transport != nil {
+		opts = append(opts, services.WithTransport(transport))
+	}
+	return services.NewOsClusterClient(
+		url,
+		c.username,
+		c.password,
+		opts...,
+	)
+}
+
+// ClientOptionForObject identifies the requests sent to OpenSearch while reconciling object
+func ClientOptionForObject(object client.Object) services.OsClusterClientOption {
+	return services.WithReconciledObject(objectKind(object), object.GetNamespace(), object.GetName(), string(object.GetUID()))
+}
+
+// ContextForObject identifies the requests sent to OpenSearch with the returned context like ClientOptionForObject,
+// for clients shared between objects
+func ContextForObject(ctx context.Context, object client.Object) context.Context {
+	return services.ContextWithReconciledObject(ctx, objectKind(object), object.GetNamespace(), object.GetName(), string(object.GetUID()))
+}
+
+func objectKind(object client.Object) string {
+	kind := object.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		// Typed objects read from the API server usually have no type meta set
+		kind = reflect.Indirect(reflect.ValueOf(object)).Type().Name()
+	}
+	return kind
+}
+
+func DashboardsURL(cluster *opsterv1.OpenSearchCluster) string {
+	scheme := "http"
+	if cluster.Spec.Dashboards.Tls != nil && cluster.Spec.Dashboards.Tls.Enable {
+		scheme = "https"
+	}
+	return fmt.Sprintf(
+		"%s://%s-dashboards.%s.svc.%s:5601%s",
+		scheme,
+		cluster.Spec.General.ServiceName,
+		cluster.Namespace,
+		helpers.ClusterDnsBase(),
+		cluster.Spec.Dashboards.BasePath,
+	)
+}
+
+func CreateDashboardsClientForCluster(
+	k8sClient k8s.K8sClient,
+	ctx context.Context,
+	cluster *opsterv1.OpenSearchCluster,
+	transport http.RoundTripper,
+	opts ...services.OsClusterClientOption,
+) (*services.DashboardsClient, error) {
+	lg := log.FromContext(ctx)
+
+	username, password, err := helpers.UsernameAndPassword(k8sClient, cluster)
+	if err != nil {
+		lg.Error(err, "failed to fetch opensearch credentials")
+		return nil, err
 	}
 
-	return osClient, err
+	if transport != nil {
+		opts = append(opts, services.WithTransport(transport))
+	}
+	return services.NewDashboardsClient(DashboardsURL(cluster), username, password, opts...), nil
 }
 
 func FetchOpensearchCluster(
@@ -2,12 +2,22 @@ package util
 
 import (
 	"context"
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
 
 	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
 	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/mocks/github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/reconcilers/k8s"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/tls"
+	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("Additional volumes", func() {
@@ -89,3 +99,176 @@ var _ = Describe("Additional volumes", func() {
 		})
 	})
 })
+
+var _ = Describe("Client for cluster", func() {
+	var mockClient *k8s.MockK8sClient
+	var transport *httpmock.MockTransport
+	var requests []*http.Request
+
+	cluster := &opsterv1.OpenSearchCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: opsterv1.ClusterSpec{
+			General: opsterv1.GeneralConfig{
+				ServiceName: "test-cluster",
+				HttpPort:    9200,
+			},
+		},
+	}
+	instance := &opsterv1.OpensearchComponentTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-template",
+			Namespace: "test-namespace",
+			UID:       "testuid",
+		},
+	}
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		transport = httpmock.NewMockTransport()
+		requests = nil
+		transport.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			return httpmock.NewStringResponse(200, "{}"), nil
+		})
+	})
+
+	It("should identify the operator and the reconciled object on every request", func() {
+		_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport, ClientOptionForObject(instance))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).ToNot(BeEmpty())
+		for _, req := range requests {
+			Expect(req.Header.Get("User-Agent")).To(Equal("opensearch-k8s-operator/" + services.OperatorVersion + " (OpensearchComponentTemplate test-namespace/test-template)"))
+			Expect(req.Header.Get("X-Opaque-Id")).To(Equal("test-namespace/test-template/testuid"))
+		}
+	})
+
+	It("should only identify the operator without a reconciled object", func() {
+		_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).ToNot(BeEmpty())
+		for _, req := range requests {
+			Expect(req.Header.Get("User-Agent")).To(Equal("opensearch-k8s-operator/" + services.OperatorVersion))
+			Expect(req.Header.Values("X-Opaque-Id")).To(BeEmpty())
+		}
+	})
+})
+
+var _ = Describe("Client for cluster with a client certificate", Ordered, func() {
+	var (
+		mockClient    *k8s.MockK8sClient
+		server        *httptest.Server
+		transport     *http.Transport
+		ca            tls.Cert
+		clientCert    tls.Cert
+		untrustedCert tls.Cert
+		authorized    []string
+		cluster       *opsterv1.OpenSearchCluster
+	)
+
+	BeforeAll(func() {
+		pki := tls.NewPKI()
+		var err error
+		ca, err = pki.GenerateCA("operator-ca")
+		Expect(err).ToNot(HaveOccurred())
+		clientCert, err = ca.CreateAndSignCertificate("operator", "operator", nil)
+		Expect(err).ToNot(HaveOccurred())
+		untrustedCA, err := pki.GenerateCA("untrusted-ca")
+		Expect(err).ToNot(HaveOccurred())
+		untrustedCert, err = untrustedCA.CreateAndSignCertificate("operator", "operator", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		clientCAs := x509.NewCertPool()
+		Expect(clientCAs.AppendCertsFromPEM(ca.CertData())).To(BeTrue())
+		server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if _, _, ok := req.BasicAuth(); ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			authorized = append(authorized, req.TLS.PeerCertificates[0].Subject.CommonName)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("{}"))
+		}))
+		server.TLS = &cryptotls.Config{
+			ClientAuth: cryptotls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+		}
+		server.StartTLS()
+		// The client connects to the service of the cluster, which is the fake server here
+		transport = &http.Transport{
+			DialContext: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+			},
+			TLSClientConfig: &cryptotls.Config{InsecureSkipVerify: true},
+		}
+	})
+
+	AfterAll(func() {
+		server.Close()
+	})
+
+	BeforeEach(func() {
+		mockClient = k8s.NewMockK8sClient(GinkgoT())
+		authorized = nil
+		cluster = &opsterv1.OpenSearchCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+			Spec: opsterv1.ClusterSpec{
+				General: opsterv1.GeneralConfig{
+					ServiceName: "test-cluster",
+					HttpPort:    9200,
+				},
+				Security: &opsterv1.Security{
+					Config: &opsterv1.SecurityConfig{
+						OperatorAuthMode:         opsterv1.OperatorAuthModeClientCertificate,
+						OperatorClientCertSecret: v1.LocalObjectReference{Name: "operator-client-cert"},
+					},
+				},
+			},
+		}
+	})
+
+	clientCertSecret := func(data map[string][]byte) {
+		mockClient.EXPECT().GetSecret("operator-client-cert", "test-namespace").Return(v1.Secret{Data: data}, nil)
+	}
+
+	It("should authenticate with the client certificate instead of basic auth", func() {
+		clientCertSecret(clientCert.SecretData(ca))
+		_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(authorized).ToNot(BeEmpty())
+		Expect(authorized).To(HaveEach("operator"))
+	})
+
+	It("should report a client certificate the cluster does not accept", func() {
+		clientCertSecret(untrustedCert.SecretData(ca))
+		_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(errors.Is(err, services.ErrClientCertificate)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("the cluster aborted the TLS handshake"))
+		Expect(authorized).To(BeEmpty())
+	})
+
+	It("should not connect with a certificate not matching its key", func() {
+		clientCertSecret(map[string][]byte{
+			"tls.crt": clientCert.CertData(),
+			"tls.key": untrustedCert.KeyData(),
+		})
+		_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(errors.Is(err, services.ErrClientCertificate)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("invalid client certificate in secret operator-client-cert"))
+		Expect(authorized).To(BeEmpty())
+	})
+
+	It("should not connect without a key", func() {
+		clientCertSecret(map[string][]byte{
+			"tls.crt": clientCert.CertData(),
+		})
+		_, err := CreateClientForCluster(mockClient, context.Background(), cluster, transport)
+		Expect(errors.Is(err, services.ErrClientCertificate)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("tls.crt or tls.key field missing in secret operator-client-cert"))
+	})
+})
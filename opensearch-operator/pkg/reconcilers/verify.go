@@ -0,0 +1,150 @@
+package reconcilers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	opsterv1 "github.com/Opster/opensearch-k8s-operator/opensearch-operator/api/v1"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/requests"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/opensearch-gateway/services"
+	"github.com/Opster/opensearch-k8s-operator/opensearch-operator/pkg/helpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	opensearchVerification = "OpensearchComponentTemplateVerification"
+
+	// verifyIndexPrefix is the prefix of the test indices and their index templates created to verify component
+	// templates, names with this prefix are reserved for the operator
+	verifyIndexPrefix = "opensearch-operator-verify-"
+	// verifyIndexTemplatePriority lets the index template of a test index take precedence over all other index
+	// templates matching it
+	verifyIndexTemplatePriority = math.MaxInt32
+)
+
+// verify checks that the applied component template is effective, unless this version was already verified. It
+// creates an index template composed of only the component template for a test index, creates the test index and
+// compares its settings and mappings with the component template. The test index and its index template use names
+// reserved for the operator, existing ones are never modified, and both are deleted again. Verification is deferred
+// while the cluster is red or below the required cluster health.
+func (r *ComponentTemplateReconciler) verify(templateName string, template requests.ComponentTemplate) (ctrl.Result, string, error) {
+	hash, err := componentTemplateHash(template)
+	if err != nil {
+		reason := "failed to hash the component template"
+		r.logger.Error(err, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if r.instance.Status.VerifiedHash == hash {
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+	}
+
+	if len(template.Template.Aliases) > 0 {
+		reason := "component templates with aliases cannot be verified without adding the test index to the aliases, disable verifyAfterApply"
+		r.recorder.Event(r.instance, "Warning", opensearchVerification, reason)
+		return ctrl.Result{}, reason, errors.New(reason)
+	}
+	indexName := verifyIndexPrefix + strings.ToLower(templateName)
+	if err := helpers.ValidateIndexPattern(indexName); err != nil {
+		reason := fmt.Sprintf("cannot verify the component template with a test index: %s", err)
+		r.recorder.Event(r.instance, "Warning", opensearchVerification, reason)
+		return ctrl.Result{}, reason, err
+	}
+
+	requiredHealth := r.instance.Spec.RequiredClusterHealth
+	if requiredHealth == "" {
+		requiredHealth = opsterv1.OpenSearchYellowHealth
+	}
+	deferred, reason, err := r.deferForRequiredHealth(requiredHealth)
+	if err != nil {
+		return ctrl.Result{}, reason, err
+	}
+	if deferred != "" {
+		r.recorder.Event(r.instance, "Normal", opensearchDeferred, deferred)
+		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, deferred, nil
+	}
+
+	mismatches, err := r.verifyWithTestIndex(templateName, indexName, template)
+	if errors.Is(err, services.ErrForbidden) {
+		reason := "operator user is not authorized to verify component templates, check that it has the " +
+			"indices:admin/create, indices:admin/delete, indices:admin/get, indices:admin/mappings/get and " +
+			"indices:admin/index_template/* privileges"
+		r.logger.Info(reason)
+		r.recorder.Event(r.instance, "Warning", opensearchForbidden, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if err != nil {
+		reason := fmt.Sprintf("failed to verify the component template: %s", err)
+		r.logger.Error(err, reason)
+		r.recorder.Event(r.instance, "Warning", opensearchVerification, reason)
+		return ctrl.Result{}, reason, err
+	}
+	if len(mismatches) > 0 {
+		reason := fmt.Sprintf("component template is not effective in test index %s: %s", indexName, strings.Join(mismatches, "; "))
+		r.recorder.Event(r.instance, "Warning", opensearchVerification, reason)
+		return ctrl.Result{}, reason, errors.New(reason)
+	}
+
+	if err := r.updateTemplateStatus(func(status *opsterv1.OpensearchComponentTemplateStatus) {
+		status.VerifiedHash = hash
+	}); err != nil {
+		reason := fmt.Sprintf("failed to update status: %s", err)
+		r.recorder.Event(r.instance, "Warning", statusError, reason)
+		return ctrl.Result{}, reason, err
+	}
+	r.recorder.Event(r.instance, "Normal", opensearchVerification, fmt.Sprintf("component template verified with test index %s", indexName))
+	return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, "", nil
+}
+
+// verifyWithTestIndex creates the test index from the component template and returns how its settings and mappings
+// differ from the template. The test index and its index template are always deleted before returning.
+func (r *ComponentTemplateReconciler) verifyWithTestIndex(templateName string, indexName string, template requests.ComponentTemplate) (mismatches []string, err error) {
+	exists, err := services.IndexTemplateExists(r.ctx, r.osClient, indexName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("index template %s already exists, delete it to verify the component template", indexName)
+	}
+
+	err = services.CreateOrUpdateIndexTemplate(r.ctx, r.osClient, indexName, requests.IndexTemplate{
+		IndexPatterns: []string{indexName},
+		ComposedOf:    []string{templateName},
+		Priority:      verifyIndexTemplatePriority,
+		Meta:          &apiextensionsv1.JSON{Raw: []byte(`{"managed_by":"opensearch-operator"}`)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cleanupErr := services.DeleteIndexTemplate(r.ctx, r.osClient, indexName); cleanupErr != nil && err == nil {
+			err = fmt.Errorf("failed to delete the index template of the test index: %w", cleanupErr)
+		}
+	}()
+
+	// A test index without replicas does not change the health of the cluster
+	err = services.CreateNewIndex(r.ctx, r.osClient, indexName, map[string]interface{}{
+		"settings": map[string]interface{}{"index.number_of_replicas": 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cleanupErr := services.DeleteIndexIfExists(r.ctx, r.osClient, indexName); cleanupErr != nil && err == nil {
+			err = fmt.Errorf("failed to delete the test index: %w", cleanupErr)
+		}
+	}()
+
+	settings, err := services.GetIndexSettings(r.ctx, r.osClient, indexName)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := services.GetIndexMappings(r.ctx, r.osClient, indexName)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.IndexMismatches(template.Template, settings, mappings, r.redaction, "index.number_of_replicas")
+}